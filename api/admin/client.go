@@ -85,3 +85,36 @@ func (c *Client) Stacktrace() (bool, error) {
 	err := c.requester.SendRequest("stacktrace", struct{}{}, res)
 	return res.Success, err
 }
+
+// ReloadPeerAllowlist ...
+func (c *Client) ReloadPeerAllowlist() ([]string, error) {
+	res := &ReloadPeerAllowlistReply{}
+	err := c.requester.SendRequest("reloadPeerAllowlist", struct{}{}, res)
+	return res.NodeIDs, err
+}
+
+// GetPeerBans ...
+func (c *Client) GetPeerBans() ([]PeerBan, error) {
+	res := &GetPeerBansReply{}
+	err := c.requester.SendRequest("getPeerBans", struct{}{}, res)
+	return res.Bans, err
+}
+
+// BanPeer ...
+func (c *Client) BanPeer(nodeID string, duration time.Duration) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("banPeer", &BanPeerArgs{
+		NodeID:   nodeID,
+		Duration: duration.String(),
+	}, res)
+	return res.Success, err
+}
+
+// UnbanPeer ...
+func (c *Client) UnbanPeer(nodeID string) (bool, error) {
+	res := &api.SuccessResponse{}
+	err := c.requester.SendRequest("unbanPeer", &UnbanPeerArgs{
+		NodeID: nodeID,
+	}, res)
+	return res.Success, err
+}
@@ -7,13 +7,16 @@ import (
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
 
 	cjson "github.com/ava-labs/avalanchego/utils/json"
@@ -35,11 +38,12 @@ type Admin struct {
 	log          logging.Logger
 	performance  Performance
 	chainManager chains.Manager
+	networking   network.Network
 	httpServer   *api.Server
 }
 
 // NewService returns a new admin API service
-func NewService(log logging.Logger, chainManager chains.Manager, httpServer *api.Server) (*common.HTTPHandler, error) {
+func NewService(log logging.Logger, chainManager chains.Manager, peers network.Network, httpServer *api.Server) (*common.HTTPHandler, error) {
 	newServer := rpc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
@@ -47,6 +51,7 @@ func NewService(log logging.Logger, chainManager chains.Manager, httpServer *api
 	if err := newServer.RegisterService(&Admin{
 		log:          log,
 		chainManager: chainManager,
+		networking:   peers,
 		httpServer:   httpServer,
 	}, "admin"); err != nil {
 		return nil, err
@@ -160,3 +165,95 @@ func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *api.Succes
 	stacktrace := []byte(logging.Stacktrace{Global: true}.String())
 	return ioutil.WriteFile(stacktraceFile, stacktrace, 0600)
 }
+
+// ReloadPeerAllowlistReply are the nodes on the reloaded peer allowlist
+type ReloadPeerAllowlistReply struct {
+	NodeIDs []string `json:"nodeIDs"`
+}
+
+// ReloadPeerAllowlist re-reads the peer allowlist from its file
+func (service *Admin) ReloadPeerAllowlist(_ *http.Request, _ *struct{}, reply *ReloadPeerAllowlistReply) error {
+	service.log.Info("Admin: ReloadPeerAllowlist called")
+
+	nodeIDs, err := service.networking.ReloadAllowlist()
+	if err != nil {
+		return err
+	}
+	reply.NodeIDs = make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		reply.NodeIDs[i] = nodeID.PrefixedString(constants.NodeIDPrefix)
+	}
+	return nil
+}
+
+// PeerBan is a node that's banned and the unix time its ban ends
+type PeerBan struct {
+	NodeID string       `json:"nodeID"`
+	Until  cjson.Uint64 `json:"until"`
+}
+
+// GetPeerBansReply are the nodes that are banned
+type GetPeerBansReply struct {
+	Bans []PeerBan `json:"bans"`
+}
+
+// GetPeerBans returns the nodes that are banned and when their bans end
+func (service *Admin) GetPeerBans(_ *http.Request, _ *struct{}, reply *GetPeerBansReply) error {
+	service.log.Info("Admin: GetPeerBans called")
+
+	bans := service.networking.Bans()
+	reply.Bans = make([]PeerBan, 0, len(bans))
+	for nodeID, until := range bans {
+		reply.Bans = append(reply.Bans, PeerBan{
+			NodeID: nodeID.PrefixedString(constants.NodeIDPrefix),
+			Until:  cjson.Uint64(until.Unix()),
+		})
+	}
+	return nil
+}
+
+// BanPeerArgs are the arguments for calling BanPeer
+type BanPeerArgs struct {
+	NodeID string `json:"nodeID"`
+	// How long the node is banned for, such as "1h30m"
+	Duration string `json:"duration"`
+}
+
+// BanPeer disconnects from a node and refuses its connections for a while
+func (service *Admin) BanPeer(_ *http.Request, args *BanPeerArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: BanPeer called with NodeID: %s, Duration: %s", args.NodeID, args.Duration)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(args.Duration)
+	if err != nil {
+		return err
+	}
+	if err := service.networking.Ban(nodeID, duration); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UnbanPeerArgs are the arguments for calling UnbanPeer
+type UnbanPeerArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// UnbanPeer lifts the ban on a node
+func (service *Admin) UnbanPeer(_ *http.Request, args *UnbanPeerArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: UnbanPeer called with NodeID: %s", args.NodeID)
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return err
+	}
+	if err := service.networking.Unban(nodeID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
 )
 
 // Info is the API service for unprivileged info on a node
@@ -29,6 +30,7 @@ type Info struct {
 	chainManager  chains.Manager
 	creationTxFee uint64
 	txFee         uint64
+	feeRegistry   *fees.Registry
 }
 
 // NewService returns a new admin API service
@@ -41,6 +43,7 @@ func NewService(
 	peers network.Network,
 	creationTxFee uint64,
 	txFee uint64,
+	feeRegistry *fees.Registry,
 ) (*common.HTTPHandler, error) {
 	newServer := rpc.NewServer()
 	codec := json.NewCodec()
@@ -55,6 +58,7 @@ func NewService(
 		networking:    peers,
 		creationTxFee: creationTxFee,
 		txFee:         txFee,
+		feeRegistry:   feeRegistry,
 	}, "info"); err != nil {
 		return nil, err
 	}
@@ -191,16 +195,41 @@ func (service *Info) IsBootstrapped(_ *http.Request, args *IsBootstrappedArgs, r
 	return nil
 }
 
+// ChainTxFees are the fees that txs issued on a chain must currently burn
+type ChainTxFees struct {
+	CreationTxFee json.Uint64 `json:"creationTxFee"`
+	TxFee         json.Uint64 `json:"txFee"`
+}
+
 // GetTxFeeResponse ...
 type GetTxFeeResponse struct {
+	// Minimum fees, which every accepted tx burns
 	CreationTxFee json.Uint64 `json:"creationTxFee"`
 	TxFee         json.Uint64 `json:"txFee"`
+	// Chain alias --> fees that txs issued through this node must currently
+	// burn on the chain. These rise above the minimum fees when the chain is
+	// busy.
+	CurrentFees map[string]ChainTxFees `json:"currentFees"`
 }
 
 // GetTxFee returns the transaction fee in nAVAX.
 func (service *Info) GetTxFee(_ *http.Request, args *struct{}, reply *GetTxFeeResponse) error {
 	reply.CreationTxFee = json.Uint64(service.creationTxFee)
 	reply.TxFee = json.Uint64(service.txFee)
+	reply.CurrentFees = make(map[string]ChainTxFees)
+	if service.feeRegistry == nil {
+		return nil
+	}
+	for chainID, source := range service.feeRegistry.Sources() {
+		chainName := chainID.String()
+		if aliases := service.chainManager.Aliases(chainID); len(aliases) > 0 {
+			chainName = aliases[0]
+		}
+		reply.CurrentFees[chainName] = ChainTxFees{
+			CreationTxFee: json.Uint64(source.CurrentFee(service.creationTxFee)),
+			TxFee:         json.Uint64(source.CurrentFee(service.txFee)),
+		}
+	}
 	return nil
 }
 
@@ -85,7 +85,13 @@ func (s *Server) Dispatch() error {
 	if err != nil {
 		return err
 	}
-	s.log.Info("HTTP API server listening on %q", s.listenAddress)
+	return s.Serve(listener)
+}
+
+// Serve starts the API server on [listener], which is closed when the server
+// shuts down
+func (s *Server) Serve(listener net.Listener) error {
+	s.log.Info("HTTP API server listening on %q", listener.Addr())
 	handler := cors.Default().Handler(s.router)
 	handler = s.auth.WrapHandler(handler)
 	s.srv = &http.Server{Handler: handler}
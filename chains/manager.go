@@ -4,8 +4,11 @@
 package chains
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -23,6 +26,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/common/queue"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
@@ -31,7 +35,9 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/vms"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
 
 	avcon "github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	aveng "github.com/ava-labs/avalanchego/snow/engine/avalanche"
@@ -67,6 +73,12 @@ type Manager interface {
 	// created, [r].RegisterChain([new chain]) is called
 	AddRegistrant(Registrant)
 
+	// Add a blocking registrant [r]. Every time a chain is
+	// created, [r].RegisterChain([new chain]) is called
+	// before the chain starts processing messages. It runs
+	// inline on chain creation, so it must not block.
+	AddBlockingRegistrant(Registrant)
+
 	// Given an alias, return the ID of the chain associated with that alias
 	Lookup(string) (ids.ID, error)
 
@@ -138,6 +150,10 @@ type ManagerConfig struct {
 	WhitelistedSubnets      ids.Set          // Subnets to validate
 	TimeoutManager          *timeout.Manager // Manages request timeouts when sending messages to other validators
 	HealthService           health.CheckRegisterer
+	StateSyncEnabled        bool             // True iff chains should sync their state from a summary when possible
+	ProposerWindowsTime     time.Time        // Time that linear chains only accept blocks proposed in the proposer's window
+	StakingCert             *tls.Certificate // Signs the blocks this node proposes. nil if this node can't propose blocks.
+	ConsensusTraceDir       string           // Directory of the consensus trace of each chain. Consensus isn't traced if empty.
 }
 
 type manager struct {
@@ -146,7 +162,13 @@ type manager struct {
 	ids.Aliaser
 	ManagerConfig
 
-	registrants []Registrant // Those notified when a chain is created
+	registrants         []Registrant // Those notified when a chain is created
+	blockingRegistrants []Registrant // Those notified before a created chain starts processing messages
+
+	// The validator sets of the subnets at each height of the P-chain, which
+	// the proposers of the blocks of the other chains are sampled from. nil
+	// until the P-chain is created.
+	validatorState validators.State
 
 	unblocked     bool
 	blockedChains []ChainParameters
@@ -218,8 +240,19 @@ func (m *manager) ForceCreateChain(chainParams ChainParameters) {
 	// Associate the newly created chain with its default alias
 	m.Log.AssertNoError(m.Alias(chainParams.ID, chainParams.ID.String()))
 
-	// Notify those that registered to be notified when a new chain is created
+	// Notify those that registered to be notified when a new chain is created.
+	// Blocking registrants are notified before the chain starts processing
+	// messages so that they don't miss any of the chain's events.
 	m.notifyRegistrants(chain.Name, chain.Ctx, chain.VM)
+
+	// If the X or P Chain panics, do not attempt to recover
+	if m.CriticalChains.Contains(chainParams.ID) {
+		go chain.Ctx.Log.RecoverAndPanic(chain.Handler.Dispatch)
+	} else {
+		go chain.Ctx.Log.RecoverAndExit(chain.Handler.Dispatch, func() {
+			chain.Ctx.Log.Error("Chain with ID: %s was shutdown due to a panic", chainParams.ID)
+		})
+	}
 }
 
 // Create a chain
@@ -258,6 +291,7 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 		Metrics:              m.ConsensusParams.Metrics,
 		EpochFirstTransition: m.EpochFirstTransition,
 		EpochDuration:        m.EpochDuration,
+		PeerReporter:         m.Net,
 	}
 
 	// Get a factory for the vm we want to use on our chain
@@ -273,6 +307,10 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 	}
 	// TODO: Shutdown VM if an error occurs
 
+	if state, ok := vm.(validators.State); ok && chainParams.ID == constants.PlatformChainID {
+		m.validatorState = state
+	}
+
 	fxs := make([]*common.Fx, len(chainParams.FxAliases))
 	for i, fxAlias := range chainParams.FxAliases {
 		fxID, err := m.VMManager.Lookup(fxAlias)
@@ -361,21 +399,17 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 
 	// Allows messages to be routed to the new chain
 	m.ManagerConfig.Router.AddChain(chain.Handler)
-
-	// If the X or P Chain panics, do not attempt to recover
-	if m.CriticalChains.Contains(chainParams.ID) {
-		go ctx.Log.RecoverAndPanic(chain.Handler.Dispatch)
-	} else {
-		go ctx.Log.RecoverAndExit(chain.Handler.Dispatch, func() {
-			ctx.Log.Error("Chain with ID: %s was shutdown due to a panic", chainParams.ID)
-		})
-	}
 	return chain, nil
 }
 
 // Implements Manager.AddRegistrant
 func (m *manager) AddRegistrant(r Registrant) { m.registrants = append(m.registrants, r) }
 
+// Implements Manager.AddBlockingRegistrant
+func (m *manager) AddBlockingRegistrant(r Registrant) {
+	m.blockingRegistrants = append(m.blockingRegistrants, r)
+}
+
 func (m *manager) unblockChains() {
 	m.unblocked = true
 	blocked := m.blockedChains
@@ -418,6 +452,11 @@ func (m *manager) createAvalancheChain(
 	// VM uses this channel to notify engine that a block is ready to be made
 	msgChan := make(chan common.Message, defaultChannelSize)
 
+	// Passes messages from the consensus engine and the VM to the network
+	sender := sender.Sender{}
+	sender.Initialize(ctx, m.Net, m.ManagerConfig.Router, m.TimeoutManager)
+	ctx.AppSender = &sender
+
 	if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
 		return nil, fmt.Errorf("error during vm's Initialize: %w", err)
 	}
@@ -427,15 +466,16 @@ func (m *manager) createAvalancheChain(
 	vtxManager := &state.Serializer{}
 	vtxManager.Initialize(ctx, vm, vertexDB)
 
-	// Passes messages from the consensus engine to the network
-	sender := sender.Sender{}
-	sender.Initialize(ctx, m.Net, m.ManagerConfig.Router, m.TimeoutManager)
-
 	sampleK := consensusParams.K
 	if uint64(sampleK) > bootstrapWeight {
 		sampleK = int(bootstrapWeight)
 	}
 
+	consensusTracer, err := m.newTracer(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
 	// The engine handles consensus
 	engine := &aveng.Transitive{}
 	if err := engine.Initialize(aveng.Config{
@@ -456,6 +496,7 @@ func (m *manager) createAvalancheChain(
 		},
 		Params:    consensusParams,
 		Consensus: &avcon.Topological{},
+		Tracer:    consensusTracer,
 	}); err != nil {
 		return nil, fmt.Errorf("error initializing avalanche engine: %w", err)
 	}
@@ -524,20 +565,40 @@ func (m *manager) createSnowmanChain(
 	// VM uses this channel to notify engine that a block is ready to be made
 	msgChan := make(chan common.Message, defaultChannelSize)
 
+	// Passes messages from the consensus engine and the VM to the network
+	sender := sender.Sender{}
+	sender.Initialize(ctx, m.Net, m.ManagerConfig.Router, m.TimeoutManager)
+	ctx.AppSender = &sender
+
+	// Once proposer windows are scheduled, the chain runs the VM wrapped in
+	// them. The wrapper serves the inner VM's APIs, and syncs its state if the
+	// inner VM can.
+	engineVM := vm
+	if m.StakingEnabled && m.ProposerWindowsTime.Before(timer.MaxTime) && m.validatorState != nil {
+		proposerDB := prefixdb.New([]byte("proposervm"), db)
+		wrapper := proposervm.New(vm, m.ProposerWindowsTime, m.StakingCert, m.validatorState, proposerDB)
+		if _, ok := vm.(block.StateSyncableVM); ok {
+			engineVM = &proposervm.StateSyncableVM{VM: wrapper}
+		} else {
+			engineVM = wrapper
+		}
+	}
+
 	// Initialize the VM
-	if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
+	if err := engineVM.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
 		return nil, err
 	}
 
-	// Passes messages from the consensus engine to the network
-	sender := sender.Sender{}
-	sender.Initialize(ctx, m.Net, m.ManagerConfig.Router, m.TimeoutManager)
-
 	sampleK := consensusParams.K
 	if uint64(sampleK) > bootstrapWeight {
 		sampleK = int(bootstrapWeight)
 	}
 
+	consensusTracer, err := m.newTracer(ctx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
 	// The engine handles consensus
 	engine := &smeng.Transitive{}
 	if err := engine.Initialize(smeng.Config{
@@ -552,11 +613,13 @@ func (m *manager) createSnowmanChain(
 				Sender:       &sender,
 			},
 			Blocked:      blocked,
-			VM:           vm,
+			VM:           engineVM,
+			StateSync:    m.StateSyncEnabled,
 			Bootstrapped: m.unblockChains,
 		},
 		Params:    consensusParams,
 		Consensus: &smcon.Topological{},
+		Tracer:    consensusTracer,
 	}); err != nil {
 		return nil, fmt.Errorf("error initializing snowman engine: %w", err)
 	}
@@ -594,11 +657,35 @@ func (m *manager) createSnowmanChain(
 		Name:    chainAlias,
 		Engine:  engine,
 		Handler: handler,
-		VM:      vm,
+		VM:      engineVM,
 		Ctx:     ctx,
 	}, nil
 }
 
+// newTracer returns the tracer of the consensus of [chainID], which writes a
+// new trace in [m.ConsensusTraceDir] each time the chain is created. Returns
+// nil if consensus isn't traced.
+func (m *manager) newTracer(chainID ids.ID) (tracer.Tracer, error) {
+	if m.ConsensusTraceDir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(m.ConsensusTraceDir, 0700); err != nil {
+		return nil, fmt.Errorf("couldn't create consensus trace directory: %w", err)
+	}
+	path := filepath.Join(m.ConsensusTraceDir, fmt.Sprintf("%s-%d.trace", chainID, time.Now().Unix()))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create consensus trace: %w", err)
+	}
+	w, err := tracer.NewWriter(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("couldn't create consensus trace: %w", err)
+	}
+	m.Log.Info("tracing the consensus of chain %s to %s", chainID, path)
+	return w, nil
+}
+
 func (m *manager) SubnetID(chainID ids.ID) (ids.ID, error) {
 	m.chainsLock.Lock()
 	defer m.chainsLock.Unlock()
@@ -633,6 +720,9 @@ func (m *manager) LookupVM(alias string) (ids.ID, error) { return m.VMManager.Lo
 // Notify registrants [those who want to know about the creation of chains]
 // that the specified chain has been created
 func (m *manager) notifyRegistrants(name string, ctx *snow.Context, vm interface{}) {
+	for _, registrant := range m.blockingRegistrants {
+		registrant.RegisterChain(name, ctx, vm)
+	}
 	for _, registrant := range m.registrants {
 		go registrant.RegisterChain(name, ctx, vm)
 	}
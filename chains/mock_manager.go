@@ -21,6 +21,9 @@ func (mm MockManager) ForceCreateChain(ChainParameters) {}
 // AddRegistrant ...
 func (mm MockManager) AddRegistrant(Registrant) {}
 
+// AddBlockingRegistrant ...
+func (mm MockManager) AddBlockingRegistrant(Registrant) {}
+
 // Lookup ...
 func (mm MockManager) Lookup(s string) (ids.ID, error) {
 	id, err := ids.FromString(s)
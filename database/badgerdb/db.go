@@ -0,0 +1,435 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package badgerdb
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dgraph-io/badger"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/nodb"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+const (
+	// Name is the name of this database for database switches
+	Name = "badgerdb"
+
+	// LSMSizeProperty is the Stat property that reports the size, in bytes, of
+	// the LSM tree
+	LSMSizeProperty = "badger.lsm-size"
+
+	// VLogSizeProperty is the Stat property that reports the size, in bytes,
+	// of the value log
+	VLogSizeProperty = "badger.vlog-size"
+
+	// NumTablesProperty is the Stat property that reports the number of tables
+	// in the LSM tree
+	NumTablesProperty = "badger.num-tables"
+
+	// valueLogGCDiscardRatio is the fraction of a value log file that must be
+	// garbage for Compact to rewrite it
+	valueLogGCDiscardRatio = 0.5
+)
+
+// ErrBatchTooLarge is returned when a batch is too large to be written in a
+// single BadgerDB transaction. Nothing in the batch is written.
+var ErrBatchTooLarge = errors.New("batch is too large to be written atomically")
+
+// Database is a persistent key-value store backed by BadgerDB. Apart from
+// basic data storage functionality it also supports batch writes and
+// iterating over the keyspace in binary-alphabetical order.
+type Database struct {
+	lock sync.RWMutex
+	db   *badger.DB
+
+	// 1 if there was previously an error other than "not found" or "closed"
+	// while performing a db operation. If [errored] == 1, Has, Get, Put,
+	// Delete and batch writes fail with ErrAvoidCorruption.
+	// The node should shut down.
+	// Accessed atomically, as it's set while only holding the read lock.
+	errored uint32
+}
+
+// New returns a wrapped BadgerDB object stored in the directory [file].
+func New(file string) (*Database, error) {
+	db, err := badger.Open(badger.DefaultOptions(file).
+		WithLogger(nil).
+		// Drop any partially written data at the end of the value log rather
+		// than refusing to open
+		WithTruncate(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Database{db: db}, nil
+}
+
+// NewReadOnly returns a wrapped BadgerDB object that fails any writes. The
+// database must already exist.
+func NewReadOnly(file string) (*Database, error) {
+	db, err := badger.Open(badger.DefaultOptions(file).
+		WithLogger(nil).
+		WithReadOnly(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Database{db: db}, nil
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if err := db.checkUsable(); err != nil {
+		return false, err
+	}
+	err := db.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(key)
+		return err
+	})
+	switch err = db.handleError(err); err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if err := db.checkUsable(); err != nil {
+		return nil, err
+	}
+	var value []byte
+	err := db.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	return value, db.handleError(err)
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if err := db.checkUsable(); err != nil {
+		return err
+	}
+	return db.handleError(db.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(utils.CopyBytes(key), utils.CopyBytes(value))
+	}))
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if err := db.checkUsable(); err != nil {
+		return err
+	}
+	return db.handleError(db.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(utils.CopyBytes(key))
+	}))
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch { return &batch{db: db} }
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+
+	seek := utils.CopyBytes(prefix)
+	if bytes.Compare(start, prefix) == 1 {
+		seek = utils.CopyBytes(start)
+	}
+	txn := db.db.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = utils.CopyBytes(prefix)
+	return &iter{
+		txn:  txn,
+		it:   txn.NewIterator(opts),
+		seek: seek,
+	}
+}
+
+// Stat returns a particular internal stat of the database. The supported
+// properties are [LSMSizeProperty], [VLogSizeProperty] and
+// [NumTablesProperty].
+func (db *Database) Stat(property string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return "", database.ErrClosed
+	}
+	switch property {
+	case LSMSizeProperty:
+		lsm, _ := db.db.Size()
+		return strconv.FormatInt(lsm, 10), nil
+	case VLogSizeProperty:
+		_, vlog := db.db.Size()
+		return strconv.FormatInt(vlog, 10), nil
+	case NumTablesProperty:
+		return strconv.Itoa(len(db.db.Tables(false))), nil
+	default:
+		return "", database.ErrNotFound
+	}
+}
+
+// Compact merges the LSM tree into as few levels as possible and rewrites
+// value log files that are mostly garbage. BadgerDB can't compact a single key
+// range, so the whole database is always compacted and [start] and [limit]
+// are ignored.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	if err := db.db.Flatten(1); err != nil {
+		return db.handleError(err)
+	}
+	for {
+		switch err := db.db.RunValueLogGC(valueLogGCDiscardRatio); err {
+		case nil:
+		case badger.ErrNoRewrite, badger.ErrRejected:
+			return nil
+		default:
+			return db.handleError(err)
+		}
+	}
+}
+
+// Close implements the Database interface
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	err := db.db.Close()
+	db.db = nil
+	return db.handleError(err)
+}
+
+// checkUsable returns an error if the database can't be used. Assumes [db.lock]
+// is held.
+func (db *Database) checkUsable() error {
+	switch {
+	case db.db == nil:
+		return database.ErrClosed
+	case atomic.LoadUint32(&db.errored) == 1:
+		return database.ErrAvoidCorruption
+	default:
+		return nil
+	}
+}
+
+func (db *Database) handleError(err error) error {
+	err = updateError(err)
+	// If we get an error other than "not found" or "closed", disallow future
+	// database operations to avoid possible corruption
+	if err != nil && err != database.ErrNotFound && err != database.ErrClosed {
+		atomic.StoreUint32(&db.errored, 1)
+	}
+	return err
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch buffers writes in memory until they are committed in a single
+// BadgerDB transaction.
+type batch struct {
+	db     *Database
+	writes []keyValue
+	size   int
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), utils.CopyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{utils.CopyBytes(key), nil, true})
+	b.size++
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int { return b.size }
+
+// Write flushes any accumulated data to disk in a single BadgerDB
+// transaction. If the batch is too large for a single transaction, nothing is
+// written and ErrBatchTooLarge is returned.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if err := b.db.checkUsable(); err != nil {
+		return err
+	}
+
+	txn := b.db.db.NewTransaction(true)
+	defer txn.Discard()
+	for _, kv := range b.writes {
+		switch err := b.write(txn, kv); err {
+		case nil:
+		case badger.ErrTxnTooBig:
+			return ErrBatchTooLarge
+		default:
+			return b.db.handleError(err)
+		}
+	}
+	return b.db.handleError(txn.Commit())
+}
+
+func (b *batch) write(txn *badger.Txn, kv keyValue) error {
+	if kv.delete {
+		return txn.Delete(kv.key)
+	}
+	return txn.Set(kv.key, kv.value)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	if cap(b.writes) > len(b.writes)*database.MaxExcessCapacityFactor {
+		b.writes = make([]keyValue, 0, cap(b.writes)/database.CapacityReductionFactor)
+	} else {
+		b.writes = b.writes[:0]
+	}
+	b.size = 0
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+// iter iterates over a snapshot of the database taken when it was created
+type iter struct {
+	txn  *badger.Txn
+	it   *badger.Iterator
+	seek []byte
+
+	initialized bool
+	key, value  []byte
+	err         error
+}
+
+// Next implements the Iterator interface
+func (it *iter) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.initialized {
+		it.initialized = true
+		it.it.Seek(it.seek)
+	} else if it.it.Valid() {
+		it.it.Next()
+	}
+	if !it.it.Valid() {
+		it.key = nil
+		it.value = nil
+		return false
+	}
+
+	item := it.it.Item()
+	it.key = item.KeyCopy(nil)
+	it.value, it.err = item.ValueCopy(nil)
+	if it.err != nil {
+		it.key = nil
+		it.value = nil
+		return false
+	}
+	return true
+}
+
+// Error implements the Iterator interface
+func (it *iter) Error() error { return updateError(it.err) }
+
+// Key implements the Iterator interface
+func (it *iter) Key() []byte { return it.key }
+
+// Value implements the Iterator interface
+func (it *iter) Value() []byte { return it.value }
+
+// Release implements the Iterator interface
+func (it *iter) Release() {
+	it.it.Close()
+	it.txn.Discard()
+}
+
+func updateError(err error) error {
+	if err == badger.ErrKeyNotFound {
+		return database.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package badgerdb
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		folder, err := ioutil.TempDir("", "badgerdb")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(folder)
+
+		db, err := New(folder)
+		if err != nil {
+			t.Fatalf("badgerdb.New(%s) errored with %s", folder, err)
+		}
+		defer db.Close()
+
+		test(t, db)
+	}
+}
+
+func TestBatchTooLarge(t *testing.T) {
+	folder, err := ioutil.TempDir("", "badgerdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	db, err := New(folder)
+	if err != nil {
+		t.Fatalf("badgerdb.New(%s) errored with %s", folder, err)
+	}
+	defer db.Close()
+
+	// The batch has more writes than fit in a single transaction
+	batch := db.NewBatch()
+	key := make([]byte, 8)
+	for i := 0; i < 1<<20; i++ {
+		binary.BigEndian.PutUint64(key, uint64(i))
+		if err := batch.Put(key, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := batch.Write(); err != ErrBatchTooLarge {
+		t.Fatalf("expected %s but got %v", ErrBatchTooLarge, err)
+	}
+
+	// Nothing in the batch was written and the database is still usable
+	binary.BigEndian.PutUint64(key, 0)
+	if has, err := db.Has(key); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("a write of a batch that was too large was committed")
+	}
+	if err := db.Put(key, key); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,41 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+const (
+	// copyBatchSize is the number of bytes of values, and copyBatchLen is the
+	// number of pairs, buffered before a batch is written to the destination
+	// database while copying
+	copyBatchSize = 4 * 1024 * 1024
+	copyBatchLen  = 16 * 1024
+)
+
+// Copy writes every key/value pair in [src] to [dst] and returns the number of
+// pairs copied. The pairs are written in batches, so if an error is returned
+// [dst] may contain a prefix of [src].
+func Copy(dst Batcher, src Iteratee) (int, error) {
+	it := src.NewIterator()
+	defer it.Release()
+
+	batch := dst.NewBatch()
+	numCopied := 0
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return numCopied, err
+		}
+		numCopied++
+
+		if batch.ValueSize() < copyBatchSize && numCopied%copyBatchLen != 0 {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return numCopied, err
+		}
+		batch.Reset()
+	}
+	if err := it.Error(); err != nil {
+		return numCopied, err
+	}
+	return numCopied, batch.Write()
+}
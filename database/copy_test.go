@@ -0,0 +1,41 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func TestCopy(t *testing.T) {
+	src := memdb.New()
+	dst := memdb.New()
+
+	keys := [][]byte{{0}, {1}, {1, 2}, {3}}
+	for i, key := range keys {
+		if err := src.Put(key, []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	numCopied, err := database.Copy(dst, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numCopied != len(keys) {
+		t.Fatalf("copied %d pairs but should have copied %d", numCopied, len(keys))
+	}
+	for i, key := range keys {
+		value, err := dst.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(value, []byte{byte(i)}) {
+			t.Fatalf("key 0x%x has value 0x%x but should have 0x%x", key, value, []byte{byte(i)})
+		}
+	}
+}
@@ -18,6 +18,9 @@ import (
 )
 
 const (
+	// Name is the name of this database for database switches
+	Name = "leveldb"
+
 	// minBlockCacheSize is the minimum number of bytes to use for block caching
 	// in leveldb.
 	minBlockCacheSize = 8 * opt.MiB
@@ -74,6 +77,19 @@ func New(file string, blockCacheSize, writeBufferSize, handleCap int) (*Database
 	return &Database{DB: db}, nil
 }
 
+// NewReadOnly returns a wrapped LevelDB object that fails any writes. The
+// database isn't recovered if it is corrupted.
+func NewReadOnly(file string) (*Database, error) {
+	db, err := leveldb.OpenFile(file, &opt.Options{
+		ReadOnly:       true,
+		ErrorIfMissing: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Database{DB: db}, nil
+}
+
 // Has returns if the key is set in the database
 func (db *Database) Has(key []byte) (bool, error) {
 	if db.errored {
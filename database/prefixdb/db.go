@@ -52,6 +52,24 @@ func NewNested(prefix []byte, db database.Database) *Database {
 	}
 }
 
+// MakePrefix returns the prefix that is added to every key of the database
+// returned by calling New with each of [prefixes] in turn. That is,
+// MakePrefix(a, b) is the prefix of New(b, New(a, db)).
+func MakePrefix(prefixes ...[]byte) []byte {
+	var prefix []byte
+	for _, p := range prefixes {
+		if prefix == nil {
+			prefix = hashing.ComputeHash256(p)
+			continue
+		}
+		simplePrefix := make([]byte, len(prefix)+len(p))
+		copy(simplePrefix, prefix)
+		copy(simplePrefix[len(prefix):], p)
+		prefix = hashing.ComputeHash256(simplePrefix)
+	}
+	return prefix
+}
+
 // Has implements the Database interface
 // Assumes that it is OK for the argument to db.db.Has
 // to be modified after db.db.Has returns
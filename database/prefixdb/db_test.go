@@ -4,6 +4,7 @@
 package prefixdb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/database"
@@ -21,3 +22,18 @@ func TestInterface(t *testing.T) {
 		test(t, NewNested([]byte("ld"), New([]byte("wor"), db)))
 	}
 }
+
+func TestMakePrefix(t *testing.T) {
+	baseDB := memdb.New()
+	db := New([]byte("ld"), New([]byte("wor"), baseDB))
+	if err := db.Put([]byte{1}, []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := MakePrefix([]byte("wor"), []byte("ld"))
+	if value, err := baseDB.Get(append(prefix, 1)); err != nil {
+		t.Fatalf("key should be under prefix 0x%x: %s", prefix, err)
+	} else if !bytes.Equal(value, []byte{2}) {
+		t.Fatalf("expected value 0x02 but got 0x%x", value)
+	}
+}
@@ -105,5 +105,7 @@ var (
 		EpochFirstTransition: time.Unix(1607626800, 0),
 		EpochDuration:        5 * time.Minute,
 		ApricotPhase0Time:    time.Date(2020, 12, 5, 5, 00, 0, 0, time.UTC),
+		MempoolGossipTime:    time.Date(2020, 12, 5, 5, 00, 0, 0, time.UTC),
+		ProposerWindowsTime:  time.Date(2020, 12, 5, 5, 00, 0, 0, time.UTC),
 	}
 )
@@ -6,6 +6,7 @@ package genesis
 import (
 	"time"
 
+	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/units"
 )
 
@@ -175784,5 +175785,7 @@ var (
 		EpochFirstTransition: time.Unix(1607626800, 0),
 		EpochDuration:        6 * time.Hour,
 		ApricotPhase0Time:    time.Date(2020, 12, 8, 3, 00, 0, 0, time.UTC),
+		MempoolGossipTime:    timer.MaxTime, // Not scheduled yet
+		ProposerWindowsTime:  timer.MaxTime, // Not scheduled yet
 	}
 )
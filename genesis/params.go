@@ -42,6 +42,11 @@ type Params struct {
 	EpochDuration time.Duration
 	// Time that Apricot phase 0 rules go into effect
 	ApricotPhase0Time time.Time
+	// Time that the P-chain starts gossiping the txs in its mempool
+	MempoolGossipTime time.Time
+	// Time that only the proposers of each height may build the blocks of
+	// linear chains in their windows
+	ProposerWindowsTime time.Time
 }
 
 // GetParams ...
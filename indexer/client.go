@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+)
+
+// Client for the index API of a single index
+type Client struct {
+	requester rpc.EndpointRequester
+}
+
+// NewClient returns a Client for interacting with the index at
+// /ext/index/[chain]/[indexType]. For example, [chain] "X" with [indexType]
+// "tx" queries the accepted transactions of the X-Chain.
+func NewClient(uri, chain, indexType string, requestTimeout time.Duration) *Client {
+	return &Client{
+		requester: rpc.NewEndpointRequester(uri, fmt.Sprintf("/ext/index/%s/%s", chain, indexType), "index", requestTimeout),
+	}
+}
+
+// GetLastAccepted returns the most recently accepted container
+func (c *Client) GetLastAccepted(encoding formatting.Encoding) (*FormattedContainer, error) {
+	res := &FormattedContainer{}
+	err := c.requester.SendRequest("getLastAccepted", &GetLastAcceptedArgs{
+		Encoding: encoding,
+	}, res)
+	return res, err
+}
+
+// GetContainerByIndex returns the container with the given index
+func (c *Client) GetContainerByIndex(index uint64, encoding formatting.Encoding) (*FormattedContainer, error) {
+	res := &FormattedContainer{}
+	err := c.requester.SendRequest("getContainerByIndex", &GetContainerByIndexArgs{
+		Index:    json.Uint64(index),
+		Encoding: encoding,
+	}, res)
+	return res, err
+}
+
+// GetContainerRange returns up to [numToFetch] containers starting at [startIndex]
+func (c *Client) GetContainerRange(startIndex, numToFetch uint64, encoding formatting.Encoding) ([]FormattedContainer, error) {
+	res := &GetContainerRangeResponse{}
+	err := c.requester.SendRequest("getContainerRange", &GetContainerRangeArgs{
+		StartIndex: json.Uint64(startIndex),
+		NumToFetch: json.Uint64(numToFetch),
+		Encoding:   encoding,
+	}, res)
+	return res.Containers, err
+}
+
+// GetIndex returns the index of the given container
+func (c *Client) GetIndex(containerID ids.ID) (uint64, error) {
+	res := &GetIndexResponse{}
+	err := c.requester.SendRequest("getIndex", &GetIndexArgs{
+		ContainerID: containerID,
+	}, res)
+	return uint64(res.Index), err
+}
+
+// IsAccepted returns true iff the given container has been accepted
+func (c *Client) IsAccepted(containerID ids.ID) (bool, error) {
+	res := &IsAcceptedResponse{}
+	err := c.requester.SendRequest("isAccepted", &GetIndexArgs{
+		ContainerID: containerID,
+	}, res)
+	return res.IsAccepted, err
+}
@@ -0,0 +1,242 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+const (
+	// MaxFetchedByRange is the maximum number of containers that can be
+	// fetched in a single call to GetContainerRange
+	MaxFetchedByRange = 1024
+)
+
+var (
+	indexToContainerPrefix = []byte{0x01}
+	containerToIndexPrefix = []byte{0x02}
+	nextAcceptedIndexKey   = []byte{0x03}
+
+	errNoneAccepted        = errors.New("no containers have been accepted")
+	errInvalidNumToFetch   = fmt.Errorf("numToFetch must be in [1, %d]", MaxFetchedByRange)
+	errNoContainerAtIndex  = errors.New("no container at the given index")
+	errUnknownContainerID  = errors.New("container has not been accepted")
+	errStartIndexTooLarge  = errors.New("start index is greater than the last accepted index")
+	errUnexpectedIndexSize = errors.New("stored index has an unexpected size")
+)
+
+// index maintains the accepted containers of a single type (block,
+// transaction or vertex) for a single chain, in the order they were accepted.
+// It is safe for concurrent use.
+type index struct {
+	lock  sync.RWMutex
+	log   logging.Logger
+	codec codec.Manager
+	clock timer.Clock
+
+	// The index that will be assigned to the next accepted container
+	nextAcceptedIndex uint64
+
+	// All writes go through [vDB] and are committed atomically per container
+	vDB *versiondb.Database
+	// Index --> Container
+	indexToContainer database.Database
+	// Container ID --> Index
+	containerToIndex database.Database
+}
+
+// newIndex returns a new index that stores its containers in [db]. If [db]
+// already contains an index, the index picks up where it left off.
+func newIndex(db database.Database, log logging.Logger, codec codec.Manager) (*index, error) {
+	vDB := versiondb.New(db)
+	i := &index{
+		log:              log,
+		codec:            codec,
+		vDB:              vDB,
+		indexToContainer: prefixdb.New(indexToContainerPrefix, vDB),
+		containerToIndex: prefixdb.New(containerToIndexPrefix, vDB),
+	}
+
+	nextAcceptedIndexBytes, err := vDB.Get(nextAcceptedIndexKey)
+	switch err {
+	case nil:
+		i.nextAcceptedIndex, err = unpackIndex(nextAcceptedIndexBytes)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse next accepted index: %w", err)
+		}
+	case database.ErrNotFound:
+		// This index has never accepted anything
+	default:
+		return nil, fmt.Errorf("couldn't read next accepted index: %w", err)
+	}
+	return i, nil
+}
+
+// Accept stores [containerBytes] as the next accepted container. If
+// [containerID] was already indexed, this is a no-op.
+// Implements triggers.Acceptor
+func (i *index) Accept(ctx *snow.Context, containerID ids.ID, containerBytes []byte) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if has, err := i.containerToIndex.Has(containerID[:]); err != nil {
+		return err
+	} else if has {
+		i.log.Debug("container %s of chain %s was already indexed", containerID, ctx.ChainID)
+		return nil
+	}
+
+	bytes, err := i.codec.Marshal(codecVersion, Container{
+		ID:        containerID,
+		Bytes:     containerBytes,
+		Timestamp: i.clock.Time().UnixNano(),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't serialize container %s: %w", containerID, err)
+	}
+
+	indexBytes := packIndex(i.nextAcceptedIndex)
+	if err := i.indexToContainer.Put(indexBytes, bytes); err != nil {
+		i.vDB.Abort()
+		return fmt.Errorf("couldn't put container %s at index %d: %w", containerID, i.nextAcceptedIndex, err)
+	}
+	if err := i.containerToIndex.Put(containerID[:], indexBytes); err != nil {
+		i.vDB.Abort()
+		return fmt.Errorf("couldn't map container %s to index %d: %w", containerID, i.nextAcceptedIndex, err)
+	}
+	if err := i.vDB.Put(nextAcceptedIndexKey, packIndex(i.nextAcceptedIndex+1)); err != nil {
+		i.vDB.Abort()
+		return fmt.Errorf("couldn't update next accepted index: %w", err)
+	}
+	if err := i.vDB.Commit(); err != nil {
+		i.vDB.Abort()
+		return fmt.Errorf("couldn't commit container %s: %w", containerID, err)
+	}
+
+	i.log.Verbo("indexed container %s of chain %s at index %d", containerID, ctx.ChainID, i.nextAcceptedIndex)
+	i.nextAcceptedIndex++
+	return nil
+}
+
+// GetContainerByIndex returns the container with index [index]
+func (i *index) GetContainerByIndex(index uint64) (Container, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.getContainerByIndex(index)
+}
+
+// Assumes [i.lock] is held
+func (i *index) getContainerByIndex(index uint64) (Container, error) {
+	if index >= i.nextAcceptedIndex {
+		return Container{}, errNoContainerAtIndex
+	}
+	containerBytes, err := i.indexToContainer.Get(packIndex(index))
+	if err != nil {
+		return Container{}, fmt.Errorf("couldn't read container at index %d: %w", index, err)
+	}
+	container := Container{}
+	if _, err := i.codec.Unmarshal(containerBytes, &container); err != nil {
+		return Container{}, fmt.Errorf("couldn't parse container at index %d: %w", index, err)
+	}
+	return container, nil
+}
+
+// GetContainerRange returns up to [numToFetch] containers, starting from the
+// container with index [startIndex]. Returns fewer than [numToFetch] containers
+// if fewer have been accepted since [startIndex].
+func (i *index) GetContainerRange(startIndex, numToFetch uint64) ([]Container, error) {
+	if numToFetch == 0 || numToFetch > MaxFetchedByRange {
+		return nil, errInvalidNumToFetch
+	}
+
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if i.nextAcceptedIndex == 0 {
+		return nil, errNoneAccepted
+	}
+	if startIndex >= i.nextAcceptedIndex {
+		return nil, errStartIndexTooLarge
+	}
+
+	// Don't fetch past the last accepted container
+	lastIndex := startIndex + numToFetch - 1
+	if lastIndex >= i.nextAcceptedIndex || lastIndex < startIndex {
+		lastIndex = i.nextAcceptedIndex - 1
+	}
+
+	containers := make([]Container, 0, lastIndex-startIndex+1)
+	for index := startIndex; index <= lastIndex; index++ {
+		container, err := i.getContainerByIndex(index)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// GetLastAccepted returns the most recently accepted container and its index
+func (i *index) GetLastAccepted() (Container, uint64, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if i.nextAcceptedIndex == 0 {
+		return Container{}, 0, errNoneAccepted
+	}
+	lastIndex := i.nextAcceptedIndex - 1
+	container, err := i.getContainerByIndex(lastIndex)
+	return container, lastIndex, err
+}
+
+// GetIndex returns the index of the container with ID [containerID]
+func (i *index) GetIndex(containerID ids.ID) (uint64, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	indexBytes, err := i.containerToIndex.Get(containerID[:])
+	switch err {
+	case nil:
+		return unpackIndex(indexBytes)
+	case database.ErrNotFound:
+		return 0, errUnknownContainerID
+	default:
+		return 0, err
+	}
+}
+
+// IsAccepted returns true iff [containerID] has been indexed
+func (i *index) IsAccepted(containerID ids.ID) (bool, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.containerToIndex.Has(containerID[:])
+}
+
+// Keys are big endian so that iteration order matches acceptance order
+func packIndex(index uint64) []byte {
+	bytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(bytes, index)
+	return bytes
+}
+
+func unpackIndex(bytes []byte) (uint64, error) {
+	if len(bytes) != 8 {
+		return 0, errUnexpectedIndexSize
+	}
+	return binary.BigEndian.Uint64(bytes), nil
+}
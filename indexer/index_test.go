@@ -0,0 +1,215 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errTestWrite = errors.New("non-nil test write error")
+
+// failingWriteDB is a database whose batches can't be written
+type failingWriteDB struct{ database.Database }
+
+func (db failingWriteDB) NewBatch() database.Batch { return failingWriteBatch{db.Database.NewBatch()} }
+
+type failingWriteBatch struct{ database.Batch }
+
+func (failingWriteBatch) Write() error { return errTestWrite }
+
+func testCodec(t *testing.T) codec.Manager {
+	manager := codec.NewManager(math.MaxInt32)
+	if err := manager.RegisterCodec(codecVersion, linearcodec.NewDefault()); err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func TestIndex(t *testing.T) {
+	db := memdb.New()
+	ctx := snow.DefaultContextTest()
+	idx, err := newIndex(db, logging.NoLog{}, testCodec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := idx.GetLastAccepted(); err != errNoneAccepted {
+		t.Fatalf("expected %s but got %s", errNoneAccepted, err)
+	}
+
+	containers := make([]Container, 5)
+	for i := range containers {
+		containers[i] = Container{
+			ID:    ids.GenerateTestID(),
+			Bytes: []byte{byte(i)},
+		}
+		if err := idx.Accept(ctx, containers[i].ID, containers[i].Bytes); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Accepting the same container twice shouldn't change the index
+	if err := idx.Accept(ctx, containers[0].ID, containers[0].Bytes); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expected := range containers {
+		container, err := idx.GetContainerByIndex(uint64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if container.ID != expected.ID || !bytes.Equal(container.Bytes, expected.Bytes) {
+			t.Fatalf("wrong container at index %d", i)
+		}
+
+		index, err := idx.GetIndex(expected.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index != uint64(i) {
+			t.Fatalf("expected index %d but got %d", i, index)
+		}
+
+		if accepted, err := idx.IsAccepted(expected.ID); err != nil {
+			t.Fatal(err)
+		} else if !accepted {
+			t.Fatalf("container %s should be accepted", expected.ID)
+		}
+	}
+
+	if _, err := idx.GetContainerByIndex(uint64(len(containers))); err != errNoContainerAtIndex {
+		t.Fatalf("expected %s but got %s", errNoContainerAtIndex, err)
+	}
+	if _, err := idx.GetIndex(ids.GenerateTestID()); err != errUnknownContainerID {
+		t.Fatalf("expected %s but got %s", errUnknownContainerID, err)
+	}
+
+	lastAccepted, lastIndex, err := idx.GetLastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastAccepted.ID != containers[len(containers)-1].ID || lastIndex != uint64(len(containers)-1) {
+		t.Fatalf("wrong last accepted container")
+	}
+}
+
+func TestIndexGetContainerRange(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	idx, err := newIndex(memdb.New(), logging.NoLog{}, testCodec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := idx.GetContainerRange(0, 1); err != errNoneAccepted {
+		t.Fatalf("expected %s but got %s", errNoneAccepted, err)
+	}
+
+	containerIDs := make([]ids.ID, 10)
+	for i := range containerIDs {
+		containerIDs[i] = ids.GenerateTestID()
+		if err := idx.Accept(ctx, containerIDs[i], []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := idx.GetContainerRange(0, 0); err != errInvalidNumToFetch {
+		t.Fatalf("expected %s but got %s", errInvalidNumToFetch, err)
+	}
+	if _, err := idx.GetContainerRange(0, MaxFetchedByRange+1); err != errInvalidNumToFetch {
+		t.Fatalf("expected %s but got %s", errInvalidNumToFetch, err)
+	}
+	if _, err := idx.GetContainerRange(uint64(len(containerIDs)), 1); err != errStartIndexTooLarge {
+		t.Fatalf("expected %s but got %s", errStartIndexTooLarge, err)
+	}
+
+	containers, err := idx.GetContainerRange(3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 4 {
+		t.Fatalf("expected 4 containers but got %d", len(containers))
+	}
+	for i, container := range containers {
+		if container.ID != containerIDs[3+i] {
+			t.Fatalf("wrong container at position %d", i)
+		}
+	}
+
+	// Fetching past the last accepted container returns what's there
+	containers, err = idx.GetContainerRange(8, MaxFetchedByRange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers but got %d", len(containers))
+	}
+}
+
+func TestIndexRestart(t *testing.T) {
+	db := memdb.New()
+	ctx := snow.DefaultContextTest()
+	idx, err := newIndex(db, logging.NoLog{}, testCodec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstID := ids.GenerateTestID()
+	if err := idx.Accept(ctx, firstID, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-open the index on the same database
+	idx, err = newIndex(db, logging.NoLog{}, testCodec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondID := ids.GenerateTestID()
+	if err := idx.Accept(ctx, secondID, []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if index, err := idx.GetIndex(firstID); err != nil {
+		t.Fatal(err)
+	} else if index != 0 {
+		t.Fatalf("expected index 0 but got %d", index)
+	}
+	if index, err := idx.GetIndex(secondID); err != nil {
+		t.Fatal(err)
+	} else if index != 1 {
+		t.Fatalf("expected index 1 but got %d", index)
+	}
+}
+
+func TestIndexAcceptCommitFailure(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	idx, err := newIndex(failingWriteDB{memdb.New()}, logging.NoLog{}, testCodec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	containerID := ids.GenerateTestID()
+	if err := idx.Accept(ctx, containerID, []byte{1}); !errors.Is(err, errTestWrite) {
+		t.Fatalf("expected %s but got %v", errTestWrite, err)
+	}
+
+	// The container that failed to be committed shouldn't be left pending
+	if _, err := idx.GetIndex(containerID); err != errUnknownContainerID {
+		t.Fatalf("expected %s but got %v", errUnknownContainerID, err)
+	}
+	if _, _, err := idx.GetLastAccepted(); err == nil {
+		t.Fatal("shouldn't have accepted a container")
+	}
+}
@@ -0,0 +1,193 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	codecVersion = 0
+
+	// Identifier the indexer uses when registering with event dispatchers
+	indexerIdentifier = "indexer"
+
+	txPrefix    = "tx"
+	vtxPrefix   = "vtx"
+	blockPrefix = "block"
+)
+
+// RouteAdder adds HTTP routes. Implemented by *api.Server
+type RouteAdder interface {
+	AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string, loggingWriter io.Writer) error
+}
+
+// Config for an Indexer
+type Config struct {
+	// Database the indexes are stored in
+	DB                  database.Database
+	Log                 logging.Logger
+	DecisionDispatcher  *triggers.EventDispatcher
+	ConsensusDispatcher *triggers.EventDispatcher
+	APIServer           RouteAdder
+}
+
+// Indexer stores the accepted transactions and vertices of Avalanche chains,
+// and the accepted blocks of Snowman chains, so that they can be queried by
+// the order in which they were accepted. Each chain is indexed in its own
+// prefixed database, so the indexes persist across restarts.
+// Implements chains.Registrant
+type Indexer struct {
+	lock sync.Mutex
+
+	db                  database.Database
+	log                 logging.Logger
+	codec               codec.Manager
+	decisionDispatcher  *triggers.EventDispatcher
+	consensusDispatcher *triggers.EventDispatcher
+	apiServer           RouteAdder
+
+	// Chain ID --> Index type --> Index
+	indexes map[ids.ID]map[string]*index
+
+	closed bool
+}
+
+// NewIndexer returns a new Indexer. It doesn't index anything until chains
+// are registered with it.
+func NewIndexer(config Config) (*Indexer, error) {
+	c := linearcodec.NewDefault()
+	// Containers may be larger than the default max size
+	manager := codec.NewManager(math.MaxInt32)
+	if err := manager.RegisterCodec(codecVersion, c); err != nil {
+		return nil, err
+	}
+	return &Indexer{
+		db:                  config.DB,
+		log:                 config.Log,
+		codec:               manager,
+		decisionDispatcher:  config.DecisionDispatcher,
+		consensusDispatcher: config.ConsensusDispatcher,
+		apiServer:           config.APIServer,
+		indexes:             make(map[ids.ID]map[string]*index),
+	}, nil
+}
+
+// RegisterChain starts indexing the chain described by [ctx]. For Avalanche
+// chains, accepted vertices and transactions are indexed. For Snowman chains,
+// accepted blocks are indexed. This must be called before the chain accepts
+// anything, otherwise the index will be incomplete, so the indexer is added to
+// the chain manager as a blocking registrant.
+// Implements chains.Registrant
+func (i *Indexer) RegisterChain(name string, ctx *snow.Context, vm interface{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.closed {
+		i.log.Debug("not indexing chain %s because the indexer is closed", name)
+		return
+	}
+	if _, exists := i.indexes[ctx.ChainID]; exists {
+		i.log.Warn("chain %s is already being indexed", ctx.ChainID)
+		return
+	}
+
+	var err error
+	switch vm.(type) {
+	case vertex.DAGVM:
+		errs := wrappers.Errs{}
+		errs.Add(
+			i.registerIndex(name, ctx.ChainID, vtxPrefix, i.consensusDispatcher),
+			i.registerIndex(name, ctx.ChainID, txPrefix, i.decisionDispatcher),
+		)
+		err = errs.Err
+	case block.ChainVM:
+		err = i.registerIndex(name, ctx.ChainID, blockPrefix, i.consensusDispatcher)
+	default:
+		i.log.Error("not indexing chain %s because its VM has an unexpected type %T", name, vm)
+		return
+	}
+	if err != nil {
+		i.log.Error("couldn't index chain %s: %s", name, err)
+	}
+}
+
+// registerIndex creates an index of type [indexType] for chain [chainID],
+// registers it with [dispatcher] and exposes it at /ext/index/[name]/[indexType].
+// Assumes [i.lock] is held.
+func (i *Indexer) registerIndex(name string, chainID ids.ID, indexType string, dispatcher *triggers.EventDispatcher) error {
+	chainDB := prefixdb.New(chainID[:], i.db)
+	idx, err := newIndex(prefixdb.New([]byte(indexType), chainDB), i.log, i.codec)
+	if err != nil {
+		return fmt.Errorf("couldn't create %s index: %w", indexType, err)
+	}
+	if err := dispatcher.RegisterChain(chainID, indexerIdentifier, idx); err != nil {
+		return fmt.Errorf("couldn't register %s index: %w", indexType, err)
+	}
+
+	chainIndexes, exists := i.indexes[chainID]
+	if !exists {
+		chainIndexes = make(map[string]*index)
+		i.indexes[chainID] = chainIndexes
+	}
+	chainIndexes[indexType] = idx
+
+	handler, err := newServiceHandler(&service{index: idx})
+	if err != nil {
+		return fmt.Errorf("couldn't create %s index API handler: %w", indexType, err)
+	}
+	return i.apiServer.AddRoute(handler, &sync.RWMutex{}, "index/"+name, "/"+indexType, i.log)
+}
+
+// Close stops indexing all chains
+func (i *Indexer) Close() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.closed {
+		return nil
+	}
+	i.closed = true
+
+	errs := wrappers.Errs{}
+	for chainID, chainIndexes := range i.indexes {
+		for indexType := range chainIndexes {
+			dispatcher := i.consensusDispatcher
+			if indexType == txPrefix {
+				dispatcher = i.decisionDispatcher
+			}
+			errs.Add(dispatcher.DeregisterChain(chainID, indexerIdentifier))
+		}
+	}
+	i.indexes = nil
+	return errs.Err
+}
+
+func newServiceHandler(s *service) (*common.HTTPHandler, error) {
+	server := rpc.NewServer()
+	codec := json.NewCodec()
+	server.RegisterCodec(codec, "application/json")
+	server.RegisterCodec(codec, "application/json;charset=UTF-8")
+	// The index is safe for concurrent use so no lock needs to be grabbed
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: server}, server.RegisterService(s, "index")
+}
@@ -0,0 +1,123 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+type testRouteAdder struct {
+	routes map[string]struct{}
+}
+
+func (r *testRouteAdder) AddRoute(_ *common.HTTPHandler, _ *sync.RWMutex, base, endpoint string, _ io.Writer) error {
+	r.routes[base+endpoint] = struct{}{}
+	return nil
+}
+
+func newTestIndexer(t *testing.T) (*Indexer, *triggers.EventDispatcher, *triggers.EventDispatcher, *testRouteAdder) {
+	decisionDispatcher := &triggers.EventDispatcher{}
+	decisionDispatcher.Initialize(logging.NoLog{})
+	consensusDispatcher := &triggers.EventDispatcher{}
+	consensusDispatcher.Initialize(logging.NoLog{})
+	routes := &testRouteAdder{routes: make(map[string]struct{})}
+
+	indexer, err := NewIndexer(Config{
+		DB:                  memdb.New(),
+		Log:                 logging.NoLog{},
+		DecisionDispatcher:  decisionDispatcher,
+		ConsensusDispatcher: consensusDispatcher,
+		APIServer:           routes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return indexer, decisionDispatcher, consensusDispatcher, routes
+}
+
+func TestIndexerSnowmanChain(t *testing.T) {
+	indexer, decisionDispatcher, consensusDispatcher, routes := newTestIndexer(t)
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	indexer.RegisterChain("P", ctx, &block.TestVM{})
+
+	if _, ok := routes.routes["index/P/block"]; !ok {
+		t.Fatalf("block index API wasn't registered")
+	}
+
+	// Snowman consensus notifies both dispatchers of each accepted block, but
+	// the block should only be indexed once.
+	blkID := ids.GenerateTestID()
+	decisionDispatcher.Accept(ctx, blkID, []byte{1})
+	consensusDispatcher.Accept(ctx, blkID, []byte{1})
+
+	idx := indexer.indexes[ctx.ChainID][blockPrefix]
+	container, index, err := idx.GetLastAccepted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if container.ID != blkID || index != 0 {
+		t.Fatalf("block should have been indexed at index 0")
+	}
+
+	if err := indexer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	consensusDispatcher.Accept(ctx, ids.GenerateTestID(), []byte{2})
+	if _, index, err := idx.GetLastAccepted(); err != nil {
+		t.Fatal(err)
+	} else if index != 0 {
+		t.Fatalf("closed indexer shouldn't index containers")
+	}
+}
+
+func TestIndexerAvalancheChain(t *testing.T) {
+	indexer, decisionDispatcher, consensusDispatcher, routes := newTestIndexer(t)
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	indexer.RegisterChain("X", ctx, &vertex.TestVM{})
+
+	for _, route := range []string{"index/X/vtx", "index/X/tx"} {
+		if _, ok := routes.routes[route]; !ok {
+			t.Fatalf("%s wasn't registered", route)
+		}
+	}
+
+	vtxID := ids.GenerateTestID()
+	txID := ids.GenerateTestID()
+	decisionDispatcher.Accept(ctx, txID, []byte{1})
+	consensusDispatcher.Accept(ctx, vtxID, []byte{2})
+
+	txIndex := indexer.indexes[ctx.ChainID][txPrefix]
+	if accepted, err := txIndex.IsAccepted(txID); err != nil {
+		t.Fatal(err)
+	} else if !accepted {
+		t.Fatalf("tx should have been indexed")
+	}
+	if accepted, err := txIndex.IsAccepted(vtxID); err != nil {
+		t.Fatal(err)
+	} else if accepted {
+		t.Fatalf("vertex shouldn't be in the tx index")
+	}
+
+	vtxIndex := indexer.indexes[ctx.ChainID][vtxPrefix]
+	if accepted, err := vtxIndex.IsAccepted(vtxID); err != nil {
+		t.Fatal(err)
+	} else if !accepted {
+		t.Fatalf("vertex should have been indexed")
+	}
+}
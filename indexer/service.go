@@ -0,0 +1,136 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+// service is the API service for a single index
+type service struct {
+	index *index
+}
+
+// FormattedContainer is a container returned by the API
+type FormattedContainer struct {
+	ID        ids.ID              `json:"id"`
+	Bytes     string              `json:"bytes"`
+	Timestamp time.Time           `json:"timestamp"`
+	Encoding  formatting.Encoding `json:"encoding"`
+	Index     json.Uint64         `json:"index"`
+}
+
+func newFormattedContainer(c Container, index uint64, enc formatting.Encoding) (FormattedContainer, error) {
+	fc := FormattedContainer{
+		ID:        c.ID,
+		Timestamp: time.Unix(0, c.Timestamp).UTC(),
+		Encoding:  enc,
+		Index:     json.Uint64(index),
+	}
+	bytesStr, err := formatting.Encode(enc, c.Bytes)
+	if err != nil {
+		return fc, fmt.Errorf("couldn't encode container %s as string: %w", c.ID, err)
+	}
+	fc.Bytes = bytesStr
+	return fc, nil
+}
+
+// GetLastAcceptedArgs are the arguments for calling GetLastAccepted
+type GetLastAcceptedArgs struct {
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetLastAccepted returns the most recently accepted container
+func (s *service) GetLastAccepted(_ *http.Request, args *GetLastAcceptedArgs, reply *FormattedContainer) error {
+	container, index, err := s.index.GetLastAccepted()
+	if err != nil {
+		return err
+	}
+	*reply, err = newFormattedContainer(container, index, args.Encoding)
+	return err
+}
+
+// GetContainerByIndexArgs are the arguments for calling GetContainerByIndex
+type GetContainerByIndexArgs struct {
+	Index    json.Uint64         `json:"index"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetContainerByIndex returns the container with the given index
+func (s *service) GetContainerByIndex(_ *http.Request, args *GetContainerByIndexArgs, reply *FormattedContainer) error {
+	index := uint64(args.Index)
+	container, err := s.index.GetContainerByIndex(index)
+	if err != nil {
+		return err
+	}
+	*reply, err = newFormattedContainer(container, index, args.Encoding)
+	return err
+}
+
+// GetContainerRangeArgs are the arguments for calling GetContainerRange
+type GetContainerRangeArgs struct {
+	StartIndex json.Uint64         `json:"startIndex"`
+	NumToFetch json.Uint64         `json:"numToFetch"`
+	Encoding   formatting.Encoding `json:"encoding"`
+}
+
+// GetContainerRangeResponse is the result from calling GetContainerRange
+type GetContainerRangeResponse struct {
+	Containers []FormattedContainer `json:"containers"`
+}
+
+// GetContainerRange returns up to [args.NumToFetch] containers, in the order
+// they were accepted, starting at index [args.StartIndex].
+// [args.NumToFetch] must be in [1, MaxFetchedByRange].
+func (s *service) GetContainerRange(_ *http.Request, args *GetContainerRangeArgs, reply *GetContainerRangeResponse) error {
+	containers, err := s.index.GetContainerRange(uint64(args.StartIndex), uint64(args.NumToFetch))
+	if err != nil {
+		return err
+	}
+
+	reply.Containers = make([]FormattedContainer, len(containers))
+	for i, container := range containers {
+		index := uint64(args.StartIndex) + uint64(i)
+		reply.Containers[i], err = newFormattedContainer(container, index, args.Encoding)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetIndexArgs are the arguments for calling GetIndex and IsAccepted
+type GetIndexArgs struct {
+	ContainerID ids.ID `json:"containerID"`
+}
+
+// GetIndexResponse is the result from calling GetIndex
+type GetIndexResponse struct {
+	Index json.Uint64 `json:"index"`
+}
+
+// GetIndex returns the index of the given container
+func (s *service) GetIndex(_ *http.Request, args *GetIndexArgs, reply *GetIndexResponse) error {
+	index, err := s.index.GetIndex(args.ContainerID)
+	reply.Index = json.Uint64(index)
+	return err
+}
+
+// IsAcceptedResponse is the result from calling IsAccepted
+type IsAcceptedResponse struct {
+	IsAccepted bool `json:"isAccepted"`
+}
+
+// IsAccepted returns true iff the given container has been accepted and indexed
+func (s *service) IsAccepted(_ *http.Request, args *GetIndexArgs, reply *IsAcceptedResponse) error {
+	isAccepted, err := s.index.IsAccepted(args.ContainerID)
+	reply.IsAccepted = isAccepted
+	return err
+}
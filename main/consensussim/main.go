@@ -0,0 +1,144 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// consensussim simulates stake-weighted networks, with byzantine nodes, for
+// each combination of the given K, Alpha and BetaRogue values, to find the
+// parameters under which consensus stays safe and finalizes quickly.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"text/tabwriter"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/simulator"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+const usage = `usage: consensussim [flags]
+
+Simulates a stake-weighted network of virtual nodes for each combination of
+--k, --alpha and --beta-rogue, and prints the safety violations, the rounds to
+finality and the messages of each combination.
+
+flags:
+`
+
+var errInvalidByzantine = errors.New("--byzantine must be in [0, 1)")
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("consensussim", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	protocolStr := fs.String("protocol", simulator.Snowball.String(), "Consensus of the honest nodes: snowball, snowman or snowstorm")
+	numNodes := fs.Int("nodes", 1000, "Number of nodes")
+	byzantine := fs.Float64("byzantine", 0.2, "Fraction of the nodes that are byzantine")
+	strategyStr := fs.String("strategy", simulator.SplitVotes{}.String(), "Strategy of the byzantine nodes: split, withhold, flip-flop or honest")
+	stakeSkew := fs.Float64("stake-skew", 0, "Weight of the i-th node is proportional to 1/i^stake-skew. 0 gives every node the same weight.")
+	numColors := fs.Int("colors", 2, "Number of conflicting choices")
+	ks := fs.IntSlice("k", []int{20}, "Sample sizes to simulate")
+	alphas := fs.IntSlice("alpha", []int{15}, "Quorum sizes to simulate")
+	betaVirtuous := fs.Int("beta-virtuous", 15, "Beta value to use for virtuous choices")
+	betaRogues := fs.IntSlice("beta-rogue", []int{20}, "Beta values to use for rogue choices to simulate")
+	maxRounds := fs.Int("rounds", 1000, "Number of rounds after which a simulation stops")
+	runs := fs.Int("runs", 10, "Number of simulations of each combination of parameters")
+	seed := fs.Int64("seed", 0, "Seed of the first simulation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	protocol, err := simulator.ParseProtocol(*protocolStr)
+	if err != nil {
+		return err
+	}
+	strategy, err := simulator.ParseStrategy(*strategyStr)
+	if err != nil {
+		return err
+	}
+	if *byzantine < 0 || *byzantine >= 1 {
+		return errInvalidByzantine
+	}
+	nodes, byzantineWeight := newNodes(*numNodes, *byzantine, strategy, *stakeSkew, *seed)
+
+	byzantineStr := "every node is honest"
+	if strategy != nil {
+		byzantineStr = fmt.Sprintf("%.1f%% of the weight is %s", 100*byzantineWeight, strategy)
+	}
+	fmt.Printf("%s consensus, %d nodes, %s, %d colors, %d runs of up to %d rounds\n\n",
+		protocol, *numNodes, byzantineStr, *numColors, *runs, *maxRounds)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "k\talpha\tbeta-rogue\tsafety violations\tunfinalized\tmean finality\tmax finality\tqueries/node\tresponses/node")
+	for _, k := range *ks {
+		for _, alpha := range *alphas {
+			for _, betaRogue := range *betaRogues {
+				params := snowball.Parameters{
+					K:                 k,
+					Alpha:             alpha,
+					BetaVirtuous:      *betaVirtuous,
+					BetaRogue:         betaRogue,
+					ConcurrentRepolls: 1,
+					OptimalProcessing: 1,
+				}
+				if err := params.Verify(); err != nil {
+					fmt.Fprintf(w, "%d\t%d\t%d\tinvalid: %s\n", k, alpha, betaRogue, err)
+					continue
+				}
+				summary, err := simulator.Simulate(simulator.Config{
+					Protocol:  protocol,
+					Params:    params,
+					Nodes:     nodes,
+					NumColors: *numColors,
+					MaxRounds: *maxRounds,
+					Seed:      *seed,
+				}, *runs)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "%d\t%d\t%d\t%d/%d\t%d/%d\t%.1f\t%d\t%.1f\t%.1f\n",
+					k, alpha, betaRogue,
+					summary.SafetyViolations, summary.Runs,
+					summary.Unfinalized, summary.Runs,
+					summary.MeanFinality, summary.MaxFinality,
+					summary.MeanQueries, summary.MeanResponses,
+				)
+			}
+		}
+	}
+	return w.Flush()
+}
+
+// newNodes returns [numNodes] nodes, of which a random [byzantine] fraction
+// follow [strategy], and the fraction of the weight that is byzantine
+func newNodes(numNodes int, byzantine float64, strategy simulator.Strategy, stakeSkew float64, seed int64) ([]simulator.Node, float64) {
+	nodes := make([]simulator.Node, numNodes)
+	totalWeight := uint64(0)
+	for i := range nodes {
+		nodes[i].Weight = uint64(math.Max(1, 1e6/math.Pow(float64(i+1), stakeSkew)))
+		totalWeight += nodes[i].Weight
+	}
+
+	if strategy == nil {
+		return nodes, 0
+	}
+	byzantineWeight := uint64(0)
+	r := rand.New(rand.NewSource(seed)) // #nosec G404
+	for _, i := range r.Perm(numNodes)[:int(byzantine*float64(numNodes))] {
+		nodes[i].Strategy = strategy
+		byzantineWeight += nodes[i].Weight
+	}
+	return nodes, float64(byzantineWeight) / float64(totalWeight)
+}
@@ -0,0 +1,187 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// deleteBatchSize is the number of keys deleted per batch by delete-chain
+const deleteBatchSize = 1024
+
+// chain is a chain whose data may be in the database
+type chain struct {
+	id      ids.ID
+	name    string
+	aliases []string
+}
+
+type chainSet []*chain
+
+// knownChains returns the P-chain, the other chains in the genesis of
+// [networkID] and the chains with IDs [extraChainIDs]
+func knownChains(networkID uint32, extraChainIDs []string) (chainSet, error) {
+	genesisBytes, _, err := genesis.Genesis(networkID, "")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get genesis of network %d: %w", networkID, err)
+	}
+	_, chainAliases, _, err := genesis.Aliases(genesisBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get aliases of the genesis chains: %w", err)
+	}
+
+	chains := chainSet{}
+	for chainID, aliases := range chainAliases {
+		chains = append(chains, &chain{
+			id:      chainID,
+			name:    aliases[0],
+			aliases: aliases,
+		})
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].name < chains[j].name })
+	for _, chainIDStr := range extraChainIDs {
+		chainID, err := ids.FromString(chainIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse chain ID %q: %w", chainIDStr, err)
+		}
+		chains = append(chains, &chain{
+			id:   chainID,
+			name: chainID.String(),
+		})
+	}
+	return chains, nil
+}
+
+// lookup returns the chain with alias or ID [chainStr]
+func (chains chainSet) lookup(chainStr string) (*chain, error) {
+	for _, chain := range chains {
+		if chain.id.String() == chainStr {
+			return chain, nil
+		}
+		for _, alias := range chain.aliases {
+			if alias == chainStr {
+				return chain, nil
+			}
+		}
+	}
+	chainID, err := ids.FromString(chainStr)
+	if err != nil {
+		return nil, fmt.Errorf("%q isn't a known chain alias or a chain ID", chainStr)
+	}
+	return &chain{
+		id:   chainID,
+		name: chainID.String(),
+	}, nil
+}
+
+// prefixes returns the prefixes of the databases the node creates for [c]
+func (c *chain) prefixes() [][]byte {
+	prefixes := make([][]byte, 0, len(chainDBPrefixes)+len(indexDBPrefixes))
+	for _, prefix := range chainDBPrefixes {
+		prefixes = append(prefixes, prefixdb.MakePrefix(c.id[:], []byte(prefix)))
+	}
+	for _, prefix := range indexDBPrefixes {
+		prefixes = append(prefixes, prefixdb.MakePrefix([]byte("index"), c.id[:], []byte(prefix)))
+	}
+	return prefixes
+}
+
+// stats returns the number and total size of the keys of [c] in [db]
+func (c *chain) stats(db database.Iteratee) (*prefixStats, error) {
+	stats := &prefixStats{label: fmt.Sprintf("%s (%s)", c.name, c.id)}
+	for _, prefix := range c.prefixes() {
+		it := db.NewIteratorWithPrefix(prefix)
+		for it.Next() {
+			stats.numKeys++
+			stats.size += len(it.Key()) + len(it.Value())
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func printChains(db database.Iteratee, chains chainSet) error {
+	stats := make([]*prefixStats, len(chains))
+	for i, chain := range chains {
+		chainStats, err := chain.stats(db)
+		if err != nil {
+			return err
+		}
+		stats[i] = chainStats
+	}
+	return printStats(stats)
+}
+
+// deleteChain deletes the data the node stored for [c]. Unless [confirmed],
+// the number of keys that would be deleted is only reported.
+func deleteChain(db database.Database, c *chain, confirmed bool) error {
+	if c.id == constants.PlatformChainID {
+		fmt.Println("warning: the P-chain tracks every other chain. They should be deleted too.")
+	}
+	if !confirmed {
+		stats, err := c.stats(db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("would delete %d keys (%d bytes) of %s. Run again with --yes to delete them.\n",
+			stats.numKeys, stats.size, stats.label)
+		return nil
+	}
+
+	numDeleted := 0
+	for _, prefix := range c.prefixes() {
+		n, err := deletePrefix(db, prefix)
+		numDeleted += n
+		if err != nil {
+			return fmt.Errorf("deleted %d keys of %s before failing: %w", numDeleted, c.name, err)
+		}
+	}
+	fmt.Printf("deleted %d keys of %s (%s)\n", numDeleted, c.name, c.id)
+	return nil
+}
+
+// deletePrefix deletes every key in [db] that starts with [prefix] and
+// returns the number of keys deleted
+func deletePrefix(db database.Database, prefix []byte) (int, error) {
+	numDeleted := 0
+	for {
+		// Collect the keys before deleting them so the iterator isn't open
+		// while the database is written to
+		it := db.NewIteratorWithPrefix(prefix)
+		keys := make([][]byte, 0, deleteBatchSize)
+		for len(keys) < deleteBatchSize && it.Next() {
+			keys = append(keys, it.Key())
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return numDeleted, err
+		}
+		if len(keys) == 0 {
+			return numDeleted, nil
+		}
+
+		batch := db.NewBatch()
+		for _, key := range keys {
+			if err := batch.Delete(key); err != nil {
+				return numDeleted, err
+			}
+		}
+		if err := batch.Write(); err != nil {
+			return numDeleted, err
+		}
+		numDeleted += len(keys)
+	}
+}
@@ -0,0 +1,209 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/state"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/core"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// avmCodecVersion is the codec version of avm txs
+const avmCodecVersion = 0
+
+// rawBlock is a block that is only known by its bytes
+type rawBlock struct {
+	snowman.Block
+	bytes []byte
+}
+
+func (b *rawBlock) Bytes() []byte { return b.bytes }
+
+// decodedTx is a tx that was decoded with its VM's codec
+type decodedTx struct {
+	ID         ids.ID      `json:"id"`
+	UnsignedTx interface{} `json:"unsignedTx"`
+}
+
+// printLastAccepted prints the last accepted block of [c] if it's a linear
+// chain, or its accepted frontier if it's a DAG
+func printLastAccepted(db database.Database, c *chain) error {
+	chainDB := prefixdb.New(c.id[:], db)
+	isDAG, err := hasKeys(db, prefixdb.MakePrefix(c.id[:], []byte("vertex")))
+	if err != nil {
+		return err
+	}
+	if isDAG {
+		return printEdge(prefixdb.New([]byte("vertex"), chainDB), c)
+	}
+	return printLastAcceptedBlock(prefixdb.New([]byte("vm"), chainDB), c)
+}
+
+// hasKeys returns true if there are any keys in [db] starting with [prefix]
+func hasKeys(db database.Iteratee, prefix []byte) (bool, error) {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+	return it.Next(), it.Error()
+}
+
+// printLastAcceptedBlock prints the last accepted block stored in [vmDB] by a
+// VM that stores its blocks with core.SnowmanState
+func printLastAcceptedBlock(vmDB database.Database, c *chain) error {
+	snowmanState, err := core.NewSnowmanState(func(b []byte) (snowman.Block, error) {
+		return &rawBlock{bytes: b}, nil
+	})
+	if err != nil {
+		return err
+	}
+	blkID, err := snowmanState.GetLastAccepted(vmDB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the last accepted block of %s: %w", c.name, err)
+	}
+	blk, err := snowmanState.GetBlock(vmDB, blkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+	blkBytes := blk.Bytes()
+
+	fmt.Printf("last accepted block: %s\n", blkID)
+	if c.id != constants.PlatformChainID {
+		fmt.Printf("bytes (%d): 0x%x\n", len(blkBytes), blkBytes)
+		return nil
+	}
+
+	var platformBlk platformvm.Block
+	if _, err := platformvm.Codec.Unmarshal(blkBytes, &platformBlk); err != nil {
+		return fmt.Errorf("couldn't parse block %s: %w", blkID, err)
+	}
+	var (
+		txs      []*platformvm.Tx
+		parentID ids.ID
+		height   uint64
+	)
+	switch blk := platformBlk.(type) {
+	case *platformvm.ProposalBlock:
+		txs, parentID, height = []*platformvm.Tx{&blk.Tx}, blk.PrntID, blk.Hght
+	case *platformvm.AtomicBlock:
+		txs, parentID, height = []*platformvm.Tx{&blk.Tx}, blk.PrntID, blk.Hght
+	case *platformvm.StandardBlock:
+		txs, parentID, height = blk.Txs, blk.PrntID, blk.Hght
+	case *platformvm.Commit:
+		parentID, height = blk.PrntID, blk.Hght
+	case *platformvm.Abort:
+		parentID, height = blk.PrntID, blk.Hght
+	}
+	fmt.Printf("type: %T\nparent: %s\nheight: %d\n", platformBlk, parentID, height)
+
+	decodedTxs := make([]decodedTx, len(txs))
+	for i, tx := range txs {
+		// Signing without any signers initializes the tx's ID
+		if err := tx.Sign(platformvm.Codec, nil); err != nil {
+			return fmt.Errorf("couldn't initialize tx: %w", err)
+		}
+		decodedTxs[i] = decodedTx{ID: tx.ID(), UnsignedTx: tx.UnsignedTx}
+	}
+	return printJSON(decodedTxs)
+}
+
+// printEdge prints the accepted frontier of the DAG stored in [vertexDB]. If
+// [c] is the X-chain, the txs in the frontier are decoded.
+func printEdge(vertexDB database.Database, c *chain) error {
+	ctx := &snow.Context{
+		ChainID: c.id,
+		Log:     logging.NoLog{},
+	}
+	serializer := &state.Serializer{}
+	serializer.Initialize(ctx, nil, vertexDB)
+
+	var avmCodec codec.Manager
+	if c.name == "X" {
+		var err error
+		if avmCodec, err = newAVMCodec(); err != nil {
+			return err
+		}
+	}
+
+	for _, vtxID := range serializer.Edge() {
+		vtx, err := serializer.Get(vtxID)
+		if err != nil {
+			return fmt.Errorf("couldn't get vertex %s: %w", vtxID, err)
+		}
+		innerVtx, err := vertex.Parse(vtx.Bytes())
+		if err != nil {
+			return fmt.Errorf("couldn't parse vertex %s: %w", vtxID, err)
+		}
+		fmt.Printf("vertex: %s\nheight: %d\nparents: %v\n", vtxID, innerVtx.Height(), innerVtx.ParentIDs())
+
+		txs := innerVtx.Txs()
+		decodedTxs := make([]decodedTx, len(txs))
+		for i, txBytes := range txs {
+			decodedTxs[i].ID = hashing.ComputeHash256Array(txBytes)
+			if avmCodec == nil {
+				decodedTxs[i].UnsignedTx = fmt.Sprintf("0x%x", txBytes)
+				continue
+			}
+			tx := avm.Tx{}
+			if _, err := avmCodec.Unmarshal(txBytes, &tx); err != nil {
+				return fmt.Errorf("couldn't parse tx %s: %w", decodedTxs[i].ID, err)
+			}
+			decodedTxs[i].UnsignedTx = tx.UnsignedTx
+		}
+		if err := printJSON(decodedTxs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newAVMCodec returns a codec that parses the txs of an AVM with the secp256k1,
+// nft and property Fxs, in that order, as the X-chain has
+func newAVMCodec() (codec.Manager, error) {
+	c := linearcodec.NewDefault()
+	vm := &secp256k1fx.TestVM{
+		Codec: c,
+		Log:   logging.NoLog{},
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&avm.BaseTx{}),
+		c.RegisterType(&avm.CreateAssetTx{}),
+		c.RegisterType(&avm.OperationTx{}),
+		c.RegisterType(&avm.ImportTx{}),
+		c.RegisterType(&avm.ExportTx{}),
+		(&secp256k1fx.Fx{}).Initialize(vm),
+		(&nftfx.Fx{}).Initialize(vm),
+		(&propertyfx.Fx{}).Initialize(vm),
+	)
+	manager := codec.NewDefaultManager()
+	errs.Add(manager.RegisterCodec(avmCodecVersion, c))
+	return manager, errs.Err
+}
+
+func printJSON(v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
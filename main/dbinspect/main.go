@@ -0,0 +1,131 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// dbinspect inspects, and repairs, the database of a node that isn't running.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/badgerdb"
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+const usage = `usage: dbinspect [flags] <command> [args]
+
+Inspects the database of a node. The node must not be running.
+
+commands:
+  prefixes               count and size the keys under each prefix
+  chains                 count and size the keys of each known chain
+  last-accepted <chain>  print the last accepted block, or the accepted
+                         frontier of vertices, of a chain
+  delete-chain <chain>   delete a chain's data so that it bootstraps again the
+                         next time the node starts. Without --yes, only
+                         reports what would be deleted. Shared memory isn't
+                         modified.
+
+<chain> is an alias, such as P or X, or a chain ID.
+
+flags:
+`
+
+var (
+	errNoDBPath       = errors.New("--db-path must be provided")
+	errMissingCommand = errors.New("missing command")
+	errMissingChain   = errors.New("missing chain argument")
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("dbinspect", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	dbPath := fs.String("db-path", "", "Database directory, such as ~/.avalanchego/db/mainnet/v1.0.0")
+	dbType := fs.String("db-type", leveldb.Name, fmt.Sprintf("Database type. Should be one of {%s, %s}", leveldb.Name, badgerdb.Name))
+	networkName := fs.String("network-id", constants.MainnetName, "Network of the database. Used to find the genesis chains")
+	chainIDs := fs.StringSlice("chain-id", nil, "IDs of chains, other than the genesis chains, to report on")
+	yes := fs.Bool("yes", false, "Actually delete the data in delete-chain")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case *dbPath == "":
+		return errNoDBPath
+	case fs.NArg() == 0:
+		fs.Usage()
+		return errMissingCommand
+	}
+
+	networkID, err := constants.NetworkID(*networkName)
+	if err != nil {
+		return err
+	}
+	chains, err := knownChains(networkID, *chainIDs)
+	if err != nil {
+		return err
+	}
+
+	command := fs.Arg(0)
+	writable := command == "delete-chain" && *yes
+	db, err := openDB(*dbType, *dbPath, writable)
+	if err != nil {
+		return fmt.Errorf("couldn't open db at %s: %w", *dbPath, err)
+	}
+	defer db.Close()
+
+	switch command {
+	case "prefixes":
+		return printPrefixes(db, chains)
+	case "chains":
+		return printChains(db, chains)
+	case "last-accepted", "delete-chain":
+		if fs.NArg() < 2 {
+			return errMissingChain
+		}
+		chain, err := chains.lookup(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		if command == "last-accepted" {
+			return printLastAccepted(db, chain)
+		}
+		return deleteChain(db, chain, *yes)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// openDB opens the database at [dbPath]. Unless [writable], any writes fail.
+func openDB(dbType, dbPath string, writable bool) (database.Database, error) {
+	switch dbType {
+	case leveldb.Name:
+		if writable {
+			return leveldb.New(dbPath, 0, 0, 0)
+		}
+		return leveldb.NewReadOnly(dbPath)
+	case badgerdb.Name:
+		if writable {
+			return badgerdb.New(dbPath)
+		}
+		return badgerdb.NewReadOnly(dbPath)
+	default:
+		return nil, fmt.Errorf("unknown db type %q", dbType)
+	}
+}
@@ -0,0 +1,118 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// The prefixes the node creates its databases with. See node.Node, the
+// migration of the database in main, the chains.Manager and the indexer.
+// These must be updated whenever one of them registers a new prefix.
+var (
+	nodeDBPrefixes  = []string{"shared memory", "keystore", "index", "peer bans", "db migration"}
+	chainDBPrefixes = []string{"vm", "bs", "vertex", "vertex_bs", "tx_bs", "proposervm"}
+	indexDBPrefixes = []string{"tx", "vtx", "block"}
+)
+
+// The label of keys that aren't under a prefix
+const unprefixedLabel = "<unprefixed>"
+
+// prefixStats are the number and total size of the keys under a prefix
+type prefixStats struct {
+	label   string
+	numKeys int
+	size    int
+}
+
+// prefixLabels returns the label of each prefix created by the node for
+// itself or one of [chains]
+func prefixLabels(chains chainSet) map[string]string {
+	labels := map[string]string{}
+	for _, prefix := range nodeDBPrefixes {
+		labels[string(prefixdb.MakePrefix([]byte(prefix)))] = prefix
+	}
+	for _, chain := range chains {
+		for _, prefix := range chainDBPrefixes {
+			key := prefixdb.MakePrefix(chain.id[:], []byte(prefix))
+			labels[string(key)] = fmt.Sprintf("%s/%s", chain.name, prefix)
+		}
+		for _, prefix := range indexDBPrefixes {
+			key := prefixdb.MakePrefix([]byte("index"), chain.id[:], []byte(prefix))
+			labels[string(key)] = fmt.Sprintf("index/%s/%s", chain.name, prefix)
+		}
+	}
+	return labels
+}
+
+// scanPrefixes returns the stats of every prefix in [db], sorted by size.
+// Prefixes that weren't created for the node itself or one of [chains] are
+// labeled by their hex encoding.
+func scanPrefixes(db database.Iteratee, chains chainSet) ([]*prefixStats, error) {
+	labels := prefixLabels(chains)
+	stats := map[string]*prefixStats{}
+
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+
+		label := unprefixedLabel
+		if len(key) >= hashing.HashLen {
+			prefix := string(key[:hashing.HashLen])
+			if knownLabel, ok := labels[prefix]; ok {
+				label = knownLabel
+			} else {
+				label = fmt.Sprintf("0x%x", prefix)
+			}
+		}
+
+		stat, ok := stats[label]
+		if !ok {
+			stat = &prefixStats{label: label}
+			stats[label] = stat
+		}
+		stat.numKeys++
+		stat.size += len(key) + len(it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	sortedStats := make([]*prefixStats, 0, len(stats))
+	for _, stat := range stats {
+		sortedStats = append(sortedStats, stat)
+	}
+	sort.Slice(sortedStats, func(i, j int) bool {
+		if sortedStats[i].size != sortedStats[j].size {
+			return sortedStats[i].size > sortedStats[j].size
+		}
+		return sortedStats[i].label < sortedStats[j].label
+	})
+	return sortedStats, nil
+}
+
+func printPrefixes(db database.Iteratee, chains chainSet) error {
+	stats, err := scanPrefixes(db, chains)
+	if err != nil {
+		return err
+	}
+	return printStats(stats)
+}
+
+func printStats(stats []*prefixStats) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "keys\tbytes\t\t")
+	for _, stat := range stats {
+		fmt.Fprintf(w, "%d\t%d\t\t%s\n", stat.numKeys, stat.size, stat.label)
+	}
+	return w.Flush()
+}
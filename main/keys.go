@@ -23,6 +23,8 @@ const (
 	signatureVerificationEnabledKey = "signature-verification-enabled"
 	dbEnabledKey                    = "db-enabled"
 	dbDirKey                        = "db-dir"
+	dbTypeKey                       = "db-type"
+	dbMigrateKey                    = "db-migrate"
 	publicIPKey                     = "public-ip"
 	dynamicUpdateDurationKey        = "dynamic-update-duration"
 	dynamicPublicIPResolverKey      = "dynamic-public-ip"
@@ -40,6 +42,7 @@ const (
 	stakingPortKey                  = "staking-port"
 	stakingEnabledKey               = "staking-enabled"
 	p2pTLSEnabledKey                = "p2p-tls-enabled"
+	p2pTransportKey                 = "p2p-transport"
 	stakingKeyPathKey               = "staking-tls-key-file"
 	stakingCertPathKey              = "staking-tls-cert-file"
 	stakingDisabledWeightKey        = "staking-disabled-weight"
@@ -53,6 +56,18 @@ const (
 	networkTimeoutIncreaseKey       = "network-timeout-increase"
 	networkTimeoutReductionKey      = "network-timeout-reduction"
 	sendQueueSizeKey                = "send-queue-size"
+	networkCompressionEnabledKey    = "network-compression-enabled"
+	inboundBytesPerSecKey           = "inbound-throttler-bytes-per-sec"
+	inboundStakerBytesPerSecKey     = "inbound-throttler-staker-bytes-per-sec"
+	inboundMsgsPerSecKey            = "inbound-throttler-msgs-per-sec"
+	inboundStakerMsgsPerSecKey      = "inbound-throttler-staker-msgs-per-sec"
+	inboundBurstKey                 = "inbound-throttler-burst"
+	peerAllowlistFileKey            = "peer-allowlist-file"
+	peerAllowlistEnforceKey         = "peer-allowlist-enforce"
+	peerAllowlistHideOthersKey      = "peer-allowlist-hide-others"
+	peerBanThresholdKey             = "peer-ban-threshold"
+	peerBanDurationKey              = "peer-ban-duration"
+	peerScoreHalfLifeKey            = "peer-score-half-life"
 	benchlistFailThresholdKey       = "benchlist-fail-threshold"
 	benchlistPeerSummaryEnabledKey  = "benchlist-peer-summary-enabled"
 	benchlistDurationKey            = "benchlist-duration"
@@ -70,6 +85,7 @@ const (
 	snowAvalancheBatchSizeKey       = "snow-avalanche-batch-size"
 	snowConcurrentRepollsKey        = "snow-concurrent-repolls"
 	snowOptimalProcessingKey        = "snow-optimal-processing"
+	snowPollTerminationKey          = "snow-poll-termination"
 	snowEpochFirstTransition        = "snow-epoch-first-transition"
 	snowEpochDuration               = "snow-epoch-duration"
 	whitelistedSubnetsKey           = "whitelisted-subnets"
@@ -79,12 +95,23 @@ const (
 	metricsAPIEnabledKey            = "api-metrics-enabled"
 	healthAPIEnabledKey             = "api-health-enabled"
 	ipcAPIEnabledKey                = "api-ipcs-enabled"
+	pubsubAPIEnabledKey             = "api-pubsub-enabled"
+	pubsubMaxSubscriptionsKey       = "pubsub-max-subscriptions"
+	pubsubBufferSizeKey             = "pubsub-buffer-size"
 	xputServerPortKey               = "xput-server-port"
 	xputServerEnabledKey            = "xput-server-enabled"
 	ipcsChainIDsKey                 = "ipcs-chain-ids"
 	ipcsPathKey                     = "ipcs-path"
+	indexEnabledKey                 = "index-enabled"
+	indexTransactionsKey            = "index-transactions"
+	stateSyncEnabledKey             = "state-sync-enabled"
+	stateHistoryDepthKey            = "state-history-depth"
+	feeWindowKey                    = "fee-window"
+	feeTargetTxsKey                 = "fee-target-txs"
+	feeMaxMultiplierKey             = "fee-max-multiplier"
 	consensusGossipFrequencyKey     = "consensus-gossip-frequency"
 	consensusShutdownTimeoutKey     = "consensus-shutdown-timeout"
+	consensusTraceDirKey            = "consensus-trace-dir"
 	fdLimitKey                      = "fd-limit"
 	corethConfigKey                 = "coreth-config"
 	disconnectedCheckFreqKey        = "disconnected-check-frequency"
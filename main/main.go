@@ -62,6 +62,11 @@ func main() {
 		log.Stop()
 	}()
 
+	if err := migrateDB(log, Config.DB, dbMigrationSource); err != nil {
+		log.Error("couldn't migrate the node's DB: %s", err)
+		return
+	}
+
 	// Track if sybil control is enforced
 	if !Config.EnableStaking && Config.EnableP2PTLS {
 		log.Warn("Staking is disabled. Sybil control is not enforced.")
@@ -99,8 +104,13 @@ func main() {
 	// Open staking port we want for NAT Traversal to have the external port
 	// (Config.StakingIP.Port) to connect to our internal listening port
 	// (Config.InternalStakingPort) which should be the same in most cases.
+	// QUIC runs over UDP.
+	stakingProtocol := "TCP"
+	if Config.P2PTransport == node.QUIC {
+		stakingProtocol = "UDP"
+	}
 	mapper.Map(
-		"TCP",
+		stakingProtocol,
 		Config.StakingIP.IP().Port,
 		Config.StakingIP.IP().Port,
 		stakingPortName,
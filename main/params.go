@@ -4,6 +4,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -20,13 +21,17 @@ import (
 
 	"github.com/kardianos/osext"
 
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/badgerdb"
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils"
@@ -74,8 +79,23 @@ var (
 	errBootstrapMismatch    = errors.New("more bootstrap IDs provided than bootstrap IPs")
 	errStakingRequiresTLS   = errors.New("if staking is enabled, network TLS must also be enabled")
 	errInvalidStakerWeights = errors.New("staking weights must be positive")
+	errMigrateNonEmptyDB    = errors.New("can't migrate into a database that already has data")
+	errPartialMigration     = fmt.Errorf("database was partially migrated. restart with --%s to migrate it again", dbMigrateKey)
+
+	// The keys that mark the migration of the database as started and as done
+	// are under this prefix
+	dbMigrationPrefix     = []byte("db migration")
+	dbMigrationStartedKey = []byte("started")
+	dbMigrationDoneKey    = []byte("done")
+
+	// The path of the LevelDB database to migrate into Config.DB, if any
+	dbMigrationSource string
 )
 
+// clearDBBatchSize is the number of deletes written at a time when clearing a
+// database
+const clearDBBatchSize = 16 * 1024
+
 // avalancheFlagSet returns the complete set of flags for avalanchego
 func avalancheFlagSet() *flag.FlagSet {
 	fs := flag.NewFlagSet(constants.AppName, flag.ContinueOnError)
@@ -95,6 +115,9 @@ func avalancheFlagSet() *flag.FlagSet {
 	// AVAX fees:
 	fs.Uint64(txFeeKey, units.MilliAvax, "Transaction fee, in nAVAX")
 	fs.Uint64(creationTxFeeKey, units.MilliAvax, "Transaction fee, in nAVAX, for transactions that create new state")
+	fs.Duration(feeWindowKey, 10*time.Second, "Length of the windows over which the usage of the X-Chain and P-Chain is measured to set their fees")
+	fs.Uint64(feeTargetTxsKey, 250, "Number of accepted txs per fee window above which fees rise and below which they fall, never below the tx fees. If 0, fees are fixed.")
+	fs.Uint64(feeMaxMultiplierKey, 100, "Max multiple of the tx fees that fees can rise to")
 
 	// Uptime requirement:
 	fs.Float64(uptimeRequirementKey, .6, "Fraction of time a validator must be online to receive rewards")
@@ -129,6 +152,8 @@ func avalancheFlagSet() *flag.FlagSet {
 	// Database:
 	fs.Bool(dbEnabledKey, true, "Turn on persistent storage")
 	fs.String(dbDirKey, defaultString, "Database directory for Avalanche state")
+	fs.String(dbTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s}", leveldb.Name, badgerdb.Name))
+	fs.Bool(dbMigrateKey, false, fmt.Sprintf("Copy the %s database in the database directory into an empty database of the type given by db-type before starting", leveldb.Name))
 
 	// IP:
 	fs.String(publicIPKey, "", "Public IP of this node for P2P communication. If empty, try to discover with NAT. Ignored if dynamic-public-ip is non-empty.")
@@ -167,6 +192,7 @@ func avalancheFlagSet() *flag.FlagSet {
 	fs.Uint(stakingPortKey, 9651, "Port of the consensus server")
 	fs.Bool(stakingEnabledKey, true, "Enable staking. If enabled, Network TLS is required.")
 	fs.Bool(p2pTLSEnabledKey, true, "Require TLS to authenticate network communication")
+	fs.String(p2pTransportKey, node.TCP, fmt.Sprintf("Transport to connect to peers over. Should be one of {%s, %s}. Nodes using %s can only connect to each other, and require TLS.", node.TCP, node.QUIC, node.QUIC))
 	fs.String(stakingKeyPathKey, defaultString, "TLS private key for staking")
 	fs.String(stakingCertPathKey, defaultString, "TLS certificate for staking")
 	fs.Uint64(stakingDisabledWeightKey, 1, "Weight to provide to each peer when staking is disabled")
@@ -184,6 +210,24 @@ func avalancheFlagSet() *flag.FlagSet {
 	fs.Duration(networkTimeoutIncreaseKey, 100*time.Millisecond, "Increase of network timeout after a failed request.")
 	fs.Duration(networkTimeoutReductionKey, 5*time.Millisecond, "Decrease of network timeout after a successful request.")
 	fs.Uint(sendQueueSizeKey, 4096, "Max number of messages waiting to be sent to peers.")
+	fs.Bool(networkCompressionEnabledKey, true, "If true, peers may send this node compressed messages, and large Put, MultiPut and PushQuery messages are compressed when sent to peers that also enable compression.")
+
+	// Inbound Throttling:
+	fs.Uint64(inboundBytesPerSecKey, 1<<21, "Bytes per second that every peer may send us. If 0, peers aren't limited by the bytes they send.")
+	fs.Uint64(inboundStakerBytesPerSecKey, 1<<28, "Bytes per second that are shared between the validators, in proportion to their stake, in addition to the per-peer rate.")
+	fs.Uint64(inboundMsgsPerSecKey, 1000, "Messages per second that every peer may send us. If 0, peers aren't limited by the messages they send.")
+	fs.Uint64(inboundStakerMsgsPerSecKey, 100000, "Messages per second that are shared between the validators, in proportion to their stake, in addition to the per-peer rate.")
+	fs.Duration(inboundBurstKey, 2*time.Second, "A peer that hasn't sent us anything for a while may send this long's worth of its rates at once.")
+
+	// Peer Allowlist:
+	fs.String(peerAllowlistFileKey, "", "File listing the node IDs on the peer allowlist, one per line. If empty, there's no allowlist. The file is re-read by admin.reloadPeerAllowlist.")
+	fs.Bool(peerAllowlistEnforceKey, true, "If true, only nodes on the peer allowlist are connected to.")
+	fs.Bool(peerAllowlistHideOthersKey, false, "If true, peers that aren't on the peer allowlist are left out of info.peers and peer lists, and aren't sent the IPs of other peers.")
+
+	// Peer Banning:
+	fs.Float64(peerBanThresholdKey, -100, "Peers start with a score of 0 that falls each time they misbehave. Peers whose score falls below this are banned.")
+	fs.Duration(peerBanDurationKey, time.Hour, "Amount of time a misbehaving peer is banned for. If 0, peers aren't banned.")
+	fs.Duration(peerScoreHalfLifeKey, 10*time.Minute, "Amount of time it takes for the score of a peer to recover halfway back to 0.")
 
 	// Benchlist Parameters:
 	fs.Int(benchlistFailThresholdKey, 10, "Number of consecutive failed queries before benchlisting a node.")
@@ -208,6 +252,7 @@ func avalancheFlagSet() *flag.FlagSet {
 	fs.Int(snowAvalancheBatchSizeKey, 30, "Number of operations to batch in each new vertex")
 	fs.Int(snowConcurrentRepollsKey, 4, "Minimum number of concurrent polls for finalizing consensus")
 	fs.Int(snowOptimalProcessingKey, 50, "Optimal number of processing vertices in consensus")
+	fs.String(snowPollTerminationKey, snowball.EarlyTermNoTraversal.String(), fmt.Sprintf("When a poll finishes before every polled validator responded. One of %q, %q or %q", snowball.EarlyTermNoTraversal, snowball.NoEarlyTerm, snowball.EarlyTermTraversal))
 	fs.Int64(snowEpochFirstTransition, 1607626800, "Unix timestamp of the first epoch transaction, in seconds. Defaults to 12/10/2020 @ 7:00pm (UTC)")
 	fs.Duration(snowEpochDuration, 6*time.Hour, "Duration of each epoch")
 
@@ -218,6 +263,7 @@ func avalancheFlagSet() *flag.FlagSet {
 	fs.Bool(metricsAPIEnabledKey, true, "If true, this node exposes the Metrics API")
 	fs.Bool(healthAPIEnabledKey, true, "If true, this node exposes the Health API")
 	fs.Bool(ipcAPIEnabledKey, false, "If true, IPCs can be opened")
+	fs.Bool(pubsubAPIEnabledKey, false, "If true, this node streams accepted X-Chain and P-Chain txs to websocket clients at /ext/pubsub")
 
 	// Throughput Server
 	fs.Uint(xputServerPortKey, 9652, "Port of the deprecated throughput test server")
@@ -227,9 +273,24 @@ func avalancheFlagSet() *flag.FlagSet {
 	fs.String(ipcsChainIDsKey, "", "Comma separated list of chain ids to add to the IPC engine. Example: 11111111111111111111111111111111LpoYY,4R5p2RXDGLqaifZE4hHWH9owe34pfoBULn1DrQTWivjg8o4aH")
 	fs.String(ipcsPathKey, defaultString, "The directory (Unix) or named pipe name prefix (Windows) for IPC sockets")
 
+	// PubSub
+	fs.Int(pubsubMaxSubscriptionsKey, 16, "Max number of subscriptions a pubsub connection can hold at once")
+	fs.Int(pubsubBufferSizeKey, 1024, "Max number of messages queued for a pubsub connection. Connections that fall further behind are closed.")
+
+	// Indexer
+	fs.Bool(indexEnabledKey, false, "If true, this node indexes accepted containers and exposes the Index API. Containers accepted while indexing is disabled are never indexed.")
+	fs.Bool(indexTransactionsKey, false, "If true, the X-Chain indexes the txs that touched each address, which can be queried with avm.getAddressTxs. Txs accepted while indexing is disabled are never indexed.")
+
+	// State Sync
+	fs.Bool(stateSyncEnabledKey, false, "If true, a chain whose VM supports state sync and that has no accepted blocks syncs its state from a summary sent by a majority of the beacons, rather than executing every block since genesis.")
+
+	// State History
+	fs.Uint64(stateHistoryDepthKey, 0, "Number of recently accepted P-Chain blocks whose state can be queried by height through the Platform API. If 0, only the last accepted state can be queried.")
+
 	// Router Configuration:
 	fs.Duration(consensusGossipFrequencyKey, 10*time.Second, "Frequency of gossiping accepted frontiers.")
 	fs.Duration(consensusShutdownTimeoutKey, 5*time.Second, "Timeout before killing an unresponsive chain.")
+	fs.String(consensusTraceDirKey, "", "If set, the chits, polls and decisions of each chain's consensus are written to a trace in this directory, which can be replayed with tracereplay.")
 
 	// Restart on disconnect configuration:
 	fs.Duration(disconnectedCheckFreqKey, 10*time.Second, "How often the node checks if it is connected to any peers. "+
@@ -284,9 +345,17 @@ func setNodeConfig(v *viper.Viper) error {
 	Config.ConsensusParams.BatchSize = v.GetInt(snowAvalancheBatchSizeKey)
 	Config.ConsensusParams.ConcurrentRepolls = v.GetInt(snowConcurrentRepollsKey)
 	Config.ConsensusParams.OptimalProcessing = v.GetInt(snowOptimalProcessingKey)
+	pollTermination, err := snowball.ParsePollTermination(v.GetString(snowPollTerminationKey))
+	if err != nil {
+		return fmt.Errorf("couldn't parse %s: %w", snowPollTerminationKey, err)
+	}
+	Config.ConsensusParams.PollTermination = pollTermination
 
 	Config.ConsensusGossipFrequency = v.GetDuration(consensusGossipFrequencyKey)
 	Config.ConsensusShutdownTimeout = v.GetDuration(consensusShutdownTimeoutKey)
+	if consensusTraceDir := v.GetString(consensusTraceDirKey); consensusTraceDir != "" {
+		Config.ConsensusTraceDir = os.ExpandEnv(consensusTraceDir)
+	}
 
 	// Logging:
 	loggingConfig, err := logging.DefaultConfig()
@@ -333,12 +402,34 @@ func setNodeConfig(v *viper.Viper) error {
 			dbDir = defaultDbDir
 		}
 		dbDir = os.ExpandEnv(dbDir) // parse any env variables
-		dbPath := path.Join(dbDir, constants.NetworkName(Config.NetworkID), dbVersion)
-		db, err := leveldb.New(dbPath, 0, 0, 0)
+		networkDBDir := path.Join(dbDir, constants.NetworkName(Config.NetworkID))
+		levelDBPath := path.Join(networkDBDir, dbVersion)
+
+		var (
+			db     database.Database
+			dbPath string
+		)
+		switch dbType := v.GetString(dbTypeKey); dbType {
+		case leveldb.Name:
+			dbPath = levelDBPath
+			db, err = leveldb.New(dbPath, 0, 0, 0)
+		case badgerdb.Name:
+			dbPath = path.Join(networkDBDir, badgerdb.Name, dbVersion)
+			db, err = badgerdb.New(dbPath)
+		default:
+			return fmt.Errorf("unknown %s %q", dbTypeKey, dbType)
+		}
 		if err != nil {
 			return fmt.Errorf("couldn't create db at %s: %w", dbPath, err)
 		}
 		Config.DB = db
+
+		if v.GetBool(dbMigrateKey) {
+			if dbPath == levelDBPath {
+				return fmt.Errorf("%s requires %s to not be %s", dbMigrateKey, dbTypeKey, leveldb.Name)
+			}
+			dbMigrationSource = levelDBPath
+		}
 	} else {
 		Config.DB = memdb.New()
 	}
@@ -389,6 +480,7 @@ func setNodeConfig(v *viper.Viper) error {
 	// Staking:
 	Config.EnableStaking = v.GetBool(stakingEnabledKey)
 	Config.EnableP2PTLS = v.GetBool(p2pTLSEnabledKey)
+	Config.P2PTransport = v.GetString(p2pTransportKey)
 	Config.StakingKeyFile = v.GetString(stakingKeyPathKey)
 	Config.StakingCertFile = v.GetString(stakingCertPathKey)
 	Config.DisabledStakingWeight = v.GetUint64(stakingDisabledWeightKey)
@@ -459,6 +551,16 @@ func setNodeConfig(v *viper.Viper) error {
 		return errStakingRequiresTLS
 	}
 
+	switch Config.P2PTransport {
+	case node.TCP:
+	case node.QUIC:
+		if !Config.EnableP2PTLS {
+			return fmt.Errorf("%s %q requires %s", p2pTransportKey, node.QUIC, p2pTLSEnabledKey)
+		}
+	default:
+		return fmt.Errorf("unknown %s %q", p2pTransportKey, Config.P2PTransport)
+	}
+
 	if !Config.EnableStaking && Config.DisabledStakingWeight == 0 {
 		return errInvalidStakerWeights
 	}
@@ -541,6 +643,7 @@ func setNodeConfig(v *viper.Viper) error {
 	Config.MetricsAPIEnabled = v.GetBool(metricsAPIEnabledKey)
 	Config.HealthAPIEnabled = v.GetBool(healthAPIEnabledKey)
 	Config.IPCAPIEnabled = v.GetBool(ipcAPIEnabledKey)
+	Config.PubSubAPIEnabled = v.GetBool(pubsubAPIEnabledKey)
 
 	// Throughput:
 	Config.ThroughputServerEnabled = v.GetBool(xputServerEnabledKey)
@@ -562,6 +665,37 @@ func setNodeConfig(v *viper.Viper) error {
 		Config.IPCPath = ipcsPath
 	}
 
+	// PubSub
+	Config.PubSubMaxSubscriptions = v.GetInt(pubsubMaxSubscriptionsKey)
+	if Config.PubSubMaxSubscriptions <= 0 {
+		return fmt.Errorf("%s must be > 0", pubsubMaxSubscriptionsKey)
+	}
+	Config.PubSubBufferSize = v.GetInt(pubsubBufferSizeKey)
+	if Config.PubSubBufferSize <= 0 {
+		return fmt.Errorf("%s must be > 0", pubsubBufferSizeKey)
+	}
+
+	// Indexer
+	Config.IndexAPIEnabled = v.GetBool(indexEnabledKey)
+	Config.IndexTransactions = v.GetBool(indexTransactionsKey)
+
+	// State Sync
+	Config.StateSyncEnabled = v.GetBool(stateSyncEnabledKey)
+
+	// State History
+	Config.StateHistoryDepth = v.GetUint64(stateHistoryDepthKey)
+
+	// Dynamic Fees
+	Config.FeeConfig.Window = v.GetDuration(feeWindowKey)
+	if Config.FeeConfig.Window <= 0 {
+		return fmt.Errorf("%s must be > 0", feeWindowKey)
+	}
+	Config.FeeConfig.TargetUnits = v.GetUint64(feeTargetTxsKey)
+	Config.FeeConfig.MaxMultiplier = v.GetUint64(feeMaxMultiplierKey)
+	if Config.FeeConfig.MaxMultiplier < 1 {
+		return fmt.Errorf("%s must be >= 1", feeMaxMultiplierKey)
+	}
+
 	// Throttling
 	Config.MaxNonStakerPendingMsgs = v.GetUint32(maxNonStakerPendingMsgsKey)
 	Config.StakerMSGPortion = v.GetFloat64(stakerMsgReservedKey)
@@ -572,6 +706,35 @@ func setNodeConfig(v *viper.Viper) error {
 		return errors.New("maximum pending messages must be >= maximum non-staker pending messages")
 	}
 
+	// Network Compression
+	Config.NetworkCompressionEnabled = v.GetBool(networkCompressionEnabledKey)
+
+	// Inbound Throttling
+	Config.InboundThrottlerConfig.BytesPerSec = v.GetUint64(inboundBytesPerSecKey)
+	Config.InboundThrottlerConfig.StakerBytesPerSec = v.GetUint64(inboundStakerBytesPerSecKey)
+	Config.InboundThrottlerConfig.MsgsPerSec = v.GetUint64(inboundMsgsPerSecKey)
+	Config.InboundThrottlerConfig.StakerMsgsPerSec = v.GetUint64(inboundStakerMsgsPerSecKey)
+	Config.InboundThrottlerConfig.Burst = v.GetDuration(inboundBurstKey)
+	if Config.InboundThrottlerConfig.Burst <= 0 {
+		return fmt.Errorf("%s must be > 0", inboundBurstKey)
+	}
+
+	// Peer Allowlist
+	Config.AllowlistConfig.File = v.GetString(peerAllowlistFileKey)
+	Config.AllowlistConfig.Enforce = v.GetBool(peerAllowlistEnforceKey)
+	Config.AllowlistConfig.HideOthers = v.GetBool(peerAllowlistHideOthersKey)
+
+	// Peer Banning
+	Config.ReputationConfig.BanThreshold = v.GetFloat64(peerBanThresholdKey)
+	Config.ReputationConfig.BanDuration = v.GetDuration(peerBanDurationKey)
+	Config.ReputationConfig.HalfLife = v.GetDuration(peerScoreHalfLifeKey)
+	if Config.ReputationConfig.BanThreshold >= 0 {
+		return errors.New("peer ban threshold must be negative")
+	}
+	if Config.ReputationConfig.BanDuration < 0 {
+		return errors.New("peer ban duration can't be negative")
+	}
+
 	// Network Timeout
 	Config.NetworkConfig.InitialTimeout = v.GetDuration(networkInitialTimeoutKey)
 	Config.NetworkConfig.MinimumTimeout = v.GetDuration(networkMinimumTimeoutKey)
@@ -698,6 +861,102 @@ func setNodeConfig(v *viper.Viper) error {
 	return nil
 }
 
+// migrateDB copies the LevelDB database at [levelDBPath] into [db], if
+// [levelDBPath] isn't empty. The migration is marked as started before any key
+// is copied and as done once every key has been copied. If a previous
+// migration into [db] was interrupted, [db] is cleared and migrated again, or,
+// if [levelDBPath] is empty, an error is returned so that a partially migrated
+// database is never used.
+func migrateDB(log logging.Logger, db database.Database, levelDBPath string) error {
+	migrationDB := prefixdb.New(dbMigrationPrefix, db)
+	started, err := migrationDB.Has(dbMigrationStartedKey)
+	if err != nil {
+		return err
+	}
+	done, err := migrationDB.Has(dbMigrationDoneKey)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case done:
+		if levelDBPath != "" {
+			log.Info("%s database at %s was already migrated", leveldb.Name, levelDBPath)
+		}
+		return nil
+	case started && levelDBPath == "":
+		return errPartialMigration
+	case levelDBPath == "":
+		return nil
+	case started:
+		log.Warn("the previous migration of the %s database at %s was interrupted. migrating it again...",
+			leveldb.Name, levelDBPath)
+		if err := clearDB(db, prefixdb.MakePrefix(dbMigrationPrefix)); err != nil {
+			return fmt.Errorf("couldn't clear partially migrated database: %w", err)
+		}
+	default:
+		it := db.NewIterator()
+		hasData := it.Next()
+		it.Release()
+		if hasData {
+			return errMigrateNonEmptyDB
+		}
+	}
+
+	if _, err := os.Stat(levelDBPath); err != nil {
+		return fmt.Errorf("couldn't find %s database: %w", leveldb.Name, err)
+	}
+	levelDB, err := leveldb.New(levelDBPath, 0, 0, 0)
+	if err != nil {
+		return err
+	}
+	defer levelDB.Close()
+
+	if err := migrationDB.Put(dbMigrationStartedKey, nil); err != nil {
+		return err
+	}
+	log.Info("migrating %s database at %s...", leveldb.Name, levelDBPath)
+	numCopied, err := database.Copy(db, levelDB)
+	if err != nil {
+		return err
+	}
+	if err := migrationDB.Put(dbMigrationDoneKey, nil); err != nil {
+		return err
+	}
+	log.Info("migrated %d keys", numCopied)
+	return nil
+}
+
+// clearDB deletes every key in [db] that doesn't start with [keepPrefix]
+func clearDB(db database.Database, keepPrefix []byte) error {
+	it := db.NewIterator()
+	defer it.Release()
+
+	batch := db.NewBatch()
+	numDeletes := 0
+	for it.Next() {
+		key := it.Key()
+		if bytes.HasPrefix(key, keepPrefix) {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		numDeletes++
+		if numDeletes%clearDBBatchSize != 0 {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
 func parseViper() error {
 	v, err := getViper()
 	if err != nil {
@@ -0,0 +1,162 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// tracereplay replays a consensus trace, written by a node run with
+// --consensus-trace-dir, and shows the preference path to each decision.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer/replay"
+)
+
+const usage = `usage: tracereplay [flags] <trace>
+
+Replays the consensus trace of a chain into a new Snowman, or Avalanche,
+consensus instance. Prints the preference of consensus after each poll from the
+issuance of each block, or vertex, until it was decided, and the decisions of
+the replay that differ from the decisions in the trace.
+
+flags:
+`
+
+var (
+	errMissingTrace = errors.New("missing trace argument")
+	errMismatches   = errors.New("the replay doesn't match the trace")
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("tracereplay", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	events := fs.Bool("events", false, "Print the events of the trace before replaying it")
+	decisionStr := fs.String("decision", "", "Only print the preference path to the decision of this block, or vertex")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errMissingTrace
+	}
+	path := fs.Arg(0)
+
+	decisionID := ids.Empty
+	if *decisionStr != "" {
+		var err error
+		decisionID, err = ids.FromString(*decisionStr)
+		if err != nil {
+			return fmt.Errorf("couldn't parse --decision: %w", err)
+		}
+	}
+
+	if *events {
+		if err := printEvents(path); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, err := tracer.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("couldn't read %s: %w", path, err)
+	}
+	result, err := replay.Run(r)
+	if err != nil {
+		return fmt.Errorf("couldn't replay %s: %w", path, err)
+	}
+	printReplay(result, decisionID)
+
+	if len(result.Mismatches) > 0 {
+		return errMismatches
+	}
+	return nil
+}
+
+// printEvents prints every event of the trace at [path]
+func printEvents(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, err := tracer.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("couldn't read %s: %w", path, err)
+	}
+	for {
+		record, err := r.Read()
+		switch {
+		case err == io.EOF:
+			fmt.Println()
+			return nil
+		case err == io.ErrUnexpectedEOF:
+			fmt.Printf("trace ends in the middle of an event\n\n")
+			return nil
+		case err != nil:
+			return err
+		}
+		fmt.Printf("%s %-11s %+v\n", record.Time.UTC().Format(time.RFC3339Nano), record.Event.Type(), record.Event)
+	}
+}
+
+// printReplay prints the preference paths of the decisions of [result]. If
+// [decisionID] isn't empty, only its path is printed.
+func printReplay(result *replay.Replay, decisionID ids.ID) {
+	consensus := "Snowman"
+	if result.DAG {
+		consensus = "Avalanche"
+	}
+	fmt.Printf("replayed %s consensus: %d chits, %d failed queries, %d polls, %d decisions\n",
+		consensus, result.Chits, result.QueryFailures, len(result.Preferences), len(result.Decisions))
+	if result.Truncated {
+		fmt.Println("the trace ends in the middle of an event")
+	}
+
+	for _, d := range result.Decisions {
+		if decisionID != ids.Empty && d.ID != decisionID {
+			continue
+		}
+		decision := "rejected"
+		if d.Accepted {
+			decision = "accepted"
+		}
+		fmt.Printf("\n%s %s after poll %d, issued after poll %d\n", d.ID, decision, d.DecidedAt, d.IssuedAt)
+		for i, prefs := range result.Path(d) {
+			prefStrs := make([]string, len(prefs))
+			for j, pref := range prefs {
+				prefStrs[j] = pref.String()
+			}
+			fmt.Printf("  poll %d: %s\n", d.IssuedAt+i+1, strings.Join(prefStrs, ", "))
+		}
+	}
+
+	if len(result.Mismatches) > 0 {
+		fmt.Printf("\n%d mismatches:\n", len(result.Mismatches))
+		for _, mismatch := range result.Mismatches {
+			fmt.Printf("  %s\n", mismatch)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+var (
+	errNotAllowed  = errors.New("node isn't on the allowlist")
+	errNoAllowlist = errors.New("no allowlist file was configured")
+)
+
+// AllowlistConfig describes the set of nodes that a private network consists
+// of
+type AllowlistConfig struct {
+	// File the allowlist is reloaded from
+	File string
+
+	// Nodes on the allowlist. If nil, there's no allowlist.
+	NodeIDs ids.ShortSet
+
+	// If true, only nodes on the allowlist are connected to
+	Enforce bool
+
+	// If true, peers that aren't on the allowlist are left out of Peers() and
+	// peer lists, and aren't sent the IPs of other peers
+	HideOthers bool
+}
+
+// ReadAllowlist returns the node IDs listed in the file at [path]. The file
+// has a node ID on each line. Empty lines and lines starting with # are
+// ignored.
+func ReadAllowlist(path string) (ids.ShortSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open allowlist: %w", err)
+	}
+	defer file.Close()
+
+	nodeIDs := ids.ShortSet{}
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(line, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse node ID on line %d of allowlist: %w", lineNum, err)
+		}
+		nodeIDs.Add(nodeID)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read allowlist: %w", err)
+	}
+	return nodeIDs, nil
+}
+
+// ReloadAllowlist implements the Network interface
+// assumes the stateLock is not held.
+func (n *network) ReloadAllowlist() ([]ids.ShortID, error) {
+	if n.allowlistConfig.File == "" {
+		return nil, errNoAllowlist
+	}
+	nodeIDs, err := ReadAllowlist(n.allowlistConfig.File)
+	if err != nil {
+		return nil, err
+	}
+
+	n.stateLock.Lock()
+	n.allowlistConfig.NodeIDs = nodeIDs
+
+	// Connect to the nodes that were rejected before they were allowed
+	for nodeID, ip := range n.rejectedIPs {
+		if !nodeIDs.Contains(nodeID) {
+			continue
+		}
+		delete(n.rejectedIPs, nodeID)
+		delete(n.disconnectedIPs, ip.String())
+		n.track(ip, nodeID)
+	}
+
+	// Disconnect from the peers that are no longer allowed
+	disallowed := []*peer(nil)
+	if n.allowlistConfig.Enforce {
+		for nodeID, peer := range n.peers {
+			if !nodeIDs.Contains(nodeID) {
+				disallowed = append(disallowed, peer)
+			}
+		}
+	}
+	n.stateLock.Unlock()
+
+	for _, peer := range disallowed {
+		n.log.Info("disconnecting from %s as it was removed from the allowlist", peer.id.PrefixedString(constants.NodeIDPrefix))
+		peer.Close()
+	}
+	n.log.Info("reloaded allowlist of %d nodes", nodeIDs.Len())
+	return nodeIDs.List(), nil
+}
+
+// allowed returns true if [nodeID] is on the allowlist, or there's no
+// allowlist.
+// assumes the stateLock is held.
+func (n *network) allowed(nodeID ids.ShortID) bool {
+	return n.allowlistConfig.NodeIDs == nil || n.allowlistConfig.NodeIDs.Contains(nodeID)
+}
+
+// canConnect returns true if we may connect to [nodeID].
+// assumes the stateLock is held.
+func (n *network) canConnect(nodeID ids.ShortID) bool {
+	return !n.allowlistConfig.Enforce || n.allowed(nodeID)
+}
+
+// visible returns true if [nodeID] may be included in Peers() and peer lists,
+// and sent the IPs of other peers.
+// assumes the stateLock is held.
+func (n *network) visible(nodeID ids.ShortID) bool {
+	return !n.allowlistConfig.HideOthers || n.allowed(nodeID)
+}
+
+// isVisible is visible for callers that don't hold the stateLock.
+// assumes the stateLock is not held.
+func (n *network) isVisible(nodeID ids.ShortID) bool {
+	n.stateLock.RLock()
+	defer n.stateLock.RUnlock()
+
+	return n.visible(nodeID)
+}
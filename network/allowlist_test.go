@@ -0,0 +1,79 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestReadAllowlist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "allowlist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	path := filepath.Join(dir, "allowlist")
+	contents := fmt.Sprintf(
+		"# validators\n%s\n\n  %s  \n",
+		nodeID0.PrefixedString(constants.NodeIDPrefix),
+		nodeID1.PrefixedString(constants.NodeIDPrefix),
+	)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+
+	nodeIDs, err := ReadAllowlist(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, nodeIDs.Len())
+	assert.True(t, nodeIDs.Contains(nodeID0))
+	assert.True(t, nodeIDs.Contains(nodeID1))
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("not a node ID\n"), 0600))
+	_, err = ReadAllowlist(path)
+	assert.Error(t, err)
+
+	_, err = ReadAllowlist(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestAllowlistPolicy(t *testing.T) {
+	listed := ids.GenerateTestShortID()
+	unlisted := ids.GenerateTestShortID()
+
+	// Without an allowlist, every node is allowed
+	n := &network{allowlistConfig: AllowlistConfig{Enforce: true, HideOthers: true}}
+	assert.True(t, n.canConnect(unlisted))
+	assert.True(t, n.visible(unlisted))
+
+	n.allowlistConfig.NodeIDs = ids.ShortSet{}
+	n.allowlistConfig.NodeIDs.Add(listed)
+	assert.True(t, n.canConnect(listed))
+	assert.True(t, n.visible(listed))
+	assert.False(t, n.canConnect(unlisted))
+	assert.False(t, n.visible(unlisted))
+
+	// Unlisted nodes may connect, but are hidden from others
+	n.allowlistConfig.Enforce = false
+	assert.True(t, n.canConnect(unlisted))
+	assert.False(t, n.visible(unlisted))
+
+	// Unlisted nodes may connect and are visible to others
+	n.allowlistConfig.HideOthers = false
+	assert.True(t, n.canConnect(unlisted))
+	assert.True(t, n.visible(unlisted))
+}
+
+func TestReloadAllowlistWithoutFile(t *testing.T) {
+	n := &network{}
+	_, err := n.ReloadAllowlist()
+	assert.Equal(t, errNoAllowlist, err)
+}
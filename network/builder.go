@@ -25,6 +25,25 @@ func (m Builder) Version(networkID, nodeID uint32, myTime uint64, ip utils.IPDes
 	})
 }
 
+// VersionWithSubnets message. [compression] is true if the sender accepts
+// compressed messages.
+func (m Builder) VersionWithSubnets(networkID, nodeID uint32, myTime uint64, ip utils.IPDesc, myVersion string, subnetIDs []ids.ID, compression bool) (Msg, error) {
+	subnetIDBytes := make([][]byte, len(subnetIDs))
+	for i, subnetID := range subnetIDs {
+		copy := subnetID
+		subnetIDBytes[i] = copy[:]
+	}
+	return m.Pack(VersionWithSubnets, map[Field]interface{}{
+		NetworkID:   networkID,
+		NodeID:      nodeID,
+		MyTime:      myTime,
+		IP:          ip,
+		VersionStr:  myVersion,
+		SubnetIDs:   subnetIDBytes,
+		Compression: compression,
+	})
+}
+
 // GetPeerList message
 func (m Builder) GetPeerList() (Msg, error) { return m.Pack(GetPeerList, nil) }
 
@@ -33,6 +52,11 @@ func (m Builder) PeerList(ipDescs []utils.IPDesc) (Msg, error) {
 	return m.Pack(PeerList, map[Field]interface{}{Peers: ipDescs})
 }
 
+// SignedPeerList message
+func (m Builder) SignedPeerList(signedIPs []*SignedIP) (Msg, error) {
+	return m.Pack(SignedPeerList, map[Field]interface{}{SignedPeers: signedIPs})
+}
+
 // Ping message
 func (m Builder) Ping() (Msg, error) { return m.Pack(Ping, nil) }
 
@@ -164,3 +188,68 @@ func (m Builder) Chits(chainID ids.ID, requestID uint32, containerIDs []ids.ID)
 		ContainerIDs: containerIDBytes,
 	})
 }
+
+// GetStateSummary message
+func (m Builder) GetStateSummary(chainID ids.ID, requestID uint32, deadline uint64) (Msg, error) {
+	return m.Pack(GetStateSummary, map[Field]interface{}{
+		ChainID:   chainID[:],
+		RequestID: requestID,
+		Deadline:  deadline,
+	})
+}
+
+// StateSummary message
+func (m Builder) StateSummary(chainID ids.ID, requestID uint32, summary []byte) (Msg, error) {
+	return m.Pack(StateSummary, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      requestID,
+		ContainerBytes: summary,
+	})
+}
+
+// GetStateChunk message
+func (m Builder) GetStateChunk(chainID ids.ID, requestID uint32, deadline uint64, summaryID ids.ID, index uint32) (Msg, error) {
+	return m.Pack(GetStateChunk, map[Field]interface{}{
+		ChainID:     chainID[:],
+		RequestID:   requestID,
+		Deadline:    deadline,
+		ContainerID: summaryID[:],
+		ChunkIndex:  index,
+	})
+}
+
+// StateChunk message
+func (m Builder) StateChunk(chainID ids.ID, requestID uint32, chunk []byte) (Msg, error) {
+	return m.Pack(StateChunk, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      requestID,
+		ContainerBytes: chunk,
+	})
+}
+
+// AppRequest message
+func (m Builder) AppRequest(chainID ids.ID, requestID uint32, deadline uint64, msg []byte) (Msg, error) {
+	return m.Pack(AppRequest, map[Field]interface{}{
+		ChainID:   chainID[:],
+		RequestID: requestID,
+		Deadline:  deadline,
+		AppBytes:  msg,
+	})
+}
+
+// AppResponse message
+func (m Builder) AppResponse(chainID ids.ID, requestID uint32, msg []byte) (Msg, error) {
+	return m.Pack(AppResponse, map[Field]interface{}{
+		ChainID:   chainID[:],
+		RequestID: requestID,
+		AppBytes:  msg,
+	})
+}
+
+// AppGossip message
+func (m Builder) AppGossip(chainID ids.ID, msg []byte) (Msg, error) {
+	return m.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: msg,
+	})
+}
@@ -66,6 +66,31 @@ func TestBuildVersion(t *testing.T) {
 	assert.Equal(t, myVersion, parsedMsg.Get(VersionStr))
 }
 
+func TestBuildVersionWithSubnets(t *testing.T) {
+	ip := utils.IPDesc{
+		IP:   net.IPv6loopback,
+		Port: 12345,
+	}
+	subnetID := ids.Empty.Prefix(0)
+
+	msg, err := TestBuilder.VersionWithSubnets(1, 3, 2, ip, "xD", []ids.ID{subnetID}, true)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, VersionWithSubnets, msg.Op())
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, VersionWithSubnets, parsedMsg.Op())
+	assert.Equal(t, uint32(1), parsedMsg.Get(NetworkID))
+	assert.Equal(t, uint32(3), parsedMsg.Get(NodeID))
+	assert.Equal(t, uint64(2), parsedMsg.Get(MyTime))
+	assert.Equal(t, ip, parsedMsg.Get(IP))
+	assert.Equal(t, "xD", parsedMsg.Get(VersionStr))
+	assert.Equal(t, [][]byte{subnetID[:]}, parsedMsg.Get(SubnetIDs))
+	assert.Equal(t, true, parsedMsg.Get(Compression))
+}
+
 func TestBuildGetPeerList(t *testing.T) {
 	msg, err := TestBuilder.GetPeerList()
 	assert.NoError(t, err)
@@ -316,3 +341,163 @@ func TestBuildChits(t *testing.T) {
 	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
 	assert.Equal(t, containerIDs, parsedMsg.Get(ContainerIDs))
 }
+
+func TestBuildGetStateSummary(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+
+	msg, err := TestBuilder.GetStateSummary(chainID, requestID, deadline)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetStateSummary, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, deadline, msg.Get(Deadline))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetStateSummary, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+}
+
+func TestBuildStateSummary(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	summary := []byte{2}
+
+	msg, err := TestBuilder.StateSummary(chainID, requestID, summary)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, StateSummary, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, summary, msg.Get(ContainerBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, StateSummary, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, summary, parsedMsg.Get(ContainerBytes))
+}
+
+func TestBuildGetStateChunk(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+	summaryID := ids.Empty.Prefix(1)
+	index := uint32(3)
+
+	msg, err := TestBuilder.GetStateChunk(chainID, requestID, deadline, summaryID, index)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, GetStateChunk, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, deadline, msg.Get(Deadline))
+	assert.Equal(t, summaryID[:], msg.Get(ContainerID))
+	assert.Equal(t, index, msg.Get(ChunkIndex))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, GetStateChunk, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+	assert.Equal(t, summaryID[:], parsedMsg.Get(ContainerID))
+	assert.Equal(t, index, parsedMsg.Get(ChunkIndex))
+}
+
+func TestBuildStateChunk(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	chunk := []byte{2}
+
+	msg, err := TestBuilder.StateChunk(chainID, requestID, chunk)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, StateChunk, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, chunk, msg.Get(ContainerBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, StateChunk, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, chunk, parsedMsg.Get(ContainerBytes))
+}
+
+func TestBuildAppRequest(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	deadline := uint64(15)
+	appBytes := []byte{2}
+
+	msg, err := TestBuilder.AppRequest(chainID, requestID, deadline, appBytes)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, AppRequest, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, deadline, msg.Get(Deadline))
+	assert.Equal(t, appBytes, msg.Get(AppBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, AppRequest, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, deadline, parsedMsg.Get(Deadline))
+	assert.Equal(t, appBytes, parsedMsg.Get(AppBytes))
+}
+
+func TestBuildAppResponse(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	appBytes := []byte{2}
+
+	msg, err := TestBuilder.AppResponse(chainID, requestID, appBytes)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, AppResponse, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, requestID, msg.Get(RequestID))
+	assert.Equal(t, appBytes, msg.Get(AppBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, AppResponse, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, requestID, parsedMsg.Get(RequestID))
+	assert.Equal(t, appBytes, parsedMsg.Get(AppBytes))
+}
+
+func TestBuildAppGossip(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	appBytes := []byte{2}
+
+	msg, err := TestBuilder.AppGossip(chainID, appBytes)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, AppGossip, msg.Op())
+	assert.Equal(t, chainID[:], msg.Get(ChainID))
+	assert.Equal(t, appBytes, msg.Get(AppBytes))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, AppGossip, parsedMsg.Op())
+	assert.Equal(t, chainID[:], parsedMsg.Get(ChainID))
+	assert.Equal(t, appBytes, parsedMsg.Get(AppBytes))
+}
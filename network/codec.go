@@ -8,20 +8,51 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
+const (
+	// If this bit of the first byte of a message is set, the bytes of the
+	// message after the first byte are compressed
+	compressedFlag byte = 1 << 7
+
+	// Messages whose fields pack into fewer bytes than this aren't compressed
+	minCompressibleSize = 128
+)
+
 var (
-	errMissingField = errors.New("message missing field")
-	errBadOp        = errors.New("input field has invalid operation")
+	errMissingField          = errors.New("message missing field")
+	errBadOp                 = errors.New("input field has invalid operation")
+	errCompressionDisabled   = errors.New("message is compressed but the codec can't decompress")
+	errUncompressibleMessage = errors.New("message is compressed but its operation doesn't allow compression")
+
+	// Messages whose fields may be compressed. These carry containers, which
+	// are the largest messages and contain redundant bytes.
+	compressibleOps = map[Op]bool{
+		Put:       true,
+		MultiPut:  true,
+		PushQuery: true,
+	}
 )
 
 // Codec defines the serialization and deserialization of network messages
-type Codec struct{}
+type Codec struct {
+	// If non-nil, compresses large messages and decompresses compressed
+	// messages
+	compressor compression.Compressor
+}
+
+// NewCodec returns a codec that compresses and decompresses messages with
+// [compressor]
+func NewCodec(compressor compression.Compressor) Codec {
+	return Codec{compressor: compressor}
+}
 
 // Pack attempts to pack a map of fields into a message.
 // The first byte of the message is the opcode of the message.
-func (Codec) Pack(op Op, fields map[Field]interface{}) (Msg, error) {
+// If the message may be compressed, its compressed form is also packed.
+func (c Codec) Pack(op Op, fields map[Field]interface{}) (Msg, error) {
 	message, ok := Messages[op]
 	if !ok {
 		return nil, errBadOp
@@ -36,17 +67,91 @@ func (Codec) Pack(op Op, fields map[Field]interface{}) (Msg, error) {
 		}
 		field.Packer()(&p, data)
 	}
+	if p.Err != nil {
+		return nil, p.Err
+	}
 
+	compressedBytes, err := c.compress(op, p.Bytes)
 	return &msg{
-		op:     op,
-		fields: fields,
-		bytes:  p.Bytes,
-	}, p.Err
+		op:              op,
+		fields:          fields,
+		bytes:           p.Bytes,
+		compressedBytes: compressedBytes,
+		codec:           c,
+	}, err
+}
+
+// compress returns the compressed form of the message [b], whose opcode is
+// [op], or nil if it shouldn't be sent compressed
+func (c Codec) compress(op Op, b []byte) ([]byte, error) {
+	if c.compressor == nil || !compressibleOps[op] || len(b) < minCompressibleSize {
+		return nil, nil
+	}
+	compressedFields, err := c.compressor.Compress(b[1:])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't compress message: %w", err)
+	}
+	if len(compressedFields)+1 >= len(b) {
+		return nil, nil // Compression doesn't make the message smaller
+	}
+	return append([]byte{b[0] | compressedFlag}, compressedFields...), nil
+}
+
+// packProto returns the message with opcode [op] and [fields] in the protobuf
+// format and its compressed form, which is nil if it shouldn't be sent
+// compressed
+func (c Codec) packProto(op Op, fields map[Field]interface{}) ([]byte, []byte, error) {
+	protoBytes, err := packProto(op, fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := append([]byte{protoFlag}, protoBytes...)
+	compressedBytes, err := c.compress(op, b)
+	return b, compressedBytes, err
 }
 
 // Parse attempts to convert bytes into a message.
 // The first byte of the message is the opcode of the message.
-func (Codec) Parse(b []byte) (Msg, error) {
+func (c Codec) Parse(b []byte) (Msg, error) {
+	if len(b) == 0 {
+		return nil, errBadOp
+	}
+
+	var compressedBytes []byte
+	if header := b[0]; header&compressedFlag != 0 {
+		if c.compressor == nil {
+			return nil, errCompressionDisabled
+		}
+		header &^= compressedFlag
+		if header != protoFlag && !compressibleOps[Op(header)] {
+			return nil, fmt.Errorf("%w: %s", errUncompressibleMessage, Op(header))
+		}
+		fieldBytes, err := c.compressor.Decompress(b[1:])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't decompress message: %w", err)
+		}
+		compressedBytes = b
+		b = append([]byte{header}, fieldBytes...)
+	}
+
+	if b[0] == protoFlag {
+		op, fields, err := parseProto(b[1:])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse protobuf message: %w", err)
+		}
+		if compressedBytes != nil && !compressibleOps[op] {
+			return nil, fmt.Errorf("%w: %s", errUncompressibleMessage, op)
+		}
+		m := &msg{
+			op:     op,
+			fields: fields,
+			bytes:  b,
+			codec:  c,
+		}
+		m.setProtoBytes(b, compressedBytes)
+		return m, nil
+	}
+
 	p := wrappers.Packer{Bytes: b}
 	op := Op(p.UnpackByte())
 	message, ok := Messages[op]
@@ -64,8 +169,10 @@ func (Codec) Parse(b []byte) (Msg, error) {
 	}
 
 	return &msg{
-		op:     op,
-		fields: fields,
-		bytes:  b,
+		op:              op,
+		fields:          fields,
+		bytes:           b,
+		compressedBytes: compressedBytes,
+		codec:           c,
 	}, p.Err
 }
@@ -4,10 +4,14 @@
 package network
 
 import (
+	"bytes"
 	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/compression"
 )
 
 var (
@@ -33,3 +37,102 @@ func TestCodecParseExtraSpace(t *testing.T) {
 	_, err := TestCodec.Parse([]byte{byte(GetVersion), 0x00})
 	assert.Error(t, err)
 }
+
+func TestCodecCompressedRoundTrip(t *testing.T) {
+	codec := NewCodec(compression.NewGzipCompressor(int64(DefaultMaxMessageSize)))
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+	container := bytes.Repeat([]byte{1, 2, 3, 4}, 1024)
+
+	msg, err := codec.Pack(Put, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      uint32(5),
+		ContainerID:    containerID[:],
+		ContainerBytes: container,
+	})
+	assert.NoError(t, err)
+	compressedBytes := msg.CompressedBytes()
+	assert.NotNil(t, compressedBytes)
+	assert.Less(t, len(compressedBytes), len(msg.Bytes()))
+
+	parsedMsg, err := codec.Parse(compressedBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, Put, parsedMsg.Op())
+	assert.Equal(t, msg.Bytes(), parsedMsg.Bytes())
+	assert.Equal(t, compressedBytes, parsedMsg.CompressedBytes())
+	assert.Equal(t, container, parsedMsg.Get(ContainerBytes))
+
+	// The uncompressed form of the message is still valid
+	parsedMsg, err = codec.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.Nil(t, parsedMsg.CompressedBytes())
+	assert.Equal(t, container, parsedMsg.Get(ContainerBytes))
+}
+
+func TestCodecDoesntCompressSmallMessages(t *testing.T) {
+	codec := NewCodec(compression.NewGzipCompressor(int64(DefaultMaxMessageSize)))
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+
+	msg, err := codec.Pack(Put, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      uint32(5),
+		ContainerID:    containerID[:],
+		ContainerBytes: []byte{1},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, msg.CompressedBytes())
+}
+
+func TestCodecDoesntCompressUncompressibleOps(t *testing.T) {
+	codec := NewCodec(compression.NewGzipCompressor(int64(DefaultMaxMessageSize)))
+	chainID := ids.Empty.Prefix(0)
+
+	msg, err := codec.Pack(AppGossip, map[Field]interface{}{
+		ChainID:  chainID[:],
+		AppBytes: make([]byte, 1024),
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, msg.CompressedBytes())
+
+	// A compressed message of an op that doesn't allow compression is invalid
+	compressedFields, err := compression.NewGzipCompressor(int64(DefaultMaxMessageSize)).Compress(msg.Bytes()[1:])
+	assert.NoError(t, err)
+	_, err = codec.Parse(append([]byte{byte(AppGossip) | compressedFlag}, compressedFields...))
+	assert.Error(t, err)
+}
+
+func TestCodecParseCompressedWithoutCompressor(t *testing.T) {
+	codec := NewCodec(compression.NewGzipCompressor(int64(DefaultMaxMessageSize)))
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+
+	msg, err := codec.Pack(Put, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      uint32(5),
+		ContainerID:    containerID[:],
+		ContainerBytes: make([]byte, 1024),
+	})
+	assert.NoError(t, err)
+
+	_, err = TestCodec.Parse(msg.CompressedBytes())
+	assert.Error(t, err)
+}
+
+func TestCodecParseDecompressedTooLarge(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+
+	msg, err := NewCodec(compression.NewGzipCompressor(int64(DefaultMaxMessageSize))).Pack(Put, map[Field]interface{}{
+		ChainID:        chainID[:],
+		RequestID:      uint32(5),
+		ContainerID:    containerID[:],
+		ContainerBytes: make([]byte, 1<<20),
+	})
+	assert.NoError(t, err)
+
+	// A receiver that allows smaller messages must not decompress this one
+	codec := NewCodec(compression.NewGzipCompressor(1024))
+	_, err = codec.Parse(msg.CompressedBytes())
+	assert.Error(t, err)
+}
@@ -25,6 +25,11 @@ const (
 	ContainerBytes                   // Used for gossiping
 	ContainerIDs                     // Used for querying
 	MultiContainerBytes              // Used in MultiPut
+	ChunkIndex                       // Used in GetStateChunk
+	AppBytes                         // Used in app messages
+	SignedPeers                      // Used in handshake
+	SubnetIDs                        // Used in handshake
+	Compression                      // Used in handshake
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -56,6 +61,16 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackHashes
 	case MultiContainerBytes:
 		return wrappers.TryPack2DBytes
+	case ChunkIndex:
+		return wrappers.TryPackInt
+	case AppBytes:
+		return wrappers.TryPackBytes
+	case SignedPeers:
+		return tryPackSignedIPs
+	case SubnetIDs:
+		return wrappers.TryPackHashes
+	case Compression:
+		return wrappers.TryPackBool
 	default:
 		return nil
 	}
@@ -90,6 +105,16 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackHashes
 	case MultiContainerBytes:
 		return wrappers.TryUnpack2DBytes
+	case ChunkIndex:
+		return wrappers.TryUnpackInt
+	case AppBytes:
+		return wrappers.TryUnpackBytes
+	case SignedPeers:
+		return tryUnpackSignedIPs
+	case SubnetIDs:
+		return wrappers.TryUnpackHashes
+	case Compression:
+		return wrappers.TryUnpackBool
 	default:
 		return nil
 	}
@@ -123,6 +148,16 @@ func (f Field) String() string {
 		return "Container IDs"
 	case MultiContainerBytes:
 		return "MultiContainerBytes"
+	case ChunkIndex:
+		return "ChunkIndex"
+	case AppBytes:
+		return "AppBytes"
+	case SignedPeers:
+		return "SignedPeers"
+	case SubnetIDs:
+		return "SubnetIDs"
+	case Compression:
+		return "Compression"
 	default:
 		return "Unknown Field"
 	}
@@ -167,6 +202,24 @@ func (op Op) String() string {
 		return "pull_query"
 	case Chits:
 		return "chits"
+	case GetStateSummary:
+		return "get_state_summary"
+	case StateSummary:
+		return "state_summary"
+	case GetStateChunk:
+		return "get_state_chunk"
+	case StateChunk:
+		return "state_chunk"
+	case AppRequest:
+		return "app_request"
+	case AppResponse:
+		return "app_response"
+	case AppGossip:
+		return "app_gossip"
+	case SignedPeerList:
+		return "signed_peerlist"
+	case VersionWithSubnets:
+		return "version_with_subnets"
 	default:
 		return "Unknown Op"
 	}
@@ -194,6 +247,19 @@ const (
 	PushQuery
 	PullQuery
 	Chits
+	// State sync:
+	GetStateSummary
+	StateSummary
+	GetStateChunk
+	StateChunk
+	// Application:
+	AppRequest
+	AppResponse
+	AppGossip
+	// Handshake, with signed IPs:
+	SignedPeerList
+	// Handshake, with subnets:
+	VersionWithSubnets
 )
 
 // Defines the messages that can be sent/received with this network
@@ -219,5 +285,18 @@ var (
 		PushQuery: {ChainID, RequestID, Deadline, ContainerID, ContainerBytes},
 		PullQuery: {ChainID, RequestID, Deadline, ContainerID},
 		Chits:     {ChainID, RequestID, ContainerIDs},
+		// State sync:
+		GetStateSummary: {ChainID, RequestID, Deadline},
+		StateSummary:    {ChainID, RequestID, ContainerBytes},
+		GetStateChunk:   {ChainID, RequestID, Deadline, ContainerID, ChunkIndex},
+		StateChunk:      {ChainID, RequestID, ContainerBytes},
+		// Application:
+		AppRequest:  {ChainID, RequestID, Deadline, AppBytes},
+		AppResponse: {ChainID, RequestID, AppBytes},
+		AppGossip:   {ChainID, AppBytes},
+		// Handshake, with signed IPs:
+		SignedPeerList: {SignedPeers},
+		// Handshake, with subnets:
+		VersionWithSubnets: {NetworkID, NodeID, MyTime, IP, VersionStr, SubnetIDs, Compression},
 	}
 )
@@ -9,7 +9,9 @@ import (
 	"github.com/ava-labs/avalanchego/utils"
 )
 
-// Dialer attempts to create a connection with the provided IP/port pair
+// Dialer attempts to create a connection with the provided IP/port pair. The
+// connection may be a StreamConn, in which case messages are sent on its
+// streams.
 type Dialer interface {
 	Dial(utils.IPDesc) (net.Conn, error)
 }
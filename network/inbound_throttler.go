@@ -0,0 +1,186 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+// InboundThrottlerConfig describes how quickly peers may send us messages
+type InboundThrottlerConfig struct {
+	// Bytes and messages per second that every peer may send us. If a rate is
+	// 0, peers aren't limited by it.
+	BytesPerSec, MsgsPerSec uint64
+
+	// Bytes and messages per second that are shared between the validators, in
+	// addition to the rates above, in proportion to their stake
+	StakerBytesPerSec, StakerMsgsPerSec uint64
+
+	// A peer that hasn't sent us anything for a while may send this many
+	// seconds' worth of its rates at once
+	Burst time.Duration
+}
+
+// The buckets of peers are checked this often, and the buckets that have
+// refilled are discarded
+const inboundThrottlerPruneInterval = time.Minute
+
+// InboundThrottler limits the rate at which each peer may send us messages.
+// Rather than dropping the messages of a peer that exceeds its rate, we stop
+// reading from its connection until it is back within its rate.
+//
+// The state of a peer is kept, by node ID, until the peer has been within its
+// rate for long enough to be back where a new peer starts. So a peer can't get
+// a fresh allowance by reconnecting.
+type InboundThrottler interface {
+	// Acquire registers that [nodeID] sent us a message of [msgSize] bytes.
+	// Returns how long we should wait before handling the message, and reading
+	// any more messages, from [nodeID].
+	Acquire(nodeID ids.ShortID, msgSize int) time.Duration
+}
+
+// NewInboundThrottler returns a new inbound throttler. Validators are looked
+// up in [vdrs]. If the config has no rates, returns an InboundThrottler that
+// never throttles.
+func NewInboundThrottler(config InboundThrottlerConfig, vdrs validators.Set) InboundThrottler {
+	if config.BytesPerSec == 0 && config.MsgsPerSec == 0 &&
+		config.StakerBytesPerSec == 0 && config.StakerMsgsPerSec == 0 {
+		return &noInboundThrottler{}
+	}
+	return &inboundThrottler{
+		config:  config,
+		vdrs:    vdrs,
+		buckets: make(map[ids.ShortID]*peerBuckets),
+	}
+}
+
+type noInboundThrottler struct{}
+
+func (*noInboundThrottler) Acquire(ids.ShortID, int) time.Duration { return 0 }
+
+// inboundThrottler implements InboundThrottler
+type inboundThrottler struct {
+	lock   sync.Mutex
+	clock  timer.Clock
+	config InboundThrottlerConfig
+	vdrs   validators.Set
+
+	// node ID --> the buckets of that peer
+	buckets map[ids.ShortID]*peerBuckets
+	// The last time that the buckets were pruned
+	lastPrune time.Time
+}
+
+type peerBuckets struct {
+	bytes, msgs tokenBucket
+}
+
+func (t *inboundThrottler) Acquire(nodeID ids.ShortID, msgSize int) time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	if now.Sub(t.lastPrune) >= inboundThrottlerPruneInterval {
+		t.prune(now)
+	}
+
+	buckets, ok := t.buckets[nodeID]
+	if !ok {
+		buckets = &peerBuckets{}
+		t.buckets[nodeID] = buckets
+	}
+
+	bytesRate, msgsRate := t.rates(nodeID)
+	bytesWait := buckets.bytes.take(now, bytesRate, t.config.Burst, float64(msgSize))
+	msgsWait := buckets.msgs.take(now, msgsRate, t.config.Burst, 1)
+	if bytesWait > msgsWait {
+		return bytesWait
+	}
+	return msgsWait
+}
+
+// rates returns the bytes and messages per second that [nodeID] may send us.
+// Assumes [t.lock] is held.
+func (t *inboundThrottler) rates(nodeID ids.ShortID) (float64, float64) {
+	// The fraction of the total stake that [nodeID] has
+	stake := float64(0)
+	if weight, ok := t.vdrs.GetWeight(nodeID); ok {
+		if totalWeight := t.vdrs.Weight(); totalWeight > 0 {
+			stake = float64(weight) / float64(totalWeight)
+		}
+	}
+	return float64(t.config.BytesPerSec) + stake*float64(t.config.StakerBytesPerSec),
+		float64(t.config.MsgsPerSec) + stake*float64(t.config.StakerMsgsPerSec)
+}
+
+// prune discards the buckets that are full at [now]. A full bucket is the same
+// as the bucket that a new peer starts with, so discarding it doesn't give the
+// peer anything. Assumes [t.lock] is held.
+func (t *inboundThrottler) prune(now time.Time) {
+	t.lastPrune = now
+	for nodeID, buckets := range t.buckets {
+		bytesRate, msgsRate := t.rates(nodeID)
+		if buckets.bytes.full(now, bytesRate, t.config.Burst) && buckets.msgs.full(now, msgsRate, t.config.Burst) {
+			delete(t.buckets, nodeID)
+		}
+	}
+}
+
+// tokenBucket is filled at a constant rate, up to a maximum, and emptied by
+// the messages a peer sends. The bucket may go into debt, which is paid off
+// before the peer may send anything else.
+type tokenBucket struct {
+	// Tokens in the bucket at [lastUpdate]. Negative if the bucket is in debt.
+	tokens     float64
+	lastUpdate time.Time
+	// Whether the bucket has been filled for the first time
+	started bool
+}
+
+// take fills the bucket, which is filled with [rate] tokens per second up to
+// [burst] worth of tokens, until [now] and removes [amount] tokens. Returns
+// how long it will take for the bucket to be out of debt. If [rate] is 0, the
+// bucket is unlimited.
+func (b *tokenBucket) take(now time.Time, rate float64, burst time.Duration, amount float64) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+
+	capacity := rate * burst.Seconds()
+	if !b.started {
+		// Peers start with a full bucket
+		b.tokens = capacity
+		b.started = true
+	} else if elapsed := now.Sub(b.lastUpdate); elapsed > 0 {
+		b.tokens += rate * elapsed.Seconds()
+	}
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.lastUpdate = now
+
+	b.tokens -= amount
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / rate * float64(time.Second))
+}
+
+// full returns true if the bucket, which is filled with [rate] tokens per
+// second up to [burst] worth of tokens, is full at [now]
+func (b *tokenBucket) full(now time.Time, rate float64, burst time.Duration) bool {
+	if rate <= 0 || !b.started {
+		return true
+	}
+	tokens := b.tokens
+	if elapsed := now.Sub(b.lastUpdate); elapsed > 0 {
+		tokens += rate * elapsed.Seconds()
+	}
+	return tokens >= rate*burst.Seconds()
+}
@@ -0,0 +1,131 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func TestNoInboundThrottler(t *testing.T) {
+	throttler := NewInboundThrottler(InboundThrottlerConfig{}, validators.NewSet())
+
+	nodeID := ids.GenerateTestShortID()
+	for i := 0; i < 100; i++ {
+		assert.Zero(t, throttler.Acquire(nodeID, int(DefaultMaxMessageSize)))
+	}
+}
+
+func TestInboundThrottlerBytes(t *testing.T) {
+	throttler := NewInboundThrottler(InboundThrottlerConfig{
+		BytesPerSec: 1000,
+		Burst:       time.Second,
+	}, validators.NewSet()).(*inboundThrottler)
+	now := time.Unix(0, 0)
+	throttler.clock.Set(now)
+
+	// A new peer may send a burst right away
+	nodeID := ids.GenerateTestShortID()
+	assert.Zero(t, throttler.Acquire(nodeID, 1000))
+
+	// Once the burst is used, the peer must wait for the bucket to refill
+	assert.Equal(t, 500*time.Millisecond, throttler.Acquire(nodeID, 500))
+
+	// Waiting pays off the debt
+	now = now.Add(500 * time.Millisecond)
+	throttler.clock.Set(now)
+	assert.Zero(t, throttler.Acquire(nodeID, 0))
+
+	// The bucket never holds more than the burst
+	now = now.Add(time.Hour)
+	throttler.clock.Set(now)
+	assert.Zero(t, throttler.Acquire(nodeID, 1000))
+	assert.Equal(t, time.Second, throttler.Acquire(nodeID, 1000))
+
+	// Other peers have their own buckets
+	assert.Zero(t, throttler.Acquire(ids.GenerateTestShortID(), 1000))
+}
+
+func TestInboundThrottlerMsgs(t *testing.T) {
+	throttler := NewInboundThrottler(InboundThrottlerConfig{
+		MsgsPerSec: 10,
+		Burst:      time.Second,
+	}, validators.NewSet()).(*inboundThrottler)
+	throttler.clock.Set(time.Unix(0, 0))
+
+	nodeID := ids.GenerateTestShortID()
+	for i := 0; i < 10; i++ {
+		assert.Zero(t, throttler.Acquire(nodeID, int(DefaultMaxMessageSize)))
+	}
+	assert.Equal(t, 100*time.Millisecond, throttler.Acquire(nodeID, 0))
+}
+
+func TestInboundThrottlerStakers(t *testing.T) {
+	vdrs := validators.NewSet()
+	staker0 := ids.GenerateTestShortID()
+	staker1 := ids.GenerateTestShortID()
+	assert.NoError(t, vdrs.AddWeight(staker0, 3))
+	assert.NoError(t, vdrs.AddWeight(staker1, 1))
+
+	throttler := NewInboundThrottler(InboundThrottlerConfig{
+		BytesPerSec:       1000,
+		StakerBytesPerSec: 4000,
+		Burst:             time.Second,
+	}, vdrs).(*inboundThrottler)
+	throttler.clock.Set(time.Unix(0, 0))
+
+	// Stakers get a share of the staker allowance in proportion to their stake
+	assert.Zero(t, throttler.Acquire(staker0, 4000))
+	assert.Equal(t, 250*time.Millisecond, throttler.Acquire(staker0, 1000))
+	assert.Zero(t, throttler.Acquire(staker1, 2000))
+	assert.Equal(t, 500*time.Millisecond, throttler.Acquire(staker1, 1000))
+
+	nonStaker := ids.GenerateTestShortID()
+	assert.Zero(t, throttler.Acquire(nonStaker, 1000))
+	assert.Equal(t, time.Second, throttler.Acquire(nonStaker, 1000))
+}
+
+func TestInboundThrottlerOnlyStakerRates(t *testing.T) {
+	vdrs := validators.NewSet()
+	staker := ids.GenerateTestShortID()
+	assert.NoError(t, vdrs.AddWeight(staker, 1))
+
+	throttler := NewInboundThrottler(InboundThrottlerConfig{
+		StakerMsgsPerSec: 10,
+		Burst:            time.Second,
+	}, vdrs)
+	_, ok := throttler.(*inboundThrottler)
+	assert.True(t, ok, "staker rates should throttle")
+}
+
+func TestInboundThrottlerPrune(t *testing.T) {
+	throttler := NewInboundThrottler(InboundThrottlerConfig{
+		BytesPerSec: 1000,
+		Burst:       time.Second,
+	}, validators.NewSet()).(*inboundThrottler)
+	now := time.Unix(0, 0)
+	throttler.clock.Set(now)
+
+	nodeID := ids.GenerateTestShortID()
+	assert.Zero(t, throttler.Acquire(nodeID, 1000))
+	assert.Equal(t, 100*time.Second, throttler.Acquire(nodeID, 100000))
+
+	// The peer keeps its debt while its bucket refills, even if it reconnects
+	now = now.Add(inboundThrottlerPruneInterval)
+	throttler.clock.Set(now)
+	assert.Equal(t, 40*time.Second, throttler.Acquire(nodeID, 0))
+	assert.Len(t, throttler.buckets, 1)
+
+	// Once the bucket is full again, it's discarded
+	now = now.Add(inboundThrottlerPruneInterval)
+	throttler.clock.Set(now)
+	assert.Zero(t, throttler.Acquire(ids.GenerateTestShortID(), 0))
+	assert.Len(t, throttler.buckets, 1)
+	assert.NotContains(t, throttler.buckets, nodeID)
+}
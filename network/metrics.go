@@ -14,6 +14,14 @@ import (
 
 type messageMetrics struct {
 	numSent, numFailed, numReceived prometheus.Counter
+
+	// Number of messages that weren't sent because the send queue was full
+	numDropped prometheus.Counter
+
+	// Bytes of the messages before compression and after compression. Equal
+	// for messages that weren't compressed.
+	sentBytes, sentCompressedBytes         prometheus.Counter
+	receivedBytes, receivedCompressedBytes prometheus.Counter
 }
 
 func (mm *messageMetrics) initialize(msgType Op, registerer prometheus.Registerer) error {
@@ -32,6 +40,31 @@ func (mm *messageMetrics) initialize(msgType Op, registerer prometheus.Registere
 		Name:      fmt.Sprintf("%s_received", msgType),
 		Help:      fmt.Sprintf("Number of %s messages received", msgType),
 	})
+	mm.numDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      fmt.Sprintf("%s_dropped", msgType),
+		Help:      fmt.Sprintf("Number of %s messages dropped due to a full send queue", msgType),
+	})
+	mm.sentBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      fmt.Sprintf("%s_sent_bytes", msgType),
+		Help:      fmt.Sprintf("Number of bytes of %s messages sent, before compression", msgType),
+	})
+	mm.sentCompressedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      fmt.Sprintf("%s_sent_compressed_bytes", msgType),
+		Help:      fmt.Sprintf("Number of bytes of %s messages sent, after compression", msgType),
+	})
+	mm.receivedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      fmt.Sprintf("%s_received_bytes", msgType),
+		Help:      fmt.Sprintf("Number of bytes of %s messages received, after decompression", msgType),
+	})
+	mm.receivedCompressedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      fmt.Sprintf("%s_received_compressed_bytes", msgType),
+		Help:      fmt.Sprintf("Number of bytes of %s messages received, before decompression", msgType),
+	})
 
 	if err := registerer.Register(mm.numSent); err != nil {
 		return fmt.Errorf("failed to register sent statistics of %s due to %s",
@@ -45,19 +78,74 @@ func (mm *messageMetrics) initialize(msgType Op, registerer prometheus.Registere
 		return fmt.Errorf("failed to register received statistics of %s due to %s",
 			msgType, err)
 	}
+	if err := registerer.Register(mm.numDropped); err != nil {
+		return fmt.Errorf("failed to register dropped statistics of %s due to %s",
+			msgType, err)
+	}
+	if err := registerer.Register(mm.sentBytes); err != nil {
+		return fmt.Errorf("failed to register sent bytes statistics of %s due to %s",
+			msgType, err)
+	}
+	if err := registerer.Register(mm.sentCompressedBytes); err != nil {
+		return fmt.Errorf("failed to register sent compressed bytes statistics of %s due to %s",
+			msgType, err)
+	}
+	if err := registerer.Register(mm.receivedBytes); err != nil {
+		return fmt.Errorf("failed to register received bytes statistics of %s due to %s",
+			msgType, err)
+	}
+	if err := registerer.Register(mm.receivedCompressedBytes); err != nil {
+		return fmt.Errorf("failed to register received compressed bytes statistics of %s due to %s",
+			msgType, err)
+	}
+	return nil
+}
+
+type priorityMetrics struct {
+	// Number of messages of this priority that weren't sent because the send
+	// queue was full
+	numDropped prometheus.Counter
+}
+
+func (pm *priorityMetrics) initialize(priority Priority, registerer prometheus.Registerer) error {
+	pm.numDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      fmt.Sprintf("%s_priority_dropped", priority),
+		Help:      fmt.Sprintf("Number of %s priority messages dropped due to a full send queue", priority),
+	})
+	if err := registerer.Register(pm.numDropped); err != nil {
+		return fmt.Errorf("failed to register dropped statistics of %s priority due to %s",
+			priority, err)
+	}
 	return nil
 }
 
 type metrics struct {
 	numPeers prometheus.Gauge
 
+	// Number of messages whose handling was delayed by the inbound throttler
+	// and the total time, in milliseconds, they were delayed for
+	numThrottled, throttledTime prometheus.Counter
+
+	// Number of times peers misbehaved, and the number of peers that are
+	// banned
+	numMisbehaviors prometheus.Counter
+	numBanned       prometheus.Gauge
+
 	getVersion, version,
 	getPeerlist, peerlist,
 	ping, pong,
 	getAcceptedFrontier, acceptedFrontier,
 	getAccepted, accepted,
 	get, getAncestors, put, multiPut,
-	pushQuery, pullQuery, chits messageMetrics
+	pushQuery, pullQuery, chits,
+	getStateSummary, stateSummary,
+	getStateChunk, stateChunk,
+	appRequest, appResponse, appGossip,
+	signedPeerlist, versionWithSubnets messageMetrics
+
+	// priority --> metrics of the messages of that priority
+	priorities [NumPriorities]priorityMetrics
 }
 
 func (m *metrics) initialize(registerer prometheus.Registerer) error {
@@ -67,11 +155,49 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 		Help:      "Number of network peers",
 	})
 
+	m.numThrottled = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "inbound_throttled",
+		Help:      "Number of messages whose handling was delayed by the inbound throttler",
+	})
+	m.throttledTime = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "inbound_throttled_time",
+		Help:      "Total time, in milliseconds, that the handling of messages was delayed by the inbound throttler",
+	})
+
+	m.numMisbehaviors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: constants.PlatformName,
+		Name:      "peer_misbehaviors",
+		Help:      "Number of times peers misbehaved",
+	})
+	m.numBanned = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: constants.PlatformName,
+		Name:      "banned_peers",
+		Help:      "Number of peers that are banned",
+	})
+
 	errs := wrappers.Errs{}
 	if err := registerer.Register(m.numPeers); err != nil {
 		errs.Add(fmt.Errorf("failed to register peers statistics due to %s",
 			err))
 	}
+	if err := registerer.Register(m.numThrottled); err != nil {
+		errs.Add(fmt.Errorf("failed to register inbound throttled statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.throttledTime); err != nil {
+		errs.Add(fmt.Errorf("failed to register inbound throttled time statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.numMisbehaviors); err != nil {
+		errs.Add(fmt.Errorf("failed to register peer misbehaviors statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.numBanned); err != nil {
+		errs.Add(fmt.Errorf("failed to register banned peers statistics due to %s",
+			err))
+	}
 	errs.Add(
 		m.getVersion.initialize(GetVersion, registerer),
 		m.version.initialize(Version, registerer),
@@ -90,7 +216,19 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 		m.pushQuery.initialize(PushQuery, registerer),
 		m.pullQuery.initialize(PullQuery, registerer),
 		m.chits.initialize(Chits, registerer),
+		m.getStateSummary.initialize(GetStateSummary, registerer),
+		m.stateSummary.initialize(StateSummary, registerer),
+		m.getStateChunk.initialize(GetStateChunk, registerer),
+		m.stateChunk.initialize(StateChunk, registerer),
+		m.appRequest.initialize(AppRequest, registerer),
+		m.appResponse.initialize(AppResponse, registerer),
+		m.appGossip.initialize(AppGossip, registerer),
+		m.signedPeerlist.initialize(SignedPeerList, registerer),
+		m.versionWithSubnets.initialize(VersionWithSubnets, registerer),
 	)
+	for priority := range m.priorities {
+		errs.Add(m.priorities[priority].initialize(Priority(priority), registerer))
+	}
 	return errs.Err
 }
 
@@ -130,6 +268,24 @@ func (m *metrics) message(msgType Op) *messageMetrics {
 		return &m.pullQuery
 	case Chits:
 		return &m.chits
+	case GetStateSummary:
+		return &m.getStateSummary
+	case StateSummary:
+		return &m.stateSummary
+	case GetStateChunk:
+		return &m.getStateChunk
+	case StateChunk:
+		return &m.stateChunk
+	case AppRequest:
+		return &m.appRequest
+	case AppResponse:
+		return &m.appResponse
+	case AppGossip:
+		return &m.appGossip
+	case SignedPeerList:
+		return &m.signedPeerlist
+	case VersionWithSubnets:
+		return &m.versionWithSubnets
 	default:
 		return nil
 	}
@@ -3,17 +3,32 @@
 
 package network
 
+import (
+	"sync"
+)
+
 // Msg represents a set of fields that can be serialized into a byte stream
 type Msg interface {
 	Op() Op
 	Get(Field) interface{}
 	Bytes() []byte
+	CompressedBytes() []byte
+	ProtoBytes() []byte
+	CompressedProtoBytes() []byte
 }
 
 type msg struct {
-	op     Op
-	fields map[Field]interface{}
-	bytes  []byte
+	op              Op
+	fields          map[Field]interface{}
+	bytes           []byte
+	compressedBytes []byte
+
+	// The protobuf format of this message is only packed if it's sent to a
+	// peer that parses that format
+	codec                Codec
+	protoOnce            sync.Once
+	protoBytes           []byte
+	compressedProtoBytes []byte
 }
 
 // Field returns the value of the specified field in this message
@@ -24,3 +39,38 @@ func (msg *msg) Get(field Field) interface{} { return msg.fields[field] }
 
 // Bytes returns this message in bytes
 func (msg *msg) Bytes() []byte { return msg.bytes }
+
+// CompressedBytes returns this message in bytes, with its fields compressed.
+// Returns nil if this message isn't compressed.
+func (msg *msg) CompressedBytes() []byte { return msg.compressedBytes }
+
+// ProtoBytes returns this message in bytes in the protobuf format. Returns nil
+// if this message can't be packed in the protobuf format.
+func (msg *msg) ProtoBytes() []byte {
+	msg.protoOnce.Do(msg.packProto)
+	return msg.protoBytes
+}
+
+// CompressedProtoBytes returns this message in bytes in the protobuf format,
+// with its fields compressed. Returns nil if this message isn't compressed.
+func (msg *msg) CompressedProtoBytes() []byte {
+	msg.protoOnce.Do(msg.packProto)
+	return msg.compressedProtoBytes
+}
+
+func (msg *msg) packProto() {
+	protoBytes, compressedProtoBytes, err := msg.codec.packProto(msg.op, msg.fields)
+	if err != nil {
+		return
+	}
+	msg.protoBytes = protoBytes
+	msg.compressedProtoBytes = compressedProtoBytes
+}
+
+// setProtoBytes sets the protobuf format of this message
+func (msg *msg) setProtoBytes(protoBytes, compressedProtoBytes []byte) {
+	msg.protoOnce.Do(func() {
+		msg.protoBytes = protoBytes
+		msg.compressedProtoBytes = compressedProtoBytes
+	})
+}
@@ -4,6 +4,8 @@
 package network
 
 import (
+	"crypto"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -15,6 +17,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
@@ -22,6 +25,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/triggers"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -49,13 +53,22 @@ const (
 	defaultReadBufferSize                            = 16 * 1024
 	defaultReadHandshakeTimeout                      = 15 * time.Second
 	defaultConnMeterCacheSize                        = 10000
+
+	// Validators of the subnets we track, other than the primary network, are
+	// redialed at least this many times as often as other nodes, as few nodes
+	// validate each subnet
+	subnetValidatorReconnectFactor = 4
 )
 
 var (
-	errNetworkClosed = errors.New("network closed")
-	errPeerIsMyself  = errors.New("peer is myself")
+	errNetworkClosed      = errors.New("network closed")
+	errPeerIsMyself       = errors.New("peer is myself")
+	errSignedIPFromFuture = errors.New("signed IP has a timestamp in the future")
 
 	minimumUnmaskedVersion = version.NewDefaultVersion(constants.PlatformName, 1, 1, 0)
+	minimumProtoVersion    = version.NewDefaultVersion(constants.PlatformName, 1, 2, 1)
+	minimumSignedIPVersion = version.NewDefaultVersion(constants.PlatformName, 1, 2, 1)
+	minimumSubnetsVersion  = version.NewDefaultVersion(constants.PlatformName, 1, 2, 1)
 )
 
 func init() { rand.Seed(time.Now().UnixNano()) }
@@ -95,6 +108,26 @@ type Network interface {
 
 	// Return the IP of the node
 	IP() utils.IPDesc
+
+	// Re-reads the allowlist from its file and applies it. Returns the nodes
+	// on the allowlist. Thread safety must be managed internally to the
+	// network.
+	ReloadAllowlist() ([]ids.ShortID, error)
+
+	// The network scores peers by the misbehavior reported to it and bans the
+	// peers whose scores fall too low. Thread safety must be managed
+	// internally to the network.
+	snow.PeerReporter
+
+	// Disconnect from the node and refuse its connections for the duration.
+	// Bans are persisted, so they survive restarts.
+	Ban(nodeID ids.ShortID, duration time.Duration) error
+
+	// Lift the ban on the node.
+	Unban(nodeID ids.ShortID) error
+
+	// Returns the banned nodes and the times their bans end.
+	Bans() map[ids.ShortID]time.Time
 }
 
 type network struct {
@@ -122,6 +155,7 @@ type network struct {
 	maxMessageSize                     int64
 	sendQueueSize                      uint32
 	maxNetworkPendingSendBytes         int64
+	peerPendingSendBytes               [NumPriorities]int64
 	networkPendingSendBytesToRateLimit int64
 	maxClockDifference                 time.Duration
 	peerListGossipSpacing              time.Duration
@@ -140,6 +174,38 @@ type network struct {
 	b                                  Builder
 	apricotPhase0Time                  time.Time
 
+	// True iff we tell peers that we accept compressed messages, and send
+	// large messages compressed to the peers that tell us the same
+	compressionEnabled bool
+
+	// Limits the rate at which each peer may send us messages
+	inboundThrottler InboundThrottler
+
+	// Staking certificate and key that our IP is signed with. If nil, peer
+	// lists are sent without signatures.
+	stakingCert   []byte
+	stakingSigner crypto.Signer
+	// Our IP, signed with our staking key. Re-signed when our IP changes.
+	mySignedIP *SignedIP
+	// node ID --> the latest IP signed by that validator
+	signedIPs map[ids.ShortID]*SignedIP
+
+	// The nodes we may connect to and show to others
+	allowlistConfig AllowlistConfig
+	// node ID --> the IP of that node, which was dialed but the node wasn't on
+	// the allowlist. It's dialed again if the node is added to the allowlist.
+	rejectedIPs map[ids.ShortID]utils.IPDesc
+
+	// Subnets we track, including the primary network. Peers are sent the IPs
+	// of the validators of the subnets we both track.
+	trackedSubnets ids.Set
+	// Validators of each subnet. May be nil, in which case only the validators
+	// of the primary network are known.
+	subnetVdrs validators.Manager
+
+	// Scores of peers and the peers that are banned
+	reputation *reputation
+
 	// stateLock should never be held when grabbing a peer lock
 	stateLock       sync.RWMutex
 	pendingBytes    int64
@@ -204,6 +270,14 @@ func NewDefaultNetwork(
 	disconnectedRestartTimeout time.Duration,
 	apricotPhase0Time time.Time,
 	sendQueueSize uint32,
+	compressionEnabled bool,
+	inboundThrottlerConfig InboundThrottlerConfig,
+	stakingCert *tls.Certificate,
+	allowlistConfig AllowlistConfig,
+	trackedSubnets ids.Set,
+	subnetVdrs validators.Manager,
+	reputationConfig ReputationConfig,
+	banDB database.Database,
 ) Network {
 	return NewNetwork(
 		registerer,
@@ -226,6 +300,7 @@ func NewDefaultNetwork(
 		sendQueueSize,
 		defaultMaxNetworkPendingSendBytes,
 		defaultNetworkPendingSendBytesToRateLimit,
+		DefaultPeerPendingSendBytes,
 		defaultMaxClockDifference,
 		defaultPeerListGossipSpacing,
 		defaultPeerListGossipSize,
@@ -245,6 +320,14 @@ func NewDefaultNetwork(
 		disconnectedCheckFreq,
 		disconnectedRestartTimeout,
 		apricotPhase0Time,
+		compressionEnabled,
+		inboundThrottlerConfig,
+		stakingCert,
+		allowlistConfig,
+		trackedSubnets,
+		subnetVdrs,
+		reputationConfig,
+		banDB,
 	)
 }
 
@@ -270,6 +353,7 @@ func NewNetwork(
 	sendQueueSize uint32,
 	maxNetworkPendingSendBytes int,
 	networkPendingSendBytesToRateLimit int,
+	peerPendingSendBytes [NumPriorities]int64,
 	maxClockDifference time.Duration,
 	peerListGossipSpacing time.Duration,
 	peerListGossipSize int,
@@ -289,6 +373,14 @@ func NewNetwork(
 	disconnectedCheckFreq time.Duration,
 	disconnectedRestartTimeout time.Duration,
 	apricotPhase0Time time.Time,
+	compressionEnabled bool,
+	inboundThrottlerConfig InboundThrottlerConfig,
+	stakingCert *tls.Certificate,
+	allowlistConfig AllowlistConfig,
+	trackedSubnets ids.Set,
+	subnetVdrs validators.Manager,
+	reputationConfig ReputationConfig,
+	banDB database.Database,
 ) Network {
 	// #nosec G404
 	netw := &network{
@@ -315,6 +407,7 @@ func NewNetwork(
 		sendQueueSize:                      sendQueueSize,
 		maxNetworkPendingSendBytes:         int64(maxNetworkPendingSendBytes),
 		networkPendingSendBytesToRateLimit: int64(networkPendingSendBytesToRateLimit),
+		peerPendingSendBytes:               peerPendingSendBytes,
 		maxClockDifference:                 maxClockDifference,
 		peerListGossipSpacing:              peerListGossipSpacing,
 		peerListGossipSize:                 peerListGossipSize,
@@ -339,11 +432,36 @@ func NewNetwork(
 		connectedMeter:                     timer.TimedMeter{Duration: disconnectedRestartTimeout},
 		restarter:                          restarter,
 		apricotPhase0Time:                  apricotPhase0Time,
+		compressionEnabled:                 compressionEnabled,
+		inboundThrottler:                   NewInboundThrottler(inboundThrottlerConfig, vdrs),
+		signedIPs:                          make(map[ids.ShortID]*SignedIP),
+		allowlistConfig:                    allowlistConfig,
+		rejectedIPs:                        make(map[ids.ShortID]utils.IPDesc),
+		trackedSubnets:                     ids.Set{},
+		subnetVdrs:                         subnetVdrs,
+		b:                                  Builder{Codec: NewCodec(compression.NewGzipCompressor(int64(maxMessageSize)))},
 	}
 
+	netw.trackedSubnets.Union(trackedSubnets)
+	netw.trackedSubnets.Add(constants.PrimaryNetworkID)
 	if err := netw.initialize(registerer); err != nil {
 		log.Warn("initializing network metrics failed with: %s", err)
 	}
+	reputation, err := newReputation(reputationConfig, banDB)
+	if err != nil {
+		log.Error("bans will only be kept in memory as loading them failed with: %s", err)
+		reputation, _ = newReputation(reputationConfig, nil)
+	}
+	netw.reputation = reputation
+	netw.numBanned.Set(float64(len(reputation.Bans())))
+	if stakingCert != nil && len(stakingCert.Certificate) > 0 {
+		if signer, ok := stakingCert.PrivateKey.(crypto.Signer); ok {
+			netw.stakingCert = stakingCert.Certificate[0]
+			netw.stakingSigner = signer
+		} else {
+			log.Warn("peer lists will be sent without signatures as the staking key of type %T can't sign", stakingCert.PrivateKey)
+		}
+	}
 	netw.executor.Initialize()
 	go netw.executor.Dispatch()
 	netw.heartbeat()
@@ -646,6 +764,187 @@ func (n *network) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint3
 	}
 }
 
+// GetStateSummary implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) {
+	msg, err := n.b.GetStateSummary(chainID, requestID, uint64(deadline.Sub(n.clock.Time())))
+	n.log.AssertNoError(err)
+
+	for _, peerElement := range n.getPeers(validatorIDs) {
+		peer := peerElement.peer
+		vID := peerElement.id
+		if peer == nil || !peer.connected.GetValue() || !peer.Send(msg) {
+			n.log.Debug("failed to send GetStateSummary(%s, %s, %d)",
+				vID,
+				chainID,
+				requestID)
+			n.executor.Add(func() { n.router.GetStateSummaryFailed(vID, chainID, requestID) })
+			n.getStateSummary.numFailed.Inc()
+		} else {
+			n.getStateSummary.numSent.Inc()
+		}
+	}
+}
+
+// StateSummary implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	msg, err := n.b.StateSummary(chainID, requestID, summary)
+	if err != nil {
+		n.log.Error("failed to build StateSummary(%s, %d) because of summary of size %d",
+			chainID,
+			requestID,
+			len(summary))
+		return
+	}
+
+	peer := n.getPeer(validatorID)
+	if peer == nil || !peer.connected.GetValue() || !peer.Send(msg) {
+		n.log.Debug("failed to send StateSummary(%s, %s, %d)",
+			validatorID,
+			chainID,
+			requestID)
+		n.stateSummary.numFailed.Inc()
+	} else {
+		n.stateSummary.numSent.Inc()
+	}
+}
+
+// GetStateChunk implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, summaryID ids.ID, index uint32) {
+	msg, err := n.b.GetStateChunk(chainID, requestID, uint64(deadline.Sub(n.clock.Time())), summaryID, index)
+	n.log.AssertNoError(err)
+
+	peer := n.getPeer(validatorID)
+	if peer == nil || !peer.connected.GetValue() || !peer.Send(msg) {
+		n.log.Debug("failed to send GetStateChunk(%s, %s, %d, %s, %d)",
+			validatorID,
+			chainID,
+			requestID,
+			summaryID,
+			index)
+		n.executor.Add(func() { n.router.GetStateChunkFailed(validatorID, chainID, requestID) })
+		n.getStateChunk.numFailed.Inc()
+	} else {
+		n.getStateChunk.numSent.Inc()
+	}
+}
+
+// StateChunk implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	msg, err := n.b.StateChunk(chainID, requestID, chunk)
+	if err != nil {
+		n.log.Error("failed to build StateChunk(%s, %d) because of chunk of size %d",
+			chainID,
+			requestID,
+			len(chunk))
+		return
+	}
+
+	peer := n.getPeer(validatorID)
+	if peer == nil || !peer.connected.GetValue() || !peer.Send(msg) {
+		n.log.Debug("failed to send StateChunk(%s, %s, %d)",
+			validatorID,
+			chainID,
+			requestID)
+		n.stateChunk.numFailed.Inc()
+	} else {
+		n.stateChunk.numSent.Inc()
+	}
+}
+
+// AppRequest implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) AppRequest(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, appRequestBytes []byte) {
+	msg, err := n.b.AppRequest(chainID, requestID, uint64(deadline.Sub(n.clock.Time())), appRequestBytes)
+	if err != nil {
+		n.log.Error("failed to build AppRequest(%s, %d) because of message of size %d",
+			chainID,
+			requestID,
+			len(appRequestBytes))
+		for _, validatorID := range validatorIDs.List() {
+			vID := validatorID
+			n.executor.Add(func() { n.router.AppRequestFailed(vID, chainID, requestID) })
+		}
+		n.appRequest.numFailed.Add(float64(validatorIDs.Len()))
+		return
+	}
+
+	for _, peerElement := range n.getPeers(validatorIDs) {
+		peer := peerElement.peer
+		vID := peerElement.id
+		if peer == nil || !peer.connected.GetValue() || !peer.Send(msg) {
+			n.log.Debug("failed to send AppRequest(%s, %s, %d)",
+				vID,
+				chainID,
+				requestID)
+			n.executor.Add(func() { n.router.AppRequestFailed(vID, chainID, requestID) })
+			n.appRequest.numFailed.Inc()
+		} else {
+			n.appRequest.numSent.Inc()
+		}
+	}
+}
+
+// AppResponse implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) AppResponse(validatorID ids.ShortID, chainID ids.ID, requestID uint32, appResponseBytes []byte) {
+	msg, err := n.b.AppResponse(chainID, requestID, appResponseBytes)
+	if err != nil {
+		n.log.Error("failed to build AppResponse(%s, %d) because of message of size %d",
+			chainID,
+			requestID,
+			len(appResponseBytes))
+		return
+	}
+
+	peer := n.getPeer(validatorID)
+	if peer == nil || !peer.connected.GetValue() || !peer.Send(msg) {
+		n.log.Debug("failed to send AppResponse(%s, %s, %d)",
+			validatorID,
+			chainID,
+			requestID)
+		n.appResponse.numFailed.Inc()
+	} else {
+		n.appResponse.numSent.Inc()
+	}
+}
+
+// AppGossip implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) AppGossip(chainID ids.ID, appGossipBytes []byte) {
+	if err := n.gossipAppMsg(chainID, appGossipBytes); err != nil {
+		n.log.Debug("failed to AppGossip(%s): %s", chainID, err)
+		n.log.Verbo("message:\n%s", formatting.DumpBytes{Bytes: appGossipBytes})
+	}
+}
+
+// AppGossipSpecific implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) AppGossipSpecific(validatorIDs ids.ShortSet, chainID ids.ID, appGossipBytes []byte) {
+	msg, err := n.b.AppGossip(chainID, appGossipBytes)
+	if err != nil {
+		n.log.Error("failed to build AppGossip(%s) because of message of size %d",
+			chainID,
+			len(appGossipBytes))
+		return
+	}
+
+	for _, peerElement := range n.getPeers(validatorIDs) {
+		peer := peerElement.peer
+		if peer == nil || !peer.connected.GetValue() || !peer.Send(msg) {
+			n.log.Debug("failed to send AppGossip(%s, %s)",
+				peerElement.id,
+				chainID)
+			n.appGossip.numFailed.Inc()
+		} else {
+			n.appGossip.numSent.Inc()
+		}
+	}
+}
+
 // Gossip attempts to gossip the container to the network
 // assumes the stateLock is not held.
 func (n *network) Gossip(chainID, containerID ids.ID, container []byte) {
@@ -756,14 +1055,15 @@ func (n *network) Peers(nodeIDs []ids.ShortID) []PeerID {
 	if len(nodeIDs) == 0 {
 		peers = make([]PeerID, 0, len(n.peers))
 		for _, peer := range n.peers {
-			if peer.connected.GetValue() {
+			if peer.connected.GetValue() && n.visible(peer.id) {
 				peers = append(peers, PeerID{
-					IP:           peer.conn.RemoteAddr().String(),
-					PublicIP:     peer.getIP().String(),
-					ID:           peer.id.PrefixedString(constants.NodeIDPrefix),
-					Version:      peer.versionStr.GetValue().(string),
-					LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
-					LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
+					IP:             peer.conn.RemoteAddr().String(),
+					PublicIP:       peer.getIP().String(),
+					ID:             peer.id.PrefixedString(constants.NodeIDPrefix),
+					Version:        peer.versionStr.GetValue().(string),
+					LastSent:       time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
+					LastReceived:   time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
+					TrackedSubnets: peer.trackedSubnetIDs(),
 				})
 			}
 		}
@@ -771,14 +1071,15 @@ func (n *network) Peers(nodeIDs []ids.ShortID) []PeerID {
 		peers = make([]PeerID, 0, len(nodeIDs))
 		for _, nodeID := range nodeIDs {
 			peer, ok := n.peers[nodeID]
-			if ok && peer.connected.GetValue() {
+			if ok && peer.connected.GetValue() && n.visible(nodeID) {
 				peers = append(peers, PeerID{
-					IP:           peer.conn.RemoteAddr().String(),
-					PublicIP:     peer.getIP().String(),
-					ID:           peer.id.PrefixedString(constants.NodeIDPrefix),
-					Version:      peer.versionStr.GetValue().(string),
-					LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
-					LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
+					IP:             peer.conn.RemoteAddr().String(),
+					PublicIP:       peer.getIP().String(),
+					ID:             peer.id.PrefixedString(constants.NodeIDPrefix),
+					Version:        peer.versionStr.GetValue().(string),
+					LastSent:       time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
+					LastReceived:   time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
+					TrackedSubnets: peer.trackedSubnetIDs(),
 				})
 			}
 		}
@@ -833,7 +1134,7 @@ func (n *network) Track(ip utils.IPDesc) {
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
-	n.track(ip)
+	n.track(ip, ids.ShortEmpty)
 }
 
 func (n *network) IP() utils.IPDesc {
@@ -872,8 +1173,42 @@ func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte)
 	return nil
 }
 
+// assumes the stateLock is not held.
+func (n *network) gossipAppMsg(chainID ids.ID, appGossipBytes []byte) error {
+	msg, err := n.b.AppGossip(chainID, appGossipBytes)
+	if err != nil {
+		return fmt.Errorf("attempted to pack too large of an AppGossip message.\nMessage length: %d", len(appGossipBytes))
+	}
+
+	allPeers := n.getAllPeers()
+
+	numToGossip := n.gossipSize
+	if numToGossip > len(allPeers) {
+		numToGossip = len(allPeers)
+	}
+
+	s := sampler.NewUniform()
+	if err := s.Initialize(uint64(len(allPeers))); err != nil {
+		return err
+	}
+	indices, err := s.Sample(numToGossip)
+	if err != nil {
+		return err
+	}
+	for _, index := range indices {
+		if allPeers[int(index)].Send(msg) {
+			n.appGossip.numSent.Inc()
+		} else {
+			n.appGossip.numFailed.Inc()
+		}
+	}
+	return nil
+}
+
+// track starts connecting to [ip]. [nodeID] is the ID of the node at [ip], or
+// ids.ShortEmpty if it isn't known.
 // assumes the stateLock is held.
-func (n *network) track(ip utils.IPDesc) {
+func (n *network) track(ip utils.IPDesc, nodeID ids.ShortID) {
 	if n.closed.GetValue() {
 		return
 	}
@@ -890,7 +1225,7 @@ func (n *network) track(ip utils.IPDesc) {
 	}
 	n.disconnectedIPs[str] = struct{}{}
 
-	go n.connectTo(ip)
+	go n.connectTo(ip, nodeID)
 }
 
 // assumes the stateLock is not held. Only returns after the network is closed.
@@ -908,95 +1243,88 @@ func (n *network) gossip() {
 			continue
 		}
 
-		ips := make([]utils.IPDesc, 0, len(allPeers))
-		for _, peer := range allPeers {
-			ip := peer.getIP()
-			if peer.connected.GetValue() &&
-				!ip.IsZero() &&
-				n.vdrs.Contains(peer.id) {
-				peerVersion := peer.versionStruct.GetValue().(version.Version)
-				if !peerVersion.Before(minimumUnmaskedVersion) || time.Since(n.apricotPhase0Time) < 0 {
-					ips = append(ips, ip)
-				}
-			}
-		}
-
-		if len(ips) == 0 {
-			n.log.Debug("skipping validator gossiping as no public validators are connected")
-			continue
-		}
-		msg, err := n.b.PeerList(ips)
-		if err != nil {
-			n.log.Error("failed to build peer list to gossip: %s. len(ips): %d",
-				err,
-				len(ips))
-			continue
-		}
-
+		subnetStakers := make([]*peer, 0, len(allPeers))
 		stakers := make([]*peer, 0, len(allPeers))
 		nonStakers := make([]*peer, 0, len(allPeers))
 		for _, peer := range allPeers {
-			if n.vdrs.Contains(peer.id) {
+			switch {
+			case !n.isVisible(peer.id):
+			case n.subnetValidator(peer.id):
+				subnetStakers = append(subnetStakers, peer)
+			case n.trackedValidator(peer.id):
 				stakers = append(stakers, peer)
-			} else {
+			default:
 				nonStakers = append(nonStakers, peer)
 			}
 		}
 
+		// The validators of the subnets we track are gossiped to before the
+		// other stakers, so that they learn the IPs of each other
 		numStakersToSend := (n.peerListGossipSize + n.peerListStakerGossipFraction - 1) / n.peerListStakerGossipFraction
+		numSubnetStakersToSend := numStakersToSend
+		if len(subnetStakers) < numSubnetStakersToSend {
+			numSubnetStakersToSend = len(subnetStakers)
+		}
+		numStakersToSend -= numSubnetStakersToSend
 		if len(stakers) < numStakersToSend {
 			numStakersToSend = len(stakers)
 		}
-		numNonStakersToSend := n.peerListGossipSize - numStakersToSend
+		numNonStakersToSend := n.peerListGossipSize - numSubnetStakersToSend - numStakersToSend
 		if len(nonStakers) < numNonStakersToSend {
 			numNonStakersToSend = len(nonStakers)
 		}
 
-		s := sampler.NewUniform()
-		if err := s.Initialize(uint64(len(stakers))); err != nil {
-			n.log.Error("failed to select stakers to sample: %s. len(stakers): %d",
+		if err := gossipPeerList(subnetStakers, numSubnetStakersToSend); err != nil {
+			n.log.Error("failed to select subnet stakers to sample: %s. len(subnetStakers): %d",
 				err,
-				len(stakers))
+				len(subnetStakers))
 			continue
 		}
-		stakerIndices, err := s.Sample(numStakersToSend)
-		if err != nil {
+		if err := gossipPeerList(stakers, numStakersToSend); err != nil {
 			n.log.Error("failed to select stakers to sample: %s. len(stakers): %d",
 				err,
 				len(stakers))
 			continue
 		}
-		for _, index := range stakerIndices {
-			stakers[int(index)].Send(msg)
-		}
-
-		if err := s.Initialize(uint64(len(nonStakers))); err != nil {
-			n.log.Error("failed to select non-stakers to sample: %s. len(nonStakers): %d",
-				err,
-				len(nonStakers))
-			continue
-		}
-		nonStakerIndices, err := s.Sample(numNonStakersToSend)
-		if err != nil {
+		if err := gossipPeerList(nonStakers, numNonStakersToSend); err != nil {
 			n.log.Error("failed to select non-stakers to sample: %s. len(nonStakers): %d",
 				err,
 				len(nonStakers))
 			continue
 		}
-		for _, index := range nonStakerIndices {
-			nonStakers[int(index)].Send(msg)
-		}
 	}
 }
 
+// gossipPeerList sends peer lists to [numToSend] peers sampled from [peers]
+// assumes the stateLock is not held.
+func gossipPeerList(peers []*peer, numToSend int) error {
+	s := sampler.NewUniform()
+	if err := s.Initialize(uint64(len(peers))); err != nil {
+		return err
+	}
+	indices, err := s.Sample(numToSend)
+	if err != nil {
+		return err
+	}
+	for _, index := range indices {
+		peers[int(index)].GossipPeerList()
+	}
+	return nil
+}
+
 // assumes the stateLock is not held. Only returns if the ip is connected to or
 // the network is closed
-func (n *network) connectTo(ip utils.IPDesc) {
+func (n *network) connectTo(ip utils.IPDesc, nodeID ids.ShortID) {
 	str := ip.String()
 	n.stateLock.RLock()
 	delay := n.retryDelay[str]
 	n.stateLock.RUnlock()
 
+	maxDelay := n.maxReconnectDelay
+	if n.subnetValidator(nodeID) {
+		maxDelay /= subnetValidatorReconnectFactor
+	}
+
 	for {
 		time.Sleep(delay)
 
@@ -1008,9 +1336,9 @@ func (n *network) connectTo(ip utils.IPDesc) {
 		// attempts to a node that previously shut down. This doesn't require
 		// cryptographically secure random number generation.
 		delay = time.Duration(float64(delay) * (1 + rand.Float64())) // #nosec G404
-		if delay > n.maxReconnectDelay {
-			// set the timeout to [.75, 1) * maxReconnectDelay
-			delay = time.Duration(float64(n.maxReconnectDelay) * (3 + rand.Float64()) / 4) // #nosec G404
+		if delay > maxDelay {
+			// set the timeout to [.75, 1) * maxDelay
+			delay = time.Duration(float64(maxDelay) * (3 + rand.Float64()) / 4) // #nosec G404
 		}
 
 		n.stateLock.Lock()
@@ -1091,7 +1419,7 @@ func (n *network) upgrade(p *peer, upgrader Upgrader) error {
 		return err
 	}
 
-	p.sender = make(chan []byte, n.sendQueueSize)
+	p.sendQueue = newSendQueue(int(n.sendQueueSize))
 	p.id = id
 	p.conn = conn
 
@@ -1134,6 +1462,17 @@ func (n *network) tryAddPeer(p *peer) error {
 		return errPeerIsMyself
 	}
 
+	if !n.canConnect(p.id) {
+		if !ip.IsZero() {
+			n.rejectedIPs[p.id] = ip
+		}
+		return fmt.Errorf("%w: %s at %s", errNotAllowed, p.id.PrefixedString(constants.NodeIDPrefix), ip)
+	}
+
+	if n.reputation.Banned(p.id) {
+		return fmt.Errorf("%w: %s at %s", errBanned, p.id.PrefixedString(constants.NodeIDPrefix), ip)
+	}
+
 	// If I am already connected to this peer, then I should close this new
 	// connection.
 	if _, ok := n.peers[p.id]; ok {
@@ -1152,15 +1491,20 @@ func (n *network) tryAddPeer(p *peer) error {
 }
 
 // assumes the stateLock is not held. Returns the ips of connections that have
-// valid IPs that are marked as validators.
-func (n *network) validatorIPs() []utils.IPDesc {
+// valid IPs that are marked as validators of a subnet that both we and [p]
+// track.
+func (n *network) validatorIPs(p *peer) []utils.IPDesc {
 	n.stateLock.RLock()
 	defer n.stateLock.RUnlock()
 
 	ips := make([]utils.IPDesc, 0, len(n.peers))
 	for _, peer := range n.peers {
 		ip := peer.getIP()
-		if peer.connected.GetValue() && !ip.IsZero() && n.vdrs.Contains(peer.id) {
+		if signedIP, ok := n.signedIPs[peer.id]; ok {
+			// The IP the validator signed is preferred over the IP it claimed
+			ip = signedIP.IP
+		}
+		if peer.connected.GetValue() && !ip.IsZero() && n.sharedValidator(p, peer.id) && n.visible(peer.id) {
 			peerVersion := peer.versionStruct.GetValue().(version.Version)
 			if !peerVersion.Before(minimumUnmaskedVersion) || time.Since(n.apricotPhase0Time) < 0 {
 				ips = append(ips, ip)
@@ -1170,6 +1514,121 @@ func (n *network) validatorIPs() []utils.IPDesc {
 	return ips
 }
 
+// signedValidatorIPs returns the latest signed IPs of the connected validators
+// of the subnets that both we and [p] track, including our own if we're one of
+// them. Signed IPs of nodes that no longer validate a subnet we track are
+// discarded.
+// assumes the stateLock is not held.
+func (n *network) signedValidatorIPs(p *peer) []*SignedIP {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	signedIPs := make([]*SignedIP, 0, len(n.signedIPs)+1)
+	for nodeID, signedIP := range n.signedIPs {
+		if !n.trackedValidator(nodeID) {
+			delete(n.signedIPs, nodeID)
+			continue
+		}
+		if peer, ok := n.peers[nodeID]; ok && peer.connected.GetValue() && n.visible(nodeID) && n.sharedValidator(p, nodeID) {
+			signedIPs = append(signedIPs, signedIP)
+		}
+	}
+
+	if len(signedIPs) >= maxSignedPeerListSize {
+		// Peers drop larger peer lists, so a random subset is sent, leaving
+		// room for our own IP
+		rand.Shuffle(len(signedIPs), func(i, j int) { signedIPs[i], signedIPs[j] = signedIPs[j], signedIPs[i] }) // #nosec G404
+		signedIPs = signedIPs[:maxSignedPeerListSize-1]
+	}
+
+	if n.stakingSigner == nil || !n.sharedValidator(p, n.id) {
+		return signedIPs
+	}
+	mySignedIP, err := n.getMySignedIP()
+	if err != nil {
+		n.log.Error("failed to sign our IP: %s", err)
+		return signedIPs
+	}
+	return append(signedIPs, mySignedIP)
+}
+
+// getMySignedIP returns our current IP, signed with our staking key.
+// assumes the stateLock is held.
+func (n *network) getMySignedIP() (*SignedIP, error) {
+	ip := n.ip.IP()
+	if n.mySignedIP != nil && n.mySignedIP.IP.Equal(ip) {
+		return n.mySignedIP, nil
+	}
+
+	// The timestamp of a new IP must be after the timestamp of the old one, so
+	// that peers replace the old one
+	timestamp := n.clock.Unix()
+	if n.mySignedIP != nil && timestamp <= n.mySignedIP.Timestamp {
+		timestamp = n.mySignedIP.Timestamp + 1
+	}
+	mySignedIP, err := newSignedIP(n.stakingCert, n.stakingSigner, ip, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	n.mySignedIP = mySignedIP
+	return mySignedIP, nil
+}
+
+// trackSignedIP verifies [signedIP] and starts connecting to it. If it was
+// signed by a validator of a subnet we track, it's kept unless a later IP
+// signed by the validator is already known.
+// assumes the stateLock is not held.
+func (n *network) trackSignedIP(signedIP *SignedIP) error {
+	// Verifying the signature is expensive, so IPs that would be dropped even
+	// if they were signed correctly are dropped first. Until the signature is
+	// verified, the ID that the IP claims to be signed by isn't trusted.
+	claimedID := certToID(signedIP.Cert)
+	if maxTimestamp := n.clock.Time().Add(n.maxClockDifference); signedIP.Timestamp > uint64(maxTimestamp.Unix()) {
+		return fmt.Errorf("%w: signed by %s at %d", errSignedIPFromFuture, claimedID, signedIP.Timestamp)
+	}
+	if !n.newerSignedIP(claimedID, signedIP) {
+		return nil
+	}
+
+	nodeID, err := signedIP.Verify()
+	if err != nil {
+		return err
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	// The IP may have been replaced while its signature was being verified
+	if latest, ok := n.signedIPs[nodeID]; ok && latest.Timestamp >= signedIP.Timestamp {
+		return nil
+	}
+	n.signedIPs[nodeID] = signedIP
+
+	ip := signedIP.IP
+	if n.canConnect(nodeID) &&
+		!ip.Equal(n.ip.IP()) &&
+		!ip.IsZero() &&
+		(n.allowPrivateIPs || !ip.IsPrivate()) {
+		n.track(ip, nodeID)
+	}
+	return nil
+}
+
+// newerSignedIP returns true if [signedIP] is the IP of a tracked validator,
+// other than us, and is newer than the IP we have for it. Peers only send the
+// IPs of validators.
+// assumes the stateLock is not held.
+func (n *network) newerSignedIP(nodeID ids.ShortID, signedIP *SignedIP) bool {
+	n.stateLock.RLock()
+	defer n.stateLock.RUnlock()
+
+	if nodeID == n.id || !n.trackedValidator(nodeID) {
+		return false
+	}
+	latest, ok := n.signedIPs[nodeID]
+	return !ok || latest.Timestamp < signedIP.Timestamp
+}
+
 // should only be called after the peer is marked as connected. Should not be
 // called after disconnected is called with this peer.
 // assumes the stateLock is not held.
@@ -1233,7 +1692,7 @@ func (n *network) disconnected(p *peer) {
 		delete(n.disconnectedIPs, str)
 		delete(n.connectedIPs, str)
 
-		n.track(ip)
+		n.track(ip, p.id)
 	}
 
 	if p.connected.GetValue() {
@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/version"
@@ -216,6 +217,14 @@ func TestNewDefaultNetwork(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net)
 
@@ -330,6 +339,375 @@ func TestEstablishConnection(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
+	)
+	assert.NotNil(t, net0)
+
+	net1 := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		id1,
+		ip1,
+		networkID,
+		appVersion,
+		versionParser,
+		listener1,
+		caller1,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		vdrs,
+		handler1,
+		time.Duration(0),
+		0,
+		nil,
+		false,
+		0,
+		0,
+		time.Now(),
+		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
+	)
+	assert.NotNil(t, net1)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	net0.Track(ip1.IP())
+
+	wg0.Wait()
+	wg1.Wait()
+
+	err := net0.Close()
+	assert.NoError(t, err)
+
+	err = net1.Close()
+	assert.NoError(t, err)
+}
+
+func TestEstablishConnectionWithSubnets(t *testing.T) {
+	log := logging.NoLog{}
+	networkID := uint32(0)
+	appVersion := version.NewDefaultVersion(constants.PlatformName, 1, 2, 1)
+	versionParser := version.NewDefaultParser()
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+
+	serverUpgrader := NewIPUpgrader()
+	clientUpgrader := NewIPUpgrader()
+
+	vdrs := validators.NewSet()
+
+	// Both nodes track the subnet, so each is told that the other tracks it
+	subnetID := ids.GenerateTestID()
+	trackedSubnets := ids.Set{}
+	trackedSubnets.Add(subnetID)
+
+	var (
+		wg0 sync.WaitGroup
+		wg1 sync.WaitGroup
+	)
+	wg0.Add(1)
+	wg1.Add(1)
+
+	handler0 := &testHandler{
+		connected: func(id ids.ShortID) {
+			if id != id0 {
+				wg0.Done()
+			}
+		},
+	}
+
+	handler1 := &testHandler{
+		connected: func(id ids.ShortID) {
+			if id != id1 {
+				wg1.Done()
+			}
+		},
+	}
+
+	net0 := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		id0,
+		ip0,
+		networkID,
+		appVersion,
+		versionParser,
+		listener0,
+		caller0,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		vdrs,
+		handler0,
+		time.Duration(0),
+		0,
+		nil,
+		false,
+		0,
+		0,
+		time.Now(),
+		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		trackedSubnets,
+		nil,
+		ReputationConfig{},
+		nil,
+	)
+	assert.NotNil(t, net0)
+
+	net1 := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		id1,
+		ip1,
+		networkID,
+		appVersion,
+		versionParser,
+		listener1,
+		caller1,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		vdrs,
+		handler1,
+		time.Duration(0),
+		0,
+		nil,
+		false,
+		0,
+		0,
+		time.Now(),
+		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		trackedSubnets,
+		nil,
+		ReputationConfig{},
+		nil,
+	)
+	assert.NotNil(t, net1)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	net0.Track(ip1.IP())
+
+	wg0.Wait()
+	wg1.Wait()
+
+	for _, n := range []Network{net0, net1} {
+		peers := n.Peers(nil)
+		assert.Len(t, peers, 1)
+		assert.Equal(t, []string{subnetID.String()}, peers[0].TrackedSubnets)
+	}
+
+	err := net0.Close()
+	assert.NoError(t, err)
+
+	err = net1.Close()
+	assert.NoError(t, err)
+}
+
+func TestEstablishConnectionCompression(t *testing.T) {
+	// Messages are only compressed if both nodes enable compression
+	compress0, compress1 := connectWithCompression(t, true, true)
+	assert.True(t, compress0)
+	assert.True(t, compress1)
+
+	compress0, compress1 = connectWithCompression(t, true, false)
+	assert.False(t, compress0)
+	assert.False(t, compress1)
+}
+
+// connectWithCompression connects two nodes, the first of which enables
+// compression iff [compression0] and the second iff [compression1]. Returns
+// whether each node sends compressed messages to the other.
+func connectWithCompression(t *testing.T, compression0, compression1 bool) (bool, bool) {
+	log := logging.NoLog{}
+	networkID := uint32(0)
+	appVersion := version.NewDefaultVersion(constants.PlatformName, 1, 2, 1)
+	versionParser := version.NewDefaultParser()
+
+	ip0 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		0,
+	)
+	id0 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip0.IP().String())))
+	ip1 := utils.NewDynamicIPDesc(
+		net.IPv6loopback,
+		1,
+	)
+	id1 := ids.ShortID(hashing.ComputeHash160Array([]byte(ip1.IP().String())))
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.IP().String()] = listener1
+	caller1.outbounds[ip0.IP().String()] = listener0
+
+	serverUpgrader := NewIPUpgrader()
+	clientUpgrader := NewIPUpgrader()
+
+	vdrs := validators.NewSet()
+
+	var (
+		wg0 sync.WaitGroup
+		wg1 sync.WaitGroup
+	)
+	wg0.Add(1)
+	wg1.Add(1)
+
+	handler0 := &testHandler{
+		connected: func(id ids.ShortID) {
+			if id != id0 {
+				wg0.Done()
+			}
+		},
+	}
+
+	handler1 := &testHandler{
+		connected: func(id ids.ShortID) {
+			if id != id1 {
+				wg1.Done()
+			}
+		},
+	}
+
+	net0 := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		id0,
+		ip0,
+		networkID,
+		appVersion,
+		versionParser,
+		listener0,
+		caller0,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		vdrs,
+		handler0,
+		time.Duration(0),
+		0,
+		nil,
+		false,
+		0,
+		0,
+		time.Now(),
+		defaultSendQueueSize,
+		compression0,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		ids.Set{},
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -356,6 +734,14 @@ func TestEstablishConnection(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		compression1,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		ids.Set{},
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -373,11 +759,24 @@ func TestEstablishConnection(t *testing.T) {
 	wg0.Wait()
 	wg1.Wait()
 
+	compress := func(n Network, peerID ids.ShortID) bool {
+		n.(*network).stateLock.RLock()
+		defer n.(*network).stateLock.RUnlock()
+
+		p, ok := n.(*network).peers[peerID]
+		assert.True(t, ok)
+		return p.compress.GetValue()
+	}
+	compress0 := compress(net0, id1)
+	compress1 := compress(net1, id0)
+
 	err := net0.Close()
 	assert.NoError(t, err)
 
 	err = net1.Close()
 	assert.NoError(t, err)
+
+	return compress0, compress1
 }
 
 func TestDoubleTrack(t *testing.T) {
@@ -482,6 +881,14 @@ func TestDoubleTrack(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -508,6 +915,14 @@ func TestDoubleTrack(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -635,6 +1050,14 @@ func TestDoubleClose(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -661,6 +1084,14 @@ func TestDoubleClose(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -793,6 +1224,14 @@ func TestTrackConnected(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -819,6 +1258,14 @@ func TestTrackConnected(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -925,6 +1372,14 @@ func TestTrackConnectedRace(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -951,6 +1406,14 @@ func TestTrackConnectedRace(t *testing.T) {
 		0,
 		time.Now(),
 		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		nil,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
 	)
 	assert.NotNil(t, net1)
 
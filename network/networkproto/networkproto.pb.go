@@ -0,0 +1,2108 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: networkproto.proto
+
+package networkproto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Message is a p2p message. Exactly one of its fields is set.
+type Message struct {
+	// Types that are valid to be assigned to Message:
+	//	*Message_GetVersion
+	//	*Message_Version_
+	//	*Message_GetPeerList
+	//	*Message_PeerList_
+	//	*Message_Ping
+	//	*Message_Pong
+	//	*Message_GetAcceptedFrontier
+	//	*Message_AcceptedFrontier_
+	//	*Message_GetAccepted
+	//	*Message_Accepted_
+	//	*Message_GetAncestors
+	//	*Message_MultiPut
+	//	*Message_Get
+	//	*Message_Put
+	//	*Message_PushQuery
+	//	*Message_PullQuery
+	//	*Message_Chits
+	//	*Message_GetStateSummary
+	//	*Message_StateSummary_
+	//	*Message_GetStateChunk
+	//	*Message_StateChunk_
+	//	*Message_AppRequest
+	//	*Message_AppResponse
+	//	*Message_AppGossip
+	//	*Message_SignedPeerList
+	//	*Message_VersionWithSubnets
+	Message              isMessage_Message `protobuf_oneof:"message"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{0}
+}
+
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Message.Unmarshal(m, b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+}
+func (m *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(m, src)
+}
+func (m *Message) XXX_Size() int {
+	return xxx_messageInfo_Message.Size(m)
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+type isMessage_Message interface {
+	isMessage_Message()
+}
+
+type Message_GetVersion struct {
+	GetVersion *GetVersion `protobuf:"bytes,1,opt,name=getVersion,proto3,oneof"`
+}
+
+type Message_Version_ struct {
+	Version_ *Version `protobuf:"bytes,2,opt,name=version,proto3,oneof"`
+}
+
+type Message_GetPeerList struct {
+	GetPeerList *GetPeerList `protobuf:"bytes,3,opt,name=getPeerList,proto3,oneof"`
+}
+
+type Message_PeerList_ struct {
+	PeerList_ *PeerList `protobuf:"bytes,4,opt,name=peerList,proto3,oneof"`
+}
+
+type Message_Ping struct {
+	Ping *Ping `protobuf:"bytes,5,opt,name=ping,proto3,oneof"`
+}
+
+type Message_Pong struct {
+	Pong *Pong `protobuf:"bytes,6,opt,name=pong,proto3,oneof"`
+}
+
+type Message_GetAcceptedFrontier struct {
+	GetAcceptedFrontier *GetAcceptedFrontier `protobuf:"bytes,7,opt,name=getAcceptedFrontier,proto3,oneof"`
+}
+
+type Message_AcceptedFrontier_ struct {
+	AcceptedFrontier_ *AcceptedFrontier `protobuf:"bytes,8,opt,name=acceptedFrontier,proto3,oneof"`
+}
+
+type Message_GetAccepted struct {
+	GetAccepted *GetAccepted `protobuf:"bytes,9,opt,name=getAccepted,proto3,oneof"`
+}
+
+type Message_Accepted_ struct {
+	Accepted_ *Accepted `protobuf:"bytes,10,opt,name=accepted,proto3,oneof"`
+}
+
+type Message_GetAncestors struct {
+	GetAncestors *GetAncestors `protobuf:"bytes,11,opt,name=getAncestors,proto3,oneof"`
+}
+
+type Message_MultiPut struct {
+	MultiPut *MultiPut `protobuf:"bytes,12,opt,name=multiPut,proto3,oneof"`
+}
+
+type Message_Get struct {
+	Get *Get `protobuf:"bytes,13,opt,name=get,proto3,oneof"`
+}
+
+type Message_Put struct {
+	Put *Put `protobuf:"bytes,14,opt,name=put,proto3,oneof"`
+}
+
+type Message_PushQuery struct {
+	PushQuery *PushQuery `protobuf:"bytes,15,opt,name=pushQuery,proto3,oneof"`
+}
+
+type Message_PullQuery struct {
+	PullQuery *PullQuery `protobuf:"bytes,16,opt,name=pullQuery,proto3,oneof"`
+}
+
+type Message_Chits struct {
+	Chits *Chits `protobuf:"bytes,17,opt,name=chits,proto3,oneof"`
+}
+
+type Message_GetStateSummary struct {
+	GetStateSummary *GetStateSummary `protobuf:"bytes,18,opt,name=getStateSummary,proto3,oneof"`
+}
+
+type Message_StateSummary_ struct {
+	StateSummary_ *StateSummary `protobuf:"bytes,19,opt,name=stateSummary,proto3,oneof"`
+}
+
+type Message_GetStateChunk struct {
+	GetStateChunk *GetStateChunk `protobuf:"bytes,20,opt,name=getStateChunk,proto3,oneof"`
+}
+
+type Message_StateChunk_ struct {
+	StateChunk_ *StateChunk `protobuf:"bytes,21,opt,name=stateChunk,proto3,oneof"`
+}
+
+type Message_AppRequest struct {
+	AppRequest *AppRequest `protobuf:"bytes,22,opt,name=appRequest,proto3,oneof"`
+}
+
+type Message_AppResponse struct {
+	AppResponse *AppResponse `protobuf:"bytes,23,opt,name=appResponse,proto3,oneof"`
+}
+
+type Message_AppGossip struct {
+	AppGossip *AppGossip `protobuf:"bytes,24,opt,name=appGossip,proto3,oneof"`
+}
+
+type Message_SignedPeerList struct {
+	SignedPeerList *SignedPeerList `protobuf:"bytes,25,opt,name=signedPeerList,proto3,oneof"`
+}
+
+type Message_VersionWithSubnets struct {
+	VersionWithSubnets *VersionWithSubnets `protobuf:"bytes,26,opt,name=versionWithSubnets,proto3,oneof"`
+}
+
+func (*Message_GetVersion) isMessage_Message() {}
+
+func (*Message_Version_) isMessage_Message() {}
+
+func (*Message_GetPeerList) isMessage_Message() {}
+
+func (*Message_PeerList_) isMessage_Message() {}
+
+func (*Message_Ping) isMessage_Message() {}
+
+func (*Message_Pong) isMessage_Message() {}
+
+func (*Message_GetAcceptedFrontier) isMessage_Message() {}
+
+func (*Message_AcceptedFrontier_) isMessage_Message() {}
+
+func (*Message_GetAccepted) isMessage_Message() {}
+
+func (*Message_Accepted_) isMessage_Message() {}
+
+func (*Message_GetAncestors) isMessage_Message() {}
+
+func (*Message_MultiPut) isMessage_Message() {}
+
+func (*Message_Get) isMessage_Message() {}
+
+func (*Message_Put) isMessage_Message() {}
+
+func (*Message_PushQuery) isMessage_Message() {}
+
+func (*Message_PullQuery) isMessage_Message() {}
+
+func (*Message_Chits) isMessage_Message() {}
+
+func (*Message_GetStateSummary) isMessage_Message() {}
+
+func (*Message_StateSummary_) isMessage_Message() {}
+
+func (*Message_GetStateChunk) isMessage_Message() {}
+
+func (*Message_StateChunk_) isMessage_Message() {}
+
+func (*Message_AppRequest) isMessage_Message() {}
+
+func (*Message_AppResponse) isMessage_Message() {}
+
+func (*Message_AppGossip) isMessage_Message() {}
+
+func (*Message_SignedPeerList) isMessage_Message() {}
+
+func (*Message_VersionWithSubnets) isMessage_Message() {}
+
+func (m *Message) GetMessage() isMessage_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *Message) GetGetVersion() *GetVersion {
+	if x, ok := m.GetMessage().(*Message_GetVersion); ok {
+		return x.GetVersion
+	}
+	return nil
+}
+
+func (m *Message) GetVersion_() *Version {
+	if x, ok := m.GetMessage().(*Message_Version_); ok {
+		return x.Version_
+	}
+	return nil
+}
+
+func (m *Message) GetGetPeerList() *GetPeerList {
+	if x, ok := m.GetMessage().(*Message_GetPeerList); ok {
+		return x.GetPeerList
+	}
+	return nil
+}
+
+func (m *Message) GetPeerList_() *PeerList {
+	if x, ok := m.GetMessage().(*Message_PeerList_); ok {
+		return x.PeerList_
+	}
+	return nil
+}
+
+func (m *Message) GetPing() *Ping {
+	if x, ok := m.GetMessage().(*Message_Ping); ok {
+		return x.Ping
+	}
+	return nil
+}
+
+func (m *Message) GetPong() *Pong {
+	if x, ok := m.GetMessage().(*Message_Pong); ok {
+		return x.Pong
+	}
+	return nil
+}
+
+func (m *Message) GetGetAcceptedFrontier() *GetAcceptedFrontier {
+	if x, ok := m.GetMessage().(*Message_GetAcceptedFrontier); ok {
+		return x.GetAcceptedFrontier
+	}
+	return nil
+}
+
+func (m *Message) GetAcceptedFrontier_() *AcceptedFrontier {
+	if x, ok := m.GetMessage().(*Message_AcceptedFrontier_); ok {
+		return x.AcceptedFrontier_
+	}
+	return nil
+}
+
+func (m *Message) GetGetAccepted() *GetAccepted {
+	if x, ok := m.GetMessage().(*Message_GetAccepted); ok {
+		return x.GetAccepted
+	}
+	return nil
+}
+
+func (m *Message) GetAccepted_() *Accepted {
+	if x, ok := m.GetMessage().(*Message_Accepted_); ok {
+		return x.Accepted_
+	}
+	return nil
+}
+
+func (m *Message) GetGetAncestors() *GetAncestors {
+	if x, ok := m.GetMessage().(*Message_GetAncestors); ok {
+		return x.GetAncestors
+	}
+	return nil
+}
+
+func (m *Message) GetMultiPut() *MultiPut {
+	if x, ok := m.GetMessage().(*Message_MultiPut); ok {
+		return x.MultiPut
+	}
+	return nil
+}
+
+func (m *Message) GetGet() *Get {
+	if x, ok := m.GetMessage().(*Message_Get); ok {
+		return x.Get
+	}
+	return nil
+}
+
+func (m *Message) GetPut() *Put {
+	if x, ok := m.GetMessage().(*Message_Put); ok {
+		return x.Put
+	}
+	return nil
+}
+
+func (m *Message) GetPushQuery() *PushQuery {
+	if x, ok := m.GetMessage().(*Message_PushQuery); ok {
+		return x.PushQuery
+	}
+	return nil
+}
+
+func (m *Message) GetPullQuery() *PullQuery {
+	if x, ok := m.GetMessage().(*Message_PullQuery); ok {
+		return x.PullQuery
+	}
+	return nil
+}
+
+func (m *Message) GetChits() *Chits {
+	if x, ok := m.GetMessage().(*Message_Chits); ok {
+		return x.Chits
+	}
+	return nil
+}
+
+func (m *Message) GetGetStateSummary() *GetStateSummary {
+	if x, ok := m.GetMessage().(*Message_GetStateSummary); ok {
+		return x.GetStateSummary
+	}
+	return nil
+}
+
+func (m *Message) GetStateSummary_() *StateSummary {
+	if x, ok := m.GetMessage().(*Message_StateSummary_); ok {
+		return x.StateSummary_
+	}
+	return nil
+}
+
+func (m *Message) GetGetStateChunk() *GetStateChunk {
+	if x, ok := m.GetMessage().(*Message_GetStateChunk); ok {
+		return x.GetStateChunk
+	}
+	return nil
+}
+
+func (m *Message) GetStateChunk_() *StateChunk {
+	if x, ok := m.GetMessage().(*Message_StateChunk_); ok {
+		return x.StateChunk_
+	}
+	return nil
+}
+
+func (m *Message) GetAppRequest() *AppRequest {
+	if x, ok := m.GetMessage().(*Message_AppRequest); ok {
+		return x.AppRequest
+	}
+	return nil
+}
+
+func (m *Message) GetAppResponse() *AppResponse {
+	if x, ok := m.GetMessage().(*Message_AppResponse); ok {
+		return x.AppResponse
+	}
+	return nil
+}
+
+func (m *Message) GetAppGossip() *AppGossip {
+	if x, ok := m.GetMessage().(*Message_AppGossip); ok {
+		return x.AppGossip
+	}
+	return nil
+}
+
+func (m *Message) GetSignedPeerList() *SignedPeerList {
+	if x, ok := m.GetMessage().(*Message_SignedPeerList); ok {
+		return x.SignedPeerList
+	}
+	return nil
+}
+
+func (m *Message) GetVersionWithSubnets() *VersionWithSubnets {
+	if x, ok := m.GetMessage().(*Message_VersionWithSubnets); ok {
+		return x.VersionWithSubnets
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Message) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Message_GetVersion)(nil),
+		(*Message_Version_)(nil),
+		(*Message_GetPeerList)(nil),
+		(*Message_PeerList_)(nil),
+		(*Message_Ping)(nil),
+		(*Message_Pong)(nil),
+		(*Message_GetAcceptedFrontier)(nil),
+		(*Message_AcceptedFrontier_)(nil),
+		(*Message_GetAccepted)(nil),
+		(*Message_Accepted_)(nil),
+		(*Message_GetAncestors)(nil),
+		(*Message_MultiPut)(nil),
+		(*Message_Get)(nil),
+		(*Message_Put)(nil),
+		(*Message_PushQuery)(nil),
+		(*Message_PullQuery)(nil),
+		(*Message_Chits)(nil),
+		(*Message_GetStateSummary)(nil),
+		(*Message_StateSummary_)(nil),
+		(*Message_GetStateChunk)(nil),
+		(*Message_StateChunk_)(nil),
+		(*Message_AppRequest)(nil),
+		(*Message_AppResponse)(nil),
+		(*Message_AppGossip)(nil),
+		(*Message_SignedPeerList)(nil),
+		(*Message_VersionWithSubnets)(nil),
+	}
+}
+
+type IPDesc struct {
+	Ip                   []byte   `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port                 uint32   `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IPDesc) Reset()         { *m = IPDesc{} }
+func (m *IPDesc) String() string { return proto.CompactTextString(m) }
+func (*IPDesc) ProtoMessage()    {}
+func (*IPDesc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{1}
+}
+
+func (m *IPDesc) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IPDesc.Unmarshal(m, b)
+}
+func (m *IPDesc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IPDesc.Marshal(b, m, deterministic)
+}
+func (m *IPDesc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IPDesc.Merge(m, src)
+}
+func (m *IPDesc) XXX_Size() int {
+	return xxx_messageInfo_IPDesc.Size(m)
+}
+func (m *IPDesc) XXX_DiscardUnknown() {
+	xxx_messageInfo_IPDesc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IPDesc proto.InternalMessageInfo
+
+func (m *IPDesc) GetIp() []byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *IPDesc) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+type GetVersion struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetVersion) Reset()         { *m = GetVersion{} }
+func (m *GetVersion) String() string { return proto.CompactTextString(m) }
+func (*GetVersion) ProtoMessage()    {}
+func (*GetVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{2}
+}
+
+func (m *GetVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetVersion.Unmarshal(m, b)
+}
+func (m *GetVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetVersion.Marshal(b, m, deterministic)
+}
+func (m *GetVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetVersion.Merge(m, src)
+}
+func (m *GetVersion) XXX_Size() int {
+	return xxx_messageInfo_GetVersion.Size(m)
+}
+func (m *GetVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetVersion proto.InternalMessageInfo
+
+type Version struct {
+	NetworkID            uint32   `protobuf:"varint,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	NodeID               uint32   `protobuf:"varint,2,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	MyTime               uint64   `protobuf:"varint,3,opt,name=myTime,proto3" json:"myTime,omitempty"`
+	Ip                   *IPDesc  `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`
+	VersionStr           string   `protobuf:"bytes,5,opt,name=versionStr,proto3" json:"versionStr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Version) Reset()         { *m = Version{} }
+func (m *Version) String() string { return proto.CompactTextString(m) }
+func (*Version) ProtoMessage()    {}
+func (*Version) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{3}
+}
+
+func (m *Version) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Version.Unmarshal(m, b)
+}
+func (m *Version) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Version.Marshal(b, m, deterministic)
+}
+func (m *Version) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Version.Merge(m, src)
+}
+func (m *Version) XXX_Size() int {
+	return xxx_messageInfo_Version.Size(m)
+}
+func (m *Version) XXX_DiscardUnknown() {
+	xxx_messageInfo_Version.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Version proto.InternalMessageInfo
+
+func (m *Version) GetNetworkID() uint32 {
+	if m != nil {
+		return m.NetworkID
+	}
+	return 0
+}
+
+func (m *Version) GetNodeID() uint32 {
+	if m != nil {
+		return m.NodeID
+	}
+	return 0
+}
+
+func (m *Version) GetMyTime() uint64 {
+	if m != nil {
+		return m.MyTime
+	}
+	return 0
+}
+
+func (m *Version) GetIp() *IPDesc {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *Version) GetVersionStr() string {
+	if m != nil {
+		return m.VersionStr
+	}
+	return ""
+}
+
+type GetPeerList struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPeerList) Reset()         { *m = GetPeerList{} }
+func (m *GetPeerList) String() string { return proto.CompactTextString(m) }
+func (*GetPeerList) ProtoMessage()    {}
+func (*GetPeerList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{4}
+}
+
+func (m *GetPeerList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPeerList.Unmarshal(m, b)
+}
+func (m *GetPeerList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPeerList.Marshal(b, m, deterministic)
+}
+func (m *GetPeerList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPeerList.Merge(m, src)
+}
+func (m *GetPeerList) XXX_Size() int {
+	return xxx_messageInfo_GetPeerList.Size(m)
+}
+func (m *GetPeerList) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPeerList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPeerList proto.InternalMessageInfo
+
+type PeerList struct {
+	Peers                []*IPDesc `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PeerList) Reset()         { *m = PeerList{} }
+func (m *PeerList) String() string { return proto.CompactTextString(m) }
+func (*PeerList) ProtoMessage()    {}
+func (*PeerList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{5}
+}
+
+func (m *PeerList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerList.Unmarshal(m, b)
+}
+func (m *PeerList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerList.Marshal(b, m, deterministic)
+}
+func (m *PeerList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerList.Merge(m, src)
+}
+func (m *PeerList) XXX_Size() int {
+	return xxx_messageInfo_PeerList.Size(m)
+}
+func (m *PeerList) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerList proto.InternalMessageInfo
+
+func (m *PeerList) GetPeers() []*IPDesc {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+// SignedIP is an IP that a node claims to be reachable at, signed with the
+// node's staking key. The node's ID is derived from its certificate.
+type SignedIP struct {
+	Cert                 []byte   `protobuf:"bytes,1,opt,name=cert,proto3" json:"cert,omitempty"`
+	Ip                   *IPDesc  `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	Timestamp            uint64   `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedIP) Reset()         { *m = SignedIP{} }
+func (m *SignedIP) String() string { return proto.CompactTextString(m) }
+func (*SignedIP) ProtoMessage()    {}
+func (*SignedIP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{6}
+}
+
+func (m *SignedIP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedIP.Unmarshal(m, b)
+}
+func (m *SignedIP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedIP.Marshal(b, m, deterministic)
+}
+func (m *SignedIP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedIP.Merge(m, src)
+}
+func (m *SignedIP) XXX_Size() int {
+	return xxx_messageInfo_SignedIP.Size(m)
+}
+func (m *SignedIP) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedIP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedIP proto.InternalMessageInfo
+
+func (m *SignedIP) GetCert() []byte {
+	if m != nil {
+		return m.Cert
+	}
+	return nil
+}
+
+func (m *SignedIP) GetIp() *IPDesc {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *SignedIP) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *SignedIP) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type SignedPeerList struct {
+	Peers                []*SignedIP `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SignedPeerList) Reset()         { *m = SignedPeerList{} }
+func (m *SignedPeerList) String() string { return proto.CompactTextString(m) }
+func (*SignedPeerList) ProtoMessage()    {}
+func (*SignedPeerList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{7}
+}
+
+func (m *SignedPeerList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedPeerList.Unmarshal(m, b)
+}
+func (m *SignedPeerList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedPeerList.Marshal(b, m, deterministic)
+}
+func (m *SignedPeerList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedPeerList.Merge(m, src)
+}
+func (m *SignedPeerList) XXX_Size() int {
+	return xxx_messageInfo_SignedPeerList.Size(m)
+}
+func (m *SignedPeerList) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedPeerList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedPeerList proto.InternalMessageInfo
+
+func (m *SignedPeerList) GetPeers() []*SignedIP {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+// VersionWithSubnets is a Version that also lists the subnets a node tracks,
+// other than the primary network, which every node tracks, and whether the
+// node accepts compressed messages.
+type VersionWithSubnets struct {
+	NetworkID            uint32   `protobuf:"varint,1,opt,name=networkID,proto3" json:"networkID,omitempty"`
+	NodeID               uint32   `protobuf:"varint,2,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	MyTime               uint64   `protobuf:"varint,3,opt,name=myTime,proto3" json:"myTime,omitempty"`
+	Ip                   *IPDesc  `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`
+	VersionStr           string   `protobuf:"bytes,5,opt,name=versionStr,proto3" json:"versionStr,omitempty"`
+	SubnetIDs            [][]byte `protobuf:"bytes,6,rep,name=subnetIDs,proto3" json:"subnetIDs,omitempty"`
+	Compression          bool     `protobuf:"varint,7,opt,name=compression,proto3" json:"compression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VersionWithSubnets) Reset()         { *m = VersionWithSubnets{} }
+func (m *VersionWithSubnets) String() string { return proto.CompactTextString(m) }
+func (*VersionWithSubnets) ProtoMessage()    {}
+func (*VersionWithSubnets) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{8}
+}
+
+func (m *VersionWithSubnets) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VersionWithSubnets.Unmarshal(m, b)
+}
+func (m *VersionWithSubnets) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VersionWithSubnets.Marshal(b, m, deterministic)
+}
+func (m *VersionWithSubnets) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionWithSubnets.Merge(m, src)
+}
+func (m *VersionWithSubnets) XXX_Size() int {
+	return xxx_messageInfo_VersionWithSubnets.Size(m)
+}
+func (m *VersionWithSubnets) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionWithSubnets.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VersionWithSubnets proto.InternalMessageInfo
+
+func (m *VersionWithSubnets) GetNetworkID() uint32 {
+	if m != nil {
+		return m.NetworkID
+	}
+	return 0
+}
+
+func (m *VersionWithSubnets) GetNodeID() uint32 {
+	if m != nil {
+		return m.NodeID
+	}
+	return 0
+}
+
+func (m *VersionWithSubnets) GetMyTime() uint64 {
+	if m != nil {
+		return m.MyTime
+	}
+	return 0
+}
+
+func (m *VersionWithSubnets) GetIp() *IPDesc {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *VersionWithSubnets) GetVersionStr() string {
+	if m != nil {
+		return m.VersionStr
+	}
+	return ""
+}
+
+func (m *VersionWithSubnets) GetSubnetIDs() [][]byte {
+	if m != nil {
+		return m.SubnetIDs
+	}
+	return nil
+}
+
+func (m *VersionWithSubnets) GetCompression() bool {
+	if m != nil {
+		return m.Compression
+	}
+	return false
+}
+
+type Ping struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Ping) Reset()         { *m = Ping{} }
+func (m *Ping) String() string { return proto.CompactTextString(m) }
+func (*Ping) ProtoMessage()    {}
+func (*Ping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{9}
+}
+
+func (m *Ping) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Ping.Unmarshal(m, b)
+}
+func (m *Ping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Ping.Marshal(b, m, deterministic)
+}
+func (m *Ping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ping.Merge(m, src)
+}
+func (m *Ping) XXX_Size() int {
+	return xxx_messageInfo_Ping.Size(m)
+}
+func (m *Ping) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ping.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Ping proto.InternalMessageInfo
+
+type Pong struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Pong) Reset()         { *m = Pong{} }
+func (m *Pong) String() string { return proto.CompactTextString(m) }
+func (*Pong) ProtoMessage()    {}
+func (*Pong) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{10}
+}
+
+func (m *Pong) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Pong.Unmarshal(m, b)
+}
+func (m *Pong) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Pong.Marshal(b, m, deterministic)
+}
+func (m *Pong) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Pong.Merge(m, src)
+}
+func (m *Pong) XXX_Size() int {
+	return xxx_messageInfo_Pong.Size(m)
+}
+func (m *Pong) XXX_DiscardUnknown() {
+	xxx_messageInfo_Pong.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Pong proto.InternalMessageInfo
+
+type GetAcceptedFrontier struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAcceptedFrontier) Reset()         { *m = GetAcceptedFrontier{} }
+func (m *GetAcceptedFrontier) String() string { return proto.CompactTextString(m) }
+func (*GetAcceptedFrontier) ProtoMessage()    {}
+func (*GetAcceptedFrontier) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{11}
+}
+
+func (m *GetAcceptedFrontier) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAcceptedFrontier.Unmarshal(m, b)
+}
+func (m *GetAcceptedFrontier) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAcceptedFrontier.Marshal(b, m, deterministic)
+}
+func (m *GetAcceptedFrontier) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAcceptedFrontier.Merge(m, src)
+}
+func (m *GetAcceptedFrontier) XXX_Size() int {
+	return xxx_messageInfo_GetAcceptedFrontier.Size(m)
+}
+func (m *GetAcceptedFrontier) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAcceptedFrontier.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAcceptedFrontier proto.InternalMessageInfo
+
+func (m *GetAcceptedFrontier) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *GetAcceptedFrontier) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *GetAcceptedFrontier) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+type AcceptedFrontier struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ContainerIDs         [][]byte `protobuf:"bytes,3,rep,name=containerIDs,proto3" json:"containerIDs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcceptedFrontier) Reset()         { *m = AcceptedFrontier{} }
+func (m *AcceptedFrontier) String() string { return proto.CompactTextString(m) }
+func (*AcceptedFrontier) ProtoMessage()    {}
+func (*AcceptedFrontier) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{12}
+}
+
+func (m *AcceptedFrontier) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcceptedFrontier.Unmarshal(m, b)
+}
+func (m *AcceptedFrontier) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcceptedFrontier.Marshal(b, m, deterministic)
+}
+func (m *AcceptedFrontier) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcceptedFrontier.Merge(m, src)
+}
+func (m *AcceptedFrontier) XXX_Size() int {
+	return xxx_messageInfo_AcceptedFrontier.Size(m)
+}
+func (m *AcceptedFrontier) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcceptedFrontier.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcceptedFrontier proto.InternalMessageInfo
+
+func (m *AcceptedFrontier) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *AcceptedFrontier) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *AcceptedFrontier) GetContainerIDs() [][]byte {
+	if m != nil {
+		return m.ContainerIDs
+	}
+	return nil
+}
+
+type GetAccepted struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerIDs         [][]byte `protobuf:"bytes,4,rep,name=containerIDs,proto3" json:"containerIDs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAccepted) Reset()         { *m = GetAccepted{} }
+func (m *GetAccepted) String() string { return proto.CompactTextString(m) }
+func (*GetAccepted) ProtoMessage()    {}
+func (*GetAccepted) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{13}
+}
+
+func (m *GetAccepted) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAccepted.Unmarshal(m, b)
+}
+func (m *GetAccepted) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAccepted.Marshal(b, m, deterministic)
+}
+func (m *GetAccepted) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAccepted.Merge(m, src)
+}
+func (m *GetAccepted) XXX_Size() int {
+	return xxx_messageInfo_GetAccepted.Size(m)
+}
+func (m *GetAccepted) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAccepted.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAccepted proto.InternalMessageInfo
+
+func (m *GetAccepted) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *GetAccepted) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *GetAccepted) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *GetAccepted) GetContainerIDs() [][]byte {
+	if m != nil {
+		return m.ContainerIDs
+	}
+	return nil
+}
+
+type Accepted struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ContainerIDs         [][]byte `protobuf:"bytes,3,rep,name=containerIDs,proto3" json:"containerIDs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Accepted) Reset()         { *m = Accepted{} }
+func (m *Accepted) String() string { return proto.CompactTextString(m) }
+func (*Accepted) ProtoMessage()    {}
+func (*Accepted) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{14}
+}
+
+func (m *Accepted) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Accepted.Unmarshal(m, b)
+}
+func (m *Accepted) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Accepted.Marshal(b, m, deterministic)
+}
+func (m *Accepted) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Accepted.Merge(m, src)
+}
+func (m *Accepted) XXX_Size() int {
+	return xxx_messageInfo_Accepted.Size(m)
+}
+func (m *Accepted) XXX_DiscardUnknown() {
+	xxx_messageInfo_Accepted.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Accepted proto.InternalMessageInfo
+
+func (m *Accepted) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *Accepted) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *Accepted) GetContainerIDs() [][]byte {
+	if m != nil {
+		return m.ContainerIDs
+	}
+	return nil
+}
+
+type GetAncestors struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerID          []byte   `protobuf:"bytes,4,opt,name=containerID,proto3" json:"containerID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAncestors) Reset()         { *m = GetAncestors{} }
+func (m *GetAncestors) String() string { return proto.CompactTextString(m) }
+func (*GetAncestors) ProtoMessage()    {}
+func (*GetAncestors) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{15}
+}
+
+func (m *GetAncestors) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAncestors.Unmarshal(m, b)
+}
+func (m *GetAncestors) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAncestors.Marshal(b, m, deterministic)
+}
+func (m *GetAncestors) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAncestors.Merge(m, src)
+}
+func (m *GetAncestors) XXX_Size() int {
+	return xxx_messageInfo_GetAncestors.Size(m)
+}
+func (m *GetAncestors) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAncestors.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAncestors proto.InternalMessageInfo
+
+func (m *GetAncestors) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *GetAncestors) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *GetAncestors) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *GetAncestors) GetContainerID() []byte {
+	if m != nil {
+		return m.ContainerID
+	}
+	return nil
+}
+
+type MultiPut struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Containers           [][]byte `protobuf:"bytes,3,rep,name=containers,proto3" json:"containers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MultiPut) Reset()         { *m = MultiPut{} }
+func (m *MultiPut) String() string { return proto.CompactTextString(m) }
+func (*MultiPut) ProtoMessage()    {}
+func (*MultiPut) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{16}
+}
+
+func (m *MultiPut) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiPut.Unmarshal(m, b)
+}
+func (m *MultiPut) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiPut.Marshal(b, m, deterministic)
+}
+func (m *MultiPut) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiPut.Merge(m, src)
+}
+func (m *MultiPut) XXX_Size() int {
+	return xxx_messageInfo_MultiPut.Size(m)
+}
+func (m *MultiPut) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiPut.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiPut proto.InternalMessageInfo
+
+func (m *MultiPut) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *MultiPut) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *MultiPut) GetContainers() [][]byte {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+type Get struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerID          []byte   `protobuf:"bytes,4,opt,name=containerID,proto3" json:"containerID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Get) Reset()         { *m = Get{} }
+func (m *Get) String() string { return proto.CompactTextString(m) }
+func (*Get) ProtoMessage()    {}
+func (*Get) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{17}
+}
+
+func (m *Get) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Get.Unmarshal(m, b)
+}
+func (m *Get) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Get.Marshal(b, m, deterministic)
+}
+func (m *Get) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Get.Merge(m, src)
+}
+func (m *Get) XXX_Size() int {
+	return xxx_messageInfo_Get.Size(m)
+}
+func (m *Get) XXX_DiscardUnknown() {
+	xxx_messageInfo_Get.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Get proto.InternalMessageInfo
+
+func (m *Get) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *Get) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *Get) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *Get) GetContainerID() []byte {
+	if m != nil {
+		return m.ContainerID
+	}
+	return nil
+}
+
+type Put struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ContainerID          []byte   `protobuf:"bytes,3,opt,name=containerID,proto3" json:"containerID,omitempty"`
+	Container            []byte   `protobuf:"bytes,4,opt,name=container,proto3" json:"container,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Put) Reset()         { *m = Put{} }
+func (m *Put) String() string { return proto.CompactTextString(m) }
+func (*Put) ProtoMessage()    {}
+func (*Put) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{18}
+}
+
+func (m *Put) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Put.Unmarshal(m, b)
+}
+func (m *Put) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Put.Marshal(b, m, deterministic)
+}
+func (m *Put) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Put.Merge(m, src)
+}
+func (m *Put) XXX_Size() int {
+	return xxx_messageInfo_Put.Size(m)
+}
+func (m *Put) XXX_DiscardUnknown() {
+	xxx_messageInfo_Put.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Put proto.InternalMessageInfo
+
+func (m *Put) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *Put) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *Put) GetContainerID() []byte {
+	if m != nil {
+		return m.ContainerID
+	}
+	return nil
+}
+
+func (m *Put) GetContainer() []byte {
+	if m != nil {
+		return m.Container
+	}
+	return nil
+}
+
+type PushQuery struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerID          []byte   `protobuf:"bytes,4,opt,name=containerID,proto3" json:"containerID,omitempty"`
+	Container            []byte   `protobuf:"bytes,5,opt,name=container,proto3" json:"container,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushQuery) Reset()         { *m = PushQuery{} }
+func (m *PushQuery) String() string { return proto.CompactTextString(m) }
+func (*PushQuery) ProtoMessage()    {}
+func (*PushQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{19}
+}
+
+func (m *PushQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushQuery.Unmarshal(m, b)
+}
+func (m *PushQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushQuery.Marshal(b, m, deterministic)
+}
+func (m *PushQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushQuery.Merge(m, src)
+}
+func (m *PushQuery) XXX_Size() int {
+	return xxx_messageInfo_PushQuery.Size(m)
+}
+func (m *PushQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushQuery proto.InternalMessageInfo
+
+func (m *PushQuery) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *PushQuery) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *PushQuery) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *PushQuery) GetContainerID() []byte {
+	if m != nil {
+		return m.ContainerID
+	}
+	return nil
+}
+
+func (m *PushQuery) GetContainer() []byte {
+	if m != nil {
+		return m.Container
+	}
+	return nil
+}
+
+type PullQuery struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	ContainerID          []byte   `protobuf:"bytes,4,opt,name=containerID,proto3" json:"containerID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PullQuery) Reset()         { *m = PullQuery{} }
+func (m *PullQuery) String() string { return proto.CompactTextString(m) }
+func (*PullQuery) ProtoMessage()    {}
+func (*PullQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{20}
+}
+
+func (m *PullQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PullQuery.Unmarshal(m, b)
+}
+func (m *PullQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PullQuery.Marshal(b, m, deterministic)
+}
+func (m *PullQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PullQuery.Merge(m, src)
+}
+func (m *PullQuery) XXX_Size() int {
+	return xxx_messageInfo_PullQuery.Size(m)
+}
+func (m *PullQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_PullQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PullQuery proto.InternalMessageInfo
+
+func (m *PullQuery) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *PullQuery) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *PullQuery) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *PullQuery) GetContainerID() []byte {
+	if m != nil {
+		return m.ContainerID
+	}
+	return nil
+}
+
+type Chits struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	ContainerIDs         [][]byte `protobuf:"bytes,3,rep,name=containerIDs,proto3" json:"containerIDs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Chits) Reset()         { *m = Chits{} }
+func (m *Chits) String() string { return proto.CompactTextString(m) }
+func (*Chits) ProtoMessage()    {}
+func (*Chits) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{21}
+}
+
+func (m *Chits) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Chits.Unmarshal(m, b)
+}
+func (m *Chits) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Chits.Marshal(b, m, deterministic)
+}
+func (m *Chits) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chits.Merge(m, src)
+}
+func (m *Chits) XXX_Size() int {
+	return xxx_messageInfo_Chits.Size(m)
+}
+func (m *Chits) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chits.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chits proto.InternalMessageInfo
+
+func (m *Chits) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *Chits) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *Chits) GetContainerIDs() [][]byte {
+	if m != nil {
+		return m.ContainerIDs
+	}
+	return nil
+}
+
+type GetStateSummary struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateSummary) Reset()         { *m = GetStateSummary{} }
+func (m *GetStateSummary) String() string { return proto.CompactTextString(m) }
+func (*GetStateSummary) ProtoMessage()    {}
+func (*GetStateSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{22}
+}
+
+func (m *GetStateSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateSummary.Unmarshal(m, b)
+}
+func (m *GetStateSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateSummary.Marshal(b, m, deterministic)
+}
+func (m *GetStateSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateSummary.Merge(m, src)
+}
+func (m *GetStateSummary) XXX_Size() int {
+	return xxx_messageInfo_GetStateSummary.Size(m)
+}
+func (m *GetStateSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateSummary proto.InternalMessageInfo
+
+func (m *GetStateSummary) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *GetStateSummary) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *GetStateSummary) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+type StateSummary struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Summary              []byte   `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateSummary) Reset()         { *m = StateSummary{} }
+func (m *StateSummary) String() string { return proto.CompactTextString(m) }
+func (*StateSummary) ProtoMessage()    {}
+func (*StateSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{23}
+}
+
+func (m *StateSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateSummary.Unmarshal(m, b)
+}
+func (m *StateSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateSummary.Marshal(b, m, deterministic)
+}
+func (m *StateSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateSummary.Merge(m, src)
+}
+func (m *StateSummary) XXX_Size() int {
+	return xxx_messageInfo_StateSummary.Size(m)
+}
+func (m *StateSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateSummary proto.InternalMessageInfo
+
+func (m *StateSummary) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *StateSummary) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *StateSummary) GetSummary() []byte {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
+type GetStateChunk struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	SummaryID            []byte   `protobuf:"bytes,4,opt,name=summaryID,proto3" json:"summaryID,omitempty"`
+	ChunkIndex           uint32   `protobuf:"varint,5,opt,name=chunkIndex,proto3" json:"chunkIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateChunk) Reset()         { *m = GetStateChunk{} }
+func (m *GetStateChunk) String() string { return proto.CompactTextString(m) }
+func (*GetStateChunk) ProtoMessage()    {}
+func (*GetStateChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{24}
+}
+
+func (m *GetStateChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateChunk.Unmarshal(m, b)
+}
+func (m *GetStateChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateChunk.Marshal(b, m, deterministic)
+}
+func (m *GetStateChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateChunk.Merge(m, src)
+}
+func (m *GetStateChunk) XXX_Size() int {
+	return xxx_messageInfo_GetStateChunk.Size(m)
+}
+func (m *GetStateChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateChunk proto.InternalMessageInfo
+
+func (m *GetStateChunk) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *GetStateChunk) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *GetStateChunk) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *GetStateChunk) GetSummaryID() []byte {
+	if m != nil {
+		return m.SummaryID
+	}
+	return nil
+}
+
+func (m *GetStateChunk) GetChunkIndex() uint32 {
+	if m != nil {
+		return m.ChunkIndex
+	}
+	return 0
+}
+
+type StateChunk struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Chunk                []byte   `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateChunk) Reset()         { *m = StateChunk{} }
+func (m *StateChunk) String() string { return proto.CompactTextString(m) }
+func (*StateChunk) ProtoMessage()    {}
+func (*StateChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{25}
+}
+
+func (m *StateChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateChunk.Unmarshal(m, b)
+}
+func (m *StateChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateChunk.Marshal(b, m, deterministic)
+}
+func (m *StateChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateChunk.Merge(m, src)
+}
+func (m *StateChunk) XXX_Size() int {
+	return xxx_messageInfo_StateChunk.Size(m)
+}
+func (m *StateChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateChunk proto.InternalMessageInfo
+
+func (m *StateChunk) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *StateChunk) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *StateChunk) GetChunk() []byte {
+	if m != nil {
+		return m.Chunk
+	}
+	return nil
+}
+
+type AppRequest struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Deadline             uint64   `protobuf:"varint,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	AppBytes             []byte   `protobuf:"bytes,4,opt,name=appBytes,proto3" json:"appBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppRequest) Reset()         { *m = AppRequest{} }
+func (m *AppRequest) String() string { return proto.CompactTextString(m) }
+func (*AppRequest) ProtoMessage()    {}
+func (*AppRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{26}
+}
+
+func (m *AppRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppRequest.Unmarshal(m, b)
+}
+func (m *AppRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppRequest.Marshal(b, m, deterministic)
+}
+func (m *AppRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppRequest.Merge(m, src)
+}
+func (m *AppRequest) XXX_Size() int {
+	return xxx_messageInfo_AppRequest.Size(m)
+}
+func (m *AppRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppRequest proto.InternalMessageInfo
+
+func (m *AppRequest) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *AppRequest) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *AppRequest) GetDeadline() uint64 {
+	if m != nil {
+		return m.Deadline
+	}
+	return 0
+}
+
+func (m *AppRequest) GetAppBytes() []byte {
+	if m != nil {
+		return m.AppBytes
+	}
+	return nil
+}
+
+type AppResponse struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	AppBytes             []byte   `protobuf:"bytes,3,opt,name=appBytes,proto3" json:"appBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppResponse) Reset()         { *m = AppResponse{} }
+func (m *AppResponse) String() string { return proto.CompactTextString(m) }
+func (*AppResponse) ProtoMessage()    {}
+func (*AppResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{27}
+}
+
+func (m *AppResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppResponse.Unmarshal(m, b)
+}
+func (m *AppResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppResponse.Marshal(b, m, deterministic)
+}
+func (m *AppResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppResponse.Merge(m, src)
+}
+func (m *AppResponse) XXX_Size() int {
+	return xxx_messageInfo_AppResponse.Size(m)
+}
+func (m *AppResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppResponse proto.InternalMessageInfo
+
+func (m *AppResponse) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *AppResponse) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *AppResponse) GetAppBytes() []byte {
+	if m != nil {
+		return m.AppBytes
+	}
+	return nil
+}
+
+type AppGossip struct {
+	ChainID              []byte   `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	AppBytes             []byte   `protobuf:"bytes,2,opt,name=appBytes,proto3" json:"appBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppGossip) Reset()         { *m = AppGossip{} }
+func (m *AppGossip) String() string { return proto.CompactTextString(m) }
+func (*AppGossip) ProtoMessage()    {}
+func (*AppGossip) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a5158953fd932ae8, []int{28}
+}
+
+func (m *AppGossip) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppGossip.Unmarshal(m, b)
+}
+func (m *AppGossip) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppGossip.Marshal(b, m, deterministic)
+}
+func (m *AppGossip) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppGossip.Merge(m, src)
+}
+func (m *AppGossip) XXX_Size() int {
+	return xxx_messageInfo_AppGossip.Size(m)
+}
+func (m *AppGossip) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppGossip.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppGossip proto.InternalMessageInfo
+
+func (m *AppGossip) GetChainID() []byte {
+	if m != nil {
+		return m.ChainID
+	}
+	return nil
+}
+
+func (m *AppGossip) GetAppBytes() []byte {
+	if m != nil {
+		return m.AppBytes
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Message)(nil), "networkproto.Message")
+	proto.RegisterType((*IPDesc)(nil), "networkproto.IPDesc")
+	proto.RegisterType((*GetVersion)(nil), "networkproto.GetVersion")
+	proto.RegisterType((*Version)(nil), "networkproto.Version")
+	proto.RegisterType((*GetPeerList)(nil), "networkproto.GetPeerList")
+	proto.RegisterType((*PeerList)(nil), "networkproto.PeerList")
+	proto.RegisterType((*SignedIP)(nil), "networkproto.SignedIP")
+	proto.RegisterType((*SignedPeerList)(nil), "networkproto.SignedPeerList")
+	proto.RegisterType((*VersionWithSubnets)(nil), "networkproto.VersionWithSubnets")
+	proto.RegisterType((*Ping)(nil), "networkproto.Ping")
+	proto.RegisterType((*Pong)(nil), "networkproto.Pong")
+	proto.RegisterType((*GetAcceptedFrontier)(nil), "networkproto.GetAcceptedFrontier")
+	proto.RegisterType((*AcceptedFrontier)(nil), "networkproto.AcceptedFrontier")
+	proto.RegisterType((*GetAccepted)(nil), "networkproto.GetAccepted")
+	proto.RegisterType((*Accepted)(nil), "networkproto.Accepted")
+	proto.RegisterType((*GetAncestors)(nil), "networkproto.GetAncestors")
+	proto.RegisterType((*MultiPut)(nil), "networkproto.MultiPut")
+	proto.RegisterType((*Get)(nil), "networkproto.Get")
+	proto.RegisterType((*Put)(nil), "networkproto.Put")
+	proto.RegisterType((*PushQuery)(nil), "networkproto.PushQuery")
+	proto.RegisterType((*PullQuery)(nil), "networkproto.PullQuery")
+	proto.RegisterType((*Chits)(nil), "networkproto.Chits")
+	proto.RegisterType((*GetStateSummary)(nil), "networkproto.GetStateSummary")
+	proto.RegisterType((*StateSummary)(nil), "networkproto.StateSummary")
+	proto.RegisterType((*GetStateChunk)(nil), "networkproto.GetStateChunk")
+	proto.RegisterType((*StateChunk)(nil), "networkproto.StateChunk")
+	proto.RegisterType((*AppRequest)(nil), "networkproto.AppRequest")
+	proto.RegisterType((*AppResponse)(nil), "networkproto.AppResponse")
+	proto.RegisterType((*AppGossip)(nil), "networkproto.AppGossip")
+}
+
+func init() { proto.RegisterFile("networkproto.proto", fileDescriptor_a5158953fd932ae8) }
+
+var fileDescriptor_a5158953fd932ae8 = []byte{
+	// 1129 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x57, 0x6b, 0x6b, 0x24, 0x45,
+	0x17, 0xee, 0xc9, 0x24, 0x73, 0x39, 0xd3, 0x93, 0x64, 0x2b, 0xd9, 0x6c, 0x6d, 0xde, 0xbc, 0x21,
+	0x16, 0x0a, 0x8b, 0x2e, 0x0b, 0x5e, 0x50, 0x10, 0x14, 0xb3, 0x09, 0x9b, 0x1e, 0xd8, 0x85, 0x58,
+	0xf1, 0x02, 0x7e, 0xb2, 0x33, 0x53, 0xf6, 0x34, 0x3b, 0xd3, 0xdd, 0x76, 0xd5, 0xa8, 0x41, 0x05,
+	0x05, 0xfd, 0x19, 0x22, 0x7e, 0xf4, 0x9f, 0xf9, 0x33, 0xa4, 0x4e, 0x57, 0xf5, 0x6d, 0x7a, 0x55,
+	0x4c, 0x86, 0xf8, 0x25, 0xe9, 0x3a, 0xf5, 0x3c, 0xe7, 0x56, 0x55, 0xe7, 0x9c, 0x01, 0x12, 0x09,
+	0xf5, 0x75, 0x9c, 0x3e, 0x4f, 0xd2, 0x58, 0xc5, 0x8f, 0xf0, 0x2f, 0x71, 0xcb, 0x32, 0xf6, 0xbb,
+	0x0b, 0xdd, 0x67, 0x42, 0x4a, 0x3f, 0x10, 0xe4, 0x5d, 0x80, 0x40, 0xa8, 0x4f, 0x44, 0x2a, 0xc3,
+	0x38, 0xa2, 0xad, 0xa3, 0xd6, 0x83, 0xc1, 0x1b, 0xf4, 0x51, 0x45, 0xc5, 0x59, 0xbe, 0xef, 0x39,
+	0xbc, 0x84, 0x26, 0xaf, 0x43, 0xf7, 0x2b, 0x43, 0x5c, 0x43, 0xe2, 0xdd, 0x2a, 0xb1, 0x60, 0x59,
+	0x1c, 0x79, 0x0f, 0x06, 0x81, 0x50, 0xe7, 0x42, 0xa4, 0x4f, 0x43, 0xa9, 0x68, 0x1b, 0x69, 0xf7,
+	0x97, 0xec, 0x59, 0x80, 0xe7, 0xf0, 0x32, 0x9e, 0xbc, 0x05, 0xbd, 0xc4, 0x72, 0xd7, 0x91, 0xbb,
+	0x57, 0xe5, 0x96, 0x88, 0x39, 0x92, 0x3c, 0x80, 0xf5, 0x24, 0x8c, 0x02, 0xba, 0x81, 0x0c, 0x52,
+	0x63, 0x84, 0x51, 0xe0, 0x39, 0x1c, 0x11, 0x88, 0x8c, 0xa3, 0x80, 0x76, 0x1a, 0x91, 0xb1, 0x41,
+	0xc6, 0x51, 0x40, 0x3e, 0x86, 0x9d, 0x40, 0xa8, 0xe3, 0xf1, 0x58, 0x24, 0x4a, 0x4c, 0x9e, 0xa4,
+	0x71, 0xa4, 0x42, 0x91, 0xd2, 0x2e, 0x12, 0x5f, 0x5a, 0x0a, 0xa8, 0x0e, 0xf4, 0x1c, 0xde, 0xc4,
+	0x27, 0x4f, 0x61, 0xdb, 0xaf, 0xeb, 0xec, 0xa1, 0xce, 0xc3, 0xaa, 0xce, 0x06, 0x85, 0x4b, 0x4c,
+	0x93, 0x6d, 0x0b, 0xa5, 0xfd, 0x17, 0x64, 0xdb, 0x02, 0x4c, 0xb6, 0xed, 0x52, 0x67, 0xdb, 0xaa,
+	0xa4, 0xd0, 0x94, 0xed, 0x12, 0x31, 0x47, 0x92, 0x0f, 0xc0, 0xd5, 0x4a, 0xa2, 0xb1, 0x90, 0x2a,
+	0x4e, 0x25, 0x1d, 0x20, 0x73, 0x7f, 0xd9, 0xaa, 0x45, 0x78, 0x0e, 0xaf, 0x30, 0xb4, 0xdd, 0xf9,
+	0x62, 0xa6, 0xc2, 0xf3, 0x85, 0xa2, 0x6e, 0x93, 0xdd, 0x67, 0x66, 0x57, 0xdb, 0xb5, 0x48, 0xf2,
+	0x0a, 0xb4, 0x03, 0xa1, 0xe8, 0x10, 0x09, 0x77, 0x96, 0xcc, 0x79, 0x0e, 0xd7, 0xfb, 0x1a, 0x96,
+	0x2c, 0x14, 0xdd, 0x6c, 0x82, 0x65, 0x2a, 0xf5, 0x3e, 0x79, 0x07, 0xfa, 0xc9, 0x42, 0x4e, 0x3f,
+	0x5c, 0x88, 0xf4, 0x8a, 0x6e, 0x21, 0xf8, 0x5e, 0x1d, 0x6c, 0xb6, 0x3d, 0x87, 0x17, 0xd8, 0x8c,
+	0x38, 0x9b, 0x65, 0xc4, 0xed, 0x66, 0xe2, 0x6c, 0x56, 0x22, 0x9a, 0x05, 0x79, 0x0d, 0x36, 0xc6,
+	0xd3, 0x50, 0x49, 0x7a, 0x07, 0x49, 0x3b, 0x55, 0xd2, 0x89, 0xde, 0xf2, 0x1c, 0x9e, 0x61, 0xc8,
+	0x08, 0xb6, 0x02, 0xa1, 0x2e, 0x94, 0xaf, 0xc4, 0xc5, 0x62, 0x3e, 0xf7, 0xd3, 0x2b, 0x4a, 0x90,
+	0xf6, 0xff, 0xa5, 0xc0, 0xcb, 0x20, 0xcf, 0xe1, 0x75, 0x9e, 0x3e, 0x2f, 0x59, 0xd6, 0xb3, 0xd3,
+	0x74, 0x5e, 0x35, 0x25, 0x15, 0x06, 0x39, 0x81, 0xa1, 0x55, 0x7a, 0x32, 0x5d, 0x44, 0xcf, 0xe9,
+	0x2e, 0xaa, 0xf8, 0x5f, 0xb3, 0x2b, 0x08, 0xf1, 0x1c, 0x5e, 0xe5, 0xe8, 0x42, 0x24, 0x0b, 0x0d,
+	0x77, 0x9b, 0x0a, 0x51, 0x85, 0x0e, 0xb2, 0xc2, 0xf5, 0x93, 0x84, 0x8b, 0x2f, 0x17, 0x42, 0x2a,
+	0xba, 0xd7, 0xc4, 0x3d, 0xce, 0xf7, 0x35, 0xb7, 0x40, 0xeb, 0x37, 0x82, 0x2b, 0x99, 0xc4, 0x91,
+	0x14, 0xf4, 0x5e, 0xd3, 0x1b, 0x39, 0x2e, 0x00, 0xfa, 0x8d, 0x94, 0xf0, 0xfa, 0xb8, 0xfd, 0x24,
+	0x39, 0x8b, 0xa5, 0x0c, 0x13, 0x4a, 0x9b, 0x8e, 0xfb, 0xd8, 0x6e, 0xeb, 0xe3, 0xce, 0xb1, 0xe4,
+	0x09, 0x6c, 0xca, 0x30, 0x88, 0xc4, 0x24, 0x2f, 0x86, 0xf7, 0x91, 0x7d, 0x50, 0x8b, 0xb9, 0x82,
+	0xf1, 0x1c, 0x5e, 0x63, 0x11, 0x0e, 0xc4, 0x14, 0xd7, 0x4f, 0x43, 0x35, 0xbd, 0x58, 0x5c, 0x46,
+	0x42, 0x49, 0xba, 0x8f, 0xba, 0x8e, 0x1a, 0xeb, 0x71, 0x09, 0xe7, 0x39, 0xbc, 0x81, 0xfd, 0xb8,
+	0x0f, 0xdd, 0x79, 0xd6, 0x1f, 0xd8, 0x43, 0xe8, 0x8c, 0xce, 0x4f, 0x85, 0x1c, 0x93, 0x4d, 0x58,
+	0x0b, 0x13, 0xec, 0x10, 0x2e, 0x5f, 0x0b, 0x13, 0x42, 0x74, 0xad, 0x4c, 0x15, 0x96, 0xfe, 0x21,
+	0xc7, 0x6f, 0xe6, 0x02, 0x14, 0xdd, 0x82, 0xfd, 0xd2, 0x82, 0xae, 0xf9, 0x26, 0x07, 0xd0, 0x37,
+	0xbe, 0x8c, 0x4e, 0x51, 0xc9, 0x90, 0x17, 0x02, 0xb2, 0x07, 0x9d, 0x28, 0x9e, 0x88, 0xd1, 0xa9,
+	0xd1, 0x66, 0x56, 0x5a, 0x3e, 0xbf, 0xfa, 0x28, 0x9c, 0x0b, 0xec, 0x14, 0xeb, 0xdc, 0xac, 0xc8,
+	0xcb, 0xe8, 0x4b, 0xd6, 0x01, 0x76, 0xab, 0x41, 0x66, 0xde, 0xa2, 0x87, 0x87, 0x00, 0x26, 0xb8,
+	0x0b, 0x95, 0x62, 0xf5, 0xef, 0xf3, 0x92, 0x84, 0x0d, 0x61, 0x50, 0xea, 0x35, 0xec, 0x6d, 0xe8,
+	0xd9, 0x6f, 0xf2, 0x2a, 0x6c, 0xe8, 0xf6, 0x21, 0x69, 0xeb, 0xa8, 0xfd, 0x42, 0x1b, 0x19, 0x84,
+	0xfd, 0xd0, 0x82, 0x5e, 0x76, 0x4c, 0xa3, 0x73, 0x9d, 0x95, 0xb1, 0x48, 0x95, 0xc9, 0x13, 0x7e,
+	0x1b, 0x6f, 0xd7, 0xfe, 0xc6, 0xdb, 0x03, 0xe8, 0xab, 0x70, 0x2e, 0xa4, 0xf2, 0xe7, 0x89, 0x09,
+	0xb7, 0x10, 0xe8, 0x5d, 0x7d, 0xf0, 0xbe, 0x5a, 0xa4, 0x02, 0x03, 0x77, 0x79, 0x21, 0x60, 0xef,
+	0xc3, 0x66, 0xf5, 0xa2, 0x90, 0x87, 0xd5, 0x00, 0xf6, 0x9a, 0x6e, 0xd5, 0xe8, 0xdc, 0x86, 0xf0,
+	0x47, 0x0b, 0xc8, 0xf2, 0xed, 0xf8, 0x2f, 0x1e, 0x1a, 0x26, 0x02, 0xdd, 0x1b, 0x9d, 0x4a, 0xda,
+	0x39, 0x6a, 0x63, 0x22, 0xac, 0x80, 0x1c, 0xc1, 0x60, 0x1c, 0xcf, 0x93, 0x54, 0x48, 0x8d, 0xc7,
+	0x76, 0xdc, 0xe3, 0x65, 0x11, 0xeb, 0xc0, 0xba, 0x6e, 0xf9, 0xf8, 0x3f, 0x8e, 0x02, 0x16, 0xc2,
+	0x4e, 0x43, 0x7f, 0x26, 0x14, 0xba, 0xe3, 0xa9, 0x1f, 0x46, 0x26, 0x70, 0x97, 0xdb, 0xa5, 0x76,
+	0x20, 0xcd, 0x6a, 0x47, 0x1e, 0x79, 0x21, 0x20, 0xfb, 0xd0, 0x9b, 0x08, 0x7f, 0x32, 0x0b, 0x23,
+	0x1b, 0x7e, 0xbe, 0x66, 0x11, 0x6c, 0xdf, 0x98, 0x1d, 0x06, 0xee, 0x38, 0x8e, 0x94, 0x1f, 0x46,
+	0x22, 0xd5, 0x99, 0x68, 0x63, 0x26, 0x2a, 0x32, 0xf6, 0x73, 0x0b, 0x2f, 0x78, 0xde, 0xcf, 0x57,
+	0x10, 0xd3, 0x92, 0x1f, 0xeb, 0x0d, 0x7e, 0x7c, 0x01, 0xbd, 0x6b, 0xfb, 0xf0, 0x4f, 0xe2, 0xfd,
+	0xa9, 0x05, 0x6e, 0x79, 0xb0, 0x58, 0x49, 0xc0, 0x78, 0xc3, 0x72, 0xa3, 0xe6, 0x29, 0x96, 0x45,
+	0xec, 0x12, 0x7a, 0x76, 0x40, 0xf9, 0xd7, 0x1e, 0x1c, 0x02, 0xe4, 0x2a, 0x6d, 0xb0, 0x25, 0x09,
+	0xfb, 0x16, 0xda, 0x67, 0x42, 0xdd, 0x52, 0x80, 0xdf, 0x43, 0xfb, 0x3a, 0xb1, 0xd5, 0x0c, 0xb4,
+	0x97, 0x0c, 0x68, 0x7e, 0xbe, 0xb4, 0xc5, 0x2e, 0x17, 0xb0, 0x5f, 0x5b, 0xd0, 0xcf, 0x87, 0xaf,
+	0xdb, 0x49, 0x41, 0xd5, 0xc3, 0x8d, 0xba, 0x87, 0x3f, 0xa2, 0x87, 0xb3, 0xd9, 0x2d, 0x7a, 0xc8,
+	0xc6, 0xb0, 0x81, 0x33, 0xe3, 0x4a, 0x5f, 0x9c, 0x80, 0xad, 0xda, 0x84, 0xb9, 0x92, 0xc2, 0xf9,
+	0x39, 0xb8, 0x37, 0x62, 0x83, 0x42, 0x57, 0x66, 0x2a, 0xcc, 0xad, 0xb3, 0x4b, 0xf6, 0x5b, 0x0b,
+	0x86, 0x95, 0x01, 0x75, 0x25, 0xa7, 0x86, 0xbd, 0x0b, 0x0d, 0xe6, 0x67, 0x56, 0x08, 0xf0, 0xcd,
+	0x6b, 0xd3, 0xa3, 0x68, 0x22, 0xbe, 0xc1, 0x4b, 0x35, 0xe4, 0x25, 0x09, 0xfb, 0x0c, 0xe0, 0x06,
+	0xfc, 0xdb, 0xd5, 0x3f, 0x33, 0xf4, 0x88, 0x9d, 0x65, 0x20, 0x5b, 0xb0, 0xef, 0x00, 0x8a, 0x09,
+	0x79, 0x25, 0xb1, 0xef, 0x43, 0xcf, 0x4f, 0x92, 0xc7, 0x57, 0x4a, 0x48, 0x13, 0x7a, 0xbe, 0x66,
+	0x3e, 0x0c, 0x4a, 0x23, 0xf6, 0x75, 0xcc, 0xe7, 0x26, 0xda, 0x35, 0x13, 0xc7, 0xd0, 0xcf, 0x07,
+	0xf1, 0xbf, 0x30, 0x50, 0x56, 0xb1, 0x56, 0x55, 0x71, 0xd9, 0xc1, 0x59, 0xe5, 0xcd, 0x3f, 0x07,
+	0x00, 0x40, 0xb2, 0x06, 0x62, 0x61, 0x11, 0x00, 0x00,
+}
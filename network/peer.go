@@ -5,6 +5,8 @@ package network
 
 import (
 	"encoding/binary"
+	"errors"
+	"io"
 	"math"
 	"net"
 	"sync"
@@ -12,7 +14,9 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
@@ -29,6 +33,31 @@ type peer struct {
 	// modified on the connection's reader routine.
 	gotPeerList utils.AtomicBool
 
+	// if large messages should be sent compressed to this peer, which is the
+	// case if both we and the peer said in our VersionWithSubnets messages
+	// that we accept them. is only modified on the connection's reader
+	// routine.
+	compress utils.AtomicBool
+
+	// if messages should be sent to this peer in the protobuf format. is only
+	// modified on the connection's reader routine.
+	proto utils.AtomicBool
+
+	// if peer lists sent to and received from this peer should contain signed
+	// IPs. is only modified on the connection's reader routine.
+	signedIPs utils.AtomicBool
+
+	// if versions are exchanged with this peer in VersionWithSubnets messages,
+	// which list the subnets the sender tracks. is only modified on the
+	// connection's reader routine.
+	versionWithSubnets utils.AtomicBool
+
+	// subnets the peer tracks, other than the primary network, as an ids.Set.
+	// nil until the peer sends them, and replaced whenever the peer sends a new
+	// VersionWithSubnets message. is only modified on the connection's reader
+	// routine.
+	trackedSubnets utils.AtomicInterface
+
 	// if the version message has been received and is valid and the peerlist
 	// has been returned. is only modified on the connection's reader routine.
 	connected utils.AtomicBool
@@ -39,14 +68,9 @@ type peer struct {
 	// if the close function has been called.
 	closed utils.AtomicBool
 
-	// number of bytes currently in the send queue.
-	pendingBytes int64
-
-	// lock to ensure that closing of the sender queue is handled safely
-	senderLock sync.Mutex
 	// queue of messages this connection is attempting to send the peer. Is
 	// closed when the connection is closed.
-	sender chan []byte
+	sendQueue *sendQueue
 
 	// ip may or may not be set when the peer is first started. is only modified
 	// on the connection's reader routine.
@@ -59,6 +83,10 @@ type peer struct {
 	// the connection object that is used to read/write messages from
 	conn net.Conn
 
+	// held while a message is handled, as messages may be read from several
+	// streams of the connection at once
+	handleLock sync.Mutex
+
 	// version that the peer reported during the handshake
 	versionStruct, versionStr utils.AtomicInterface
 
@@ -140,19 +168,47 @@ func (p *peer) ReadMessages() {
 		return
 	}
 
-	pendingBuffer := wrappers.Packer{}
-	readBuffer := make([]byte, p.net.readBufferSize)
+	conn, ok := p.conn.(StreamConn)
+	if !ok {
+		p.readMessages(p.conn)
+		return
+	}
 	for {
-		read, err := p.conn.Read(readBuffer)
+		stream, err := conn.AcceptStream()
 		if err != nil {
-			p.net.log.Verbo("error on connection read to %s %s %s", p.id, p.getIP(), err)
+			p.net.log.Verbo("error on accepting a stream from %s %s %s", p.id, p.getIP(), err)
 			return
 		}
+		go func() {
+			defer p.Close()
+			p.readMessages(stream)
+		}()
+	}
+}
+
+// read messages from [r], which is the connection to the peer or one of its
+// streams
+func (p *peer) readMessages(r io.Reader) {
+	pendingBuffer := wrappers.Packer{}
+	readBuffer := make([]byte, p.net.readBufferSize)
+	// true if the pending bytes don't hold a full message
+	partial := true
+	for {
+		// A single read may return several messages, so the pending bytes
+		// are only read from once they don't hold a full message
+		if partial || len(pendingBuffer.Bytes) == 0 {
+			read, err := r.Read(readBuffer)
+			if err != nil {
+				p.net.log.Verbo("error on connection read to %s %s %s", p.id, p.getIP(), err)
+				return
+			}
 
-		pendingBuffer.Bytes = append(pendingBuffer.Bytes, readBuffer[:read]...)
+			pendingBuffer.Bytes = append(pendingBuffer.Bytes, readBuffer[:read]...)
+		}
 
 		msgBytes := pendingBuffer.UnpackBytes()
-		if pendingBuffer.Errored() {
+		partial = pendingBuffer.Errored()
+		if partial {
 			// if reading the bytes errored, then we haven't read the full
 			// message yet
 			pendingBuffer.Offset = 0
@@ -162,7 +218,7 @@ func (p *peer) ReadMessages() {
 				// we have read more bytes than the max message size allows for,
 				// so we should terminate this connection
 
-				p.net.log.Verbo("error reading too many bytes on %s %s", p.id, err)
+				p.net.log.Verbo("error reading too many bytes on %s", p.id)
 				return
 			}
 
@@ -181,10 +237,26 @@ func (p *peer) ReadMessages() {
 			// if this message is longer than the max message length, then we
 			// should terminate this connection
 
-			p.net.log.Verbo("error reading too many bytes on %s %s", p.id, err)
+			p.net.log.Verbo("error reading too many bytes on %s", p.id)
 			return
 		}
 
+		// If the peer is sending messages too quickly, stop reading from its
+		// connection until it's allowed to send another message
+		if wait := p.net.inboundThrottler.Acquire(p.id, len(msgBytes)); wait > 0 {
+			p.net.numThrottled.Inc()
+			p.net.throttledTime.Add(float64(wait.Milliseconds()))
+
+			p.net.log.Verbo("throttling %s for %s", p.id, wait)
+			throttleTimer := time.NewTimer(wait)
+			select {
+			case <-throttleTimer.C:
+			case <-p.tickerCloser:
+				throttleTimer.Stop()
+				return
+			}
+		}
+
 		p.net.log.Verbo("parsing new message from %s:\n%s",
 			p.id,
 			formatting.DumpBytes{Bytes: msgBytes})
@@ -195,10 +267,17 @@ func (p *peer) ReadMessages() {
 				p.id,
 				formatting.DumpBytes{Bytes: msgBytes},
 				err)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
+		if msgMetrics := p.net.message(msg.Op()); msgMetrics != nil {
+			msgMetrics.receivedBytes.Add(float64(len(msg.Bytes())))
+			msgMetrics.receivedCompressedBytes.Add(float64(len(msgBytes)))
+		}
 
+		p.handleLock.Lock()
 		p.handle(msg)
+		p.handleLock.Unlock()
 	}
 }
 
@@ -208,74 +287,118 @@ func (p *peer) WriteMessages() {
 
 	p.Version()
 
-	for msg := range p.sender {
-		p.net.log.Verbo("sending new message to %s:\n%s",
-			p.id,
-			formatting.DumpBytes{Bytes: msg})
-
-		atomic.AddInt64(&p.pendingBytes, -int64(len(msg)))
-		atomic.AddInt64(&p.net.pendingBytes, -int64(len(msg)))
-
-		msgb := [wrappers.IntLen]byte{}
-		binary.BigEndian.PutUint32(msgb[:], uint32(len(msg)))
-		for _, byteSlice := range [][]byte{msgb[:], msg} {
-			for len(byteSlice) > 0 {
-				written, err := p.conn.Write(byteSlice)
-				if err != nil {
-					p.net.log.Verbo("error writing to %s at %s due to: %s", p.id, p.getIP(), err)
-					return
-				}
-				p.tickerOnce.Do(p.StartTicker)
-				byteSlice = byteSlice[written:]
+	if conn, ok := p.conn.(StreamConn); ok {
+		// Messages of each priority are written to their own stream
+		for priority := ReplyPriority + 1; priority < Priority(NumPriorities); priority++ {
+			go p.writeStream(conn, priority)
+		}
+		p.writeStream(conn, ReplyPriority)
+		return
+	}
+
+	for {
+		msg, ok := p.sendQueue.Pop()
+		if !ok || !p.writeMessage(p.conn, msg) {
+			return
+		}
+	}
+}
+
+// write the messages of [priority] to a stream of [conn], which is opened when
+// the first of them is sent
+func (p *peer) writeStream(conn StreamConn, priority Priority) {
+	defer p.Close()
+
+	var stream io.WriteCloser
+	for {
+		msg, ok := p.sendQueue.PopPriority(priority)
+		if !ok {
+			return
+		}
+		if stream == nil {
+			var err error
+			stream, err = conn.OpenStream()
+			if err != nil {
+				p.net.log.Verbo("error opening a %s stream to %s at %s due to: %s", priority, p.id, p.getIP(), err)
+				return
 			}
 		}
-		atomic.StoreInt64(&p.lastSent, p.net.clock.Time().Unix())
+		if !p.writeMessage(stream, msg) {
+			return
+		}
 	}
 }
 
+// write [msg] to [w], which is the connection to the peer or one of its
+// streams. Returns false if the write failed.
+func (p *peer) writeMessage(w io.Writer, msg []byte) bool {
+	p.net.log.Verbo("sending new message to %s:\n%s",
+		p.id,
+		formatting.DumpBytes{Bytes: msg})
+
+	atomic.AddInt64(&p.net.pendingBytes, -int64(len(msg)))
+
+	msgb := [wrappers.IntLen]byte{}
+	binary.BigEndian.PutUint32(msgb[:], uint32(len(msg)))
+	for _, byteSlice := range [][]byte{msgb[:], msg} {
+		for len(byteSlice) > 0 {
+			written, err := w.Write(byteSlice)
+			if err != nil {
+				p.net.log.Verbo("error writing to %s at %s due to: %s", p.id, p.getIP(), err)
+				return false
+			}
+			p.tickerOnce.Do(p.StartTicker)
+			byteSlice = byteSlice[written:]
+		}
+	}
+	atomic.StoreInt64(&p.lastSent, p.net.clock.Time().Unix())
+	return true
+}
+
 // send assumes that the stateLock is not held.
 func (p *peer) Send(msg Msg) bool {
-	p.senderLock.Lock()
-	defer p.senderLock.Unlock()
-
-	// If the peer was closed then the sender channel was closed and we are
-	// unable to send this message without panicking. So drop the message.
+	// If the peer was closed then the send queue was closed and this message
+	// can't be sent. So drop the message.
 	if p.closed.GetValue() {
 		p.net.log.Debug("dropping message to %s due to a closed connection", p.id)
 		return false
 	}
 
-	// is it possible to send?
-	if dropMsg := p.dropMessagePeer(); dropMsg {
-		p.net.log.Debug("dropping message to %s due to a send queue with too many bytes", p.id)
-		return false
+	msgBytes, compressedBytes := msg.Bytes(), msg.CompressedBytes()
+	if p.proto.GetValue() {
+		if protoBytes := msg.ProtoBytes(); protoBytes != nil {
+			msgBytes, compressedBytes = protoBytes, msg.CompressedProtoBytes()
+		}
+	}
+	uncompressedLen := len(msgBytes)
+	if compressedBytes != nil && p.compress.GetValue() {
+		msgBytes = compressedBytes
 	}
-
-	msgBytes := msg.Bytes()
 	msgBytesLen := int64(len(msgBytes))
+	priority := msgPriority(msg)
 
 	// lets assume send will be successful, we add to the network pending bytes
 	// if we determine that we are being a bit restrictive, we could increase the global bandwidth?
 	newPendingBytes := atomic.AddInt64(&p.net.pendingBytes, msgBytesLen)
 
-	newConnPendingBytes := atomic.LoadInt64(&p.pendingBytes) + msgBytesLen
-	if dropMsg := p.dropMessage(newConnPendingBytes, newPendingBytes); dropMsg {
+	newConnPendingBytes := p.sendQueue.Bytes() + msgBytesLen
+	if p.dropMessage(priority, newConnPendingBytes, newPendingBytes) ||
+		!p.sendQueue.Push(msgBytes, priority, p.net.peerPendingSendBytes[priority]) {
 		// we never sent the message, remove from pending totals
 		atomic.AddInt64(&p.net.pendingBytes, -msgBytesLen)
-		p.net.log.Debug("dropping message to %s due to a send queue with too many bytes", p.id)
+		p.net.log.Debug("dropping %s message to %s due to a full %s send queue", msg.Op(), p.id, priority)
+		if msgMetrics := p.net.message(msg.Op()); msgMetrics != nil {
+			msgMetrics.numDropped.Inc()
+		}
+		p.net.priorities[priority].numDropped.Inc()
 		return false
 	}
 
-	select {
-	case p.sender <- msgBytes:
-		atomic.AddInt64(&p.pendingBytes, msgBytesLen)
-		return true
-	default:
-		// we never sent the message, remove from pending totals
-		atomic.AddInt64(&p.net.pendingBytes, -msgBytesLen)
-		p.net.log.Debug("dropping message to %s due to a full send queue", p.id)
-		return false
+	if msgMetrics := p.net.message(msg.Op()); msgMetrics != nil {
+		msgMetrics.sentBytes.Add(float64(uncompressedLen))
+		msgMetrics.sentCompressedBytes.Add(float64(msgBytesLen))
 	}
+	return true
 }
 
 // assumes the stateLock is not held
@@ -300,7 +423,7 @@ func (p *peer) handle(msg Msg) {
 	msgMetrics.numReceived.Inc()
 
 	switch op {
-	case Version:
+	case Version, VersionWithSubnets:
 		p.version(msg)
 		return
 	case GetVersion:
@@ -318,6 +441,9 @@ func (p *peer) handle(msg Msg) {
 	case PeerList:
 		p.peerList(msg)
 		return
+	case SignedPeerList:
+		p.signedPeerList(msg)
+		return
 	}
 	if !p.connected.GetValue() {
 		p.net.log.Debug("dropping message from %s because the connection hasn't been established yet", p.id)
@@ -361,20 +487,39 @@ func (p *peer) handle(msg Msg) {
 		p.pullQuery(msg)
 	case Chits:
 		p.chits(msg)
+	case GetStateSummary:
+		p.getStateSummary(msg)
+	case StateSummary:
+		p.stateSummary(msg)
+	case GetStateChunk:
+		p.getStateChunk(msg)
+	case StateChunk:
+		p.stateChunk(msg)
+	case AppRequest:
+		p.appRequest(msg)
+	case AppResponse:
+		p.appResponse(msg)
+	case AppGossip:
+		p.appGossip(msg)
 	default:
 		p.net.log.Debug("dropping an unknown message from %s with op %s", p.id, op.String())
 	}
 }
 
-func (p *peer) dropMessagePeer() bool {
-	return atomic.LoadInt64(&p.pendingBytes) > p.net.maxMessageSize
-}
-
-func (p *peer) dropMessage(connPendingLen, networkPendingLen int64) bool {
-	return networkPendingLen > p.net.networkPendingSendBytesToRateLimit && // Check to see if we should be enforcing any rate limiting
-		p.dropMessagePeer() && // this connection should have a minimum allowed bandwidth
-		(networkPendingLen > p.net.maxNetworkPendingSendBytes || // Check to see if this message would put too much memory into the network
-			connPendingLen > p.net.maxNetworkPendingSendBytes/20) // Check to see if this connection is using too much memory
+// dropMessage returns true if a message of [priority] shouldn't be sent
+// because it would exceed the global budget of bytes waiting to be sent
+func (p *peer) dropMessage(priority Priority, connPendingLen, networkPendingLen int64) bool {
+	if networkPendingLen <= p.net.networkPendingSendBytesToRateLimit || // Check to see if we should be enforcing any rate limiting
+		connPendingLen <= p.net.maxMessageSize { // this connection should have a minimum allowed bandwidth
+		return false
+	}
+	if networkPendingLen > p.net.maxNetworkPendingSendBytes || // Check to see if this message would put too much memory into the network
+		connPendingLen > p.net.maxNetworkPendingSendBytes/20 { // Check to see if this connection is using too much memory
+		return true
+	}
+	// While rate limiting is enforced, only replies and queries may use more
+	// than the minimum bandwidth
+	return priority >= BootstrapPriority
 }
 
 // assumes the stateLock is not held
@@ -392,11 +537,10 @@ func (p *peer) close() {
 		p.net.log.Debug("closing peer %s resulted in an error: %s", p.id, err)
 	}
 
-	p.senderLock.Lock()
-	// The locks guarantee here that the sender routine will read that the peer
-	// has been closed and will therefore not attempt to write on this channel.
-	close(p.sender)
-	p.senderLock.Unlock()
+	// The messages that were never sent no longer count towards the bytes
+	// pending in the network
+	dropped := p.sendQueue.Close()
+	atomic.AddInt64(&p.net.pendingBytes, -dropped)
 
 	p.net.disconnected(p)
 }
@@ -410,14 +554,30 @@ func (p *peer) GetVersion() {
 
 // assumes the stateLock is not held
 func (p *peer) Version() {
-	p.net.stateLock.RLock()
-	msg, err := p.net.b.Version(
-		p.net.networkID,
-		p.net.nodeID,
-		p.net.clock.Unix(),
-		p.net.ip.IP(),
-		p.net.version.String(),
+	var (
+		msg Msg
+		err error
 	)
+	p.net.stateLock.RLock()
+	if p.versionWithSubnets.GetValue() {
+		msg, err = p.net.b.VersionWithSubnets(
+			p.net.networkID,
+			p.net.nodeID,
+			p.net.clock.Unix(),
+			p.net.ip.IP(),
+			p.net.version.String(),
+			p.net.subnetsToAdvertise(),
+			p.net.compressionEnabled,
+		)
+	} else {
+		msg, err = p.net.b.Version(
+			p.net.networkID,
+			p.net.nodeID,
+			p.net.clock.Unix(),
+			p.net.ip.IP(),
+			p.net.version.String(),
+		)
+	}
 	p.net.stateLock.RUnlock()
 	p.net.log.AssertNoError(err)
 	p.Send(msg)
@@ -430,10 +590,55 @@ func (p *peer) GetPeerList() {
 	p.Send(msg)
 }
 
+// SendPeerList sends the peer the IPs of the validators of the subnets we both
+// track. The list is sent even if it's empty, as it finishes the handshake.
+// assumes the stateLock is not held
+func (p *peer) SendPeerList() { p.sendPeerList(true) }
+
+// GossipPeerList sends the peer the IPs of the validators of the subnets we
+// both track, unless there are none.
+// assumes the stateLock is not held
+func (p *peer) GossipPeerList() {
+	if p.connected.GetValue() {
+		p.sendPeerList(false)
+	}
+}
+
+// assumes the stateLock is not held
+func (p *peer) sendPeerList(sendEmpty bool) {
+	if !p.net.isVisible(p.id) {
+		if !sendEmpty {
+			return
+		}
+		// the peer list must still be sent to finish the handshake
+		if p.signedIPs.GetValue() {
+			p.SignedPeerList(nil)
+		} else {
+			p.PeerList(nil)
+		}
+		return
+	}
+	if p.signedIPs.GetValue() {
+		signedIPs := p.net.signedValidatorIPs(p)
+		if len(signedIPs) > 0 || sendEmpty {
+			p.SignedPeerList(signedIPs)
+		}
+		return
+	}
+	ips := p.net.validatorIPs(p)
+	if len(ips) > 0 || sendEmpty {
+		p.PeerList(ips)
+	}
+}
+
 // assumes the stateLock is not held
-func (p *peer) SendPeerList() {
-	ips := p.net.validatorIPs()
-	p.PeerList(ips)
+func (p *peer) SignedPeerList(peers []*SignedIP) {
+	msg, err := p.net.b.SignedPeerList(peers)
+	if err != nil {
+		p.net.log.Warn("failed to send SignedPeerList message due to %s", err)
+		return
+	}
+	p.Send(msg)
 }
 
 // assumes the stateLock is not held
@@ -474,6 +679,11 @@ func (p *peer) getVersion(_ Msg) { p.Version() }
 // assumes the stateLock is not held
 func (p *peer) version(msg Msg) {
 	if p.gotVersion.GetValue() {
+		// The subnets the peer tracks may change after the handshake
+		if msg.Op() == VersionWithSubnets && p.setTrackedSubnets(msg) {
+			p.GossipPeerList()
+			return
+		}
 		p.net.log.Verbo("dropping duplicated version message from %s", p.id)
 		return
 	}
@@ -545,6 +755,24 @@ func (p *peer) version(msg Msg) {
 			peerVersion)
 	}
 
+	if !peerVersion.Before(minimumSubnetsVersion) && !p.versionWithSubnets.GetValue() {
+		// The peer sends its version again, with the subnets it tracks, once
+		// it gets our version with the subnets we track. The handshake
+		// continues with that message.
+		p.versionWithSubnets.SetValue(true)
+		p.Version()
+		if msg.Op() == Version {
+			return
+		}
+	}
+	if msg.Op() == Version && p.versionWithSubnets.GetValue() {
+		p.net.log.Verbo("waiting for the version with subnets of %s", p.id)
+		return
+	}
+	if msg.Op() == VersionWithSubnets && !p.setTrackedSubnets(msg) {
+		return
+	}
+
 	ip := p.getIP()
 	if ip.IsZero() {
 		// we only care about the claimed IP if we don't know the IP yet
@@ -562,10 +790,13 @@ func (p *peer) version(msg Msg) {
 		}
 	}
 
+	p.signedIPs.SetValue(p.net.stakingSigner != nil && !peerVersion.Before(minimumSignedIPVersion))
 	p.SendPeerList()
 
 	p.versionStruct.SetValue(peerVersion)
 	p.versionStr.SetValue(peerVersion.String())
+	p.compress.SetValue(p.net.compressionEnabled && msg.Op() == VersionWithSubnets && msg.Get(Compression).(bool))
+	p.proto.SetValue(!peerVersion.Before(minimumProtoVersion))
 	p.gotVersion.SetValue(true)
 
 	p.tryMarkConnected()
@@ -585,18 +816,113 @@ func (p *peer) peerList(msg Msg) {
 	p.gotPeerList.SetValue(true)
 	p.tryMarkConnected()
 
+	if p.signedIPs.GetValue() {
+		// peers that can sign their peer lists aren't trusted to send IPs
+		// without signatures
+		p.net.log.Verbo("ignoring unsigned peer list from %s", p.id)
+		return
+	}
+
 	for _, ip := range ips {
 		p.net.stateLock.Lock()
 		if !ip.Equal(p.net.ip.IP()) &&
 			!ip.IsZero() &&
 			(p.net.allowPrivateIPs || !ip.IsPrivate()) {
 			// TODO: only try to connect once
-			p.net.track(ip)
+			p.net.track(ip, ids.ShortEmpty)
 		}
 		p.net.stateLock.Unlock()
 	}
 }
 
+// assumes the stateLock is not held
+func (p *peer) signedPeerList(msg Msg) {
+	// Signed IPs are only handled once the peer has sent its version, so that
+	// an unknown peer can't make us verify signatures
+	if !p.gotVersion.GetValue() {
+		p.net.log.Debug("dropping signed peer list from %s because its version hasn't been received yet", p.id)
+		p.GetVersion()
+		return
+	}
+
+	signedIPs := msg.Get(SignedPeers).([]*SignedIP)
+	if len(signedIPs) > maxSignedPeerListSize {
+		p.net.log.Debug("peer %s sent %d signed IPs, which is more than the max of %d",
+			p.id,
+			len(signedIPs),
+			maxSignedPeerListSize)
+		p.net.Misbehaved(p.id, snow.InvalidMessage)
+		return
+	}
+
+	p.gotPeerList.SetValue(true)
+	p.tryMarkConnected()
+	if !p.connected.GetValue() {
+		return
+	}
+
+	invalidSignature := false
+	for _, signedIP := range signedIPs {
+		if err := p.net.trackSignedIP(signedIP); err != nil {
+			p.net.log.Debug("dropping signed IP %s from %s due to %s", signedIP.IP, p.id, err)
+			invalidSignature = invalidSignature || errors.Is(err, errInvalidSignedIPSignature)
+		}
+	}
+	// The peer is only penalized once, however many of the IPs it sent were
+	// signed incorrectly
+	if invalidSignature {
+		p.net.Misbehaved(p.id, snow.InvalidMessage)
+	}
+}
+
+// setTrackedSubnets sets the subnets the peer tracks to those listed in its
+// VersionWithSubnets message [msg]. Returns false if [msg] is invalid.
+// assumes the stateLock is not held
+func (p *peer) setTrackedSubnets(msg Msg) bool {
+	subnetIDsBytes := msg.Get(SubnetIDs).([][]byte)
+	if len(subnetIDsBytes) > maxTrackedSubnets {
+		// The peer may run a version that advertised every subnet it tracks
+		p.net.log.Debug("peer %s claims to track %d subnets, so only the first %d are tracked",
+			p.id,
+			len(subnetIDsBytes),
+			maxTrackedSubnets)
+		subnetIDsBytes = subnetIDsBytes[:maxTrackedSubnets]
+	}
+	subnetIDs := ids.Set{}
+	for _, subnetIDBytes := range subnetIDsBytes {
+		subnetID, err := ids.ToID(subnetIDBytes)
+		if err != nil {
+			p.net.log.Debug("error parsing SubnetID 0x%x: %s", subnetIDBytes, err)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
+			return false
+		}
+		subnetIDs.Add(subnetID)
+	}
+	p.trackedSubnets.SetValue(subnetIDs)
+	return true
+}
+
+// tracksSubnet returns true if the peer told us it tracks [subnetID]. Every
+// peer tracks the primary network.
+func (p *peer) tracksSubnet(subnetID ids.ID) bool {
+	if subnetID == constants.PrimaryNetworkID {
+		return true
+	}
+	subnetIDs, ok := p.trackedSubnets.GetValue().(ids.Set)
+	return ok && subnetIDs.Contains(subnetID)
+}
+
+// trackedSubnetIDs returns the subnets the peer told us it tracks, other than
+// the primary network
+func (p *peer) trackedSubnetIDs() []string {
+	subnetIDs, _ := p.trackedSubnets.GetValue().(ids.Set)
+	subnetIDStrs := make([]string, 0, subnetIDs.Len())
+	for subnetID := range subnetIDs {
+		subnetIDStrs = append(subnetIDStrs, subnetID.String())
+	}
+	return subnetIDStrs
+}
+
 // assumes the stateLock is not held
 func (p *peer) ping(_ Msg) { p.Pong() }
 
@@ -626,10 +952,12 @@ func (p *peer) acceptedFrontier(msg Msg) {
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
 		if containerIDsSet.Contains(containerID) {
 			p.net.log.Debug("message contains duplicate of container ID %s", containerID)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
 		containerIDs[i] = containerID
@@ -653,10 +981,12 @@ func (p *peer) getAccepted(msg Msg) {
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
 		if containerIDsSet.Contains(containerID) {
 			p.net.log.Debug("message contains duplicate of container ID %s", containerID)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
 		containerIDs[i] = containerID
@@ -679,10 +1009,12 @@ func (p *peer) accepted(msg Msg) {
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
 		if containerIDsSet.Contains(containerID) {
 			p.net.log.Debug("message contains duplicate of container ID %s", containerID)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
 		containerIDs[i] = containerID
@@ -775,10 +1107,12 @@ func (p *peer) chits(msg Msg) {
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
 		if containerIDsSet.Contains(containerID) {
 			p.net.log.Debug("message contains duplicate of container ID %s", containerID)
+			p.net.Misbehaved(p.id, snow.InvalidMessage)
 			return
 		}
 		containerIDs[i] = containerID
@@ -788,6 +1122,79 @@ func (p *peer) chits(msg Msg) {
 	p.net.router.Chits(p.id, chainID, requestID, containerIDs)
 }
 
+// assumes the stateLock is not held
+func (p *peer) getStateSummary(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
+
+	p.net.router.GetStateSummary(p.id, chainID, requestID, deadline)
+}
+
+// assumes the stateLock is not held
+func (p *peer) stateSummary(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	summary := msg.Get(ContainerBytes).([]byte)
+
+	p.net.router.StateSummary(p.id, chainID, requestID, summary)
+}
+
+// assumes the stateLock is not held
+func (p *peer) getStateChunk(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
+	summaryID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+	index := msg.Get(ChunkIndex).(uint32)
+
+	p.net.router.GetStateChunk(p.id, chainID, requestID, deadline, summaryID, index)
+}
+
+// assumes the stateLock is not held
+func (p *peer) stateChunk(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	chunk := msg.Get(ContainerBytes).([]byte)
+
+	p.net.router.StateChunk(p.id, chainID, requestID, chunk)
+}
+
+// assumes the stateLock is not held
+func (p *peer) appRequest(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	deadline := p.net.clock.Time().Add(time.Duration(msg.Get(Deadline).(uint64)))
+	appRequestBytes := msg.Get(AppBytes).([]byte)
+
+	p.net.router.AppRequest(p.id, chainID, requestID, deadline, appRequestBytes)
+}
+
+// assumes the stateLock is not held
+func (p *peer) appResponse(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	appResponseBytes := msg.Get(AppBytes).([]byte)
+
+	p.net.router.AppResponse(p.id, chainID, requestID, appResponseBytes)
+}
+
+// assumes the stateLock is not held
+func (p *peer) appGossip(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	appGossipBytes := msg.Get(AppBytes).([]byte)
+
+	p.net.router.AppGossip(p.id, chainID, appGossipBytes)
+}
+
 // assumes the stateLock is held
 func (p *peer) tryMarkConnected() {
 	if !p.connected.GetValue() && // not already connected
@@ -9,10 +9,11 @@ import (
 
 // PeerID ...
 type PeerID struct {
-	IP           string    `json:"ip"`
-	PublicIP     string    `json:"publicIP"`
-	ID           string    `json:"nodeID"`
-	Version      string    `json:"version"`
-	LastSent     time.Time `json:"lastSent"`
-	LastReceived time.Time `json:"lastReceived"`
+	IP             string    `json:"ip"`
+	PublicIP       string    `json:"publicIP"`
+	ID             string    `json:"nodeID"`
+	Version        string    `json:"version"`
+	LastSent       time.Time `json:"lastSent"`
+	LastReceived   time.Time `json:"lastReceived"`
+	TrackedSubnets []string  `json:"trackedSubnets"`
 }
@@ -0,0 +1,419 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/ava-labs/avalanchego/network/networkproto"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+// Messages can be sent in the format defined by networkproto, rather than
+// packed with wrappers.Packer. A peer is sent messages in the protobuf format
+// once its version message shows it can parse them. Messages in either format
+// are parsed into the same fields, so handling a message doesn't depend on its
+// format.
+
+// If the first byte of a message, other than [compressedFlag], is
+// [protoFlag], the rest of the message is a networkproto.Message
+const protoFlag byte = 1 << 6
+
+var (
+	errEmptyProtoMessage = errors.New("protobuf message doesn't contain a message")
+	errInvalidIP         = errors.New("invalid IP")
+)
+
+// packProto returns the message with opcode [op] and [fields] in the protobuf
+// format, without the first byte. Assumes [fields] were successfully packed
+// by a Codec.
+func packProto(op Op, fields map[Field]interface{}) ([]byte, error) {
+	m := &networkproto.Message{}
+	switch op {
+	case GetVersion:
+		m.Message = &networkproto.Message_GetVersion{GetVersion: &networkproto.GetVersion{}}
+	case Version:
+		m.Message = &networkproto.Message_Version_{Version_: &networkproto.Version{
+			NetworkID:  fields[NetworkID].(uint32),
+			NodeID:     fields[NodeID].(uint32),
+			MyTime:     fields[MyTime].(uint64),
+			Ip:         ipToProto(fields[IP].(utils.IPDesc)),
+			VersionStr: fields[VersionStr].(string),
+		}}
+	case GetPeerList:
+		m.Message = &networkproto.Message_GetPeerList{GetPeerList: &networkproto.GetPeerList{}}
+	case PeerList:
+		ips := fields[Peers].([]utils.IPDesc)
+		peers := make([]*networkproto.IPDesc, len(ips))
+		for i, ip := range ips {
+			peers[i] = ipToProto(ip)
+		}
+		m.Message = &networkproto.Message_PeerList_{PeerList_: &networkproto.PeerList{Peers: peers}}
+	case Ping:
+		m.Message = &networkproto.Message_Ping{Ping: &networkproto.Ping{}}
+	case Pong:
+		m.Message = &networkproto.Message_Pong{Pong: &networkproto.Pong{}}
+	case GetAcceptedFrontier:
+		m.Message = &networkproto.Message_GetAcceptedFrontier{GetAcceptedFrontier: &networkproto.GetAcceptedFrontier{
+			ChainID:   fields[ChainID].([]byte),
+			RequestID: fields[RequestID].(uint32),
+			Deadline:  fields[Deadline].(uint64),
+		}}
+	case AcceptedFrontier:
+		m.Message = &networkproto.Message_AcceptedFrontier_{AcceptedFrontier_: &networkproto.AcceptedFrontier{
+			ChainID:      fields[ChainID].([]byte),
+			RequestID:    fields[RequestID].(uint32),
+			ContainerIDs: fields[ContainerIDs].([][]byte),
+		}}
+	case GetAccepted:
+		m.Message = &networkproto.Message_GetAccepted{GetAccepted: &networkproto.GetAccepted{
+			ChainID:      fields[ChainID].([]byte),
+			RequestID:    fields[RequestID].(uint32),
+			Deadline:     fields[Deadline].(uint64),
+			ContainerIDs: fields[ContainerIDs].([][]byte),
+		}}
+	case Accepted:
+		m.Message = &networkproto.Message_Accepted_{Accepted_: &networkproto.Accepted{
+			ChainID:      fields[ChainID].([]byte),
+			RequestID:    fields[RequestID].(uint32),
+			ContainerIDs: fields[ContainerIDs].([][]byte),
+		}}
+	case GetAncestors:
+		m.Message = &networkproto.Message_GetAncestors{GetAncestors: &networkproto.GetAncestors{
+			ChainID:     fields[ChainID].([]byte),
+			RequestID:   fields[RequestID].(uint32),
+			Deadline:    fields[Deadline].(uint64),
+			ContainerID: fields[ContainerID].([]byte),
+		}}
+	case MultiPut:
+		m.Message = &networkproto.Message_MultiPut{MultiPut: &networkproto.MultiPut{
+			ChainID:    fields[ChainID].([]byte),
+			RequestID:  fields[RequestID].(uint32),
+			Containers: fields[MultiContainerBytes].([][]byte),
+		}}
+	case Get:
+		m.Message = &networkproto.Message_Get{Get: &networkproto.Get{
+			ChainID:     fields[ChainID].([]byte),
+			RequestID:   fields[RequestID].(uint32),
+			Deadline:    fields[Deadline].(uint64),
+			ContainerID: fields[ContainerID].([]byte),
+		}}
+	case Put:
+		m.Message = &networkproto.Message_Put{Put: &networkproto.Put{
+			ChainID:     fields[ChainID].([]byte),
+			RequestID:   fields[RequestID].(uint32),
+			ContainerID: fields[ContainerID].([]byte),
+			Container:   fields[ContainerBytes].([]byte),
+		}}
+	case PushQuery:
+		m.Message = &networkproto.Message_PushQuery{PushQuery: &networkproto.PushQuery{
+			ChainID:     fields[ChainID].([]byte),
+			RequestID:   fields[RequestID].(uint32),
+			Deadline:    fields[Deadline].(uint64),
+			ContainerID: fields[ContainerID].([]byte),
+			Container:   fields[ContainerBytes].([]byte),
+		}}
+	case PullQuery:
+		m.Message = &networkproto.Message_PullQuery{PullQuery: &networkproto.PullQuery{
+			ChainID:     fields[ChainID].([]byte),
+			RequestID:   fields[RequestID].(uint32),
+			Deadline:    fields[Deadline].(uint64),
+			ContainerID: fields[ContainerID].([]byte),
+		}}
+	case Chits:
+		m.Message = &networkproto.Message_Chits{Chits: &networkproto.Chits{
+			ChainID:      fields[ChainID].([]byte),
+			RequestID:    fields[RequestID].(uint32),
+			ContainerIDs: fields[ContainerIDs].([][]byte),
+		}}
+	case GetStateSummary:
+		m.Message = &networkproto.Message_GetStateSummary{GetStateSummary: &networkproto.GetStateSummary{
+			ChainID:   fields[ChainID].([]byte),
+			RequestID: fields[RequestID].(uint32),
+			Deadline:  fields[Deadline].(uint64),
+		}}
+	case StateSummary:
+		m.Message = &networkproto.Message_StateSummary_{StateSummary_: &networkproto.StateSummary{
+			ChainID:   fields[ChainID].([]byte),
+			RequestID: fields[RequestID].(uint32),
+			Summary:   fields[ContainerBytes].([]byte),
+		}}
+	case GetStateChunk:
+		m.Message = &networkproto.Message_GetStateChunk{GetStateChunk: &networkproto.GetStateChunk{
+			ChainID:    fields[ChainID].([]byte),
+			RequestID:  fields[RequestID].(uint32),
+			Deadline:   fields[Deadline].(uint64),
+			SummaryID:  fields[ContainerID].([]byte),
+			ChunkIndex: fields[ChunkIndex].(uint32),
+		}}
+	case StateChunk:
+		m.Message = &networkproto.Message_StateChunk_{StateChunk_: &networkproto.StateChunk{
+			ChainID:   fields[ChainID].([]byte),
+			RequestID: fields[RequestID].(uint32),
+			Chunk:     fields[ContainerBytes].([]byte),
+		}}
+	case AppRequest:
+		m.Message = &networkproto.Message_AppRequest{AppRequest: &networkproto.AppRequest{
+			ChainID:   fields[ChainID].([]byte),
+			RequestID: fields[RequestID].(uint32),
+			Deadline:  fields[Deadline].(uint64),
+			AppBytes:  fields[AppBytes].([]byte),
+		}}
+	case AppResponse:
+		m.Message = &networkproto.Message_AppResponse{AppResponse: &networkproto.AppResponse{
+			ChainID:   fields[ChainID].([]byte),
+			RequestID: fields[RequestID].(uint32),
+			AppBytes:  fields[AppBytes].([]byte),
+		}}
+	case AppGossip:
+		m.Message = &networkproto.Message_AppGossip{AppGossip: &networkproto.AppGossip{
+			ChainID:  fields[ChainID].([]byte),
+			AppBytes: fields[AppBytes].([]byte),
+		}}
+	case SignedPeerList:
+		signedIPs := fields[SignedPeers].([]*SignedIP)
+		peers := make([]*networkproto.SignedIP, len(signedIPs))
+		for i, signedIP := range signedIPs {
+			peers[i] = &networkproto.SignedIP{
+				Cert:      signedIP.Cert,
+				Ip:        ipToProto(signedIP.IP),
+				Timestamp: signedIP.Timestamp,
+				Signature: signedIP.Signature,
+			}
+		}
+		m.Message = &networkproto.Message_SignedPeerList{SignedPeerList: &networkproto.SignedPeerList{Peers: peers}}
+	case VersionWithSubnets:
+		m.Message = &networkproto.Message_VersionWithSubnets{VersionWithSubnets: &networkproto.VersionWithSubnets{
+			NetworkID:   fields[NetworkID].(uint32),
+			NodeID:      fields[NodeID].(uint32),
+			MyTime:      fields[MyTime].(uint64),
+			Ip:          ipToProto(fields[IP].(utils.IPDesc)),
+			VersionStr:  fields[VersionStr].(string),
+			SubnetIDs:   fields[SubnetIDs].([][]byte),
+			Compression: fields[Compression].(bool),
+		}}
+	default:
+		return nil, errBadOp
+	}
+	return proto.Marshal(m)
+}
+
+// parseProto returns the opcode and fields of the message [b], which is in
+// the protobuf format without the first byte
+func parseProto(b []byte) (Op, map[Field]interface{}, error) {
+	m := &networkproto.Message{}
+	if err := proto.Unmarshal(b, m); err != nil {
+		return 0, nil, err
+	}
+
+	switch m := m.Message.(type) {
+	case *networkproto.Message_GetVersion:
+		return GetVersion, map[Field]interface{}{}, nil
+	case *networkproto.Message_Version_:
+		ip, err := ipFromProto(m.Version_.Ip)
+		if err != nil {
+			return 0, nil, err
+		}
+		return Version, map[Field]interface{}{
+			NetworkID:  m.Version_.NetworkID,
+			NodeID:     m.Version_.NodeID,
+			MyTime:     m.Version_.MyTime,
+			IP:         ip,
+			VersionStr: m.Version_.VersionStr,
+		}, nil
+	case *networkproto.Message_GetPeerList:
+		return GetPeerList, map[Field]interface{}{}, nil
+	case *networkproto.Message_PeerList_:
+		ips := make([]utils.IPDesc, len(m.PeerList_.Peers))
+		for i, peer := range m.PeerList_.Peers {
+			ip, err := ipFromProto(peer)
+			if err != nil {
+				return 0, nil, err
+			}
+			ips[i] = ip
+		}
+		return PeerList, map[Field]interface{}{Peers: ips}, nil
+	case *networkproto.Message_Ping:
+		return Ping, map[Field]interface{}{}, nil
+	case *networkproto.Message_Pong:
+		return Pong, map[Field]interface{}{}, nil
+	case *networkproto.Message_GetAcceptedFrontier:
+		return GetAcceptedFrontier, map[Field]interface{}{
+			ChainID:   m.GetAcceptedFrontier.ChainID,
+			RequestID: m.GetAcceptedFrontier.RequestID,
+			Deadline:  m.GetAcceptedFrontier.Deadline,
+		}, nil
+	case *networkproto.Message_AcceptedFrontier_:
+		return AcceptedFrontier, map[Field]interface{}{
+			ChainID:      m.AcceptedFrontier_.ChainID,
+			RequestID:    m.AcceptedFrontier_.RequestID,
+			ContainerIDs: m.AcceptedFrontier_.ContainerIDs,
+		}, nil
+	case *networkproto.Message_GetAccepted:
+		return GetAccepted, map[Field]interface{}{
+			ChainID:      m.GetAccepted.ChainID,
+			RequestID:    m.GetAccepted.RequestID,
+			Deadline:     m.GetAccepted.Deadline,
+			ContainerIDs: m.GetAccepted.ContainerIDs,
+		}, nil
+	case *networkproto.Message_Accepted_:
+		return Accepted, map[Field]interface{}{
+			ChainID:      m.Accepted_.ChainID,
+			RequestID:    m.Accepted_.RequestID,
+			ContainerIDs: m.Accepted_.ContainerIDs,
+		}, nil
+	case *networkproto.Message_GetAncestors:
+		return GetAncestors, map[Field]interface{}{
+			ChainID:     m.GetAncestors.ChainID,
+			RequestID:   m.GetAncestors.RequestID,
+			Deadline:    m.GetAncestors.Deadline,
+			ContainerID: m.GetAncestors.ContainerID,
+		}, nil
+	case *networkproto.Message_MultiPut:
+		return MultiPut, map[Field]interface{}{
+			ChainID:             m.MultiPut.ChainID,
+			RequestID:           m.MultiPut.RequestID,
+			MultiContainerBytes: m.MultiPut.Containers,
+		}, nil
+	case *networkproto.Message_Get:
+		return Get, map[Field]interface{}{
+			ChainID:     m.Get.ChainID,
+			RequestID:   m.Get.RequestID,
+			Deadline:    m.Get.Deadline,
+			ContainerID: m.Get.ContainerID,
+		}, nil
+	case *networkproto.Message_Put:
+		return Put, map[Field]interface{}{
+			ChainID:        m.Put.ChainID,
+			RequestID:      m.Put.RequestID,
+			ContainerID:    m.Put.ContainerID,
+			ContainerBytes: m.Put.Container,
+		}, nil
+	case *networkproto.Message_PushQuery:
+		return PushQuery, map[Field]interface{}{
+			ChainID:        m.PushQuery.ChainID,
+			RequestID:      m.PushQuery.RequestID,
+			Deadline:       m.PushQuery.Deadline,
+			ContainerID:    m.PushQuery.ContainerID,
+			ContainerBytes: m.PushQuery.Container,
+		}, nil
+	case *networkproto.Message_PullQuery:
+		return PullQuery, map[Field]interface{}{
+			ChainID:     m.PullQuery.ChainID,
+			RequestID:   m.PullQuery.RequestID,
+			Deadline:    m.PullQuery.Deadline,
+			ContainerID: m.PullQuery.ContainerID,
+		}, nil
+	case *networkproto.Message_Chits:
+		return Chits, map[Field]interface{}{
+			ChainID:      m.Chits.ChainID,
+			RequestID:    m.Chits.RequestID,
+			ContainerIDs: m.Chits.ContainerIDs,
+		}, nil
+	case *networkproto.Message_GetStateSummary:
+		return GetStateSummary, map[Field]interface{}{
+			ChainID:   m.GetStateSummary.ChainID,
+			RequestID: m.GetStateSummary.RequestID,
+			Deadline:  m.GetStateSummary.Deadline,
+		}, nil
+	case *networkproto.Message_StateSummary_:
+		return StateSummary, map[Field]interface{}{
+			ChainID:        m.StateSummary_.ChainID,
+			RequestID:      m.StateSummary_.RequestID,
+			ContainerBytes: m.StateSummary_.Summary,
+		}, nil
+	case *networkproto.Message_GetStateChunk:
+		return GetStateChunk, map[Field]interface{}{
+			ChainID:     m.GetStateChunk.ChainID,
+			RequestID:   m.GetStateChunk.RequestID,
+			Deadline:    m.GetStateChunk.Deadline,
+			ContainerID: m.GetStateChunk.SummaryID,
+			ChunkIndex:  m.GetStateChunk.ChunkIndex,
+		}, nil
+	case *networkproto.Message_StateChunk_:
+		return StateChunk, map[Field]interface{}{
+			ChainID:        m.StateChunk_.ChainID,
+			RequestID:      m.StateChunk_.RequestID,
+			ContainerBytes: m.StateChunk_.Chunk,
+		}, nil
+	case *networkproto.Message_AppRequest:
+		return AppRequest, map[Field]interface{}{
+			ChainID:   m.AppRequest.ChainID,
+			RequestID: m.AppRequest.RequestID,
+			Deadline:  m.AppRequest.Deadline,
+			AppBytes:  m.AppRequest.AppBytes,
+		}, nil
+	case *networkproto.Message_AppResponse:
+		return AppResponse, map[Field]interface{}{
+			ChainID:   m.AppResponse.ChainID,
+			RequestID: m.AppResponse.RequestID,
+			AppBytes:  m.AppResponse.AppBytes,
+		}, nil
+	case *networkproto.Message_AppGossip:
+		return AppGossip, map[Field]interface{}{
+			ChainID:  m.AppGossip.ChainID,
+			AppBytes: m.AppGossip.AppBytes,
+		}, nil
+	case *networkproto.Message_SignedPeerList:
+		signedIPs := make([]*SignedIP, len(m.SignedPeerList.Peers))
+		for i, peer := range m.SignedPeerList.Peers {
+			ip, err := ipFromProto(peer.Ip)
+			if err != nil {
+				return 0, nil, err
+			}
+			signedIPs[i] = &SignedIP{
+				Cert:      peer.Cert,
+				IP:        ip,
+				Timestamp: peer.Timestamp,
+				Signature: peer.Signature,
+			}
+		}
+		return SignedPeerList, map[Field]interface{}{SignedPeers: signedIPs}, nil
+	case *networkproto.Message_VersionWithSubnets:
+		ip, err := ipFromProto(m.VersionWithSubnets.Ip)
+		if err != nil {
+			return 0, nil, err
+		}
+		return VersionWithSubnets, map[Field]interface{}{
+			NetworkID:   m.VersionWithSubnets.NetworkID,
+			NodeID:      m.VersionWithSubnets.NodeID,
+			MyTime:      m.VersionWithSubnets.MyTime,
+			IP:          ip,
+			VersionStr:  m.VersionWithSubnets.VersionStr,
+			SubnetIDs:   m.VersionWithSubnets.SubnetIDs,
+			Compression: m.VersionWithSubnets.Compression,
+		}, nil
+	case nil:
+		return 0, nil, errEmptyProtoMessage
+	default:
+		return 0, nil, errBadOp
+	}
+}
+
+func ipToProto(ip utils.IPDesc) *networkproto.IPDesc {
+	return &networkproto.IPDesc{
+		Ip:   ip.IP.To16(),
+		Port: uint32(ip.Port),
+	}
+}
+
+func ipFromProto(ip *networkproto.IPDesc) (utils.IPDesc, error) {
+	switch {
+	case ip == nil:
+		return utils.IPDesc{}, fmt.Errorf("%w: missing", errInvalidIP)
+	case len(ip.Ip) != net.IPv6len:
+		return utils.IPDesc{}, fmt.Errorf("%w: expected %d bytes but got %d", errInvalidIP, net.IPv6len, len(ip.Ip))
+	case ip.Port > math.MaxUint16:
+		return utils.IPDesc{}, fmt.Errorf("%w: port %d", errInvalidIP, ip.Port)
+	}
+	return utils.IPDesc{
+		IP:   net.IP(ip.Ip),
+		Port: uint16(ip.Port),
+	}, nil
+}
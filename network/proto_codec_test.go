@@ -0,0 +1,109 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/compression"
+)
+
+// Ensure every message parses into the same fields in the protobuf format as
+// in the packed format
+func TestProtoRoundTrip(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+	containerIDs := []ids.ID{containerID, ids.Empty.Prefix(2)}
+	container := []byte{1, 2, 3}
+	ip := utils.IPDesc{
+		IP:   net.IPv6loopback,
+		Port: 12345,
+	}
+
+	b := TestBuilder
+	msgs := []func() (Msg, error){
+		b.GetVersion,
+		func() (Msg, error) { return b.Version(1, 2, 3, ip, "xD") },
+		b.GetPeerList,
+		func() (Msg, error) { return b.PeerList([]utils.IPDesc{ip, ip}) },
+		b.Ping,
+		b.Pong,
+		func() (Msg, error) { return b.GetAcceptedFrontier(chainID, 1, 2) },
+		func() (Msg, error) { return b.AcceptedFrontier(chainID, 1, containerIDs) },
+		func() (Msg, error) { return b.GetAccepted(chainID, 1, 2, containerIDs) },
+		func() (Msg, error) { return b.Accepted(chainID, 1, containerIDs) },
+		func() (Msg, error) { return b.GetAncestors(chainID, 1, 2, containerID) },
+		func() (Msg, error) { return b.MultiPut(chainID, 1, [][]byte{container, container}) },
+		func() (Msg, error) { return b.Get(chainID, 1, 2, containerID) },
+		func() (Msg, error) { return b.Put(chainID, 1, containerID, container) },
+		func() (Msg, error) { return b.PushQuery(chainID, 1, 2, containerID, container) },
+		func() (Msg, error) { return b.PullQuery(chainID, 1, 2, containerID) },
+		func() (Msg, error) { return b.Chits(chainID, 1, containerIDs) },
+		func() (Msg, error) { return b.GetStateSummary(chainID, 1, 2) },
+		func() (Msg, error) { return b.StateSummary(chainID, 1, container) },
+		func() (Msg, error) { return b.GetStateChunk(chainID, 1, 2, containerID, 3) },
+		func() (Msg, error) { return b.StateChunk(chainID, 1, container) },
+		func() (Msg, error) { return b.AppRequest(chainID, 1, 2, container) },
+		func() (Msg, error) { return b.AppResponse(chainID, 1, container) },
+		func() (Msg, error) { return b.AppGossip(chainID, container) },
+		func() (Msg, error) {
+			return b.SignedPeerList([]*SignedIP{{Cert: container, IP: ip, Timestamp: 1, Signature: container}})
+		},
+		func() (Msg, error) { return b.VersionWithSubnets(1, 2, 3, ip, "xD", []ids.ID{chainID}, true) },
+	}
+	assert.Len(t, msgs, len(Messages))
+
+	for _, build := range msgs {
+		msg, err := build()
+		assert.NoError(t, err)
+
+		protoBytes := msg.ProtoBytes()
+		assert.NotNil(t, protoBytes)
+		assert.Equal(t, protoFlag, protoBytes[0])
+
+		parsedMsg, err := TestBuilder.Parse(protoBytes)
+		assert.NoError(t, err)
+		assert.Equal(t, msg.Op(), parsedMsg.Op())
+		for _, field := range Messages[msg.Op()] {
+			assert.Equal(t, msg.Get(field), parsedMsg.Get(field), "%s of %s", field, msg.Op())
+		}
+	}
+}
+
+func TestProtoCompressed(t *testing.T) {
+	codec := NewCodec(compression.NewGzipCompressor(int64(DefaultMaxMessageSize)))
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+	container := bytes.Repeat([]byte{1, 2, 3, 4}, 1024)
+
+	msg, err := Builder{Codec: codec}.Put(chainID, 1, containerID, container)
+	assert.NoError(t, err)
+	compressedBytes := msg.CompressedProtoBytes()
+	assert.NotNil(t, compressedBytes)
+	assert.Equal(t, protoFlag|compressedFlag, compressedBytes[0])
+	assert.Less(t, len(compressedBytes), len(msg.ProtoBytes()))
+
+	parsedMsg, err := codec.Parse(compressedBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, Put, parsedMsg.Op())
+	assert.Equal(t, container, parsedMsg.Get(ContainerBytes))
+	assert.Equal(t, msg.ProtoBytes(), parsedMsg.ProtoBytes())
+	assert.Equal(t, compressedBytes, parsedMsg.CompressedProtoBytes())
+}
+
+func TestProtoParseEmpty(t *testing.T) {
+	_, err := TestBuilder.Parse([]byte{protoFlag})
+	assert.Error(t, err)
+}
+
+func TestProtoParseInvalid(t *testing.T) {
+	_, err := TestBuilder.Parse([]byte{protoFlag, 0xff, 0xff})
+	assert.Error(t, err)
+}
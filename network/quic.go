@@ -0,0 +1,193 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+const (
+	// Protocol negotiated by QUIC connections between nodes
+	quicALPN = "avalanche"
+
+	// QUIC closes connections that are idle for a while, so keep-alives are
+	// sent between the pings of the network
+	quicKeepAlivePeriod = 10 * time.Second
+)
+
+var (
+	errStreamConn  = errors.New("messages must be sent and received on the streams of a QUIC connection")
+	errNotQUICConn = errors.New("connection isn't a QUIC connection")
+
+	_ StreamConn   = &quicConn{}
+	_ net.Listener = &quicListener{}
+)
+
+// quicConfig returns the config of QUIC connections. Peers may only open a
+// unidirectional stream for each priority.
+func quicConfig() *quic.Config {
+	return &quic.Config{
+		MaxIncomingStreams:    -1,
+		MaxIncomingUniStreams: int64(NumPriorities),
+		KeepAlivePeriod:       quicKeepAlivePeriod,
+	}
+}
+
+// quicTLSConfig returns a copy of [config] that QUIC connections can use
+func quicTLSConfig(config *tls.Config) *tls.Config {
+	config = config.Clone()
+	config.NextProtos = []string{quicALPN}
+	config.MinVersion = tls.VersionTLS13
+	return config
+}
+
+// quicConn is a QUIC connection to a peer. Each side opens a unidirectional
+// stream for each priority of the messages it sends.
+type quicConn struct {
+	conn quic.Connection
+
+	lock sync.Mutex
+	// read deadline of the streams opened by the peer
+	readDeadline time.Time
+	// streams opened by the peer
+	streams []quic.ReceiveStream
+}
+
+// Read isn't supported, as messages are received on the streams of the
+// connection
+func (*quicConn) Read([]byte) (int, error) { return 0, errStreamConn }
+
+// Write isn't supported, as messages are sent on the streams of the
+// connection
+func (*quicConn) Write([]byte) (int, error) { return 0, errStreamConn }
+
+func (c *quicConn) Close() error { return c.conn.CloseWithError(0, "") }
+
+func (c *quicConn) LocalAddr() net.Addr  { return c.conn.LocalAddr() }
+func (c *quicConn) RemoteAddr() net.Addr { return c.conn.RemoteAddr() }
+
+func (c *quicConn) SetDeadline(t time.Time) error { return c.SetReadDeadline(t) }
+
+// SetReadDeadline sets the read deadline of every stream opened by the peer,
+// including the streams it opens later.
+func (c *quicConn) SetReadDeadline(t time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.readDeadline = t
+	for _, stream := range c.streams {
+		if err := stream.SetReadDeadline(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetWriteDeadline is a no-op, as the network doesn't set write deadlines
+func (*quicConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *quicConn) OpenStream() (io.WriteCloser, error) {
+	return c.conn.OpenUniStreamSync(c.conn.Context())
+}
+
+// AcceptStream returns the next stream opened by the peer. Until the peer
+// opens its first stream, the read deadline bounds how long this waits.
+func (c *quicConn) AcceptStream() (io.Reader, error) {
+	ctx := c.conn.Context()
+	c.lock.Lock()
+	if deadline := c.readDeadline; len(c.streams) == 0 && !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	c.lock.Unlock()
+
+	stream, err := c.conn.AcceptUniStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := stream.SetReadDeadline(c.readDeadline); err != nil {
+		return nil, err
+	}
+	c.streams = append(c.streams, stream)
+	return stream, nil
+}
+
+// quicListener accepts QUIC connections from peers
+type quicListener struct {
+	listener *quic.Listener
+}
+
+// NewQUICListener returns a listener that accepts QUIC connections on [addr].
+// The connections are secured with [config].
+func NewQUICListener(addr string, config *tls.Config) (net.Listener, error) {
+	listener, err := quic.ListenAddr(addr, quicTLSConfig(config), quicConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &quicListener{listener: listener}, nil
+}
+
+func (l *quicListener) Accept() (net.Conn, error) {
+	conn, err := l.listener.Accept(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &quicConn{conn: conn}, nil
+}
+
+func (l *quicListener) Close() error   { return l.listener.Close() }
+func (l *quicListener) Addr() net.Addr { return l.listener.Addr() }
+
+type quicDialer struct {
+	config *tls.Config
+}
+
+// NewQUICDialer returns a Dialer that makes QUIC connections secured with
+// [config]
+func NewQUICDialer(config *tls.Config) Dialer {
+	return &quicDialer{config: quicTLSConfig(config)}
+}
+
+func (d *quicDialer) Dial(ip utils.IPDesc) (net.Conn, error) {
+	conn, err := quic.DialAddr(context.Background(), ip.String(), d.config, quicConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &quicConn{conn: conn}, nil
+}
+
+type quicUpgrader struct{}
+
+// NewQUICUpgrader returns an Upgrader of QUIC connections. QUIC connections
+// are secured by TLS when they're made, so upgrading them only returns the ID
+// of the peer.
+func NewQUICUpgrader() Upgrader { return quicUpgrader{} }
+
+func (quicUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, error) {
+	qConn, ok := conn.(*quicConn)
+	if !ok {
+		return ids.ShortID{}, nil, errNotQUICConn
+	}
+	connState := qConn.conn.ConnectionState().TLS
+	if len(connState.PeerCertificates) == 0 {
+		return ids.ShortID{}, nil, errNoCert
+	}
+	return certToID(connState.PeerCertificates[0].Raw), conn, nil
+}
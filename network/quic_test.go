@@ -0,0 +1,136 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+// newTestQUICNetwork returns a network that listens for QUIC connections on
+// the loopback interface, and its ID and IP
+func newTestQUICNetwork(t *testing.T, router *testHandler) (Network, ids.ShortID, utils.IPDesc) {
+	key := newTestECDSAKey(t)
+	cert := newTestStakingCert(t, key)
+	stakingCert := &tls.Certificate{
+		Certificate: [][]byte{cert},
+		PrivateKey:  key,
+	}
+	// #nosec G402
+	tlsConfig := &tls.Config{
+		Certificates:       []tls.Certificate{*stakingCert},
+		ClientAuth:         tls.RequireAnyClientCert,
+		InsecureSkipVerify: true,
+	}
+
+	listener, err := NewQUICListener("127.0.0.1:0", tlsConfig)
+	assert.NoError(t, err)
+	ip := utils.IPDesc{
+		IP:   net.IPv4(127, 0, 0, 1),
+		Port: uint16(listener.Addr().(*net.UDPAddr).Port),
+	}
+	id := certToID(cert)
+
+	n := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		logging.NoLog{},
+		id,
+		utils.NewDynamicIPDesc(ip.IP, ip.Port),
+		0,
+		version.NewDefaultVersion("app", 0, 1, 0),
+		version.NewDefaultParser(),
+		listener,
+		NewQUICDialer(tlsConfig),
+		NewQUICUpgrader(),
+		NewQUICUpgrader(),
+		validators.NewSet(),
+		validators.NewSet(),
+		router,
+		time.Duration(0),
+		0,
+		nil,
+		false,
+		0,
+		0,
+		time.Now(),
+		defaultSendQueueSize,
+		true,
+		InboundThrottlerConfig{},
+		stakingCert,
+		AllowlistConfig{},
+		nil,
+		nil,
+		ReputationConfig{},
+		nil,
+	)
+	go func() {
+		err := n.Dispatch()
+		assert.Error(t, err)
+	}()
+	return n, id, ip
+}
+
+func TestQUICLoopback(t *testing.T) {
+	var (
+		wg0 sync.WaitGroup
+		wg1 sync.WaitGroup
+
+		id0, id1 ids.ShortID
+	)
+	wg0.Add(1)
+	wg1.Add(1)
+
+	// The IDs are only read once both networks were made
+	ready := make(chan struct{})
+	handler0 := &testHandler{
+		connected: func(id ids.ShortID) {
+			<-ready
+			assert.Equal(t, id1, id)
+			wg0.Done()
+		},
+	}
+	handler1 := &testHandler{
+		connected: func(id ids.ShortID) {
+			<-ready
+			assert.Equal(t, id0, id)
+			wg1.Done()
+		},
+	}
+
+	net0, nodeID0, _ := newTestQUICNetwork(t, handler0)
+	net1, nodeID1, ip1 := newTestQUICNetwork(t, handler1)
+	id0, id1 = nodeID0, nodeID1
+	close(ready)
+
+	// Both networks are only connected once they finished the handshake, which
+	// is sent on several streams of the connection
+	net0.Track(ip1)
+
+	wg0.Wait()
+	wg1.Wait()
+
+	peers := net0.Peers(nil)
+	assert.Len(t, peers, 1)
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestQUICUpgraderRejectsOtherConns(t *testing.T) {
+	_, _, err := NewQUICUpgrader().Upgrade(&testConn{})
+	assert.Equal(t, errNotQUICConn, err)
+}
@@ -0,0 +1,321 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Once this many peers have scores, the scores that have decayed to almost 0
+// are dropped
+const maxUnprunedScores = 1024
+
+var (
+	errBanned           = errors.New("node is banned")
+	errNotBanned        = errors.New("node isn't banned")
+	errInvalidBanLength = errors.New("ban length must be positive")
+
+	// misbehavior --> amount the score of a peer falls by when it misbehaves
+	misbehaviorPenalties = map[snow.Misbehavior]float64{
+		snow.InvalidMessage:      20,
+		snow.InvalidContainer:    20,
+		snow.UnrequestedResponse: 5,
+		// A peer may be benched because of a slow network rather than because
+		// it misbehaved, so being benched counts for little
+		snow.Benched: 2,
+	}
+)
+
+// ReputationConfig describes how peers are scored and when they're banned.
+// Each peer's score starts at 0, falls each time the peer misbehaves and
+// decays back towards 0 over time.
+type ReputationConfig struct {
+	// Peers whose score falls below this are banned
+	BanThreshold float64
+
+	// How long peers are banned for. If 0, peers aren't banned.
+	BanDuration time.Duration
+
+	// Time it takes for a score to decay halfway back to 0
+	HalfLife time.Duration
+}
+
+// score of a peer at a point in time
+type score struct {
+	value   float64
+	updated time.Time
+}
+
+// reputation keeps the scores of peers and the peers that are banned. The
+// bans are persisted to a database, if one is given, so they survive
+// restarts.
+type reputation struct {
+	config ReputationConfig
+	clock  timer.Clock
+
+	// nil if bans aren't persisted
+	db database.Database
+
+	lock sync.Mutex
+	// node ID --> score of that node
+	scores map[ids.ShortID]*score
+	// node ID --> time the ban of that node ends
+	bans map[ids.ShortID]time.Time
+}
+
+// newReputation returns a reputation that loads and persists its bans to
+// [db], which may be nil
+func newReputation(config ReputationConfig, db database.Database) (*reputation, error) {
+	r := &reputation{
+		config: config,
+		db:     db,
+		scores: make(map[ids.ShortID]*score),
+		bans:   make(map[ids.ShortID]time.Time),
+	}
+	if db == nil {
+		return r, nil
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	now := r.clock.Time()
+	expired := []ids.ShortID(nil)
+	for it.Next() {
+		nodeID, err := ids.ToShortID(it.Key())
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse banned node ID: %w", err)
+		}
+		p := wrappers.Packer{Bytes: it.Value()}
+		until := time.Unix(int64(p.UnpackLong()), 0)
+		if p.Errored() {
+			return nil, fmt.Errorf("couldn't parse the ban of %s: %w", nodeID, p.Err)
+		}
+		if until.After(now) {
+			r.bans[nodeID] = until
+		} else {
+			expired = append(expired, nodeID)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	for _, nodeID := range expired {
+		if err := db.Delete(nodeID.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// misbehaved lowers the score of [nodeID] for [misbehavior]. Returns true if
+// [nodeID] was banned as a result.
+func (r *reputation) misbehaved(nodeID ids.ShortID, misbehavior snow.Misbehavior) (bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Time()
+	s, ok := r.scores[nodeID]
+	if !ok {
+		if len(r.scores) >= maxUnprunedScores {
+			r.prune(now)
+		}
+		s = &score{updated: now}
+		r.scores[nodeID] = s
+	}
+	r.decay(s, now)
+	s.value -= misbehaviorPenalties[misbehavior]
+
+	if r.config.BanDuration <= 0 || s.value >= r.config.BanThreshold || r.banned(nodeID, now) {
+		return false, nil
+	}
+	delete(r.scores, nodeID)
+	return true, r.ban(nodeID, now.Add(r.config.BanDuration))
+}
+
+// Score returns the current score of [nodeID]
+func (r *reputation) Score(nodeID ids.ShortID) float64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s, ok := r.scores[nodeID]
+	if !ok {
+		return 0
+	}
+	r.decay(s, r.clock.Time())
+	return s.value
+}
+
+// Ban [nodeID] for [duration]
+func (r *reputation) Ban(nodeID ids.ShortID, duration time.Duration) error {
+	if duration <= 0 {
+		return errInvalidBanLength
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.scores, nodeID)
+	return r.ban(nodeID, r.clock.Time().Add(duration))
+}
+
+// Unban [nodeID]
+func (r *reputation) Unban(nodeID ids.ShortID) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.banned(nodeID, r.clock.Time()) {
+		return fmt.Errorf("%w: %s", errNotBanned, nodeID.PrefixedString(constants.NodeIDPrefix))
+	}
+	return r.unban(nodeID)
+}
+
+// Banned returns true if [nodeID] is banned
+func (r *reputation) Banned(nodeID ids.ShortID) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.banned(nodeID, r.clock.Time())
+}
+
+// Bans returns the banned nodes and the times their bans end
+func (r *reputation) Bans() map[ids.ShortID]time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Time()
+	bans := make(map[ids.ShortID]time.Time, len(r.bans))
+	for nodeID := range r.bans {
+		if r.banned(nodeID, now) {
+			bans[nodeID] = r.bans[nodeID]
+		}
+	}
+	return bans
+}
+
+// banned returns true if [nodeID] is banned at [now]. Removes the ban if it
+// ended.
+// assumes the lock is held.
+func (r *reputation) banned(nodeID ids.ShortID, now time.Time) bool {
+	until, ok := r.bans[nodeID]
+	if !ok {
+		return false
+	}
+	if now.Before(until) {
+		return true
+	}
+	// The error is ignored, as the ban is dropped when it's next loaded
+	_ = r.unban(nodeID)
+	return false
+}
+
+// assumes the lock is held.
+func (r *reputation) ban(nodeID ids.ShortID, until time.Time) error {
+	r.bans[nodeID] = until
+	if r.db == nil {
+		return nil
+	}
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(uint64(until.Unix()))
+	return r.db.Put(nodeID.Bytes(), p.Bytes)
+}
+
+// assumes the lock is held.
+func (r *reputation) unban(nodeID ids.ShortID) error {
+	delete(r.bans, nodeID)
+	if r.db == nil {
+		return nil
+	}
+	return r.db.Delete(nodeID.Bytes())
+}
+
+// decay [s] to what it is at [now]
+// assumes the lock is held.
+func (r *reputation) decay(s *score, now time.Time) {
+	if elapsed := now.Sub(s.updated); elapsed > 0 && r.config.HalfLife > 0 {
+		s.value *= math.Pow(0.5, float64(elapsed)/float64(r.config.HalfLife))
+	}
+	s.updated = now
+}
+
+// prune drops the scores that have decayed to almost 0
+// assumes the lock is held.
+func (r *reputation) prune(now time.Time) {
+	for nodeID, s := range r.scores {
+		r.decay(s, now)
+		if s.value > -1 {
+			delete(r.scores, nodeID)
+		}
+	}
+}
+
+// Misbehaved lowers the score of [nodeID] and, if its score falls below the
+// ban threshold, disconnects from and bans it.
+func (n *network) Misbehaved(nodeID ids.ShortID, misbehavior snow.Misbehavior) {
+	n.numMisbehaviors.Inc()
+	n.log.Debug("%s misbehaved: %s", nodeID.PrefixedString(constants.NodeIDPrefix), misbehavior)
+
+	banned, err := n.reputation.misbehaved(nodeID, misbehavior)
+	if err != nil {
+		n.log.Error("failed to persist the ban of %s: %s", nodeID.PrefixedString(constants.NodeIDPrefix), err)
+	}
+	if !banned {
+		return
+	}
+	n.log.Info("banning %s for %s after it misbehaved", nodeID.PrefixedString(constants.NodeIDPrefix), n.reputation.config.BanDuration)
+	n.disconnectBanned(nodeID)
+}
+
+// Ban disconnects from [nodeID] and doesn't let it connect for [duration]
+func (n *network) Ban(nodeID ids.ShortID, duration time.Duration) error {
+	if err := n.reputation.Ban(nodeID, duration); err != nil {
+		return err
+	}
+	n.log.Info("banning %s for %s", nodeID.PrefixedString(constants.NodeIDPrefix), duration)
+	n.disconnectBanned(nodeID)
+	return nil
+}
+
+// Unban lets [nodeID] connect again
+func (n *network) Unban(nodeID ids.ShortID) error {
+	if err := n.reputation.Unban(nodeID); err != nil {
+		return err
+	}
+	n.numBanned.Set(float64(len(n.reputation.Bans())))
+	n.log.Info("unbanned %s", nodeID.PrefixedString(constants.NodeIDPrefix))
+	return nil
+}
+
+// Bans returns the banned nodes and the times their bans end
+func (n *network) Bans() map[ids.ShortID]time.Time {
+	bans := n.reputation.Bans()
+	n.numBanned.Set(float64(len(bans)))
+	return bans
+}
+
+// disconnectBanned closes the connection to [nodeID], which was just banned.
+// This may be called by the engine while it holds the context lock, so the
+// peer is closed on another goroutine.
+// assumes the stateLock is not held.
+func (n *network) disconnectBanned(nodeID ids.ShortID) {
+	n.numBanned.Set(float64(len(n.reputation.Bans())))
+
+	n.stateLock.RLock()
+	peer, ok := n.peers[nodeID]
+	n.stateLock.RUnlock()
+	if ok {
+		go peer.Close()
+	}
+}
@@ -0,0 +1,130 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+)
+
+func TestReputationDecay(t *testing.T) {
+	r, err := newReputation(ReputationConfig{
+		BanThreshold: -100,
+		BanDuration:  time.Hour,
+		HalfLife:     time.Minute,
+	}, nil)
+	assert.NoError(t, err)
+	now := time.Unix(1000, 0)
+	r.clock.Set(now)
+
+	nodeID := ids.GenerateTestShortID()
+	assert.Zero(t, r.Score(nodeID))
+
+	banned, err := r.misbehaved(nodeID, snow.InvalidMessage)
+	assert.NoError(t, err)
+	assert.False(t, banned)
+	assert.Equal(t, -20.0, r.Score(nodeID))
+
+	// After one half-life, half of the penalty is forgiven
+	r.clock.Set(now.Add(time.Minute))
+	assert.InDelta(t, -10.0, r.Score(nodeID), 0.001)
+
+	banned, err = r.misbehaved(nodeID, snow.UnrequestedResponse)
+	assert.NoError(t, err)
+	assert.False(t, banned)
+	assert.InDelta(t, -15.0, r.Score(nodeID), 0.001)
+}
+
+func TestReputationBan(t *testing.T) {
+	r, err := newReputation(ReputationConfig{
+		BanThreshold: -50,
+		BanDuration:  time.Hour,
+		HalfLife:     time.Minute,
+	}, nil)
+	assert.NoError(t, err)
+	now := time.Unix(1000, 0)
+	r.clock.Set(now)
+
+	nodeID := ids.GenerateTestShortID()
+	for i := 0; i < 2; i++ {
+		banned, err := r.misbehaved(nodeID, snow.InvalidContainer)
+		assert.NoError(t, err)
+		assert.False(t, banned)
+	}
+	banned, err := r.misbehaved(nodeID, snow.InvalidContainer)
+	assert.NoError(t, err)
+	assert.True(t, banned)
+	assert.True(t, r.Banned(nodeID))
+	assert.Equal(t, map[ids.ShortID]time.Time{nodeID: now.Add(time.Hour)}, r.Bans())
+
+	// The score is reset by the ban
+	assert.Zero(t, r.Score(nodeID))
+
+	// The ban ends after its duration
+	r.clock.Set(now.Add(time.Hour))
+	assert.False(t, r.Banned(nodeID))
+	assert.Empty(t, r.Bans())
+}
+
+func TestReputationNoBans(t *testing.T) {
+	r, err := newReputation(ReputationConfig{BanThreshold: -1}, nil)
+	assert.NoError(t, err)
+
+	nodeID := ids.GenerateTestShortID()
+	banned, err := r.misbehaved(nodeID, snow.InvalidMessage)
+	assert.NoError(t, err)
+	assert.False(t, banned)
+	assert.False(t, r.Banned(nodeID))
+}
+
+func TestReputationManualBans(t *testing.T) {
+	r, err := newReputation(ReputationConfig{}, nil)
+	assert.NoError(t, err)
+
+	nodeID := ids.GenerateTestShortID()
+	assert.True(t, errors.Is(r.Unban(nodeID), errNotBanned))
+	assert.Equal(t, errInvalidBanLength, r.Ban(nodeID, 0))
+
+	assert.NoError(t, r.Ban(nodeID, time.Minute))
+	assert.True(t, r.Banned(nodeID))
+	assert.NoError(t, r.Unban(nodeID))
+	assert.False(t, r.Banned(nodeID))
+}
+
+func TestReputationPersistsBans(t *testing.T) {
+	db := memdb.New()
+	r, err := newReputation(ReputationConfig{}, db)
+	assert.NoError(t, err)
+	now := r.clock.Time()
+
+	nodeID0 := ids.GenerateTestShortID()
+	nodeID1 := ids.GenerateTestShortID()
+	nodeID2 := ids.GenerateTestShortID()
+	// Mimic a ban that ended while the node was down
+	assert.NoError(t, r.ban(nodeID0, now.Add(-time.Minute)))
+	assert.NoError(t, r.Ban(nodeID1, time.Hour))
+	assert.NoError(t, r.Ban(nodeID2, time.Hour))
+	assert.NoError(t, r.Unban(nodeID2))
+
+	// Bans that ended are dropped when the bans are loaded
+	r, err = newReputation(ReputationConfig{}, db)
+	assert.NoError(t, err)
+	bans := r.Bans()
+	assert.Len(t, bans, 1)
+	assert.Equal(t, now.Add(time.Hour).Unix(), bans[nodeID1].Unix())
+
+	has, err := db.Has(nodeID0.Bytes())
+	assert.NoError(t, err)
+	assert.False(t, has)
+	has, err = db.Has(nodeID2.Bytes())
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
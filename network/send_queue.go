@@ -0,0 +1,205 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// Priority of a message waiting to be sent. Messages of a higher priority are
+// sent first, and each priority has its own limit on the bytes that may be
+// waiting to be sent to a peer, so that a backlog of one kind of message
+// doesn't cause the messages consensus depends on to be dropped.
+type Priority byte
+
+// Priorities from highest to lowest
+const (
+	// Handshake messages and replies to queries
+	ReplyPriority Priority = iota
+	// Consensus queries
+	QueryPriority
+	// Bootstrapping and state sync requests and replies
+	BootstrapPriority
+	// Peer lists and gossip
+	GossipPriority
+
+	NumPriorities = int(GossipPriority) + 1
+)
+
+func (p Priority) String() string {
+	switch p {
+	case ReplyPriority:
+		return "reply"
+	case QueryPriority:
+		return "query"
+	case BootstrapPriority:
+		return "bootstrap"
+	case GossipPriority:
+		return "gossip"
+	default:
+		return "Unknown Priority"
+	}
+}
+
+// DefaultPeerPendingSendBytes is the default max number of bytes of each
+// priority that may be waiting to be sent to a peer
+var DefaultPeerPendingSendBytes = [NumPriorities]int64{
+	ReplyPriority:     2 * int64(DefaultMaxMessageSize),
+	QueryPriority:     int64(DefaultMaxMessageSize),
+	BootstrapPriority: 2 * int64(DefaultMaxMessageSize),
+	GossipPriority:    int64(DefaultMaxMessageSize) / 2,
+}
+
+// msgPriority returns the priority that [msg] is sent with
+func msgPriority(msg Msg) Priority {
+	switch msg.Op() {
+	case GetVersion, Version, VersionWithSubnets, Ping, Pong, Chits, AppResponse:
+		return ReplyPriority
+	case Put:
+		// Put is both the reply to Get and the message containers are gossiped
+		// in
+		if msg.Get(RequestID).(uint32) == constants.GossipMsgRequestID {
+			return GossipPriority
+		}
+		return ReplyPriority
+	case PushQuery, PullQuery, Get, AppRequest:
+		return QueryPriority
+	case GetAcceptedFrontier, AcceptedFrontier, GetAccepted, Accepted,
+		GetAncestors, MultiPut, GetStateSummary, StateSummary, GetStateChunk,
+		StateChunk:
+		return BootstrapPriority
+	default:
+		return GossipPriority
+	}
+}
+
+// sendQueue holds the messages waiting to be sent to a peer, in a queue for
+// each priority
+type sendQueue struct {
+	lock sync.Mutex
+	// signalled when a message is pushed or the queue is closed
+	cond *sync.Cond
+
+	closed bool
+
+	// max number of messages in the queues
+	maxMsgs int
+	numMsgs int
+
+	// priority --> messages of that priority, oldest first
+	msgs [NumPriorities][][]byte
+	// priority --> number of bytes in the messages of that priority
+	bytes [NumPriorities]int64
+}
+
+func newSendQueue(maxMsgs int) *sendQueue {
+	q := &sendQueue{maxMsgs: maxMsgs}
+	q.cond = sync.NewCond(&q.lock)
+	return q
+}
+
+// Push adds [msg] to the queue of [priority] if it wouldn't cause the queue to
+// hold more than [maxBytes] bytes. A message is always added to an empty queue
+// of its priority, so that messages larger than [maxBytes] can be sent. Returns
+// true if the message was added.
+func (q *sendQueue) Push(msg []byte, priority Priority, maxBytes int64) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	msgLen := int64(len(msg))
+	switch {
+	case q.closed, q.numMsgs >= q.maxMsgs:
+		return false
+	case q.bytes[priority] > 0 && q.bytes[priority]+msgLen > maxBytes:
+		return false
+	}
+
+	q.msgs[priority] = append(q.msgs[priority], msg)
+	q.bytes[priority] += msgLen
+	q.numMsgs++
+	// Wake every waiter, as each may be waiting for a different priority
+	q.cond.Broadcast()
+	return true
+}
+
+// Pop removes and returns the oldest message of the highest priority. Blocks
+// until there is a message. Returns false if the queue was closed.
+func (q *sendQueue) Pop() ([]byte, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for !q.closed && q.numMsgs == 0 {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, false
+	}
+
+	for priority, msgs := range q.msgs {
+		if len(msgs) == 0 {
+			continue
+		}
+		msg := msgs[0]
+		msgs[0] = nil
+		q.msgs[priority] = msgs[1:]
+		q.bytes[priority] -= int64(len(msg))
+		q.numMsgs--
+		return msg, true
+	}
+	return nil, false
+}
+
+// PopPriority removes and returns the oldest message of [priority]. Blocks
+// until there is one. Returns false if the queue was closed.
+func (q *sendQueue) PopPriority(priority Priority) ([]byte, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for !q.closed && len(q.msgs[priority]) == 0 {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, false
+	}
+
+	msgs := q.msgs[priority]
+	msg := msgs[0]
+	msgs[0] = nil
+	q.msgs[priority] = msgs[1:]
+	q.bytes[priority] -= int64(len(msg))
+	q.numMsgs--
+	return msg, true
+}
+
+// Bytes returns the number of bytes in the queue
+func (q *sendQueue) Bytes() int64 {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	total := int64(0)
+	for _, bytes := range q.bytes {
+		total += bytes
+	}
+	return total
+}
+
+// Close the queue, dropping the messages in it. Returns the number of bytes
+// that were dropped.
+func (q *sendQueue) Close() int64 {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	dropped := int64(0)
+	for priority, bytes := range q.bytes {
+		dropped += bytes
+		q.msgs[priority] = nil
+		q.bytes[priority] = 0
+	}
+	q.numMsgs = 0
+	q.closed = true
+	q.cond.Broadcast()
+	return dropped
+}
@@ -0,0 +1,128 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+func TestSendQueuePriorities(t *testing.T) {
+	q := newSendQueue(10)
+
+	assert.True(t, q.Push([]byte{3}, GossipPriority, 10))
+	assert.True(t, q.Push([]byte{1}, QueryPriority, 10))
+	assert.True(t, q.Push([]byte{0}, ReplyPriority, 10))
+	assert.True(t, q.Push([]byte{2}, QueryPriority, 10))
+	assert.Equal(t, int64(4), q.Bytes())
+
+	// Messages of higher priorities are sent first, and messages of the same
+	// priority are sent in order
+	for i := byte(0); i < 4; i++ {
+		msg, ok := q.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, []byte{i}, msg)
+	}
+	assert.Zero(t, q.Bytes())
+}
+
+func TestSendQueueLimits(t *testing.T) {
+	q := newSendQueue(3)
+
+	// A message is always added to an empty queue
+	assert.True(t, q.Push(make([]byte, 5), GossipPriority, 4))
+	assert.False(t, q.Push(make([]byte, 1), GossipPriority, 4))
+
+	// A full queue of one priority doesn't affect the others
+	assert.True(t, q.Push(make([]byte, 2), ReplyPriority, 4))
+	assert.True(t, q.Push(make([]byte, 2), ReplyPriority, 4))
+
+	// The queue holds at most [maxMsgs] messages
+	assert.False(t, q.Push(make([]byte, 1), QueryPriority, 4))
+}
+
+func TestSendQueueClose(t *testing.T) {
+	// Closing the queue unblocks Pop
+	q := newSendQueue(10)
+	popped := make(chan bool)
+	go func() {
+		_, ok := q.Pop()
+		popped <- ok
+	}()
+	assert.Zero(t, q.Close())
+	assert.False(t, <-popped)
+
+	// Closing the queue drops the messages in it
+	q = newSendQueue(10)
+	assert.True(t, q.Push(make([]byte, 5), ReplyPriority, 10))
+	assert.True(t, q.Push(make([]byte, 3), GossipPriority, 10))
+	assert.Equal(t, int64(8), q.Close())
+	assert.False(t, q.Push(make([]byte, 1), ReplyPriority, 10))
+	_, ok := q.Pop()
+	assert.False(t, ok)
+}
+
+func TestSendQueuePopPriority(t *testing.T) {
+	q := newSendQueue(10)
+
+	// Popping a priority waits for a message of that priority
+	popped := make(chan []byte)
+	go func() {
+		msg, ok := q.PopPriority(GossipPriority)
+		assert.True(t, ok)
+		popped <- msg
+	}()
+	assert.True(t, q.Push([]byte{0}, ReplyPriority, 10))
+	assert.True(t, q.Push([]byte{1}, GossipPriority, 10))
+	assert.Equal(t, []byte{1}, <-popped)
+	assert.Equal(t, int64(1), q.Bytes())
+
+	msg, ok := q.PopPriority(ReplyPriority)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0}, msg)
+	assert.Zero(t, q.Bytes())
+
+	// Closing the queue unblocks PopPriority
+	closed := make(chan bool)
+	go func() {
+		_, ok := q.PopPriority(QueryPriority)
+		closed <- ok
+	}()
+	assert.Zero(t, q.Close())
+	assert.False(t, <-closed)
+}
+
+func TestMsgPriority(t *testing.T) {
+	b := Builder{}
+	chainID := ids.GenerateTestID()
+	containerID := ids.GenerateTestID()
+
+	msg, err := b.Chits(chainID, 1, []ids.ID{containerID})
+	assert.NoError(t, err)
+	assert.Equal(t, ReplyPriority, msgPriority(msg))
+
+	msg, err = b.Put(chainID, 1, containerID, []byte{1})
+	assert.NoError(t, err)
+	assert.Equal(t, ReplyPriority, msgPriority(msg))
+
+	msg, err = b.PullQuery(chainID, 1, 1, containerID)
+	assert.NoError(t, err)
+	assert.Equal(t, QueryPriority, msgPriority(msg))
+
+	msg, err = b.MultiPut(chainID, 1, [][]byte{{1}})
+	assert.NoError(t, err)
+	assert.Equal(t, BootstrapPriority, msgPriority(msg))
+
+	msg, err = b.Put(chainID, constants.GossipMsgRequestID, containerID, []byte{1})
+	assert.NoError(t, err)
+	assert.Equal(t, GossipPriority, msgPriority(msg))
+
+	msg, err = b.GetPeerList()
+	assert.NoError(t, err)
+	assert.Equal(t, GossipPriority, msgPriority(msg))
+}
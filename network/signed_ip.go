@@ -0,0 +1,134 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// Max number of signed IPs in a peer list. Each signed IP may need its signature
+// verified, so larger peer lists are dropped.
+const maxSignedPeerListSize = 1024
+
+var (
+	errBadSignedIPsType         = errors.New("wrong type for signed IPs")
+	errUnsupportedStakingKey    = errors.New("unsupported staking key type")
+	errInvalidSignedIPSignature = errors.New("invalid signed IP signature")
+)
+
+// SignedIP is an IP that a node claims to be reachable at, signed with the
+// node's staking key. Because the ID of the node is derived from its
+// certificate, as it is when the node's connection is upgraded, only the node
+// itself can sign an IP for its ID.
+type SignedIP struct {
+	// Staking certificate of the node, in DER form
+	Cert []byte
+	IP   utils.IPDesc
+	// Unix time at which the IP was signed. Of two IPs signed by a node, the
+	// one signed later is the node's current IP.
+	Timestamp uint64
+	Signature []byte
+}
+
+// newSignedIP returns [ip] signed at [timestamp] by the node with the staking
+// certificate [cert] and key [signer]
+func newSignedIP(cert []byte, signer crypto.Signer, ip utils.IPDesc, timestamp uint64) (*SignedIP, error) {
+	signedIP := &SignedIP{
+		Cert:      cert,
+		IP:        ip,
+		Timestamp: timestamp,
+	}
+	unsignedBytes, err := signedIP.unsignedBytes()
+	if err != nil {
+		return nil, err
+	}
+	signedIP.Signature, err = signer.Sign(rand.Reader, hashing.ComputeHash256(unsignedBytes), crypto.SHA256)
+	return signedIP, err
+}
+
+// unsignedBytes returns the bytes that are signed
+func (ip *SignedIP) unsignedBytes() ([]byte, error) {
+	p := wrappers.Packer{MaxSize: net.IPv6len + wrappers.ShortLen + wrappers.LongLen}
+	p.PackIP(ip.IP)
+	p.PackLong(ip.Timestamp)
+	return p.Bytes, p.Err
+}
+
+// Verify returns the ID of the node that signed [ip]. Returns an error if the
+// signature isn't valid.
+func (ip *SignedIP) Verify() (ids.ShortID, error) {
+	cert, err := x509.ParseCertificate(ip.Cert)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+
+	var algorithm x509.SignatureAlgorithm
+	switch cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		algorithm = x509.ECDSAWithSHA256
+	default:
+		return ids.ShortID{}, fmt.Errorf("%w: %T", errUnsupportedStakingKey, cert.PublicKey)
+	}
+
+	unsignedBytes, err := ip.unsignedBytes()
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	if err := cert.CheckSignature(algorithm, unsignedBytes, ip.Signature); err != nil {
+		return ids.ShortID{}, fmt.Errorf("%w: %s", errInvalidSignedIPSignature, err)
+	}
+	return certToID(cert.Raw), nil
+}
+
+// certToID returns the ID of the node with the staking certificate [cert]
+func certToID(cert []byte) ids.ShortID {
+	return ids.ShortID(
+		hashing.ComputeHash160Array(
+			hashing.ComputeHash256(cert)))
+}
+
+// tryPackSignedIPs attempts to pack the value as a list of signed IPs
+func tryPackSignedIPs(p *wrappers.Packer, valIntf interface{}) {
+	ips, ok := valIntf.([]*SignedIP)
+	if !ok {
+		p.Add(errBadSignedIPsType)
+		return
+	}
+	p.PackInt(uint32(len(ips)))
+	for i := 0; i < len(ips) && !p.Errored(); i++ {
+		p.PackBytes(ips[i].Cert)
+		p.PackIP(ips[i].IP)
+		p.PackLong(ips[i].Timestamp)
+		p.PackBytes(ips[i].Signature)
+	}
+}
+
+// tryUnpackSignedIPs attempts to unpack the value as a list of signed IPs
+func tryUnpackSignedIPs(p *wrappers.Packer) interface{} {
+	numIPs := p.UnpackInt()
+	ips := []*SignedIP(nil)
+	for i := uint32(0); i < numIPs && !p.Errored(); i++ {
+		ips = append(ips, &SignedIP{
+			Cert:      p.UnpackBytes(),
+			IP:        p.UnpackIP(),
+			Timestamp: p.UnpackLong(),
+			Signature: p.UnpackBytes(),
+		})
+	}
+	return ips
+}
@@ -0,0 +1,150 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+// newTestStakingCert returns a self-signed certificate, in DER form, and its
+// key
+func newTestStakingCert(t *testing.T, key crypto.Signer) []byte {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0),
+		NotBefore:    time.Date(2000, time.January, 0, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	assert.NoError(t, err)
+	return cert
+}
+
+func newTestECDSAKey(t *testing.T) crypto.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	return key
+}
+
+func TestSignedIPVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	for _, key := range []crypto.Signer{rsaKey, newTestECDSAKey(t)} {
+		cert := newTestStakingCert(t, key)
+		signedIP, err := newSignedIP(cert, key, ip, 10)
+		assert.NoError(t, err)
+
+		nodeID, err := signedIP.Verify()
+		assert.NoError(t, err)
+		assert.Equal(t, certToID(cert), nodeID)
+
+		// The signature doesn't cover a different IP or timestamp
+		signedIP.Timestamp++
+		_, err = signedIP.Verify()
+		assert.True(t, errors.Is(err, errInvalidSignedIPSignature))
+		signedIP.Timestamp--
+		signedIP.IP.Port++
+		_, err = signedIP.Verify()
+		assert.True(t, errors.Is(err, errInvalidSignedIPSignature))
+	}
+}
+
+func TestSignedIPVerifyOtherCert(t *testing.T) {
+	key := newTestECDSAKey(t)
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	signedIP, err := newSignedIP(newTestStakingCert(t, key), key, ip, 10)
+	assert.NoError(t, err)
+
+	// An IP signed by one node can't be claimed for another
+	signedIP.Cert = newTestStakingCert(t, newTestECDSAKey(t))
+	_, err = signedIP.Verify()
+	assert.True(t, errors.Is(err, errInvalidSignedIPSignature))
+}
+
+func TestSignedPeerListParse(t *testing.T) {
+	key := newTestECDSAKey(t)
+	ip := utils.IPDesc{IP: net.IPv4(1, 2, 3, 4).To16(), Port: 9651}
+	signedIP, err := newSignedIP(newTestStakingCert(t, key), key, ip, 10)
+	assert.NoError(t, err)
+
+	msg, err := TestBuilder.SignedPeerList([]*SignedIP{signedIP})
+	assert.NoError(t, err)
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, SignedPeerList, parsedMsg.Op())
+	assert.Equal(t, []*SignedIP{signedIP}, parsedMsg.Get(SignedPeers))
+}
+
+func TestTrackSignedIPKeepsLatest(t *testing.T) {
+	key := newTestECDSAKey(t)
+	cert := newTestStakingCert(t, key)
+	nodeID := certToID(cert)
+
+	vdrs := validators.NewSet()
+	assert.NoError(t, vdrs.AddWeight(nodeID, 1))
+	n := &network{
+		id:              ids.GenerateTestShortID(),
+		ip:              utils.NewDynamicIPDesc(net.IPv4(5, 6, 7, 8), 9651),
+		vdrs:            vdrs,
+		signedIPs:       make(map[ids.ShortID]*SignedIP),
+		disconnectedIPs: make(map[string]struct{}),
+	}
+	n.clock.Set(time.Unix(100, 0))
+
+	// Private IPs aren't connected to, so none of these IPs are dialed
+	oldIP, err := newSignedIP(cert, key, utils.IPDesc{IP: net.IPv4(10, 0, 0, 1), Port: 9651}, 10)
+	assert.NoError(t, err)
+	newIP, err := newSignedIP(cert, key, utils.IPDesc{IP: net.IPv4(10, 0, 0, 2), Port: 9651}, 20)
+	assert.NoError(t, err)
+
+	assert.NoError(t, n.trackSignedIP(newIP))
+	assert.NoError(t, n.trackSignedIP(oldIP))
+	assert.Equal(t, newIP, n.signedIPs[nodeID])
+
+	// IPs signed too far in the future are dropped
+	futureIP, err := newSignedIP(cert, key, utils.IPDesc{IP: net.IPv4(10, 0, 0, 3), Port: 9651}, 1000)
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(n.trackSignedIP(futureIP), errSignedIPFromFuture))
+	assert.Equal(t, newIP, n.signedIPs[nodeID])
+
+	// IPs that don't verify are dropped
+	forgedIP := *newIP
+	forgedIP.Timestamp = 30
+	assert.Error(t, n.trackSignedIP(&forgedIP))
+	assert.Equal(t, newIP, n.signedIPs[nodeID])
+
+	// Only the IPs of validators are kept
+	otherKey := newTestECDSAKey(t)
+	otherCert := newTestStakingCert(t, otherKey)
+	otherIP, err := newSignedIP(otherCert, otherKey, utils.IPDesc{IP: net.IPv4(10, 0, 0, 4), Port: 9651}, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, n.trackSignedIP(otherIP))
+	assert.NotContains(t, n.signedIPs, certToID(otherCert))
+
+	// Signatures of IPs that would be dropped anyways aren't verified
+	staleForgedIP := *oldIP
+	staleForgedIP.Signature = newIP.Signature
+	assert.NoError(t, n.trackSignedIP(&staleForgedIP))
+	otherForgedIP := *otherIP
+	otherForgedIP.Signature = newIP.Signature
+	assert.NoError(t, n.trackSignedIP(&otherForgedIP))
+	assert.Equal(t, newIP, n.signedIPs[nodeID])
+}
@@ -0,0 +1,280 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	_ net.Conn     = &conn{}
+	_ net.Listener = &listener{}
+	_ net.Error    = timeoutError{}
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// segment is a write that's on its way to the other node
+type segment struct {
+	data    []byte
+	arrival time.Time
+}
+
+// pipe carries the bytes sent from one node to another over their link
+type pipe struct {
+	network  *Network
+	from, to ids.ShortID
+
+	lock sync.Mutex
+	cond *sync.Cond
+	// writes that haven't arrived yet, in the order they were sent
+	segments []segment
+	// bytes that arrived and haven't been read yet
+	buf bytes.Buffer
+	// time the link is done sending the bytes written so far
+	departure time.Time
+	// arrival time of the last write, which later writes can't overtake
+	arrival time.Time
+	// true iff the writer closed the pipe. The reader gets io.EOF once the
+	// bytes in flight were read.
+	writeClosed bool
+	// true iff the reader closed the pipe. The bytes in flight are dropped.
+	readClosed bool
+	// closed when the reader closes the pipe
+	readDone chan struct{}
+
+	readDeadline  time.Time
+	deadlineTimer *time.Timer
+}
+
+func newPipe(network *Network, from, to ids.ShortID) *pipe {
+	p := &pipe{
+		network:  network,
+		from:     from,
+		to:       to,
+		readDone: make(chan struct{}),
+	}
+	p.cond = sync.NewCond(&p.lock)
+	go p.deliver()
+	return p
+}
+
+func (p *pipe) Write(b []byte) (int, error) {
+	link := p.network.link(p.from, p.to)
+
+	p.lock.Lock()
+	if p.writeClosed || p.readClosed {
+		p.lock.Unlock()
+		return 0, io.ErrClosedPipe
+	}
+
+	now := time.Now()
+	departure := p.departure
+	if departure.Before(now) {
+		departure = now
+	}
+	departure = departure.Add(link.transmitTime(len(b)))
+	p.departure = departure
+
+	arrival := departure.Add(link.Latency + link.retransmitDelay())
+	if arrival.Before(p.arrival) {
+		arrival = p.arrival
+	}
+	p.arrival = arrival
+
+	p.segments = append(p.segments, segment{
+		data:    append([]byte(nil), b...),
+		arrival: arrival,
+	})
+	p.cond.Broadcast()
+	p.lock.Unlock()
+
+	// Like a full send buffer, the writer waits for the link to send what it
+	// wrote
+	time.Sleep(time.Until(departure))
+	return len(b), nil
+}
+
+// deliver moves each write to the reader's buffer once it arrives
+func (p *pipe) deliver() {
+	for {
+		p.lock.Lock()
+		for len(p.segments) == 0 && !p.writeClosed && !p.readClosed {
+			p.cond.Wait()
+		}
+		if len(p.segments) == 0 || p.readClosed {
+			// Nothing more will be delivered
+			p.lock.Unlock()
+			return
+		}
+		seg := p.segments[0]
+		p.lock.Unlock()
+
+		timer := time.NewTimer(time.Until(seg.arrival))
+		select {
+		case <-timer.C:
+		case <-p.readDone:
+			timer.Stop()
+			return
+		}
+
+		// Bytes sent across a partition are held until it heals
+		if !p.network.waitReachable(p.from, p.to, p.readDone) {
+			return
+		}
+
+		p.lock.Lock()
+		if p.readClosed {
+			// The bytes in flight were dropped
+			p.lock.Unlock()
+			return
+		}
+		p.segments = p.segments[1:]
+		p.buf.Write(seg.data)
+		p.cond.Broadcast()
+		p.lock.Unlock()
+	}
+}
+
+func (p *pipe) Read(b []byte) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for {
+		switch {
+		case p.readClosed:
+			return 0, io.ErrClosedPipe
+		case p.buf.Len() > 0:
+			return p.buf.Read(b)
+		case p.writeClosed && len(p.segments) == 0:
+			return 0, io.EOF
+		case !p.readDeadline.IsZero() && !time.Now().Before(p.readDeadline):
+			return 0, timeoutError{}
+		}
+		p.cond.Wait()
+	}
+}
+
+func (p *pipe) setReadDeadline(t time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.readDeadline = t
+	if p.deadlineTimer != nil {
+		p.deadlineTimer.Stop()
+		p.deadlineTimer = nil
+	}
+	if !t.IsZero() {
+		// Wake up the reader once the deadline passes
+		p.deadlineTimer = time.AfterFunc(time.Until(t), func() {
+			p.lock.Lock()
+			p.cond.Broadcast()
+			p.lock.Unlock()
+		})
+	}
+	p.cond.Broadcast()
+}
+
+func (p *pipe) closeWrite() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.writeClosed = true
+	p.cond.Broadcast()
+}
+
+func (p *pipe) closeRead() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.readClosed {
+		return
+	}
+	p.readClosed = true
+	p.segments = nil
+	p.buf.Reset()
+	if p.deadlineTimer != nil {
+		p.deadlineTimer.Stop()
+	}
+	close(p.readDone)
+	p.cond.Broadcast()
+}
+
+// conn is one side of a connection between two nodes
+type conn struct {
+	local, remote *node
+	// bytes sent by the remote node
+	in *pipe
+	// bytes sent to the remote node
+	out *pipe
+}
+
+func (c *conn) Read(b []byte) (int, error)  { return c.in.Read(b) }
+func (c *conn) Write(b []byte) (int, error) { return c.out.Write(b) }
+
+// Close closes both directions of the connection. The bytes already written
+// are still delivered to the remote node before it reads io.EOF.
+func (c *conn) Close() error {
+	c.in.closeRead()
+	c.out.closeWrite()
+	return nil
+}
+
+func (c *conn) LocalAddr() net.Addr  { return addr(c.local.ip) }
+func (c *conn) RemoteAddr() net.Addr { return addr(c.remote.ip) }
+
+func (c *conn) SetDeadline(t time.Time) error { return c.SetReadDeadline(t) }
+
+func (c *conn) SetReadDeadline(t time.Time) error {
+	c.in.setReadDeadline(t)
+	return nil
+}
+
+// SetWriteDeadline is a no-op, as the network doesn't set write deadlines
+func (*conn) SetWriteDeadline(time.Time) error { return nil }
+
+// listener accepts the connections made to a node
+type listener struct {
+	addr  net.Addr
+	conns chan net.Conn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// accept hands [c] to the node. Returns an error if the listener is closed.
+func (l *listener) accept(c net.Conn) error {
+	select {
+	case l.conns <- c:
+		return nil
+	case <-l.done:
+		return io.ErrClosedPipe
+	}
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, io.ErrClosedPipe
+	}
+}
+
+func (l *listener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *listener) Addr() net.Addr { return l.addr }
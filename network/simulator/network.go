@@ -0,0 +1,339 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+const (
+	// Time a lost write waits before it's sent again, on top of the round
+	// trip of the link
+	retransmitTimeout = 200 * time.Millisecond
+)
+
+var (
+	errInvalidLoss    = errors.New("loss must be in [0, 1)")
+	errUnknownNode    = errors.New("unknown node")
+	errDuplicateNode  = errors.New("duplicated node")
+	errDuplicateIP    = errors.New("duplicated IP")
+	errRefused        = errors.New("connection refused")
+	errUnreachable    = errors.New("no route to host")
+	errNotSimulated   = errors.New("connection isn't a simulated connection")
+	errDuplicateGroup = errors.New("node is in more than one group")
+)
+
+// Link describes the messages sent from one node to another
+type Link struct {
+	// Time it takes a byte to reach the other node
+	Latency time.Duration
+	// Probability, in [0, 1), that a write is lost and has to be sent again
+	Loss float64
+	// Bytes per second that can be sent over the link. 0 means the bandwidth
+	// is unlimited.
+	Bandwidth uint64
+}
+
+func (l Link) verify() error {
+	if l.Loss < 0 || l.Loss >= 1 {
+		return errInvalidLoss
+	}
+	return nil
+}
+
+// transmitTime returns how long it takes to put [numBytes] on the link
+func (l Link) transmitTime(numBytes int) time.Duration {
+	if l.Bandwidth == 0 {
+		return 0
+	}
+	return time.Duration(uint64(numBytes) * uint64(time.Second) / l.Bandwidth)
+}
+
+// retransmitDelay returns how long a write is delayed by the times it's lost
+func (l Link) retransmitDelay() time.Duration {
+	delay := time.Duration(0)
+	for l.Loss > 0 && rand.Float64() < l.Loss { // #nosec G404
+		delay += retransmitTimeout + 2*l.Latency
+	}
+	return delay
+}
+
+type route struct{ from, to ids.ShortID }
+
+type node struct {
+	id       ids.ShortID
+	ip       utils.IPDesc
+	listener *listener
+}
+
+// Network is an in-memory network that connects nodes running in the same
+// process. The latency, loss and bandwidth of the links between the nodes can
+// be changed at any time, and the nodes can be partitioned from each other.
+//
+// Like TCP, connections are reliable. Writes that are lost are delayed until
+// they're sent again, and writes sent across a partition are held until the
+// partition heals, unless the connection is closed first.
+type Network struct {
+	lock sync.Mutex
+
+	defaultLink Link
+	links       map[route]Link
+
+	nodes   map[ids.ShortID]*node
+	nodeIPs map[string]*node
+
+	// Group of each node. Nodes in different groups can't reach each other.
+	// Empty if the network isn't partitioned.
+	groups map[ids.ShortID]int
+	// Closed, and replaced, when the partition of the network changes
+	partitionChanged chan struct{}
+}
+
+// NewNetwork returns a network that uses [defaultLink] between nodes that
+// weren't given a link
+func NewNetwork(defaultLink Link) (*Network, error) {
+	if err := defaultLink.verify(); err != nil {
+		return nil, err
+	}
+	return &Network{
+		defaultLink:      defaultLink,
+		links:            make(map[route]Link),
+		nodes:            make(map[ids.ShortID]*node),
+		nodeIPs:          make(map[string]*node),
+		groups:           make(map[ids.ShortID]int),
+		partitionChanged: make(chan struct{}),
+	}, nil
+}
+
+// AddNode adds a node with ID [nodeID] that's reachable at [ip]. Returns the
+// listener the node accepts connections on and the dialer it connects to
+// other nodes with.
+func (n *Network) AddNode(nodeID ids.ShortID, ip utils.IPDesc) (net.Listener, network.Dialer, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if _, exists := n.nodes[nodeID]; exists {
+		return nil, nil, fmt.Errorf("%w: %s", errDuplicateNode, nodeID)
+	}
+	if _, exists := n.nodeIPs[ip.String()]; exists {
+		return nil, nil, fmt.Errorf("%w: %s", errDuplicateIP, ip)
+	}
+
+	nd := &node{
+		id: nodeID,
+		ip: ip,
+	}
+	nd.listener = &listener{
+		addr:  addr(ip),
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+	n.nodes[nodeID] = nd
+	n.nodeIPs[ip.String()] = nd
+	return nd.listener, &dialer{network: n, node: nd}, nil
+}
+
+// RemoveNode removes the node with ID [nodeID] and closes its listener. The
+// node can be added again, such as when it restarts.
+func (n *Network) RemoveNode(nodeID ids.ShortID) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	nd, exists := n.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("%w: %s", errUnknownNode, nodeID)
+	}
+	delete(n.nodes, nodeID)
+	delete(n.nodeIPs, nd.ip.String())
+	return nd.listener.Close()
+}
+
+// Upgrader returns an Upgrader of the connections of this network. The ID of
+// the peer is the ID its node was added with.
+func (n *Network) Upgrader() network.Upgrader { return upgrader{} }
+
+// SetLink sets the link messages from [from] to [to] are sent over. The link
+// from [to] to [from] isn't changed.
+func (n *Network) SetLink(from, to ids.ShortID, link Link) error {
+	if err := link.verify(); err != nil {
+		return err
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.links[route{from: from, to: to}] = link
+	return nil
+}
+
+// SetDefaultLink sets the link between nodes that weren't given a link
+func (n *Network) SetDefaultLink(link Link) error {
+	if err := link.verify(); err != nil {
+		return err
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.defaultLink = link
+	return nil
+}
+
+// Partition splits the network into [groups]. Nodes in different groups
+// can't reach each other. Nodes that aren't in a group are put in a group
+// together. Any previous partition is replaced.
+func (n *Network) Partition(groups ...ids.ShortSet) error {
+	newGroups := make(map[ids.ShortID]int)
+	for i, group := range groups {
+		for nodeID := range group {
+			if _, exists := newGroups[nodeID]; exists {
+				return fmt.Errorf("%w: %s", errDuplicateGroup, nodeID)
+			}
+			// Group 0 is left for the nodes that aren't in a group
+			newGroups[nodeID] = i + 1
+		}
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.groups = newGroups
+	// Messages held by the previous partition may be reachable now
+	n.changePartition()
+	return nil
+}
+
+// Heal removes the partition of the network. Messages held by the partition
+// are delivered.
+func (n *Network) Heal() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.groups = make(map[ids.ShortID]int)
+	n.changePartition()
+}
+
+// changePartition wakes up everything waiting for the partition to change.
+// Assumes [n.lock] is held.
+func (n *Network) changePartition() {
+	close(n.partitionChanged)
+	n.partitionChanged = make(chan struct{})
+}
+
+// Reachable returns true if messages sent from [from] can reach [to]
+func (n *Network) Reachable(from, to ids.ShortID) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.reachable(from, to)
+}
+
+// Assumes [n.lock] is held
+func (n *Network) reachable(from, to ids.ShortID) bool {
+	return n.groups[from] == n.groups[to]
+}
+
+// waitReachable blocks until [from] can reach [to] or [done] is closed.
+// Returns false if [done] was closed first.
+func (n *Network) waitReachable(from, to ids.ShortID, done <-chan struct{}) bool {
+	for {
+		n.lock.Lock()
+		reachable := n.reachable(from, to)
+		partitionChanged := n.partitionChanged
+		n.lock.Unlock()
+
+		if reachable {
+			return true
+		}
+		select {
+		case <-partitionChanged:
+		case <-done:
+			return false
+		}
+	}
+}
+
+// link returns the link messages from [from] to [to] are sent over
+func (n *Network) link(from, to ids.ShortID) Link {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if link, ok := n.links[route{from: from, to: to}]; ok {
+		return link
+	}
+	return n.defaultLink
+}
+
+// dial connects [from] to the node listening on [ip]
+func (n *Network) dial(from *node, ip utils.IPDesc) (net.Conn, error) {
+	n.lock.Lock()
+	to, exists := n.nodeIPs[ip.String()]
+	reachable := exists && n.reachable(from.id, to.id) && n.reachable(to.id, from.id)
+	n.lock.Unlock()
+
+	switch {
+	case !exists:
+		return nil, fmt.Errorf("%w: %s", errRefused, ip)
+	case !reachable:
+		return nil, fmt.Errorf("%w: %s", errUnreachable, ip)
+	}
+
+	// The handshake takes a round trip
+	time.Sleep(n.link(from.id, to.id).Latency + n.link(to.id, from.id).Latency)
+
+	toPipe := newPipe(n, from.id, to.id)
+	fromPipe := newPipe(n, to.id, from.id)
+	fromConn := &conn{
+		local:  from,
+		remote: to,
+		in:     fromPipe,
+		out:    toPipe,
+	}
+	toConn := &conn{
+		local:  to,
+		remote: from,
+		in:     toPipe,
+		out:    fromPipe,
+	}
+	if err := to.listener.accept(toConn); err != nil {
+		_ = fromConn.Close()
+		_ = toConn.Close()
+		return nil, fmt.Errorf("%w: %s", errRefused, ip)
+	}
+	return fromConn, nil
+}
+
+type dialer struct {
+	network *Network
+	node    *node
+}
+
+func (d *dialer) Dial(ip utils.IPDesc) (net.Conn, error) { return d.network.dial(d.node, ip) }
+
+type upgrader struct{}
+
+func (upgrader) Upgrade(c net.Conn) (ids.ShortID, net.Conn, error) {
+	simConn, ok := c.(*conn)
+	if !ok {
+		return ids.ShortID{}, nil, errNotSimulated
+	}
+	return simConn.remote.id, c, nil
+}
+
+// addr returns the address of [ip]
+func addr(ip utils.IPDesc) net.Addr {
+	return &net.TCPAddr{
+		IP:   ip.IP,
+		Port: int(ip.Port),
+	}
+}
@@ -0,0 +1,216 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/utils"
+)
+
+type testNode struct {
+	id       ids.ShortID
+	ip       utils.IPDesc
+	listener net.Listener
+	dialer   network.Dialer
+}
+
+func newTestNode(t *testing.T, n *Network, i byte) testNode {
+	node := testNode{
+		id: ids.ShortID{i},
+		ip: utils.IPDesc{
+			IP:   net.IPv4(10, 0, 0, i),
+			Port: 9651,
+		},
+	}
+	var err error
+	node.listener, node.dialer, err = n.AddNode(node.id, node.ip)
+	assert.NoError(t, err)
+	return node
+}
+
+// connect returns the connection [from] made to [to], and the connection [to]
+// accepted
+func connect(t *testing.T, from, to testNode) (net.Conn, net.Conn) {
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := to.listener.Accept()
+		assert.NoError(t, err)
+		accepted <- conn
+	}()
+	conn, err := from.dialer.Dial(to.ip)
+	assert.NoError(t, err)
+	return conn, <-accepted
+}
+
+func TestNetworkConnect(t *testing.T) {
+	n, err := NewNetwork(Link{})
+	assert.NoError(t, err)
+	node0 := newTestNode(t, n, 0)
+	node1 := newTestNode(t, n, 1)
+
+	conn0, conn1 := connect(t, node0, node1)
+
+	upgrader := n.Upgrader()
+	id, _, err := upgrader.Upgrade(conn0)
+	assert.NoError(t, err)
+	assert.Equal(t, node1.id, id)
+	id, _, err = upgrader.Upgrade(conn1)
+	assert.NoError(t, err)
+	assert.Equal(t, node0.id, id)
+
+	assert.Equal(t, node1.ip.String(), conn0.RemoteAddr().String())
+	assert.Equal(t, node0.ip.String(), conn1.RemoteAddr().String())
+
+	_, err = conn0.Write([]byte("hello"))
+	assert.NoError(t, err)
+	_, err = conn0.Write([]byte(" world"))
+	assert.NoError(t, err)
+	assert.NoError(t, conn0.Close())
+
+	// The bytes written before the connection was closed are still read
+	b, err := ioutil.ReadAll(conn1)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+
+	_, err = conn1.Write([]byte("hello"))
+	assert.Error(t, err)
+}
+
+func TestNetworkDialUnknown(t *testing.T) {
+	n, err := NewNetwork(Link{})
+	assert.NoError(t, err)
+	node0 := newTestNode(t, n, 0)
+	node1 := newTestNode(t, n, 1)
+
+	assert.NoError(t, n.RemoveNode(node1.id))
+	_, err = node0.dialer.Dial(node1.ip)
+	assert.True(t, errors.Is(err, errRefused))
+
+	_, err = node1.listener.Accept()
+	assert.Error(t, err)
+
+	// The node can be added again once it was removed
+	_, _, err = n.AddNode(node1.id, node1.ip)
+	assert.NoError(t, err)
+	_, _, err = n.AddNode(node1.id, node1.ip)
+	assert.True(t, errors.Is(err, errDuplicateNode))
+}
+
+func TestNetworkLatency(t *testing.T) {
+	n, err := NewNetwork(Link{})
+	assert.NoError(t, err)
+	node0 := newTestNode(t, n, 0)
+	node1 := newTestNode(t, n, 1)
+
+	latency := 50 * time.Millisecond
+	assert.NoError(t, n.SetLink(node0.id, node1.id, Link{Latency: latency}))
+
+	conn0, conn1 := connect(t, node0, node1)
+
+	start := time.Now()
+	_, err = conn0.Write([]byte{1})
+	assert.NoError(t, err)
+	b := make([]byte, 1)
+	_, err = conn1.Read(b)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(latency))
+
+	// The link back to node0 wasn't changed
+	start = time.Now()
+	_, err = conn1.Write([]byte{1})
+	assert.NoError(t, err)
+	_, err = conn0.Read(b)
+	assert.NoError(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(latency))
+}
+
+func TestNetworkBandwidth(t *testing.T) {
+	n, err := NewNetwork(Link{Bandwidth: 10 * 1024})
+	assert.NoError(t, err)
+	node0 := newTestNode(t, n, 0)
+	node1 := newTestNode(t, n, 1)
+
+	conn0, conn1 := connect(t, node0, node1)
+
+	// Sending 1 KiB at 10 KiB/s takes 100ms
+	start := time.Now()
+	go func() {
+		for i := 0; i < 4; i++ {
+			_, err := conn0.Write(make([]byte, 256))
+			assert.NoError(t, err)
+		}
+	}()
+	_, err = io.ReadFull(conn1, make([]byte, 1024))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+}
+
+func TestNetworkLoss(t *testing.T) {
+	_, err := NewNetwork(Link{Loss: 1})
+	assert.True(t, errors.Is(err, errInvalidLoss))
+
+	n, err := NewNetwork(Link{Loss: .99})
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(n.SetDefaultLink(Link{Loss: -1}), errInvalidLoss))
+	node0 := newTestNode(t, n, 0)
+	node1 := newTestNode(t, n, 1)
+
+	conn0, conn1 := connect(t, node0, node1)
+
+	// A lost write isn't read until it's sent again
+	assert.NoError(t, conn1.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+	_, err = conn0.Write([]byte{1})
+	assert.NoError(t, err)
+	_, err = conn1.Read(make([]byte, 1))
+	assert.Error(t, err)
+	netErr, ok := err.(net.Error)
+	assert.True(t, ok)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestNetworkPartition(t *testing.T) {
+	n, err := NewNetwork(Link{})
+	assert.NoError(t, err)
+	node0 := newTestNode(t, n, 0)
+	node1 := newTestNode(t, n, 1)
+	node2 := newTestNode(t, n, 2)
+
+	conn0, conn1 := connect(t, node0, node1)
+
+	group := ids.ShortSet{}
+	group.Add(node1.id)
+	assert.NoError(t, n.Partition(group))
+	assert.False(t, n.Reachable(node0.id, node1.id))
+	assert.True(t, n.Reachable(node0.id, node2.id))
+
+	// New connections can't be made across the partition
+	_, err = node0.dialer.Dial(node1.ip)
+	assert.True(t, errors.Is(err, errUnreachable))
+
+	// Bytes sent across the partition are held until it heals
+	_, err = conn0.Write([]byte{1})
+	assert.NoError(t, err)
+	assert.NoError(t, conn1.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+	_, err = conn1.Read(make([]byte, 1))
+	assert.Error(t, err)
+
+	n.Heal()
+	assert.NoError(t, conn1.SetReadDeadline(time.Time{}))
+	b := make([]byte, 1)
+	_, err = conn1.Read(b)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(1), b[0])
+
+	assert.True(t, errors.Is(n.Partition(group, group), errDuplicateGroup))
+}
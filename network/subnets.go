@@ -0,0 +1,90 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// Max number of subnets, other than the primary network, that a peer may
+// claim to track in its version. Subnets beyond these aren't advertised, and
+// those a peer claims beyond these are ignored.
+const maxTrackedSubnets = 16
+
+// subnetValidators returns the validators of [subnetID]. Returns false if the
+// validators of the subnet aren't known.
+func (n *network) subnetValidators(subnetID ids.ID) (validators.Set, bool) {
+	if subnetID == constants.PrimaryNetworkID {
+		return n.vdrs, true
+	}
+	if n.subnetVdrs == nil {
+		return nil, false
+	}
+	return n.subnetVdrs.GetValidators(subnetID)
+}
+
+// trackedValidator returns true if [nodeID] validates a subnet we track. Every
+// node tracks the primary network.
+func (n *network) trackedValidator(nodeID ids.ShortID) bool {
+	if n.vdrs.Contains(nodeID) {
+		return true
+	}
+	for subnetID := range n.trackedSubnets {
+		if vdrs, ok := n.subnetValidators(subnetID); ok && vdrs.Contains(nodeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// subnetValidator returns true if [nodeID] validates a subnet we track, other
+// than the primary network. As few nodes validate each subnet, these nodes are
+// redialed and gossiped to more often than other nodes.
+func (n *network) subnetValidator(nodeID ids.ShortID) bool {
+	for subnetID := range n.trackedSubnets {
+		if subnetID == constants.PrimaryNetworkID {
+			continue
+		}
+		if vdrs, ok := n.subnetValidators(subnetID); ok && vdrs.Contains(nodeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedValidator returns true if [nodeID] validates a subnet that both we and
+// [p] track.
+func (n *network) sharedValidator(p *peer, nodeID ids.ShortID) bool {
+	if n.vdrs.Contains(nodeID) {
+		return true
+	}
+	for subnetID := range n.trackedSubnets {
+		if !p.tracksSubnet(subnetID) {
+			continue
+		}
+		if vdrs, ok := n.subnetValidators(subnetID); ok && vdrs.Contains(nodeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// subnetsToAdvertise returns the subnets we track, other than the primary
+// network, which every node tracks. If we track more than [maxTrackedSubnets]
+// subnets, only the first of them by ID are advertised.
+func (n *network) subnetsToAdvertise() []ids.ID {
+	subnetIDs := make([]ids.ID, 0, n.trackedSubnets.Len())
+	for subnetID := range n.trackedSubnets {
+		if subnetID != constants.PrimaryNetworkID {
+			subnetIDs = append(subnetIDs, subnetID)
+		}
+	}
+	if len(subnetIDs) > maxTrackedSubnets {
+		ids.SortIDs(subnetIDs)
+		subnetIDs = subnetIDs[:maxTrackedSubnets]
+	}
+	return subnetIDs
+}
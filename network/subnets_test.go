@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestSharedValidator(t *testing.T) {
+	subnetA := ids.GenerateTestID()
+	subnetB := ids.GenerateTestID()
+
+	primaryVdr := ids.GenerateTestShortID()
+	vdrA := ids.GenerateTestShortID()
+	vdrB := ids.GenerateTestShortID()
+
+	primaryVdrs := validators.NewSet()
+	assert.NoError(t, primaryVdrs.AddWeight(primaryVdr, 1))
+	subnetVdrs := validators.NewManager()
+	assert.NoError(t, subnetVdrs.Set(constants.PrimaryNetworkID, primaryVdrs))
+	assert.NoError(t, subnetVdrs.AddWeight(subnetA, vdrA, 1))
+	assert.NoError(t, subnetVdrs.AddWeight(subnetB, vdrB, 1))
+
+	// We track subnet A, but not subnet B
+	n := &network{
+		vdrs:           primaryVdrs,
+		subnetVdrs:     subnetVdrs,
+		trackedSubnets: ids.Set{},
+	}
+	n.trackedSubnets.Add(constants.PrimaryNetworkID, subnetA)
+
+	assert.True(t, n.trackedValidator(primaryVdr))
+	assert.True(t, n.trackedValidator(vdrA))
+	assert.False(t, n.trackedValidator(vdrB))
+	assert.False(t, n.subnetValidator(primaryVdr))
+	assert.True(t, n.subnetValidator(vdrA))
+	assert.False(t, n.subnetValidator(vdrB))
+	assert.Equal(t, []ids.ID{subnetA}, n.subnetsToAdvertise())
+
+	// A peer that hasn't told us its subnets is only sent the validators of
+	// the primary network
+	p := &peer{net: n}
+	assert.True(t, n.sharedValidator(p, primaryVdr))
+	assert.False(t, n.sharedValidator(p, vdrA))
+	assert.False(t, n.sharedValidator(p, vdrB))
+	assert.Empty(t, p.trackedSubnetIDs())
+
+	// A peer that tracks both subnets is only sent the validators of the
+	// subnets we both track
+	peerSubnets := ids.Set{}
+	peerSubnets.Add(subnetA, subnetB)
+	p.trackedSubnets.SetValue(peerSubnets)
+	assert.True(t, n.sharedValidator(p, primaryVdr))
+	assert.True(t, n.sharedValidator(p, vdrA))
+	assert.False(t, n.sharedValidator(p, vdrB))
+	assert.Len(t, p.trackedSubnetIDs(), 2)
+}
+
+func TestSharedValidatorWithoutManager(t *testing.T) {
+	primaryVdr := ids.GenerateTestShortID()
+	primaryVdrs := validators.NewSet()
+	assert.NoError(t, primaryVdrs.AddWeight(primaryVdr, 1))
+
+	subnetID := ids.GenerateTestID()
+	n := &network{
+		vdrs:           primaryVdrs,
+		trackedSubnets: ids.Set{},
+	}
+	n.trackedSubnets.Add(constants.PrimaryNetworkID, subnetID)
+
+	peerSubnets := ids.Set{}
+	peerSubnets.Add(subnetID)
+	p := &peer{net: n}
+	p.trackedSubnets.SetValue(peerSubnets)
+
+	assert.True(t, n.sharedValidator(p, primaryVdr))
+	assert.False(t, n.sharedValidator(p, ids.GenerateTestShortID()))
+}
+
+func TestTooManyTrackedSubnets(t *testing.T) {
+	n := &network{
+		log:            logging.NoLog{},
+		trackedSubnets: ids.Set{},
+	}
+	subnetIDs := make([]ids.ID, maxTrackedSubnets+1)
+	for i := range subnetIDs {
+		subnetIDs[i] = ids.GenerateTestID()
+	}
+	n.trackedSubnets.Add(constants.PrimaryNetworkID)
+	n.trackedSubnets.Add(subnetIDs...)
+
+	// Only [maxTrackedSubnets] subnets are advertised, and always the same ones
+	advertised := n.subnetsToAdvertise()
+	assert.Len(t, advertised, maxTrackedSubnets)
+	assert.Equal(t, advertised, n.subnetsToAdvertise())
+	for _, subnetID := range advertised {
+		assert.NotEqual(t, constants.PrimaryNetworkID, subnetID)
+		assert.True(t, n.trackedSubnets.Contains(subnetID))
+	}
+
+	// A peer that claims to track more subnets isn't penalized, which would
+	// panic as [n] has no reputation, and only the first of them are tracked
+	msg, err := TestBuilder.VersionWithSubnets(0, 0, 0, utils.IPDesc{}, "", subnetIDs, false)
+	assert.NoError(t, err)
+	p := &peer{net: n}
+	assert.True(t, p.setTrackedSubnets(msg))
+	assert.Len(t, p.trackedSubnetIDs(), maxTrackedSubnets)
+	for _, subnetID := range subnetIDs[:maxTrackedSubnets] {
+		assert.True(t, p.tracksSubnet(subnetID))
+	}
+	assert.False(t, p.tracksSubnet(subnetIDs[maxTrackedSubnets]))
+}
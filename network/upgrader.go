@@ -6,6 +6,7 @@ package network
 import (
 	"crypto/tls"
 	"errors"
+	"io"
 	"net"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -16,12 +17,28 @@ var (
 	errNoCert = errors.New("tls handshake finished with no peer certificate")
 )
 
-// Upgrader ...
+// Upgrader secures connections and identifies the peers on the other side of
+// them. Connections made by transports that secure them on their own, such as
+// QUIC, are only identified.
 type Upgrader interface {
 	// Must be thread safe
 	Upgrade(net.Conn) (ids.ShortID, net.Conn, error)
 }
 
+// StreamConn is a connection that multiplexes streams, such as a QUIC
+// connection. Messages of each priority are sent on their own stream, so a
+// large message doesn't delay the messages of other priorities. Messages are
+// only sent and received on the streams of the connection.
+type StreamConn interface {
+	net.Conn
+
+	// OpenStream opens a stream to send messages to the peer on
+	OpenStream() (io.WriteCloser, error)
+
+	// AcceptStream blocks until the peer opens a stream and returns it
+	AcceptStream() (io.Reader, error)
+}
+
 type ipUpgrader struct{}
 
 // NewIPUpgrader ...
@@ -56,9 +73,7 @@ func (t tlsServerUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, error)
 		return ids.ShortID{}, nil, errNoCert
 	}
 	peerCert := connState.PeerCertificates[0]
-	id := ids.ShortID(
-		hashing.ComputeHash160Array(
-			hashing.ComputeHash256(peerCert.Raw)))
+	id := certToID(peerCert.Raw)
 	return id, encConn, nil
 }
 
@@ -84,8 +99,6 @@ func (t tlsClientUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, error)
 		return ids.ShortID{}, nil, errNoCert
 	}
 	peerCert := connState.PeerCertificates[0]
-	id := ids.ShortID(
-		hashing.ComputeHash160Array(
-			hashing.ComputeHash256(peerCert.Raw)))
+	id := certToID(peerCert.Raw)
 	return id, encConn, nil
 }
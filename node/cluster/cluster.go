@@ -0,0 +1,635 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package cluster runs a network of full nodes in one process, connected by a
+// simulated network. It's used to test how the nodes behave when links between
+// them are slow, lossy or cut.
+package cluster
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/nat"
+	"github.com/ava-labs/avalanchego/network/simulator"
+	"github.com/ava-labs/avalanchego/node"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+const (
+	// Port every node listens on for its peers. Each node has its own
+	// simulated IP.
+	stakingPort = 9651
+
+	// How often the cluster checks if a condition it waits for is met
+	pollFrequency = 100 * time.Millisecond
+
+	apiRequestTimeout = 10 * time.Second
+)
+
+var (
+	errNoNodes         = errors.New("a cluster needs at least one node")
+	errTooManyNodes    = errors.New("a cluster can't have more than 254 nodes")
+	errInvalidFraction = errors.New("fraction must be in (0, 1)")
+	errFractionTooLow  = errors.New("every node holds more than the fraction of the stake")
+	errRunning         = errors.New("node is running")
+	errNotRunning      = errors.New("node isn't running")
+	errNoCert          = errors.New("couldn't decode the staking certificate")
+	errNoStake         = errors.New("node doesn't validate at genesis")
+	errTimeout         = errors.New("timed out")
+)
+
+// Config of a cluster
+type Config struct {
+	// Number of nodes in the cluster. Every node validates the primary network
+	// with about the same stake.
+	NumNodes int
+
+	// Directory the nodes write their staking keys and logs to
+	Dir string
+
+	// Link between the nodes, until it's changed through the cluster's network
+	Link simulator.Link
+
+	// Consensus parameters of the nodes. If zero, parameters that suit the
+	// size of the cluster are used.
+	ConsensusParams avalanche.Parameters
+
+	// Timeouts of the requests the nodes send each other. If zero, the
+	// timeouts used by default are shortened so that tests run quickly.
+	NetworkConfig timer.AdaptiveTimeoutConfig
+
+	// How often the nodes gossip their accepted frontiers
+	ConsensusGossipFrequency time.Duration
+}
+
+// Node is a node of a cluster
+type Node struct {
+	ID ids.ShortID
+	// Simulated IP that peers connect to the node at
+	IP utils.IPDesc
+	// URI of the node's APIs, which are served over HTTP on the loopback
+	// interface
+	URI string
+	// Stake the node validates the primary network with
+	Weight uint64
+
+	dir      string
+	certFile string
+	keyFile  string
+	httpPort uint16
+	// Listener of the node's API server until the node first starts. The API
+	// server closes it when the node stops.
+	httpListener net.Listener
+	// Kept when the node is stopped, so the node restarts with its state
+	db database.Database
+
+	lock sync.Mutex
+	// nil if the node isn't running
+	node       *node.Node
+	logFactory logging.Factory
+	// Receives the error the node stopped with
+	done chan error
+
+	chainsLock sync.Mutex
+	// The chains the node created since it last started, by primary alias
+	chains map[string]nodeChain
+}
+
+// nodeChain is a chain a node created
+type nodeChain struct {
+	ctx *snow.Context
+	vm  interface{}
+}
+
+// Running returns true if the node is running
+func (n *Node) Running() bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.node != nil
+}
+
+// RegisterChain records the chains the node creates
+// Implements chains.Registrant
+func (n *Node) RegisterChain(name string, ctx *snow.Context, vm interface{}) {
+	n.chainsLock.Lock()
+	defer n.chainsLock.Unlock()
+
+	n.chains[name] = nodeChain{ctx: ctx, vm: vm}
+}
+
+// Chain returns the context and VM of the chain with the primary alias
+// [alias], which the node created since it last started. The VM may only be
+// used while holding the context's lock. Returns false if the node didn't
+// create the chain.
+func (n *Node) Chain(alias string) (*snow.Context, interface{}, bool) {
+	n.chainsLock.Lock()
+	defer n.chainsLock.Unlock()
+
+	chain, ok := n.chains[alias]
+	return chain.ctx, chain.vm, ok
+}
+
+// Cluster is a network of full nodes that run in one process. Every node
+// validates the primary network from genesis, and the nodes reach each other
+// over [Network].
+type Cluster struct {
+	Network *simulator.Network
+	Nodes   []*Node
+
+	config       Config
+	genesisBytes []byte
+	avaxAssetID  ids.ID
+}
+
+// New returns a cluster of [config.NumNodes] nodes. The nodes aren't started.
+func New(config Config) (*Cluster, error) {
+	switch {
+	case config.NumNodes < 1:
+		return nil, errNoNodes
+	case config.NumNodes > math.MaxUint8-1:
+		return nil, errTooManyNodes
+	}
+	if config.ConsensusParams == (avalanche.Parameters{}) {
+		config.ConsensusParams = defaultConsensusParams(config.NumNodes)
+	}
+	if config.NetworkConfig == (timer.AdaptiveTimeoutConfig{}) {
+		config.NetworkConfig = timer.AdaptiveTimeoutConfig{
+			InitialTimeout: time.Second,
+			MinimumTimeout: 500 * time.Millisecond,
+			MaximumTimeout: 2 * time.Second,
+			TimeoutInc:     100 * time.Millisecond,
+			TimeoutDec:     5 * time.Millisecond,
+		}
+	}
+	if config.ConsensusGossipFrequency == 0 {
+		config.ConsensusGossipFrequency = time.Second
+	}
+
+	simNetwork, err := simulator.NewNetwork(config.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cluster{
+		Network: simNetwork,
+		Nodes:   make([]*Node, config.NumNodes),
+		config:  config,
+	}
+	for i := range c.Nodes {
+		n, err := newNode(config.Dir, i)
+		if err != nil {
+			return nil, err
+		}
+		c.Nodes[i] = n
+	}
+
+	c.genesisBytes, c.avaxAssetID, err = c.genesis()
+	if err != nil {
+		return nil, err
+	}
+	return c, c.setWeights()
+}
+
+func newNode(dir string, i int) (*Node, error) {
+	n := &Node{
+		IP: utils.IPDesc{
+			IP:   net.IPv4(10, 0, 0, byte(i+1)),
+			Port: stakingPort,
+		},
+		dir: filepath.Join(dir, fmt.Sprintf("node%d", i)),
+		db:  memdb.New(),
+	}
+	n.certFile = filepath.Join(n.dir, "staking.crt")
+	n.keyFile = filepath.Join(n.dir, "staking.key")
+	if err := staking.GenerateStakingKeyCert(n.keyFile, n.certFile); err != nil {
+		return nil, err
+	}
+
+	var err error
+	n.ID, err = certToID(n.certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// The listener is kept open until the API server takes it over, so no
+	// other node can be given the same port
+	n.httpListener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	n.httpPort = uint16(n.httpListener.Addr().(*net.TCPAddr).Port)
+	n.URI = fmt.Sprintf("http://127.0.0.1:%d", n.httpPort)
+	return n, nil
+}
+
+// genesis returns the genesis of the local network, staked by the nodes of the
+// cluster rather than by the local stakers
+func (c *Cluster) genesis() ([]byte, ids.ID, error) {
+	config := *genesis.GetConfig(constants.LocalID)
+	// The nodes validate from now, so they don't stop validating while the
+	// cluster runs
+	config.StartTime = uint64(time.Now().Add(-time.Minute).Unix())
+	config.InitialStakeDuration = uint64((365 * 24 * time.Hour).Seconds())
+	config.InitialStakers = make([]genesis.Staker, len(c.Nodes))
+	for i, n := range c.Nodes {
+		config.InitialStakers[i] = genesis.Staker{
+			NodeID:        n.ID,
+			RewardAddress: config.InitialStakedFunds[0],
+		}
+	}
+	return genesis.FromConfig(&config)
+}
+
+// setWeights sets the stake of each node to the stake it validates with in
+// the genesis of the cluster
+func (c *Cluster) setWeights() error {
+	g := &platformvm.Genesis{}
+	if _, err := platformvm.GenesisCodec.Unmarshal(c.genesisBytes, g); err != nil {
+		return err
+	}
+	weights := make(map[ids.ShortID]uint64, len(g.Validators))
+	for _, tx := range g.Validators {
+		if vdrTx, ok := tx.UnsignedTx.(*platformvm.UnsignedAddValidatorTx); ok {
+			weights[vdrTx.Validator.NodeID] += vdrTx.Validator.Weight()
+		}
+	}
+	for _, n := range c.Nodes {
+		weight, ok := weights[n.ID]
+		if !ok {
+			return fmt.Errorf("%w: %s", errNoStake, n.ID)
+		}
+		n.Weight = weight
+	}
+	return nil
+}
+
+// nodeConfig returns the config [n] runs with. A new config is made each time
+// the node starts, as the node modifies its config.
+func (c *Cluster) nodeConfig(n *Node, transport *node.Transport, httpListener net.Listener) node.Config {
+	consensusParams := c.config.ConsensusParams
+	consensusParams.Metrics = prometheus.NewRegistry()
+
+	whitelistedSubnets := ids.Set{}
+	whitelistedSubnets.Add(constants.PrimaryNetworkID)
+
+	return node.Config{
+		Params:                     genesis.LocalParams,
+		GenesisBytes:               c.genesisBytes,
+		AvaxAssetID:                c.avaxAssetID,
+		Nat:                        nat.NewNoRouter(),
+		NetworkID:                  constants.LocalID,
+		EnableCrypto:               true,
+		DB:                         n.db,
+		StakingIP:                  utils.NewDynamicIPDesc(n.IP.IP, n.IP.Port),
+		EnableP2PTLS:               true,
+		EnableStaking:              true,
+		StakingKeyFile:             n.keyFile,
+		StakingCertFile:            n.certFile,
+		Transport:                  transport,
+		MaxNonStakerPendingMsgs:    router.DefaultMaxNonStakerPendingMsgs,
+		StakerMSGPortion:           router.DefaultStakerPortion,
+		StakerCPUPortion:           router.DefaultStakerPortion,
+		SendQueueSize:              4096,
+		MaxPendingMsgs:             4096,
+		NetworkConfig:              c.config.NetworkConfig,
+		HTTPHost:                   "127.0.0.1",
+		HTTPPort:                   n.httpPort,
+		HTTPListener:               httpListener,
+		InfoAPIEnabled:             true,
+		KeystoreAPIEnabled:         true,
+		LoggingConfig:              loggingConfig(n.dir),
+		PluginDir:                  filepath.Join(n.dir, "plugins"),
+		ConsensusParams:            consensusParams,
+		FeeConfig:                  fees.Config{Window: 10 * time.Second, MaxMultiplier: 1},
+		ConsensusRouter:            &router.ChainRouter{},
+		ConsensusGossipFrequency:   c.config.ConsensusGossipFrequency,
+		ConsensusShutdownTimeout:   5 * time.Second,
+		ChainRegistrants:           []chains.Registrant{n},
+		ConnMeterMaxConns:          5,
+		WhitelistedSubnets:         whitelistedSubnets,
+		DisconnectedCheckFreq:      10 * time.Second,
+		DisconnectedRestartTimeout: time.Minute,
+	}
+}
+
+// Start starts every node of the cluster that isn't running
+func (c *Cluster) Start() error {
+	for i, n := range c.Nodes {
+		if n.Running() {
+			continue
+		}
+		if err := c.StartNode(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops every node of the cluster that's running
+func (c *Cluster) Stop() error {
+	for i, n := range c.Nodes {
+		if !n.Running() {
+			continue
+		}
+		if err := c.StopNode(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartNode starts the [i]th node of the cluster. A node that was stopped
+// restarts with the state it had.
+func (c *Cluster) StartNode(i int) error {
+	n := c.Nodes[i]
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.node != nil {
+		return fmt.Errorf("%w: %s", errRunning, n.ID)
+	}
+
+	// The API server closed the node's listener when the node stopped, so the
+	// node's port is listened on again when it restarts
+	httpListener := n.httpListener
+	n.httpListener = nil
+	if httpListener == nil {
+		var err error
+		httpListener, err = net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", n.httpPort))
+		if err != nil {
+			return err
+		}
+	}
+
+	listener, dialer, err := c.Network.AddNode(n.ID, n.IP)
+	if err != nil {
+		_ = httpListener.Close()
+		return err
+	}
+	upgrader := c.Network.Upgrader()
+	config := c.nodeConfig(n, &node.Transport{
+		Listener:       listener,
+		Dialer:         dialer,
+		ServerUpgrader: upgrader,
+		ClientUpgrader: upgrader,
+	}, httpListener)
+
+	logFactory := logging.NewFactory(config.LoggingConfig)
+	log, err := logFactory.Make()
+	if err != nil {
+		logFactory.Close()
+		_ = httpListener.Close()
+		_ = c.Network.RemoveNode(n.ID)
+		return err
+	}
+
+	n.chainsLock.Lock()
+	n.chains = make(map[string]nodeChain)
+	n.chainsLock.Unlock()
+
+	nd := &node.Node{}
+	if err := nd.Initialize(&config, log, logFactory, &restarter{cluster: c, i: i}); err != nil {
+		logFactory.Close()
+		_ = httpListener.Close()
+		_ = c.Network.RemoveNode(n.ID)
+		return fmt.Errorf("couldn't initialize node %s: %w", n.ID, err)
+	}
+
+	// The nodes don't have beacons, so they're told where the other nodes are
+	for _, peer := range c.Nodes {
+		if peer != n {
+			nd.Net.Track(peer.IP)
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- nd.Dispatch() }()
+
+	n.node = nd
+	n.logFactory = logFactory
+	n.done = done
+	return nil
+}
+
+// StopNode stops the [i]th node of the cluster
+func (c *Cluster) StopNode(i int) error {
+	n := c.Nodes[i]
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.node == nil {
+		return fmt.Errorf("%w: %s", errNotRunning, n.ID)
+	}
+
+	n.node.Shutdown()
+	// The node's network always stops with an error once it's closed
+	<-n.done
+	n.logFactory.Close()
+	n.node = nil
+	n.logFactory = nil
+	n.done = nil
+	return c.Network.RemoveNode(n.ID)
+}
+
+// RestartNode stops and starts the [i]th node of the cluster
+func (c *Cluster) RestartNode(i int) error {
+	if err := c.StopNode(i); err != nil {
+		return err
+	}
+	return c.StartNode(i)
+}
+
+// PartitionStake cuts nodes holding at most [fraction] of the stake off from
+// the rest of the cluster, and returns their IDs. Nodes are cut off from the
+// end of [c.Nodes] for as long as the stake they hold stays within
+// [fraction]. The partition lasts until it's healed.
+func (c *Cluster) PartitionStake(fraction float64) (ids.ShortSet, error) {
+	if fraction <= 0 || fraction >= 1 {
+		return nil, errInvalidFraction
+	}
+
+	totalWeight := uint64(0)
+	for _, n := range c.Nodes {
+		totalWeight += n.Weight
+	}
+	maxCutOffWeight := uint64(fraction * float64(totalWeight))
+
+	cutOff := ids.ShortSet{}
+	cutOffWeight := uint64(0)
+	for i := len(c.Nodes) - 1; i >= 0; i-- {
+		n := c.Nodes[i]
+		if cutOffWeight+n.Weight > maxCutOffWeight {
+			break
+		}
+		cutOffWeight += n.Weight
+		cutOff.Add(n.ID)
+	}
+	if cutOff.Len() == 0 {
+		return nil, errFractionTooLow
+	}
+	return cutOff, c.Network.Partition(cutOff)
+}
+
+// Heal removes the partition of the cluster
+func (c *Cluster) Heal() { c.Network.Heal() }
+
+// WaitConnected blocks until every running node is connected to every other
+// running node, or [timeout] passes
+func (c *Cluster) WaitConnected(timeout time.Duration) error {
+	return wait(timeout, func() (bool, error) {
+		running := c.running()
+		for _, n := range running {
+			n.lock.Lock()
+			numPeers := 0
+			if n.node != nil {
+				numPeers = len(n.node.Net.Peers(nil))
+			}
+			n.lock.Unlock()
+
+			if numPeers < len(running)-1 {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// WaitBootstrapped blocks until every running node finished bootstrapping
+// [chain], or [timeout] passes
+func (c *Cluster) WaitBootstrapped(chain string, timeout time.Duration) error {
+	return wait(timeout, func() (bool, error) {
+		for _, n := range c.running() {
+			bootstrapped, err := info.NewClient(n.URI, apiRequestTimeout).IsBootstrapped(chain)
+			if err != nil || !bootstrapped {
+				// The API server may not be serving yet
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// running returns the nodes that are running
+func (c *Cluster) running() []*Node {
+	running := make([]*Node, 0, len(c.Nodes))
+	for _, n := range c.Nodes {
+		if n.Running() {
+			running = append(running, n)
+		}
+	}
+	return running
+}
+
+// wait blocks until [condition] is met, returns an error, or [timeout] passes
+func wait(timeout time.Duration, condition func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		met, err := condition()
+		switch {
+		case err != nil:
+			return err
+		case met:
+			return nil
+		case time.Now().After(deadline):
+			return errTimeout
+		}
+		time.Sleep(pollFrequency)
+	}
+}
+
+// restarter restarts a node of a cluster when the node asks to be restarted
+type restarter struct {
+	cluster *Cluster
+	i       int
+}
+
+func (r *restarter) Restart() {
+	go func() {
+		// The node may have been stopped already
+		_ = r.cluster.RestartNode(r.i)
+	}()
+}
+
+// defaultConsensusParams returns consensus parameters that suit a cluster of
+// [numNodes] nodes. Every node is sampled, and a majority of them is needed
+// to finalize.
+func defaultConsensusParams(numNodes int) avalanche.Parameters {
+	return avalanche.Parameters{
+		Parameters: snowball.Parameters{
+			Namespace:         constants.PlatformName,
+			K:                 numNodes,
+			Alpha:             numNodes/2 + 1,
+			BetaVirtuous:      3,
+			BetaRogue:         5,
+			ConcurrentRepolls: 3,
+			OptimalProcessing: 50,
+		},
+		Parents:   5,
+		BatchSize: 30,
+	}
+}
+
+// loggingConfig returns the logging config of a node that logs to [dir] and
+// doesn't display its logs
+func loggingConfig(dir string) logging.Config {
+	return logging.Config{
+		RotationInterval:  24 * time.Hour,
+		FileSize:          1 << 23, // 8 MB
+		RotationSize:      7,
+		FlushSize:         1,
+		DisableDisplaying: true,
+		LogLevel:          logging.Info,
+		DisplayLevel:      logging.Off,
+		DisplayHighlight:  logging.Plain,
+		Directory:         filepath.Join(dir, "logs"),
+	}
+}
+
+// certToID returns the ID of the node that stakes with the certificate in
+// [certFile]
+func certToID(certFile string) (ids.ShortID, error) {
+	certBytes, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	block, _ := pem.Decode(certBytes)
+	if block == nil {
+		return ids.ShortID{}, errNoCert
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	return ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+}
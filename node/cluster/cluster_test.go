@@ -0,0 +1,315 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cluster
+
+import (
+	"crypto"
+	"crypto/tls"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/keystore"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/simulator"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/proposervm"
+)
+
+const (
+	// Funded by the genesis of the local network
+	testKey = "PrivateKey-ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN"
+	testTo  = "X-local1g65uqn6t77p656w64023nh8nd9updzmxyymev2"
+	// Address of [testKey]
+	testControlKey = "P-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"
+
+	testTimeout = 30 * time.Second
+)
+
+func newTestCluster(t *testing.T, numNodes int) *Cluster {
+	if testing.Short() {
+		t.Skip("runs a cluster of full nodes")
+	}
+
+	dir, err := ioutil.TempDir("", "cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	c, err := New(Config{
+		NumNodes: numNodes,
+		Dir:      dir,
+		Link:     simulator.Link{Latency: 10 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := c.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WaitConnected(testTimeout); err != nil {
+		t.Fatalf("nodes didn't connect: %s", err)
+	}
+	if err := c.WaitBootstrapped("X", testTimeout); err != nil {
+		t.Fatalf("nodes didn't bootstrap: %s", err)
+	}
+	return c
+}
+
+// send issues a tx to the X-Chain through [n]
+func send(t *testing.T, n *Node) ids.ID {
+	user := api.UserPass{
+		Username: "cluster",
+		Password: "jOn9dgBdTTc3Tc8QYHxU",
+	}
+	if _, err := keystore.NewClient(n.URI, apiRequestTimeout).CreateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	client := avm.NewClient(n.URI, "X", apiRequestTimeout)
+	if _, err := client.ImportKey(user, testKey); err != nil {
+		t.Fatal(err)
+	}
+	txID, err := client.Send(user, nil, "", units.Avax, "AVAX", testTo, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txID
+}
+
+// createSubnet issues a tx to the P-Chain through [n]
+func createSubnet(t *testing.T, n *Node) ids.ID {
+	user := api.UserPass{
+		Username: "cluster",
+		Password: "jOn9dgBdTTc3Tc8QYHxU",
+	}
+	if _, err := keystore.NewClient(n.URI, apiRequestTimeout).CreateUser(user); err != nil {
+		t.Fatal(err)
+	}
+	client := platformvm.NewClient(n.URI, apiRequestTimeout)
+	if _, err := client.ImportKey(user, testKey); err != nil {
+		t.Fatal(err)
+	}
+	txID, err := client.CreateSubnet(user, nil, "", []string{testControlKey}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txID
+}
+
+// waitAccepted blocks until every node of [nodes] accepted [txID]
+func waitAccepted(nodes []*Node, txID ids.ID) error {
+	return wait(testTimeout, func() (bool, error) {
+		for _, n := range nodes {
+			status, err := avm.NewClient(n.URI, "X", apiRequestTimeout).GetTxStatus(txID)
+			// The X-Chain's API isn't served while it bootstraps
+			if err != nil || status != choices.Accepted {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+func TestClusterPartition(t *testing.T) {
+	c := newTestCluster(t, 5)
+
+	// Cutting off at most 30% of the stake leaves enough stake to finalize
+	cutOff, err := c.PartitionStake(.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connected, partitioned := []*Node(nil), []*Node(nil)
+	totalWeight, cutOffWeight := uint64(0), uint64(0)
+	for _, n := range c.Nodes {
+		totalWeight += n.Weight
+		if cutOff.Contains(n.ID) {
+			cutOffWeight += n.Weight
+			partitioned = append(partitioned, n)
+		} else {
+			connected = append(connected, n)
+		}
+	}
+	if len(partitioned) == 0 || len(partitioned) != cutOff.Len() {
+		t.Fatalf("expected %d cut off nodes of the cluster but got %d", cutOff.Len(), len(partitioned))
+	}
+	if fraction := float64(cutOffWeight) / float64(totalWeight); fraction > .3 {
+		t.Fatalf("expected at most 30%% of the stake to be cut off but got %.1f%%", 100*fraction)
+	}
+	// Cutting off one more node would have exceeded 30% of the stake
+	if next := connected[len(connected)-1]; float64(cutOffWeight+next.Weight) <= .3*float64(totalWeight) {
+		t.Fatalf("expected %s to be cut off", next.ID)
+	}
+
+	txID := send(t, connected[0])
+	if err := waitAccepted(connected, txID); err != nil {
+		t.Fatalf("tx wasn't accepted during the partition: %s", err)
+	}
+	for _, n := range partitioned {
+		status, err := avm.NewClient(n.URI, "X", apiRequestTimeout).GetTxStatus(txID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status == choices.Accepted {
+			t.Fatalf("%s accepted the tx across the partition", n.ID)
+		}
+	}
+
+	// Once the partition heals, the nodes that were cut off catch up
+	c.Heal()
+	if err := waitAccepted(c.Nodes, txID); err != nil {
+		t.Fatalf("tx wasn't accepted after the partition healed: %s", err)
+	}
+}
+
+func TestClusterRestart(t *testing.T) {
+	c := newTestCluster(t, 3)
+
+	if err := c.StopNode(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.StopNode(2); err == nil {
+		t.Fatal("stopped a node that wasn't running")
+	}
+
+	txID := send(t, c.Nodes[0])
+	if err := waitAccepted(c.Nodes[:2], txID); err != nil {
+		t.Fatalf("tx wasn't accepted while a node was stopped: %s", err)
+	}
+
+	// The node restarts with its state and learns of the tx from its peers
+	if err := c.StartNode(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitAccepted(c.Nodes, txID); err != nil {
+		t.Fatalf("tx wasn't accepted by the restarted node: %s", err)
+	}
+}
+
+// On the local network, the P-Chain's blocks are proposed in proposer windows
+func TestClusterProposerWindows(t *testing.T) {
+	c := newTestCluster(t, 3)
+	if err := c.WaitBootstrapped("P", testTimeout); err != nil {
+		t.Fatalf("nodes didn't bootstrap: %s", err)
+	}
+
+	txID := createSubnet(t, c.Nodes[0])
+	err := wait(testTimeout, func() (bool, error) {
+		for _, n := range c.Nodes {
+			status, err := platformvm.NewClient(n.URI, apiRequestTimeout).GetTxStatus(txID, false)
+			if err != nil || status.Status != platformvm.Committed {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("tx wasn't committed: %s", err)
+	}
+
+	ctx, vmIntf, ok := c.Nodes[0].Chain("P")
+	if !ok {
+		t.Fatal("node didn't create the P-Chain")
+	}
+	vm, ok := vmIntf.(*proposervm.StateSyncableVM)
+	if !ok {
+		t.Fatalf("expected the P-Chain to run in proposer windows but its VM is a %T", vmIntf)
+	}
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	// Each node proposes a child of the last accepted block as soon as the
+	// windows of its children start. Only the first proposer's window started
+	// by then.
+	parentID := vm.LastAccepted()
+	windowsBlk := signedBlock(t, vm, parentID)
+	pChainHeight, err := vm.ChainVM.(validators.State).GetCurrentHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	early := 0
+	for _, n := range c.Nodes {
+		key, err := tls.LoadX509KeyPair(n.certFile, n.keyFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stateless := &proposervm.SignedBlock{
+			ParentID:     parentID,
+			Timestamp:    windowsBlk.Timestamp,
+			PChainHeight: pChainHeight,
+			Certificate:  key.Certificate[0],
+			// Not a child of the parent's inner block, so even the first
+			// proposer's block is invalid once its window is checked
+			Block: windowsBlk.Block,
+		}
+		if err := stateless.Sign(key.PrivateKey.(crypto.Signer)); err != nil {
+			t.Fatal(err)
+		}
+		blkBytes, err := stateless.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		blk, err := vm.ParseBlock(blkBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch err := blk.Verify(); {
+		case err == nil:
+			t.Fatal("verified a block whose inner block isn't a child of its parent's")
+		case errors.Is(err, proposervm.ErrWindowNotStarted):
+			early++
+		}
+	}
+	if early != len(c.Nodes)-1 {
+		t.Fatalf("expected %d blocks to be rejected as proposed before their windows but %d were", len(c.Nodes)-1, early)
+	}
+}
+
+// signedBlock returns the block of [vm] that the windows of the children of
+// [blkID] start at, which is the block itself unless it's an option
+func signedBlock(t *testing.T, vm *proposervm.StateSyncableVM, blkID ids.ID) *proposervm.SignedBlock {
+	blk, err := vm.GetBlock(blkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stateless interface{}
+	if _, err := proposervm.Codec.Unmarshal(blk.Bytes(), &stateless); err != nil {
+		t.Fatal(err)
+	}
+	switch stateless := stateless.(type) {
+	case *proposervm.SignedBlock:
+		return stateless
+	case *proposervm.OptionBlock:
+		return signedBlock(t, vm, stateless.ParentID)
+	default:
+		t.Fatalf("unexpected block type %T", stateless)
+		return nil
+	}
+}
+
+func TestClusterPartitionStakeInvalidFraction(t *testing.T) {
+	c := &Cluster{}
+	if _, err := c.PartitionStake(1); err != errInvalidFraction {
+		t.Fatalf("expected %s but got %s", errInvalidFraction, err)
+	}
+
+	// Every node holds a third of the stake
+	c.Nodes = []*Node{{Weight: 1}, {Weight: 1}, {Weight: 1}}
+	if _, err := c.PartitionStake(.3); err != errFractionTooLow {
+		t.Fatalf("expected %s but got %s", errFractionTooLow, err)
+	}
+}
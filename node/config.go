@@ -4,12 +4,15 @@
 package node
 
 import (
+	"net"
 	"time"
 
+	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/nat"
+	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
@@ -17,6 +20,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/dynamicip"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
 )
 
 // Config contains all of the configurations of an Avalanche node.
@@ -48,11 +52,16 @@ type Config struct {
 	// Staking configuration
 	StakingIP             utils.DynamicIPDesc
 	EnableP2PTLS          bool
+	P2PTransport          string
 	EnableStaking         bool
 	StakingKeyFile        string
 	StakingCertFile       string
 	DisabledStakingWeight uint64
 
+	// If non-nil, the node connects to its peers with this transport rather
+	// than listening on its staking port
+	Transport *Transport
+
 	// Throttling
 	MaxNonStakerPendingMsgs uint32
 	StakerMSGPortion        float64
@@ -63,6 +72,20 @@ type Config struct {
 	// Network configuration
 	NetworkConfig timer.AdaptiveTimeoutConfig
 
+	// True iff large messages should be compressed when sent to peers that
+	// support compression
+	NetworkCompressionEnabled bool
+
+	// Limits on how quickly peers may send us messages
+	InboundThrottlerConfig network.InboundThrottlerConfig
+
+	// The nodes we may connect to. The allowlist is read from its file when
+	// the node starts.
+	AllowlistConfig network.AllowlistConfig
+
+	// Peer scoring and banning configuration
+	ReputationConfig network.ReputationConfig
+
 	// Benchlist Configuration
 	BenchlistConfig benchlist.Config
 
@@ -72,6 +95,9 @@ type Config struct {
 	// HTTP configuration
 	HTTPHost string
 	HTTPPort uint16
+	// If non-nil, the API server serves HTTP on this listener rather than
+	// listening on [HTTPHost]:[HTTPPort]
+	HTTPListener net.Listener
 
 	HTTPSEnabled        bool
 	HTTPSKeyFile        string
@@ -104,10 +130,35 @@ type Config struct {
 	IPCPath            string
 	IPCDefaultChainIDs []string
 
+	// PubSub configuration
+	PubSubAPIEnabled       bool
+	PubSubMaxSubscriptions int
+	PubSubBufferSize       int
+
+	// Indexer configuration
+	IndexAPIEnabled bool
+	// True iff the X-Chain should index the txs of each address
+	IndexTransactions bool
+
+	// True iff chains should sync their state from a summary when possible
+	StateSyncEnabled bool
+
+	// Number of recently accepted P-Chain heights whose state is kept
+	StateHistoryDepth uint64
+
+	// How the fees of the X-Chain and P-Chain respond to their usage
+	FeeConfig fees.Config
+
 	// Router that is used to handle incoming consensus messages
 	ConsensusRouter          router.Router
 	ConsensusGossipFrequency time.Duration
 	ConsensusShutdownTimeout time.Duration
+	// Directory of the consensus trace of each chain. Consensus isn't traced
+	// if empty.
+	ConsensusTraceDir string
+
+	// Notified of each chain the node creates, before the chain starts
+	ChainRegistrants []chains.Registrant
 
 	// Dynamic Update duration for IP or NAT traversal
 	DynamicUpdateDuration time.Duration
@@ -30,8 +30,11 @@ import (
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/indexer"
 	"github.com/ava-labs/avalanchego/ipcs"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/pubsub"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
@@ -47,6 +50,7 @@ import (
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms"
 	"github.com/ava-labs/avalanchego/vms/avm"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
 	"github.com/ava-labs/avalanchego/vms/evm"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
@@ -60,14 +64,15 @@ import (
 
 // Networking constants
 const (
-	TCP = "tcp"
+	TCP  = "tcp"
+	QUIC = "quic"
 )
 
 var (
 	genesisHashKey = []byte("genesisID")
 
 	// Version is the version of this code
-	Version                 = version.NewDefaultVersion(constants.PlatformName, 1, 2, 0)
+	Version                 = version.NewDefaultVersion(constants.PlatformName, 1, 2, 1)
 	versionParser           = version.NewDefaultParser()
 	beaconConnectionTimeout = 1 * time.Minute
 )
@@ -100,15 +105,28 @@ type Node struct {
 	// Manages Virtual Machines
 	vmManager vms.Manager
 
+	// Current fees of the chains whose fees respond to usage
+	feeRegistry *fees.Registry
+
 	// dispatcher for events as they happen in consensus
 	DecisionDispatcher  *triggers.EventDispatcher
 	ConsensusDispatcher *triggers.EventDispatcher
 
 	IPCs *ipcs.ChainIPCs
 
+	// Indexes accepted containers. Nil if indexing is disabled.
+	indexer *indexer.Indexer
+
+	// Streams accepted txs to websocket clients. Nil if the pubsub API is
+	// disabled.
+	pubsubServer *pubsub.Server
+
 	// Net runs the networking stack
 	Net network.Network
 
+	// Staking certificate and key of this node. nil if P2P TLS is disabled.
+	stakingCert *tls.Certificate
+
 	// this node's initial connections to the network
 	beacons validators.Set
 
@@ -145,18 +163,22 @@ type Node struct {
  */
 
 func (n *Node) initNetworking() error {
-	listener, err := net.Listen(TCP, fmt.Sprintf(":%d", n.Config.StakingIP.Port))
-	if err != nil {
-		return err
-	}
-	dialer := network.NewDialer(TCP)
-
-	var serverUpgrader, clientUpgrader network.Upgrader
+	var (
+		listener                       net.Listener
+		dialer                         network.Dialer
+		serverUpgrader, clientUpgrader network.Upgrader
+		// signs the IPs we send to peers. nil if TLS is disabled.
+		stakingCert *tls.Certificate
+		err         error
+	)
+	addr := fmt.Sprintf(":%d", n.Config.StakingIP.Port)
 	if n.Config.EnableP2PTLS {
 		cert, err := tls.LoadX509KeyPair(n.Config.StakingCertFile, n.Config.StakingKeyFile)
 		if err != nil {
 			return err
 		}
+		stakingCert = &cert
+		n.stakingCert = stakingCert
 
 		// #nosec G402
 		tlsConfig := &tls.Config{
@@ -171,12 +193,44 @@ func (n *Node) initNetworking() error {
 			InsecureSkipVerify: true,
 		}
 
-		serverUpgrader = network.NewTLSServerUpgrader(tlsConfig)
-		clientUpgrader = network.NewTLSClientUpgrader(tlsConfig)
+		if n.Config.P2PTransport == QUIC && n.Config.Transport == nil {
+			listener, err = network.NewQUICListener(addr, tlsConfig)
+			if err != nil {
+				return err
+			}
+			dialer = network.NewQUICDialer(tlsConfig)
+			serverUpgrader = network.NewQUICUpgrader()
+			clientUpgrader = network.NewQUICUpgrader()
+		} else {
+			serverUpgrader = network.NewTLSServerUpgrader(tlsConfig)
+			clientUpgrader = network.NewTLSClientUpgrader(tlsConfig)
+		}
 	} else {
 		serverUpgrader = network.NewIPUpgrader()
 		clientUpgrader = network.NewIPUpgrader()
 	}
+	switch transport := n.Config.Transport; {
+	case transport != nil:
+		listener = transport.Listener
+		dialer = transport.Dialer
+		serverUpgrader = transport.ServerUpgrader
+		clientUpgrader = transport.ClientUpgrader
+	case listener == nil:
+		listener, err = net.Listen(TCP, addr)
+		if err != nil {
+			return err
+		}
+		dialer = network.NewDialer(TCP)
+	}
+
+	if n.Config.AllowlistConfig.File != "" {
+		nodeIDs, err := network.ReadAllowlist(n.Config.AllowlistConfig.File)
+		if err != nil {
+			return err
+		}
+		n.Config.AllowlistConfig.NodeIDs = nodeIDs
+		n.Log.Info("read the allowlist of %d nodes", nodeIDs.Len())
+	}
 
 	// Initialize validator manager and primary network's validator set
 	primaryNetworkValidators := validators.NewSet()
@@ -246,6 +300,14 @@ func (n *Node) initNetworking() error {
 		n.Config.DisconnectedRestartTimeout,
 		n.Config.ApricotPhase0Time,
 		n.Config.SendQueueSize,
+		n.Config.NetworkCompressionEnabled,
+		n.Config.InboundThrottlerConfig,
+		stakingCert,
+		n.Config.AllowlistConfig,
+		n.Config.WhitelistedSubnets,
+		n.vdrs,
+		n.Config.ReputationConfig,
+		prefixdb.New([]byte("peer bans"), n.DB),
 	)
 
 	n.nodeCloser = utils.HandleSignals(func(os.Signal) {
@@ -326,7 +388,12 @@ func (n *Node) Dispatch() error {
 		}
 
 		n.Log.Debug("initializing API server without TLS")
-		err := n.APIServer.Dispatch()
+		var err error
+		if n.Config.HTTPListener != nil {
+			err = n.APIServer.Serve(n.Config.HTTPListener)
+		} else {
+			err = n.APIServer.Dispatch()
+		}
 
 		// When [n].Shutdown() is called, [n.APIServer].Close() is called.
 		// This causes [n.APIServer].Dispatch() to return an error.
@@ -463,6 +530,53 @@ func (n *Node) initIPCs() error {
 	return err
 }
 
+// initIndexer initializes the indexer, which stores accepted containers of
+// each chain and exposes them through the Index API.
+// Assumes n.DB, n.chainManager and the event dispatchers are initialized.
+// Must be called before any chains are created.
+func (n *Node) initIndexer() error {
+	if !n.Config.IndexAPIEnabled {
+		n.Log.Info("skipping indexer initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing indexer")
+	var err error
+	n.indexer, err = indexer.NewIndexer(indexer.Config{
+		DB:                  prefixdb.New([]byte("index"), n.DB),
+		Log:                 n.Log,
+		DecisionDispatcher:  n.DecisionDispatcher,
+		ConsensusDispatcher: n.ConsensusDispatcher,
+		APIServer:           &n.APIServer,
+	})
+	if err != nil {
+		return err
+	}
+	// Start indexing chains as they are created. The indexer must register a
+	// chain before it accepts anything, so it's notified inline.
+	n.chainManager.AddBlockingRegistrant(n.indexer)
+	return nil
+}
+
+// initPubSubAPI streams the txs accepted by chains that support it to
+// websocket clients subscribed to their addresses
+func (n *Node) initPubSubAPI() error {
+	if !n.Config.PubSubAPIEnabled {
+		n.Log.Info("skipping pubsub API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing pubsub API")
+	n.pubsubServer = pubsub.NewServer(pubsub.Config{
+		Log:                n.Log,
+		DecisionDispatcher: n.DecisionDispatcher,
+		MaxSubscriptions:   n.Config.PubSubMaxSubscriptions,
+		BufferSize:         n.Config.PubSubBufferSize,
+	})
+	// Start streaming chains as they are created
+	n.chainManager.AddRegistrant(n.pubsubServer)
+	handler := &common.HTTPHandler{LockOptions: common.NoLock, Handler: n.pubsubServer}
+	return n.APIServer.AddRoute(handler, &sync.RWMutex{}, "pubsub", "", n.HTTPLog)
+}
+
 // Initializes the Platform chain.
 // Its genesis data specifies the other chains that should
 // be created.
@@ -500,6 +614,7 @@ func (n *Node) initAPIServer() error {
 // Assumes n.DB, n.vdrs all initialized (non-nil)
 func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 	n.vmManager = vms.NewManager(&n.APIServer, n.HTTPLog)
+	n.feeRegistry = fees.NewRegistry()
 
 	createAVMTx, err := genesis.VMGenesis(n.Config.GenesisBytes, avm.ID)
 	if err != nil {
@@ -535,6 +650,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		n.Config.ConsensusShutdownTimeout,
 		criticalChains,
 		n.Shutdown,
+		n.Net,
 	)
 
 	n.chainManager = chains.New(&chains.ManagerConfig{
@@ -566,6 +682,10 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		TimeoutManager:          &timeoutManager,
 		HealthService:           n.healthService,
 		WhitelistedSubnets:      n.Config.WhitelistedSubnets,
+		StateSyncEnabled:        n.Config.StateSyncEnabled,
+		ProposerWindowsTime:     n.Config.ProposerWindowsTime,
+		StakingCert:             n.stakingCert,
+		ConsensusTraceDir:       n.Config.ConsensusTraceDir,
 	})
 
 	vdrs := n.vdrs
@@ -595,10 +715,17 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 			MaxStakeDuration:   n.Config.MaxStakeDuration,
 			StakeMintingPeriod: n.Config.StakeMintingPeriod,
 			ApricotPhase0Time:  n.Config.ApricotPhase0Time,
+			MempoolGossipTime:  n.Config.MempoolGossipTime,
+			StateHistoryDepth:  n.Config.StateHistoryDepth,
+			FeeConfig:          n.Config.FeeConfig,
+			FeeRegistry:        n.feeRegistry,
 		}),
 		n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{
-			CreationFee: n.Config.CreationTxFee,
-			Fee:         n.Config.TxFee,
+			CreationFee:       n.Config.CreationTxFee,
+			Fee:               n.Config.TxFee,
+			IndexTransactions: n.Config.IndexTransactions,
+			FeeConfig:         n.Config.FeeConfig,
+			FeeRegistry:       n.feeRegistry,
 		}),
 		n.vmManager.RegisterVMFactory(evm.ID, &rpcchainvm.Factory{
 			Path:   filepath.Join(n.Config.PluginDir, "evm"),
@@ -615,6 +742,9 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 
 	// Notify the API server when new chains are created
 	n.chainManager.AddRegistrant(&n.APIServer)
+	for _, registrant := range n.Config.ChainRegistrants {
+		n.chainManager.AddRegistrant(registrant)
+	}
 	return nil
 }
 
@@ -677,7 +807,7 @@ func (n *Node) initAdminAPI() error {
 		return nil
 	}
 	n.Log.Info("initializing admin API")
-	service, err := admin.NewService(n.Log, n.chainManager, &n.APIServer)
+	service, err := admin.NewService(n.Log, n.chainManager, n.Net, &n.APIServer)
 	if err != nil {
 		return err
 	}
@@ -699,6 +829,7 @@ func (n *Node) initInfoAPI() error {
 		n.Net,
 		n.Config.CreationTxFee,
 		n.Config.TxFee,
+		n.feeRegistry,
 	)
 	if err != nil {
 		return err
@@ -866,6 +997,12 @@ func (n *Node) Initialize(
 	if err := n.initIPCAPI(); err != nil { // Start the IPC API
 		return fmt.Errorf("couldn't initialize the IPC API: %w", err)
 	}
+	if err := n.initIndexer(); err != nil { // Start the indexer
+		return fmt.Errorf("couldn't initialize indexer: %w", err)
+	}
+	if err := n.initPubSubAPI(); err != nil { // Start the PubSub API
+		return fmt.Errorf("couldn't initialize the pubsub API: %w", err)
+	}
 	if err := n.initAliases(n.Config.GenesisBytes); err != nil { // Set up aliases
 		return fmt.Errorf("couldn't initialize aliases: %w", err)
 	}
@@ -892,6 +1029,16 @@ func (n *Node) shutdown() {
 	if n.chainManager != nil {
 		n.chainManager.Shutdown()
 	}
+	if n.indexer != nil {
+		if err := n.indexer.Close(); err != nil {
+			n.Log.Debug("error during indexer shutdown: %s", err)
+		}
+	}
+	if n.pubsubServer != nil {
+		if err := n.pubsubServer.Close(); err != nil {
+			n.Log.Debug("error during pubsub server shutdown: %s", err)
+		}
+	}
 	if n.Net != nil {
 		// Close already logs its own error if one occurs, so the error is ignored here
 		_ = n.Net.Close()
@@ -0,0 +1,22 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"net"
+
+	"github.com/ava-labs/avalanchego/network"
+)
+
+// Transport contains how a node connects to its peers, such as when the node
+// runs on a simulated network
+type Transport struct {
+	// Accepts the connections of peers
+	Listener net.Listener
+	// Makes connections to peers
+	Dialer network.Dialer
+	// Identify the peers of accepted and dialed connections
+	ServerUpgrader network.Upgrader
+	ClientUpgrader network.Upgrader
+}
@@ -0,0 +1,283 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	// Time allowed to write a message to the peer.
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer.
+	pongWait = 60 * time.Second
+
+	// Send pings to peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+
+	// Maximum size of a command from the peer. Large enough for a command
+	// with the max number of addresses or a hex encoded max size bloom filter.
+	maxCommandSize = 256 * 1024 // bytes
+
+	// MaxSubscriptionAddresses is the max number of addresses a single
+	// subscription can list. Clients that follow more addresses should use a
+	// bloom filter.
+	MaxSubscriptionAddresses = 1024
+)
+
+var (
+	errNoFilter             = errors.New("subscription must provide addresses or a bloom filter")
+	errTooManyAddresses     = fmt.Errorf("subscription can't list more than %d addresses", MaxSubscriptionAddresses)
+	errTooManySubscriptions = errors.New("connection has too many subscriptions")
+	errUnknownSubscription  = errors.New("unknown subscription")
+)
+
+// Command is sent by a client to add or remove a subscription
+type Command struct {
+	// Client chosen ID of the subscription. Subscribing with an ID that is
+	// already in use replaces that subscription.
+	ID          json.Uint32 `json:"id"`
+	Unsubscribe bool        `json:"unsubscribe"`
+
+	// Addresses, such as X-avax1..., whose txs should be sent. The chain
+	// alias of each address is ignored, so txs on all chains are sent.
+	Addresses []string `json:"addresses"`
+	// Bloom filter of addresses whose txs should be sent. Only used if no
+	// [Addresses] are given.
+	Bloom *BloomArgs `json:"bloom"`
+}
+
+// BloomArgs describes a BloomFilter
+type BloomArgs struct {
+	Filter    string              `json:"filter"`
+	NumHashes json.Uint32         `json:"numHashes"`
+	Encoding  formatting.Encoding `json:"encoding"`
+}
+
+// Response is sent to a client after it sends a Command
+type Response struct {
+	ID    json.Uint32 `json:"id"`
+	Error string      `json:"error,omitempty"`
+}
+
+// Notification is sent to a client when a tx matching one of its
+// subscriptions is accepted
+type Notification struct {
+	// IDs of the subscriptions the tx matches
+	Subscriptions []json.Uint32       `json:"subscriptions"`
+	ChainID       ids.ID              `json:"chainID"`
+	TxID          ids.ID              `json:"txID"`
+	Tx            string              `json:"tx"`
+	Encoding      formatting.Encoding `json:"encoding"`
+}
+
+// connection is a client's websocket connection
+type connection struct {
+	s *Server
+
+	// The websocket connection.
+	conn *websocket.Conn
+
+	// Subscription ID --> filter. Guarded by [s.lock].
+	subscriptions map[json.Uint32]Filter
+
+	// Buffered channel of outbound messages.
+	send chan interface{}
+
+	// Closed when the connection should be closed
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+func newConnection(s *Server, conn *websocket.Conn) *connection {
+	return &connection{
+		s:             s,
+		conn:          conn,
+		subscriptions: make(map[json.Uint32]Filter),
+		send:          make(chan interface{}, s.bufferSize),
+		quit:          make(chan struct{}),
+	}
+}
+
+// matches returns the IDs of the subscriptions that match any of [addrs], in
+// increasing order. Assumes [c.s.lock] is held.
+func (c *connection) matches(addrs [][]byte) []json.Uint32 {
+	subscriptionIDs := []json.Uint32(nil)
+	for subscriptionID, filter := range c.subscriptions {
+		for _, addr := range addrs {
+			if filter.Check(addr) {
+				subscriptionIDs = append(subscriptionIDs, subscriptionID)
+				break
+			}
+		}
+	}
+	sort.Slice(subscriptionIDs, func(i, j int) bool { return subscriptionIDs[i] < subscriptionIDs[j] })
+	return subscriptionIDs
+}
+
+// queue [msg] to be sent to the client. If the client is too far behind, the
+// connection is closed instead.
+func (c *connection) queue(msg interface{}) {
+	select {
+	case c.send <- msg:
+	default:
+		c.s.log.Debug("closing pubsub connection because it has too many pending messages")
+		c.close()
+	}
+}
+
+// close the connection. Safe to call multiple times.
+func (c *connection) close() {
+	c.closeOnce.Do(func() { close(c.quit) })
+}
+
+// handle [cmd] and return the error to report to the client, if any
+func (c *connection) handle(cmd *Command) error {
+	if cmd.Unsubscribe {
+		c.s.lock.Lock()
+		defer c.s.lock.Unlock()
+
+		if _, exists := c.subscriptions[cmd.ID]; !exists {
+			return errUnknownSubscription
+		}
+		delete(c.subscriptions, cmd.ID)
+		return nil
+	}
+
+	filter, err := parseFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	c.s.lock.Lock()
+	defer c.s.lock.Unlock()
+
+	if _, exists := c.subscriptions[cmd.ID]; !exists && len(c.subscriptions) >= c.s.maxSubscriptions {
+		return errTooManySubscriptions
+	}
+	c.subscriptions[cmd.ID] = filter
+	return nil
+}
+
+func parseFilter(cmd *Command) (Filter, error) {
+	switch {
+	case len(cmd.Addresses) > MaxSubscriptionAddresses:
+		return nil, errTooManyAddresses
+	case len(cmd.Addresses) > 0:
+		addrs := make([]ids.ShortID, len(cmd.Addresses))
+		for i, addrStr := range cmd.Addresses {
+			_, _, addrBytes, err := formatting.ParseAddress(addrStr)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+			}
+			addrs[i], err = ids.ToShortID(addrBytes)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse address %q: %w", addrStr, err)
+			}
+		}
+		return newAddressFilter(addrs), nil
+	case cmd.Bloom != nil:
+		bits, err := formatting.Decode(cmd.Bloom.Encoding, cmd.Bloom.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't decode bloom filter: %w", err)
+		}
+		return NewBloomFilter(bits, int(cmd.Bloom.NumHashes))
+	default:
+		return nil, errNoFilter
+	}
+}
+
+// readPump reads commands from the websocket connection.
+//
+// The application runs readPump in a per-connection goroutine. The application
+// ensures that there is at most one reader on a connection by executing all
+// reads from this goroutine.
+func (c *connection) readPump() {
+	defer func() {
+		c.s.removeConnection(c)
+		c.close()
+		// close is called by both the writePump and the readPump so one of them
+		// will always error
+		_ = c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxCommandSize)
+	// SetReadDeadline returns an error if the connection is corrupted
+	if err := c.conn.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
+		return
+	}
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		cmd := Command{}
+		if err := c.conn.ReadJSON(&cmd); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.s.log.Debug("unexpected close in pubsub websocket: %s", err)
+			}
+			return
+		}
+
+		response := &Response{ID: cmd.ID}
+		if err := c.handle(&cmd); err != nil {
+			response.Error = err.Error()
+		}
+		c.queue(response)
+	}
+}
+
+// writePump writes queued messages to the websocket connection.
+//
+// A goroutine running writePump is started for each connection. The
+// application ensures that there is at most one writer to a connection by
+// executing all writes from this goroutine.
+func (c *connection) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		// close is called by both the writePump and the readPump so one of them
+		// will always error
+		_ = c.conn.Close()
+	}()
+	for {
+		select {
+		case msg := <-c.send:
+			if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				c.s.log.Debug("failed to set the write deadline, closing the connection due to %s", err)
+				return
+			}
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+				c.s.log.Debug("failed to set the write deadline, closing the connection due to %s", err)
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.quit:
+			// Attempt to close the connection gracefully.
+			_ = c.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(writeWait),
+			)
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+const (
+	// MaxBloomBytes is the max size of a bloom filter a subscriber can provide
+	MaxBloomBytes = 64 * 1024
+
+	// MaxBloomHashes is the max number of hash functions a bloom filter can use
+	MaxBloomHashes = 16
+)
+
+var (
+	errEmptyBloom       = errors.New("bloom filter is empty")
+	errBloomTooLarge    = errors.New("bloom filter is too large")
+	errInvalidNumHashes = errors.New("invalid number of bloom filter hashes")
+)
+
+// Filter decides which accepted txs are sent to a subscription
+type Filter interface {
+	// Check returns true iff a tx that touched [addr] should be sent
+	Check(addr []byte) bool
+}
+
+// addressFilter matches a fixed set of addresses
+type addressFilter struct {
+	addrs ids.ShortSet
+}
+
+func newAddressFilter(addrs []ids.ShortID) *addressFilter {
+	f := &addressFilter{addrs: ids.ShortSet{}}
+	f.addrs.Add(addrs...)
+	return f
+}
+
+func (f *addressFilter) Check(addr []byte) bool {
+	shortID, err := ids.ToShortID(addr)
+	return err == nil && f.addrs.Contains(shortID)
+}
+
+// BloomFilter is a bloom filter of addresses. It lets a subscriber follow a
+// large set of addresses, or hide exactly which addresses it follows, at the
+// cost of receiving some txs that don't touch any of its addresses.
+//
+// The filter has m = 8*len(bits) bits. The i'th of the k hash functions maps
+// an address to bit (h1 + i*h2) mod m, where h1 and h2 are the first and
+// second big-endian uint64s of the SHA-256 hash of the address. Bit b is the
+// (b mod 8)'th least significant bit of bits[b/8].
+type BloomFilter struct {
+	bits      []byte
+	numHashes int
+}
+
+// NewBloomFilter returns a bloom filter with the given bits that uses
+// [numHashes] hash functions. Addresses may already have been added to [bits].
+func NewBloomFilter(bits []byte, numHashes int) (*BloomFilter, error) {
+	switch {
+	case len(bits) == 0:
+		return nil, errEmptyBloom
+	case len(bits) > MaxBloomBytes:
+		return nil, errBloomTooLarge
+	case numHashes <= 0 || numHashes > MaxBloomHashes:
+		return nil, errInvalidNumHashes
+	}
+	return &BloomFilter{
+		bits:      bits,
+		numHashes: numHashes,
+	}, nil
+}
+
+// Add [addr] to the filter
+func (f *BloomFilter) Add(addr []byte) {
+	h1, h2 := bloomHashes(addr)
+	numBits := uint64(len(f.bits)) * 8
+	for i := 0; i < f.numHashes; i++ {
+		bit := (h1 + uint64(i)*h2) % numBits
+		f.bits[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Check returns true if [addr] may have been added to the filter
+func (f *BloomFilter) Check(addr []byte) bool {
+	h1, h2 := bloomHashes(addr)
+	numBits := uint64(len(f.bits)) * 8
+	for i := 0; i < f.numHashes; i++ {
+		bit := (h1 + uint64(i)*h2) % numBits
+		if f.bits[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes returns the bits of the filter
+func (f *BloomFilter) Bytes() []byte { return f.bits }
+
+func bloomHashes(addr []byte) (uint64, uint64) {
+	hash := hashing.ComputeHash256(addr)
+	return binary.BigEndian.Uint64(hash[:8]), binary.BigEndian.Uint64(hash[8:16])
+}
@@ -0,0 +1,66 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestAddressFilter(t *testing.T) {
+	addr := ids.GenerateTestShortID()
+	filter := newAddressFilter([]ids.ShortID{addr})
+
+	if !filter.Check(addr.Bytes()) {
+		t.Fatalf("filter should match %s", addr)
+	}
+	if filter.Check(ids.GenerateTestShortID().Bytes()) {
+		t.Fatalf("filter shouldn't match an unknown address")
+	}
+	if filter.Check([]byte{1, 2, 3}) {
+		t.Fatalf("filter shouldn't match a malformed address")
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	if _, err := NewBloomFilter(nil, 1); err != errEmptyBloom {
+		t.Fatalf("expected %s but got %s", errEmptyBloom, err)
+	}
+	if _, err := NewBloomFilter(make([]byte, MaxBloomBytes+1), 1); err != errBloomTooLarge {
+		t.Fatalf("expected %s but got %s", errBloomTooLarge, err)
+	}
+	if _, err := NewBloomFilter(make([]byte, 8), 0); err != errInvalidNumHashes {
+		t.Fatalf("expected %s but got %s", errInvalidNumHashes, err)
+	}
+	if _, err := NewBloomFilter(make([]byte, 8), MaxBloomHashes+1); err != errInvalidNumHashes {
+		t.Fatalf("expected %s but got %s", errInvalidNumHashes, err)
+	}
+
+	filter, err := NewBloomFilter(make([]byte, 1024), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs := make([]ids.ShortID, 16)
+	for i := range addrs {
+		addrs[i] = ids.GenerateTestShortID()
+		filter.Add(addrs[i].Bytes())
+	}
+	for _, addr := range addrs {
+		if !filter.Check(addr.Bytes()) {
+			t.Fatalf("filter should match %s", addr)
+		}
+	}
+
+	// A filter built from the bytes of another should match the same addresses
+	copied, err := NewBloomFilter(filter.Bytes(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range addrs {
+		if !copied.Check(addr.Bytes()) {
+			t.Fatalf("copied filter should match %s", addr)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// Identifier the server uses when registering with event dispatchers
+	serverIdentifier = "pubsub"
+
+	// Size of the ws read buffer
+	readBufferSize = 1024
+
+	// Size of the ws write buffer
+	writeBufferSize = 1024
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  readBufferSize,
+	WriteBufferSize: writeBufferSize,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// Tx is an accepted transaction and the addresses it touched
+type Tx struct {
+	ID        ids.ID
+	Bytes     []byte
+	Addresses [][]byte
+}
+
+// TxParser is implemented by VMs whose accepted txs can be streamed
+type TxParser interface {
+	// AcceptedTxs returns the txs that were accepted when the container with
+	// ID [containerID] and bytes [container] was accepted, along with the
+	// addresses each tx touched. It's called, with the chain's lock held,
+	// after the container has been accepted.
+	AcceptedTxs(containerID ids.ID, container []byte) ([]Tx, error)
+}
+
+// Config for a Server
+type Config struct {
+	Log                logging.Logger
+	DecisionDispatcher *triggers.EventDispatcher
+
+	// Max number of subscriptions a connection can hold at once
+	MaxSubscriptions int
+
+	// Max number of messages queued to be sent to a connection. A connection
+	// that falls further behind is closed, so that a slow client can't hold
+	// an unbounded amount of memory and never silently misses a tx.
+	BufferSize int
+}
+
+// Server streams accepted txs, over websockets, to the clients that
+// subscribed to the addresses the txs touched. It learns about accepted txs
+// by registering with the decision dispatcher of each chain whose VM is a
+// TxParser.
+// Implements chains.Registrant and http.Handler
+type Server struct {
+	log                logging.Logger
+	decisionDispatcher *triggers.EventDispatcher
+	maxSubscriptions   int
+	bufferSize         int
+
+	// Guards the fields below and the subscriptions of each connection
+	lock   sync.RWMutex
+	conns  map[*connection]struct{}
+	chains ids.Set
+	closed bool
+}
+
+// NewServer returns a new Server. It doesn't stream anything until chains are
+// registered with it.
+func NewServer(config Config) *Server {
+	return &Server{
+		log:                config.Log,
+		decisionDispatcher: config.DecisionDispatcher,
+		maxSubscriptions:   config.MaxSubscriptions,
+		bufferSize:         config.BufferSize,
+		conns:              make(map[*connection]struct{}),
+		chains:             ids.Set{},
+	}
+}
+
+// RegisterChain starts streaming the txs accepted by the chain described by
+// [ctx], if its VM is a TxParser.
+// Implements chains.Registrant
+func (s *Server) RegisterChain(name string, ctx *snow.Context, vm interface{}) {
+	parser, ok := vm.(TxParser)
+	if !ok {
+		s.log.Debug("not streaming txs of chain %s because its VM doesn't parse accepted txs", name)
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		s.log.Debug("not streaming txs of chain %s because the pubsub server is closed", name)
+		return
+	}
+	if s.chains.Contains(ctx.ChainID) {
+		s.log.Warn("txs of chain %s are already being streamed", ctx.ChainID)
+		return
+	}
+
+	acceptor := &chainAcceptor{
+		s:      s,
+		parser: parser,
+	}
+	if err := s.decisionDispatcher.RegisterChain(ctx.ChainID, serverIdentifier, acceptor); err != nil {
+		s.log.Error("couldn't stream txs of chain %s: %s", name, err)
+		return
+	}
+	s.chains.Add(ctx.ChainID)
+}
+
+// ServeHTTP upgrades the request to a websocket connection, which the client
+// can then use to add and remove subscriptions.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wsConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Debug("failed to upgrade %s", err)
+		return
+	}
+
+	conn := newConnection(s, wsConn)
+
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		_ = wsConn.Close()
+		return
+	}
+	s.conns[conn] = struct{}{}
+	s.lock.Unlock()
+
+	go conn.writePump()
+	go conn.readPump()
+}
+
+// Close stops streaming txs and closes all connections
+func (s *Server) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	errs := wrappers.Errs{}
+	for chainID := range s.chains {
+		errs.Add(s.decisionDispatcher.DeregisterChain(chainID, serverIdentifier))
+	}
+	for conn := range s.conns {
+		conn.close()
+	}
+	return errs.Err
+}
+
+// hasConns returns true iff there is at least one open connection
+func (s *Server) hasConns() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.conns) > 0
+}
+
+func (s *Server) removeConnection(conn *connection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.conns, conn)
+}
+
+// publish sends each of [txs], which were accepted by chain [chainID], to the
+// connections with a subscription matching it
+func (s *Server) publish(chainID ids.ID, txs []Tx) {
+	for _, tx := range txs {
+		txStr, err := formatting.Encode(formatting.Hex, tx.Bytes)
+		if err != nil {
+			s.log.Error("couldn't encode tx %s: %s", tx.ID, err)
+			continue
+		}
+
+		s.lock.RLock()
+		for conn := range s.conns {
+			subscriptionIDs := conn.matches(tx.Addresses)
+			if len(subscriptionIDs) == 0 {
+				continue
+			}
+			conn.queue(&Notification{
+				Subscriptions: subscriptionIDs,
+				ChainID:       chainID,
+				TxID:          tx.ID,
+				Tx:            txStr,
+				Encoding:      formatting.Hex,
+			})
+		}
+		s.lock.RUnlock()
+	}
+}
+
+// chainAcceptor publishes the txs accepted by a single chain
+type chainAcceptor struct {
+	s      *Server
+	parser TxParser
+}
+
+func (a *chainAcceptor) Accept(ctx *snow.Context, containerID ids.ID, container []byte) error {
+	// Don't bother parsing the container if nobody could be listening
+	if !a.s.hasConns() {
+		return nil
+	}
+
+	txs, err := a.parser.AcceptedTxs(containerID, container)
+	if err != nil {
+		return err
+	}
+	a.s.publish(ctx.ChainID, txs)
+	return nil
+}
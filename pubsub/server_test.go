@@ -0,0 +1,248 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/triggers"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// testParser treats each container as a single tx that touched [addrs]
+type testParser struct {
+	addrs [][]byte
+}
+
+func (p *testParser) AcceptedTxs(containerID ids.ID, container []byte) ([]Tx, error) {
+	return []Tx{{
+		ID:        containerID,
+		Bytes:     container,
+		Addresses: p.addrs,
+	}}, nil
+}
+
+func newTestServer(t *testing.T, maxSubscriptions, bufferSize int) (*Server, *triggers.EventDispatcher, *httptest.Server) {
+	dispatcher := &triggers.EventDispatcher{}
+	dispatcher.Initialize(logging.NoLog{})
+	s := NewServer(Config{
+		Log:                logging.NoLog{},
+		DecisionDispatcher: dispatcher,
+		MaxSubscriptions:   maxSubscriptions,
+		BufferSize:         bufferSize,
+	})
+	return s, dispatcher, httptest.NewServer(s)
+}
+
+func dial(t *testing.T, httpServer *httptest.Server) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func subscribe(t *testing.T, conn *websocket.Conn, cmd *Command) *Response {
+	if err := conn.WriteJSON(cmd); err != nil {
+		t.Fatal(err)
+	}
+	response := &Response{}
+	if err := conn.ReadJSON(response); err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+func formatAddress(t *testing.T, addr ids.ShortID) string {
+	addrStr, err := formatting.FormatAddress("X", constants.GetHRP(constants.LocalID), addr.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addrStr
+}
+
+func TestServerAddressSubscription(t *testing.T) {
+	s, dispatcher, httpServer := newTestServer(t, 4, 16)
+	defer httpServer.Close()
+
+	addr := ids.GenerateTestShortID()
+	ctx := snow.DefaultContextTest()
+	s.RegisterChain("X", ctx, &testParser{addrs: [][]byte{addr.Bytes()}})
+
+	conn := dial(t, httpServer)
+	defer conn.Close()
+
+	if response := subscribe(t, conn, &Command{
+		ID:        1,
+		Addresses: []string{formatAddress(t, addr)},
+	}); response.ID != 1 || response.Error != "" {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	// This subscription doesn't match the accepted tx
+	if response := subscribe(t, conn, &Command{
+		ID:        2,
+		Addresses: []string{formatAddress(t, ids.GenerateTestShortID())},
+	}); response.Error != "" {
+		t.Fatalf("unexpected error %s", response.Error)
+	}
+
+	txID := ids.GenerateTestID()
+	txBytes := []byte{1, 2, 3}
+	dispatcher.Accept(ctx, txID, txBytes)
+
+	notification := &Notification{}
+	if err := conn.ReadJSON(notification); err != nil {
+		t.Fatal(err)
+	}
+	if notification.TxID != txID || notification.ChainID != ctx.ChainID {
+		t.Fatalf("unexpected notification %+v", notification)
+	}
+	if len(notification.Subscriptions) != 1 || notification.Subscriptions[0] != 1 {
+		t.Fatalf("expected the tx to match only subscription 1 but matched %v", notification.Subscriptions)
+	}
+	gotBytes, err := formatting.Decode(notification.Encoding, notification.Tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotBytes) != string(txBytes) {
+		t.Fatalf("wrong tx bytes")
+	}
+
+	if response := subscribe(t, conn, &Command{ID: 3, Unsubscribe: true}); response.Error != errUnknownSubscription.Error() {
+		t.Fatalf("expected %s but got %q", errUnknownSubscription, response.Error)
+	}
+}
+
+func TestServerBloomSubscription(t *testing.T) {
+	s, dispatcher, httpServer := newTestServer(t, 4, 16)
+	defer httpServer.Close()
+
+	addr := ids.GenerateTestShortID()
+	ctx := snow.DefaultContextTest()
+	s.RegisterChain("X", ctx, &testParser{addrs: [][]byte{addr.Bytes()}})
+
+	conn := dial(t, httpServer)
+	defer conn.Close()
+
+	bloom, err := NewBloomFilter(make([]byte, 256), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bloom.Add(addr.Bytes())
+	bloomStr, err := formatting.Encode(formatting.Hex, bloom.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := subscribe(t, conn, &Command{
+		ID: 7,
+		Bloom: &BloomArgs{
+			Filter:    bloomStr,
+			NumHashes: 3,
+			Encoding:  formatting.Hex,
+		},
+	}); response.Error != "" {
+		t.Fatalf("unexpected error %s", response.Error)
+	}
+
+	txID := ids.GenerateTestID()
+	dispatcher.Accept(ctx, txID, []byte{1})
+
+	notification := &Notification{}
+	if err := conn.ReadJSON(notification); err != nil {
+		t.Fatal(err)
+	}
+	if notification.TxID != txID || len(notification.Subscriptions) != 1 || notification.Subscriptions[0] != 7 {
+		t.Fatalf("unexpected notification %+v", notification)
+	}
+}
+
+func TestServerMaxSubscriptions(t *testing.T) {
+	_, _, httpServer := newTestServer(t, 1, 16)
+	defer httpServer.Close()
+
+	conn := dial(t, httpServer)
+	defer conn.Close()
+
+	addrStr := formatAddress(t, ids.GenerateTestShortID())
+	if response := subscribe(t, conn, &Command{ID: 1, Addresses: []string{addrStr}}); response.Error != "" {
+		t.Fatalf("unexpected error %s", response.Error)
+	}
+	// Replacing an existing subscription doesn't count against the limit
+	if response := subscribe(t, conn, &Command{ID: 1, Addresses: []string{addrStr}}); response.Error != "" {
+		t.Fatalf("unexpected error %s", response.Error)
+	}
+	if response := subscribe(t, conn, &Command{ID: 2, Addresses: []string{addrStr}}); response.Error != errTooManySubscriptions.Error() {
+		t.Fatalf("expected %s but got %q", errTooManySubscriptions, response.Error)
+	}
+	if response := subscribe(t, conn, &Command{ID: 2}); response.Error != errNoFilter.Error() {
+		t.Fatalf("expected %s but got %q", errNoFilter, response.Error)
+	}
+}
+
+func TestServerClosesSlowConnection(t *testing.T) {
+	s, _, httpServer := newTestServer(t, 1, 1)
+	defer httpServer.Close()
+
+	conn := dial(t, httpServer)
+	defer conn.Close()
+
+	addr := ids.GenerateTestShortID()
+	if response := subscribe(t, conn, &Command{ID: 1, Addresses: []string{formatAddress(t, addr)}}); response.Error != "" {
+		t.Fatalf("unexpected error %s", response.Error)
+	}
+
+	// Publish more txs than the connection can buffer without reading them.
+	txs := make([]Tx, 64)
+	for i := range txs {
+		txs[i] = Tx{
+			ID:        ids.GenerateTestID(),
+			Bytes:     []byte{byte(i)},
+			Addresses: [][]byte{addr.Bytes()},
+		}
+	}
+	s.publish(ids.GenerateTestID(), txs)
+
+	// The connection should be closed after at most the buffered messages
+	for {
+		if err := conn.ReadJSON(&Notification{}); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				t.Fatalf("expected the connection to be closed but got %s", err)
+			}
+			return
+		}
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	s, dispatcher, httpServer := newTestServer(t, 1, 16)
+	defer httpServer.Close()
+
+	ctx := snow.DefaultContextTest()
+	s.RegisterChain("X", ctx, &testParser{})
+	// VMs that don't parse txs aren't streamed
+	s.RegisterChain("Y", snow.DefaultContextTest(), struct{}{})
+	if s.chains.Len() != 1 {
+		t.Fatalf("expected 1 registered chain but got %d", s.chains.Len())
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.DeregisterChain(ctx.ChainID, serverIdentifier); err == nil {
+		t.Fatalf("closing the server should have deregistered the chain")
+	}
+}
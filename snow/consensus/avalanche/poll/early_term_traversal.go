@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+type earlyTermTraversalFactory struct {
+	alpha     int
+	traverser Traverser
+}
+
+// NewEarlyTermTraversalFactory returns a factory that returns polls with early
+// termination, applying each vote to the processing transactions it
+// transitively supports
+func NewEarlyTermTraversalFactory(alpha int, traverser Traverser) Factory {
+	return &earlyTermTraversalFactory{
+		alpha:     alpha,
+		traverser: traverser,
+	}
+}
+
+func (f *earlyTermTraversalFactory) New(vdrs ids.ShortBag) Poll {
+	return &earlyTermTraversalPoll{
+		polled:     vdrs,
+		alpha:      f.alpha,
+		traverser:  f.traverser,
+		transitive: make(map[ids.ID]ids.BitSet),
+	}
+}
+
+// earlyTermTraversalPoll finishes when any remaining validators can't change
+// the result of the poll. A vote for a vertex is also a vote for each
+// processing transaction in the vertex and in its processing ancestors, so the
+// poll tracks the voters of every processing transaction. It terminates
+// tightly with this bound.
+type earlyTermTraversalPoll struct {
+	votes     ids.UniqueBag
+	polled    ids.ShortBag
+	alpha     int
+	traverser Traverser
+
+	// transitive is the set of voters of each processing transaction
+	transitive map[ids.ID]ids.BitSet
+	// wildcard is the set of voters that voted for a vertex that wasn't known
+	// when the votes were received. They may be applied to any transaction.
+	wildcard ids.BitSet
+}
+
+// Vote registers a response for this poll
+func (p *earlyTermTraversalPoll) Vote(vdr ids.ShortID, votes []ids.ID) {
+	count := p.polled.Count(vdr)
+	// make sure that a validator can't respond multiple times
+	p.polled.Remove(vdr)
+
+	// track the votes the validator responded with
+	voters := ids.BitSet(0)
+	for i := 0; i < count; i++ {
+		voter := uint(p.polled.Len() + i)
+		voters.Add(voter)
+		p.votes.Add(voter, votes...)
+	}
+	if count == 0 {
+		return
+	}
+
+	txIDs := ids.Set{}
+	for _, vote := range votes {
+		voteTxIDs, ok := p.traverser(vote)
+		if !ok {
+			p.wildcard.Union(voters)
+			return
+		}
+		txIDs.Add(voteTxIDs...)
+	}
+	for _, txID := range txIDs.List() {
+		txVoters := p.transitive[txID]
+		txVoters.Union(voters)
+		p.transitive[txID] = txVoters
+	}
+}
+
+// Finished returns true when all validators have voted
+func (p *earlyTermTraversalPoll) Finished() bool {
+	// If there are no outstanding queries, the poll is finished
+	numPending := p.polled.Len()
+	if numPending == 0 {
+		return true
+	}
+	// If the pending responses could give alpha votes to a transaction that
+	// hasn't been voted for, then the poll must wait for more responses
+	if numPending+p.wildcard.Len() >= p.alpha {
+		return false
+	}
+
+	// Ignore any transaction that has already received alpha votes. The poll
+	// must wait while any other transaction could still receive alpha votes.
+	for _, voters := range p.transitive {
+		if voters.Len() >= p.alpha {
+			continue
+		}
+		voters.Union(p.wildcard)
+		if voters.Len()+numPending >= p.alpha {
+			return false
+		}
+	}
+	return true
+}
+
+// Result returns the result of this poll
+func (p *earlyTermTraversalPoll) Result() ids.UniqueBag { return p.votes }
+
+func (p *earlyTermTraversalPoll) PrefixedString(prefix string) string {
+	return fmt.Sprintf("waiting on %s", p.polled.PrefixedString(prefix))
+}
+
+func (p *earlyTermTraversalPoll) String() string { return p.PrefixedString("") }
@@ -0,0 +1,249 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// testTraverser returns a traverser over [txs], which maps each known vertex
+// to the processing transactions a vote for it is applied to
+func testTraverser(txs map[ids.ID][]ids.ID) Traverser {
+	return func(vtxID ids.ID) ([]ids.ID, bool) {
+		txIDs, ok := txs[vtxID]
+		return txIDs, ok
+	}
+}
+
+func TestEarlyTermTraversalResults(t *testing.T) {
+	alpha := 1
+
+	vtxID := ids.ID{1}
+	votes := []ids.ID{vtxID}
+
+	vdr1 := ids.ShortID{1} // k = 1
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(vdr1)
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		vtxID: {ids.ID{2}},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, votes)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving k votes")
+	}
+
+	result := poll.Result()
+	if list := result.List(); len(list) != 1 {
+		t.Fatalf("Wrong number of vertices returned")
+	} else if retVtxID := list[0]; retVtxID != vtxID {
+		t.Fatalf("Wrong vertex returned")
+	} else if set := result.GetSet(vtxID); set.Len() != 1 {
+		t.Fatalf("Wrong number of votes returned")
+	}
+}
+
+func TestEarlyTermTraversalDropsDuplicatedVotes(t *testing.T) {
+	alpha := 2
+
+	vtxID := ids.ID{1}
+	votes := []ids.ID{vtxID}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2} // k = 2
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+	)
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		vtxID: {ids.ID{2}},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, votes)
+	if poll.Finished() {
+		t.Fatalf("Poll finished after less than alpha votes")
+	}
+	poll.Vote(vdr1, votes)
+	if poll.Finished() {
+		t.Fatalf("Poll finished after getting a duplicated vote")
+	}
+	poll.Vote(vdr2, votes)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving k votes")
+	}
+}
+
+func TestEarlyTermTraversalTerminatesEarly(t *testing.T) {
+	alpha := 3
+
+	vtxA := ids.ID{1}
+	vtxB := ids.ID{2}
+	txA := ids.ID{3}
+	txB := ids.ID{4}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	vdr4 := ids.ShortID{4}
+	vdr5 := ids.ShortID{5} // k = 5
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+		vdr4,
+		vdr5,
+	)
+
+	// B is a child of A, so a vote for B is also a vote for the transaction
+	// of A
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		vtxA: {txA},
+		vtxB: {txB, txA},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, []ids.ID{vtxA})
+	poll.Vote(vdr2, []ids.ID{vtxB})
+	if poll.Finished() {
+		t.Fatalf("Poll finished after less than alpha votes")
+	}
+	poll.Vote(vdr3, []ids.ID{vtxB})
+	if poll.Finished() {
+		t.Fatalf("Poll terminated early, when a transaction could have received alpha votes")
+	}
+	poll.Vote(vdr4, []ids.ID{vtxB})
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate early after receiving alpha votes for every voted transaction")
+	}
+}
+
+func TestEarlyTermTraversalForSharedAncestor(t *testing.T) {
+	alpha := 4
+
+	vtxB := ids.ID{2}
+	vtxC := ids.ID{3}
+	vtxD := ids.ID{4}
+	txA := ids.ID{5}
+
+	// If validators 1-3 vote for frontier vertices B, C, and D respectively,
+	// which all share the common ancestor A, then we cannot terminate early
+	// with alpha = k = 4. If the final vote is cast for any of B, C, or D,
+	// then the transaction of A will have transitively received alpha = 4
+	// votes.
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	vdr4 := ids.ShortID{4}
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+		vdr4,
+	)
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		vtxB: {txA},
+		vtxC: {txA},
+		vtxD: {txA},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, []ids.ID{vtxB})
+	poll.Vote(vdr2, []ids.ID{vtxC})
+	poll.Vote(vdr3, []ids.ID{vtxD})
+	if poll.Finished() {
+		t.Fatalf("Poll terminated early, when a shared ancestor could have received alpha votes")
+	}
+	poll.Vote(vdr4, []ids.ID{vtxB})
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving all outstanding votes")
+	}
+}
+
+func TestEarlyTermTraversalUnknownVotes(t *testing.T) {
+	alpha := 4
+
+	decidedVtxID := ids.ID{1}
+	unknownVtxID := ids.ID{2}
+	vtxID := ids.ID{3}
+	txID := ids.ID{4}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	vdr4 := ids.ShortID{4}
+	vdr5 := ids.ShortID{5} // k = 5
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+		vdr4,
+		vdr5,
+	)
+
+	// The unknown vertex may be a descendant of any processing vertex
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		decidedVtxID: nil,
+		vtxID:        {txID},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, []ids.ID{decidedVtxID})
+	poll.Vote(vdr2, []ids.ID{unknownVtxID})
+	poll.Vote(vdr3, []ids.ID{vtxID})
+	if poll.Finished() {
+		t.Fatalf("Poll terminated early, when the unknown vote could have been for the voted vertex")
+	}
+	poll.Vote(vdr4, nil)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate early, when no transaction could receive alpha votes")
+	}
+}
+
+func TestEarlyTermTraversalWithWeightedResponses(t *testing.T) {
+	alpha := 2
+
+	vtxID := ids.ID{1}
+	votes := []ids.ID{vtxID}
+
+	vdr1 := ids.ShortID{2}
+	vdr2 := ids.ShortID{3}
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr2,
+	) // k = 3
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		vtxID: {ids.ID{2}},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr2, votes)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving two votes")
+	}
+
+	result := poll.Result()
+	if set := result.GetSet(vtxID); set.Len() != 2 {
+		t.Fatalf("Wrong number of votes returned")
+	}
+}
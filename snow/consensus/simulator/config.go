@@ -0,0 +1,126 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+)
+
+var (
+	errNoHonestNodes   = errors.New("no honest nodes")
+	errZeroWeight      = errors.New("node has no weight")
+	errTooFewColors    = errors.New("fewer than two colors")
+	errNoRounds        = errors.New("max rounds must be positive")
+	errNotEnoughWeight = errors.New("total weight is less than K")
+)
+
+// Protocol is the consensus that honest nodes run
+type Protocol byte
+
+// The protocols that can be simulated
+const (
+	// Snowball nodes run a snowball tree over the colors
+	Snowball Protocol = iota
+	// Snowman nodes run Snowman consensus over conflicting blocks, one for
+	// each color, that share the last accepted block as their parent
+	Snowman
+	// Snowstorm nodes run a directed conflict graph over conflicting
+	// transactions, one for each color, that spend the same input
+	Snowstorm
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case Snowball:
+		return "snowball"
+	case Snowman:
+		return "snowman"
+	case Snowstorm:
+		return "snowstorm"
+	default:
+		return fmt.Sprintf("Unknown(%d)", byte(p))
+	}
+}
+
+// ParseProtocol returns the Protocol whose String is [s]
+func ParseProtocol(s string) (Protocol, error) {
+	for p := Snowball; p <= Snowstorm; p++ {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown protocol %q", s)
+}
+
+// Node is a virtual node of the simulation
+type Node struct {
+	// Weight is the stake of the node. The node is sampled in proportion to
+	// its weight.
+	Weight uint64
+	// Strategy is how the node responds to queries. If nil, the node is
+	// honest: it runs consensus and responds with its preference.
+	Strategy Strategy
+}
+
+// Config of a simulation
+type Config struct {
+	Protocol Protocol
+	Params   snowball.Parameters
+	Nodes    []Node
+	// NumColors is the number of conflicting choices. Each honest node
+	// initially prefers one of them at random.
+	NumColors int
+	// MaxRounds is the number of rounds after which the simulation stops,
+	// even if some honest nodes haven't finalized
+	MaxRounds int
+	// Seed of the randomness of the simulation
+	Seed int64
+	// Sampler samples the nodes to query in proportion to their weight. If
+	// nil, sampler.NewWeightedWithoutReplacement is used.
+	Sampler sampler.WeightedWithoutReplacement
+}
+
+// Verify returns nil if the simulation can be run with this config
+func (c *Config) Verify() error {
+	if err := c.Params.Verify(); err != nil {
+		return err
+	}
+	if c.Protocol > Snowstorm {
+		return fmt.Errorf("unknown protocol %s", c.Protocol)
+	}
+	if c.NumColors < 2 {
+		return errTooFewColors
+	}
+	if c.MaxRounds <= 0 {
+		return errNoRounds
+	}
+
+	honest := 0
+	totalWeight := uint64(0)
+	for i, node := range c.Nodes {
+		if node.Weight == 0 {
+			return fmt.Errorf("%w: node %d", errZeroWeight, i)
+		}
+		newWeight, err := math.Add64(totalWeight, node.Weight)
+		if err != nil {
+			return err
+		}
+		totalWeight = newWeight
+		if node.Strategy == nil {
+			honest++
+		}
+	}
+	if honest == 0 {
+		return errNoHonestNodes
+	}
+	if totalWeight < uint64(c.Params.K) {
+		return errNotEnoughWeight
+	}
+	return nil
+}
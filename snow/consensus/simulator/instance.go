@@ -0,0 +1,155 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+)
+
+var (
+	_ instance = &snowballInstance{}
+	_ instance = &snowmanInstance{}
+	_ instance = &snowstormInstance{}
+)
+
+// genesisID is the last accepted block of the Snowman instances, and inputID is
+// the input spent by the transactions of the Snowstorm instances. The colors
+// are hashes, so they never collide with ids.Empty.
+var (
+	genesisID = ids.Empty
+	inputID   = ids.Empty
+)
+
+// instance is the consensus of an honest node, deciding between the colors
+type instance interface {
+	// preference returns the preferred color, or the accepted color once the
+	// instance is finalized
+	preference() ids.ID
+	recordPoll(votes ids.Bag) error
+	finalized() bool
+}
+
+// newInstance returns an instance of [protocol] that initially prefers
+// colors[0]
+func newInstance(protocol Protocol, params snowball.Parameters, colors []ids.ID) (instance, error) {
+	params.Metrics = prometheus.NewRegistry()
+	switch protocol {
+	case Snowman:
+		return newSnowmanInstance(params, colors)
+	case Snowstorm:
+		return newSnowstormInstance(params, colors)
+	default:
+		return newSnowballInstance(params, colors), nil
+	}
+}
+
+type snowballInstance struct{ consensus snowball.Consensus }
+
+func newSnowballInstance(params snowball.Parameters, colors []ids.ID) *snowballInstance {
+	sb := snowball.TreeFactory{}.New()
+	sb.Initialize(params, colors[0])
+	for _, color := range colors[1:] {
+		sb.Add(color)
+	}
+	return &snowballInstance{consensus: sb}
+}
+
+func (i *snowballInstance) preference() ids.ID { return i.consensus.Preference() }
+
+func (i *snowballInstance) recordPoll(votes ids.Bag) error {
+	i.consensus.RecordPoll(votes)
+	return nil
+}
+
+func (i *snowballInstance) finalized() bool { return i.consensus.Finalized() }
+
+type snowmanInstance struct{ consensus snowman.Consensus }
+
+func newSnowmanInstance(params snowball.Parameters, colors []ids.ID) (*snowmanInstance, error) {
+	sm := snowman.TopologicalFactory{}.New()
+	if err := sm.Initialize(snow.DefaultContextTest(), params, genesisID); err != nil {
+		return nil, err
+	}
+	genesis := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     genesisID,
+		StatusV: choices.Accepted,
+	}}
+	for _, color := range colors {
+		blk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     color,
+				StatusV: choices.Processing,
+			},
+			ParentV: genesis,
+			HeightV: 1,
+		}
+		if err := sm.Add(blk); err != nil {
+			return nil, err
+		}
+	}
+	return &snowmanInstance{consensus: sm}, nil
+}
+
+func (i *snowmanInstance) preference() ids.ID { return i.consensus.Preference() }
+
+func (i *snowmanInstance) recordPoll(votes ids.Bag) error { return i.consensus.RecordPoll(votes) }
+
+func (i *snowmanInstance) finalized() bool { return i.consensus.Finalized() }
+
+type snowstormInstance struct {
+	consensus snowstorm.Consensus
+	txs       []*snowstorm.TestTx
+}
+
+func newSnowstormInstance(params snowball.Parameters, colors []ids.ID) (*snowstormInstance, error) {
+	cg := snowstorm.DirectedFactory{}.New()
+	if err := cg.Initialize(snow.DefaultContextTest(), params); err != nil {
+		return nil, err
+	}
+	i := &snowstormInstance{
+		consensus: cg,
+		txs:       make([]*snowstorm.TestTx, len(colors)),
+	}
+	for j, color := range colors {
+		tx := &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     color,
+				StatusV: choices.Processing,
+			},
+			InputIDsV: []ids.ID{inputID},
+		}
+		if err := cg.Add(tx); err != nil {
+			return nil, err
+		}
+		i.txs[j] = tx
+	}
+	return i, nil
+}
+
+func (i *snowstormInstance) preference() ids.ID {
+	for _, tx := range i.txs {
+		if tx.Status() == choices.Accepted {
+			return tx.ID()
+		}
+	}
+	// Only one of the conflicting transactions is preferred
+	for txID := range i.consensus.Preferences() {
+		return txID
+	}
+	return ids.Empty
+}
+
+func (i *snowstormInstance) recordPoll(votes ids.Bag) error {
+	_, err := i.consensus.RecordPoll(votes)
+	return err
+}
+
+func (i *snowstormInstance) finalized() bool { return i.consensus.Finalized() }
@@ -0,0 +1,163 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package simulator runs stake-weighted networks of virtual nodes, some of
+// which may be byzantine, using the real snowball, Snowman and Snowstorm
+// consensus, to find the parameters under which they stay safe and finalize
+// quickly.
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+)
+
+// Result of a simulation
+type Result struct {
+	// Rounds is the number of rounds that were simulated. In each round, every
+	// honest node that hasn't finalized polls K nodes.
+	Rounds int
+	// Honest is the number of honest nodes. FinalizedAt is the round in which
+	// each honest node that finalized did so, in increasing order.
+	Honest      int
+	FinalizedAt []int
+	// Decisions is the number of honest nodes that accepted each color
+	Decisions map[ids.ID]int
+	// Queries is the number of queries that honest nodes sent, and Responses
+	// is the number of responses they received
+	Queries, Responses int
+}
+
+// Finalized returns true if every honest node finalized
+func (r *Result) Finalized() bool { return len(r.FinalizedAt) == r.Honest }
+
+// SafetyViolated returns true if honest nodes accepted conflicting colors
+func (r *Result) SafetyViolated() bool { return len(r.Decisions) > 1 }
+
+// Finality returns the round by which [fraction] of the honest nodes
+// finalized. Returns false if fewer honest nodes finalized.
+func (r *Result) Finality(fraction float64) (int, bool) {
+	count := int(math.Ceil(fraction * float64(r.Honest)))
+	switch {
+	case count <= 0:
+		return 0, true
+	case count > len(r.FinalizedAt):
+		return 0, false
+	default:
+		return r.FinalizedAt[count-1], true
+	}
+}
+
+// Run a simulation of [config]
+func Run(config Config) (*Result, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	rand.Seed(config.Seed)
+
+	s := config.Sampler
+	if s == nil {
+		s = sampler.NewWeightedWithoutReplacement()
+	}
+	weights := make([]uint64, len(config.Nodes))
+	for i, node := range config.Nodes {
+		weights[i] = node.Weight
+	}
+	if err := s.Initialize(weights); err != nil {
+		return nil, err
+	}
+
+	colors := make([]ids.ID, config.NumColors)
+	for i := range colors {
+		colors[i] = ids.Empty.Prefix(uint64(i))
+	}
+
+	// The consensus and the preference of each honest node. The consensus of
+	// byzantine nodes is nil.
+	instances := make([]instance, len(config.Nodes))
+	prefs := make([]ids.ID, len(config.Nodes))
+	query := &Query{
+		Colors:      colors,
+		Preferences: make(map[ids.ID]uint64, len(colors)),
+	}
+	running := []int(nil)
+	for i, node := range config.Nodes {
+		if node.Strategy != nil {
+			continue
+		}
+		// Each honest node initially prefers a random color
+		first := rand.Intn(len(colors)) // #nosec G404
+		nodeColors := make([]ids.ID, 0, len(colors))
+		nodeColors = append(nodeColors, colors[first])
+		nodeColors = append(nodeColors, colors[:first]...)
+		nodeColors = append(nodeColors, colors[first+1:]...)
+
+		inst, err := newInstance(config.Protocol, config.Params, nodeColors)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't initialize node %d: %w", i, err)
+		}
+		instances[i] = inst
+		prefs[i] = inst.preference()
+		query.Preferences[prefs[i]] += node.Weight
+		running = append(running, i)
+	}
+
+	result := &Result{
+		Honest:    len(running),
+		Decisions: make(map[ids.ID]int),
+	}
+	for round := 1; round <= config.MaxRounds && len(running) > 0; round++ {
+		result.Rounds = round
+		query.Round = round
+		rand.Shuffle(len(running), func(i, j int) { running[i], running[j] = running[j], running[i] }) // #nosec G404
+
+		for j := 0; j < len(running); {
+			i := running[j]
+			indices, err := s.Sample(config.Params.K)
+			if err != nil {
+				return nil, err
+			}
+
+			query.Querier = i
+			query.QuerierPreference = prefs[i]
+			votes := ids.Bag{}
+			for _, index := range indices {
+				result.Queries++
+				if instances[index] != nil {
+					votes.Add(prefs[index])
+					result.Responses++
+				} else if vote, ok := config.Nodes[index].Strategy.Vote(query); ok {
+					votes.Add(vote)
+					result.Responses++
+				}
+			}
+
+			inst := instances[i]
+			if err := inst.recordPoll(votes); err != nil {
+				return nil, fmt.Errorf("node %d couldn't record a poll in round %d: %w", i, round, err)
+			}
+			if pref := inst.preference(); pref != prefs[i] {
+				weight := config.Nodes[i].Weight
+				query.Preferences[prefs[i]] -= weight
+				query.Preferences[pref] += weight
+				prefs[i] = pref
+			}
+			if !inst.finalized() {
+				j++
+				continue
+			}
+
+			// This node finalized, so it stops polling
+			result.FinalizedAt = append(result.FinalizedAt, round)
+			result.Decisions[prefs[i]]++
+			newLen := len(running) - 1
+			running[j] = running[newLen]
+			running = running[:newLen]
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,300 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+var params = snowball.Parameters{
+	K:                 20,
+	Alpha:             15,
+	BetaVirtuous:      15,
+	BetaRogue:         20,
+	ConcurrentRepolls: 1,
+	OptimalProcessing: 1,
+}
+
+// testNodes returns [numHonest] honest nodes followed by [numByzantine] nodes
+// that follow [strategy]. The weights of the nodes vary between 1 and 4.
+func testNodes(numHonest, numByzantine int, strategy Strategy) []Node {
+	nodes := make([]Node, numHonest+numByzantine)
+	for i := range nodes {
+		nodes[i].Weight = uint64(i%4 + 1)
+		if i >= numHonest {
+			nodes[i].Strategy = strategy
+		}
+	}
+	return nodes
+}
+
+func TestRunHonest(t *testing.T) {
+	for _, protocol := range []Protocol{Snowball, Snowman, Snowstorm} {
+		result, err := Run(Config{
+			Protocol:  protocol,
+			Params:    params,
+			Nodes:     testNodes(200, 0, nil),
+			NumColors: 3,
+			MaxRounds: 500,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Finalized() {
+			t.Fatalf("%s: only %d of %d nodes finalized", protocol, len(result.FinalizedAt), result.Honest)
+		}
+		if result.SafetyViolated() {
+			t.Fatalf("%s: nodes accepted conflicting colors %v", protocol, result.Decisions)
+		}
+		if result.Queries != result.Responses {
+			t.Fatalf("%s: honest nodes responded to %d of %d queries", protocol, result.Responses, result.Queries)
+		}
+		if expected := len(result.FinalizedAt) * params.K; result.Queries < expected {
+			t.Fatalf("%s: expected at least %d queries but got %d", protocol, expected, result.Queries)
+		}
+	}
+}
+
+func TestRunByzantine(t *testing.T) {
+	for _, strategy := range []Strategy{SplitVotes{}, FlipFlop{Period: 1}} {
+		for _, protocol := range []Protocol{Snowball, Snowman, Snowstorm} {
+			result, err := Run(Config{
+				Protocol:  protocol,
+				Params:    params,
+				Nodes:     testNodes(180, 20, strategy),
+				NumColors: 2,
+				MaxRounds: 500,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Honest != 180 {
+				t.Fatalf("%s, %s: expected 180 honest nodes but got %d", protocol, strategy, result.Honest)
+			}
+			if !result.Finalized() {
+				t.Fatalf("%s, %s: only %d of %d nodes finalized", protocol, strategy, len(result.FinalizedAt), result.Honest)
+			}
+			if result.SafetyViolated() {
+				t.Fatalf("%s, %s: nodes accepted conflicting colors %v", protocol, strategy, result.Decisions)
+			}
+		}
+	}
+}
+
+func TestRunWithhold(t *testing.T) {
+	// Half of the weight never responds, so alpha is rarely reached
+	result, err := Run(Config{
+		Protocol:  Snowball,
+		Params:    params,
+		Nodes:     testNodes(100, 100, Withhold{}),
+		NumColors: 2,
+		MaxRounds: 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Finalized() {
+		t.Fatalf("Every node finalized while half of the weight withheld its votes")
+	}
+	if result.Rounds != 20 {
+		t.Fatalf("Expected 20 rounds but got %d", result.Rounds)
+	}
+	if result.Responses >= result.Queries {
+		t.Fatalf("Nodes received %d responses to %d queries", result.Responses, result.Queries)
+	}
+}
+
+func TestRunDeterministic(t *testing.T) {
+	config := Config{
+		Protocol:  Snowman,
+		Params:    params,
+		Nodes:     testNodes(90, 10, SplitVotes{}),
+		NumColors: 2,
+		MaxRounds: 500,
+		Seed:      5,
+	}
+	result0, err := Run(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result1, err := Run(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result0.Rounds != result1.Rounds || result0.Queries != result1.Queries {
+		t.Fatalf("Runs with the same seed differ: %d rounds and %d queries, and %d rounds and %d queries",
+			result0.Rounds, result0.Queries, result1.Rounds, result1.Queries)
+	}
+}
+
+func TestConfigVerify(t *testing.T) {
+	valid := Config{
+		Params:    params,
+		Nodes:     testNodes(10, 0, nil),
+		NumColors: 2,
+		MaxRounds: 1,
+	}
+	if err := valid.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*Config)
+		expected error
+	}{
+		{"no honest nodes", func(c *Config) { c.Nodes = testNodes(0, 10, Withhold{}) }, errNoHonestNodes},
+		{"zero weight", func(c *Config) { c.Nodes = []Node{{Weight: 0}} }, errZeroWeight},
+		{"too few colors", func(c *Config) { c.NumColors = 1 }, errTooFewColors},
+		{"no rounds", func(c *Config) { c.MaxRounds = 0 }, errNoRounds},
+		{"not enough weight", func(c *Config) { c.Nodes = testNodes(2, 0, nil) }, errNotEnoughWeight},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := valid
+			test.modify(&config)
+			if err := config.Verify(); !errors.Is(err, test.expected) {
+				t.Fatalf("expected %s but got %v", test.expected, err)
+			}
+		})
+	}
+
+	invalidParams := valid
+	invalidParams.Params.Alpha = 0
+	if err := invalidParams.Verify(); err == nil {
+		t.Fatalf("Should have failed due to invalid parameters")
+	}
+	unknownProtocol := valid
+	unknownProtocol.Protocol = Snowstorm + 1
+	if err := unknownProtocol.Verify(); err == nil {
+		t.Fatalf("Should have failed due to an unknown protocol")
+	}
+}
+
+func TestResultFinality(t *testing.T) {
+	result := &Result{
+		Honest:      4,
+		FinalizedAt: []int{3, 5, 8},
+	}
+	if round, ok := result.Finality(0.5); !ok || round != 5 {
+		t.Fatalf("Expected half of the nodes to finalize by round 5 but got %d, %t", round, ok)
+	}
+	if round, ok := result.Finality(0.75); !ok || round != 8 {
+		t.Fatalf("Expected 3/4 of the nodes to finalize by round 8 but got %d, %t", round, ok)
+	}
+	if _, ok := result.Finality(1); ok {
+		t.Fatalf("Not every node finalized")
+	}
+	if result.Finalized() {
+		t.Fatalf("Not every node finalized")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	colorA, colorB := ids.Empty.Prefix(0), ids.Empty.Prefix(1)
+	summary := Summarize([]*Result{
+		{
+			Honest:      2,
+			FinalizedAt: []int{2, 4},
+			Decisions:   map[ids.ID]int{colorA: 1, colorB: 1},
+			Queries:     10,
+			Responses:   8,
+		},
+		{
+			Honest:      2,
+			FinalizedAt: []int{6},
+			Decisions:   map[ids.ID]int{colorA: 1},
+			Queries:     10,
+			Responses:   10,
+		},
+	})
+	expected := Summary{
+		Runs:             2,
+		SafetyViolations: 1,
+		Unfinalized:      1,
+		MeanFinality:     4,
+		MaxFinality:      6,
+		MeanQueries:      5,
+		MeanResponses:    4.5,
+	}
+	if summary != expected {
+		t.Fatalf("Expected %+v but got %+v", expected, summary)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	summary, err := Simulate(Config{
+		Protocol:  Snowball,
+		Params:    params,
+		Nodes:     testNodes(100, 0, nil),
+		NumColors: 2,
+		MaxRounds: 500,
+	}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Runs != 3 || summary.SafetyViolations != 0 || summary.Unfinalized != 0 {
+		t.Fatalf("Unexpected summary %+v", summary)
+	}
+	if summary.MeanFinality < float64(params.BetaVirtuous) {
+		t.Fatalf("Nodes finalized in %f rounds on average, faster than beta", summary.MeanFinality)
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	colorA, colorB := ids.Empty.Prefix(0), ids.Empty.Prefix(1)
+	q := &Query{
+		Round:       3,
+		Colors:      []ids.ID{colorA, colorB},
+		Preferences: map[ids.ID]uint64{colorA: 5, colorB: 2},
+	}
+
+	if _, ok := (Withhold{}).Vote(q); ok {
+		t.Fatalf("Withhold responded")
+	}
+	if vote, ok := (SplitVotes{}).Vote(q); !ok || vote != colorB {
+		t.Fatalf("SplitVotes should have voted for the minority color")
+	}
+	if vote, ok := (FlipFlop{Period: 1}).Vote(q); !ok || vote != colorB {
+		t.Fatalf("FlipFlop should have voted for the second color in odd rounds")
+	}
+	if vote, ok := (FlipFlop{Period: 4}).Vote(q); !ok || vote != colorA {
+		t.Fatalf("FlipFlop should have voted for the first color in the first period")
+	}
+
+	for _, strategy := range []Strategy{Withhold{}, SplitVotes{}, FlipFlop{Period: 1}} {
+		parsed, err := ParseStrategy(strategy.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != strategy {
+			t.Fatalf("Parsed %s as %s", strategy, parsed)
+		}
+	}
+	if strategy, err := ParseStrategy("honest"); err != nil || strategy != nil {
+		t.Fatalf("honest should be parsed as a nil strategy")
+	}
+	if _, err := ParseStrategy("unknown"); err == nil {
+		t.Fatalf("Should have failed to parse an unknown strategy")
+	}
+}
+
+func TestParseProtocol(t *testing.T) {
+	for _, protocol := range []Protocol{Snowball, Snowman, Snowstorm} {
+		parsed, err := ParseProtocol(protocol.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != protocol {
+			t.Fatalf("Parsed %s as %s", protocol, parsed)
+		}
+	}
+	if _, err := ParseProtocol("unknown"); err == nil {
+		t.Fatalf("Should have failed to parse an unknown protocol")
+	}
+}
@@ -0,0 +1,96 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	_ Strategy = Withhold{}
+	_ Strategy = SplitVotes{}
+	_ Strategy = FlipFlop{}
+)
+
+// Query is a query that a byzantine node responds to
+type Query struct {
+	// Round the query was sent in
+	Round int
+	// Querier is the index of the honest node that sent the query, and
+	// QuerierPreference is its preference
+	Querier           int
+	QuerierPreference ids.ID
+	// Colors are the conflicting choices of the simulation
+	Colors []ids.ID
+	// Preferences is the weight of the honest nodes that prefer each color
+	Preferences map[ids.ID]uint64
+}
+
+// Strategy is how a byzantine node responds to queries
+type Strategy interface {
+	fmt.Stringer
+
+	// Vote returns the response to [q]. Returns false if the node doesn't
+	// respond.
+	Vote(q *Query) (ids.ID, bool)
+}
+
+// Withhold never responds, so that its weight never counts towards alpha
+type Withhold struct{}
+
+// Vote implements the Strategy interface
+func (Withhold) Vote(*Query) (ids.ID, bool) { return ids.Empty, false }
+
+func (Withhold) String() string { return "withhold" }
+
+// SplitVotes responds with the color that the least honest weight prefers, to
+// keep the honest nodes split between the colors
+type SplitVotes struct{}
+
+// Vote implements the Strategy interface
+func (SplitVotes) Vote(q *Query) (ids.ID, bool) {
+	vote := q.Colors[0]
+	for _, color := range q.Colors[1:] {
+		if q.Preferences[color] < q.Preferences[vote] {
+			vote = color
+		}
+	}
+	return vote, true
+}
+
+func (SplitVotes) String() string { return "split" }
+
+// FlipFlop responds with a different color every [Period] rounds, to reset the
+// confidence of the honest nodes
+type FlipFlop struct{ Period int }
+
+// Vote implements the Strategy interface
+func (f FlipFlop) Vote(q *Query) (ids.ID, bool) {
+	period := f.Period
+	if period <= 0 {
+		period = 1
+	}
+	return q.Colors[(q.Round/period)%len(q.Colors)], true
+}
+
+func (FlipFlop) String() string { return "flip-flop" }
+
+// ParseStrategy returns the Strategy whose String is [s]. [s] is "honest" for
+// a nil Strategy.
+func ParseStrategy(s string) (Strategy, error) {
+	switch s {
+	case "honest":
+		return nil, nil
+	case Withhold{}.String():
+		return Withhold{}, nil
+	case SplitVotes{}.String():
+		return SplitVotes{}, nil
+	case FlipFlop{}.String():
+		return FlipFlop{Period: 1}, nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %q", s)
+	}
+}
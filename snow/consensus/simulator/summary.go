@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+// Summary of simulations of the same config with different seeds
+type Summary struct {
+	Runs int
+	// SafetyViolations is the number of runs in which honest nodes accepted
+	// conflicting colors, and Unfinalized is the number of runs in which some
+	// honest nodes didn't finalize
+	SafetyViolations, Unfinalized int
+	// MeanFinality is the mean of the rounds in which honest nodes finalized,
+	// and MaxFinality is the latest of them
+	MeanFinality float64
+	MaxFinality  int
+	// MeanQueries and MeanResponses are the mean number of queries that an
+	// honest node sent, and of responses that it received
+	MeanQueries, MeanResponses float64
+}
+
+// Simulate runs [runs] simulations of [config], with the seeds config.Seed,
+// config.Seed+1, ..., and summarizes them
+func Simulate(config Config, runs int) (Summary, error) {
+	results := make([]*Result, runs)
+	for i := range results {
+		runConfig := config
+		runConfig.Seed = config.Seed + int64(i)
+		result, err := Run(runConfig)
+		if err != nil {
+			return Summary{}, err
+		}
+		results[i] = result
+	}
+	return Summarize(results), nil
+}
+
+// Summarize [results]
+func Summarize(results []*Result) Summary {
+	s := Summary{Runs: len(results)}
+	finalized, honest := 0, 0
+	finalitySum, queries, responses := 0, 0, 0
+	for _, result := range results {
+		if result.SafetyViolated() {
+			s.SafetyViolations++
+		}
+		if !result.Finalized() {
+			s.Unfinalized++
+		}
+		for _, round := range result.FinalizedAt {
+			finalitySum += round
+			if round > s.MaxFinality {
+				s.MaxFinality = round
+			}
+		}
+		finalized += len(result.FinalizedAt)
+		honest += result.Honest
+		queries += result.Queries
+		responses += result.Responses
+	}
+	if finalized > 0 {
+		s.MeanFinality = float64(finalitySum) / float64(finalized)
+	}
+	if honest > 0 {
+		s.MeanQueries = float64(queries) / float64(honest)
+		s.MeanResponses = float64(responses) / float64(honest)
+	}
+	return s
+}
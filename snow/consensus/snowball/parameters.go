@@ -27,11 +27,54 @@ const (
 		`        \/         \/         \/` + "\n"
 )
 
+// PollTermination is when a poll finishes before every polled validator
+// responded
+type PollTermination byte
+
+// The ways a poll can terminate
+const (
+	// EarlyTermNoTraversal finishes a poll once the outstanding votes can't
+	// change its outcome, assuming that the votes for different items may be
+	// applied to a shared ancestor. It's the default.
+	EarlyTermNoTraversal PollTermination = iota
+	// NoEarlyTerm waits for every polled validator to respond, or to time out
+	NoEarlyTerm
+	// EarlyTermTraversal finishes a poll once the outstanding votes can't
+	// change its outcome, applying each vote to the processing items it
+	// transitively supports. It finishes as soon as alpha is reached for an
+	// item whose outcome the outstanding votes can't change elsewhere.
+	EarlyTermTraversal
+)
+
+func (t PollTermination) String() string {
+	switch t {
+	case EarlyTermNoTraversal:
+		return "early-term-no-traversal"
+	case NoEarlyTerm:
+		return "no-early-term"
+	case EarlyTermTraversal:
+		return "early-term-traversal"
+	default:
+		return fmt.Sprintf("Unknown(%d)", byte(t))
+	}
+}
+
+// ParsePollTermination returns the PollTermination whose String is [s]
+func ParsePollTermination(s string) (PollTermination, error) {
+	for t := EarlyTermNoTraversal; t <= EarlyTermTraversal; t++ {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown poll termination %q", s)
+}
+
 // Parameters required for snowball consensus
 type Parameters struct {
 	Namespace                                                               string
 	Metrics                                                                 prometheus.Registerer
 	K, Alpha, BetaVirtuous, BetaRogue, ConcurrentRepolls, OptimalProcessing int
+	PollTermination                                                         PollTermination
 }
 
 // Verify returns nil if the parameters describe a valid initialization.
@@ -53,6 +96,8 @@ func (p Parameters) Verify() error {
 		return fmt.Errorf("ConcurrentRepolls = %d, BetaRogue = %d: Fails the condition that: ConcurrentRepolls <= BetaRogue", p.ConcurrentRepolls, p.BetaRogue)
 	case p.OptimalProcessing <= 0:
 		return fmt.Errorf("OptimalProcessing = %d: Fails the condition that: 0 < OptimalProcessing", p.OptimalProcessing)
+	case p.PollTermination > EarlyTermTraversal:
+		return fmt.Errorf("PollTermination = %s: Fails the condition that: PollTermination is known", p.PollTermination)
 	default:
 		return nil
 	}
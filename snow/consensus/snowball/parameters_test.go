@@ -174,3 +174,34 @@ func TestParametersInvalidOptimalProcessing(t *testing.T) {
 		t.Fatalf("Should have failed due to invalid optimal processing")
 	}
 }
+
+func TestParametersInvalidPollTermination(t *testing.T) {
+	p := Parameters{
+		K:                 1,
+		Alpha:             1,
+		BetaVirtuous:      1,
+		BetaRogue:         1,
+		ConcurrentRepolls: 1,
+		OptimalProcessing: 1,
+		PollTermination:   EarlyTermTraversal + 1,
+	}
+
+	if err := p.Verify(); err == nil {
+		t.Fatalf("Should have failed due to invalid poll termination")
+	}
+}
+
+func TestParsePollTermination(t *testing.T) {
+	for _, termination := range []PollTermination{EarlyTermNoTraversal, NoEarlyTerm, EarlyTermTraversal} {
+		parsed, err := ParsePollTermination(termination.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != termination {
+			t.Fatalf("Parsed %s as %s", termination, parsed)
+		}
+	}
+	if _, err := ParsePollTermination("unknown"); err == nil {
+		t.Fatalf("Should have failed to parse an unknown poll termination")
+	}
+}
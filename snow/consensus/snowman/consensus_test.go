@@ -1246,37 +1246,50 @@ func ErrorOnTransitiveRejectionTest(t *testing.T, factory Factory) {
 }
 
 func RandomizedConsistencyTest(t *testing.T, factory Factory) {
-	numColors := 50
-	numNodes := 100
-	params := snowball.Parameters{
-		Metrics:           prometheus.NewRegistry(),
-		K:                 20,
-		Alpha:             15,
-		BetaVirtuous:      20,
-		BetaRogue:         30,
-		ConcurrentRepolls: 1,
-		OptimalProcessing: 1,
-	}
-	seed := int64(0)
+	for _, termination := range []snowball.PollTermination{
+		snowball.NoEarlyTerm,
+		snowball.EarlyTermNoTraversal,
+		snowball.EarlyTermTraversal,
+	} {
+		numColors := 50
+		numNodes := 100
+		params := snowball.Parameters{
+			Metrics:           prometheus.NewRegistry(),
+			K:                 20,
+			Alpha:             15,
+			BetaVirtuous:      20,
+			BetaRogue:         30,
+			ConcurrentRepolls: 1,
+			OptimalProcessing: 1,
+			PollTermination:   termination,
+		}
+		seed := int64(0)
 
-	rand.Seed(seed)
+		rand.Seed(seed)
 
-	n := Network{}
-	n.Initialize(params, numColors)
+		n := Network{}
+		n.Initialize(params, numColors)
 
-	for i := 0; i < numNodes; i++ {
-		if err := n.AddNode(factory.New()); err != nil {
-			t.Fatal(err)
+		for i := 0; i < numNodes; i++ {
+			if err := n.AddNode(factory.New()); err != nil {
+				t.Fatal(err)
+			}
 		}
-	}
 
-	for !n.Finalized() {
-		if err := n.Round(); err != nil {
-			t.Fatal(err)
+		for !n.Finalized() {
+			if err := n.Round(); err != nil {
+				t.Fatal(err)
+			}
 		}
-	}
 
-	if !n.Agreement() {
-		t.Fatalf("Network agreed on inconsistent values")
+		if !n.Agreement() {
+			t.Fatalf("Network agreed on inconsistent values with %s", termination)
+		}
+		if termination == snowball.NoEarlyTerm && n.earlyPolls != 0 {
+			t.Fatalf("%d polls finished early with %s", n.earlyPolls, termination)
+		}
+		if termination != snowball.NoEarlyTerm && n.earlyPolls == 0 {
+			t.Fatalf("No polls finished early with %s", termination)
+		}
 	}
 }
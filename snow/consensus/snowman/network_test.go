@@ -12,13 +12,26 @@ import (
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
 	"github.com/ava-labs/avalanchego/utils/sampler"
 )
 
+// dropFrequency is the inverse of the probability that a sampled node doesn't
+// respond to a poll
+const dropFrequency = 20
+
 type Network struct {
-	params         snowball.Parameters
-	colors         []*TestBlock
-	nodes, running []Consensus
+	params snowball.Parameters
+	colors []*TestBlock
+	nodes  []Consensus
+	// nodeIDs and nodeBlocks are the ID and the blocks of each node
+	nodeIDs    []ids.ShortID
+	nodeBlocks []map[ids.ID]*TestBlock
+	// running are the indices of the nodes that haven't finalized
+	running []int
+	// earlyPolls is the number of polls that finished before every sampled
+	// node responded
+	earlyPolls int
 }
 
 func (n *Network) shuffleColors() {
@@ -66,11 +79,11 @@ func (n *Network) AddNode(sm Consensus) error {
 	}
 
 	n.shuffleColors()
-	deps := map[ids.ID]Block{}
+	deps := map[ids.ID]*TestBlock{}
 	for _, blk := range n.colors {
-		myDep, found := deps[blk.ParentV.ID()]
-		if !found {
-			myDep = blk.Parent()
+		var myDep Block = blk.Parent()
+		if dep, found := deps[blk.ParentV.ID()]; found {
+			myDep = dep
 		}
 		myVtx := &TestBlock{
 			TestDecidable: choices.TestDecidable{
@@ -85,10 +98,12 @@ func (n *Network) AddNode(sm Consensus) error {
 		if err := sm.Add(myVtx); err != nil {
 			return err
 		}
-		deps[myVtx.ID()] = myDep
+		deps[myVtx.ID()] = myVtx
 	}
+	n.running = append(n.running, len(n.nodes))
 	n.nodes = append(n.nodes, sm)
-	n.running = append(n.running, sm)
+	n.nodeIDs = append(n.nodeIDs, ids.GenerateTestShortID())
+	n.nodeBlocks = append(n.nodeBlocks, deps)
 	return nil
 }
 
@@ -100,18 +115,48 @@ func (n *Network) Round() error {
 	}
 
 	runningInd := rand.Intn(len(n.running)) // #nosec G404
-	running := n.running[runningInd]
+	running := n.nodes[n.running[runningInd]]
+	runningBlocks := n.nodeBlocks[n.running[runningInd]]
 
 	s := sampler.NewUniform()
 	_ = s.Initialize(uint64(len(n.nodes)))
 	indices, _ := s.Sample(n.params.K)
-	sampledColors := ids.Bag{}
+	sampledNodes := ids.ShortBag{}
 	for _, index := range indices {
-		peer := n.nodes[int(index)]
-		sampledColors.Add(peer.Preference())
+		sampledNodes.Add(n.nodeIDs[int(index)])
 	}
 
-	if err := running.RecordPoll(sampledColors); err != nil {
+	// The sampled nodes respond in the order they were sampled, and the poll
+	// is recorded as soon as it finishes
+	p := poll.NewFactory(n.params, func(blkID ids.ID) ([]ids.ID, bool) {
+		testBlk, ok := runningBlocks[blkID]
+		if !ok {
+			return nil, false
+		}
+		blkIDs := []ids.ID(nil)
+		for blk := Block(testBlk); blk.Status() == choices.Processing; blk = blk.Parent() {
+			blkIDs = append(blkIDs, blk.ID())
+		}
+		return blkIDs, true
+	}).New(sampledNodes)
+	responses := 0
+	for _, index := range indices {
+		if p.Finished() {
+			break
+		}
+		responses++
+		peerID := n.nodeIDs[int(index)]
+		if rand.Intn(dropFrequency) == 0 { // #nosec G404
+			p.Drop(peerID)
+			continue
+		}
+		p.Vote(peerID, n.nodes[int(index)].Preference())
+	}
+
+	if responses < len(indices) {
+		n.earlyPolls++
+	}
+	if err := running.RecordPoll(p.Result()); err != nil {
 		return err
 	}
 
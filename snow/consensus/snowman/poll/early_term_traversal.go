@@ -0,0 +1,127 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+type earlyTermTraversalFactory struct {
+	alpha     int
+	traverser Traverser
+}
+
+// NewEarlyTermTraversalFactory returns a factory that returns polls with early
+// termination, applying each vote to the processing blocks it transitively
+// supports
+func NewEarlyTermTraversalFactory(alpha int, traverser Traverser) Factory {
+	return &earlyTermTraversalFactory{
+		alpha:     alpha,
+		traverser: traverser,
+	}
+}
+
+func (f *earlyTermTraversalFactory) New(vdrs ids.ShortBag) Poll {
+	return &earlyTermTraversalPoll{
+		polled:     vdrs,
+		alpha:      f.alpha,
+		traverser:  f.traverser,
+		transitive: make(map[ids.ID]int),
+		parents:    make(map[ids.ID]ids.ID),
+	}
+}
+
+// earlyTermTraversalPoll finishes when any remaining validators can't change
+// the result of the poll. A vote for a block is also a vote for each of its
+// processing ancestors, so the poll tracks the transitive votes of every
+// processing block. Siblings may succeed together in snowball, so the poll
+// waits while the pending votes, along with the votes of the siblings that
+// haven't reached alpha, could reach alpha.
+type earlyTermTraversalPoll struct {
+	votes     ids.Bag
+	polled    ids.ShortBag
+	alpha     int
+	traverser Traverser
+
+	// transitive is the number of votes applied to each processing block
+	transitive map[ids.ID]int
+	// parents is the parent of each voted processing block. The parent of the
+	// oldest processing blocks is ids.Empty.
+	parents map[ids.ID]ids.ID
+	// wildcard is the number of votes for blocks that weren't known when the
+	// votes were received. They may be applied to any block.
+	wildcard int
+}
+
+// Vote registers a response for this poll
+func (p *earlyTermTraversalPoll) Vote(vdr ids.ShortID, vote ids.ID) {
+	count := p.polled.Count(vdr)
+	// make sure that a validator can't respond multiple times
+	p.polled.Remove(vdr)
+
+	// track the votes the validator responded with
+	p.votes.AddCount(vote, count)
+	if count == 0 {
+		return
+	}
+
+	blkIDs, ok := p.traverser(vote)
+	if !ok {
+		p.wildcard += count
+		return
+	}
+	for i, blkID := range blkIDs {
+		p.transitive[blkID] += count
+		if i+1 < len(blkIDs) {
+			p.parents[blkID] = blkIDs[i+1]
+		} else {
+			p.parents[blkID] = ids.Empty
+		}
+	}
+}
+
+// Drop any future response for this poll
+func (p *earlyTermTraversalPoll) Drop(vdr ids.ShortID) { p.polled.Remove(vdr) }
+
+// Finished returns true when all validators have voted
+func (p *earlyTermTraversalPoll) Finished() bool {
+	// If there are no outstanding queries, the poll is finished
+	remaining := p.polled.Len()
+	if remaining == 0 {
+		return true
+	}
+	// If the outstanding votes could give alpha votes to a block that hasn't
+	// been voted for, then the poll must wait for more responses
+	outstanding := remaining + p.wildcard
+	if outstanding >= p.alpha {
+		return false
+	}
+
+	// Ignore any block that has already received alpha votes. The outstanding
+	// votes could give alpha votes to any subset of the remaining children of
+	// a block, so they must not be able to do so for all of them.
+	partialVotes := make(map[ids.ID]int)
+	for blkID, votes := range p.transitive {
+		if votes < p.alpha {
+			partialVotes[p.parents[blkID]] += votes
+		}
+	}
+	for _, votes := range partialVotes {
+		if votes+outstanding >= p.alpha {
+			return false
+		}
+	}
+	return true
+}
+
+// Result returns the result of this poll
+func (p *earlyTermTraversalPoll) Result() ids.Bag { return p.votes }
+
+func (p *earlyTermTraversalPoll) PrefixedString(prefix string) string {
+	return fmt.Sprintf("waiting on %s", p.polled.PrefixedString(prefix))
+}
+
+func (p *earlyTermTraversalPoll) String() string { return p.PrefixedString("") }
@@ -0,0 +1,356 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// testTraverser returns a traverser over [chains], which maps each known block
+// to the processing blocks a vote for it is applied to
+func testTraverser(chains map[ids.ID][]ids.ID) Traverser {
+	return func(blkID ids.ID) ([]ids.ID, bool) {
+		chain, ok := chains[blkID]
+		return chain, ok
+	}
+}
+
+func TestEarlyTermTraversalResults(t *testing.T) {
+	alpha := 1
+
+	blkID := ids.ID{1}
+
+	vdr1 := ids.ShortID{1} // k = 1
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(vdr1)
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		blkID: {blkID},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, blkID)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving k votes")
+	}
+
+	result := poll.Result()
+	if list := result.List(); len(list) != 1 {
+		t.Fatalf("Wrong number of blocks returned")
+	} else if retBlkID := list[0]; retBlkID != blkID {
+		t.Fatalf("Wrong block returned")
+	} else if result.Count(blkID) != 1 {
+		t.Fatalf("Wrong number of votes returned")
+	}
+}
+
+func TestEarlyTermTraversalDropsDuplicatedVotes(t *testing.T) {
+	alpha := 2
+
+	blkID := ids.ID{1}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2} // k = 2
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+	)
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		blkID: {blkID},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, blkID)
+	if poll.Finished() {
+		t.Fatalf("Poll finished after less than alpha votes")
+	}
+	poll.Vote(vdr1, blkID)
+	if poll.Finished() {
+		t.Fatalf("Poll finished after getting a duplicated vote")
+	}
+	poll.Vote(vdr2, blkID)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving k votes")
+	}
+}
+
+func TestEarlyTermTraversalTerminatesEarly(t *testing.T) {
+	alpha := 3
+
+	blkA := ids.ID{1}
+	blkB := ids.ID{2}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	vdr4 := ids.ShortID{4}
+	vdr5 := ids.ShortID{5} // k = 5
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+		vdr4,
+		vdr5,
+	)
+
+	// B is a child of A, so a vote for B is also a vote for A
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		blkA: {blkA},
+		blkB: {blkB, blkA},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, blkB)
+	if poll.Finished() {
+		t.Fatalf("Poll finished after less than alpha votes")
+	}
+	poll.Vote(vdr2, blkB)
+	if poll.Finished() {
+		t.Fatalf("Poll finished after less than alpha votes")
+	}
+	poll.Vote(vdr3, blkB)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate early after receiving alpha votes for one block and its ancestor")
+	}
+}
+
+func TestEarlyTermTraversalForSharedAncestor(t *testing.T) {
+	alpha := 4
+
+	blkA := ids.ID{1}
+	blkB := ids.ID{2}
+	blkC := ids.ID{3}
+	blkD := ids.ID{4}
+
+	// If validators 1-3 vote for blocks B, C, and D respectively, which are
+	// all children of A, then we cannot terminate early with alpha = k = 4. If
+	// the final vote is cast for any of A, B, C, or D, then block A will have
+	// transitively received alpha = 4 votes.
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	vdr4 := ids.ShortID{4}
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+		vdr4,
+	)
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		blkA: {blkA},
+		blkB: {blkB, blkA},
+		blkC: {blkC, blkA},
+		blkD: {blkD, blkA},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, blkB)
+	if poll.Finished() {
+		t.Fatalf("Poll finished early after receiving one vote")
+	}
+	poll.Vote(vdr2, blkC)
+	if poll.Finished() {
+		t.Fatalf("Poll finished early after receiving two votes")
+	}
+	poll.Vote(vdr3, blkD)
+	if poll.Finished() {
+		t.Fatalf("Poll terminated early, when a shared ancestor could have received alpha votes")
+	}
+	poll.Vote(vdr4, blkA)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving all outstanding votes")
+	}
+}
+
+func TestEarlyTermTraversalForSiblings(t *testing.T) {
+	alpha := 3
+
+	blkA := ids.ID{1}
+	blkB := ids.ID{2}
+	blkC := ids.ID{3}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	vdr4 := ids.ShortID{4} // k = 4
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+		vdr4,
+	)
+
+	// B and C are conflicting children of A. Once A has alpha votes, the
+	// outstanding vote could still give B and C alpha votes together.
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		blkA: {blkA},
+		blkB: {blkB, blkA},
+		blkC: {blkC, blkA},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, blkB)
+	poll.Vote(vdr2, blkC)
+	poll.Vote(vdr3, blkA)
+	if poll.Finished() {
+		t.Fatalf("Poll terminated early, when the children could have received alpha votes")
+	}
+	poll.Vote(vdr4, blkA)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving all outstanding votes")
+	}
+}
+
+func TestEarlyTermTraversalIgnoresDecidedVotes(t *testing.T) {
+	alpha := 4
+
+	decidedBlkID := ids.ID{1}
+	blkID := ids.ID{2}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	vdr4 := ids.ShortID{4}
+	vdr5 := ids.ShortID{5} // k = 5
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+		vdr4,
+		vdr5,
+	)
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		decidedBlkID: nil,
+		blkID:        {blkID},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, decidedBlkID)
+	if poll.Finished() {
+		t.Fatalf("Poll finished early after receiving one vote")
+	}
+	poll.Vote(vdr2, blkID)
+	if poll.Finished() {
+		t.Fatalf("Poll finished early after receiving two votes")
+	}
+	poll.Vote(vdr3, decidedBlkID)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate early, when no block could receive alpha votes")
+	}
+}
+
+func TestEarlyTermTraversalUnknownVotes(t *testing.T) {
+	alpha := 4
+
+	decidedBlkID := ids.ID{1}
+	unknownBlkID := ids.ID{2}
+	blkID := ids.ID{3}
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3}
+	vdr4 := ids.ShortID{4}
+	vdr5 := ids.ShortID{5} // k = 5
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+		vdr4,
+		vdr5,
+	)
+
+	// The unknown block may be a descendant of any processing block
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		decidedBlkID: nil,
+		blkID:        {blkID},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr1, decidedBlkID)
+	poll.Vote(vdr2, unknownBlkID)
+	poll.Vote(vdr3, blkID)
+	if poll.Finished() {
+		t.Fatalf("Poll terminated early, when the unknown vote could have been for the voted block")
+	}
+	poll.Drop(vdr4)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate early, when no block could receive alpha votes")
+	}
+}
+
+func TestEarlyTermTraversalWithWeightedResponses(t *testing.T) {
+	alpha := 2
+
+	blkID := ids.ID{1}
+
+	vdr1 := ids.ShortID{2}
+	vdr2 := ids.ShortID{3}
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr2,
+	) // k = 3
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(map[ids.ID][]ids.ID{
+		blkID: {blkID},
+	}))
+	poll := factory.New(vdrs)
+
+	poll.Vote(vdr2, blkID)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after receiving two votes")
+	}
+
+	result := poll.Result()
+	if result.Count(blkID) != 2 {
+		t.Fatalf("Wrong number of votes returned")
+	}
+}
+
+func TestEarlyTermTraversalWithFastDrops(t *testing.T) {
+	alpha := 2
+
+	vdr1 := ids.ShortID{1}
+	vdr2 := ids.ShortID{2}
+	vdr3 := ids.ShortID{3} // k = 3
+
+	vdrs := ids.ShortBag{}
+	vdrs.Add(
+		vdr1,
+		vdr2,
+		vdr3,
+	)
+
+	factory := NewEarlyTermTraversalFactory(alpha, testTraverser(nil))
+	poll := factory.New(vdrs)
+
+	poll.Drop(vdr1)
+	if poll.Finished() {
+		t.Fatalf("Poll finished early after dropping one vote")
+	}
+	poll.Drop(vdr2)
+	if !poll.Finished() {
+		t.Fatalf("Poll did not terminate after dropping two votes")
+	}
+}
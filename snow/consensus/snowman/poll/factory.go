@@ -0,0 +1,27 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+// Traverser returns the processing blocks that a vote for [blkID] is applied
+// to: the block and its processing ancestors, from the block to the oldest
+// ancestor. Returns false if the block isn't known.
+type Traverser func(blkID ids.ID) ([]ids.ID, bool)
+
+// NewFactory returns a factory that returns polls with the termination of
+// [params]
+func NewFactory(params snowball.Parameters, traverser Traverser) Factory {
+	switch params.PollTermination {
+	case snowball.NoEarlyTerm:
+		return NewNoEarlyTermFactory()
+	case snowball.EarlyTermTraversal:
+		return NewEarlyTermTraversalFactory(params.Alpha, traverser)
+	default:
+		return NewEarlyTermNoTraversalFactory(params.Alpha)
+	}
+}
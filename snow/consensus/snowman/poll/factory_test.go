@@ -0,0 +1,27 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+func TestNewFactory(t *testing.T) {
+	params := snowball.Parameters{Alpha: 1}
+
+	params.PollTermination = snowball.EarlyTermNoTraversal
+	if _, ok := NewFactory(params, nil).(*earlyTermNoTraversalFactory); !ok {
+		t.Fatalf("Wrong factory returned for %s", params.PollTermination)
+	}
+	params.PollTermination = snowball.NoEarlyTerm
+	if _, ok := NewFactory(params, nil).(noEarlyTermFactory); !ok {
+		t.Fatalf("Wrong factory returned for %s", params.PollTermination)
+	}
+	params.PollTermination = snowball.EarlyTermTraversal
+	if _, ok := NewFactory(params, nil).(*earlyTermTraversalFactory); !ok {
+		t.Fatalf("Wrong factory returned for %s", params.PollTermination)
+	}
+}
@@ -11,16 +11,27 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche/poll"
 	"github.com/ava-labs/avalanchego/utils/sampler"
 
 	sbcon "github.com/ava-labs/avalanchego/snow/consensus/snowball"
 )
 
+// dropFrequency is the inverse of the probability that a sampled node doesn't
+// respond to a poll
+const dropFrequency = 20
+
 type Network struct {
-	params         sbcon.Parameters
-	consumers      []*TestTx
-	nodeTxs        []map[ids.ID]*TestTx
-	nodes, running []Consensus
+	params    sbcon.Parameters
+	consumers []*TestTx
+	nodeIDs   []ids.ShortID
+	nodeTxs   []map[ids.ID]*TestTx
+	nodes     []Consensus
+	// running are the indices of the nodes that haven't finalized
+	running []int
+	// earlyPolls is the number of polls that finished before every sampled
+	// node responded
+	earlyPolls int
 }
 
 func (n *Network) shuffleConsumers() {
@@ -119,9 +130,10 @@ func (n *Network) AddNode(cg Consensus) error {
 		}
 	}
 
+	n.running = append(n.running, len(n.nodes))
+	n.nodeIDs = append(n.nodeIDs, ids.GenerateTestShortID())
 	n.nodeTxs = append(n.nodeTxs, txs)
 	n.nodes = append(n.nodes, cg)
-	n.running = append(n.running, cg)
 
 	return nil
 }
@@ -136,29 +148,59 @@ func (n *Network) Round() error {
 	}
 
 	runningInd := rand.Intn(len(n.running)) // #nosec G404
-	running := n.running[runningInd]
+	running := n.nodes[n.running[runningInd]]
+	runningTxs := n.nodeTxs[n.running[runningInd]]
 
 	s := sampler.NewUniform()
 	_ = s.Initialize(uint64(len(n.nodes)))
 	indices, _ := s.Sample(n.params.K)
-	sampledColors := ids.Bag{}
-	sampledColors.SetThreshold(n.params.Alpha)
+	sampledNodes := ids.ShortBag{}
+	for _, index := range indices {
+		sampledNodes.Add(n.nodeIDs[int(index)])
+	}
+
+	// Each transaction is voted for as if it were alone in a vertex. The
+	// sampled nodes respond in the order they were sampled, and the poll is
+	// recorded as soon as it finishes.
+	p := poll.NewFactory(n.params, func(txID ids.ID) ([]ids.ID, bool) {
+		tx, ok := runningTxs[txID]
+		if !ok {
+			return nil, false
+		}
+		if tx.Status().Decided() {
+			return nil, true
+		}
+		return []ids.ID{txID}, true
+	}).New(sampledNodes)
+	responses := 0
 	for _, index := range indices {
+		if p.Finished() {
+			break
+		}
+		responses++
+		peerID := n.nodeIDs[int(index)]
+		if rand.Intn(dropFrequency) == 0 { // #nosec G404
+			p.Vote(peerID, nil)
+			continue
+		}
+
 		peer := n.nodes[int(index)]
 		peerTxs := n.nodeTxs[int(index)]
 
-		preferences := peer.Preferences()
-		for _, color := range preferences.List() {
-			sampledColors.Add(color)
-		}
+		votes := peer.Preferences().List()
 		for _, tx := range peerTxs {
 			if tx.Status() == choices.Accepted {
-				sampledColors.Add(tx.ID())
+				votes = append(votes, tx.ID())
 			}
 		}
+		p.Vote(peerID, votes)
 	}
 
-	if _, err := running.RecordPoll(sampledColors); err != nil {
+	if responses < len(indices) {
+		n.earlyPolls++
+	}
+	result := p.Result()
+	if _, err := running.RecordPoll(result.Bag(n.params.Alpha)); err != nil {
 		return err
 	}
 
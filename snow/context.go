@@ -41,6 +41,24 @@ type SubnetLookup interface {
 	SubnetID(chainID ids.ID) (ids.ID, error)
 }
 
+// AppSender sends application-level messages to other nodes. Responses to
+// app requests, and notifications that they failed, are delivered to the VM's
+// common.AppHandler.
+type AppSender interface {
+	// Send an application-level request to each of [nodeIDs]. A response or
+	// a failure notification will be delivered for each node.
+	SendAppRequest(nodeIDs ids.ShortSet, requestID uint32, appRequestBytes []byte) error
+
+	// Send an application-level response to a request sent by [nodeID].
+	SendAppResponse(nodeID ids.ShortID, requestID uint32, appResponseBytes []byte) error
+
+	// Gossip an application-level message to a sample of the network.
+	SendAppGossip(appGossipBytes []byte) error
+
+	// Gossip an application-level message to each of [nodeIDs].
+	SendAppGossipSpecific(nodeIDs ids.ShortSet, appGossipBytes []byte) error
+}
+
 // Context is information about the current execution.
 // [NetworkID] is the ID of the network this context exists within.
 // [ChainID] is the ID of the chain this context exists within.
@@ -62,6 +80,8 @@ type Context struct {
 	SharedMemory        atomic.SharedMemory
 	BCLookup            AliasLookup
 	SNLookup            SubnetLookup
+	AppSender           AppSender
+	PeerReporter        PeerReporter
 	Namespace           string
 	Metrics             prometheus.Registerer
 
@@ -84,6 +104,14 @@ func (ctx *Context) Bootstrapped() {
 	stdatomic.StoreUint32(&ctx.bootstrapped, 1)
 }
 
+// ReportMisbehavior reports to the PeerReporter, if there is one, that the
+// peer [nodeID] misbehaved
+func (ctx *Context) ReportMisbehavior(nodeID ids.ShortID, misbehavior Misbehavior) {
+	if ctx.PeerReporter != nil {
+		ctx.PeerReporter.Misbehaved(nodeID, misbehavior)
+	}
+}
+
 // Epoch this context thinks it's in based on the wall clock time.
 func (ctx *Context) Epoch() uint32 {
 	now := ctx.Clock.Time()
@@ -243,6 +243,7 @@ func (b *Bootstrapper) process(vtxs ...avalanche.Vertex) error {
 func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) error {
 	if lenVtxs := len(vtxs); lenVtxs > common.MaxContainersPerMultiPut {
 		b.Ctx.Log.Debug("MultiPut(%s, %d) contains more than maximum number of vertices", vdr, requestID)
+		b.Ctx.ReportMisbehavior(vdr, snow.InvalidMessage)
 		return b.GetAncestorsFailed(vdr, requestID)
 	} else if lenVtxs == 0 {
 		b.Ctx.Log.Debug("MultiPut(%s, %d) contains no vertices", vdr, requestID)
@@ -266,6 +267,7 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte
 	// If the vertex is neither the requested vertex nor a needed vertex, return early and re-fetch if necessary
 	if requested && requestedVtxID != vtxID {
 		b.Ctx.Log.Debug("received incorrect vertex from %s with vertexID %s", vdr, vtxID)
+		b.Ctx.ReportMisbehavior(vdr, snow.InvalidContainer)
 		return b.fetch(requestedVtxID)
 	}
 	if !requested && !b.OutstandingRequests.Contains(vtxID) && !b.needToFetch.Contains(vtxID) {
@@ -401,6 +403,83 @@ func (b *Bootstrapper) executeAll(jobs *queue.Jobs, events snow.EventDispatcher)
 	return nil
 }
 
+// GetStateSummary implements the Engine interface. Avalanche VMs don't
+// summarize their state, so an empty summary is sent.
+func (b *Bootstrapper) GetStateSummary(vdr ids.ShortID, requestID uint32) error {
+	b.Sender.StateSummary(vdr, requestID, nil)
+	return nil
+}
+
+// StateSummary implements the Engine interface.
+func (b *Bootstrapper) StateSummary(vdr ids.ShortID, requestID uint32, _ []byte) error {
+	b.Ctx.Log.Debug("dropping StateSummary(%s, %d) as state sync isn't supported", vdr, requestID)
+	return nil
+}
+
+// GetStateSummaryFailed implements the Engine interface.
+func (b *Bootstrapper) GetStateSummaryFailed(vdr ids.ShortID, requestID uint32) error {
+	b.Ctx.Log.Debug("dropping GetStateSummaryFailed(%s, %d) as state sync isn't supported", vdr, requestID)
+	return nil
+}
+
+// GetStateChunk implements the Engine interface.
+func (b *Bootstrapper) GetStateChunk(vdr ids.ShortID, requestID uint32, _ ids.ID, _ uint32) error {
+	b.Sender.StateChunk(vdr, requestID, nil)
+	return nil
+}
+
+// StateChunk implements the Engine interface.
+func (b *Bootstrapper) StateChunk(vdr ids.ShortID, requestID uint32, _ []byte) error {
+	b.Ctx.Log.Debug("dropping StateChunk(%s, %d) as state sync isn't supported", vdr, requestID)
+	return nil
+}
+
+// GetStateChunkFailed implements the Engine interface.
+func (b *Bootstrapper) GetStateChunkFailed(vdr ids.ShortID, requestID uint32) error {
+	b.Ctx.Log.Debug("dropping GetStateChunkFailed(%s, %d) as state sync isn't supported", vdr, requestID)
+	return nil
+}
+
+// AppRequest implements the Engine interface.
+func (b *Bootstrapper) AppRequest(nodeID ids.ShortID, requestID uint32, request []byte) error {
+	handler, ok := b.VM.(common.AppHandler)
+	if !ok {
+		b.Ctx.Log.Debug("dropping AppRequest(%s, %d) as the VM doesn't handle app messages", nodeID, requestID)
+		return nil
+	}
+	return handler.AppRequest(nodeID, requestID, request)
+}
+
+// AppResponse implements the Engine interface.
+func (b *Bootstrapper) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
+	handler, ok := b.VM.(common.AppHandler)
+	if !ok {
+		b.Ctx.Log.Debug("dropping AppResponse(%s, %d) as the VM doesn't handle app messages", nodeID, requestID)
+		return nil
+	}
+	return handler.AppResponse(nodeID, requestID, response)
+}
+
+// AppRequestFailed implements the Engine interface.
+func (b *Bootstrapper) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
+	handler, ok := b.VM.(common.AppHandler)
+	if !ok {
+		b.Ctx.Log.Debug("dropping AppRequestFailed(%s, %d) as the VM doesn't handle app messages", nodeID, requestID)
+		return nil
+	}
+	return handler.AppRequestFailed(nodeID, requestID)
+}
+
+// AppGossip implements the Engine interface.
+func (b *Bootstrapper) AppGossip(nodeID ids.ShortID, msg []byte) error {
+	handler, ok := b.VM.(common.AppHandler)
+	if !ok {
+		b.Ctx.Log.Verbo("dropping AppGossip(%s) as the VM doesn't handle app messages", nodeID)
+		return nil
+	}
+	return handler.AppGossip(nodeID, msg)
+}
+
 // Connected implements the Engine interface.
 func (b *Bootstrapper) Connected(validatorID ids.ShortID) error {
 	if connector, ok := b.VM.(validators.Connector); ok {
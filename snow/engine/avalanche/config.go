@@ -6,6 +6,7 @@ package avalanche
 import (
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/bootstrap"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
 )
 
 // Config wraps all the parameters needed for an avalanche engine
@@ -14,4 +15,7 @@ type Config struct {
 
 	Params    avalanche.Parameters
 	Consensus avalanche.Consensus
+
+	// Tracer records the events of consensus. If nil, nothing is recorded.
+	Tracer tracer.Tracer
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche/poll"
@@ -16,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/bootstrap"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
 	"github.com/ava-labs/avalanchego/snow/events"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
@@ -59,6 +61,11 @@ type Transitive struct {
 	// optimal number.
 	pendingTxs []snowstorm.Tx
 
+	// tracer records the events of consensus. decisions is nil unless the
+	// events are recorded.
+	tracer    tracer.Tracer
+	decisions *tracer.Decisions
+
 	errs wrappers.Errs
 }
 
@@ -68,8 +75,13 @@ func (t *Transitive) Initialize(config Config) error {
 
 	t.Params = config.Params
 	t.Consensus = config.Consensus
+	t.tracer = tracer.Noop{}
+	if config.Tracer != nil {
+		t.tracer = config.Tracer
+		t.decisions = tracer.NewDecisions(config.Tracer)
+	}
 
-	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
+	factory := poll.NewFactory(config.Params.Parameters, t.traverse)
 	t.polls = poll.NewSet(factory,
 		config.Ctx.Log,
 		config.Params.Namespace,
@@ -101,7 +113,22 @@ func (t *Transitive) finishBootstrapping() error {
 	}
 
 	t.Ctx.Log.Info("bootstrapping finished with %d vertices in the accepted frontier", len(frontier))
-	return t.Consensus.Initialize(t.Ctx, t.Params, frontier)
+	if err := t.Consensus.Initialize(t.Ctx, t.Params, frontier); err != nil {
+		return err
+	}
+
+	frontierIDs := make([]ids.ID, len(frontier))
+	for i, vtx := range frontier {
+		frontierIDs[i] = vtx.ID()
+	}
+	t.tracer.Trace(&tracer.Initialize{
+		DAG:       true,
+		Params:    t.Params.Parameters,
+		Parents:   t.Params.Parents,
+		BatchSize: t.Params.BatchSize,
+		Accepted:  frontierIDs,
+	})
+	return nil
 }
 
 // Gossip implements the Engine interface
@@ -135,7 +162,12 @@ func (t *Transitive) Gossip() error {
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() error {
 	t.Ctx.Log.Info("shutting down consensus engine")
-	return t.VM.Shutdown()
+	errs := wrappers.Errs{}
+	errs.Add(
+		t.VM.Shutdown(),
+		t.tracer.Close(),
+	)
+	return errs.Err
 }
 
 // Get implements the Engine interface
@@ -311,7 +343,35 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) er
 		t.Ctx.Log.Debug("dropping Chits(%s, %d) due to bootstrapping", vdr, requestID)
 		return nil
 	}
+	t.tracer.Trace(&tracer.Chits{
+		NodeID:    vdr,
+		RequestID: requestID,
+		Votes:     votes,
+	})
+	if !t.Requested(requestID) {
+		t.Ctx.Log.Debug("Chits(%s, %d) is for a query that was never sent", vdr, requestID)
+		t.Ctx.ReportMisbehavior(vdr, snow.UnrequestedResponse)
+		return nil
+	}
+	return t.recordChits(vdr, requestID, votes)
+}
 
+// QueryFailed implements the Engine interface
+func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
+	if !t.Ctx.IsBootstrapped() {
+		t.Ctx.Log.Debug("dropping QueryFailed(%s, %d) due to bootstrapping", vdr, requestID)
+		return nil
+	}
+	t.tracer.Trace(&tracer.QueryFailed{
+		NodeID:    vdr,
+		RequestID: requestID,
+	})
+	return t.recordChits(vdr, requestID, nil)
+}
+
+// recordChits records [votes] from [vdr] once the vertices they're for are
+// issued. No votes means the query failed.
+func (t *Transitive) recordChits(vdr ids.ShortID, requestID uint32, votes []ids.ID) error {
 	v := &voter{
 		t:         t,
 		vdr:       vdr,
@@ -330,11 +390,6 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) er
 	return t.attemptToIssueTxs()
 }
 
-// QueryFailed implements the Engine interface
-func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
-	return t.Chits(vdr, requestID, nil)
-}
-
 // Notify implements the Engine interface
 func (t *Transitive) Notify(msg common.Message) error {
 	if !t.Ctx.IsBootstrapped() {
@@ -632,6 +687,87 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, vtxID ids.ID) {
 	t.numVtxRequests.Set(float64(t.outstandingVtxReqs.Len())) // Tracks performance statistics
 }
 
+// add [vtx], whose transactions are [txs], to consensus and trace it
+func (t *Transitive) add(vtx avalanche.Vertex, txs []snowstorm.Tx) error {
+	parents, err := vtx.Parents()
+	if err != nil {
+		return err
+	}
+	height, err := vtx.Height()
+	if err != nil {
+		return err
+	}
+	issue := &tracer.Issue{
+		ID:      vtx.ID(),
+		Parents: make([]ids.ID, len(parents)),
+		Height:  height,
+		Txs:     make([]tracer.Tx, len(txs)),
+	}
+	for i, parent := range parents {
+		issue.Parents[i] = parent.ID()
+	}
+	for i, tx := range txs {
+		deps := tx.Dependencies()
+		issue.Txs[i] = tracer.Tx{
+			ID:           tx.ID(),
+			Dependencies: make([]ids.ID, len(deps)),
+			InputIDs:     tx.InputIDs(),
+		}
+		for j, dep := range deps {
+			issue.Txs[i].Dependencies[j] = dep.ID()
+		}
+	}
+	t.tracer.Trace(issue)
+
+	if err := t.Consensus.Add(vtx); err != nil {
+		return err
+	}
+	if t.decisions != nil {
+		t.decisions.Issued(vtx)
+		t.decisions.Trace()
+	}
+	return nil
+}
+
+// traverse returns the processing transactions that a vote for [vtxID] is
+// applied to, the transactions of [vtxID] and of its processing ancestors
+func (t *Transitive) traverse(vtxID ids.ID) ([]ids.ID, bool) {
+	vtx, err := t.Manager.Get(vtxID)
+	if err != nil {
+		return nil, false
+	}
+
+	txIDs := ids.Set{}
+	visited := ids.Set{}
+	frontier := []avalanche.Vertex{vtx}
+	for len(frontier) > 0 {
+		newLen := len(frontier) - 1
+		vtx := frontier[newLen]
+		frontier = frontier[:newLen]
+
+		if vtx.Status() != choices.Processing || visited.Contains(vtx.ID()) {
+			continue
+		}
+		visited.Add(vtx.ID())
+
+		txs, err := vtx.Txs()
+		if err != nil {
+			return nil, false
+		}
+		for _, tx := range txs {
+			if !tx.Status().Decided() {
+				txIDs.Add(tx.ID())
+			}
+		}
+		parents, err := vtx.Parents()
+		if err != nil {
+			return nil, false
+		}
+		frontier = append(frontier, parents...)
+	}
+	return txIDs.List(), true
+}
+
 // Health implements the common.Engine interface
 func (t *Transitive) Health() (interface{}, error) {
 	// TODO add more health checks
@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
@@ -3795,3 +3796,51 @@ func TestEngineIssue(t *testing.T) {
 		t.Fatalf("Should have issued txs differently")
 	}
 }
+
+// misbehaviorRecorder records the misbehavior reported to it
+type misbehaviorRecorder map[ids.ShortID][]snow.Misbehavior
+
+func (r misbehaviorRecorder) Misbehaved(nodeID ids.ShortID, misbehavior snow.Misbehavior) {
+	r[nodeID] = append(r[nodeID], misbehavior)
+}
+
+func TestEngineUnrequestedChits(t *testing.T) {
+	config := DefaultConfig()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vdr := ids.GenerateTestShortID()
+	if err := vals.AddWeight(vdr, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	manager := vertex.NewTestManager(t)
+	config.Manager = manager
+
+	manager.Default(true)
+
+	manager.CantEdge = false
+
+	te := &Transitive{}
+	if err := te.Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+
+	reporter := misbehaviorRecorder{}
+	te.Ctx.PeerReporter = reporter
+
+	if err := te.Chits(vdr, te.RequestID+1, []ids.ID{ids.GenerateTestID()}); err != nil {
+		t.Fatal(err)
+	}
+	if misbehavior := reporter[vdr]; len(misbehavior) != 1 || misbehavior[0] != snow.UnrequestedResponse {
+		t.Fatalf("expected an unrequested response to be reported but got %v", misbehavior)
+	}
+}
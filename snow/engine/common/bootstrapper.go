@@ -92,6 +92,12 @@ func (b *Bootstrapper) Startup() error {
 	return nil
 }
 
+// Requested returns false if a request with [requestID] was never sent. Request
+// IDs are sent in increasing order, so a larger request ID than the last one
+// sent was never sent. A response to an older request ID that is no longer
+// outstanding may just have arrived after the request timed out.
+func (b *Bootstrapper) Requested(requestID uint32) bool { return requestID <= b.RequestID }
+
 // GetAcceptedFrontier implements the Engine interface.
 func (b *Bootstrapper) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) error {
 	b.Sender.AcceptedFrontier(validatorID, requestID, b.Bootstrapable.CurrentAcceptedFrontier())
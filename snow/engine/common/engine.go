@@ -36,6 +36,8 @@ type ExternalHandler interface {
 	AcceptedHandler
 	FetchHandler
 	QueryHandler
+	StateSyncHandler
+	AppHandler
 }
 
 // FrontierHandler defines how a consensus engine reacts to frontier messages
@@ -276,6 +278,110 @@ type QueryHandler interface {
 	QueryFailed(validatorID ids.ShortID, requestID uint32) error
 }
 
+// StateSyncHandler defines how a consensus engine reacts to state sync messages
+// from other validators. Functions only return fatal errors if they occur.
+type StateSyncHandler interface {
+	// Notify this engine of a request for its most recent state summary.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is utilizing a unique requestID. However, the validatorID is
+	// assumed to be authenticated.
+	//
+	// This engine should respond with a StateSummary message with the same
+	// requestID. If the chain's VM doesn't summarize its state, or hasn't
+	// built a summary yet, the summary should be empty.
+	GetStateSummary(validatorID ids.ShortID, requestID uint32) error
+
+	// Notify this engine of a state summary.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is in response to a GetStateSummary message, is utilizing a
+	// unique requestID, or that the summary is valid. However, the validatorID
+	// is assumed to be authenticated.
+	StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) error
+
+	// Notify this engine that a GetStateSummary request it issued has failed.
+	//
+	// This function will be called if the engine sent a GetStateSummary
+	// message that is not anticipated to be responded to. This could be
+	// because the recipient of the message is unknown or if the message
+	// request has timed out.
+	//
+	// The validatorID and requestID are assumed to be the same as those sent in
+	// the GetStateSummary message.
+	GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error
+
+	// Notify this engine of a request for chunk [index] of the state described
+	// by the summary with ID [summaryID].
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is utilizing a unique requestID. It is also not safe to
+	// assume the requested summary or chunk exists. However, the validatorID is
+	// assumed to be authenticated.
+	//
+	// This engine should respond with a StateChunk message with the same
+	// requestID. If the chunk isn't locally available, the chunk in the
+	// response should be empty.
+	GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) error
+
+	// Notify this engine of a chunk of state.
+	//
+	// This function can be called by any validator. It is not safe to assume
+	// this message is in response to a GetStateChunk message, is utilizing a
+	// unique requestID, or that the chunk is valid. However, the validatorID is
+	// assumed to be authenticated. An empty chunk means the validator doesn't
+	// have the requested chunk.
+	StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) error
+
+	// Notify this engine that a GetStateChunk request it issued has failed.
+	//
+	// This function will be called if the engine sent a GetStateChunk message
+	// that is not anticipated to be responded to. This could be because the
+	// recipient of the message is unknown or if the message request has timed
+	// out.
+	//
+	// The validatorID and requestID are assumed to be the same as those sent in
+	// the GetStateChunk message.
+	GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) error
+}
+
+// AppHandler defines how a consensus engine, or a VM that implements it,
+// reacts to application-level messages from other nodes. Functions only return
+// fatal errors if they occur.
+type AppHandler interface {
+	// Notify this engine of an application-level request.
+	//
+	// This function can be called by any node. It is not safe to assume this
+	// message is utilizing a unique requestID. However, the nodeID is assumed
+	// to be authenticated.
+	//
+	// The response, if any, should be sent with AppSender.SendAppResponse
+	// using the same requestID.
+	AppRequest(nodeID ids.ShortID, requestID uint32, request []byte) error
+
+	// Notify this engine that an AppRequest message it sent to [nodeID] with
+	// request ID [requestID] failed.
+	//
+	// This function will be called if the request was sent but is not
+	// anticipated to be responded to. This could be because the recipient of
+	// the message is unknown or if the message request has timed out.
+	AppRequestFailed(nodeID ids.ShortID, requestID uint32) error
+
+	// Notify this engine of a response to an AppRequest message it sent to
+	// [nodeID] with request ID [requestID].
+	//
+	// This function can be called by any node. It is not safe to assume this
+	// message is in response to an AppRequest message or that the response is
+	// valid. However, the nodeID is assumed to be authenticated.
+	AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error
+
+	// Notify this engine of a gossiped application-level message.
+	//
+	// This function can be called by any node. It is not safe to assume the
+	// message is valid. However, the nodeID is assumed to be authenticated.
+	AppGossip(nodeID ids.ShortID, msg []byte) error
+}
+
 // InternalHandler defines how this consensus engine reacts to messages from
 // other components of this validator. Functions only return fatal errors if
 // they occur.
@@ -14,6 +14,7 @@ type Sender interface {
 	AcceptedSender
 	FetchSender
 	QuerySender
+	StateSyncSender
 	Gossiper
 }
 
@@ -80,6 +81,25 @@ type QuerySender interface {
 	Chits(validatorID ids.ShortID, requestID uint32, votes []ids.ID)
 }
 
+// StateSyncSender defines how a consensus engine sends state sync messages to
+// other validators
+type StateSyncSender interface {
+	// GetStateSummary requests that every validator in [validatorIDs] sends a
+	// StateSummary message with its most recent summary of the chain's state.
+	GetStateSummary(validatorIDs ids.ShortSet, requestID uint32)
+
+	// StateSummary responds to a GetStateSummary message with this engine's
+	// most recent state summary. [summary] is empty if there is none.
+	StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte)
+
+	// GetStateChunk requests that the validator with ID [validatorID] sends
+	// chunk [index] of the state described by the summary with ID [summaryID].
+	GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32)
+
+	// StateChunk responds to a GetStateChunk message with the requested chunk.
+	StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte)
+}
+
 // Gossiper defines how a consensus engine gossips a container on the accepted
 // frontier to other validators
 type Gossiper interface {
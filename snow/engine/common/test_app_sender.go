@@ -0,0 +1,94 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+)
+
+var (
+	errSendAppRequest        = errors.New("unexpectedly called SendAppRequest")
+	errSendAppResponse       = errors.New("unexpectedly called SendAppResponse")
+	errSendAppGossip         = errors.New("unexpectedly called SendAppGossip")
+	errSendAppGossipSpecific = errors.New("unexpectedly called SendAppGossipSpecific")
+
+	_ snow.AppSender = &AppSenderTest{}
+)
+
+// AppSenderTest is a test app sender
+type AppSenderTest struct {
+	T *testing.T
+
+	CantSendAppRequest, CantSendAppResponse,
+	CantSendAppGossip, CantSendAppGossipSpecific bool
+
+	SendAppRequestF        func(ids.ShortSet, uint32, []byte) error
+	SendAppResponseF       func(ids.ShortID, uint32, []byte) error
+	SendAppGossipF         func([]byte) error
+	SendAppGossipSpecificF func(ids.ShortSet, []byte) error
+}
+
+// Default set the default callable value to [cant]
+func (s *AppSenderTest) Default(cant bool) {
+	s.CantSendAppRequest = cant
+	s.CantSendAppResponse = cant
+	s.CantSendAppGossip = cant
+	s.CantSendAppGossipSpecific = cant
+}
+
+// SendAppRequest calls SendAppRequestF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *AppSenderTest) SendAppRequest(nodeIDs ids.ShortSet, requestID uint32, appRequestBytes []byte) error {
+	if s.SendAppRequestF != nil {
+		return s.SendAppRequestF(nodeIDs, requestID, appRequestBytes)
+	}
+	if s.CantSendAppRequest && s.T != nil {
+		s.T.Fatal(errSendAppRequest)
+	}
+	return errSendAppRequest
+}
+
+// SendAppResponse calls SendAppResponseF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *AppSenderTest) SendAppResponse(nodeID ids.ShortID, requestID uint32, appResponseBytes []byte) error {
+	if s.SendAppResponseF != nil {
+		return s.SendAppResponseF(nodeID, requestID, appResponseBytes)
+	}
+	if s.CantSendAppResponse && s.T != nil {
+		s.T.Fatal(errSendAppResponse)
+	}
+	return errSendAppResponse
+}
+
+// SendAppGossip calls SendAppGossipF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *AppSenderTest) SendAppGossip(appGossipBytes []byte) error {
+	if s.SendAppGossipF != nil {
+		return s.SendAppGossipF(appGossipBytes)
+	}
+	if s.CantSendAppGossip && s.T != nil {
+		s.T.Fatal(errSendAppGossip)
+	}
+	return errSendAppGossip
+}
+
+// SendAppGossipSpecific calls SendAppGossipSpecificF if it was initialized. If
+// it wasn't initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *AppSenderTest) SendAppGossipSpecific(nodeIDs ids.ShortSet, appGossipBytes []byte) error {
+	if s.SendAppGossipSpecificF != nil {
+		return s.SendAppGossipSpecificF(nodeIDs, appGossipBytes)
+	}
+	if s.CantSendAppGossipSpecific && s.T != nil {
+		s.T.Fatal(errSendAppGossipSpecific)
+	}
+	return errSendAppGossipSpecific
+}
@@ -44,6 +44,18 @@ type EngineTest struct {
 	CantQueryFailed,
 	CantChits,
 
+	CantGetStateSummary,
+	CantStateSummary,
+	CantGetStateSummaryFailed,
+	CantGetStateChunk,
+	CantStateChunk,
+	CantGetStateChunkFailed,
+
+	CantAppRequest,
+	CantAppRequestFailed,
+	CantAppResponse,
+	CantAppGossip,
+
 	CantConnected,
 	CantDisconnected,
 
@@ -58,9 +70,15 @@ type EngineTest struct {
 	MultiPutF                                          func(validatorID ids.ShortID, requestID uint32, containers [][]byte) error
 	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF func(validatorID ids.ShortID, requestID uint32, containerIDs []ids.ID) error
 	GetAcceptedFrontierF, GetFailedF, GetAncestorsFailedF,
-	QueryFailedF, GetAcceptedFrontierFailedF, GetAcceptedFailedF func(validatorID ids.ShortID, requestID uint32) error
-	ConnectedF, DisconnectedF func(validatorID ids.ShortID) error
-	HealthF                   func() (interface{}, error)
+	QueryFailedF, GetAcceptedFrontierFailedF, GetAcceptedFailedF,
+	GetStateSummaryF, GetStateSummaryFailedF, GetStateChunkFailedF func(validatorID ids.ShortID, requestID uint32) error
+	StateSummaryF, StateChunkF func(validatorID ids.ShortID, requestID uint32, bytes []byte) error
+	GetStateChunkF             func(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) error
+	AppRequestF, AppResponseF  func(nodeID ids.ShortID, requestID uint32, msg []byte) error
+	AppRequestFailedF          func(nodeID ids.ShortID, requestID uint32) error
+	AppGossipF                 func(nodeID ids.ShortID, msg []byte) error
+	ConnectedF, DisconnectedF  func(validatorID ids.ShortID) error
+	HealthF                    func() (interface{}, error)
 }
 
 var _ Engine = &EngineTest{}
@@ -97,6 +115,18 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantQueryFailed = cant
 	e.CantChits = cant
 
+	e.CantGetStateSummary = cant
+	e.CantStateSummary = cant
+	e.CantGetStateSummaryFailed = cant
+	e.CantGetStateChunk = cant
+	e.CantStateChunk = cant
+	e.CantGetStateChunkFailed = cant
+
+	e.CantAppRequest = cant
+	e.CantAppRequestFailed = cant
+	e.CantAppResponse = cant
+	e.CantAppGossip = cant
+
 	e.CantConnected = cant
 	e.CantDisconnected = cant
 
@@ -395,6 +425,146 @@ func (e *EngineTest) Chits(validatorID ids.ShortID, requestID uint32, containerI
 	return errors.New("unexpectedly called Chits")
 }
 
+// GetStateSummary ...
+func (e *EngineTest) GetStateSummary(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetStateSummaryF != nil {
+		return e.GetStateSummaryF(validatorID, requestID)
+	}
+	if !e.CantGetStateSummary {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+	return errors.New("unexpectedly called GetStateSummary")
+}
+
+// StateSummary ...
+func (e *EngineTest) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) error {
+	if e.StateSummaryF != nil {
+		return e.StateSummaryF(validatorID, requestID, summary)
+	}
+	if !e.CantStateSummary {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called StateSummary")
+	}
+	return errors.New("unexpectedly called StateSummary")
+}
+
+// GetStateSummaryFailed ...
+func (e *EngineTest) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetStateSummaryFailedF != nil {
+		return e.GetStateSummaryFailedF(validatorID, requestID)
+	}
+	if !e.CantGetStateSummaryFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummaryFailed")
+	}
+	return errors.New("unexpectedly called GetStateSummaryFailed")
+}
+
+// GetStateChunk ...
+func (e *EngineTest) GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) error {
+	if e.GetStateChunkF != nil {
+		return e.GetStateChunkF(validatorID, requestID, summaryID, index)
+	}
+	if !e.CantGetStateChunk {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateChunk")
+	}
+	return errors.New("unexpectedly called GetStateChunk")
+}
+
+// StateChunk ...
+func (e *EngineTest) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) error {
+	if e.StateChunkF != nil {
+		return e.StateChunkF(validatorID, requestID, chunk)
+	}
+	if !e.CantStateChunk {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called StateChunk")
+	}
+	return errors.New("unexpectedly called StateChunk")
+}
+
+// GetStateChunkFailed ...
+func (e *EngineTest) GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) error {
+	if e.GetStateChunkFailedF != nil {
+		return e.GetStateChunkFailedF(validatorID, requestID)
+	}
+	if !e.CantGetStateChunkFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateChunkFailed")
+	}
+	return errors.New("unexpectedly called GetStateChunkFailed")
+}
+
+// AppRequest ...
+func (e *EngineTest) AppRequest(nodeID ids.ShortID, requestID uint32, request []byte) error {
+	if e.AppRequestF != nil {
+		return e.AppRequestF(nodeID, requestID, request)
+	}
+	if !e.CantAppRequest {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called AppRequest")
+	}
+	return errors.New("unexpectedly called AppRequest")
+}
+
+// AppRequestFailed ...
+func (e *EngineTest) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
+	if e.AppRequestFailedF != nil {
+		return e.AppRequestFailedF(nodeID, requestID)
+	}
+	if !e.CantAppRequestFailed {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called AppRequestFailed")
+	}
+	return errors.New("unexpectedly called AppRequestFailed")
+}
+
+// AppResponse ...
+func (e *EngineTest) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
+	if e.AppResponseF != nil {
+		return e.AppResponseF(nodeID, requestID, response)
+	}
+	if !e.CantAppResponse {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called AppResponse")
+	}
+	return errors.New("unexpectedly called AppResponse")
+}
+
+// AppGossip ...
+func (e *EngineTest) AppGossip(nodeID ids.ShortID, msg []byte) error {
+	if e.AppGossipF != nil {
+		return e.AppGossipF(nodeID, msg)
+	}
+	if !e.CantAppGossip {
+		return nil
+	}
+	if e.T != nil {
+		e.T.Fatalf("Unexpectedly called AppGossip")
+	}
+	return errors.New("unexpectedly called AppGossip")
+}
+
 // Connected ...
 func (e *EngineTest) Connected(validatorID ids.ShortID) error {
 	if e.ConnectedF != nil {
@@ -17,6 +17,8 @@ type SenderTest struct {
 	CantGetAccepted, CantAccepted,
 	CantGet, CantGetAncestors, CantPut, CantMultiPut,
 	CantPullQuery, CantPushQuery, CantChits,
+	CantGetStateSummary, CantStateSummary,
+	CantGetStateChunk, CantStateChunk,
 	CantGossip bool
 
 	GetAcceptedFrontierF func(ids.ShortSet, uint32)
@@ -30,6 +32,10 @@ type SenderTest struct {
 	PushQueryF           func(ids.ShortSet, uint32, ids.ID, []byte)
 	PullQueryF           func(ids.ShortSet, uint32, ids.ID)
 	ChitsF               func(ids.ShortID, uint32, []ids.ID)
+	GetStateSummaryF     func(ids.ShortSet, uint32)
+	StateSummaryF        func(ids.ShortID, uint32, []byte)
+	GetStateChunkF       func(ids.ShortID, uint32, ids.ID, uint32)
+	StateChunkF          func(ids.ShortID, uint32, []byte)
 	GossipF              func(ids.ID, []byte)
 }
 
@@ -46,6 +52,10 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantPullQuery = cant
 	s.CantPushQuery = cant
 	s.CantChits = cant
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
+	s.CantGetStateChunk = cant
+	s.CantStateChunk = cant
 	s.CantGossip = cant
 }
 
@@ -170,6 +180,50 @@ func (s *SenderTest) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) {
 	}
 }
 
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GetStateSummary(vdrs ids.ShortSet, requestID uint32) {
+	if s.GetStateSummaryF != nil {
+		s.GetStateSummaryF(vdrs, requestID)
+	} else if s.CantGetStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) StateSummary(vdr ids.ShortID, requestID uint32, summary []byte) {
+	if s.StateSummaryF != nil {
+		s.StateSummaryF(vdr, requestID, summary)
+	} else if s.CantStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
+// GetStateChunk calls GetStateChunkF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GetStateChunk(vdr ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+	if s.GetStateChunkF != nil {
+		s.GetStateChunkF(vdr, requestID, summaryID, index)
+	} else if s.CantGetStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateChunk")
+	}
+}
+
+// StateChunk calls StateChunkF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *SenderTest) StateChunk(vdr ids.ShortID, requestID uint32, chunk []byte) {
+	if s.StateChunkF != nil {
+		s.StateChunkF(vdr, requestID, chunk)
+	} else if s.CantStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateChunk")
+	}
+}
+
 // Gossip calls GossipF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
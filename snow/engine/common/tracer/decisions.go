@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracer
+
+import (
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+)
+
+// Decisions traces the decisions of the containers issued into consensus.
+//
+// Consensus decides containers without telling the engine, so the engine calls
+// Trace whenever consensus may have decided some, after adding a container and
+// after recording a poll. Decisions are traced in the order their containers
+// were issued, so an accepted container is traced before its descendants.
+//
+// Decisions isn't safe for concurrent use.
+type Decisions struct {
+	tracer     Tracer
+	numIssued  uint64
+	processing map[ids.ID]issued
+}
+
+type issued struct {
+	choices.Decidable
+	// index is the number of containers issued before this one
+	index uint64
+}
+
+// NewDecisions returns a Decisions that traces to [tracer]
+func NewDecisions(tracer Tracer) *Decisions {
+	return &Decisions{
+		tracer:     tracer,
+		processing: make(map[ids.ID]issued),
+	}
+}
+
+// Issued marks [c] as issued into consensus
+func (d *Decisions) Issued(c choices.Decidable) {
+	d.processing[c.ID()] = issued{
+		Decidable: c,
+		index:     d.numIssued,
+	}
+	d.numIssued++
+}
+
+// Trace traces the decisions of the issued containers that were decided since
+// the last call
+func (d *Decisions) Trace() {
+	decided := []issued(nil)
+	for id, c := range d.processing {
+		if c.Status().Decided() {
+			decided = append(decided, c)
+			delete(d.processing, id)
+		}
+	}
+	sort.Slice(decided, func(i, j int) bool { return decided[i].index < decided[j].index })
+
+	for _, c := range decided {
+		if c.Status() == choices.Accepted {
+			d.tracer.Trace(&Accept{ID: c.ID()})
+		} else {
+			d.tracer.Trace(&Reject{ID: c.ID()})
+		}
+	}
+}
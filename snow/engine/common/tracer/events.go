@@ -0,0 +1,375 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	idLen      = len(ids.ID{})
+	shortIDLen = len(ids.ShortID{})
+)
+
+// EventType identifies the type of an event in a trace
+type EventType byte
+
+// The types of events in a trace
+const (
+	InitializeType EventType = iota + 1
+	IssueType
+	ChitsType
+	QueryFailedType
+	PollType
+	VertexPollType
+	AcceptType
+	RejectType
+)
+
+func (t EventType) String() string {
+	switch t {
+	case InitializeType:
+		return "Initialize"
+	case IssueType:
+		return "Issue"
+	case ChitsType:
+		return "Chits"
+	case QueryFailedType:
+		return "QueryFailed"
+	case PollType:
+		return "Poll"
+	case VertexPollType:
+		return "VertexPoll"
+	case AcceptType:
+		return "Accept"
+	case RejectType:
+		return "Reject"
+	default:
+		return fmt.Sprintf("Unknown(%d)", byte(t))
+	}
+}
+
+// Event is an event of a consensus engine
+type Event interface {
+	Type() EventType
+
+	pack(p *wrappers.Packer)
+	unpack(p *wrappers.Packer)
+}
+
+// newEvent returns an empty event of type [t]
+func newEvent(t EventType) (Event, error) {
+	switch t {
+	case InitializeType:
+		return &Initialize{}, nil
+	case IssueType:
+		return &Issue{}, nil
+	case ChitsType:
+		return &Chits{}, nil
+	case QueryFailedType:
+		return &QueryFailed{}, nil
+	case PollType:
+		return &Poll{}, nil
+	case VertexPollType:
+		return &VertexPoll{}, nil
+	case AcceptType:
+		return &Accept{}, nil
+	case RejectType:
+		return &Reject{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownEventType, t)
+	}
+}
+
+// Initialize is the start of consensus, once the chain finished bootstrapping
+type Initialize struct {
+	// DAG is true if the chain runs Avalanche, rather than Snowman, consensus
+	DAG bool
+	// Parameters of consensus
+	Params snowball.Parameters
+	// Parameters of the vertices of a DAG
+	Parents, BatchSize int
+	// The last accepted block, or the accepted frontier of vertices
+	Accepted []ids.ID
+}
+
+// Type implements the Event interface
+func (*Initialize) Type() EventType { return InitializeType }
+
+func (e *Initialize) pack(p *wrappers.Packer) {
+	p.PackBool(e.DAG)
+	for _, param := range []int{
+		e.Params.K,
+		e.Params.Alpha,
+		e.Params.BetaVirtuous,
+		e.Params.BetaRogue,
+		e.Params.ConcurrentRepolls,
+		e.Params.OptimalProcessing,
+		e.Parents,
+		e.BatchSize,
+	} {
+		p.PackInt(uint32(param))
+	}
+	packIDs(p, e.Accepted)
+}
+
+func (e *Initialize) unpack(p *wrappers.Packer) {
+	e.DAG = p.UnpackBool()
+	for _, param := range []*int{
+		&e.Params.K,
+		&e.Params.Alpha,
+		&e.Params.BetaVirtuous,
+		&e.Params.BetaRogue,
+		&e.Params.ConcurrentRepolls,
+		&e.Params.OptimalProcessing,
+		&e.Parents,
+		&e.BatchSize,
+	} {
+		*param = int(p.UnpackInt())
+	}
+	e.Accepted = unpackIDs(p)
+}
+
+// Issue is a block, or a vertex, being added to consensus
+type Issue struct {
+	ID      ids.ID
+	Parents []ids.ID
+	Height  uint64
+	// Transactions of a vertex
+	Txs []Tx
+}
+
+// Tx is a transaction of an issued vertex
+type Tx struct {
+	ID           ids.ID
+	Dependencies []ids.ID
+	InputIDs     []ids.ID
+}
+
+// Type implements the Event interface
+func (*Issue) Type() EventType { return IssueType }
+
+func (e *Issue) pack(p *wrappers.Packer) {
+	p.PackFixedBytes(e.ID[:])
+	packIDs(p, e.Parents)
+	p.PackLong(e.Height)
+	p.PackInt(uint32(len(e.Txs)))
+	for _, tx := range e.Txs {
+		p.PackFixedBytes(tx.ID[:])
+		packIDs(p, tx.Dependencies)
+		packIDs(p, tx.InputIDs)
+	}
+}
+
+func (e *Issue) unpack(p *wrappers.Packer) {
+	e.ID = unpackID(p)
+	e.Parents = unpackIDs(p)
+	e.Height = p.UnpackLong()
+	numTxs := p.UnpackInt()
+	if p.Errored() {
+		return
+	}
+	// Each tx takes at least an ID and two lengths
+	if int(numTxs) > remaining(p)/(idLen+2*wrappers.IntLen) {
+		p.Add(errTooManyElements)
+		return
+	}
+	e.Txs = make([]Tx, numTxs)
+	for i := range e.Txs {
+		e.Txs[i] = Tx{
+			ID:           unpackID(p),
+			Dependencies: unpackIDs(p),
+			InputIDs:     unpackIDs(p),
+		}
+	}
+}
+
+// Chits is a response to a query
+type Chits struct {
+	NodeID    ids.ShortID
+	RequestID uint32
+	Votes     []ids.ID
+}
+
+// Type implements the Event interface
+func (*Chits) Type() EventType { return ChitsType }
+
+func (e *Chits) pack(p *wrappers.Packer) {
+	p.PackFixedBytes(e.NodeID[:])
+	p.PackInt(e.RequestID)
+	packIDs(p, e.Votes)
+}
+
+func (e *Chits) unpack(p *wrappers.Packer) {
+	copy(e.NodeID[:], p.UnpackFixedBytes(shortIDLen))
+	e.RequestID = p.UnpackInt()
+	e.Votes = unpackIDs(p)
+}
+
+// QueryFailed is a query that wasn't answered
+type QueryFailed struct {
+	NodeID    ids.ShortID
+	RequestID uint32
+}
+
+// Type implements the Event interface
+func (*QueryFailed) Type() EventType { return QueryFailedType }
+
+func (e *QueryFailed) pack(p *wrappers.Packer) {
+	p.PackFixedBytes(e.NodeID[:])
+	p.PackInt(e.RequestID)
+}
+
+func (e *QueryFailed) unpack(p *wrappers.Packer) {
+	copy(e.NodeID[:], p.UnpackFixedBytes(shortIDLen))
+	e.RequestID = p.UnpackInt()
+}
+
+// Poll is the outcome of a Snowman poll, as it's recorded in consensus. The
+// decisions it causes follow it.
+type Poll struct {
+	Votes ids.Bag
+}
+
+// Type implements the Event interface
+func (*Poll) Type() EventType { return PollType }
+
+func (e *Poll) pack(p *wrappers.Packer) {
+	votes := e.Votes.List()
+	ids.SortIDs(votes)
+	p.PackInt(uint32(len(votes)))
+	for _, vote := range votes {
+		p.PackFixedBytes(vote[:])
+		p.PackInt(uint32(e.Votes.Count(vote)))
+	}
+}
+
+func (e *Poll) String() string {
+	votes := e.Votes.List()
+	ids.SortIDs(votes)
+	voteStrs := make([]string, len(votes))
+	for i, vote := range votes {
+		voteStrs[i] = fmt.Sprintf("%s:%d", vote, e.Votes.Count(vote))
+	}
+	return fmt.Sprintf("&{Votes:[%s]}", strings.Join(voteStrs, " "))
+}
+
+func (e *Poll) unpack(p *wrappers.Packer) {
+	numVotes := p.UnpackInt()
+	if p.Errored() {
+		return
+	}
+	if int(numVotes) > remaining(p)/(idLen+wrappers.IntLen) {
+		p.Add(errTooManyElements)
+		return
+	}
+	for i := uint32(0); i < numVotes; i++ {
+		vote := unpackID(p)
+		e.Votes.AddCount(vote, int(p.UnpackInt()))
+	}
+}
+
+// VertexPoll is the outcome of an Avalanche poll, as it's recorded in
+// consensus. The decisions it causes follow it.
+type VertexPoll struct {
+	Votes ids.UniqueBag
+}
+
+// Type implements the Event interface
+func (*VertexPoll) Type() EventType { return VertexPollType }
+
+func (e *VertexPoll) pack(p *wrappers.Packer) {
+	votes := e.Votes.List()
+	ids.SortIDs(votes)
+	p.PackInt(uint32(len(votes)))
+	for _, vote := range votes {
+		p.PackFixedBytes(vote[:])
+		p.PackLong(uint64(e.Votes.GetSet(vote)))
+	}
+}
+
+func (e *VertexPoll) String() string {
+	votes := e.Votes.List()
+	ids.SortIDs(votes)
+	voteStrs := make([]string, len(votes))
+	for i, vote := range votes {
+		voteStrs[i] = fmt.Sprintf("%s:%s", vote, e.Votes.GetSet(vote))
+	}
+	return fmt.Sprintf("&{Votes:[%s]}", strings.Join(voteStrs, " "))
+}
+
+func (e *VertexPoll) unpack(p *wrappers.Packer) {
+	numVotes := p.UnpackInt()
+	if p.Errored() {
+		return
+	}
+	if int(numVotes) > remaining(p)/(idLen+wrappers.LongLen) {
+		p.Add(errTooManyElements)
+		return
+	}
+	e.Votes = make(ids.UniqueBag, numVotes)
+	for i := uint32(0); i < numVotes; i++ {
+		vote := unpackID(p)
+		e.Votes.UnionSet(vote, ids.BitSet(p.UnpackLong()))
+	}
+}
+
+// Accept is a block, or a vertex, being accepted
+type Accept struct {
+	ID ids.ID
+}
+
+// Type implements the Event interface
+func (*Accept) Type() EventType { return AcceptType }
+
+func (e *Accept) pack(p *wrappers.Packer)   { p.PackFixedBytes(e.ID[:]) }
+func (e *Accept) unpack(p *wrappers.Packer) { e.ID = unpackID(p) }
+
+// Reject is a block, or a vertex, being rejected
+type Reject struct {
+	ID ids.ID
+}
+
+// Type implements the Event interface
+func (*Reject) Type() EventType { return RejectType }
+
+func (e *Reject) pack(p *wrappers.Packer)   { p.PackFixedBytes(e.ID[:]) }
+func (e *Reject) unpack(p *wrappers.Packer) { e.ID = unpackID(p) }
+
+func packIDs(p *wrappers.Packer, idList []ids.ID) {
+	p.PackInt(uint32(len(idList)))
+	for _, id := range idList {
+		p.PackFixedBytes(id[:])
+	}
+}
+
+func unpackID(p *wrappers.Packer) ids.ID {
+	id := ids.ID{}
+	copy(id[:], p.UnpackFixedBytes(idLen))
+	return id
+}
+
+func unpackIDs(p *wrappers.Packer) []ids.ID {
+	numIDs := p.UnpackInt()
+	if p.Errored() {
+		return nil
+	}
+	if int(numIDs) > remaining(p)/idLen {
+		p.Add(errTooManyElements)
+		return nil
+	}
+	idList := make([]ids.ID, numIDs)
+	for i := range idList {
+		idList[i] = unpackID(p)
+	}
+	return idList
+}
+
+// remaining returns the number of bytes [p] hasn't unpacked
+func remaining(p *wrappers.Packer) int { return len(p.Bytes) - p.Offset }
@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
+)
+
+var _ consensus = &chain{}
+
+// chain replays a trace of Snowman consensus
+type chain struct {
+	consensus *snowman.Topological
+	blocks    map[ids.ID]*snowman.TestBlock
+}
+
+func newChain(ctx *snow.Context, params snowball.Parameters, init *tracer.Initialize) (*chain, error) {
+	c := &chain{
+		consensus: &snowman.Topological{},
+		blocks:    make(map[ids.ID]*snowman.TestBlock),
+	}
+	for _, blkID := range init.Accepted {
+		c.blocks[blkID] = &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+			IDV:     blkID,
+			StatusV: choices.Accepted,
+		}}
+	}
+	lastAcceptedID := ids.Empty
+	if len(init.Accepted) > 0 {
+		lastAcceptedID = init.Accepted[0]
+	}
+	return c, c.consensus.Initialize(ctx, params, lastAcceptedID)
+}
+
+func (c *chain) issue(e *tracer.Issue) (choices.Decidable, error) {
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     e.ID,
+			StatusV: choices.Processing,
+		},
+		HeightV: e.Height,
+	}
+	if len(e.Parents) > 0 {
+		blk.ParentV = c.block(e.Parents[0])
+	}
+	c.blocks[e.ID] = blk
+	return blk, c.consensus.Add(blk)
+}
+
+func (c *chain) recordPoll(e tracer.Event) error {
+	poll, ok := e.(*tracer.Poll)
+	if !ok {
+		return errWrongPoll
+	}
+	return c.consensus.RecordPoll(poll.Votes)
+}
+
+func (c *chain) preference() []ids.ID { return []ids.ID{c.consensus.Preference()} }
+
+// block returns the block [blkID]. A block that wasn't issued in the trace was
+// accepted before the trace started.
+func (c *chain) block(blkID ids.ID) *snowman.TestBlock {
+	blk, ok := c.blocks[blkID]
+	if !ok {
+		blk = &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+			IDV:     blkID,
+			StatusV: choices.Accepted,
+		}}
+		c.blocks[blkID] = blk
+	}
+	return blk
+}
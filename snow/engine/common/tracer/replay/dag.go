@@ -0,0 +1,117 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
+)
+
+var _ consensus = &dag{}
+
+// dag replays a trace of Avalanche consensus
+type dag struct {
+	consensus *avalanche.Topological
+	vertices  map[ids.ID]*avalanche.TestVertex
+	txs       map[ids.ID]*snowstorm.TestTx
+}
+
+func newDAG(ctx *snow.Context, params snowball.Parameters, init *tracer.Initialize) (*dag, error) {
+	d := &dag{
+		consensus: &avalanche.Topological{},
+		vertices:  make(map[ids.ID]*avalanche.TestVertex),
+		txs:       make(map[ids.ID]*snowstorm.TestTx),
+	}
+	frontier := make([]avalanche.Vertex, len(init.Accepted))
+	for i, vtxID := range init.Accepted {
+		frontier[i] = d.vertex(vtxID)
+	}
+	return d, d.consensus.Initialize(ctx, avalanche.Parameters{
+		Parameters: params,
+		Parents:    init.Parents,
+		BatchSize:  init.BatchSize,
+	}, frontier)
+}
+
+func (d *dag) issue(e *tracer.Issue) (choices.Decidable, error) {
+	vtx := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     e.ID,
+			StatusV: choices.Processing,
+		},
+		ParentsV: make([]avalanche.Vertex, len(e.Parents)),
+		HeightV:  e.Height,
+		TxsV:     make([]snowstorm.Tx, len(e.Txs)),
+	}
+	for i, parentID := range e.Parents {
+		vtx.ParentsV[i] = d.vertex(parentID)
+	}
+	for i, tracedTx := range e.Txs {
+		tx, ok := d.txs[tracedTx.ID]
+		if !ok {
+			tx = &snowstorm.TestTx{
+				TestDecidable: choices.TestDecidable{
+					IDV:     tracedTx.ID,
+					StatusV: choices.Processing,
+				},
+				DependenciesV: make([]snowstorm.Tx, len(tracedTx.Dependencies)),
+				InputIDsV:     tracedTx.InputIDs,
+			}
+			for j, depID := range tracedTx.Dependencies {
+				tx.DependenciesV[j] = d.tx(depID)
+			}
+			d.txs[tracedTx.ID] = tx
+		}
+		vtx.TxsV[i] = tx
+	}
+	d.vertices[e.ID] = vtx
+	return vtx, d.consensus.Add(vtx)
+}
+
+func (d *dag) recordPoll(e tracer.Event) error {
+	poll, ok := e.(*tracer.VertexPoll)
+	if !ok {
+		return errWrongPoll
+	}
+	return d.consensus.RecordPoll(poll.Votes)
+}
+
+func (d *dag) preference() []ids.ID {
+	prefs := d.consensus.Preferences().List()
+	ids.SortIDs(prefs)
+	return prefs
+}
+
+// vertex returns the vertex [vtxID]. A vertex that wasn't issued in the trace
+// was accepted before the trace started.
+func (d *dag) vertex(vtxID ids.ID) *avalanche.TestVertex {
+	vtx, ok := d.vertices[vtxID]
+	if !ok {
+		vtx = &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+			IDV:     vtxID,
+			StatusV: choices.Accepted,
+		}}
+		d.vertices[vtxID] = vtx
+	}
+	return vtx
+}
+
+// tx returns the transaction [txID]. A transaction that wasn't issued in the
+// trace was accepted before the trace started.
+func (d *dag) tx(txID ids.ID) *snowstorm.TestTx {
+	tx, ok := d.txs[txID]
+	if !ok {
+		tx = &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+			IDV:     txID,
+			StatusV: choices.Accepted,
+		}}
+		d.txs[txID] = tx
+	}
+	return tx
+}
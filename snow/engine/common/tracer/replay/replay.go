@@ -0,0 +1,212 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package replay feeds a consensus trace back into a Snowman or Avalanche
+// consensus instance, to show how the preference of consensus evolved up to
+// each decision.
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
+)
+
+var (
+	errNotInitialized = errors.New("trace doesn't start with an Initialize event")
+	errReinitialized  = errors.New("trace has more than one Initialize event")
+	errWrongPoll      = errors.New("poll doesn't match the type of consensus")
+
+	_ tracer.Tracer = &replayer{}
+)
+
+// Decision is a block, or a vertex, that consensus decided during the replay
+type Decision struct {
+	ID       ids.ID
+	Accepted bool
+	// The number of polls recorded before the container was issued, and
+	// before it was decided, including the poll that decided it
+	IssuedAt, DecidedAt int
+}
+
+// Replay is the outcome of replaying a trace
+type Replay struct {
+	// DAG is true if the trace is of Avalanche, rather than Snowman, consensus
+	DAG bool
+	// The number of chits and of failed queries in the trace
+	Chits, QueryFailures int
+	// Preferences[i] is the preference of consensus after the (i+1)th poll:
+	// the preferred block, or the sorted preferred vertices
+	Preferences [][]ids.ID
+	// Decisions of the replay, in the order they were made
+	Decisions []Decision
+	// Mismatches are the decisions of the replay that differ from the
+	// decisions in the trace
+	Mismatches []string
+	// Truncated is true if the trace ends in the middle of a record
+	Truncated bool
+}
+
+// Path returns the preferences of consensus from the issuance of [d] until it
+// was decided
+func (r *Replay) Path(d Decision) [][]ids.ID { return r.Preferences[d.IssuedAt:d.DecidedAt] }
+
+// consensus is a consensus instance that a trace is replayed into
+type consensus interface {
+	// issue adds the container of [e] to consensus
+	issue(e *tracer.Issue) (choices.Decidable, error)
+	// recordPoll records [e], a Poll or a VertexPoll, in consensus
+	recordPoll(e tracer.Event) error
+	// preference returns the current preference of consensus
+	preference() []ids.ID
+}
+
+// replayer replays a trace. It's the Tracer of the decisions of the replay.
+type replayer struct {
+	replay    *Replay
+	consensus consensus
+	decisions *tracer.Decisions
+	issuedAt  map[ids.ID]int
+}
+
+// Run replays the trace read by [r]
+func Run(r *tracer.Reader) (*Replay, error) {
+	record, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the start of the trace: %w", err)
+	}
+	init, ok := record.Event.(*tracer.Initialize)
+	if !ok {
+		return nil, fmt.Errorf("%w: got %s", errNotInitialized, record.Event.Type())
+	}
+
+	ctx := snow.DefaultContextTest()
+	params := init.Params
+	params.Namespace = ""
+	params.Metrics = prometheus.NewRegistry()
+	rp := &replayer{
+		replay:   &Replay{DAG: init.DAG},
+		issuedAt: make(map[ids.ID]int),
+	}
+	if init.DAG {
+		rp.consensus, err = newDAG(ctx, params, init)
+	} else {
+		rp.consensus, err = newChain(ctx, params, init)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't initialize consensus: %w", err)
+	}
+	rp.decisions = tracer.NewDecisions(rp)
+
+	// The decisions in the trace
+	traced := make(map[ids.ID]bool)
+	tracedOrder := []ids.ID(nil)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			rp.replay.Truncated = true
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch e := record.Event.(type) {
+		case *tracer.Initialize:
+			return nil, errReinitialized
+		case *tracer.Issue:
+			if _, ok := rp.issuedAt[e.ID]; ok {
+				continue
+			}
+			container, err := rp.consensus.issue(e)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't issue %s: %w", e.ID, err)
+			}
+			rp.issuedAt[e.ID] = len(rp.replay.Preferences)
+			rp.decisions.Issued(container)
+			rp.decisions.Trace()
+		case *tracer.Chits:
+			rp.replay.Chits++
+		case *tracer.QueryFailed:
+			rp.replay.QueryFailures++
+		case *tracer.Poll, *tracer.VertexPoll:
+			if err := rp.consensus.recordPoll(e); err != nil {
+				return nil, fmt.Errorf("couldn't record poll %d: %w", len(rp.replay.Preferences), err)
+			}
+			rp.replay.Preferences = append(rp.replay.Preferences, rp.consensus.preference())
+			rp.decisions.Trace()
+		case *tracer.Accept:
+			traced[e.ID] = true
+			tracedOrder = append(tracedOrder, e.ID)
+		case *tracer.Reject:
+			traced[e.ID] = false
+			tracedOrder = append(tracedOrder, e.ID)
+		}
+	}
+
+	rp.replay.Mismatches = mismatches(rp.replay.Decisions, traced, tracedOrder)
+	return rp.replay, nil
+}
+
+// Trace implements the Tracer interface. It records the decisions of the
+// replay.
+func (rp *replayer) Trace(e tracer.Event) {
+	d := Decision{DecidedAt: len(rp.replay.Preferences)}
+	switch e := e.(type) {
+	case *tracer.Accept:
+		d.ID = e.ID
+		d.Accepted = true
+	case *tracer.Reject:
+		d.ID = e.ID
+	default:
+		return
+	}
+	d.IssuedAt = rp.issuedAt[d.ID]
+	rp.replay.Decisions = append(rp.replay.Decisions, d)
+}
+
+// Close implements the Tracer interface
+func (rp *replayer) Close() error { return nil }
+
+// mismatches returns the differences between [decisions], the decisions of the
+// replay, and [traced], the decisions in the trace
+func mismatches(decisions []Decision, traced map[ids.ID]bool, tracedOrder []ids.ID) []string {
+	result := []string(nil)
+	replayed := make(map[ids.ID]bool, len(decisions))
+	for _, d := range decisions {
+		replayed[d.ID] = d.Accepted
+		accepted, ok := traced[d.ID]
+		switch {
+		case !ok:
+			result = append(result, fmt.Sprintf("%s was %s in the replay but not decided in the trace",
+				d.ID, decisionString(d.Accepted)))
+		case accepted != d.Accepted:
+			result = append(result, fmt.Sprintf("%s was %s in the replay but %s in the trace",
+				d.ID, decisionString(d.Accepted), decisionString(accepted)))
+		}
+	}
+	for _, id := range tracedOrder {
+		if _, ok := replayed[id]; !ok {
+			result = append(result, fmt.Sprintf("%s was %s in the trace but not decided in the replay",
+				id, decisionString(traced[id])))
+		}
+	}
+	return result
+}
+
+func decisionString(accepted bool) string {
+	if accepted {
+		return "accepted"
+	}
+	return "rejected"
+}
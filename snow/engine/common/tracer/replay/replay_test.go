@@ -0,0 +1,198 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
+)
+
+var params = snowball.Parameters{
+	K:                 1,
+	Alpha:             1,
+	BetaVirtuous:      1,
+	BetaRogue:         2,
+	ConcurrentRepolls: 1,
+	OptimalProcessing: 1,
+}
+
+// run replays a trace of [events]
+func run(t *testing.T, events ...tracer.Event) *Replay {
+	buf := &bytes.Buffer{}
+	w, err := tracer.NewWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range events {
+		w.Trace(e)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := tracer.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, err := Run(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return replay
+}
+
+func poll(votes ...ids.ID) *tracer.Poll {
+	p := &tracer.Poll{}
+	p.Votes.Add(votes...)
+	return p
+}
+
+func TestReplayChain(t *testing.T) {
+	genesisID, blkID0, blkID1 := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+
+	// blk0 and blk1 conflict. The first poll prefers blk1, which stays
+	// preferred until blk0 has more votes, and blk0 is accepted once enough
+	// consecutive polls voted for it.
+	replay := run(t,
+		&tracer.Initialize{
+			Params:   params,
+			Accepted: []ids.ID{genesisID},
+		},
+		&tracer.Issue{ID: blkID0, Parents: []ids.ID{genesisID}, Height: 1},
+		&tracer.Issue{ID: blkID1, Parents: []ids.ID{genesisID}, Height: 1},
+		&tracer.Chits{NodeID: ids.GenerateTestShortID(), RequestID: 1, Votes: []ids.ID{blkID1}},
+		poll(blkID1),
+		&tracer.QueryFailed{NodeID: ids.GenerateTestShortID(), RequestID: 2},
+		poll(blkID0),
+		poll(blkID0),
+		&tracer.Accept{ID: blkID0},
+		&tracer.Reject{ID: blkID1},
+	)
+
+	if replay.DAG {
+		t.Fatal("replayed a chain as a DAG")
+	}
+	if replay.Chits != 1 || replay.QueryFailures != 1 {
+		t.Fatalf("expected 1 chit and 1 failed query but got %d and %d", replay.Chits, replay.QueryFailures)
+	}
+	expectedPrefs := [][]ids.ID{{blkID1}, {blkID1}, {blkID0}}
+	if !reflect.DeepEqual(replay.Preferences, expectedPrefs) {
+		t.Fatalf("expected preferences %v but got %v", expectedPrefs, replay.Preferences)
+	}
+	expectedDecisions := []Decision{
+		{ID: blkID0, Accepted: true, IssuedAt: 0, DecidedAt: 3},
+		{ID: blkID1, Accepted: false, IssuedAt: 0, DecidedAt: 3},
+	}
+	if !reflect.DeepEqual(replay.Decisions, expectedDecisions) {
+		t.Fatalf("expected decisions %v but got %v", expectedDecisions, replay.Decisions)
+	}
+	if path := replay.Path(replay.Decisions[0]); !reflect.DeepEqual(path, expectedPrefs) {
+		t.Fatalf("expected the path %v but got %v", expectedPrefs, path)
+	}
+	if len(replay.Mismatches) != 0 {
+		t.Fatalf("unexpected mismatches: %v", replay.Mismatches)
+	}
+}
+
+func TestReplayChainMismatch(t *testing.T) {
+	genesisID, blkID0, blkID1 := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+
+	replay := run(t,
+		&tracer.Initialize{
+			Params:   params,
+			Accepted: []ids.ID{genesisID},
+		},
+		&tracer.Issue{ID: blkID0, Parents: []ids.ID{genesisID}, Height: 1},
+		&tracer.Issue{ID: blkID1, Parents: []ids.ID{blkID0}, Height: 2},
+		poll(blkID0),
+		&tracer.Reject{ID: blkID0},
+		&tracer.Accept{ID: blkID1},
+	)
+
+	expected := []string{
+		blkID0.String() + " was accepted in the replay but rejected in the trace",
+		blkID1.String() + " was accepted in the trace but not decided in the replay",
+	}
+	if !reflect.DeepEqual(replay.Mismatches, expected) {
+		t.Fatalf("expected mismatches %v but got %v", expected, replay.Mismatches)
+	}
+}
+
+func TestReplayDAG(t *testing.T) {
+	genesisID, vtxID0, vtxID1 := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	txID0, txID1, inputID := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+
+	// The transactions of vtx0 and vtx1 conflict
+	votes := ids.UniqueBag{}
+	votes.Add(0, vtxID0)
+	replay := run(t,
+		&tracer.Initialize{
+			DAG:       true,
+			Params:    params,
+			Parents:   2,
+			BatchSize: 1,
+			Accepted:  []ids.ID{genesisID},
+		},
+		&tracer.Issue{
+			ID:      vtxID0,
+			Parents: []ids.ID{genesisID},
+			Height:  1,
+			Txs:     []tracer.Tx{{ID: txID0, InputIDs: []ids.ID{inputID}}},
+		},
+		&tracer.Issue{
+			ID:      vtxID1,
+			Parents: []ids.ID{genesisID},
+			Height:  1,
+			Txs:     []tracer.Tx{{ID: txID1, InputIDs: []ids.ID{inputID}}},
+		},
+		&tracer.VertexPoll{Votes: votes},
+		&tracer.VertexPoll{Votes: votes},
+		&tracer.Accept{ID: vtxID0},
+		&tracer.Reject{ID: vtxID1},
+	)
+
+	if !replay.DAG {
+		t.Fatal("replayed a DAG as a chain")
+	}
+	expectedPrefs := [][]ids.ID{{vtxID0}, {vtxID0}}
+	if !reflect.DeepEqual(replay.Preferences, expectedPrefs) {
+		t.Fatalf("expected preferences %v but got %v", expectedPrefs, replay.Preferences)
+	}
+	expectedDecisions := []Decision{
+		{ID: vtxID0, Accepted: true, IssuedAt: 0, DecidedAt: 2},
+		{ID: vtxID1, Accepted: false, IssuedAt: 0, DecidedAt: 2},
+	}
+	if !reflect.DeepEqual(replay.Decisions, expectedDecisions) {
+		t.Fatalf("expected decisions %v but got %v", expectedDecisions, replay.Decisions)
+	}
+	if len(replay.Mismatches) != 0 {
+		t.Fatalf("unexpected mismatches: %v", replay.Mismatches)
+	}
+}
+
+func TestReplayNotInitialized(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := tracer.NewWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Trace(&tracer.Accept{ID: ids.GenerateTestID()})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := tracer.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(r); !errors.Is(err, errNotInitialized) {
+		t.Fatalf("expected %s but got %v", errNotInitialized, err)
+	}
+}
@@ -0,0 +1,242 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package tracer records the events of a consensus engine, such as the chits
+// it receives and the outcome of its polls, to a compact binary log so that
+// the consensus of a chain can be replayed offline.
+//
+// A trace starts with [magic] and a version byte. Each record that follows is
+// the length of the rest of the record, the type of the event, the time it
+// occurred at, in Unix nanoseconds, and the fields of the event.
+package tracer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	version = 0
+
+	// The largest record that is written or read
+	maxRecordSize = 16 * 1024 * 1024
+
+	// How often the buffered records are flushed while tracing
+	flushFrequency = time.Second
+)
+
+var (
+	// magic starts every trace
+	magic = []byte("avatrace")
+
+	errUnknownEventType = errors.New("unknown event type")
+	errTooManyElements  = errors.New("length is larger than the rest of the record")
+	errNotATrace        = errors.New("not a consensus trace")
+	errUnknownVersion   = errors.New("unknown trace version")
+	errRecordTooLarge   = errors.New("record is too large")
+
+	_ Tracer = Noop{}
+	_ Tracer = &Writer{}
+)
+
+// Tracer records the events of a consensus engine
+type Tracer interface {
+	// Trace records [e]
+	Trace(e Event)
+
+	// Close stops tracing. Returns the first error that occurred while
+	// tracing, if any.
+	Close() error
+}
+
+// Noop is a Tracer that drops every event
+type Noop struct{}
+
+// Trace implements the Tracer interface
+func (Noop) Trace(Event) {}
+
+// Close implements the Tracer interface
+func (Noop) Close() error { return nil }
+
+// Writer is a Tracer that writes a trace. The records are buffered and flushed
+// every [flushFrequency], so that the trace of a stalled chain can be read
+// while the node is running.
+type Writer struct {
+	clock  timer.Clock
+	lock   sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+	// The first error that occurred. Once it's set, events are dropped.
+	err error
+
+	// Closed when the Writer is closed
+	closing chan struct{}
+	// Closed once the buffered records are no longer flushed periodically
+	flusherDone chan struct{}
+}
+
+// NewWriter returns a Writer that writes a trace to [w]. If [w] is an
+// io.Closer, closing the Writer closes [w].
+func NewWriter(w io.Writer) (*Writer, error) {
+	return newWriter(w, flushFrequency)
+}
+
+func newWriter(w io.Writer, frequency time.Duration) (*Writer, error) {
+	tw := &Writer{
+		w:           bufio.NewWriter(w),
+		closing:     make(chan struct{}),
+		flusherDone: make(chan struct{}),
+	}
+	if closer, ok := w.(io.Closer); ok {
+		tw.closer = closer
+	}
+	if _, err := tw.w.Write(magic); err != nil {
+		return nil, err
+	}
+	if err := tw.w.WriteByte(version); err != nil {
+		return nil, err
+	}
+	go tw.flushPeriodically(frequency)
+	return tw, nil
+}
+
+// Trace implements the Tracer interface
+func (w *Writer) Trace(e Event) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.err != nil {
+		return
+	}
+
+	now := w.clock.Time()
+	p := wrappers.Packer{
+		MaxSize: maxRecordSize,
+		Bytes:   make([]byte, wrappers.IntLen, 64),
+		Offset:  wrappers.IntLen, // The length is set once the record is packed
+	}
+	p.PackByte(byte(e.Type()))
+	p.PackLong(uint64(now.UnixNano()))
+	e.pack(&p)
+	if p.Errored() {
+		w.err = fmt.Errorf("couldn't pack %s event: %w", e.Type(), p.Err)
+		return
+	}
+	binary.BigEndian.PutUint32(p.Bytes, uint32(p.Offset-wrappers.IntLen))
+	if _, err := w.w.Write(p.Bytes[:p.Offset]); err != nil {
+		w.err = err
+	}
+}
+
+// flushPeriodically flushes the buffered records every [frequency] until the
+// Writer is closed
+func (w *Writer) flushPeriodically(frequency time.Duration) {
+	defer close(w.flusherDone)
+
+	ticker := time.NewTicker(frequency)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.lock.Lock()
+			if w.err == nil {
+				w.err = w.w.Flush()
+			}
+			w.lock.Unlock()
+		case <-w.closing:
+			return
+		}
+	}
+}
+
+// Close implements the Tracer interface
+func (w *Writer) Close() error {
+	close(w.closing)
+	<-w.flusherDone
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	errs := wrappers.Errs{}
+	errs.Add(w.err, w.w.Flush())
+	if w.closer != nil {
+		errs.Add(w.closer.Close())
+	}
+	return errs.Err
+}
+
+// Record is an event read from a trace
+type Record struct {
+	Time  time.Time
+	Event Event
+}
+
+// Reader reads a trace
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns a Reader of the trace in [r]
+func NewReader(r io.Reader) (*Reader, error) {
+	tr := &Reader{r: bufio.NewReader(r)}
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(tr.r, header); err != nil {
+		return nil, fmt.Errorf("%w: %s", errNotATrace, err)
+	}
+	if !bytes.Equal(header[:len(magic)], magic) {
+		return nil, errNotATrace
+	}
+	if v := header[len(magic)]; v != version {
+		return nil, fmt.Errorf("%w: %d", errUnknownVersion, v)
+	}
+	return tr, nil
+}
+
+// Read returns the next record of the trace. Returns io.EOF once every record
+// was read, or io.ErrUnexpectedEOF if the trace ends in the middle of a
+// record, as it does if the node was stopped before the trace was closed.
+func (r *Reader) Read() (Record, error) {
+	lenBytes := make([]byte, wrappers.IntLen)
+	if _, err := io.ReadFull(r.r, lenBytes); err != nil {
+		return Record{}, err
+	}
+	recordLen := binary.BigEndian.Uint32(lenBytes)
+	if recordLen > maxRecordSize {
+		return Record{}, fmt.Errorf("%w: %d bytes", errRecordTooLarge, recordLen)
+	}
+	recordBytes := make([]byte, recordLen)
+	if _, err := io.ReadFull(r.r, recordBytes); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Record{}, err
+	}
+
+	p := wrappers.Packer{Bytes: recordBytes}
+	eventType := EventType(p.UnpackByte())
+	timestamp := int64(p.UnpackLong())
+	if p.Errored() {
+		return Record{}, p.Err
+	}
+	e, err := newEvent(eventType)
+	if err != nil {
+		return Record{}, err
+	}
+	e.unpack(&p)
+	if p.Errored() {
+		return Record{}, fmt.Errorf("couldn't unpack %s event: %w", eventType, p.Err)
+	}
+	return Record{
+		Time:  time.Unix(0, timestamp),
+		Event: e,
+	}, nil
+}
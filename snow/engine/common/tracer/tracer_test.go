@@ -0,0 +1,197 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+func testEvents() []Event {
+	blkID0, blkID1, txID := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	nodeID := ids.GenerateTestShortID()
+
+	poll := &Poll{}
+	poll.Votes.AddCount(blkID0, 3)
+	poll.Votes.AddCount(blkID1, 2)
+
+	vertexPoll := &VertexPoll{Votes: ids.UniqueBag{}}
+	vertexPoll.Votes.Add(0, blkID0)
+	vertexPoll.Votes.Add(3, blkID0, blkID1)
+
+	return []Event{
+		&Initialize{
+			DAG: true,
+			Params: snowball.Parameters{
+				K:                 20,
+				Alpha:             15,
+				BetaVirtuous:      15,
+				BetaRogue:         20,
+				ConcurrentRepolls: 4,
+				OptimalProcessing: 50,
+			},
+			Parents:   5,
+			BatchSize: 30,
+			Accepted:  []ids.ID{blkID0},
+		},
+		&Issue{
+			ID:      blkID1,
+			Parents: []ids.ID{blkID0},
+			Height:  1,
+			Txs: []Tx{{
+				ID:           txID,
+				Dependencies: []ids.ID{},
+				InputIDs:     []ids.ID{ids.GenerateTestID()},
+			}},
+		},
+		&Chits{
+			NodeID:    nodeID,
+			RequestID: 7,
+			Votes:     []ids.ID{blkID1},
+		},
+		&QueryFailed{
+			NodeID:    nodeID,
+			RequestID: 8,
+		},
+		poll,
+		vertexPoll,
+		&Accept{ID: blkID1},
+		&Reject{ID: blkID0},
+	}
+}
+
+func TestTraceRoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1607000000, 0)
+	w.clock.Set(start)
+
+	events := testEvents()
+	for i, e := range events {
+		w.clock.Set(start.Add(time.Duration(i) * time.Millisecond))
+		w.Trace(e)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range events {
+		record, err := r.Read()
+		if err != nil {
+			t.Fatalf("couldn't read event %d: %s", i, err)
+		}
+		if expectedTime := start.Add(time.Duration(i) * time.Millisecond); !record.Time.Equal(expectedTime) {
+			t.Fatalf("expected event %d at %s but got %s", i, expectedTime, record.Time)
+		}
+		if !reflect.DeepEqual(record.Event, expected) {
+			t.Fatalf("expected event %d to be %+v but got %+v", i, expected, record.Event)
+		}
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("expected EOF but got %v", err)
+	}
+}
+
+func TestTraceTruncated(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Trace(&Accept{ID: ids.GenerateTestID()})
+	w.Trace(&Accept{ID: ids.GenerateTestID()})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop the end of the last record, as if the node stopped while writing it
+	r, err := NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected %s but got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer that's safe for concurrent use
+type lockedBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestTraceFlushedWithoutLaterEvent(t *testing.T) {
+	buf := &lockedBuffer{}
+	w, err := newWriter(buf, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	expected := &Accept{ID: ids.GenerateTestID()}
+	w.Trace(expected)
+
+	// The event should become readable while the Writer is open, even though
+	// no event is traced after it
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		r, err := NewReader(bytes.NewReader(buf.Bytes()))
+		if err == nil {
+			record, err := r.Read()
+			if err == nil {
+				if !reflect.DeepEqual(record.Event, expected) {
+					t.Fatalf("expected event %+v but got %+v", expected, record.Event)
+				}
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the traced event was never flushed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTraceNotATrace(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("not a trace"))); !errors.Is(err, errNotATrace) {
+		t.Fatalf("expected %s but got %v", errNotATrace, err)
+	}
+	if _, err := NewReader(bytes.NewReader(nil)); !errors.Is(err, errNotATrace) {
+		t.Fatalf("expected %s but got %v", errNotATrace, err)
+	}
+}
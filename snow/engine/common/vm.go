@@ -19,6 +19,9 @@ type VM interface {
 	//     [ctx.Lock]: A Read/Write lock shared by this VM and the consensus
 	//                 engine that manages this VM. The write lock is held
 	//                 whenever code in the consensus engine calls the VM.
+	//     [ctx.AppSender]: Used to send application-level messages to other
+	//                      nodes. Messages from other nodes are delivered to
+	//                      the VM if it implements AppHandler.
 	// [db]: The database this VM will persist data to.
 	// [genesisBytes]: The byte-encoding of the genesis information of this
 	//                 VM. The VM uses it to initialize its state. For
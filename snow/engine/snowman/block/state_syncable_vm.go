@@ -0,0 +1,70 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ErrNoStateSummary is returned by a StateSyncableVM that hasn't built a
+// summary of its state yet
+var ErrNoStateSummary = errors.New("no state summary available")
+
+// StateSyncableVM defines a Snowman VM whose state can be synced from a
+// summary, rather than by executing every block since genesis.
+//
+// A summary describes the VM's state after an accepted block. The state itself
+// is split into chunks, which are transferred separately and verified against
+// the summary.
+type StateSyncableVM interface {
+	ChainVM
+
+	// StateSummary returns the most recent summary this VM has built of its
+	// state.
+	//
+	// If the VM hasn't built a summary yet, ErrNoStateSummary should be
+	// returned.
+	StateSummary() (StateSummary, error)
+
+	// ParseStateSummary attempts to create a summary from a stream of bytes.
+	ParseStateSummary([]byte) (StateSummary, error)
+
+	// StateChunk returns chunk [index] of the state described by the summary
+	// with ID [summaryID].
+	//
+	// If the VM no longer has the summary, an error should be returned.
+	StateChunk(summaryID ids.ID, index uint32) ([]byte, error)
+
+	// SyncState replaces the VM's state with the state described by
+	// [summary], whose chunks are [chunks]. Each chunk has been verified
+	// against [summary].
+	//
+	// This is only called while bootstrapping, before any block has been
+	// accepted after genesis. Once it returns, the block the summary was taken
+	// at should be the last accepted block.
+	SyncState(summary StateSummary, chunks [][]byte) error
+}
+
+// StateSummary describes the state of a StateSyncableVM after an accepted
+// block
+type StateSummary interface {
+	// ID returns a unique ID of this summary.
+	ID() ids.ID
+
+	// Height returns the height of the block this summary was taken at.
+	Height() uint64
+
+	// Bytes returns the binary representation of this summary.
+	Bytes() []byte
+
+	// NumChunks returns the number of chunks the state is split into. Chunks
+	// are never empty.
+	NumChunks() uint32
+
+	// VerifyChunk returns nil iff [chunk] is chunk [index] of the state this
+	// summary describes.
+	VerifyChunk(index uint32, chunk []byte) error
+}
@@ -0,0 +1,86 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package block
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	errStateSummary      = errors.New("unexpectedly called StateSummary")
+	errParseStateSummary = errors.New("unexpectedly called ParseStateSummary")
+	errStateChunk        = errors.New("unexpectedly called StateChunk")
+	errSyncState         = errors.New("unexpectedly called SyncState")
+)
+
+// TestStateSyncableVM ...
+type TestStateSyncableVM struct {
+	TestVM
+
+	CantStateSummary,
+	CantParseStateSummary,
+	CantStateChunk,
+	CantSyncState bool
+
+	StateSummaryF      func() (StateSummary, error)
+	ParseStateSummaryF func([]byte) (StateSummary, error)
+	StateChunkF        func(ids.ID, uint32) ([]byte, error)
+	SyncStateF         func(StateSummary, [][]byte) error
+}
+
+// Default ...
+func (vm *TestStateSyncableVM) Default(cant bool) {
+	vm.TestVM.Default(cant)
+
+	vm.CantStateSummary = cant
+	vm.CantParseStateSummary = cant
+	vm.CantStateChunk = cant
+	vm.CantSyncState = cant
+}
+
+// StateSummary ...
+func (vm *TestStateSyncableVM) StateSummary() (StateSummary, error) {
+	if vm.StateSummaryF != nil {
+		return vm.StateSummaryF()
+	}
+	if vm.CantStateSummary && vm.T != nil {
+		vm.T.Fatal(errStateSummary)
+	}
+	return nil, errStateSummary
+}
+
+// ParseStateSummary ...
+func (vm *TestStateSyncableVM) ParseStateSummary(b []byte) (StateSummary, error) {
+	if vm.ParseStateSummaryF != nil {
+		return vm.ParseStateSummaryF(b)
+	}
+	if vm.CantParseStateSummary && vm.T != nil {
+		vm.T.Fatal(errParseStateSummary)
+	}
+	return nil, errParseStateSummary
+}
+
+// StateChunk ...
+func (vm *TestStateSyncableVM) StateChunk(summaryID ids.ID, index uint32) ([]byte, error) {
+	if vm.StateChunkF != nil {
+		return vm.StateChunkF(summaryID, index)
+	}
+	if vm.CantStateChunk && vm.T != nil {
+		vm.T.Fatal(errStateChunk)
+	}
+	return nil, errStateChunk
+}
+
+// SyncState ...
+func (vm *TestStateSyncableVM) SyncState(summary StateSummary, chunks [][]byte) error {
+	if vm.SyncStateF != nil {
+		return vm.SyncStateF(summary, chunks)
+	}
+	if vm.CantSyncState && vm.T != nil {
+		vm.T.Fatal(errSyncState)
+	}
+	return errSyncState
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -27,6 +28,11 @@ type Config struct {
 
 	VM block.ChainVM
 
+	// StateSync is true if a VM that implements block.StateSyncableVM should
+	// sync its state from a summary, rather than from genesis, when it has no
+	// accepted blocks
+	StateSync bool
+
 	Bootstrapped func()
 }
 
@@ -43,6 +49,8 @@ type Bootstrapper struct {
 
 	Bootstrapped func()
 
+	stateSyncer
+
 	// true if all of the vertices in the original accepted frontier have been processed
 	processedStartingAcceptedFrontier bool
 }
@@ -57,6 +65,7 @@ func (b *Bootstrapper) Initialize(
 	b.Blocked = config.Blocked
 	b.VM = config.VM
 	b.Bootstrapped = config.Bootstrapped
+	b.stateSync = config.StateSync
 	b.OnFinished = onFinished
 
 	if err := b.metrics.Initialize(namespace, registerer); err != nil {
@@ -98,6 +107,19 @@ func (b *Bootstrapper) ForceAccepted(acceptedContainerIDs []ids.ID) error {
 			err)
 	}
 
+	shouldSync, err := b.shouldSyncState()
+	if err != nil {
+		return err
+	}
+	if shouldSync {
+		return b.startStateSync(acceptedContainerIDs)
+	}
+	return b.fetchAccepted(acceptedContainerIDs)
+}
+
+// fetchAccepted fetches and processes the blocks in [acceptedContainerIDs] and
+// their ancestors
+func (b *Bootstrapper) fetchAccepted(acceptedContainerIDs []ids.ID) error {
 	for _, blkID := range acceptedContainerIDs {
 		if blk, err := b.VM.GetBlock(blkID); err == nil {
 			if err := b.process(blk); err != nil {
@@ -148,6 +170,7 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte
 	if lenBlks := len(blks); lenBlks > common.MaxContainersPerMultiPut {
 		b.Ctx.Log.Debug("MultiPut(%s, %d) contains more than maximum number of blocks",
 			vdr, requestID)
+		b.Ctx.ReportMisbehavior(vdr, snow.InvalidMessage)
 		return b.GetAncestorsFailed(vdr, requestID)
 	} else if lenBlks == 0 {
 		b.Ctx.Log.Debug("MultiPut(%s, %d) contains no blocks", vdr, requestID)
@@ -159,6 +182,9 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte
 	if !ok { // this message isn't in response to a request we made
 		b.Ctx.Log.Debug("received unexpected MultiPut from %s with ID %d",
 			vdr, requestID)
+		if !b.Requested(requestID) {
+			b.Ctx.ReportMisbehavior(vdr, snow.UnrequestedResponse)
+		}
 		return nil
 	}
 
@@ -169,6 +195,7 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte
 	} else if actualID := wantedBlk.ID(); actualID != wantedBlkID {
 		b.Ctx.Log.Debug("expected the first block to be the requested block, %s, but is %s",
 			wantedBlk, actualID)
+		b.Ctx.ReportMisbehavior(vdr, snow.InvalidContainer)
 		return b.fetch(wantedBlkID)
 	}
 
@@ -285,6 +312,46 @@ func (b *Bootstrapper) executeAll(jobs *queue.Jobs) error {
 	return nil
 }
 
+// AppRequest implements the Engine interface.
+func (b *Bootstrapper) AppRequest(nodeID ids.ShortID, requestID uint32, request []byte) error {
+	handler, ok := b.VM.(common.AppHandler)
+	if !ok {
+		b.Ctx.Log.Debug("dropping AppRequest(%s, %d) as the VM doesn't handle app messages", nodeID, requestID)
+		return nil
+	}
+	return handler.AppRequest(nodeID, requestID, request)
+}
+
+// AppResponse implements the Engine interface.
+func (b *Bootstrapper) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
+	handler, ok := b.VM.(common.AppHandler)
+	if !ok {
+		b.Ctx.Log.Debug("dropping AppResponse(%s, %d) as the VM doesn't handle app messages", nodeID, requestID)
+		return nil
+	}
+	return handler.AppResponse(nodeID, requestID, response)
+}
+
+// AppRequestFailed implements the Engine interface.
+func (b *Bootstrapper) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
+	handler, ok := b.VM.(common.AppHandler)
+	if !ok {
+		b.Ctx.Log.Debug("dropping AppRequestFailed(%s, %d) as the VM doesn't handle app messages", nodeID, requestID)
+		return nil
+	}
+	return handler.AppRequestFailed(nodeID, requestID)
+}
+
+// AppGossip implements the Engine interface.
+func (b *Bootstrapper) AppGossip(nodeID ids.ShortID, msg []byte) error {
+	handler, ok := b.VM.(common.AppHandler)
+	if !ok {
+		b.Ctx.Log.Verbo("dropping AppGossip(%s) as the VM doesn't handle app messages", nodeID)
+		return nil
+	}
+	return handler.AppGossip(nodeID, msg)
+}
+
 // Connected implements the Engine interface.
 func (b *Bootstrapper) Connected(validatorID ids.ShortID) error {
 	if connector, ok := b.VM.(validators.Connector); ok {
@@ -0,0 +1,378 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"fmt"
+	stdmath "math"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/math"
+)
+
+const (
+	// A summary is abandoned once a chunk of it fails to be fetched this many
+	// times
+	maxStateChunkFailures = 5
+
+	// Summaries are chosen at most this many times before bootstrapping
+	// continues from genesis instead
+	maxStateSyncAttempts = 3
+)
+
+// chunkRequest identifies an outstanding GetStateChunk request
+type chunkRequest struct {
+	validatorID ids.ShortID
+	requestID   uint32
+}
+
+// stateSyncer tracks the progress of syncing the VM's state from a summary
+type stateSyncer struct {
+	// true if the VM's state should be synced when possible
+	stateSync bool
+
+	// true while the state is being synced
+	syncing bool
+
+	// The accepted frontier to bootstrap from once the state is synced
+	syncFrontier []ids.ID
+
+	// The request ID of the GetStateSummary message sent to the beacons and the
+	// beacons that haven't responded to it
+	summaryRequestID uint32
+	pendingSummaries ids.ShortSet
+
+	// Summary ID --> summary, the weight of the beacons that sent it and the
+	// beacons that sent it
+	summaries      map[ids.ID]block.StateSummary
+	summaryWeights map[ids.ID]uint64
+	summaryVoters  map[ids.ID]ids.ShortSet
+
+	// The summary being synced and the chunks of it that have been received
+	summary block.StateSummary
+	chunks  [][]byte
+
+	// Indices of the chunks that haven't been requested yet
+	chunksToFetch []uint32
+	// Outstanding request --> index of the requested chunk
+	outstandingChunks map[chunkRequest]uint32
+	// Chunk index --> number of times fetching the chunk failed
+	chunkFailures map[uint32]int
+
+	// Number of summaries that have been chosen
+	stateSyncAttempts int
+
+	// The beacons that sent the summary being synced. Chunks are requested
+	// from them in turn.
+	syncPeers    []ids.ShortID
+	nextSyncPeer int
+}
+
+// GetStateSummary implements the Engine interface.
+func (b *Bootstrapper) GetStateSummary(vdr ids.ShortID, requestID uint32) error {
+	vm, ok := b.VM.(block.StateSyncableVM)
+	if !ok {
+		b.Sender.StateSummary(vdr, requestID, nil)
+		return nil
+	}
+
+	summary, err := vm.StateSummary()
+	if err != nil {
+		if err != block.ErrNoStateSummary {
+			b.Ctx.Log.Debug("couldn't get state summary for GetStateSummary(%s, %d): %s", vdr, requestID, err)
+		}
+		b.Sender.StateSummary(vdr, requestID, nil)
+		return nil
+	}
+	b.Sender.StateSummary(vdr, requestID, summary.Bytes())
+	return nil
+}
+
+// GetStateChunk implements the Engine interface.
+func (b *Bootstrapper) GetStateChunk(vdr ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) error {
+	// An empty chunk tells [vdr] that the chunk isn't available here, so that
+	// it can stop waiting for it
+	vm, ok := b.VM.(block.StateSyncableVM)
+	if !ok {
+		b.Sender.StateChunk(vdr, requestID, nil)
+		return nil
+	}
+
+	chunk, err := vm.StateChunk(summaryID, index)
+	if err != nil {
+		b.Ctx.Log.Debug("couldn't get state chunk for GetStateChunk(%s, %d, %s, %d): %s", vdr, requestID, summaryID, index, err)
+		b.Sender.StateChunk(vdr, requestID, nil)
+		return nil
+	}
+	b.Sender.StateChunk(vdr, requestID, chunk)
+	return nil
+}
+
+// shouldSyncState returns true if state sync is enabled, the VM supports it
+// and the VM hasn't accepted any blocks since genesis
+func (b *Bootstrapper) shouldSyncState() (bool, error) {
+	if !b.stateSync || b.Beacons.Len() == 0 {
+		return false, nil
+	}
+	if _, ok := b.VM.(block.StateSyncableVM); !ok {
+		return false, nil
+	}
+
+	lastAcceptedID := b.VM.LastAccepted()
+	lastAccepted, err := b.VM.GetBlock(lastAcceptedID)
+	if err != nil {
+		return false, fmt.Errorf("couldn't get last accepted block %s: %w", lastAcceptedID, err)
+	}
+	return lastAccepted.Height() == 0, nil
+}
+
+// startStateSync asks every beacon for its state summary. Once the state is
+// synced, the blocks in [acceptedContainerIDs] are fetched.
+func (b *Bootstrapper) startStateSync(acceptedContainerIDs []ids.ID) error {
+	b.syncing = true
+	b.syncFrontier = acceptedContainerIDs
+	b.summaries = make(map[ids.ID]block.StateSummary)
+	b.summaryWeights = make(map[ids.ID]uint64)
+	b.summaryVoters = make(map[ids.ID]ids.ShortSet)
+	b.outstandingChunks = make(map[chunkRequest]uint32)
+
+	b.pendingSummaries.Clear()
+	for _, vdr := range b.Beacons.List() {
+		b.pendingSummaries.Add(vdr.ID())
+	}
+
+	vdrs := ids.ShortSet{}
+	vdrs.Union(b.pendingSummaries)
+
+	b.RequestID++
+	b.summaryRequestID = b.RequestID
+	b.Ctx.Log.Info("requesting state summaries from %d beacons", vdrs.Len())
+	b.Sender.GetStateSummary(vdrs, b.RequestID)
+	return nil
+}
+
+// StateSummary implements the Engine interface.
+func (b *Bootstrapper) StateSummary(vdr ids.ShortID, requestID uint32, summaryBytes []byte) error {
+	if !b.syncing || requestID != b.summaryRequestID || !b.pendingSummaries.Contains(vdr) {
+		b.Ctx.Log.Debug("received an unexpected StateSummary message from %s with ID %d", vdr, requestID)
+		return nil
+	}
+	// Mark that we received a response from [vdr]
+	b.pendingSummaries.Remove(vdr)
+
+	if len(summaryBytes) > 0 {
+		b.addSummaryVote(vdr, summaryBytes)
+	}
+
+	if b.pendingSummaries.Len() != 0 {
+		return nil
+	}
+	return b.chooseSummary()
+}
+
+// GetStateSummaryFailed implements the Engine interface.
+func (b *Bootstrapper) GetStateSummaryFailed(vdr ids.ShortID, requestID uint32) error {
+	// If we can't get a response from [vdr], act as though they don't have a
+	// summary
+	return b.StateSummary(vdr, requestID, nil)
+}
+
+// addSummaryVote adds the weight of [vdr] to the summary it sent
+func (b *Bootstrapper) addSummaryVote(vdr ids.ShortID, summaryBytes []byte) {
+	vm := b.VM.(block.StateSyncableVM)
+	summary, err := vm.ParseStateSummary(summaryBytes)
+	if err != nil {
+		b.Ctx.Log.Debug("failed to parse state summary from %s: %s", vdr, err)
+		return
+	}
+
+	summaryID := summary.ID()
+	weight, _ := b.Beacons.GetWeight(vdr)
+	newWeight, err := math.Add64(weight, b.summaryWeights[summaryID])
+	if err != nil {
+		newWeight = stdmath.MaxUint64
+	}
+
+	voters := b.summaryVoters[summaryID]
+	voters.Add(vdr)
+
+	b.summaries[summaryID] = summary
+	b.summaryWeights[summaryID] = newWeight
+	b.summaryVoters[summaryID] = voters
+}
+
+// chooseSummary starts fetching the chunks of the summary sent by a majority
+// of the beacons, by weight. If there isn't one, bootstrapping continues from
+// genesis.
+func (b *Bootstrapper) chooseSummary() error {
+	totalWeight := b.Beacons.Weight()
+	for summaryID, weight := range b.summaryWeights {
+		if weight <= totalWeight/2 {
+			continue
+		}
+
+		summary := b.summaries[summaryID]
+		if summary.Height() == 0 {
+			break
+		}
+
+		numChunks := summary.NumChunks()
+		b.stateSyncAttempts++
+		b.summary = summary
+		b.chunkFailures = make(map[uint32]int)
+		b.chunks = make([][]byte, numChunks)
+		b.chunksToFetch = make([]uint32, numChunks)
+		for i := range b.chunksToFetch {
+			b.chunksToFetch[i] = uint32(i)
+		}
+		b.syncPeers = b.summaryVoters[summaryID].List()
+
+		b.Ctx.Log.Info("syncing state summary %s at height %d with %d chunks",
+			summaryID, summary.Height(), numChunks)
+		return b.fetchChunks()
+	}
+
+	b.Ctx.Log.Info("state sync skipped as no state summary was sent by a majority of the beacons")
+	b.syncing = false
+	return b.fetchAccepted(b.syncFrontier)
+}
+
+// fetchChunks requests chunks of the summary being synced until there are
+// MaxOutstandingRequests outstanding requests. Once every chunk has been
+// received, the state is synced.
+func (b *Bootstrapper) fetchChunks() error {
+	for len(b.outstandingChunks) < common.MaxOutstandingRequests && len(b.chunksToFetch) > 0 {
+		index := b.chunksToFetch[0]
+		b.chunksToFetch = b.chunksToFetch[1:]
+
+		vdr := b.syncPeers[b.nextSyncPeer%len(b.syncPeers)]
+		b.nextSyncPeer++
+
+		b.RequestID++
+		b.outstandingChunks[chunkRequest{validatorID: vdr, requestID: b.RequestID}] = index
+		b.Sender.GetStateChunk(vdr, b.RequestID, b.summary.ID(), index)
+	}
+
+	if len(b.outstandingChunks) > 0 || len(b.chunksToFetch) > 0 {
+		return nil
+	}
+	return b.finishStateSync()
+}
+
+// StateChunk implements the Engine interface.
+func (b *Bootstrapper) StateChunk(vdr ids.ShortID, requestID uint32, chunk []byte) error {
+	request := chunkRequest{validatorID: vdr, requestID: requestID}
+	index, ok := b.outstandingChunks[request]
+	if !b.syncing || !ok {
+		b.Ctx.Log.Debug("received an unexpected StateChunk message from %s with ID %d", vdr, requestID)
+		return nil
+	}
+	delete(b.outstandingChunks, request)
+
+	if len(chunk) == 0 {
+		// [vdr] no longer has the summary, so no more chunks are requested
+		// from it
+		b.Ctx.Log.Debug("%s no longer serves state summary %s", vdr, b.summary.ID())
+		b.removeSyncPeer(vdr)
+		if len(b.syncPeers) == 0 {
+			return b.abandonSummary("no beacon serves it anymore")
+		}
+		b.chunksToFetch = append(b.chunksToFetch, index)
+		return b.fetchChunks()
+	}
+	if err := b.summary.VerifyChunk(index, chunk); err != nil {
+		b.Ctx.Log.Debug("received invalid state chunk %d from %s: %s", index, vdr, err)
+		return b.retryChunk(index)
+	}
+
+	b.chunks[index] = chunk
+	b.NumFetched++
+	if b.NumFetched%common.StatusUpdateFrequency == 0 { // Periodically print progress
+		b.Ctx.Log.Info("fetched %d state chunks", b.NumFetched)
+	}
+	return b.fetchChunks()
+}
+
+// GetStateChunkFailed implements the Engine interface.
+func (b *Bootstrapper) GetStateChunkFailed(vdr ids.ShortID, requestID uint32) error {
+	request := chunkRequest{validatorID: vdr, requestID: requestID}
+	index, ok := b.outstandingChunks[request]
+	if !b.syncing || !ok {
+		b.Ctx.Log.Debug("GetStateChunkFailed(%s, %d) called but there was no outstanding request to this validator with this ID",
+			vdr, requestID)
+		return nil
+	}
+	delete(b.outstandingChunks, request)
+	return b.retryChunk(index)
+}
+
+// retryChunk requests chunk [index] again, likely from another beacon. If
+// fetching the chunk failed [maxStateChunkFailures] times, the summary is
+// abandoned.
+func (b *Bootstrapper) retryChunk(index uint32) error {
+	b.chunkFailures[index]++
+	if failures := b.chunkFailures[index]; failures >= maxStateChunkFailures {
+		return b.abandonSummary(fmt.Sprintf("chunk %d failed to be fetched %d times", index, failures))
+	}
+	b.chunksToFetch = append(b.chunksToFetch, index)
+	return b.fetchChunks()
+}
+
+// removeSyncPeer stops requesting chunks from [vdr]
+func (b *Bootstrapper) removeSyncPeer(vdr ids.ShortID) {
+	for i, syncPeer := range b.syncPeers {
+		if syncPeer == vdr {
+			b.syncPeers = append(b.syncPeers[:i], b.syncPeers[i+1:]...)
+			return
+		}
+	}
+}
+
+// abandonSummary stops syncing the summary being synced because of [reason].
+// Summaries are requested from the beacons again, unless [maxStateSyncAttempts]
+// summaries have been chosen, in which case bootstrapping continues from
+// genesis.
+func (b *Bootstrapper) abandonSummary(reason string) error {
+	summaryID := b.summary.ID()
+	b.summary = nil
+	b.chunks = nil
+	b.chunksToFetch = nil
+	b.chunkFailures = nil
+	b.syncPeers = nil
+	b.NumFetched = 0
+
+	if b.stateSyncAttempts < maxStateSyncAttempts {
+		b.Ctx.Log.Info("abandoning state summary %s as %s. requesting state summaries again...", summaryID, reason)
+		return b.startStateSync(b.syncFrontier)
+	}
+
+	b.Ctx.Log.Info("abandoning state summary %s as %s. bootstrapping from genesis instead...", summaryID, reason)
+	b.syncing = false
+	b.summaries = nil
+	b.summaryWeights = nil
+	b.summaryVoters = nil
+	b.outstandingChunks = nil
+	return b.fetchAccepted(b.syncFrontier)
+}
+
+// finishStateSync passes the fetched chunks to the VM and then bootstraps the
+// blocks accepted after the summary
+func (b *Bootstrapper) finishStateSync() error {
+	vm := b.VM.(block.StateSyncableVM)
+	if err := vm.SyncState(b.summary, b.chunks); err != nil {
+		return fmt.Errorf("failed to sync state summary %s: %w", b.summary.ID(), err)
+	}
+	b.Ctx.Log.Info("synced state at height %d. fetching the blocks accepted since then...",
+		b.summary.Height())
+
+	b.syncing = false
+	b.NumFetched = 0
+	b.summaries = nil
+	b.summaryWeights = nil
+	b.summaryVoters = nil
+	b.chunks = nil
+	return b.fetchAccepted(b.syncFrontier)
+}
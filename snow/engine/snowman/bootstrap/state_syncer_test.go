@@ -0,0 +1,469 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bootstrap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/queue"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+var errInvalidChunk = errors.New("invalid chunk")
+
+type testStateSummary struct {
+	id     ids.ID
+	height uint64
+	bytes  []byte
+	chunks [][]byte
+}
+
+func (s *testStateSummary) ID() ids.ID        { return s.id }
+func (s *testStateSummary) Height() uint64    { return s.height }
+func (s *testStateSummary) Bytes() []byte     { return s.bytes }
+func (s *testStateSummary) NumChunks() uint32 { return uint32(len(s.chunks)) }
+func (s *testStateSummary) VerifyChunk(index uint32, chunk []byte) error {
+	if index >= s.NumChunks() || !bytes.Equal(chunk, s.chunks[index]) {
+		return errInvalidChunk
+	}
+	return nil
+}
+
+func newStateSyncConfig(t *testing.T) (Config, ids.ShortID, *common.SenderTest, *block.TestStateSyncableVM) {
+	ctx := snow.DefaultContextTest()
+
+	peers := validators.NewSet()
+	db := memdb.New()
+	sender := &common.SenderTest{}
+	vm := &block.TestStateSyncableVM{}
+
+	sender.T = t
+	vm.T = t
+
+	sender.Default(true)
+	vm.Default(true)
+
+	sender.CantGetAcceptedFrontier = false
+
+	peer := ids.GenerateTestShortID()
+	if err := peers.AddWeight(peer, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	blocker, _ := queue.New(db)
+
+	commonConfig := common.Config{
+		Ctx:        ctx,
+		Validators: peers,
+		Beacons:    peers,
+		SampleK:    int(peers.Weight()),
+		Alpha:      uint64(peers.Len()/2 + 1),
+		Sender:     sender,
+	}
+	return Config{
+		Config:    commonConfig,
+		Blocked:   blocker,
+		VM:        vm,
+		StateSync: true,
+	}, peer, sender, vm
+}
+
+// The state is synced from the summary sent by the beacons and the blocks
+// after it are then bootstrapped
+func TestStateSyncerSyncsSummary(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	// The block the summary was taken at
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID1,
+			StatusV: choices.Accepted,
+		},
+		ParentV: blk0,
+		HeightV: 1,
+	}
+
+	summary := &testStateSummary{
+		id:     ids.GenerateTestID(),
+		height: 1,
+		bytes:  []byte{1},
+		chunks: [][]byte{{2}, {3}},
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	synced := false
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID == blkID0:
+			return blk0, nil
+		case blkID == blkID1 && synced:
+			return blk1, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	vm.ParseStateSummaryF = func(summaryBytes []byte) (block.StateSummary, error) {
+		if !bytes.Equal(summaryBytes, summary.bytes) {
+			t.Fatalf("unexpected summary %v", summaryBytes)
+		}
+		return summary, nil
+	}
+	vm.SyncStateF = func(syncedSummary block.StateSummary, chunks [][]byte) error {
+		if syncedSummary.ID() != summary.ID() {
+			t.Fatalf("synced summary %s but expected %s", syncedSummary.ID(), summary.ID())
+		}
+		for i, chunk := range chunks {
+			if !bytes.Equal(chunk, summary.chunks[i]) {
+				t.Fatalf("chunk %d is %v but should be %v", i, chunk, summary.chunks[i])
+			}
+		}
+		synced = true
+		return nil
+	}
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	summaryRequestID := new(uint32)
+	sender.GetStateSummaryF = func(vdrs ids.ShortSet, requestID uint32) {
+		if !vdrs.Contains(peerID) {
+			t.Fatalf("should have requested a summary from %s", peerID)
+		}
+		*summaryRequestID = requestID
+	}
+	chunkRequests := map[uint32]uint32{} // request ID --> chunk index
+	sender.GetStateChunkF = func(vdr ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+		if vdr != peerID {
+			t.Fatalf("requested chunk from %s but should have from %s", vdr, peerID)
+		}
+		if summaryID != summary.ID() {
+			t.Fatalf("requested chunk of %s but should have of %s", summaryID, summary.ID())
+		}
+		chunkRequests[requestID] = index
+	}
+
+	if err := bs.ForceAccepted([]ids.ID{blkID1}); err != nil {
+		t.Fatal(err)
+	}
+	if !bs.syncing {
+		t.Fatal("should be syncing state")
+	}
+
+	if err := bs.StateSummary(peerID, *summaryRequestID, summary.bytes); err != nil {
+		t.Fatal(err)
+	}
+	if len(chunkRequests) != len(summary.chunks) {
+		t.Fatalf("should have requested %d chunks but requested %d", len(summary.chunks), len(chunkRequests))
+	}
+
+	// An invalid chunk is requested again
+	var (
+		requestID uint32
+		index     uint32
+	)
+	for requestID, index = range chunkRequests {
+		break
+	}
+	delete(chunkRequests, requestID)
+	if err := bs.StateChunk(peerID, requestID, []byte{4}); err != nil {
+		t.Fatal(err)
+	}
+	if len(chunkRequests) != len(summary.chunks) {
+		t.Fatal("should have requested the invalid chunk again")
+	}
+
+	for requestID, index = range chunkRequests {
+		if err := bs.StateChunk(peerID, requestID, summary.chunks[index]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	switch {
+	case !synced:
+		t.Fatal("state should have been synced")
+	case bs.syncing:
+		t.Fatal("shouldn't be syncing state anymore")
+	case !*finished:
+		t.Fatal("bootstrapping should have finished")
+	}
+}
+
+// If no summary is sent by a majority of the beacons, bootstrapping continues
+// from genesis
+func TestStateSyncerNoSummary(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	blkID0 := ids.Empty.Prefix(0)
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == blkID0 {
+			return blk0, nil
+		}
+		return nil, errUnknownBlock
+	}
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	summaryRequestID := new(uint32)
+	sender.GetStateSummaryF = func(_ ids.ShortSet, requestID uint32) {
+		*summaryRequestID = requestID
+	}
+
+	if err := bs.ForceAccepted([]ids.ID{blkID0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.GetStateSummaryFailed(peerID, *summaryRequestID); err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case bs.syncing:
+		t.Fatal("shouldn't be syncing state")
+	case !*finished:
+		t.Fatal("bootstrapping should have finished")
+	}
+}
+
+// A summary that no beacon serves anymore is abandoned. Summaries are chosen
+// again until too many have been abandoned, and then bootstrapping continues
+// from genesis.
+func TestStateSyncerAbandonsSummary(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	blkID0 := ids.Empty.Prefix(0)
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	summary := &testStateSummary{
+		id:     ids.GenerateTestID(),
+		height: 1,
+		bytes:  []byte{1},
+		chunks: [][]byte{{2}},
+	}
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == blkID0 {
+			return blk0, nil
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseStateSummaryF = func([]byte) (block.StateSummary, error) { return summary, nil }
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	summaryRequests := 0
+	summaryRequestID := new(uint32)
+	sender.GetStateSummaryF = func(_ ids.ShortSet, requestID uint32) {
+		summaryRequests++
+		*summaryRequestID = requestID
+	}
+	chunkRequestID := new(uint32)
+	sender.GetStateChunkF = func(_ ids.ShortID, requestID uint32, _ ids.ID, _ uint32) {
+		*chunkRequestID = requestID
+	}
+
+	if err := bs.ForceAccepted([]ids.ID{blkID0}); err != nil {
+		t.Fatal(err)
+	}
+	for attempt := 1; attempt <= maxStateSyncAttempts; attempt++ {
+		if summaryRequests != attempt {
+			t.Fatalf("should have requested summaries %d times but did %d times", attempt, summaryRequests)
+		}
+		if err := bs.StateSummary(peerID, *summaryRequestID, summary.bytes); err != nil {
+			t.Fatal(err)
+		}
+		// The only beacon no longer serves the summary
+		if err := bs.StateChunk(peerID, *chunkRequestID, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	switch {
+	case summaryRequests != maxStateSyncAttempts:
+		t.Fatalf("should have requested summaries %d times but did %d times", maxStateSyncAttempts, summaryRequests)
+	case bs.syncing:
+		t.Fatal("shouldn't be syncing state anymore")
+	case !*finished:
+		t.Fatal("bootstrapping should have finished")
+	}
+}
+
+// A chunk that repeatedly fails to be fetched causes its summary to be
+// abandoned
+func TestStateSyncerBoundsChunkRetries(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	blkID0 := ids.Empty.Prefix(0)
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID0,
+			StatusV: choices.Accepted,
+		},
+		HeightV: 0,
+	}
+	summary := &testStateSummary{
+		id:     ids.GenerateTestID(),
+		height: 1,
+		bytes:  []byte{1},
+		chunks: [][]byte{{2}},
+	}
+
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.LastAcceptedF = func() ids.ID { return blkID0 }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID == blkID0 {
+			return blk0, nil
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseStateSummaryF = func([]byte) (block.StateSummary, error) { return summary, nil }
+	vm.CantBootstrapping = false
+
+	summaryRequests := 0
+	summaryRequestID := new(uint32)
+	sender.GetStateSummaryF = func(_ ids.ShortSet, requestID uint32) {
+		summaryRequests++
+		*summaryRequestID = requestID
+	}
+	chunkRequests := 0
+	chunkRequestID := new(uint32)
+	sender.GetStateChunkF = func(_ ids.ShortID, requestID uint32, _ ids.ID, _ uint32) {
+		chunkRequests++
+		*chunkRequestID = requestID
+	}
+
+	if err := bs.ForceAccepted([]ids.ID{blkID0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.StateSummary(peerID, *summaryRequestID, summary.bytes); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < maxStateChunkFailures; i++ {
+		if err := bs.GetStateChunkFailed(peerID, *chunkRequestID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if chunkRequests != maxStateChunkFailures {
+		t.Fatalf("should have requested the chunk %d times but did %d times", maxStateChunkFailures, chunkRequests)
+	}
+	if summaryRequests != 2 {
+		t.Fatal("should have requested summaries again after abandoning the summary")
+	}
+}
+
+// A request for a chunk that isn't available is answered with an empty chunk
+func TestGetStateChunkUnavailable(t *testing.T) {
+	config, peerID, sender, vm := newStateSyncConfig(t)
+
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { return nil },
+		fmt.Sprintf("%s_%s", constants.PlatformName, config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.StateChunkF = func(ids.ID, uint32) ([]byte, error) { return nil, errInvalidChunk }
+	responded := false
+	sender.StateChunkF = func(vdr ids.ShortID, requestID uint32, chunk []byte) {
+		switch {
+		case vdr != peerID || requestID != 5:
+			t.Fatalf("responded to the wrong request")
+		case len(chunk) != 0:
+			t.Fatalf("should have responded with an empty chunk")
+		}
+		responded = true
+	}
+	if err := bs.GetStateChunk(peerID, 5, ids.GenerateTestID(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if !responded {
+		t.Fatal("should have responded to the request")
+	}
+}
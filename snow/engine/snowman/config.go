@@ -6,6 +6,7 @@ package snowman
 import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 )
 
@@ -15,4 +16,7 @@ type Config struct {
 
 	Params    snowball.Parameters
 	Consensus snowman.Consensus
+
+	// Tracer records the events of consensus. If nil, nothing is recorded.
+	Tracer tracer.Tracer
 }
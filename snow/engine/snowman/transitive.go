@@ -9,11 +9,13 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
 	"github.com/ava-labs/avalanchego/snow/events"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -53,6 +55,11 @@ type Transitive struct {
 	// processing blocks has gone below the optimal number.
 	pendingBuildBlocks int
 
+	// tracer records the events of consensus. decisions is nil unless the
+	// events are recorded.
+	tracer    tracer.Tracer
+	decisions *tracer.Decisions
+
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
 }
@@ -63,8 +70,13 @@ func (t *Transitive) Initialize(config Config) error {
 
 	t.Params = config.Params
 	t.Consensus = config.Consensus
+	t.tracer = tracer.Noop{}
+	if config.Tracer != nil {
+		t.tracer = config.Tracer
+		t.decisions = tracer.NewDecisions(config.Tracer)
+	}
 
-	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
+	factory := poll.NewFactory(config.Params, t.traverse)
 	t.polls = poll.NewSet(factory,
 		config.Ctx.Log,
 		config.Params.Namespace,
@@ -91,6 +103,10 @@ func (t *Transitive) finishBootstrapping() error {
 	if err := t.Consensus.Initialize(t.Ctx, t.Params, lastAcceptedID); err != nil {
 		return err
 	}
+	t.tracer.Trace(&tracer.Initialize{
+		Params:   t.Params,
+		Accepted: []ids.ID{lastAcceptedID},
+	})
 
 	lastAccepted, err := t.VM.GetBlock(lastAcceptedID)
 	if err != nil {
@@ -137,7 +153,12 @@ func (t *Transitive) Gossip() error {
 // Shutdown implements the Engine interface
 func (t *Transitive) Shutdown() error {
 	t.Ctx.Log.Info("shutting down consensus engine")
-	return t.VM.Shutdown()
+	errs := wrappers.Errs{}
+	errs.Add(
+		t.VM.Shutdown(),
+		t.tracer.Close(),
+	)
+	return errs.Err
 }
 
 // Get implements the Engine interface
@@ -314,10 +335,22 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes []ids.ID) er
 		t.Ctx.Log.Debug("dropping Chits(%s, %d) due to bootstrapping", vdr, requestID)
 		return nil
 	}
+	t.tracer.Trace(&tracer.Chits{
+		NodeID:    vdr,
+		RequestID: requestID,
+		Votes:     votes,
+	})
+
+	if !t.Requested(requestID) {
+		t.Ctx.Log.Debug("Chits(%s, %d) is for a query that was never sent", vdr, requestID)
+		t.Ctx.ReportMisbehavior(vdr, snow.UnrequestedResponse)
+		return nil
+	}
 
 	// Since this is a linear chain, there should only be one ID in the vote set
 	if len(votes) != 1 {
 		t.Ctx.Log.Debug("Chits(%s, %d) was called with %d votes (expected 1)", vdr, requestID, len(votes))
+		t.Ctx.ReportMisbehavior(vdr, snow.InvalidMessage)
 		// because QueryFailed doesn't utilize the assumption that we actually
 		// sent a Query message, we can safely call QueryFailed here to
 		// potentially abandon the request.
@@ -356,6 +389,10 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 		t.Ctx.Log.Warn("dropping QueryFailed(%s, %d) due to bootstrapping", vdr, requestID)
 		return nil
 	}
+	t.tracer.Trace(&tracer.QueryFailed{
+		NodeID:    vdr,
+		RequestID: requestID,
+	})
 
 	t.blocked.Register(&voter{
 		t:         t,
@@ -631,7 +668,7 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	}
 
 	t.Ctx.Log.Verbo("adding block to consensus: %s", blkID)
-	if err := t.Consensus.Add(blk); err != nil {
+	if err := t.add(blk); err != nil {
 		return err
 	}
 
@@ -650,7 +687,7 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 				t.Ctx.Log.Debug("block failed verification due to %s, dropping block", err)
 				dropped = append(dropped, blk)
 			} else {
-				if err := t.Consensus.Add(blk); err != nil {
+				if err := t.add(blk); err != nil {
 					return err
 				}
 				added = append(added, blk)
@@ -688,6 +725,38 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	return t.errs.Err
 }
 
+// add [blk] to consensus and trace it
+func (t *Transitive) add(blk snowman.Block) error {
+	t.tracer.Trace(&tracer.Issue{
+		ID:      blk.ID(),
+		Parents: []ids.ID{blk.Parent().ID()},
+		Height:  blk.Height(),
+	})
+	if err := t.Consensus.Add(blk); err != nil {
+		return err
+	}
+	if t.decisions != nil {
+		t.decisions.Issued(blk)
+		t.decisions.Trace()
+	}
+	return nil
+}
+
+// traverse returns the processing blocks that a vote for [blkID] is applied
+// to, from [blkID] to its oldest processing ancestor
+func (t *Transitive) traverse(blkID ids.ID) ([]ids.ID, bool) {
+	blk, err := t.VM.GetBlock(blkID)
+	if err != nil {
+		return nil, false
+	}
+	blkIDs := []ids.ID(nil)
+	for blk.Status() == choices.Processing {
+		blkIDs = append(blkIDs, blk.ID())
+		blk = blk.Parent()
+	}
+	return blkIDs, true
+}
+
 // IsBootstrapped returns true iff this chain is done bootstrapping
 func (t *Transitive) IsBootstrapped() bool {
 	return t.Ctx.IsBootstrapped()
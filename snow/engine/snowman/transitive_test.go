@@ -6,15 +6,18 @@ package snowman
 import (
 	"bytes"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
@@ -2104,3 +2107,112 @@ func TestEngineReceiveNewRejectedBlock(t *testing.T) {
 		t.Fatalf("Should have finished all requests")
 	}
 }
+
+// testTracer records the events it traces
+type testTracer struct{ events []tracer.Event }
+
+func (tt *testTracer) Trace(e tracer.Event) { tt.events = append(tt.events, e) }
+func (*testTracer) Close() error            { return nil }
+
+func TestEngineTrace(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	tt := &testTracer{}
+	te.tracer = tt
+	te.decisions = tracer.NewDecisions(tt)
+
+	sender.Default(true)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	var reqID uint32
+	sender.PushQueryF = func(_ ids.ShortSet, requestID uint32, _ ids.ID, _ []byte) { reqID = requestID }
+	vm.BuildBlockF = func() (snowman.Block, error) { return blk, nil }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID != blk.ID() {
+			t.Fatalf("Wrong block requested")
+		}
+		return blk, nil
+	}
+	if err := te.Chits(vdr, reqID, []ids.ID{blk.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if status := blk.Status(); status != choices.Accepted {
+		t.Fatalf("Block should have been accepted but is %s", status)
+	}
+
+	poll := &tracer.Poll{}
+	poll.Votes.Add(blk.ID())
+	expected := []tracer.Event{
+		&tracer.Issue{
+			ID:      blk.ID(),
+			Parents: []ids.ID{gBlk.ID()},
+			Height:  1,
+		},
+		&tracer.Chits{
+			NodeID:    vdr,
+			RequestID: reqID,
+			Votes:     []ids.ID{blk.ID()},
+		},
+		poll,
+		&tracer.Accept{ID: blk.ID()},
+	}
+	if !reflect.DeepEqual(tt.events, expected) {
+		t.Fatalf("Expected events %+v but got %+v", expected, tt.events)
+	}
+}
+
+// misbehaviorRecorder records the misbehavior reported to it
+type misbehaviorRecorder map[ids.ShortID][]snow.Misbehavior
+
+func (r misbehaviorRecorder) Misbehaved(nodeID ids.ShortID, misbehavior snow.Misbehavior) {
+	r[nodeID] = append(r[nodeID], misbehavior)
+}
+
+func TestEngineUnrequestedChits(t *testing.T) {
+	vdr, _, _, _, te, gBlk := setup(t)
+
+	reporter := misbehaviorRecorder{}
+	te.Ctx.PeerReporter = reporter
+
+	if err := te.Chits(vdr, te.RequestID+1, []ids.ID{gBlk.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if misbehavior := reporter[vdr]; len(misbehavior) != 1 || misbehavior[0] != snow.UnrequestedResponse {
+		t.Fatalf("expected an unrequested response to be reported but got %v", misbehavior)
+	}
+}
+
+func TestEngineUnparsableContainerNotReported(t *testing.T) {
+	vdr, _, sender, vm, te, _ := setup(t)
+
+	sender.Default(true)
+
+	reporter := misbehaviorRecorder{}
+	te.Ctx.PeerReporter = reporter
+
+	// The peer may run a newer version of the VM than this node
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) { return nil, errUnknownBytes }
+
+	if err := te.PushQuery(vdr, 0, ids.GenerateTestID(), []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.Put(vdr, 0, ids.GenerateTestID(), []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	if misbehavior := reporter[vdr]; len(misbehavior) != 0 {
+		t.Fatalf("expected nothing to be reported but got %v", misbehavior)
+	}
+}
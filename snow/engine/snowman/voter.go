@@ -5,6 +5,7 @@ package snowman
 
 import (
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracer"
 )
 
 // Voter records chits received from [vdr] once its dependencies are met.
@@ -49,10 +50,14 @@ func (v *voter) Update() {
 	results = v.bubbleVotes(results)
 
 	v.t.Ctx.Log.Debug("Finishing poll [%d] with:\n%s", v.requestID, &results)
+	v.t.tracer.Trace(&tracer.Poll{Votes: results})
 	if err := v.t.Consensus.RecordPoll(results); err != nil {
 		v.t.errs.Add(err)
 		return
 	}
+	if v.t.decisions != nil {
+		v.t.decisions.Trace()
+	}
 
 	v.t.VM.SetPreference(v.t.Consensus.Preference())
 
@@ -0,0 +1,46 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Misbehavior is a way in which a peer may misbehave
+type Misbehavior byte
+
+// Kinds of misbehavior
+const (
+	// The peer sent a message that couldn't be parsed or was malformed
+	InvalidMessage Misbehavior = iota
+	// The peer sent a container other than the one that was requested from
+	// it. A container that couldn't be parsed isn't misbehavior, as the peer
+	// may run a newer version of the VM.
+	InvalidContainer
+	// The peer sent a response to a request that was never sent to it
+	UnrequestedResponse
+	// The peer was benched after repeatedly failing to respond to queries
+	Benched
+)
+
+func (m Misbehavior) String() string {
+	switch m {
+	case InvalidMessage:
+		return "invalid message"
+	case InvalidContainer:
+		return "invalid container"
+	case UnrequestedResponse:
+		return "unrequested response"
+	case Benched:
+		return "benched"
+	default:
+		return "Unknown Misbehavior"
+	}
+}
+
+// PeerReporter is told when a peer misbehaves
+type PeerReporter interface {
+	// Misbehaved reports that the peer [nodeID] misbehaved
+	Misbehaved(nodeID ids.ShortID, misbehavior Misbehavior)
+}
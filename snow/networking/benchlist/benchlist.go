@@ -197,6 +197,7 @@ func (b *queryBenchlist) bench(validatorID ids.ShortID) {
 		b.threshold,
 		randomizedEndTime.Sub(currTime),
 	)
+	b.ctx.ReportMisbehavior(validatorID, snow.Benched)
 
 	// Note: there could be a memory leak if a large number of
 	// validators were added, sampled, benched, and never sampled
@@ -247,6 +247,64 @@ func failMessage(benchlist *queryBenchlist, validatorID ids.ShortID) bool {
 
 // bench adjusts the time and fails sufficient messages to bench
 // [validatorIDs]
+// misbehaviorRecorder records the misbehavior reported to it
+type misbehaviorRecorder map[ids.ShortID][]snow.Misbehavior
+
+func (r misbehaviorRecorder) Misbehaved(nodeID ids.ShortID, misbehavior snow.Misbehavior) {
+	r[nodeID] = append(r[nodeID], misbehavior)
+}
+
+// Test that benching a validator is reported as misbehavior
+func TestBenchlistReportsBenched(t *testing.T) {
+	vdrs := validators.NewSet()
+	vdr0 := validators.GenerateRandomValidator(50)
+	vdr1 := validators.GenerateRandomValidator(50)
+	vdr2 := validators.GenerateRandomValidator(50)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		vdrs.AddWeight(vdr0.ID(), vdr0.Weight()),
+		vdrs.AddWeight(vdr1.ID(), vdr1.Weight()),
+		vdrs.AddWeight(vdr2.ID(), vdr2.Weight()),
+	)
+	if errs.Errored() {
+		t.Fatal(errs.Err)
+	}
+
+	ctx := snow.DefaultContextTest()
+	reporter := misbehaviorRecorder{}
+	ctx.PeerReporter = reporter
+
+	benchIntf, err := NewQueryBenchlist(
+		vdrs,
+		ctx,
+		3,
+		minimumFailingDuration,
+		time.Minute,
+		0.5,
+		false,
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := benchIntf.(*queryBenchlist)
+	b.clock.Set(time.Now())
+
+	if ok := bench(b, []ids.ShortID{vdr0.ID()}); !ok {
+		t.Fatal("RegisterQuery failed early")
+	}
+	if ok := b.RegisterQuery(vdr0.ID(), 0, constants.PullQueryMsg); ok {
+		t.Fatal("RegisterQuery should have benchlisted query from unresponsive peer: vdr0")
+	}
+	if misbehavior := reporter[vdr0.ID()]; len(misbehavior) != 1 || misbehavior[0] != snow.Benched {
+		t.Fatalf("expected vdr0 to be reported as benched but got %v", misbehavior)
+	}
+	if misbehavior := reporter[vdr1.ID()]; len(misbehavior) != 0 {
+		t.Fatalf("expected nothing to be reported about vdr1 but got %v", misbehavior)
+	}
+}
+
 func bench(b *queryBenchlist, validatorIDs []ids.ShortID) bool {
 	currentTime := b.clock.Time()
 	for _, validatorID := range validatorIDs {
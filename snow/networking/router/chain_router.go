@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/formatting"
@@ -38,6 +39,7 @@ type ChainRouter struct {
 	peers            ids.ShortSet
 	criticalChains   ids.Set
 	onFatal          func()
+	peerReporter     snow.PeerReporter
 }
 
 // Initialize the router.
@@ -48,6 +50,10 @@ type ChainRouter struct {
 //
 // This router also fires a gossip event every [gossipFrequency] to the engine,
 // notifying the engine it should gossip it's accepted set.
+//
+// Responses for chains this router doesn't route to are reported to
+// [peerReporter], if it's non-nil, as requests are only sent for chains this
+// router routes to.
 func (sr *ChainRouter) Initialize(
 	nodeID ids.ShortID,
 	log logging.Logger,
@@ -56,6 +62,7 @@ func (sr *ChainRouter) Initialize(
 	closeTimeout time.Duration,
 	criticalChains ids.Set,
 	onFatal func(),
+	peerReporter snow.PeerReporter,
 ) {
 	sr.log = log
 	sr.chains = make(map[ids.ID]*Handler)
@@ -65,6 +72,7 @@ func (sr *ChainRouter) Initialize(
 	sr.closeTimeout = closeTimeout
 	sr.criticalChains = criticalChains
 	sr.onFatal = onFatal
+	sr.peerReporter = peerReporter
 
 	sr.peers.Add(nodeID)
 
@@ -173,6 +181,7 @@ func (sr *ChainRouter) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID,
 		}
 	} else {
 		sr.log.Debug("AcceptedFrontier(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, containerIDs)
+		sr.unrequestedResponse(validatorID)
 	}
 }
 
@@ -218,6 +227,7 @@ func (sr *ChainRouter) Accepted(validatorID ids.ShortID, chainID ids.ID, request
 		}
 	} else {
 		sr.log.Debug("Accepted(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, containerIDs)
+		sr.unrequestedResponse(validatorID)
 	}
 }
 
@@ -264,6 +274,7 @@ func (sr *ChainRouter) MultiPut(validatorID ids.ShortID, chainID ids.ID, request
 		}
 	} else {
 		sr.log.Debug("MultiPut(%s, %s, %d, %d) dropped due to unknown chain", validatorID, chainID, requestID, len(containers))
+		sr.unrequestedResponse(validatorID)
 	}
 }
 
@@ -315,6 +326,7 @@ func (sr *ChainRouter) Put(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	default:
 		sr.log.Debug("Put(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, containerID)
 		sr.log.Verbo("container:\n%s", formatting.DumpBytes{Bytes: container})
+		sr.unrequestedResponse(validatorID)
 	}
 }
 
@@ -372,6 +384,7 @@ func (sr *ChainRouter) Chits(validatorID ids.ShortID, chainID ids.ID, requestID
 		}
 	} else {
 		sr.log.Debug("Chits(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, votes)
+		sr.unrequestedResponse(validatorID)
 	}
 }
 
@@ -392,6 +405,154 @@ func (sr *ChainRouter) QueryFailed(validatorID ids.ShortID, chainID ids.ID, requ
 	}
 }
 
+// GetStateSummary routes an incoming GetStateSummary message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID]; exists {
+		chain.GetStateSummary(validatorID, requestID, deadline)
+	} else {
+		sr.log.Debug("GetStateSummary(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+	}
+}
+
+// StateSummary routes an incoming StateSummary message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	// This message came in response to a GetStateSummary message from this node, and when we sent that
+	// message we set a timeout. Since we got a response, cancel the timeout.
+	if chain, exists := sr.chains[chainID]; exists {
+		if chain.StateSummary(validatorID, requestID, summary) {
+			sr.timeouts.Cancel(validatorID, chainID, requestID)
+		}
+	} else {
+		sr.log.Debug("StateSummary(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		sr.unrequestedResponse(validatorID)
+	}
+}
+
+// GetStateSummaryFailed routes an incoming GetStateSummaryFailed message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID]; exists {
+		chain.GetStateSummaryFailed(validatorID, requestID)
+	} else {
+		sr.log.Debug("GetStateSummaryFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+	}
+}
+
+// GetStateChunk routes an incoming GetStateChunk message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, summaryID ids.ID, index uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID]; exists {
+		chain.GetStateChunk(validatorID, requestID, deadline, summaryID, index)
+	} else {
+		sr.log.Debug("GetStateChunk(%s, %s, %d, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID, summaryID, index)
+	}
+}
+
+// StateChunk routes an incoming StateChunk message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	// This message came in response to a GetStateChunk message from this node, and when we sent that
+	// message we set a timeout. Since we got a response, cancel the timeout.
+	if chain, exists := sr.chains[chainID]; exists {
+		if chain.StateChunk(validatorID, requestID, chunk) {
+			sr.timeouts.Cancel(validatorID, chainID, requestID)
+		}
+	} else {
+		sr.log.Debug("StateChunk(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		sr.unrequestedResponse(validatorID)
+	}
+}
+
+// GetStateChunkFailed routes an incoming GetStateChunkFailed message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetStateChunkFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID]; exists {
+		chain.GetStateChunkFailed(validatorID, requestID)
+	} else {
+		sr.log.Debug("GetStateChunkFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+	}
+}
+
+// AppRequest routes an incoming AppRequest message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) AppRequest(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, appRequestBytes []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID]; exists {
+		chain.AppRequest(validatorID, requestID, deadline, appRequestBytes)
+	} else {
+		sr.log.Debug("AppRequest(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+	}
+}
+
+// AppResponse routes an incoming AppResponse message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) AppResponse(validatorID ids.ShortID, chainID ids.ID, requestID uint32, appResponseBytes []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	// This message came in response to an AppRequest message from this node, and when we sent that
+	// message we set a timeout. Since we got a response, cancel the timeout.
+	if chain, exists := sr.chains[chainID]; exists {
+		if chain.AppResponse(validatorID, requestID, appResponseBytes) {
+			sr.timeouts.CancelAppRequest(validatorID, chainID, requestID)
+		}
+	} else {
+		sr.log.Debug("AppResponse(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+		sr.unrequestedResponse(validatorID)
+	}
+}
+
+// AppRequestFailed routes an incoming AppRequestFailed message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) AppRequestFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.CancelAppRequest(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID]; exists {
+		chain.AppRequestFailed(validatorID, requestID)
+	} else {
+		sr.log.Debug("AppRequestFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
+	}
+}
+
+// AppGossip routes an incoming AppGossip message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) AppGossip(validatorID ids.ShortID, chainID ids.ID, appGossipBytes []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID]; exists {
+		chain.AppGossip(validatorID, appGossipBytes)
+	} else {
+		sr.log.Verbo("AppGossip(%s, %s) dropped due to unknown chain", validatorID, chainID)
+	}
+}
+
 // Connected routes an incoming notification that a validator was just connected
 func (sr *ChainRouter) Connected(validatorID ids.ShortID) {
 	sr.lock.Lock()
@@ -433,3 +594,12 @@ func (sr *ChainRouter) EndInterval() {
 		chain.endInterval()
 	}
 }
+
+// unrequestedResponse reports that [validatorID] sent a response for a chain
+// this router doesn't route to. As requests are only sent for the chains this
+// router routes to, the response wasn't requested.
+func (sr *ChainRouter) unrequestedResponse(validatorID ids.ShortID) {
+	if sr.peerReporter != nil {
+		sr.peerReporter.Misbehaved(validatorID, snow.UnrequestedResponse)
+	}
+}
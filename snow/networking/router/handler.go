@@ -376,6 +376,113 @@ func (h *Handler) GetAncestorsFailed(validatorID ids.ShortID, requestID uint32)
 	})
 }
 
+// GetStateSummary passes a GetStateSummary message received from the network to the consensus engine.
+func (h *Handler) GetStateSummary(validatorID ids.ShortID, requestID uint32, deadline time.Time) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.GetStateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		deadline:    deadline,
+		received:    h.clock.Time(),
+	})
+}
+
+// StateSummary passes a StateSummary message received from the network to the consensus engine.
+func (h *Handler) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.StateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   summary,
+		received:    h.clock.Time(),
+	})
+}
+
+// GetStateSummaryFailed passes a GetStateSummaryFailed message to the consensus engine.
+func (h *Handler) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) {
+	h.sendReliableMsg(message{
+		messageType: constants.GetStateSummaryFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
+// GetStateChunk passes a GetStateChunk message received from the network to the consensus engine.
+func (h *Handler) GetStateChunk(validatorID ids.ShortID, requestID uint32, deadline time.Time, summaryID ids.ID, index uint32) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.GetStateChunkMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		deadline:    deadline,
+		containerID: summaryID,
+		chunkIndex:  index,
+		received:    h.clock.Time(),
+	})
+}
+
+// StateChunk passes a StateChunk message received from the network to the consensus engine.
+func (h *Handler) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.StateChunkMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   chunk,
+		received:    h.clock.Time(),
+	})
+}
+
+// GetStateChunkFailed passes a GetStateChunkFailed message to the consensus engine.
+func (h *Handler) GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) {
+	h.sendReliableMsg(message{
+		messageType: constants.GetStateChunkFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
+// AppRequest passes an AppRequest message received from the network to the consensus engine.
+func (h *Handler) AppRequest(validatorID ids.ShortID, requestID uint32, deadline time.Time, appRequestBytes []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.AppRequestMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		deadline:    deadline,
+		container:   appRequestBytes,
+		received:    h.clock.Time(),
+	})
+}
+
+// AppResponse passes an AppResponse message received from the network to the consensus engine.
+func (h *Handler) AppResponse(validatorID ids.ShortID, requestID uint32, appResponseBytes []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.AppResponseMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   appResponseBytes,
+		received:    h.clock.Time(),
+	})
+}
+
+// AppRequestFailed passes an AppRequestFailed message to the consensus engine.
+func (h *Handler) AppRequestFailed(validatorID ids.ShortID, requestID uint32) {
+	h.sendReliableMsg(message{
+		messageType: constants.AppRequestFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
+// AppGossip passes an AppGossip message received from the network to the consensus engine.
+func (h *Handler) AppGossip(validatorID ids.ShortID, appGossipBytes []byte) bool {
+	return h.serviceQueue.PushMessage(message{
+		messageType: constants.AppGossipMsg,
+		validatorID: validatorID,
+		requestID:   constants.GossipMsgRequestID,
+		container:   appGossipBytes,
+		received:    h.clock.Time(),
+	})
+}
+
 // Get passes a Get message received from the network to the consensus engine.
 func (h *Handler) Get(validatorID ids.ShortID, requestID uint32, deadline time.Time, containerID ids.ID) bool {
 	return h.serviceQueue.PushMessage(message{
@@ -550,6 +657,26 @@ func (h *Handler) handleValidatorMsg(msg message, startTime time.Time) error {
 		err = h.engine.QueryFailed(msg.validatorID, msg.requestID)
 	case constants.ChitsMsg:
 		err = h.engine.Chits(msg.validatorID, msg.requestID, msg.containerIDs)
+	case constants.GetStateSummaryMsg:
+		err = h.engine.GetStateSummary(msg.validatorID, msg.requestID)
+	case constants.StateSummaryMsg:
+		err = h.engine.StateSummary(msg.validatorID, msg.requestID, msg.container)
+	case constants.GetStateSummaryFailedMsg:
+		err = h.engine.GetStateSummaryFailed(msg.validatorID, msg.requestID)
+	case constants.GetStateChunkMsg:
+		err = h.engine.GetStateChunk(msg.validatorID, msg.requestID, msg.containerID, msg.chunkIndex)
+	case constants.StateChunkMsg:
+		err = h.engine.StateChunk(msg.validatorID, msg.requestID, msg.container)
+	case constants.GetStateChunkFailedMsg:
+		err = h.engine.GetStateChunkFailed(msg.validatorID, msg.requestID)
+	case constants.AppRequestMsg:
+		h.appMsgFailed(msg, h.engine.AppRequest(msg.validatorID, msg.requestID, msg.container))
+	case constants.AppResponseMsg:
+		h.appMsgFailed(msg, h.engine.AppResponse(msg.validatorID, msg.requestID, msg.container))
+	case constants.AppRequestFailedMsg:
+		h.appMsgFailed(msg, h.engine.AppRequestFailed(msg.validatorID, msg.requestID))
+	case constants.AppGossipMsg:
+		h.appMsgFailed(msg, h.engine.AppGossip(msg.validatorID, msg.container))
 	case constants.ConnectedMsg:
 		err = h.engine.Connected(msg.validatorID)
 	case constants.DisconnectedMsg:
@@ -566,6 +693,15 @@ func (h *Handler) handleValidatorMsg(msg message, startTime time.Time) error {
 	return err
 }
 
+// appMsgFailed logs [err], if any, returned while handling the app message
+// [msg]. The contents of app messages are chosen by peers, so failing to handle
+// one doesn't shut down the chain.
+func (h *Handler) appMsgFailed(msg message, err error) {
+	if err != nil {
+		h.ctx.Log.Debug("failed to handle app message %s due to: %s", msg, err)
+	}
+}
+
 func (h *Handler) sendReliableMsg(msg message) {
 	h.reliableMsgsLock.Lock()
 	defer h.reliableMsgsLock.Unlock()
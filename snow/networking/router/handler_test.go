@@ -145,6 +145,61 @@ func TestHandlerClosesOnError(t *testing.T) {
 	}
 }
 
+func TestHandlerDoesntCloseOnAppError(t *testing.T) {
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+
+	gossiped := make(chan struct{}, 1)
+	called := make(chan struct{}, 1)
+
+	engine.ContextF = snow.DefaultContextTest
+	engine.AppGossipF = func(nodeID ids.ShortID, msg []byte) error {
+		gossiped <- struct{}{}
+		return errors.New("invalid gossip shouldn't close the handler")
+	}
+	engine.GetFailedF = func(validatorID ids.ShortID, requestID uint32) error {
+		called <- struct{}{}
+		return nil
+	}
+
+	handler := &Handler{}
+	handler.Initialize(
+		&engine,
+		validators.NewSet(),
+		nil,
+		16,
+		DefaultMaxNonStakerPendingMsgs,
+		DefaultStakerPortion,
+		DefaultStakerPortion,
+		"",
+		prometheus.NewRegistry(),
+	)
+	handler.clock.Set(time.Now())
+
+	handler.toClose = func() {
+		t.Fatal("handler closed due to an app message")
+	}
+	go handler.Dispatch()
+
+	handler.AppGossip(ids.ShortEmpty, []byte{1})
+
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	select {
+	case <-ticker.C:
+		t.Fatalf("Calling engine function timed out")
+	case <-gossiped:
+	}
+
+	handler.GetFailed(ids.ShortEmpty, 1)
+
+	select {
+	case <-ticker.C:
+		t.Fatalf("Handler stopped handling messages after an app message failed")
+	case <-called:
+	}
+}
+
 func TestHandlerDropsGossipDuringBootstrapping(t *testing.T) {
 	engine := common.EngineTest{T: t}
 	engine.Default(false)
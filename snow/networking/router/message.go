@@ -21,6 +21,7 @@ type message struct {
 	container    []byte
 	containers   [][]byte
 	containerIDs []ids.ID
+	chunkIndex   uint32
 	notification common.Message
 	received     time.Time // Time this message was received
 	deadline     time.Time // Time this message must be responded to
@@ -45,6 +46,12 @@ func (m message) String() string {
 		sb.WriteString(fmt.Sprintf("\n    containerID: %s", m.containerID))
 	case constants.MultiPutMsg:
 		sb.WriteString(fmt.Sprintf("\n    numContainers: %d", len(m.containers)))
+	case constants.StateSummaryMsg, constants.StateChunkMsg,
+		constants.AppRequestMsg, constants.AppResponseMsg, constants.AppGossipMsg:
+		sb.WriteString(fmt.Sprintf("\n    size: %d", len(m.container)))
+	case constants.GetStateChunkMsg:
+		sb.WriteString(fmt.Sprintf("\n    summaryID: %s", m.containerID))
+		sb.WriteString(fmt.Sprintf("\n    chunkIndex: %d", m.chunkIndex))
 	case constants.NotifyMsg:
 		sb.WriteString(fmt.Sprintf("\n    notification: %s", m.notification))
 	}
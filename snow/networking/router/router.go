@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
@@ -25,6 +26,7 @@ type Router interface {
 		shutdownTimeout time.Duration,
 		criticalChains ids.Set,
 		onFatal func(),
+		peerReporter snow.PeerReporter,
 	)
 	Shutdown()
 	AddChain(chain *Handler)
@@ -45,6 +47,13 @@ type ExternalRouter interface {
 	PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, container []byte)
 	PullQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
 	Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes []ids.ID)
+	GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time)
+	StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+	GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, summaryID ids.ID, index uint32)
+	StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte)
+	AppRequest(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, appRequestBytes []byte)
+	AppResponse(validatorID ids.ShortID, chainID ids.ID, requestID uint32, appResponseBytes []byte)
+	AppGossip(validatorID ids.ShortID, chainID ids.ID, appGossipBytes []byte)
 }
 
 // InternalRouter deals with messages internal to this node
@@ -54,6 +63,9 @@ type InternalRouter interface {
 	GetFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	GetAncestorsFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	QueryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetStateChunkFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	AppRequestFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 
 	Connected(validatorID ids.ShortID)
 	Disconnected(validatorID ids.ShortID)
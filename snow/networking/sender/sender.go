@@ -29,6 +29,8 @@ func (s *Sender) Initialize(ctx *snow.Context, sender ExternalSender, router rou
 	s.timeouts = timeouts
 }
 
+var _ snow.AppSender = &Sender{}
+
 // Context of this sender
 func (s *Sender) Context() *snow.Context { return s.ctx }
 
@@ -238,6 +240,125 @@ func (s *Sender) Chits(validatorID ids.ShortID, requestID uint32, votes []ids.ID
 	}
 }
 
+// GetStateSummary sends a GetStateSummary message to each of [validatorIDs]
+func (s *Sender) GetStateSummary(validatorIDs ids.ShortSet, requestID uint32) {
+	s.ctx.Log.Verbo("Sending GetStateSummary to validators %v. RequestID: %d", validatorIDs, requestID)
+
+	currentDeadline := time.Time{}
+	for validatorID := range validatorIDs {
+		vID := validatorID // Prevent overwrite in next loop iteration
+		// Sending a GetStateSummary to myself will always fail
+		if vID == s.ctx.NodeID {
+			validatorIDs.Remove(vID)
+			go s.router.GetStateSummaryFailed(vID, s.ctx.ChainID, requestID)
+			continue
+		}
+
+		deadline, ok := s.timeouts.Register(vID, s.ctx.ChainID, requestID, false, constants.GetStateSummaryMsg, func() {
+			s.router.GetStateSummaryFailed(vID, s.ctx.ChainID, requestID)
+		})
+		if deadline.After(currentDeadline) {
+			currentDeadline = deadline
+		}
+		if !ok {
+			validatorIDs.Remove(vID)
+		}
+	}
+
+	s.sender.GetStateSummary(validatorIDs, s.ctx.ChainID, requestID, currentDeadline)
+}
+
+// StateSummary sends a StateSummary message, in response to a GetStateSummary
+// message, to the specified validator
+func (s *Sender) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	s.ctx.Log.Verbo("Sending StateSummary to validator %s. RequestID: %d", validatorID, requestID)
+	s.sender.StateSummary(validatorID, s.ctx.ChainID, requestID, summary)
+}
+
+// GetStateChunk sends a GetStateChunk message to the specified validator
+func (s *Sender) GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+	s.ctx.Log.Verbo("Sending GetStateChunk to validator %s. RequestID: %d. SummaryID: %s. Index: %d", validatorID, requestID, summaryID, index)
+	// Sending a GetStateChunk to myself will always fail
+	if validatorID == s.ctx.NodeID {
+		go s.router.GetStateChunkFailed(validatorID, s.ctx.ChainID, requestID)
+		return
+	}
+
+	deadline, ok := s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, false, constants.GetStateChunkMsg, func() {
+		s.router.GetStateChunkFailed(validatorID, s.ctx.ChainID, requestID)
+	})
+	if !ok {
+		return
+	}
+	s.sender.GetStateChunk(validatorID, s.ctx.ChainID, requestID, deadline, summaryID, index)
+}
+
+// StateChunk sends a StateChunk message, in response to a GetStateChunk
+// message, to the specified validator
+func (s *Sender) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) {
+	s.ctx.Log.Verbo("Sending StateChunk to validator %s. RequestID: %d. ChunkSize: %d", validatorID, requestID, len(chunk))
+	s.sender.StateChunk(validatorID, s.ctx.ChainID, requestID, chunk)
+}
+
+// SendAppRequest sends an AppRequest message to each of [nodeIDs]
+func (s *Sender) SendAppRequest(nodeIDs ids.ShortSet, requestID uint32, appRequestBytes []byte) error {
+	s.ctx.Log.Verbo("Sending AppRequest to validators %v. RequestID: %d", nodeIDs, requestID)
+
+	// Don't modify the set passed in by the VM
+	validatorIDs := ids.ShortSet{}
+	currentDeadline := time.Time{}
+	for nodeID := range nodeIDs {
+		vID := nodeID // Prevent overwrite in next loop iteration
+		// Sending an AppRequest to myself will always fail
+		if vID == s.ctx.NodeID {
+			go s.router.AppRequestFailed(vID, s.ctx.ChainID, requestID)
+			continue
+		}
+
+		deadline := s.timeouts.RegisterAppRequest(vID, s.ctx.ChainID, requestID, func() {
+			s.router.AppRequestFailed(vID, s.ctx.ChainID, requestID)
+		})
+		if deadline.After(currentDeadline) {
+			currentDeadline = deadline
+		}
+		validatorIDs.Add(vID)
+	}
+
+	if validatorIDs.Len() > 0 {
+		s.sender.AppRequest(validatorIDs, s.ctx.ChainID, requestID, currentDeadline, appRequestBytes)
+	}
+	return nil
+}
+
+// SendAppResponse sends an AppResponse message, in response to an AppRequest
+// message, to the specified node
+func (s *Sender) SendAppResponse(nodeID ids.ShortID, requestID uint32, appResponseBytes []byte) error {
+	s.ctx.Log.Verbo("Sending AppResponse to validator %s. RequestID: %d. Size: %d", nodeID, requestID, len(appResponseBytes))
+	s.sender.AppResponse(nodeID, s.ctx.ChainID, requestID, appResponseBytes)
+	return nil
+}
+
+// SendAppGossip gossips the provided message to a sample of the network
+func (s *Sender) SendAppGossip(appGossipBytes []byte) error {
+	s.ctx.Log.Verbo("Gossiping AppGossip message. Size: %d", len(appGossipBytes))
+	s.sender.AppGossip(s.ctx.ChainID, appGossipBytes)
+	return nil
+}
+
+// SendAppGossipSpecific gossips the provided message to each of [nodeIDs]
+func (s *Sender) SendAppGossipSpecific(nodeIDs ids.ShortSet, appGossipBytes []byte) error {
+	s.ctx.Log.Verbo("Gossiping AppGossip message to validators %v. Size: %d", nodeIDs, len(appGossipBytes))
+
+	// Gossiping to myself is a no-op
+	validatorIDs := ids.ShortSet{}
+	validatorIDs.Union(nodeIDs)
+	validatorIDs.Remove(s.ctx.NodeID)
+	if validatorIDs.Len() > 0 {
+		s.sender.AppGossipSpecific(validatorIDs, s.ctx.ChainID, appGossipBytes)
+	}
+	return nil
+}
+
 // Gossip the provided container
 func (s *Sender) Gossip(containerID ids.ID, container []byte) {
 	s.ctx.Log.Verbo("Gossiping %s", containerID)
@@ -56,7 +56,7 @@ func TestTimeout(t *testing.T) {
 	go tm.Dispatch()
 
 	chainRouter := router.ChainRouter{}
-	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Second, ids.Set{}, nil)
+	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Second, ids.Set{}, nil, nil)
 
 	sender := Sender{}
 	sender.Initialize(snow.DefaultContextTest(), &ExternalSenderTest{}, &chainRouter, &tm)
@@ -106,6 +106,82 @@ func TestTimeout(t *testing.T) {
 	}
 }
 
+func TestAppRequestTimeout(t *testing.T) {
+	vdrs := validators.NewSet()
+	benchlist := benchlist.NewNoBenchlist()
+	tm := timeout.Manager{}
+	err := tm.Initialize(&timer.AdaptiveTimeoutConfig{
+		InitialTimeout: time.Millisecond,
+		MinimumTimeout: time.Millisecond,
+		MaximumTimeout: 10 * time.Second,
+		TimeoutInc:     2 * time.Millisecond,
+		TimeoutDec:     time.Millisecond,
+		Namespace:      "",
+		Registerer:     prometheus.NewRegistry(),
+	}, benchlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go tm.Dispatch()
+
+	chainRouter := router.ChainRouter{}
+	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Second, ids.Set{}, nil, nil)
+
+	sender := Sender{}
+	sender.Initialize(snow.DefaultContextTest(), &ExternalSenderTest{}, &chainRouter, &tm)
+
+	engine := common.EngineTest{T: t}
+	engine.Default(true)
+	engine.CantConnected = false
+
+	engine.ContextF = snow.DefaultContextTest
+
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+
+	failedVDRs := ids.ShortSet{}
+	lock := sync.Mutex{}
+	engine.AppRequestFailedF = func(validatorID ids.ShortID, _ uint32) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		failedVDRs.Add(validatorID)
+		wg.Done()
+		return nil
+	}
+
+	handler := router.Handler{}
+	handler.Initialize(
+		&engine,
+		vdrs,
+		nil,
+		1,
+		router.DefaultMaxNonStakerPendingMsgs,
+		router.DefaultStakerPortion,
+		router.DefaultStakerPortion,
+		"",
+		prometheus.NewRegistry(),
+	)
+	go handler.Dispatch()
+
+	chainRouter.AddChain(&handler)
+
+	vdrIDs := ids.ShortSet{}
+	vdrIDs.Add(ids.ShortID{255})
+	vdrIDs.Add(ids.ShortID{254})
+	vdrIDs.Add(sender.ctx.NodeID) // Requests to myself fail immediately
+
+	if err := sender.SendAppRequest(vdrIDs, 0, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	wg.Wait()
+
+	if !failedVDRs.Equals(vdrIDs) {
+		t.Fatalf("App request timeouts should have fired")
+	}
+}
+
 func TestReliableMessages(t *testing.T) {
 	vdrs := validators.NewSet()
 	benchlist := benchlist.NewNoBenchlist()
@@ -125,7 +201,7 @@ func TestReliableMessages(t *testing.T) {
 	go tm.Dispatch()
 
 	chainRouter := router.ChainRouter{}
-	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Second, ids.Set{}, nil)
+	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Second, ids.Set{}, nil, nil)
 
 	sender := Sender{}
 	sender.Initialize(snow.DefaultContextTest(), &ExternalSenderTest{}, &chainRouter, &tm)
@@ -205,7 +281,7 @@ func TestReliableMessagesToMyself(t *testing.T) {
 	go tm.Dispatch()
 
 	chainRouter := router.ChainRouter{}
-	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Second, ids.Set{}, nil)
+	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &tm, time.Hour, time.Second, ids.Set{}, nil, nil)
 
 	sender := Sender{}
 	sender.Initialize(snow.DefaultContextTest(), &ExternalSenderTest{}, &chainRouter, &tm)
@@ -20,6 +20,9 @@ type ExternalSenderTest struct {
 	CantGetAncestors, CantMultiPut,
 	CantGet, CantPut,
 	CantPullQuery, CantPushQuery, CantChits,
+	CantGetStateSummary, CantStateSummary,
+	CantGetStateChunk, CantStateChunk,
+	CantAppRequest, CantAppResponse, CantAppGossip, CantAppGossipSpecific,
 	CantGossip bool
 
 	GetAcceptedFrontierF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time)
@@ -38,6 +41,17 @@ type ExternalSenderTest struct {
 	PullQueryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
 	ChitsF     func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes []ids.ID)
 
+	GetStateSummaryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time)
+	StateSummaryF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+	GetStateChunkF   func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, summaryID ids.ID, index uint32)
+	StateChunkF      func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte)
+
+	AppRequestF  func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, appRequestBytes []byte)
+	AppResponseF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, appResponseBytes []byte)
+	AppGossipF   func(chainID ids.ID, appGossipBytes []byte)
+
+	AppGossipSpecificF func(validatorIDs ids.ShortSet, chainID ids.ID, appGossipBytes []byte)
+
 	GossipF func(chainID ids.ID, containerID ids.ID, container []byte)
 }
 
@@ -59,6 +73,16 @@ func (s *ExternalSenderTest) Default(cant bool) {
 	s.CantPushQuery = cant
 	s.CantChits = cant
 
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
+	s.CantGetStateChunk = cant
+	s.CantStateChunk = cant
+
+	s.CantAppRequest = cant
+	s.CantAppResponse = cant
+	s.CantAppGossip = cant
+	s.CantAppGossipSpecific = cant
+
 	s.CantGossip = cant
 }
 
@@ -216,6 +240,118 @@ func (s *ExternalSenderTest) Chits(vdr ids.ShortID, chainID ids.ID, requestID ui
 	}
 }
 
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) GetStateSummary(vdrs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) {
+	switch {
+	case s.GetStateSummaryF != nil:
+		s.GetStateSummaryF(vdrs, chainID, requestID, deadline)
+	case s.CantGetStateSummary && s.T != nil:
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	case s.CantGetStateSummary && s.B != nil:
+		s.B.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) StateSummary(vdr ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	switch {
+	case s.StateSummaryF != nil:
+		s.StateSummaryF(vdr, chainID, requestID, summary)
+	case s.CantStateSummary && s.T != nil:
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	case s.CantStateSummary && s.B != nil:
+		s.B.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
+// GetStateChunk calls GetStateChunkF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) GetStateChunk(vdr ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, summaryID ids.ID, index uint32) {
+	switch {
+	case s.GetStateChunkF != nil:
+		s.GetStateChunkF(vdr, chainID, requestID, deadline, summaryID, index)
+	case s.CantGetStateChunk && s.T != nil:
+		s.T.Fatalf("Unexpectedly called GetStateChunk")
+	case s.CantGetStateChunk && s.B != nil:
+		s.B.Fatalf("Unexpectedly called GetStateChunk")
+	}
+}
+
+// StateChunk calls StateChunkF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) StateChunk(vdr ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	switch {
+	case s.StateChunkF != nil:
+		s.StateChunkF(vdr, chainID, requestID, chunk)
+	case s.CantStateChunk && s.T != nil:
+		s.T.Fatalf("Unexpectedly called StateChunk")
+	case s.CantStateChunk && s.B != nil:
+		s.B.Fatalf("Unexpectedly called StateChunk")
+	}
+}
+
+// AppRequest calls AppRequestF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) AppRequest(vdrs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, appRequestBytes []byte) {
+	switch {
+	case s.AppRequestF != nil:
+		s.AppRequestF(vdrs, chainID, requestID, deadline, appRequestBytes)
+	case s.CantAppRequest && s.T != nil:
+		s.T.Fatalf("Unexpectedly called AppRequest")
+	case s.CantAppRequest && s.B != nil:
+		s.B.Fatalf("Unexpectedly called AppRequest")
+	}
+}
+
+// AppResponse calls AppResponseF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) AppResponse(vdr ids.ShortID, chainID ids.ID, requestID uint32, appResponseBytes []byte) {
+	switch {
+	case s.AppResponseF != nil:
+		s.AppResponseF(vdr, chainID, requestID, appResponseBytes)
+	case s.CantAppResponse && s.T != nil:
+		s.T.Fatalf("Unexpectedly called AppResponse")
+	case s.CantAppResponse && s.B != nil:
+		s.B.Fatalf("Unexpectedly called AppResponse")
+	}
+}
+
+// AppGossip calls AppGossipF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) AppGossip(chainID ids.ID, appGossipBytes []byte) {
+	switch {
+	case s.AppGossipF != nil:
+		s.AppGossipF(chainID, appGossipBytes)
+	case s.CantAppGossip && s.T != nil:
+		s.T.Fatalf("Unexpectedly called AppGossip")
+	case s.CantAppGossip && s.B != nil:
+		s.B.Fatalf("Unexpectedly called AppGossip")
+	}
+}
+
+// AppGossipSpecific calls AppGossipSpecificF if it was initialized. If it
+// wasn't initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *ExternalSenderTest) AppGossipSpecific(vdrs ids.ShortSet, chainID ids.ID, appGossipBytes []byte) {
+	switch {
+	case s.AppGossipSpecificF != nil:
+		s.AppGossipSpecificF(vdrs, chainID, appGossipBytes)
+	case s.CantAppGossipSpecific && s.T != nil:
+		s.T.Fatalf("Unexpectedly called AppGossipSpecific")
+	case s.CantAppGossipSpecific && s.B != nil:
+		s.B.Fatalf("Unexpectedly called AppGossipSpecific")
+	}
+}
+
 // Gossip calls GossipF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
@@ -74,3 +74,50 @@ func TestManagerCancel(t *testing.T) {
 		t.Fatalf("Should have cancelled the function")
 	}
 }
+
+func TestManagerCancelAppRequest(t *testing.T) {
+	manager := Manager{}
+	benchlist := benchlist.NewNoBenchlist()
+	err := manager.Initialize(&timer.AdaptiveTimeoutConfig{
+		InitialTimeout: time.Millisecond,
+		MinimumTimeout: time.Millisecond,
+		MaximumTimeout: 10 * time.Second,
+		TimeoutInc:     2 * time.Millisecond,
+		TimeoutDec:     time.Millisecond,
+		Namespace:      "",
+		Registerer:     prometheus.NewRegistry(),
+	}, benchlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go manager.Dispatch()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	fired := new(bool)
+
+	// App requests and engine requests with the same ID shouldn't collide
+	manager.Register(ids.ShortID{}, ids.ID{}, 0, true, 0, func() { *fired = true })
+	manager.RegisterAppRequest(ids.ShortID{}, ids.ID{}, 0, wg.Done)
+
+	manager.Cancel(ids.ShortID{}, ids.ID{}, 0)
+
+	wg.Wait()
+
+	if *fired {
+		t.Fatalf("Should have cancelled the function")
+	}
+
+	manager.RegisterAppRequest(ids.ShortID{}, ids.ID{}, 1, func() { *fired = true })
+	manager.CancelAppRequest(ids.ShortID{}, ids.ID{}, 1)
+
+	wg.Add(1)
+	manager.RegisterAppRequest(ids.ShortID{}, ids.ID{}, 2, wg.Done)
+
+	wg.Wait()
+
+	if *fired {
+		t.Fatalf("Should have cancelled the app request")
+	}
+}
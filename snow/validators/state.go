@@ -0,0 +1,21 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// State allows the lookup of the validator sets of subnets at the heights of
+// the P-chain. Unlike a Set, which only holds the current validators, it lets
+// nodes that agreed on a P-chain height agree on the validators at it.
+type State interface {
+	// GetCurrentHeight returns the height of the last accepted block of the
+	// P-chain
+	GetCurrentHeight() (uint64, error)
+
+	// GetValidatorSet returns the weight of each validator of [subnetID] once
+	// the P-chain block at [height] was accepted
+	GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error)
+}
@@ -0,0 +1,13 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+// Compressor compresses and decompresses messages
+type Compressor interface {
+	// Compress [msg] and return the compressed bytes
+	Compress(msg []byte) ([]byte, error)
+	// Decompress [msg] and return the decompressed bytes. Returns an error if
+	// [msg] decompresses to more bytes than this compressor allows.
+	Decompress(msg []byte) ([]byte, error)
+}
@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+var (
+	errMsgTooLarge             = errors.New("msg too large to be compressed")
+	errDecompressedMsgTooLarge = errors.New("msg decompresses to more bytes than allowed")
+)
+
+// gzipCompressor compresses messages with gzip
+type gzipCompressor struct {
+	// Max number of bytes a message may have before compression or after
+	// decompression
+	maxSize int64
+}
+
+// NewGzipCompressor returns a compressor that uses gzip and that refuses to
+// compress or decompress messages of more than [maxSize] bytes
+func NewGzipCompressor(maxSize int64) Compressor {
+	return &gzipCompressor{maxSize: maxSize}
+}
+
+// Compress implements the Compressor interface
+func (g *gzipCompressor) Compress(msg []byte) ([]byte, error) {
+	if int64(len(msg)) > g.maxSize {
+		return nil, fmt.Errorf("%w: %d > %d", errMsgTooLarge, len(msg), g.maxSize)
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress implements the Compressor interface
+func (g *gzipCompressor) Decompress(msg []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Read at most one byte more than allowed, so that a message that
+	// decompresses to too many bytes is detected without decompressing all of
+	// it
+	decompressed, err := ioutil.ReadAll(io.LimitReader(reader, g.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(decompressed)) > g.maxSize {
+		return nil, fmt.Errorf("%w: > %d", errDecompressedMsgTooLarge, g.maxSize)
+	}
+	return decompressed, nil
+}
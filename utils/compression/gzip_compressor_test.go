@@ -0,0 +1,61 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compression
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestGzipCompressDecompress(t *testing.T) {
+	compressor := NewGzipCompressor(1024)
+
+	msg := bytes.Repeat([]byte{1, 2, 3, 4}, 256)
+	compressed, err := compressor.Compress(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(compressed) >= len(msg) {
+		t.Fatalf("expected %d bytes to compress but got %d bytes", len(msg), len(compressed))
+	}
+
+	decompressed, err := compressor.Decompress(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(msg, decompressed) {
+		t.Fatal("decompressed msg doesn't match the original msg")
+	}
+}
+
+func TestGzipCompressTooLarge(t *testing.T) {
+	compressor := NewGzipCompressor(1024)
+
+	if _, err := compressor.Compress(make([]byte, 1025)); !errors.Is(err, errMsgTooLarge) {
+		t.Fatalf("expected %s but got %v", errMsgTooLarge, err)
+	}
+}
+
+func TestGzipDecompressTooLarge(t *testing.T) {
+	// A msg that compresses to a few bytes but decompresses to more than the
+	// max size must be rejected
+	compressed, err := NewGzipCompressor(1 << 20).Compress(make([]byte, 1<<20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compressor := NewGzipCompressor(1024)
+	if _, err := compressor.Decompress(compressed); !errors.Is(err, errDecompressedMsgTooLarge) {
+		t.Fatalf("expected %s but got %v", errDecompressedMsgTooLarge, err)
+	}
+}
+
+func TestGzipDecompressInvalid(t *testing.T) {
+	compressor := NewGzipCompressor(1024)
+
+	if _, err := compressor.Decompress([]byte{1, 2, 3}); err == nil {
+		t.Fatal("expected invalid msg to fail decompression")
+	}
+}
@@ -30,6 +30,16 @@ const (
 	GetAncestorsMsg
 	MultiPutMsg
 	GetAncestorsFailedMsg
+	GetStateSummaryMsg
+	StateSummaryMsg
+	GetStateSummaryFailedMsg
+	GetStateChunkMsg
+	StateChunkMsg
+	GetStateChunkFailedMsg
+	AppRequestMsg
+	AppResponseMsg
+	AppRequestFailedMsg
+	AppGossipMsg
 )
 
 func (t MsgType) String() string {
@@ -76,6 +86,26 @@ func (t MsgType) String() string {
 		return "Notify Message"
 	case GossipMsg:
 		return "Gossip Message"
+	case GetStateSummaryMsg:
+		return "Get State Summary Message"
+	case StateSummaryMsg:
+		return "State Summary Message"
+	case GetStateSummaryFailedMsg:
+		return "Get State Summary Failed Message"
+	case GetStateChunkMsg:
+		return "Get State Chunk Message"
+	case StateChunkMsg:
+		return "State Chunk Message"
+	case GetStateChunkFailedMsg:
+		return "Get State Chunk Failed Message"
+	case AppRequestMsg:
+		return "App Request Message"
+	case AppResponseMsg:
+		return "App Response Message"
+	case AppRequestFailedMsg:
+		return "App Request Failed Message"
+	case AppGossipMsg:
+		return "App Gossip Message"
 	default:
 		return fmt.Sprintf("Unknown Message Type: %d", t)
 	}
@@ -32,6 +32,8 @@ func (m defaultMap) get(key uint64, defaultVal uint64) uint64 {
 //
 // Sampling is performed in O(count) time and O(count) space.
 type uniformReplacer struct {
+	// Source of the draws. If nil, the global source is used.
+	rng    *rand.Rand
 	length uint64
 	drawn  defaultMap
 }
@@ -61,7 +63,7 @@ func (s *uniformReplacer) Sample(count int) ([]uint64, error) {
 	for i := 0; i < count; i++ {
 		// We don't use a cryptographically secure source of randomness here, as
 		// there's no need to ensure a truly random sampling.
-		draw := uint64(rand.Int63n(int64(s.length-uint64(i)))) + uint64(i) // #nosec G404
+		draw := uint64(s.int63n(int64(s.length-uint64(i)))) + uint64(i)
 
 		ret := s.drawn.get(draw, draw)
 		s.drawn[draw] = s.drawn.get(uint64(i), uint64(i))
@@ -71,3 +73,10 @@ func (s *uniformReplacer) Sample(count int) ([]uint64, error) {
 
 	return results, nil
 }
+
+func (s *uniformReplacer) int63n(n int64) int64 {
+	if s.rng != nil {
+		return s.rng.Int63n(n)
+	}
+	return rand.Int63n(n) // #nosec G404
+}
@@ -3,6 +3,10 @@
 
 package sampler
 
+import (
+	"math/rand"
+)
+
 // WeightedWithoutReplacement defines how to sample weight without replacement.
 // Note that the behavior is to sample the weight without replacement, not the
 // indices. So duplicate indices can be returned.
@@ -28,3 +32,13 @@ func NewBestWeightedWithoutReplacement(
 		w: NewWeighted(),
 	}
 }
+
+// NewDeterministicWeightedWithoutReplacement returns a new sampler that draws
+// its samples from [source]. Samplers with sources that were seeded the same,
+// initialized with the same weights, return the same samples.
+func NewDeterministicWeightedWithoutReplacement(source rand.Source) WeightedWithoutReplacement {
+	return &weightedWithoutReplacementGeneric{
+		u: &uniformReplacer{rng: rand.New(source)}, // #nosec G404
+		w: &weightedArray{},
+	}
+}
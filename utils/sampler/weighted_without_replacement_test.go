@@ -6,6 +6,7 @@ package sampler
 import (
 	"fmt"
 	"math"
+	"math/rand"
 	"sort"
 	"testing"
 
@@ -33,6 +34,10 @@ var (
 				},
 			},
 		},
+		{
+			name:    "deterministic",
+			sampler: NewDeterministicWeightedWithoutReplacement(rand.NewSource(0)),
+		},
 	}
 	weightedWithoutReplacementTests = []struct {
 		name string
@@ -169,3 +174,17 @@ func WeightedWithoutReplacementDistributionTest(
 		"should have selected all the elements",
 	)
 }
+
+func TestDeterministicWeightedWithoutReplacement(t *testing.T) {
+	weights := []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	samples := [2][]int{}
+	for i := range samples {
+		s := NewDeterministicWeightedWithoutReplacement(rand.NewSource(5))
+		err := s.Initialize(weights)
+		assert.NoError(t, err)
+
+		samples[i], err = s.Sample(10)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, samples[0], samples[1], "samplers with the same seed should return the same samples")
+}
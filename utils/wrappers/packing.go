@@ -335,6 +335,20 @@ func TryUnpackByte(packer *Packer) interface{} {
 	return packer.UnpackByte()
 }
 
+// TryPackBool attempts to pack the value as a bool
+func TryPackBool(packer *Packer, valIntf interface{}) {
+	if val, ok := valIntf.(bool); ok {
+		packer.PackBool(val)
+	} else {
+		packer.Add(errBadType)
+	}
+}
+
+// TryUnpackBool attempts to unpack a value as a bool
+func TryUnpackBool(packer *Packer) interface{} {
+	return packer.UnpackBool()
+}
+
 // TryPackShort attempts to pack the value as a short
 func TryPackShort(packer *Packer, valIntf interface{}) {
 	if val, ok := valIntf.(uint16); ok {
@@ -0,0 +1,79 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/pubsub"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var _ pubsub.TxParser = &VM{}
+
+// AcceptedTxs returns the accepted tx [txBytes] along with the addresses it
+// touched, which are the owners of the UTXOs it spent and of the UTXOs it
+// created. UTXOs imported from other chains aren't considered, as they aren't
+// in this chain's state.
+// Implements pubsub.TxParser
+func (vm *VM) AcceptedTxs(_ ids.ID, txBytes []byte) ([]pubsub.Tx, error) {
+	tx, err := vm.parsePrivateTx(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse tx: %w", err)
+	}
+
+	addrs := ids.ShortSet{}
+	for _, utxoID := range tx.InputUTXOs() {
+		if utxoID.Symbolic() {
+			continue
+		}
+		// The spent UTXO was removed from state when the tx was accepted, so
+		// it's read from the tx that created it.
+		parentID, outputIndex := utxoID.InputSource()
+		parent, err := vm.state.Tx(parentID)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get tx %s: %w", parentID, err)
+		}
+		parentUTXOs := parent.UTXOs()
+		if int(outputIndex) >= len(parentUTXOs) {
+			return nil, errInvalidUTXO
+		}
+		if err := addOwners(addrs, parentUTXOs[outputIndex]); err != nil {
+			return nil, err
+		}
+	}
+	for _, utxo := range tx.UTXOs() {
+		if err := addOwners(addrs, utxo); err != nil {
+			return nil, err
+		}
+	}
+
+	addrList := addrs.List()
+	addrBytes := make([][]byte, len(addrList))
+	for i, addr := range addrList {
+		addrBytes[i] = addr.Bytes()
+	}
+	return []pubsub.Tx{{
+		ID:        tx.ID(),
+		Bytes:     txBytes,
+		Addresses: addrBytes,
+	}}, nil
+}
+
+// addOwners adds the addresses that own [utxo] to [addrs]
+func addOwners(addrs ids.ShortSet, utxo *avax.UTXO) error {
+	out, ok := utxo.Out.(avax.Addressable)
+	if !ok {
+		return nil
+	}
+	for _, addrBytes := range out.Addresses() {
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return err
+		}
+		addrs.Add(addr)
+	}
+	return nil
+}
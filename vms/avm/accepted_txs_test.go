@@ -0,0 +1,45 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAcceptedTxs(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	tx := NewTx(t, genesisBytes, vm)
+	parsedTx, err := vm.Parse(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := vm.AcceptedTxs(tx.ID(), tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].ID != tx.ID() {
+		t.Fatalf("expected tx %s to be returned", tx.ID())
+	}
+	// The tx spent a genesis UTXO owned by keys[0] and created no UTXOs
+	owner := keys[0].PublicKey().Address()
+	if len(txs[0].Addresses) != 1 || !bytes.Equal(txs[0].Addresses[0], owner.Bytes()) {
+		t.Fatalf("expected the tx to have only touched %s", owner)
+	}
+}
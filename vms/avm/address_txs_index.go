@@ -0,0 +1,135 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var (
+	addressTxsPrefix = []byte("addressTxs")
+	// Key, in each (address, asset) database, of the number of indexed txs
+	addressTxsCountKey = []byte("count")
+
+	errUnexpectedCountSize = errors.New("unexpected address tx count size")
+)
+
+// addressTxsIndex records, for each address and asset, the IDs of the
+// accepted transactions that spent or created UTXOs referencing that address
+// and asset. The txs of each (address, asset) pair are numbered in the order
+// they were accepted, which lets the index be read page by page.
+type addressTxsIndex struct {
+	db database.Database
+}
+
+func newAddressTxsIndex(db database.Database) *addressTxsIndex {
+	return &addressTxsIndex{db: prefixdb.New(addressTxsPrefix, db)}
+}
+
+// Accept indexes [txID] under every (address, asset) pair referenced by
+// [spentUTXOs], the UTXOs the tx consumed, and [createdUTXOs], the UTXOs the
+// tx produced. A tx is indexed at most once per pair.
+func (i *addressTxsIndex) Accept(txID ids.ID, spentUTXOs, createdUTXOs []*avax.UTXO) error {
+	// Address --> IDs of the assets the tx touched for that address
+	addrAssets := make(map[ids.ShortID]ids.Set)
+	for _, utxos := range [][]*avax.UTXO{spentUTXOs, createdUTXOs} {
+		for _, utxo := range utxos {
+			out, ok := utxo.Out.(avax.Addressable)
+			if !ok {
+				continue
+			}
+			assetID := utxo.AssetID()
+			for _, addrBytes := range out.Addresses() {
+				addr, err := ids.ToShortID(addrBytes)
+				if err != nil {
+					return err
+				}
+				assets, exists := addrAssets[addr]
+				if !exists {
+					assets = ids.Set{}
+					addrAssets[addr] = assets
+				}
+				assets.Add(assetID)
+			}
+		}
+	}
+
+	for addr, assets := range addrAssets {
+		for assetID := range assets {
+			if err := i.add(addr, assetID, txID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// add appends [txID] to the txs of [addr] and [assetID]
+func (i *addressTxsIndex) add(addr ids.ShortID, assetID ids.ID, txID ids.ID) error {
+	db := i.pairDB(addr, assetID)
+	count, err := getCount(db)
+	if err != nil {
+		return err
+	}
+	if err := db.Put(packCount(count), txID[:]); err != nil {
+		return err
+	}
+	return db.Put(addressTxsCountKey, packCount(count+1))
+}
+
+// Read returns the IDs of up to [pageSize] txs that touched [addr] and
+// [assetID], in the order they were accepted, skipping the first [cursor].
+func (i *addressTxsIndex) Read(addr ids.ShortID, assetID ids.ID, cursor, pageSize uint64) ([]ids.ID, error) {
+	db := i.pairDB(addr, assetID)
+	iter := db.NewIteratorWithStart(packCount(cursor))
+	defer iter.Release()
+
+	txIDs := []ids.ID(nil)
+	for uint64(len(txIDs)) < pageSize && iter.Next() {
+		// The count key is the only key that isn't a tx's position
+		if len(iter.Key()) != wrappers.LongLen {
+			continue
+		}
+		txID, err := ids.ToID(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		txIDs = append(txIDs, txID)
+	}
+	return txIDs, iter.Error()
+}
+
+// pairDB returns the database holding the txs of [addr] and [assetID]
+func (i *addressTxsIndex) pairDB(addr ids.ShortID, assetID ids.ID) database.Database {
+	prefix := make([]byte, 0, len(addr)+len(assetID))
+	prefix = append(prefix, addr[:]...)
+	prefix = append(prefix, assetID[:]...)
+	return prefixdb.New(prefix, i.db)
+}
+
+func getCount(db database.Database) (uint64, error) {
+	countBytes, err := db.Get(addressTxsCountKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if len(countBytes) != wrappers.LongLen {
+		return 0, errUnexpectedCountSize
+	}
+	return binary.BigEndian.Uint64(countBytes), nil
+}
+
+func packCount(count uint64) []byte {
+	b := make([]byte, wrappers.LongLen)
+	binary.BigEndian.PutUint64(b, count)
+	return b
+}
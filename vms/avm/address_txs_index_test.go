@@ -0,0 +1,92 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func newTestUTXO(assetID ids.ID, addrs ...ids.ShortID) *avax.UTXO {
+	return &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     addrs,
+			},
+		},
+	}
+}
+
+func TestAddressTxsIndex(t *testing.T) {
+	index := newAddressTxsIndex(memdb.New())
+
+	addr0 := ids.GenerateTestShortID()
+	addr1 := ids.GenerateTestShortID()
+	assetID0 := ids.GenerateTestID()
+	assetID1 := ids.GenerateTestID()
+
+	// [addr0] spends [assetID0] to [addr1]
+	txID0 := ids.GenerateTestID()
+	if err := index.Accept(
+		txID0,
+		[]*avax.UTXO{newTestUTXO(assetID0, addr0)},
+		[]*avax.UTXO{newTestUTXO(assetID0, addr1), newTestUTXO(assetID0, addr0)},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// [addr1] receives [assetID1]
+	txID1 := ids.GenerateTestID()
+	if err := index.Accept(txID1, nil, []*avax.UTXO{newTestUTXO(assetID1, addr1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// [addr1] spends [assetID0] to itself
+	txID2 := ids.GenerateTestID()
+	if err := index.Accept(
+		txID2,
+		[]*avax.UTXO{newTestUTXO(assetID0, addr1)},
+		[]*avax.UTXO{newTestUTXO(assetID0, addr1)},
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		addr     ids.ShortID
+		assetID  ids.ID
+		cursor   uint64
+		pageSize uint64
+		expected []ids.ID
+	}{
+		{addr0, assetID0, 0, 10, []ids.ID{txID0}},
+		{addr0, assetID1, 0, 10, nil},
+		{addr1, assetID0, 0, 10, []ids.ID{txID0, txID2}},
+		{addr1, assetID0, 0, 1, []ids.ID{txID0}},
+		{addr1, assetID0, 1, 1, []ids.ID{txID2}},
+		{addr1, assetID0, 2, 1, nil},
+		{addr1, assetID1, 0, 10, []ids.ID{txID1}},
+	}
+	for i, test := range tests {
+		txIDs, err := index.Read(test.addr, test.assetID, test.cursor, test.pageSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txIDs) != len(test.expected) {
+			t.Fatalf("test %d: expected %d txs but got %d", i, len(test.expected), len(txIDs))
+		}
+		for j, txID := range txIDs {
+			if txID != test.expected[j] {
+				t.Fatalf("test %d: expected tx %s at position %d but got %s", i, test.expected[j], j, txID)
+			}
+		}
+	}
+}
@@ -42,6 +42,21 @@ func (c *Client) IssueTx(txBytes []byte) (ids.ID, error) {
 	return res.TxID, err
 }
 
+// EstimateFee returns the fee that the unsigned tx [unsignedTxBytes] must
+// currently burn, and the ID of the asset it must burn
+func (c *Client) EstimateFee(unsignedTxBytes []byte) (uint64, ids.ID, error) {
+	txStr, err := formatting.Encode(formatting.Hex, unsignedTxBytes)
+	if err != nil {
+		return 0, ids.ID{}, err
+	}
+	res := &EstimateFeeReply{}
+	err = c.requester.SendRequest("estimateFee", &EstimateFeeArgs{
+		UnsignedTx: txStr,
+		Encoding:   formatting.Hex,
+	}, res)
+	return uint64(res.Fee), res.AssetID, err
+}
+
 // GetTxStatus returns the status of [txID]
 func (c *Client) GetTxStatus(txID ids.ID) (choices.Status, error) {
 	res := &GetTxStatusReply{}
@@ -145,6 +160,19 @@ func (c *Client) GetBalance(addr string, assetID string, includePartial bool) (*
 	return res, err
 }
 
+// GetAddressTxs returns the IDs of up to [pageSize] txs that touched [assetID]
+// for [addr], skipping the first [cursor], and the cursor of the next page.
+func (c *Client) GetAddressTxs(addr string, assetID string, cursor uint64, pageSize uint64) ([]ids.ID, uint64, error) {
+	res := &GetAddressTxsReply{}
+	err := c.requester.SendRequest("getAddressTxs", &GetAddressTxsArgs{
+		JSONAddress: api.JSONAddress{Address: addr},
+		AssetID:     assetID,
+		Cursor:      cjson.Uint64(cursor),
+		PageSize:    cjson.Uint64(pageSize),
+	}, res)
+	return res.TxIDs, uint64(res.Cursor), err
+}
+
 // GetAllBalances returns all asset balances for [addr]
 func (c *Client) GetAllBalances(addr string, includePartial bool) (*GetAllBalancesReply, error) {
 	res := &GetAllBalancesReply{}
@@ -6,6 +6,7 @@ package avm
 import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
 )
 
 // ID that this VM uses when labeled
@@ -17,6 +18,15 @@ var (
 type Factory struct {
 	CreationFee uint64
 	Fee         uint64
+
+	// IndexTransactions enables the index of the txs that touched each
+	// address, which is exposed by the getAddressTxs API method
+	IndexTransactions bool
+
+	// FeeConfig is how fees respond to usage of the chain
+	FeeConfig fees.Config
+	// FeeRegistry, if non-nil, is where the VM registers its fee manager
+	FeeRegistry *fees.Registry
 }
 
 // New ...
@@ -24,5 +34,10 @@ func (f *Factory) New(*snow.Context) (interface{}, error) {
 	return &VM{
 		creationTxFee: f.CreationFee,
 		txFee:         f.Fee,
+
+		indexTransactions: f.IndexTransactions,
+
+		feeConfig:   f.FeeConfig,
+		feeRegistry: f.FeeRegistry,
 	}, nil
 }
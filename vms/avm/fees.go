@@ -0,0 +1,113 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// Fees rise above [vm.txFee] and [vm.creationTxFee] when the chain is busy.
+// Txs must burn the current fee to be put into this node's vertices, which
+// makes spamming the chain through this node expensive. Vertices don't carry a
+// timestamp and txs aren't ordered, so nodes can't agree on the fee of a tx in
+// a vertex. Txs in vertices issued by other nodes are therefore only required
+// to burn the minimum fee.
+
+var (
+	errFeeTooLow = errors.New("tx doesn't burn the current fee")
+
+	_ fees.Source = &VM{}
+)
+
+// CurrentFee implements the fees.Source interface
+func (vm *VM) CurrentFee(minFee uint64) uint64 {
+	return vm.fees.Fee(vm.clock.Time(), minFee)
+}
+
+// currentTxFee returns the fee that must currently be burned by every non-state
+// creating tx
+func (vm *VM) currentTxFee() uint64 {
+	return vm.CurrentFee(vm.txFee)
+}
+
+// currentCreationTxFee returns the fee that must currently be burned by every
+// state creating tx
+func (vm *VM) currentCreationTxFee() uint64 {
+	return vm.CurrentFee(vm.creationTxFee)
+}
+
+// currentFee returns the fee that [tx] must currently burn
+func (vm *VM) currentFee(tx UnsignedTx) uint64 {
+	if _, ok := tx.(*CreateAssetTx); ok {
+		return vm.currentCreationTxFee()
+	}
+	return vm.currentTxFee()
+}
+
+// verifyCurrentFee returns an error if [tx] doesn't burn the current fee
+func (vm *VM) verifyCurrentFee(tx UnsignedTx) error {
+	burned, err := vm.burnedAVAX(tx)
+	if err != nil {
+		return err
+	}
+	if fee := vm.currentFee(tx); burned < fee {
+		return fmt.Errorf("%w: burns %d but the current fee is %d", errFeeTooLow, burned, fee)
+	}
+	return nil
+}
+
+// burnedAVAX returns the amount of AVAX consumed, but not produced, by [tx]
+func (vm *VM) burnedAVAX(tx UnsignedTx) (uint64, error) {
+	var (
+		baseTx       *BaseTx
+		importedIns  []*avax.TransferableInput
+		exportedOuts []*avax.TransferableOutput
+	)
+	switch tx := tx.(type) {
+	case *BaseTx:
+		baseTx = tx
+	case *CreateAssetTx:
+		baseTx = &tx.BaseTx
+	case *OperationTx:
+		baseTx = &tx.BaseTx
+	case *ImportTx:
+		baseTx, importedIns = &tx.BaseTx, tx.ImportedIns
+	case *ExportTx:
+		baseTx, exportedOuts = &tx.BaseTx, tx.ExportedOuts
+	default:
+		return 0, fmt.Errorf("unknown tx type %T", tx)
+	}
+
+	consumed := uint64(0)
+	for _, ins := range [][]*avax.TransferableInput{baseTx.Ins, importedIns} {
+		for _, in := range ins {
+			if in.AssetID() != vm.ctx.AVAXAssetID {
+				continue
+			}
+			var err error
+			if consumed, err = safemath.Add64(consumed, in.Input().Amount()); err != nil {
+				return 0, err
+			}
+		}
+	}
+	produced := uint64(0)
+	for _, outs := range [][]*avax.TransferableOutput{baseTx.Outs, exportedOuts} {
+		for _, out := range outs {
+			if out.AssetID() != vm.ctx.AVAXAssetID {
+				continue
+			}
+			var err error
+			if produced, err = safemath.Add64(produced, out.Output().Amount()); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return safemath.Sub64(consumed, produced)
+}
@@ -0,0 +1,127 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// newTxBurning returns a tx that spends the genesis AVAX UTXO and burns
+// [burned] of it
+func newTxBurning(t *testing.T, genesisBytes []byte, vm *VM, burned uint64) *Tx {
+	avaxTx := GetAVAXTxFromGenesisTest(genesisBytes, t)
+
+	tx := &Tx{UnsignedTx: &BaseTx{BaseTx: avax.BaseTx{
+		NetworkID:    networkID,
+		BlockchainID: chainID,
+		Ins: []*avax.TransferableInput{{
+			UTXOID: avax.UTXOID{
+				TxID:        avaxTx.ID(),
+				OutputIndex: 2,
+			},
+			Asset: avax.Asset{ID: avaxTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt: startBalance,
+				Input: secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+		}},
+		Outs: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: avaxTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: startBalance - burned,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+				},
+			},
+		}},
+	}}}
+	if err := tx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// raiseFees makes the current fees of [vm] 1.125 times their minimum
+func raiseFees(vm *VM) {
+	now := time.Unix(1000000, 0)
+	vm.clock.Set(now)
+	config := fees.Config{
+		Window:        time.Second,
+		TargetUnits:   1,
+		MaxMultiplier: 2,
+	}
+	vm.fees = fees.NewManager(config, now)
+	vm.fees.Consume(now, 2)
+	vm.clock.Set(now.Add(time.Second))
+}
+
+func TestIssueTxBelowCurrentFee(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	raiseFees(vm)
+	if fee := vm.currentTxFee(); fee != testTxFee*9/8 {
+		t.Fatalf("expected current fee %d but got %d", testTxFee*9/8, fee)
+	}
+
+	tx := newTxBurning(t, genesisBytes, vm, testTxFee)
+	if _, err := vm.IssueTx(tx.Bytes()); !errors.Is(err, errFeeTooLow) {
+		t.Fatalf("expected %s but got %v", errFeeTooLow, err)
+	}
+	// The tx still passes consensus verification, as it burns the minimum fee
+	if err := tx.SyntacticVerify(vm.ctx, vm.codec, vm.ctx.AVAXAssetID, vm.txFee, vm.creationTxFee, len(vm.fxs)); err != nil {
+		t.Fatal(err)
+	}
+
+	tx = newTxBurning(t, genesisBytes, vm, vm.currentTxFee())
+	if _, err := vm.IssueTx(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServiceEstimateFee(t *testing.T) {
+	genesisBytes, vm, s, _ := setup(t)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	raiseFees(vm)
+	tx := newTxBurning(t, genesisBytes, vm, testTxFee)
+	unsignedTx, err := formatting.Encode(formatting.Hex, tx.UnsignedBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply := EstimateFeeReply{}
+	if err := s.EstimateFee(nil, &EstimateFeeArgs{UnsignedTx: unsignedTx, Encoding: formatting.Hex}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if uint64(reply.Fee) != testTxFee*9/8 {
+		t.Fatalf("expected fee %d but got %d", testTxFee*9/8, reply.Fee)
+	}
+	if reply.AssetID != vm.ctx.AVAXAssetID {
+		t.Fatalf("expected asset %s but got %s", vm.ctx.AVAXAssetID, reply.AssetID)
+	}
+}
@@ -31,6 +31,9 @@ const (
 
 	// Max number of addresses allowed for a single keystore user
 	maxKeystoreAddresses = 5000
+
+	// Max number of tx IDs returned by a single call to GetAddressTxs
+	maxAddressTxsPageSize = 1024
 )
 
 var (
@@ -47,6 +50,7 @@ var (
 	errNilTxID                = errors.New("nil transaction ID")
 	errNoAddresses            = errors.New("no addresses provided")
 	errNoKeys                 = errors.New("from addresses have no keys or funds")
+	errAddressTxsNotIndexed   = errors.New("this node isn't indexing the txs of addresses")
 )
 
 // Service defines the base service for the asset vm
@@ -74,6 +78,39 @@ func (service *Service) IssueTx(r *http.Request, args *api.FormattedTx, reply *a
 	return nil
 }
 
+// EstimateFeeArgs are arguments for calling EstimateFee
+type EstimateFeeArgs struct {
+	UnsignedTx string              `json:"unsignedTx"`
+	Encoding   formatting.Encoding `json:"encoding"`
+}
+
+// EstimateFeeReply is the response from calling EstimateFee
+type EstimateFeeReply struct {
+	// Amount of [AssetID] the tx must burn to be issued through this node
+	Fee     json.Uint64 `json:"fee"`
+	AssetID ids.ID      `json:"assetID"`
+}
+
+// EstimateFee returns the fee that the unsigned tx [args.UnsignedTx] must
+// currently burn. The fee rises when the chain is busy, so a tx issued later
+// may need to burn more.
+func (service *Service) EstimateFee(r *http.Request, args *EstimateFeeArgs, reply *EstimateFeeReply) error {
+	service.vm.ctx.Log.Info("AVM: EstimateFee called")
+
+	txBytes, err := formatting.Decode(args.Encoding, args.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem decoding unsigned transaction: %w", err)
+	}
+	var tx UnsignedTx
+	if _, err := service.vm.codec.Unmarshal(txBytes, &tx); err != nil {
+		return fmt.Errorf("problem parsing unsigned transaction: %w", err)
+	}
+
+	reply.Fee = json.Uint64(service.vm.currentFee(tx))
+	reply.AssetID = service.vm.ctx.AVAXAssetID
+	return nil
+}
+
 // GetTxStatusReply defines the GetTxStatus replies returned from the API
 type GetTxStatusReply struct {
 	Status choices.Status `json:"status"`
@@ -217,6 +254,61 @@ func (service *Service) GetUTXOs(r *http.Request, args *api.GetUTXOsArgs, reply
 	return nil
 }
 
+// GetAddressTxsArgs are arguments for passing into GetAddressTxs requests
+type GetAddressTxsArgs struct {
+	api.JSONAddress
+	// Number of txs to skip. Should be the [Cursor] of the previous reply.
+	Cursor json.Uint64 `json:"cursor"`
+	// Max number of txs to return. If 0 or too large, defaults to the max.
+	PageSize json.Uint64 `json:"pageSize"`
+	AssetID  string      `json:"assetID"`
+}
+
+// GetAddressTxsReply defines the GetAddressTxs replies returned from the API
+type GetAddressTxsReply struct {
+	TxIDs []ids.ID `json:"txIDs"`
+	// Cursor to pass in to fetch the next page of txs
+	Cursor json.Uint64 `json:"cursor"`
+}
+
+// GetAddressTxs returns the IDs of the accepted txs that spent or created
+// UTXOs of [args.AssetID] for [args.Address], in the order they were accepted.
+// UTXOs imported from other chains aren't considered to be spent by the
+// importing tx. Only txs accepted while the node was indexing are returned.
+func (service *Service) GetAddressTxs(_ *http.Request, args *GetAddressTxsArgs, reply *GetAddressTxsReply) error {
+	service.vm.ctx.Log.Info("AVM: GetAddressTxs called with address: %s assetID: %s cursor: %d pageSize: %d",
+		args.Address, args.AssetID, args.Cursor, args.PageSize)
+
+	if service.vm.addressTxs == nil {
+		return errAddressTxsNotIndexed
+	}
+
+	addr, err := service.vm.ParseLocalAddress(args.Address)
+	if err != nil {
+		return fmt.Errorf("problem parsing address '%s': %w", args.Address, err)
+	}
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	pageSize := uint64(args.PageSize)
+	if pageSize == 0 || pageSize > maxAddressTxsPageSize {
+		pageSize = maxAddressTxsPageSize
+	}
+
+	cursor := uint64(args.Cursor)
+	txIDs, err := service.vm.addressTxs.Read(addr, assetID, cursor, pageSize)
+	if err != nil {
+		return fmt.Errorf("problem reading the txs of address %s: %w", args.Address, err)
+	}
+
+	reply.TxIDs = txIDs
+	reply.Cursor = json.Uint64(cursor + uint64(len(txIDs)))
+	return nil
+}
+
 // GetAssetDescriptionArgs are arguments for passing into GetAssetDescription requests
 type GetAssetDescriptionArgs struct {
 	AssetID string `json:"assetID"`
@@ -472,11 +564,12 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 		return err
 	}
 
+	creationTxFee := service.vm.currentCreationTxFee()
 	amountsSpent, ins, keys, err := service.vm.Spend(
 		utxos,
 		kc,
 		map[ids.ID]uint64{
-			service.vm.ctx.AVAXAssetID: service.vm.creationTxFee,
+			service.vm.ctx.AVAXAssetID: creationTxFee,
 		},
 	)
 	if err != nil {
@@ -484,11 +577,11 @@ func (service *Service) CreateAsset(r *http.Request, args *CreateAssetArgs, repl
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > service.vm.creationTxFee {
+	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > creationTxFee {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.creationTxFee,
+				Amt: amountSpent - creationTxFee,
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -627,11 +720,12 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 		return err
 	}
 
+	creationTxFee := service.vm.currentCreationTxFee()
 	amountsSpent, ins, keys, err := service.vm.Spend(
 		utxos,
 		kc,
 		map[ids.ID]uint64{
-			service.vm.ctx.AVAXAssetID: service.vm.creationTxFee,
+			service.vm.ctx.AVAXAssetID: creationTxFee,
 		},
 	)
 	if err != nil {
@@ -639,11 +733,11 @@ func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > service.vm.creationTxFee {
+	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > creationTxFee {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.creationTxFee,
+				Amt: amountSpent - creationTxFee,
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -1019,7 +1113,8 @@ func (service *Service) SendMultiple(r *http.Request, args *SendMultipleArgs, re
 		amountsWithFee[assetID] = amount
 	}
 
-	amountWithFee, err := safemath.Add64(amounts[service.vm.ctx.AVAXAssetID], service.vm.txFee)
+	txFee := service.vm.currentTxFee()
+	amountWithFee, err := safemath.Add64(amounts[service.vm.ctx.AVAXAssetID], txFee)
 	if err != nil {
 		return fmt.Errorf("problem calculating required spend amount: %w", err)
 	}
@@ -1126,11 +1221,12 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 		return err
 	}
 
+	txFee := service.vm.currentTxFee()
 	amountsSpent, ins, keys, err := service.vm.Spend(
 		feeUTXOs,
 		feeKc,
 		map[ids.ID]uint64{
-			service.vm.ctx.AVAXAssetID: service.vm.txFee,
+			service.vm.ctx.AVAXAssetID: txFee,
 		},
 	)
 	if err != nil {
@@ -1138,11 +1234,11 @@ func (service *Service) Mint(r *http.Request, args *MintArgs, reply *api.JSONTxI
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > service.vm.txFee {
+	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > txFee {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.txFee,
+				Amt: amountSpent - txFee,
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -1243,11 +1339,12 @@ func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *api.J
 		return err
 	}
 
+	txFee := service.vm.currentTxFee()
 	amountsSpent, ins, secpKeys, err := service.vm.Spend(
 		utxos,
 		kc,
 		map[ids.ID]uint64{
-			service.vm.ctx.AVAXAssetID: service.vm.txFee,
+			service.vm.ctx.AVAXAssetID: txFee,
 		},
 	)
 	if err != nil {
@@ -1255,11 +1352,11 @@ func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *api.J
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > service.vm.txFee {
+	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > txFee {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.txFee,
+				Amt: amountSpent - txFee,
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -1359,11 +1456,12 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 		return err
 	}
 
+	txFee := service.vm.currentTxFee()
 	amountsSpent, ins, secpKeys, err := service.vm.Spend(
 		feeUTXOs,
 		feeKc,
 		map[ids.ID]uint64{
-			service.vm.ctx.AVAXAssetID: service.vm.txFee,
+			service.vm.ctx.AVAXAssetID: txFee,
 		},
 	)
 	if err != nil {
@@ -1371,11 +1469,11 @@ func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *api.J
 	}
 
 	outs := []*avax.TransferableOutput{}
-	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > service.vm.txFee {
+	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent > txFee {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: service.vm.ctx.AVAXAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: amountSpent - service.vm.txFee,
+				Amt: amountSpent - txFee,
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -1479,13 +1577,14 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 	ins := []*avax.TransferableInput{}
 	keys := [][]*crypto.PrivateKeySECP256K1R{}
 
-	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent < service.vm.txFee {
+	txFee := service.vm.currentTxFee()
+	if amountSpent := amountsSpent[service.vm.ctx.AVAXAssetID]; amountSpent < txFee {
 		var localAmountsSpent map[ids.ID]uint64
 		localAmountsSpent, ins, keys, err = service.vm.Spend(
 			utxos,
 			kc,
 			map[ids.ID]uint64{
-				service.vm.ctx.AVAXAssetID: service.vm.txFee - amountSpent,
+				service.vm.ctx.AVAXAssetID: txFee - amountSpent,
 			},
 		)
 		if err != nil {
@@ -1502,7 +1601,7 @@ func (service *Service) Import(_ *http.Request, args *ImportArgs, reply *api.JSO
 
 	// Because we ensured that we had enough inputs for the fee, we can
 	// safely just remove it without concern for underflow.
-	amountsSpent[service.vm.ctx.AVAXAssetID] -= service.vm.txFee
+	amountsSpent[service.vm.ctx.AVAXAssetID] -= txFee
 
 	keys = append(keys, importKeys...)
 
@@ -1623,14 +1722,15 @@ func (service *Service) Export(_ *http.Request, args *ExportArgs, reply *api.JSO
 	}
 
 	amounts := map[ids.ID]uint64{}
+	txFee := service.vm.currentTxFee()
 	if assetID == service.vm.ctx.AVAXAssetID {
-		amountWithFee, err := safemath.Add64(uint64(args.Amount), service.vm.txFee)
+		amountWithFee, err := safemath.Add64(uint64(args.Amount), txFee)
 		if err != nil {
 			return fmt.Errorf("problem calculating required spend amount: %w", err)
 		}
 		amounts[service.vm.ctx.AVAXAssetID] = amountWithFee
 	} else {
-		amounts[service.vm.ctx.AVAXAssetID] = service.vm.txFee
+		amounts[service.vm.ctx.AVAXAssetID] = txFee
 		amounts[assetID] = uint64(args.Amount)
 	}
 
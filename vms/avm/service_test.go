@@ -1588,3 +1588,61 @@ func TestImportAVAX(t *testing.T) {
 		t.Fatalf("Failed to import AVAX due to %s", err)
 	}
 }
+
+func TestServiceGetAddressTxs(t *testing.T) {
+	genesisBytes, vm, s, _ := setup(t)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.ctx.Lock.Unlock()
+	}()
+
+	addr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	avaxTx := GetAVAXTxFromGenesisTest(genesisBytes, t)
+	args := &GetAddressTxsArgs{
+		JSONAddress: api.JSONAddress{Address: addr},
+		AssetID:     avaxTx.ID().String(),
+	}
+	reply := &GetAddressTxsReply{}
+	if err := s.GetAddressTxs(nil, args, reply); err != errAddressTxsNotIndexed {
+		t.Fatalf("expected %s but got %s", errAddressTxsNotIndexed, err)
+	}
+
+	vm.addressTxs = newAddressTxsIndex(vm.db)
+
+	tx := NewTx(t, genesisBytes, vm)
+	parsedTx, err := vm.Parse(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.GetAddressTxs(nil, args, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 1 || reply.TxIDs[0] != tx.ID() {
+		t.Fatalf("expected tx %s to be indexed but got %v", tx.ID(), reply.TxIDs)
+	}
+	if reply.Cursor != 1 {
+		t.Fatalf("expected cursor 1 but got %d", reply.Cursor)
+	}
+
+	// The next page should be empty
+	args.Cursor = reply.Cursor
+	reply = &GetAddressTxsReply{}
+	if err := s.GetAddressTxs(nil, args, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 0 || reply.Cursor != 1 {
+		t.Fatalf("expected an empty page at cursor 1 but got %v at cursor %d", reply.TxIDs, reply.Cursor)
+	}
+}
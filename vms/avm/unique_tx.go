@@ -117,6 +117,9 @@ func (tx *UniqueTx) Accept() error {
 
 	defer tx.vm.db.Abort()
 
+	// UTXOs spent by this tx. Only populated if txs are being indexed.
+	spentUTXOs := []*avax.UTXO(nil)
+
 	// Remove spent utxos
 	for _, utxo := range tx.InputUTXOs() {
 		if utxo.Symbolic() {
@@ -124,6 +127,14 @@ func (tx *UniqueTx) Accept() error {
 			continue
 		}
 		utxoID := utxo.InputID()
+		if tx.vm.addressTxs != nil {
+			spentUTXO, err := tx.vm.state.UTXO(utxoID)
+			if err != nil {
+				tx.vm.ctx.Log.Error("Failed to fetch utxo %s due to %s", utxoID, err)
+				return err
+			}
+			spentUTXOs = append(spentUTXOs, spentUTXO)
+		}
 		if err := tx.vm.state.SpendUTXO(utxoID); err != nil {
 			tx.vm.ctx.Log.Error("Failed to spend utxo %s due to %s", utxoID, err)
 			return err
@@ -131,13 +142,21 @@ func (tx *UniqueTx) Accept() error {
 	}
 
 	// Add new utxos
-	for _, utxo := range tx.UTXOs() {
+	createdUTXOs := tx.UTXOs()
+	for _, utxo := range createdUTXOs {
 		if err := tx.vm.state.FundUTXO(utxo); err != nil {
 			tx.vm.ctx.Log.Error("Failed to fund utxo %s due to %s", utxo.InputID(), err)
 			return err
 		}
 	}
 
+	if tx.vm.addressTxs != nil {
+		if err := tx.vm.addressTxs.Accept(tx.ID(), spentUTXOs, createdUTXOs); err != nil {
+			tx.vm.ctx.Log.Error("Failed to index tx %s due to %s", tx.txID, err)
+			return err
+		}
+	}
+
 	if err := tx.setStatus(choices.Accepted); err != nil {
 		tx.vm.ctx.Log.Error("Failed to accept tx %s due to %s", tx.txID, err)
 		return err
@@ -156,6 +175,7 @@ func (tx *UniqueTx) Accept() error {
 	}
 
 	tx.vm.ctx.Log.Verbo("Accepted Tx: %s", txID)
+	tx.vm.fees.Consume(tx.vm.clock.Time(), 1)
 
 	tx.vm.pubsub.Publish("accepted", txID)
 	tx.vm.walletService.decided(txID)
@@ -33,6 +33,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
 	"github.com/ava-labs/avalanchego/vms/components/verify"
 	"github.com/ava-labs/avalanchego/vms/nftfx"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
@@ -92,6 +93,17 @@ type VM struct {
 	// fee that must be burned by every non-state creating transaction
 	txFee uint64
 
+	// Raises the fees that txs issued through this node must burn when the
+	// chain is busy
+	feeConfig   fees.Config
+	fees        *fees.Manager
+	feeRegistry *fees.Registry
+
+	// true iff the txs that touched each address should be indexed
+	indexTransactions bool
+	// Accepted txs by address and asset. nil if [indexTransactions] is false
+	addressTxs *addressTxsIndex
+
 	// Asset ID --> Bit set with fx IDs the asset supports
 	assetToFxCache *cache.LRU
 
@@ -204,6 +216,15 @@ func (vm *VM) Initialize(
 		uniqueTx: &cache.EvictableLRU{Size: txCacheSize},
 	}
 
+	if vm.indexTransactions {
+		vm.addressTxs = newAddressTxsIndex(vm.db)
+	}
+
+	vm.fees = fees.NewManager(vm.feeConfig, vm.clock.Time())
+	if vm.feeRegistry != nil {
+		vm.feeRegistry.Register(ctx.ChainID, vm)
+	}
+
 	if err := vm.initAliases(genesisBytes); err != nil {
 		return err
 	}
@@ -363,6 +384,9 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 	if err := tx.verifyWithoutCacheWrites(); err != nil {
 		return ids.ID{}, err
 	}
+	if err := vm.verifyCurrentFee(tx.UnsignedTx); err != nil {
+		return ids.ID{}, err
+	}
 	vm.issueTx(tx)
 	return tx.ID(), nil
 }
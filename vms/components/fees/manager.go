@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fees
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// MultiplierDenominator is the denominator of fee multipliers. A
+	// multiplier of [MultiplierDenominator] leaves fees at their minimum.
+	MultiplierDenominator = 1000
+
+	// The usage of a window changes the multiplier by at most
+	// 1/[changeDenominator] of its value
+	changeDenominator = 8
+)
+
+// Config of how fees respond to usage
+type Config struct {
+	// Usage is measured over consecutive windows of this length
+	Window time.Duration
+
+	// Units of usage per window at which fees stay the same. Fees rise after a
+	// window with more usage and fall after a window with less. If 0, fees
+	// always stay at their minimum.
+	TargetUnits uint64
+
+	// Fees never rise above this multiple of their minimum
+	MaxMultiplier uint64
+}
+
+// Manager tracks recent usage of a chain, by the clock of the node, and the
+// fees that result from it. Fees are never below the minimums they're
+// calculated from. Manager is safe for concurrent use.
+type Manager struct {
+	lock   sync.Mutex
+	config Config
+	state  State
+}
+
+// NewManager returns a manager, with fees at their minimum, whose first window
+// starts at [now]
+func NewManager(config Config, now time.Time) *Manager {
+	return &Manager{
+		config: config,
+		state:  NewState(now),
+	}
+}
+
+// Consume records that [units] were used at time [now]
+func (m *Manager) Consume(now time.Time, units uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.state.Advance(m.config, now)
+	m.state.Consume(units)
+}
+
+// Fee returns the fee at time [now] of a tx whose minimum fee is [minFee]
+func (m *Manager) Fee(now time.Time, minFee uint64) uint64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.state.Advance(m.config, now)
+	return m.state.Fee(minFee)
+}
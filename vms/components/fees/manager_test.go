@@ -0,0 +1,83 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fees
+
+import (
+	"testing"
+	"time"
+)
+
+var testConfig = Config{
+	Window:        10 * time.Second,
+	TargetUnits:   100,
+	MaxMultiplier: 2,
+}
+
+func TestManagerStartsAtMinimum(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewManager(testConfig, now)
+	if fee := m.Fee(now, 1000); fee != 1000 {
+		t.Fatalf("expected fee 1000 but got %d", fee)
+	}
+}
+
+func TestManagerRisesAndFalls(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewManager(testConfig, now)
+
+	// Usage at the target doesn't change the fee
+	m.Consume(now, testConfig.TargetUnits)
+	now = now.Add(testConfig.Window)
+	if fee := m.Fee(now, 1000); fee != 1000 {
+		t.Fatalf("expected fee 1000 but got %d", fee)
+	}
+
+	// Usage of twice the target raises the fee by 1/8
+	m.Consume(now, 2*testConfig.TargetUnits)
+	now = now.Add(testConfig.Window)
+	if fee := m.Fee(now, 1000); fee != 1125 {
+		t.Fatalf("expected fee 1125 but got %d", fee)
+	}
+
+	// Usage above twice the target raises the fee by at most 1/8
+	m.Consume(now, 10*testConfig.TargetUnits)
+	now = now.Add(testConfig.Window)
+	if fee := m.Fee(now, 1000); fee != 1265 {
+		t.Fatalf("expected fee 1265 but got %d", fee)
+	}
+
+	// A window without usage lowers the fee by 1/8
+	now = now.Add(testConfig.Window)
+	if fee := m.Fee(now, 1000); fee != 1107 {
+		t.Fatalf("expected fee 1107 but got %d", fee)
+	}
+
+	// Fees never fall below their minimum
+	now = now.Add(100 * testConfig.Window)
+	if fee := m.Fee(now, 1000); fee != 1000 {
+		t.Fatalf("expected fee 1000 but got %d", fee)
+	}
+}
+
+func TestManagerMaxMultiplier(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewManager(testConfig, now)
+	for i := 0; i < 100; i++ {
+		m.Consume(now, 2*testConfig.TargetUnits)
+		now = now.Add(testConfig.Window)
+	}
+	if fee := m.Fee(now, 1000); fee != 2000 {
+		t.Fatalf("expected fee 2000 but got %d", fee)
+	}
+}
+
+func TestManagerDisabled(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewManager(Config{}, now)
+	m.Consume(now, 1000000)
+	now = now.Add(time.Hour)
+	if fee := m.Fee(now, 1000); fee != 1000 {
+		t.Fatalf("expected fee 1000 but got %d", fee)
+	}
+}
@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fees
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Source reports the current fees of a chain
+type Source interface {
+	// CurrentFee returns the fee that a tx whose minimum fee is [minFee] must
+	// currently burn
+	CurrentFee(minFee uint64) uint64
+}
+
+// Registry holds the fee source of each chain whose fees respond to usage,
+// so that the current fees can be reported outside of the chains' VMs.
+// Registry is safe for concurrent use.
+type Registry struct {
+	lock    sync.RWMutex
+	sources map[ids.ID]Source
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{sources: make(map[ids.ID]Source)}
+}
+
+// Register [source] as the fee source of the chain [chainID]
+func (r *Registry) Register(chainID ids.ID, source Source) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.sources[chainID] = source
+}
+
+// Sources returns the fee source of each registered chain
+func (r *Registry) Sources() map[ids.ID]Source {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	sources := make(map[ids.ID]Source, len(r.sources))
+	for chainID, source := range r.sources {
+		sources[chainID] = source
+	}
+	return sources
+}
@@ -0,0 +1,99 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fees
+
+import (
+	"math"
+	"time"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// State of the fees of a chain. The state only changes through Advance and
+// Consume, so a chain that advances it with the timestamps of its blocks, and
+// consumes the units used by each block, gets the same fees on every node.
+type State struct {
+	// Start of the current window, in Unix time in nanoseconds
+	WindowStart int64 `serialize:"true"`
+	// Units consumed in the current window
+	WindowUnits uint64 `serialize:"true"`
+	// Fees are their minimum times [Multiplier]/[MultiplierDenominator]
+	Multiplier uint64 `serialize:"true"`
+}
+
+// NewState returns a state, with fees at their minimum, whose first window
+// starts at [start]
+func NewState(start time.Time) State {
+	return State{
+		WindowStart: start.UnixNano(),
+		Multiplier:  MultiplierDenominator,
+	}
+}
+
+// Advance ends every window that ended by [now]
+func (s *State) Advance(config Config, now time.Time) {
+	if config.TargetUnits == 0 || config.Window <= 0 {
+		return
+	}
+	window := int64(config.Window)
+	nowNano := now.UnixNano()
+	for s.WindowStart+window <= nowNano {
+		s.Multiplier = s.nextMultiplier(config)
+		s.WindowStart += window
+		s.WindowUnits = 0
+
+		// Fees stay at their minimum over windows without usage, so skip to
+		// the window that contains [now]
+		if s.Multiplier == MultiplierDenominator {
+			elapsed := nowNano - s.WindowStart
+			s.WindowStart += elapsed - elapsed%window
+			return
+		}
+	}
+}
+
+// Consume records that [units] were used in the current window
+func (s *State) Consume(units uint64) {
+	windowUnits, err := safemath.Add64(s.WindowUnits, units)
+	if err != nil {
+		windowUnits = math.MaxUint64
+	}
+	s.WindowUnits = windowUnits
+}
+
+// Fee returns the fee, in the current window, of a tx whose minimum fee is
+// [minFee]
+func (s *State) Fee(minFee uint64) uint64 {
+	fee, err := safemath.Mul64(minFee, s.Multiplier)
+	if err != nil {
+		return math.MaxUint64
+	}
+	return fee / MultiplierDenominator
+}
+
+// nextMultiplier returns the multiplier after the current window ends
+func (s *State) nextMultiplier(config Config) uint64 {
+	target := config.TargetUnits
+	step := s.Multiplier / changeDenominator
+	switch {
+	case s.WindowUnits > target:
+		excess := safemath.Min64(s.WindowUnits-target, target)
+		multiplier := s.Multiplier + step*excess/target
+		maxMultiplier, err := safemath.Mul64(config.MaxMultiplier, MultiplierDenominator)
+		if err == nil && multiplier > maxMultiplier {
+			multiplier = maxMultiplier
+		}
+		if multiplier < MultiplierDenominator {
+			multiplier = MultiplierDenominator
+		}
+		return multiplier
+	default:
+		shortfall := target - s.WindowUnits
+		decrease := step * shortfall / target
+		if s.Multiplier-decrease < MultiplierDenominator {
+			return MultiplierDenominator
+		}
+		return s.Multiplier - decrease
+	}
+}
@@ -0,0 +1,170 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package statetree keeps the state of the processing blocks of a Snowman
+// chain as a tree of versiondb diffs keyed by block ID.
+package statetree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	errUnknownState      = errors.New("unknown block state")
+	errDuplicateState    = errors.New("block already has a state")
+	errParentNotAccepted = errors.New("block's parent isn't the last accepted block")
+)
+
+// Tree is the state of the processing blocks of a chain.
+//
+// The state of the last accepted block is the base database. The state of a
+// processing block is a diff on top of the state of its parent, so verifying a
+// block only needs the state of its parent, however deep the processing chain
+// is. Accepting a block commits its diff into the base database and rebases its
+// children on the base, so the diffs never nest deeper than the processing
+// blocks. Rejecting a block drops its diff and the diffs of its descendants.
+//
+// Tree isn't safe for concurrent use.
+type Tree struct {
+	base         *versiondb.Database
+	lastAccepted ids.ID
+	diffs        map[ids.ID]*diff
+}
+
+// diff is the state of a processing block
+type diff struct {
+	db       *versiondb.Database
+	parentID ids.ID
+	children ids.Set
+}
+
+// New returns a tree where [base] is the state of the last accepted block,
+// [lastAccepted], and no block is processing
+func New(base *versiondb.Database, lastAccepted ids.ID) *Tree {
+	return &Tree{
+		base:         base,
+		lastAccepted: lastAccepted,
+		diffs:        make(map[ids.ID]*diff),
+	}
+}
+
+// LastAccepted returns the ID of the block whose state is the base database
+func (t *Tree) LastAccepted() ids.ID { return t.lastAccepted }
+
+// Len returns the number of processing blocks with a state
+func (t *Tree) Len() int { return len(t.diffs) }
+
+// Get returns the state the chain has if [blkID] is accepted. Returns false if
+// [blkID] is neither the last accepted block nor a processing block with a
+// state.
+func (t *Tree) Get(blkID ids.ID) (database.Database, bool) {
+	if blkID == t.lastAccepted {
+		return t.base, true
+	}
+	d, ok := t.diffs[blkID]
+	if !ok {
+		return nil, false
+	}
+	return d.db, true
+}
+
+// NewDiff returns an empty diff on top of the state of [parentID]. The diff
+// isn't part of the tree until it's added with Add.
+func (t *Tree) NewDiff(parentID ids.ID) (*versiondb.Database, error) {
+	parentState, ok := t.Get(parentID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownState, parentID)
+	}
+	return versiondb.New(parentState), nil
+}
+
+// Add sets [db], a diff on top of the state of [parentID], as the state of
+// [blkID]. [db] is rebased on the current state of [parentID], which may have
+// been accepted since [db] was created. If [blkID] already has a state, because
+// it's verified again, [db] replaces it and the children of [blkID] are rebased
+// on [db].
+func (t *Tree) Add(blkID, parentID ids.ID, db *versiondb.Database) error {
+	if blkID == t.lastAccepted {
+		return fmt.Errorf("%w: %s", errDuplicateState, blkID)
+	}
+	parentState, ok := t.Get(parentID)
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownState, parentID)
+	}
+	if err := db.SetDatabase(parentState); err != nil {
+		return err
+	}
+
+	if d, ok := t.diffs[blkID]; ok {
+		for childID := range d.children {
+			if err := t.diffs[childID].db.SetDatabase(db); err != nil {
+				return err
+			}
+		}
+		if d.db != db {
+			d.db.Abort()
+		}
+		d.db = db
+		return nil
+	}
+
+	if parent, ok := t.diffs[parentID]; ok {
+		parent.children.Add(blkID)
+	}
+	t.diffs[blkID] = &diff{
+		db:       db,
+		parentID: parentID,
+	}
+	return nil
+}
+
+// Accept writes the state of [blkID] into the base database and makes it the
+// last accepted block. The parent of [blkID] must be the last accepted block.
+// The caller is responsible for committing the base database.
+func (t *Tree) Accept(blkID ids.ID) error {
+	d, ok := t.diffs[blkID]
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownState, blkID)
+	}
+	if d.parentID != t.lastAccepted {
+		return fmt.Errorf("%w: %s", errParentNotAccepted, blkID)
+	}
+	if err := d.db.Commit(); err != nil {
+		return err
+	}
+	for childID := range d.children {
+		if err := t.diffs[childID].db.SetDatabase(t.base); err != nil {
+			return err
+		}
+	}
+	delete(t.diffs, blkID)
+	t.lastAccepted = blkID
+	return nil
+}
+
+// Reject drops the states of [blkID] and its descendants. It's a no-op if
+// [blkID] has no state.
+func (t *Tree) Reject(blkID ids.ID) {
+	d, ok := t.diffs[blkID]
+	if !ok {
+		return
+	}
+	if parent, ok := t.diffs[d.parentID]; ok {
+		parent.children.Remove(blkID)
+	}
+	t.drop(blkID, d)
+}
+
+// drop removes [d], the state of [blkID], and the states of its descendants
+func (t *Tree) drop(blkID ids.ID, d *diff) {
+	for childID := range d.children {
+		t.drop(childID, t.diffs[childID])
+	}
+	d.db.Abort()
+	delete(t.diffs, blkID)
+}
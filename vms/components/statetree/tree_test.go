@@ -0,0 +1,196 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statetree
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// add adds the state of [blkID] on top of [parentID], where [key] is set to
+// [value]
+func add(t *testing.T, tree *Tree, blkID, parentID ids.ID, key, value string) *versiondb.Database {
+	db, err := tree.NewDiff(parentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte(key), []byte(value)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Add(blkID, parentID, db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// assertValue fails unless [key] maps to [value] in the state of [blkID]. An
+// empty [value] means [key] isn't set.
+func assertValue(t *testing.T, tree *Tree, blkID ids.ID, key, value string) {
+	db, ok := tree.Get(blkID)
+	if !ok {
+		t.Fatalf("block %s has no state", blkID)
+	}
+	assertDBValue(t, db, key, value)
+}
+
+func assertDBValue(t *testing.T, db database.Database, key, value string) {
+	got, err := db.Get([]byte(key))
+	switch {
+	case value == "" && err == database.ErrNotFound:
+	case err != nil:
+		t.Fatalf("couldn't get %q: %s", key, err)
+	case !bytes.Equal(got, []byte(value)):
+		t.Fatalf("expected %q to be %q but got %q", key, value, got)
+	}
+}
+
+func TestTreeChain(t *testing.T) {
+	base := versiondb.New(memdb.New())
+	genesisID, blk1ID, blk2ID := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	tree := New(base, genesisID)
+
+	add(t, tree, blk1ID, genesisID, "a", "1")
+	add(t, tree, blk2ID, blk1ID, "b", "2")
+
+	assertValue(t, tree, blk1ID, "a", "1")
+	assertValue(t, tree, blk1ID, "b", "")
+	assertValue(t, tree, blk2ID, "a", "1")
+	assertValue(t, tree, blk2ID, "b", "2")
+	assertDBValue(t, base, "a", "")
+
+	if err := tree.Accept(blk2ID); !errors.Is(err, errParentNotAccepted) {
+		t.Fatalf("expected %s but got %v", errParentNotAccepted, err)
+	}
+	if err := tree.Accept(blk1ID); err != nil {
+		t.Fatal(err)
+	}
+	if lastAccepted := tree.LastAccepted(); lastAccepted != blk1ID {
+		t.Fatalf("expected the last accepted block to be %s but got %s", blk1ID, lastAccepted)
+	}
+	assertDBValue(t, base, "a", "1")
+	assertDBValue(t, base, "b", "")
+
+	// The child was rebased on the base database
+	blk2DB, _ := tree.Get(blk2ID)
+	if got := blk2DB.(*versiondb.Database).GetDatabase(); got != base {
+		t.Fatal("the child of the accepted block wasn't rebased on the base database")
+	}
+	assertValue(t, tree, blk2ID, "b", "2")
+
+	if err := tree.Accept(blk2ID); err != nil {
+		t.Fatal(err)
+	}
+	assertDBValue(t, base, "a", "1")
+	assertDBValue(t, base, "b", "2")
+	if n := tree.Len(); n != 0 {
+		t.Fatalf("expected no processing states but got %d", n)
+	}
+}
+
+func TestTreeReject(t *testing.T) {
+	base := versiondb.New(memdb.New())
+	genesisID := ids.GenerateTestID()
+	tree := New(base, genesisID)
+
+	// blk1 and blk2 conflict. blk3 builds on blk2.
+	blk1ID, blk2ID, blk3ID := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	add(t, tree, blk1ID, genesisID, "a", "1")
+	add(t, tree, blk2ID, genesisID, "a", "2")
+	add(t, tree, blk3ID, blk2ID, "b", "3")
+
+	assertValue(t, tree, blk1ID, "a", "1")
+	assertValue(t, tree, blk3ID, "a", "2")
+
+	if err := tree.Accept(blk1ID); err != nil {
+		t.Fatal(err)
+	}
+	tree.Reject(blk2ID)
+	if _, ok := tree.Get(blk2ID); ok {
+		t.Fatal("the rejected block still has a state")
+	}
+	if _, ok := tree.Get(blk3ID); ok {
+		t.Fatal("the descendant of the rejected block still has a state")
+	}
+	if n := tree.Len(); n != 0 {
+		t.Fatalf("expected no processing states but got %d", n)
+	}
+	assertDBValue(t, base, "a", "1")
+	assertDBValue(t, base, "b", "")
+
+	// Rejecting a block without a state is a no-op
+	tree.Reject(blk2ID)
+}
+
+func TestTreeAddRebases(t *testing.T) {
+	base := versiondb.New(memdb.New())
+	genesisID, blk1ID, blk2ID := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	tree := New(base, genesisID)
+
+	add(t, tree, blk1ID, genesisID, "a", "1")
+
+	// The diff of blk2 is created before its parent is accepted, but added
+	// after
+	blk2DB, err := tree.NewDiff(blk1ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Accept(blk1ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Add(blk2ID, blk1ID, blk2DB); err != nil {
+		t.Fatal(err)
+	}
+	if got := blk2DB.GetDatabase(); got != base {
+		t.Fatal("the diff wasn't rebased on the state of its parent")
+	}
+	assertValue(t, tree, blk2ID, "a", "1")
+}
+
+func TestTreeUnknownState(t *testing.T) {
+	tree := New(versiondb.New(memdb.New()), ids.GenerateTestID())
+
+	blkID := ids.GenerateTestID()
+	if _, err := tree.NewDiff(blkID); !errors.Is(err, errUnknownState) {
+		t.Fatalf("expected %s but got %v", errUnknownState, err)
+	}
+	if err := tree.Add(ids.GenerateTestID(), blkID, versiondb.New(memdb.New())); !errors.Is(err, errUnknownState) {
+		t.Fatalf("expected %s but got %v", errUnknownState, err)
+	}
+	if err := tree.Accept(blkID); !errors.Is(err, errUnknownState) {
+		t.Fatalf("expected %s but got %v", errUnknownState, err)
+	}
+	if _, ok := tree.Get(blkID); ok {
+		t.Fatal("unknown block has a state")
+	}
+}
+
+func TestTreeVerifyAgain(t *testing.T) {
+	genesisID, blk1ID, blk2ID := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	tree := New(versiondb.New(memdb.New()), genesisID)
+
+	add(t, tree, blk1ID, genesisID, "a", "1")
+	add(t, tree, blk2ID, blk1ID, "b", "2")
+
+	// Verifying blk1 again replaces its state
+	blk1DB := add(t, tree, blk1ID, genesisID, "a", "1")
+	if n := tree.Len(); n != 2 {
+		t.Fatalf("expected 2 processing states but got %d", n)
+	}
+	blk2DB, _ := tree.Get(blk2ID)
+	if got := blk2DB.(*versiondb.Database).GetDatabase(); got != blk1DB {
+		t.Fatal("the child wasn't rebased on the new state")
+	}
+	assertValue(t, tree, blk2ID, "a", "1")
+	assertValue(t, tree, blk2ID, "b", "2")
+
+	if err := tree.Add(genesisID, blk1ID, blk1DB); !errors.Is(err, errDuplicateState) {
+		t.Fatalf("expected %s but got %v", errDuplicateState, err)
+	}
+}
@@ -20,7 +20,8 @@ type Abort struct {
 //
 // The parent block must be a proposal
 //
-// This function also sets onAcceptDB database if the verification passes.
+// This function also adds the state of the chain if this block is accepted
+// to the VM's states if the verification passes.
 func (a *Abort) Verify() error {
 	parent, ok := a.parentBlock().(*ProposalBlock)
 	// Abort is a decision, so its parent must be a proposal
@@ -35,10 +36,13 @@ func (a *Abort) Verify() error {
 		return errInvalidBlockType
 	}
 
-	a.onAcceptDB, a.onAcceptFunc = parent.onAbort()
+	onAcceptDB, onAcceptFunc := parent.onAbort()
+	if err := a.vm.states.Add(a.ID(), a.parentStateID(), onAcceptDB); err != nil {
+		return fmt.Errorf("failed to add state: %w", err)
+	}
+	a.onAcceptFunc = onAcceptFunc
 
 	a.vm.currentBlocks[a.ID()] = a
-	a.parentBlock().addChild(a)
 	return nil
 }
 
@@ -0,0 +1,203 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/pubsub"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var _ pubsub.TxParser = &VM{}
+
+// AcceptedTxs returns the txs decided by the accepted block [blkID] along
+// with the addresses each tx touched, which are:
+// * The addresses that signed the tx
+// * The owners of the outputs the tx created or exported
+// * The owners of the stake and rewards of the staker the tx added or removed
+// The tx of a proposal block is returned when either of its options is
+// accepted, with the addresses touched by that option.
+// Implements pubsub.TxParser
+func (vm *VM) AcceptedTxs(blkID ids.ID, _ []byte) ([]pubsub.Tx, error) {
+	blk, err := vm.getBlock(blkID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+
+	var (
+		txs       []*Tx
+		committed = true
+	)
+	switch blk := blk.(type) {
+	case *StandardBlock:
+		txs = blk.Txs
+	case *AtomicBlock:
+		txs = []*Tx{&blk.Tx}
+	case *Commit, *Abort:
+		// The tx in a proposal block is decided when the commit or abort block
+		// after it is accepted. An aborted tx may still change the UTXO set,
+		// such as by returning the stake of a staker, so it's reported too.
+		parent, ok := blk.parentBlock().(*ProposalBlock)
+		if !ok {
+			return nil, fmt.Errorf("expected parent of block %s to be a proposal block", blkID)
+		}
+		txs = []*Tx{&parent.Tx}
+		_, committed = blk.(*Commit)
+	}
+
+	acceptedTxs := make([]pubsub.Tx, len(txs))
+	for i, tx := range txs {
+		addrs, err := vm.txAddresses(tx, committed)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get addresses of tx %s: %w", tx.ID(), err)
+		}
+		addrList := addrs.List()
+		addrBytes := make([][]byte, len(addrList))
+		for j, addr := range addrList {
+			addrBytes[j] = addr.Bytes()
+		}
+		acceptedTxs[i] = pubsub.Tx{
+			ID:        tx.ID(),
+			Bytes:     tx.Bytes(),
+			Addresses: addrBytes,
+		}
+	}
+	return acceptedTxs, nil
+}
+
+// txAddresses returns the addresses [tx] touched. If [tx] is a proposal tx,
+// [committed] is true if the tx was committed rather than aborted.
+func (vm *VM) txAddresses(tx *Tx, committed bool) (ids.ShortSet, error) {
+	addrs := ids.ShortSet{}
+
+	// The UTXOs the tx spent may no longer exist, so the addresses that spent
+	// them are recovered from the tx's signatures
+	hash := hashing.ComputeHash256(tx.UnsignedBytes())
+	for _, credIntf := range tx.Creds {
+		cred, ok := credIntf.(*secp256k1fx.Credential)
+		if !ok {
+			continue
+		}
+		for _, sig := range cred.Sigs {
+			pk, err := vm.factory.RecoverHashPublicKey(hash, sig[:])
+			if err != nil {
+				return nil, err
+			}
+			addrs.Add(pk.Address())
+		}
+	}
+
+	var (
+		outs   [][]*avax.TransferableOutput
+		owners []interface{}
+	)
+	switch utx := tx.UnsignedTx.(type) {
+	case *UnsignedAddValidatorTx:
+		// If the staker isn't added, its stake is returned right away and it
+		// isn't rewarded
+		outs = [][]*avax.TransferableOutput{utx.Outs, utx.Stake}
+		if committed {
+			owners = []interface{}{utx.RewardsOwner}
+		}
+	case *UnsignedAddDelegatorTx:
+		outs = [][]*avax.TransferableOutput{utx.Outs, utx.Stake}
+		if committed {
+			owners = []interface{}{utx.RewardsOwner}
+		}
+	case *UnsignedAddSubnetValidatorTx:
+		outs = [][]*avax.TransferableOutput{utx.Outs}
+	case *UnsignedCreateChainTx:
+		outs = [][]*avax.TransferableOutput{utx.Outs}
+	case *UnsignedCreateSubnetTx:
+		outs = [][]*avax.TransferableOutput{utx.Outs}
+		owners = []interface{}{utx.Owner}
+	case *UnsignedImportTx:
+		outs = [][]*avax.TransferableOutput{utx.Outs}
+	case *UnsignedExportTx:
+		outs = [][]*avax.TransferableOutput{utx.Outs, utx.ExportedOutputs}
+	case *UnsignedRewardValidatorTx:
+		// The stake of the removed staker is returned to the owners given in
+		// the tx that added the staker. The staker is only rewarded if the tx
+		// is committed.
+		stakerTx, err := vm.getStakerTx(utx.TxID)
+		if err != nil {
+			return nil, err
+		}
+		switch stakerUTX := stakerTx.UnsignedTx.(type) {
+		case *UnsignedAddValidatorTx:
+			outs = [][]*avax.TransferableOutput{stakerUTX.Stake}
+			if committed {
+				owners = []interface{}{stakerUTX.RewardsOwner}
+			}
+		case *UnsignedAddDelegatorTx:
+			outs = [][]*avax.TransferableOutput{stakerUTX.Stake}
+			if committed {
+				owners = []interface{}{stakerUTX.RewardsOwner}
+			}
+		}
+	}
+
+	for _, outList := range outs {
+		for _, out := range outList {
+			owners = append(owners, out.Out)
+		}
+	}
+	for _, owner := range owners {
+		addressable, ok := owner.(avax.Addressable)
+		if !ok {
+			continue
+		}
+		for _, addrBytes := range addressable.Addresses() {
+			addr, err := ids.ToShortID(addrBytes)
+			if err != nil {
+				return nil, err
+			}
+			addrs.Add(addr)
+		}
+	}
+	return addrs, nil
+}
+
+// getStakerTx returns the accepted tx with ID [txID]
+func (vm *VM) getStakerTx(txID ids.ID) (*Tx, error) {
+	txBytes, err := vm.getTx(vm.DB, txID)
+	if err == database.ErrNotFound {
+		// The txs that added the genesis validators aren't stored
+		return vm.getGenesisStakerTx(txID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get tx %s: %w", txID, err)
+	}
+	tx := &Tx{}
+	if _, err := vm.codec.Unmarshal(txBytes, tx); err != nil {
+		return nil, fmt.Errorf("couldn't parse tx %s: %w", txID, err)
+	}
+	if err := tx.Sign(vm.codec, nil); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// getGenesisStakerTx returns the tx with ID [txID] that added a genesis
+// validator
+func (vm *VM) getGenesisStakerTx(txID ids.ID) (*Tx, error) {
+	genesis := &Genesis{}
+	if _, err := GenesisCodec.Unmarshal(vm.genesisBytes, genesis); err != nil {
+		return nil, fmt.Errorf("couldn't parse genesis: %w", err)
+	}
+	if err := genesis.Initialize(); err != nil {
+		return nil, err
+	}
+	for _, tx := range genesis.Validators {
+		if tx.ID() == txID {
+			return tx, nil
+		}
+	}
+	return nil, fmt.Errorf("couldn't get tx %s: %w", txID, database.ErrNotFound)
+}
@@ -0,0 +1,247 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/timestampvm"
+)
+
+func TestAcceptedTxs(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	signer := testSubnet1ControlKeys[0]
+	tx, err := vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		timestampvm.ID,
+		nil,
+		"name",
+		[]*crypto.PrivateKeySECP256K1R{signer, testSubnet1ControlKeys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.mempool.IssueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := vm.AcceptedTxs(blk.ID(), blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("expected 1 accepted tx but got %d", len(txs))
+	}
+	if txs[0].ID != tx.ID() || !bytes.Equal(txs[0].Bytes, tx.Bytes()) {
+		t.Fatalf("wrong accepted tx")
+	}
+	signerAddr := signer.PublicKey().Address()
+	found := false
+	for _, addr := range txs[0].Addresses {
+		if bytes.Equal(addr, signerAddr.Bytes()) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the tx to have touched signer %s", signerAddr)
+	}
+}
+
+func TestAcceptedTxsCommit(t *testing.T) { testAcceptedProposalTx(t, true) }
+
+func TestAcceptedTxsAbort(t *testing.T) { testAcceptedProposalTx(t, false) }
+
+// testAcceptedProposalTx accepts a proposal block that adds a validator,
+// followed by its commit block if [commit] and by its abort block otherwise.
+// The validator's rewards owner is only touched if the tx is committed.
+func testAcceptedProposalTx(t *testing.T, commit bool) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	startTime := defaultGenesisTime.Add(syncBound).Add(1 * time.Second)
+	endTime := startTime.Add(defaultMinStakingDuration)
+	key, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID := key.PublicKey().Address()
+	rewardKey, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewardAddr := rewardKey.PublicKey().Address()
+	tx, err := vm.newAddValidatorTx(
+		vm.minValidatorStake,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		nodeID,
+		rewardAddr,
+		PercentDenominator,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		ids.ShortEmpty, // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.mempool.IssueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	block := blk.(*ProposalBlock)
+	options, err := block.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var option snowman.Block
+	for _, opt := range options {
+		if _, isCommit := opt.(*Commit); isCommit == commit {
+			option = opt
+		}
+	}
+	if err := block.Accept(); err != nil {
+		t.Fatal(err)
+	} else if err := option.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := option.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := vm.AcceptedTxs(option.ID(), option.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("expected 1 decided tx but got %d", len(txs))
+	}
+	if txs[0].ID != tx.ID() {
+		t.Fatalf("expected tx %s but got %s", tx.ID(), txs[0].ID)
+	}
+	touched := ids.ShortSet{}
+	for _, addrBytes := range txs[0].Addresses {
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		touched.Add(addr)
+	}
+	// The stake is owned by the signer, and is returned right away if the tx
+	// is aborted
+	if signerAddr := keys[0].PublicKey().Address(); !touched.Contains(signerAddr) {
+		t.Fatalf("expected the tx to have touched signer %s", signerAddr)
+	}
+	if touched.Contains(rewardAddr) != commit {
+		t.Fatalf("expected the tx to have touched the rewards owner only if it was committed")
+	}
+}
+
+// Ensure that when a genesis validator isn't rewarded, the return of its stake
+// is still reported
+func TestAcceptedTxsRewardAbort(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	// Advance the chain's time to when the genesis validators leave
+	vm.clock.Set(defaultValidateEndTime)
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	options, err := blk.(*ProposalBlock).Options()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := options[0].Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := options[0].Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Remove a genesis validator without rewarding it
+	if blk, err = vm.BuildBlock(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	block := blk.(*ProposalBlock)
+	if options, err = block.Options(); err != nil {
+		t.Fatal(err)
+	}
+	abort, ok := options[1].(*Abort)
+	if !ok {
+		t.Fatal(errShouldPrefCommit)
+	} else if err := abort.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := abort.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	rewardTx, ok := block.Tx.UnsignedTx.(*UnsignedRewardValidatorTx)
+	if !ok {
+		t.Fatalf("expected a reward tx but got %T", block.Tx.UnsignedTx)
+	}
+	stakerTx, err := vm.getStakerTx(rewardTx.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stakerAddr := stakerTx.UnsignedTx.(*UnsignedAddValidatorTx).Validator.ID()
+
+	txs, err := vm.AcceptedTxs(abort.ID(), abort.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].ID != block.Tx.ID() {
+		t.Fatalf("expected the aborted reward tx to be reported")
+	}
+	found := false
+	for _, addr := range txs[0].Addresses {
+		if bytes.Equal(addr, stakerAddr.Bytes()) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the tx to have touched the owner %s of the returned stake", stakerAddr)
+	}
+}
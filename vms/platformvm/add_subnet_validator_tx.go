@@ -181,7 +181,11 @@ func (tx *UnsignedAddSubnetValidatorTx) SemanticVerify(
 		}
 
 		// Verify the flowcheck
-		if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, baseTxCreds, vm.txFee, vm.Ctx.AVAXAssetID); err != nil {
+		fee, err := vm.feeOf(db, vm.txFee)
+		if err != nil {
+			return nil, nil, nil, nil, tempError{err}
+		}
+		if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, baseTxCreds, fee, vm.Ctx.AVAXAssetID); err != nil {
 			return nil, nil, nil, nil, err
 		}
 	}
@@ -202,6 +206,10 @@ func (tx *UnsignedAddSubnetValidatorTx) SemanticVerify(
 	if err := vm.enqueueStaker(onCommitDB, tx.Validator.Subnet, stx); err != nil {
 		return nil, nil, nil, nil, tempError{err}
 	}
+	// Record the usage of the chain
+	if err := vm.consumeFee(onCommitDB); err != nil {
+		return nil, nil, nil, nil, tempError{err}
+	}
 
 	onAbortDB := versiondb.New(db)
 	// Consume the UTXOS
@@ -212,6 +220,10 @@ func (tx *UnsignedAddSubnetValidatorTx) SemanticVerify(
 	if err := vm.produceOutputs(onAbortDB, txID, tx.Outs); err != nil {
 		return nil, nil, nil, nil, tempError{err}
 	}
+	// Record the usage of the chain
+	if err := vm.consumeFee(onAbortDB); err != nil {
+		return nil, nil, nil, nil, tempError{err}
+	}
 
 	return onCommitDB, onAbortDB, nil, nil, nil
 }
@@ -232,7 +244,7 @@ func (vm *VM) newAddSubnetValidatorTx(
 	keys []*crypto.PrivateKeySECP256K1R, // Keys to use for adding the validator
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
-	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.txFee, changeAddr)
+	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.currentTxFee(), changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
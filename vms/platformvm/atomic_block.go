@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/vms/components/core"
@@ -59,7 +58,8 @@ func (ab *AtomicBlock) conflicts(s ids.Set) bool {
 //
 // The parent block must be a proposal
 //
-// This function also sets onAcceptDB database if the verification passes.
+// This function also adds the state of the chain if this block is accepted
+// to the VM's states if the verification passes.
 func (ab *AtomicBlock) Verify() error {
 	tx, ok := ab.Tx.UnsignedTx.(UnsignedAtomicTx)
 	if !ok {
@@ -74,27 +74,29 @@ func (ab *AtomicBlock) Verify() error {
 
 	// AtomicBlock is not a modifier on a proposal block, so its parent must be
 	// a decision.
-	parent, ok := parentBlock.(decision)
-	if !ok {
+	if _, ok := parentBlock.(decision); !ok {
 		return errInvalidBlockType
 	}
 
-	pdb := parent.onAccept()
-
-	ab.onAcceptDB = versiondb.New(pdb)
-	if err := tx.SemanticVerify(ab.vm, ab.onAcceptDB, &ab.Tx); err != nil {
+	onAcceptDB, err := ab.vm.states.NewDiff(ab.parentStateID())
+	if err != nil {
+		return err
+	}
+	if err := tx.SemanticVerify(ab.vm, onAcceptDB, &ab.Tx); err != nil {
 		ab.vm.droppedTxCache.Put(ab.Tx.ID(), err.Error()) // cache tx as dropped
 		return fmt.Errorf("tx %s failed semantic verification: %w", tx.ID(), err)
 	}
 	txBytes := ab.Tx.Bytes()
-	if err := ab.vm.putTx(ab.onAcceptDB, ab.Tx.ID(), txBytes); err != nil {
+	if err := ab.vm.putTx(onAcceptDB, ab.Tx.ID(), txBytes); err != nil {
 		return fmt.Errorf("failed to put tx %s: %w", tx.ID(), err)
-	} else if err := ab.vm.putStatus(ab.onAcceptDB, ab.Tx.ID(), Committed); err != nil {
+	} else if err := ab.vm.putStatus(onAcceptDB, ab.Tx.ID(), Committed); err != nil {
 		return fmt.Errorf("failed to put status of tx %s: %w", tx.ID(), err)
 	}
 
+	if err := ab.vm.states.Add(ab.ID(), ab.parentStateID(), onAcceptDB); err != nil {
+		return fmt.Errorf("failed to add state: %w", err)
+	}
 	ab.vm.currentBlocks[ab.ID()] = ab
-	ab.parentBlock().addChild(ab)
 	return nil
 }
 
@@ -112,10 +114,19 @@ func (ab *AtomicBlock) Accept() error {
 	}
 
 	// Update the state of the chain in the database
-	if err := ab.onAcceptDB.Commit(); err != nil {
-		return fmt.Errorf("failed to commit onAcceptDB for block %s: %w", ab.ID(), err)
+	if err := ab.vm.states.Accept(ab.ID()); err != nil {
+		return fmt.Errorf("failed to accept state of block %s: %w", ab.ID(), err)
+	}
+	if err := ab.vm.putStateDiff(ab.Height(), ab.Height()); err != nil {
+		return fmt.Errorf("failed to put state diff for block %s: %w", ab.ID(), err)
 	}
 
+	if err := ab.vm.recordStateSummaryPreimages(); err != nil {
+		return fmt.Errorf("failed to record state summary preimages for block %s: %w", ab.ID(), err)
+	}
+	if err := ab.vm.maybeBuildStateSummary(&ab.CommonBlock); err != nil {
+		return fmt.Errorf("failed to start state summary for block %s: %w", ab.ID(), err)
+	}
 	batch, err := ab.vm.DB.CommitBatch()
 	if err != nil {
 		return fmt.Errorf("failed to commit VM's database for block %s: %w", ab.ID(), err)
@@ -125,10 +136,8 @@ func (ab *AtomicBlock) Accept() error {
 	if err := tx.Accept(ab.vm.Ctx, batch); err != nil {
 		return fmt.Errorf("failed to atomically accept tx %s in block %s: %w", tx.ID(), ab.ID(), err)
 	}
+	ab.vm.setAcceptedHeight(ab.Height())
 
-	for _, child := range ab.children {
-		child.setBaseDatabase(ab.vm.DB)
-	}
 	if ab.onAcceptFunc != nil {
 		if err := ab.onAcceptFunc(); err != nil {
 			return fmt.Errorf("failed to execute onAcceptFunc of %s: %w", ab.ID(), err)
@@ -55,8 +55,8 @@ func (c *Client) ImportKey(user api.UserPass, privateKey string) (string, error)
 // GetBalance returns the balance of [address] on the P Chain
 func (c *Client) GetBalance(address string) (*GetBalanceResponse, error) {
 	res := &GetBalanceResponse{}
-	err := c.requester.SendRequest("getBalance", &api.JSONAddress{
-		Address: address,
+	err := c.requester.SendRequest("getBalance", &GetBalanceArgs{
+		JSONAddress: api.JSONAddress{Address: address},
 	}, res)
 	return res, err
 }
@@ -426,8 +426,8 @@ func (c *Client) GetTxStatus(txID ids.ID, includeReason bool) (*GetTxStatusRespo
 // staked on the Primary Network.
 func (c *Client) GetStake(addrs []string) (uint64, error) {
 	res := new(GetStakeReply)
-	err := c.requester.SendRequest("getStake", &api.JSONAddresses{
-		Addresses: addrs,
+	err := c.requester.SendRequest("getStake", &GetStakeArgs{
+		JSONAddresses: api.JSONAddresses{Addresses: addrs},
 	}, res)
 	return uint64(res.Staked), err
 }
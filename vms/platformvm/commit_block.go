@@ -20,7 +20,8 @@ type Commit struct {
 //
 // The parent block must either be a proposal
 //
-// This function also sets the onCommit databases if the verification passes.
+// This function also adds the state of the chain if this block is accepted
+// to the VM's states if the verification passes.
 func (c *Commit) Verify() error {
 	// the parent of an Commit block should always be a proposal
 	parent, ok := c.parentBlock().(*ProposalBlock)
@@ -35,10 +36,13 @@ func (c *Commit) Verify() error {
 		return errInvalidBlockType
 	}
 
-	c.onAcceptDB, c.onAcceptFunc = parent.onCommit()
+	onAcceptDB, onAcceptFunc := parent.onCommit()
+	if err := c.vm.states.Add(c.ID(), c.parentStateID(), onAcceptDB); err != nil {
+		return fmt.Errorf("failed to add state: %w", err)
+	}
+	c.onAcceptFunc = onAcceptFunc
 
 	c.vm.currentBlocks[c.ID()] = c
-	c.parentBlock().addChild(c)
 	return nil
 }
 
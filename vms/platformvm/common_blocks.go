@@ -8,7 +8,6 @@ import (
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/database"
-	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
@@ -94,17 +93,8 @@ type Block interface {
 	// provides the more specific staking.Block interface.
 	parentBlock() Block
 
-	// addChild notifies this block that it has a child block building on
-	// its database. When this block commits its database, it should set the
-	// child's database to the former's underlying database instance. This ensures that
-	// the database versions do not recurse the length of the chain.
-	addChild(Block)
-
 	// free all the references of this block from the vm's memory
 	free()
-
-	// Set the database underlying the block's versiondb's to [db]
-	setBaseDatabase(db database.Database)
 }
 
 // A decision block (either Commit, Abort, or DecisionBlock.) represents a
@@ -122,23 +112,18 @@ type decision interface {
 type CommonBlock struct {
 	*core.Block `serialize:"true"`
 	vm          *VM
-
-	// This block's children
-	children []Block
 }
 
 // Reject implements the snowman.Block interface
 func (cb *CommonBlock) Reject() error {
 	defer cb.free() // remove this block from memory
 
+	cb.vm.states.Reject(cb.ID())
 	return cb.Block.Reject()
 }
 
 // free removes this block from memory
-func (cb *CommonBlock) free() {
-	delete(cb.vm.currentBlocks, cb.ID())
-	cb.children = nil
-}
+func (cb *CommonBlock) free() { delete(cb.vm.currentBlocks, cb.ID()) }
 
 // Reject implements the snowman.Block interface
 func (cb *CommonBlock) conflicts(s ids.Set) bool {
@@ -168,16 +153,20 @@ func (cb *CommonBlock) parentBlock() Block {
 	return parent.(Block)
 }
 
-// addChild adds [child] as a child of this block
-func (cb *CommonBlock) addChild(child Block) { cb.children = append(cb.children, child) }
+// parentStateID returns the ID of the block whose state this block is verified
+// on top of. The VM's database is the state of every decided block, so that's
+// the last accepted block if the parent is decided.
+func (cb *CommonBlock) parentStateID() ids.ID {
+	if parent := cb.parentBlock(); parent != nil && parent.Status().Decided() {
+		return cb.vm.states.LastAccepted()
+	}
+	return cb.ParentID()
+}
 
 // CommonDecisionBlock contains the fields and methods common to all decision blocks
 type CommonDecisionBlock struct {
 	CommonBlock `serialize:"true"`
 
-	// state of the chain if this block is accepted
-	onAcceptDB *versiondb.Database
-
 	// to be executed if this block is accepted
 	onAcceptFunc func() error
 }
@@ -189,13 +178,6 @@ func (cdb *CommonDecisionBlock) initialize(vm *VM, bytes []byte) error {
 	return nil
 }
 
-// setBaseDatabase sets this block's base database to [db]
-func (cdb *CommonDecisionBlock) setBaseDatabase(db database.Database) {
-	if err := cdb.onAcceptDB.SetDatabase(db); err != nil {
-		cdb.vm.Ctx.Log.Error("problem while setting base database: %s", err)
-	}
-}
-
 // onAccept returns:
 // 1) The state of the chain if this block is accepted
 // 2) The function to execute if this block is accepted
@@ -203,7 +185,8 @@ func (cdb *CommonDecisionBlock) onAccept() database.Database {
 	if cdb.Status().Decided() {
 		return cdb.vm.DB
 	}
-	return cdb.onAcceptDB
+	db, _ := cdb.vm.states.Get(cdb.ID())
+	return db
 }
 
 // SingleDecisionBlock contains the accept for standalone decision blocks
@@ -220,16 +203,23 @@ func (sdb *SingleDecisionBlock) Accept() error {
 	}
 
 	// Update the state of the chain in the database
-	if err := sdb.onAcceptDB.Commit(); err != nil {
-		return fmt.Errorf("failed to commit onAcceptDB: %w", err)
+	if err := sdb.vm.states.Accept(sdb.ID()); err != nil {
+		return fmt.Errorf("failed to accept state: %w", err)
+	}
+	if err := sdb.vm.putStateDiff(sdb.Height(), sdb.Height()); err != nil {
+		return fmt.Errorf("failed to put state diff: %w", err)
+	}
+	if err := sdb.vm.recordStateSummaryPreimages(); err != nil {
+		return fmt.Errorf("failed to record state summary preimages: %w", err)
+	}
+	if err := sdb.vm.maybeBuildStateSummary(&sdb.CommonBlock); err != nil {
+		return fmt.Errorf("failed to start state summary: %w", err)
 	}
 	if err := sdb.vm.DB.Commit(); err != nil {
 		return fmt.Errorf("failed to commit vm's DB: %w", err)
 	}
+	sdb.vm.setAcceptedHeight(sdb.Height())
 
-	for _, child := range sdb.children {
-		child.setBaseDatabase(sdb.vm.DB)
-	}
 	if sdb.onAcceptFunc != nil {
 		if err := sdb.onAcceptFunc(); err != nil {
 			return fmt.Errorf("failed to execute onAcceptFunc: %w", err)
@@ -258,22 +248,38 @@ func (ddb *DoubleDecisionBlock) Accept() error {
 	if err := parent.CommonBlock.Accept(); err != nil {
 		return fmt.Errorf("failed to accept parent's CommonBlock: %w", err)
 	}
+	if parentID := parent.ID(); ddb.vm.states.LastAccepted() != parentID {
+		if err := ddb.vm.states.Accept(parentID); err != nil {
+			return fmt.Errorf("failed to accept parent's state: %w", err)
+		}
+	}
 
 	if err := ddb.CommonBlock.Accept(); err != nil {
 		return fmt.Errorf("failed to accept CommonBlock: %w", err)
 	}
 
 	// Update the state of the chain in the database
-	if err := ddb.onAcceptDB.Commit(); err != nil {
-		return fmt.Errorf("failed to commit onAcceptDB: %w", err)
+	if err := ddb.vm.states.Accept(ddb.ID()); err != nil {
+		return fmt.Errorf("failed to accept state: %w", err)
+	}
+	if err := ddb.vm.putStateDiff(parent.Height(), ddb.Height()); err != nil {
+		return fmt.Errorf("failed to put state diff: %w", err)
+	}
+	if err := ddb.vm.recordStateSummaryPreimages(); err != nil {
+		return fmt.Errorf("failed to record state summary preimages: %w", err)
+	}
+	// Only proposal txs change the validator sets
+	if err := ddb.vm.putValidatorSets(ddb.Height()); err != nil {
+		return fmt.Errorf("failed to put validator sets: %w", err)
+	}
+	if err := ddb.vm.maybeBuildStateSummary(&ddb.CommonBlock); err != nil {
+		return fmt.Errorf("failed to start state summary: %w", err)
 	}
 	if err := ddb.vm.DB.Commit(); err != nil {
 		return fmt.Errorf("failed to commit vm's DB: %w", err)
 	}
+	ddb.vm.setAcceptedHeight(ddb.Height())
 
-	for _, child := range ddb.children {
-		child.setBaseDatabase(ddb.vm.DB)
-	}
 	if ddb.onAcceptFunc != nil {
 		if err := ddb.onAcceptFunc(); err != nil {
 			return fmt.Errorf("failed to execute OnAcceptFunc: %w", err)
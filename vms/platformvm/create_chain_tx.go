@@ -115,7 +115,11 @@ func (tx *UnsignedCreateChainTx) SemanticVerify(
 	subnetCred := stx.Creds[baseTxCredsLen]
 
 	// Verify the flowcheck
-	if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, baseTxCreds, vm.creationTxFee, vm.Ctx.AVAXAssetID); err != nil {
+	fee, sErr := vm.feeOf(db, vm.creationTxFee)
+	if sErr != nil {
+		return nil, tempError{sErr}
+	}
+	if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, baseTxCreds, fee, vm.Ctx.AVAXAssetID); err != nil {
 		return nil, err
 	}
 
@@ -129,6 +133,10 @@ func (tx *UnsignedCreateChainTx) SemanticVerify(
 	if err := vm.produceOutputs(db, txID, tx.Outs); err != nil {
 		return nil, tempError{err}
 	}
+	// Record the usage of the chain
+	if err := vm.consumeFee(db); err != nil {
+		return nil, tempError{err}
+	}
 
 	// Verify that this chain is authorized by the subnet
 	subnet, err := vm.getSubnet(db, tx.SubnetID)
@@ -171,7 +179,7 @@ func (vm *VM) newCreateChainTx(
 	keys []*crypto.PrivateKeySECP256K1R, // Keys to sign the tx
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
-	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.creationTxFee, changeAddr)
+	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.currentCreationTxFee(), changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
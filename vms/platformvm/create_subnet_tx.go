@@ -79,7 +79,11 @@ func (tx *UnsignedCreateSubnetTx) SemanticVerify(
 	}
 
 	// Verify the flowcheck
-	if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, stx.Creds, vm.creationTxFee, vm.Ctx.AVAXAssetID); err != nil {
+	fee, err := vm.feeOf(db, vm.creationTxFee)
+	if err != nil {
+		return nil, tempError{err}
+	}
+	if err := vm.semanticVerifySpend(db, tx, tx.Ins, tx.Outs, stx.Creds, fee, vm.Ctx.AVAXAssetID); err != nil {
 		return nil, err
 	}
 
@@ -93,6 +97,10 @@ func (tx *UnsignedCreateSubnetTx) SemanticVerify(
 	if err := vm.produceOutputs(db, txID, tx.Outs); err != nil {
 		return nil, tempError{err}
 	}
+	// Record the usage of the chain
+	if err := vm.consumeFee(db); err != nil {
+		return nil, tempError{err}
+	}
 	// Register new subnet in validator manager
 	onAccept := func() error {
 		return vm.vdrMgr.Set(tx.ID(), validators.NewSet())
@@ -108,7 +116,7 @@ func (vm *VM) newCreateSubnetTx(
 	keys []*crypto.PrivateKeySECP256K1R, // pay the fee
 	changeAddr ids.ShortID, // Address to send change to, if there is any
 ) (*Tx, error) {
-	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.creationTxFee, changeAddr)
+	ins, outs, _, signers, err := vm.stake(vm.DB, keys, 0, vm.currentCreationTxFee(), changeAddr)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 	}
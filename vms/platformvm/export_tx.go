@@ -97,7 +97,11 @@ func (tx *UnsignedExportTx) SemanticVerify(
 	copy(outs[len(tx.Outs):], tx.ExportedOutputs)
 
 	// Verify the flowcheck
-	if err := vm.semanticVerifySpend(db, tx, tx.Ins, outs, stx.Creds, vm.txFee, vm.Ctx.AVAXAssetID); err != nil {
+	fee, err := vm.feeOf(db, vm.txFee)
+	if err != nil {
+		return tempError{err}
+	}
+	if err := vm.semanticVerifySpend(db, tx, tx.Ins, outs, stx.Creds, fee, vm.Ctx.AVAXAssetID); err != nil {
 		switch err.(type) {
 		case permError:
 			return permError{
@@ -124,6 +128,10 @@ func (tx *UnsignedExportTx) SemanticVerify(
 			fmt.Errorf("failed to produce outputs: %w", err),
 		}
 	}
+	// Record the usage of the chain
+	if err := vm.consumeFee(db); err != nil {
+		return tempError{err}
+	}
 	return nil
 }
 
@@ -173,7 +181,7 @@ func (vm *VM) newExportTx(
 		return nil, errWrongChainID
 	}
 
-	toBurn, err := safemath.Add64(amount, vm.txFee)
+	toBurn, err := safemath.Add64(amount, vm.currentTxFee())
 	if err != nil {
 		return nil, errOverflowExport
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
 )
 
 // ID of the platform VM
@@ -33,6 +34,10 @@ type Factory struct {
 	MaxStakeDuration   time.Duration // Max time allowed for validating
 	StakeMintingPeriod time.Duration // Staking consumption period
 	ApricotPhase0Time  time.Time     // Time of the Phase 0 upgrade
+	MempoolGossipTime  time.Time     // Time mempool txs start being gossiped
+	StateHistoryDepth  uint64        // Num. of accepted heights whose state is kept
+	FeeConfig          fees.Config   // How fees respond to usage of the chain
+	FeeRegistry        *fees.Registry
 }
 
 // New returns a new instance of the Platform Chain
@@ -52,5 +57,9 @@ func (f *Factory) New(*snow.Context) (interface{}, error) {
 		maxStakeDuration:   f.MaxStakeDuration,
 		stakeMintingPeriod: f.StakeMintingPeriod,
 		apricotPhase0Time:  f.ApricotPhase0Time,
+		mempoolGossipTime:  f.MempoolGossipTime,
+		stateHistoryDepth:  f.StateHistoryDepth,
+		feeConfig:          f.FeeConfig,
+		feeRegistry:        f.FeeRegistry,
 	}, nil
 }
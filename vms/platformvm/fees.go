@@ -0,0 +1,185 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// Fees rise above [vm.txFee] and [vm.creationTxFee] when the chain is busy.
+// The fee state is part of the chain's state. It advances with the chain's
+// timestamp, and every tx that burns a fee consumes a unit of it when the tx is
+// executed. So every node requires the same fee of a tx in a given block, and
+// blocks, gossiped txs and the mempool all reject txs that don't burn it.
+
+var (
+	// Key of the fee state in the chain's database
+	feeStateKey = []byte("fee state")
+
+	errFeeTooLow = errors.New("tx doesn't burn the current fee")
+
+	_ fees.Source = &VM{}
+)
+
+// getFeeState returns the fee state in [db], advanced to the chain's timestamp
+// in [db]
+func (vm *VM) getFeeState(db database.Database) (fees.State, error) {
+	timestamp, err := vm.getTimestamp(db)
+	if err != nil {
+		return fees.State{}, err
+	}
+	state := fees.NewState(timestamp)
+	stateBytes, err := db.Get(feeStateKey)
+	switch err {
+	case nil:
+		if _, err := Codec.Unmarshal(stateBytes, &state); err != nil {
+			return fees.State{}, fmt.Errorf("couldn't parse fee state: %w", err)
+		}
+	case database.ErrNotFound:
+		// No tx has burned a fee yet
+	default:
+		return fees.State{}, err
+	}
+	state.Advance(vm.feeConfig, timestamp)
+	return state, nil
+}
+
+// putFeeState puts the fee state in [db]
+func (vm *VM) putFeeState(db database.Database, state fees.State) error {
+	stateBytes, err := Codec.Marshal(codecVersion, &state)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal fee state: %w", err)
+	}
+	return db.Put(feeStateKey, stateBytes)
+}
+
+// feeOf returns the fee that a tx whose minimum fee is [minFee] must burn when
+// it's executed on [db]
+func (vm *VM) feeOf(db database.Database, minFee uint64) (uint64, error) {
+	state, err := vm.getFeeState(db)
+	if err != nil {
+		return 0, err
+	}
+	return state.Fee(minFee), nil
+}
+
+// consumeFee records in [db] that a tx that burns a fee was executed on [db]
+func (vm *VM) consumeFee(db database.Database) error {
+	state, err := vm.getFeeState(db)
+	if err != nil {
+		return err
+	}
+	state.Consume(1)
+	return vm.putFeeState(db, state)
+}
+
+// preferredFee returns the fee that a tx whose minimum fee is [minFee] must
+// burn to be put into a block built on the preferred block
+func (vm *VM) preferredFee(minFee uint64) uint64 {
+	fee, err := vm.feeOf(vm.preferredState(), minFee)
+	if err != nil {
+		vm.Ctx.Log.Warn("couldn't get the current fee: %s", err)
+		return minFee
+	}
+	return fee
+}
+
+// CurrentFee implements the fees.Source interface. Unlike the other methods of
+// VM, it's called without the context lock held.
+func (vm *VM) CurrentFee(minFee uint64) uint64 {
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	return vm.preferredFee(minFee)
+}
+
+// currentTxFee returns the fee that must currently be burned by every non-state
+// creating tx
+func (vm *VM) currentTxFee() uint64 {
+	return vm.preferredFee(vm.txFee)
+}
+
+// currentCreationTxFee returns the fee that must currently be burned by every
+// state creating tx
+func (vm *VM) currentCreationTxFee() uint64 {
+	return vm.preferredFee(vm.creationTxFee)
+}
+
+// verifyCurrentFee returns an error if [tx] doesn't burn the current fee.
+// Staking txs, which don't burn a fee, and txs issued by the VM itself always
+// pass.
+func (vm *VM) verifyCurrentFee(tx UnsignedTx) error {
+	var (
+		fee          uint64
+		baseTx       *BaseTx
+		importedIns  []*avax.TransferableInput
+		exportedOuts []*avax.TransferableOutput
+	)
+	switch tx := tx.(type) {
+	case *UnsignedAddSubnetValidatorTx:
+		fee, baseTx = vm.currentTxFee(), &tx.BaseTx
+	case *UnsignedCreateChainTx:
+		fee, baseTx = vm.currentCreationTxFee(), &tx.BaseTx
+	case *UnsignedCreateSubnetTx:
+		fee, baseTx = vm.currentCreationTxFee(), &tx.BaseTx
+	case *UnsignedImportTx:
+		fee, baseTx, importedIns = vm.currentTxFee(), &tx.BaseTx, tx.ImportedInputs
+	case *UnsignedExportTx:
+		fee, baseTx, exportedOuts = vm.currentTxFee(), &tx.BaseTx, tx.ExportedOutputs
+	default:
+		return nil
+	}
+
+	burned, err := vm.burnedAVAX(
+		[][]*avax.TransferableInput{baseTx.Ins, importedIns},
+		[][]*avax.TransferableOutput{baseTx.Outs, exportedOuts},
+	)
+	if err != nil {
+		return err
+	}
+	if burned < fee {
+		return fmt.Errorf("%w: burns %d but the current fee is %d", errFeeTooLow, burned, fee)
+	}
+	return nil
+}
+
+// burnedAVAX returns the amount of AVAX consumed by [insList], but not
+// produced by [outsList]
+func (vm *VM) burnedAVAX(
+	insList [][]*avax.TransferableInput,
+	outsList [][]*avax.TransferableOutput,
+) (uint64, error) {
+	consumed := uint64(0)
+	for _, ins := range insList {
+		for _, in := range ins {
+			if in.AssetID() != vm.Ctx.AVAXAssetID {
+				continue
+			}
+			var err error
+			if consumed, err = safemath.Add64(consumed, in.Input().Amount()); err != nil {
+				return 0, err
+			}
+		}
+	}
+	produced := uint64(0)
+	for _, outs := range outsList {
+		for _, out := range outs {
+			if out.AssetID() != vm.Ctx.AVAXAssetID {
+				continue
+			}
+			var err error
+			if produced, err = safemath.Add64(produced, out.Output().Amount()); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return safemath.Sub64(consumed, produced)
+}
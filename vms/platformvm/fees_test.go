@@ -0,0 +1,118 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
+)
+
+// newTestExportTx returns a tx that exports funds of keys[0] and burns the
+// current fee
+func newTestExportTx(t *testing.T, vm *VM) *Tx {
+	tx, err := vm.newExportTx(
+		defaultTxFee,
+		avmID,
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// raiseFees doubles the fees in the last accepted state of [vm]
+func raiseFees(t *testing.T, vm *VM) {
+	vm.feeConfig = fees.Config{
+		Window:        time.Hour,
+		TargetUnits:   1,
+		MaxMultiplier: 2,
+	}
+	state, err := vm.getFeeState(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Multiplier = 2 * fees.MultiplierDenominator
+	if err := vm.putFeeState(vm.DB, state); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.DB.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure that txs are verified against the fee in the state they're executed
+// on, and that executing them consumes the fee state
+func TestSemanticVerifyCurrentFee(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	cheapTx := newTestExportTx(t, vm)
+	raiseFees(t, vm)
+	if fee := vm.currentTxFee(); fee != 2*defaultTxFee {
+		t.Fatalf("expected the current fee to be %d but got %d", 2*defaultTxFee, fee)
+	}
+
+	db := versiondb.New(vm.DB)
+	if err := cheapTx.UnsignedTx.(UnsignedAtomicTx).SemanticVerify(vm, db, cheapTx); err == nil {
+		t.Fatalf("should have failed because the tx only burns the minimum fee")
+	}
+	db.Abort()
+
+	before, err := vm.getFeeState(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := newTestExportTx(t, vm)
+	if err := tx.UnsignedTx.(UnsignedAtomicTx).SemanticVerify(vm, db, tx); err != nil {
+		t.Fatal(err)
+	}
+	after, err := vm.getFeeState(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consumed := after.WindowUnits - before.WindowUnits; consumed != 1 {
+		t.Fatalf("expected the tx to consume 1 unit but %d were consumed", consumed)
+	}
+}
+
+// Ensure that the mempool and gossip reject txs that don't burn the current
+// fee
+func TestMempoolRejectsTxBelowCurrentFee(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	cheapTx := newTestExportTx(t, vm)
+	raiseFees(t, vm)
+
+	if err := vm.mempool.IssueTx(cheapTx); !errors.Is(err, errFeeTooLow) {
+		t.Fatalf("expected %s but got %v", errFeeTooLow, err)
+	}
+	if err := vm.verifyGossipedTx(cheapTx); err == nil {
+		t.Fatalf("should have failed because the tx only burns the minimum fee")
+	}
+	if err := vm.mempool.IssueTx(newTestExportTx(t, vm)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -105,6 +105,11 @@ func (tx *UnsignedImportTx) SemanticVerify(
 		utxos[index] = utxo
 	}
 
+	fee, err := vm.feeOf(db, vm.txFee)
+	if err != nil {
+		return tempError{err}
+	}
+
 	txID := tx.ID()
 
 	// Consume the UTXOS
@@ -119,6 +124,10 @@ func (tx *UnsignedImportTx) SemanticVerify(
 			fmt.Errorf("failed to produce outputs: %w", err),
 		}
 	}
+	// Record the usage of the chain
+	if err := vm.consumeFee(db); err != nil {
+		return tempError{err}
+	}
 
 	if !vm.bootstrapped {
 		return nil
@@ -150,7 +159,7 @@ func (tx *UnsignedImportTx) SemanticVerify(
 	copy(ins, tx.Ins)
 	copy(ins[len(tx.Ins):], tx.ImportedInputs)
 
-	return vm.semanticVerifySpendUTXOs(tx, utxos, ins, tx.Outs, stx.Creds, vm.txFee, vm.Ctx.AVAXAssetID)
+	return vm.semanticVerifySpendUTXOs(tx, utxos, ins, tx.Outs, stx.Creds, fee, vm.Ctx.AVAXAssetID)
 }
 
 // Accept this transaction and spend imported inputs
@@ -222,20 +231,21 @@ func (vm *VM) newImportTx(
 		return nil, errNoFunds // No imported UTXOs were spendable
 	}
 
+	fee := vm.currentTxFee()
 	ins := []*avax.TransferableInput{}
 	outs := []*avax.TransferableOutput{}
-	if importedAmount < vm.txFee { // imported amount goes toward paying tx fee
+	if importedAmount < fee { // imported amount goes toward paying tx fee
 		var baseSigners [][]*crypto.PrivateKeySECP256K1R
-		ins, outs, _, baseSigners, err = vm.stake(vm.DB, keys, 0, vm.txFee-importedAmount, changeAddr)
+		ins, outs, _, baseSigners, err = vm.stake(vm.DB, keys, 0, fee-importedAmount, changeAddr)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't generate tx inputs/outputs: %w", err)
 		}
 		signers = append(baseSigners, signers...)
-	} else if importedAmount > vm.txFee {
+	} else if importedAmount > fee {
 		outs = append(outs, &avax.TransferableOutput{
 			Asset: avax.Asset{ID: vm.Ctx.AVAXAssetID},
 			Out: &secp256k1fx.TransferOutput{
-				Amt: importedAmount - vm.txFee,
+				Amt: importedAmount - fee,
 				OutputOwners: secp256k1fx.OutputOwners{
 					Locktime:  0,
 					Threshold: 1,
@@ -109,6 +109,9 @@ func (m *Mempool) IssueTx(tx *Tx) error {
 	if m.unissuedTxIDs.Contains(txID) {
 		return nil
 	}
+	if err := m.vm.verifyCurrentFee(tx.UnsignedTx); err != nil {
+		return err
+	}
 	switch tx.UnsignedTx.(type) {
 	case TimedTx:
 		m.unissuedProposalTxs.Add(tx)
@@ -121,6 +124,9 @@ func (m *Mempool) IssueTx(tx *Tx) error {
 	}
 	m.unissuedTxIDs.Add(txID)
 	m.ResetTimer()
+	if err := m.vm.gossipTx(tx); err != nil {
+		m.vm.Ctx.Log.Debug("failed to gossip tx %s: %s", txID, err)
+	}
 	return nil
 }
 
@@ -0,0 +1,221 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+)
+
+const (
+	// Number of connected primary network validators each new mempool tx is
+	// gossiped to
+	mempoolGossipSize = 10
+
+	// Number of recently gossiped or rejected tx IDs remembered so that the
+	// same tx isn't gossiped or verified again
+	recentlyGossipedTxsSize = 512
+
+	// Maximum number of txs accepted from a single peer per
+	// [peerGossipPeriod]. Txs beyond this are dropped.
+	maxPeerGossipedTxs = 32
+	peerGossipPeriod   = time.Minute
+)
+
+var _ common.AppHandler = &VM{}
+
+// gossipedTx is the app message used to gossip a mempool tx. The codec
+// version it is marshalled with is the version of the gossip protocol;
+// messages with an unknown version fail to unmarshal and are dropped.
+type gossipedTx struct {
+	Tx []byte `serialize:"true"`
+}
+
+// peerGossipLimit tracks how many txs a peer gossiped to us in the current
+// period
+type peerGossipLimit struct {
+	periodStart time.Time
+	numTxs      int
+}
+
+// mempoolGossipEnabled returns true if mempool txs should be gossiped and
+// accepted from peers
+func (vm *VM) mempoolGossipEnabled() bool {
+	return vm.bootstrapped && !vm.clock.Time().Before(vm.mempoolGossipTime)
+}
+
+// gossipTx sends [tx] to a sample of the connected primary network
+// validators, unless it was gossiped recently
+func (vm *VM) gossipTx(tx *Tx) error {
+	if !vm.mempoolGossipEnabled() || vm.Ctx.AppSender == nil {
+		return nil
+	}
+
+	txID := tx.ID()
+	if _, ok := vm.recentlyGossipedTxs.Get(txID); ok {
+		return nil
+	}
+	vm.recentlyGossipedTxs.Put(txID, nil)
+
+	vdrs, ok := vm.vdrMgr.GetValidators(constants.PrimaryNetworkID)
+	if !ok {
+		return nil
+	}
+	peers := make([]ids.ShortID, 0, len(vm.connections))
+	for nodeID := range vm.connections {
+		if vdrs.Contains(nodeID) {
+			peers = append(peers, nodeID)
+		}
+	}
+	numPeers := mempoolGossipSize
+	if numPeers > len(peers) {
+		numPeers = len(peers)
+	}
+	if numPeers == 0 {
+		return nil
+	}
+
+	s := sampler.NewUniform()
+	if err := s.Initialize(uint64(len(peers))); err != nil {
+		return err
+	}
+	indices, err := s.Sample(numPeers)
+	if err != nil {
+		return err
+	}
+	nodeIDs := ids.ShortSet{}
+	for _, index := range indices {
+		nodeIDs.Add(peers[int(index)])
+	}
+
+	msgBytes, err := vm.codec.Marshal(codecVersion, &gossipedTx{Tx: tx.Bytes()})
+	if err != nil {
+		return fmt.Errorf("couldn't marshal gossiped tx %s: %w", txID, err)
+	}
+	vm.Ctx.Log.Verbo("gossiping tx %s to %d validators", txID, nodeIDs.Len())
+	return vm.Ctx.AppSender.SendAppGossipSpecific(nodeIDs, msgBytes)
+}
+
+// allowGossipFrom returns true if [nodeID] hasn't gossiped more than
+// [maxPeerGossipedTxs] txs to us in the current period. It counts the new tx
+// against the limit.
+func (vm *VM) allowGossipFrom(nodeID ids.ShortID) bool {
+	now := vm.clock.Time()
+	limit, ok := vm.peerGossipLimits[nodeID]
+	if !ok || now.Sub(limit.periodStart) >= peerGossipPeriod {
+		limit = &peerGossipLimit{periodStart: now}
+		vm.peerGossipLimits[nodeID] = limit
+	}
+	if limit.numTxs >= maxPeerGossipedTxs {
+		return false
+	}
+	limit.numTxs++
+	return true
+}
+
+// preferredState returns the state of the preferred block, or the last
+// accepted state if the preferred block isn't a decision block
+func (vm *VM) preferredState() database.Database {
+	if blk, err := vm.getBlock(vm.Preferred()); err == nil {
+		if preferred, ok := blk.(decision); ok {
+			return preferred.onAccept()
+		}
+	}
+	return vm.DB
+}
+
+// verifyGossipedTx verifies [tx] against the state of the preferred block
+func (vm *VM) verifyGossipedTx(tx *Tx) error {
+	db := versiondb.New(vm.preferredState())
+	defer db.Abort()
+
+	switch utx := tx.UnsignedTx.(type) {
+	case TimedTx:
+		proposalTx, ok := utx.(UnsignedProposalTx)
+		if !ok {
+			return errUnknownTxType
+		}
+		if _, _, _, _, err := proposalTx.SemanticVerify(vm, db, tx); err != nil {
+			return err
+		}
+	case UnsignedDecisionTx:
+		if _, err := utx.SemanticVerify(vm, db, tx); err != nil {
+			return err
+		}
+	case UnsignedAtomicTx:
+		if err := utx.SemanticVerify(vm, db, tx); err != nil {
+			return err
+		}
+	default:
+		return errUnknownTxType
+	}
+	return nil
+}
+
+// AppGossip implements the common.AppHandler interface. A valid tx gossiped
+// by [nodeID] is added to the mempool, which in turn gossips it onwards.
+func (vm *VM) AppGossip(nodeID ids.ShortID, msg []byte) error {
+	if !vm.mempoolGossipEnabled() {
+		return nil
+	}
+	if !vm.allowGossipFrom(nodeID) {
+		vm.Ctx.Log.Verbo("dropping gossiped tx from %s as it exceeded its rate limit", nodeID)
+		return nil
+	}
+
+	gossip := gossipedTx{}
+	if _, err := vm.codec.Unmarshal(msg, &gossip); err != nil {
+		vm.Ctx.Log.Debug("dropping unparsable gossip from %s: %s", nodeID, err)
+		return nil
+	}
+	tx := &Tx{}
+	if _, err := vm.codec.Unmarshal(gossip.Tx, tx); err != nil {
+		vm.Ctx.Log.Debug("dropping unparsable tx gossiped by %s: %s", nodeID, err)
+		return nil
+	}
+	if err := tx.Sign(vm.codec, nil); err != nil {
+		vm.Ctx.Log.Debug("dropping tx gossiped by %s: %s", nodeID, err)
+		return nil
+	}
+
+	txID := tx.ID()
+	if _, ok := vm.recentlyGossipedTxs.Get(txID); ok || vm.mempool.unissuedTxIDs.Contains(txID) {
+		return nil
+	}
+	if err := vm.verifyGossipedTx(tx); err != nil {
+		vm.recentlyGossipedTxs.Put(txID, nil)
+		vm.Ctx.Log.Debug("dropping unverifiable tx %s gossiped by %s: %s", txID, nodeID, err)
+		return nil
+	}
+	// The tx was chosen by the peer, so failing to issue it isn't an error of
+	// this chain
+	if err := vm.mempool.IssueTx(tx); err != nil {
+		vm.recentlyGossipedTxs.Put(txID, nil)
+		vm.Ctx.Log.Debug("dropping tx %s gossiped by %s: %s", txID, nodeID, err)
+	}
+	return nil
+}
+
+// AppRequest implements the common.AppHandler interface. The P-chain doesn't
+// send app requests, so any it receives are dropped.
+func (vm *VM) AppRequest(nodeID ids.ShortID, requestID uint32, request []byte) error {
+	return nil
+}
+
+// AppRequestFailed implements the common.AppHandler interface
+func (vm *VM) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
+	return nil
+}
+
+// AppResponse implements the common.AppHandler interface
+func (vm *VM) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
+	return nil
+}
@@ -0,0 +1,230 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/vms/timestampvm"
+)
+
+// newGossipTestVM returns a VM connected to the genesis validator with key
+// [keys[0]] and a sender that records the gossip it sends
+func newGossipTestVM(t *testing.T) (*VM, ids.ShortID, *[][]byte) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+
+	nodeID := keys[0].PublicKey().Address()
+	vm.Connected(nodeID)
+
+	gossiped := &[][]byte{}
+	sender := &common.AppSenderTest{T: t}
+	sender.Default(true)
+	sender.SendAppGossipSpecificF = func(nodeIDs ids.ShortSet, msg []byte) error {
+		if !nodeIDs.Contains(nodeID) {
+			t.Fatalf("should have gossiped to %s", nodeID)
+		}
+		*gossiped = append(*gossiped, msg)
+		return nil
+	}
+	vm.Ctx.AppSender = sender
+	return vm, nodeID, gossiped
+}
+
+func newGossipTestTx(t *testing.T, vm *VM, chainName string) *Tx {
+	tx, err := vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		timestampvm.ID,
+		nil,
+		chainName,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func marshalGossipedTx(t *testing.T, vm *VM, tx *Tx) []byte {
+	msg, err := vm.codec.Marshal(codecVersion, &gossipedTx{Tx: tx.Bytes()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+// Ensure a tx issued to the mempool is gossiped once to connected validators
+func TestMempoolGossipIssuedTx(t *testing.T) {
+	vm, _, gossiped := newGossipTestVM(t)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	tx := newGossipTestTx(t, vm, "chain")
+	if err := vm.mempool.IssueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(*gossiped) != 1 {
+		t.Fatalf("should have gossiped 1 message but gossiped %d", len(*gossiped))
+	}
+
+	gossip := gossipedTx{}
+	if _, err := vm.codec.Unmarshal((*gossiped)[0], &gossip); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gossip.Tx, tx.Bytes()) {
+		t.Fatal("gossiped the wrong tx")
+	}
+
+	// The same tx shouldn't be gossiped again
+	if err := vm.gossipTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(*gossiped) != 1 {
+		t.Fatal("shouldn't have gossiped the tx again")
+	}
+}
+
+// Ensure a valid gossiped tx is added to the mempool and gossiped onwards, and
+// that invalid or duplicate gossip is dropped
+func TestMempoolGossipReceivedTx(t *testing.T) {
+	vm, nodeID, gossiped := newGossipTestVM(t)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	if err := vm.AppGossip(nodeID, []byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := newGossipTestTx(t, vm, "chain")
+	msg := marshalGossipedTx(t, vm, tx)
+	if err := vm.AppGossip(nodeID, msg); err != nil {
+		t.Fatal(err)
+	}
+	if !vm.mempool.unissuedTxIDs.Contains(tx.ID()) {
+		t.Fatal("gossiped tx should have been added to the mempool")
+	}
+	if len(*gossiped) != 1 {
+		t.Fatalf("should have gossiped the tx onwards once but gossiped %d messages", len(*gossiped))
+	}
+
+	if err := vm.AppGossip(nodeID, msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(*gossiped) != 1 {
+		t.Fatal("shouldn't have gossiped a duplicate tx")
+	}
+
+	// A tx that spends more than the payer has fails verification
+	invalidTx := newGossipTestTx(t, vm, "invalid")
+	vm.creationTxFee = defaultBalance * 10
+	if err := vm.AppGossip(nodeID, marshalGossipedTx(t, vm, invalidTx)); err != nil {
+		t.Fatal(err)
+	}
+	if vm.mempool.unissuedTxIDs.Contains(invalidTx.ID()) {
+		t.Fatal("invalid tx shouldn't have been added to the mempool")
+	}
+}
+
+// Ensure a gossiped tx that doesn't burn the current fee is dropped without
+// returning an error, which would shut down the chain
+func TestMempoolGossipUnderpricedTx(t *testing.T) {
+	vm, nodeID, gossiped := newGossipTestVM(t)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	tx := newGossipTestTx(t, vm, "chain")
+	vm.creationTxFee++
+	if err := vm.AppGossip(nodeID, marshalGossipedTx(t, vm, tx)); err != nil {
+		t.Fatalf("gossiping an underpriced tx returned %s", err)
+	}
+	if vm.mempool.unissuedTxIDs.Contains(tx.ID()) {
+		t.Fatal("underpriced tx shouldn't have been added to the mempool")
+	}
+	if len(*gossiped) != 0 {
+		t.Fatal("underpriced tx shouldn't have been gossiped onwards")
+	}
+	if _, ok := vm.recentlyGossipedTxs.Get(tx.ID()); !ok {
+		t.Fatal("underpriced tx should have been remembered so it isn't verified again")
+	}
+}
+
+// Ensure a peer can't gossip more than [maxPeerGossipedTxs] per period
+func TestMempoolGossipRateLimit(t *testing.T) {
+	vm, nodeID, _ := newGossipTestVM(t)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	for i := 0; i < maxPeerGossipedTxs; i++ {
+		if err := vm.AppGossip(nodeID, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tx := newGossipTestTx(t, vm, "chain")
+	msg := marshalGossipedTx(t, vm, tx)
+	if err := vm.AppGossip(nodeID, msg); err != nil {
+		t.Fatal(err)
+	}
+	if vm.mempool.unissuedTxIDs.Contains(tx.ID()) {
+		t.Fatal("tx should have been dropped by the rate limit")
+	}
+
+	vm.clock.Set(vm.clock.Time().Add(peerGossipPeriod))
+	if err := vm.AppGossip(nodeID, msg); err != nil {
+		t.Fatal(err)
+	}
+	if !vm.mempool.unissuedTxIDs.Contains(tx.ID()) {
+		t.Fatal("tx should have been accepted after the rate limit period")
+	}
+}
+
+// Ensure txs aren't gossiped before the mempool gossip time
+func TestMempoolGossipNotActivated(t *testing.T) {
+	vm, nodeID, gossiped := newGossipTestVM(t)
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+	vm.mempoolGossipTime = vm.clock.Time().Add(peerGossipPeriod)
+
+	tx := newGossipTestTx(t, vm, "chain")
+	if err := vm.mempool.IssueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(*gossiped) != 0 {
+		t.Fatal("shouldn't have gossiped before the mempool gossip time")
+	}
+
+	receivedTx := newGossipTestTx(t, vm, "gossiped")
+	if err := vm.AppGossip(nodeID, marshalGossipedTx(t, vm, receivedTx)); err != nil {
+		t.Fatal(err)
+	}
+	if vm.mempool.unissuedTxIDs.Contains(receivedTx.ID()) {
+		t.Fatal("shouldn't have accepted gossip before the mempool gossip time")
+	}
+}
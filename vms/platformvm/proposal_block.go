@@ -6,7 +6,6 @@ package platformvm
 import (
 	"fmt"
 
-	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
@@ -40,7 +39,7 @@ type ProposalBlock struct {
 func (pb *ProposalBlock) Accept() error {
 	pb.SetStatus(choices.Accepted)
 	pb.VM.LastAcceptedID = pb.ID()
-	return nil
+	return pb.vm.states.Accept(pb.ID())
 }
 
 // Reject implements the snowman.Block interface
@@ -70,16 +69,6 @@ func (pb *ProposalBlock) initialize(vm *VM, bytes []byte) error {
 	return nil
 }
 
-// setBaseDatabase sets this block's base database to [db]
-func (pb *ProposalBlock) setBaseDatabase(db database.Database) {
-	if err := pb.onCommitDB.SetDatabase(db); err != nil {
-		pb.vm.Ctx.Log.Error("problem while setting base database: %s", err)
-	}
-	if err := pb.onAbortDB.SetDatabase(db); err != nil {
-		pb.vm.Ctx.Log.Error("problem while setting base database: %s", err)
-	}
-}
-
 // onCommit should only be called after Verify is called.
 // onCommit returns:
 //   1. A database that contains the state of the chain assuming this proposal
@@ -113,8 +102,7 @@ func (pb *ProposalBlock) Verify() error {
 	parentIntf := pb.parentBlock()
 
 	// The parent of a proposal block (ie this block) must be a decision block
-	parent, ok := parentIntf.(decision)
-	if !ok {
+	if _, ok := parentIntf.(decision); !ok {
 		if err := pb.Reject(); err == nil {
 			if err := pb.vm.DB.Commit(); err != nil {
 				return fmt.Errorf("couldn't commit VM's database: %w", err)
@@ -125,18 +113,22 @@ func (pb *ProposalBlock) Verify() error {
 		return errInvalidBlockType
 	}
 
-	// pdb is the database if this block's parent is accepted
-	pdb := parent.onAccept()
+	// pdb is the state of this block. The proposal doesn't change the state
+	// until it's committed or aborted, so it's the state of the parent.
+	pdb, err := pb.vm.states.NewDiff(pb.parentStateID())
+	if err != nil {
+		return err
+	}
 
 	txID := tx.ID()
 
-	var err TxError
-	pb.onCommitDB, pb.onAbortDB, pb.onCommitFunc, pb.onAbortFunc, err = tx.SemanticVerify(pb.vm, pdb, &pb.Tx)
-	if err != nil {
-		pb.vm.droppedTxCache.Put(txID, err.Error()) // cache tx as dropped
+	var txErr TxError
+	pb.onCommitDB, pb.onAbortDB, pb.onCommitFunc, pb.onAbortFunc, txErr = tx.SemanticVerify(pb.vm, pdb, &pb.Tx)
+	if txErr != nil {
+		pb.vm.droppedTxCache.Put(txID, txErr.Error()) // cache tx as dropped
 		// If this block's transaction proposes to advance the timestamp, the transaction may fail
 		// verification now but be valid in the future, so don't (permanently) mark the block as rejected.
-		if !err.Temporary() {
+		if !txErr.Temporary() {
 			if err := pb.Reject(); err == nil {
 				if err := pb.vm.DB.Commit(); err != nil {
 					return fmt.Errorf("couldn't commit VM's database: %w", err)
@@ -145,7 +137,7 @@ func (pb *ProposalBlock) Verify() error {
 				pb.vm.DB.Abort()
 			}
 		}
-		return err
+		return txErr
 	}
 
 	txBytes := tx.Bytes()
@@ -163,8 +155,10 @@ func (pb *ProposalBlock) Verify() error {
 		return fmt.Errorf("failed to put status of tx %s: %w", txID, err)
 	}
 
+	if err := pb.vm.states.Add(pb.ID(), pb.parentStateID(), pdb); err != nil {
+		return fmt.Errorf("failed to add state: %w", err)
+	}
 	pb.vm.currentBlocks[pb.ID()] = pb
-	parentIntf.addChild(pb)
 	return nil
 }
 
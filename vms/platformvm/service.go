@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -43,6 +44,7 @@ var (
 	errInvalidDelegationRate = errors.New("argument 'delegationFeeRate' must be between 0 and 100, inclusive")
 	errNoAddresses           = errors.New("no addresses provided")
 	errNoKeys                = errors.New("user has no keys or funds")
+	errHeightOfAtomicUTXOs   = errors.New("argument 'height' can't be provided for UTXOs from another chain")
 )
 
 // Service defines the API calls that can be made to the platform chain
@@ -53,6 +55,20 @@ type GetHeightResponse struct {
 	Height json.Uint64 `json:"height"`
 }
 
+// stateAt returns the state of the chain after the accepted block at
+// [height]. If [height] is nil, returns the state after the last accepted
+// block.
+func (service *Service) stateAt(height *json.Uint64) (database.Database, error) {
+	if height == nil {
+		return service.vm.DB, nil
+	}
+	db, err := service.vm.stateAtHeight(uint64(*height))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get state at height %d: %w", *height, err)
+	}
+	return db, nil
+}
+
 // GetHeight returns the height of the last accepted block
 func (service *Service) GetHeight(r *http.Request, args *struct{}, response *GetHeightResponse) error {
 	lastAccepted, err := service.vm.getBlock(service.vm.LastAccepted())
@@ -161,6 +177,14 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *a
  ******************************************************
  */
 
+// GetBalanceArgs are the arguments for calling GetBalance
+type GetBalanceArgs struct {
+	api.JSONAddress
+	// If provided, the balance as of the accepted block at this height is
+	// returned, rather than the current balance
+	Height *json.Uint64 `json:"height"`
+}
+
 // GetBalanceResponse ...
 type GetBalanceResponse struct {
 	// Balance, in nAVAX, of the address
@@ -172,7 +196,7 @@ type GetBalanceResponse struct {
 }
 
 // GetBalance gets the balance of an address
-func (service *Service) GetBalance(_ *http.Request, args *api.JSONAddress, response *GetBalanceResponse) error {
+func (service *Service) GetBalance(_ *http.Request, args *GetBalanceArgs, response *GetBalanceResponse) error {
 	service.vm.SnowmanVM.Ctx.Log.Info("Platform: GetBalance called for address %s", args.Address)
 
 	// Parse to address
@@ -181,9 +205,14 @@ func (service *Service) GetBalance(_ *http.Request, args *api.JSONAddress, respo
 		return fmt.Errorf("couldn't parse argument 'address' to address: %w", err)
 	}
 
+	db, err := service.stateAt(args.Height)
+	if err != nil {
+		return err
+	}
+
 	addrs := ids.ShortSet{}
 	addrs.Add(addr)
-	utxos, _, _, err := service.vm.GetUTXOs(service.vm.DB, addrs, ids.ShortEmpty, ids.Empty, -1, false)
+	utxos, _, _, err := service.vm.GetUTXOs(db, addrs, ids.ShortEmpty, ids.Empty, -1, false)
 	if err != nil {
 		addr, err2 := service.vm.FormatLocalAddress(addr)
 		if err2 != nil {
@@ -192,7 +221,16 @@ func (service *Service) GetBalance(_ *http.Request, args *api.JSONAddress, respo
 		return fmt.Errorf("couldn't get UTXO set of %s: %w", addr, err)
 	}
 
+	// Historical balances are locked or unlocked as of the chain time at that
+	// height
 	currentTime := service.vm.clock.Unix()
+	if args.Height != nil {
+		timestamp, err := service.vm.getTimestamp(db)
+		if err != nil {
+			return fmt.Errorf("couldn't get chain time at height %d: %w", *args.Height, err)
+		}
+		currentTime = uint64(timestamp.Unix())
+	}
 
 	unlocked := uint64(0)
 	lockedStakeable := uint64(0)
@@ -349,6 +387,9 @@ type GetUTXOsArgs struct {
 	Limit       json.Uint32         `json:"limit"`
 	StartIndex  Index               `json:"startIndex"`
 	Encoding    formatting.Encoding `json:"encoding"`
+	// If provided, the UTXOs as of the accepted block at this height are
+	// returned. Can only be provided for UTXOs on this chain.
+	Height *json.Uint64 `json:"height"`
 }
 
 // GetUTXOsResponse defines the GetUTXOs replies returned from the API
@@ -417,8 +458,13 @@ func (service *Service) GetUTXOs(_ *http.Request, args *GetUTXOsArgs, response *
 		err       error
 	)
 	if sourceChain == service.vm.Ctx.ChainID {
+		var db database.Database
+		db, err = service.stateAt(args.Height)
+		if err != nil {
+			return err
+		}
 		utxos, endAddr, endUTXOID, err = service.vm.GetUTXOs(
-			service.vm.DB,
+			db,
 			addrSet,
 			startAddr,
 			startUTXO,
@@ -426,6 +472,9 @@ func (service *Service) GetUTXOs(_ *http.Request, args *GetUTXOsArgs, response *
 			true,
 		)
 	} else {
+		if args.Height != nil {
+			return errHeightOfAtomicUTXOs
+		}
 		utxos, endAddr, endUTXOID, err = service.vm.GetAtomicUTXOs(
 			sourceChain,
 			addrSet,
@@ -608,6 +657,9 @@ type GetCurrentValidatorsArgs struct {
 	// some nodeIDs are not currently validators, they
 	// will be omitted from the response.
 	NodeIDs []string `json:"nodeIDs"`
+	// If provided, the validators as of the accepted
+	// block at this height are returned.
+	Height *json.Uint64 `json:"height"`
 }
 
 // GetCurrentValidatorsReply are the results from calling GetCurrentValidators.
@@ -636,8 +688,13 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 	}
 	includeAllNodes := nodeIDs.Len() == 0
 
+	db, err := service.stateAt(args.Height)
+	if err != nil {
+		return err
+	}
+
 	stopPrefix := []byte(fmt.Sprintf("%s%s", args.SubnetID, stopDBPrefix))
-	stopDB := prefixdb.NewNested(stopPrefix, service.vm.DB)
+	stopDB := prefixdb.NewNested(stopPrefix, db)
 	defer stopDB.Close()
 
 	stopIter := stopDB.NewIterator()
@@ -701,7 +758,7 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 			weight := json.Uint64(staker.Validator.Weight())
 			potentialReward := json.Uint64(tx.Reward)
 			delegationFee := json.Float32(100 * float32(staker.Shares) / float32(PercentDenominator))
-			rawUptime, err := service.vm.calculateUptime(service.vm.DB, nodeID, startTime)
+			rawUptime, err := service.vm.calculateUptime(db, nodeID, startTime)
 			if err != nil {
 				return err
 			}
@@ -1985,6 +2042,14 @@ func (service *Service) GetTxStatus(_ *http.Request, args *GetTxStatusArgs, resp
 	return nil
 }
 
+// GetStakeArgs are the arguments for calling GetStake
+type GetStakeArgs struct {
+	api.JSONAddresses
+	// If provided, the stake as of the accepted block at this height is
+	// returned, rather than the current stake
+	Height *json.Uint64 `json:"height"`
+}
+
 // GetStakeReply is the response from calling GetStake.
 type GetStakeReply struct {
 	Staked json.Uint64 `json:"staked"`
@@ -1998,7 +2063,7 @@ type GetStakeReply struct {
 // This method only concerns itself with the Primary Network, not subnets
 // TODO: Improve the performance of this method by maintaining this data
 // in a data structure rather than re-calculating it by iterating over stakers
-func (service *Service) GetStake(_ *http.Request, args *api.JSONAddresses, response *GetStakeReply) error {
+func (service *Service) GetStake(_ *http.Request, args *GetStakeArgs, response *GetStakeReply) error {
 	service.vm.Ctx.Log.Info("Platform: GetStake called")
 
 	if len(args.Addresses) > maxGetStakeAddrs {
@@ -2059,10 +2124,15 @@ func (service *Service) GetStake(_ *http.Request, args *api.JSONAddresses, respo
 		return amount, nil
 	}
 
+	db, err := service.stateAt(args.Height)
+	if err != nil {
+		return err
+	}
+
 	var totalStake uint64
 
 	stopPrefix := []byte(fmt.Sprintf("%s%s", constants.PrimaryNetworkID, stopDBPrefix))
-	stopDB := prefixdb.NewNested(stopPrefix, service.vm.DB)
+	stopDB := prefixdb.NewNested(stopPrefix, db)
 	defer stopDB.Close()
 	stopIter := stopDB.NewIterator()
 	defer stopIter.Release()
@@ -2093,7 +2163,7 @@ func (service *Service) GetStake(_ *http.Request, args *api.JSONAddresses, respo
 
 	// Iterate over pending validators
 	startPrefix := []byte(fmt.Sprintf("%s%s", constants.PrimaryNetworkID, startDBPrefix))
-	startDB := prefixdb.NewNested(startPrefix, service.vm.DB)
+	startDB := prefixdb.NewNested(startPrefix, db)
 	defer startDB.Close()
 	startIter := startDB.NewIterator()
 	defer startIter.Release()
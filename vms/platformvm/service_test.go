@@ -397,8 +397,10 @@ func TestGetBalance(t *testing.T) {
 	// Ensure GetStake is correct for each of the genesis validators
 	genesis, _ := defaultGenesis()
 	for _, utxo := range genesis.UTXOs {
-		request := api.JSONAddress{
-			Address: fmt.Sprintf("P-%s", utxo.Address),
+		request := GetBalanceArgs{
+			JSONAddress: api.JSONAddress{
+				Address: fmt.Sprintf("P-%s", utxo.Address),
+			},
 		}
 		reply := GetBalanceResponse{}
 		if err := service.GetBalance(nil, &request, &reply); err != nil {
@@ -437,8 +439,10 @@ func TestGetStake(t *testing.T) {
 	for _, validator := range genesis.Validators {
 		addr := fmt.Sprintf("P-%s", validator.RewardOwner.Addresses[0])
 		addrs = append(addrs, addr)
-		args := api.JSONAddresses{
-			Addresses: []string{addr},
+		args := GetStakeArgs{
+			JSONAddresses: api.JSONAddresses{
+				Addresses: []string{addr},
+			},
 		}
 		response := GetStakeReply{}
 		if err := service.GetStake(nil, &args, &response); err != nil {
@@ -450,8 +454,10 @@ func TestGetStake(t *testing.T) {
 	}
 
 	// Make sure this works for multiple addresses
-	args := api.JSONAddresses{
-		Addresses: addrs,
+	args := GetStakeArgs{
+		JSONAddresses: api.JSONAddresses{
+			Addresses: addrs,
+		},
 	}
 	response := GetStakeReply{}
 	if err := service.GetStake(nil, &args, &response); err != nil {
@@ -6,7 +6,6 @@ package platformvm
 import (
 	"fmt"
 
-	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/vms/components/core"
 )
@@ -36,13 +35,13 @@ func (sb *StandardBlock) initialize(vm *VM, bytes []byte) error {
 //
 // The parent block must be a proposal
 //
-// This function also sets onAcceptDB database if the verification passes.
+// This function also adds the state of the chain if this block is accepted
+// to the VM's states if the verification passes.
 func (sb *StandardBlock) Verify() error {
 	parentBlock := sb.parentBlock()
 	// StandardBlock is not a modifier on a proposal block, so its parent must
 	// be a decision.
-	parent, ok := parentBlock.(decision)
-	if !ok {
+	if _, ok := parentBlock.(decision); !ok {
 		if err := sb.Reject(); err == nil {
 			if err := sb.vm.DB.Commit(); err != nil {
 				return fmt.Errorf("failed to commit VM's database: %w", err)
@@ -53,9 +52,10 @@ func (sb *StandardBlock) Verify() error {
 		return errInvalidBlockType
 	}
 
-	pdb := parent.onAccept()
-
-	sb.onAcceptDB = versiondb.New(pdb)
+	onAcceptDB, err := sb.vm.states.NewDiff(sb.parentStateID())
+	if err != nil {
+		return err
+	}
 	funcs := make([]func() error, 0, len(sb.Txs))
 	for _, tx := range sb.Txs {
 		utx, ok := tx.UnsignedTx.(UnsignedDecisionTx)
@@ -63,7 +63,7 @@ func (sb *StandardBlock) Verify() error {
 			return errWrongTxType
 		}
 		txID := tx.ID()
-		onAccept, err := utx.SemanticVerify(sb.vm, sb.onAcceptDB, tx)
+		onAccept, err := utx.SemanticVerify(sb.vm, onAcceptDB, tx)
 		if err != nil {
 			sb.vm.droppedTxCache.Put(txID, err.Error()) // cache tx as dropped
 			if err := sb.Reject(); err == nil {
@@ -77,9 +77,9 @@ func (sb *StandardBlock) Verify() error {
 		}
 		if txBytes, err := sb.vm.codec.Marshal(codecVersion, tx); err != nil {
 			return fmt.Errorf("failed to marshal tx %s: %w", txID, err)
-		} else if err := sb.vm.putTx(sb.onAcceptDB, txID, txBytes); err != nil {
+		} else if err := sb.vm.putTx(onAcceptDB, txID, txBytes); err != nil {
 			return fmt.Errorf("failed to put tx %s: %w", txID, err)
-		} else if err := sb.vm.putStatus(sb.onAcceptDB, txID, Committed); err != nil {
+		} else if err := sb.vm.putStatus(onAcceptDB, txID, Committed); err != nil {
 			return fmt.Errorf("failed to put tx %s status: %w", txID, err)
 		} else if onAccept != nil {
 			funcs = append(funcs, onAccept)
@@ -99,8 +99,10 @@ func (sb *StandardBlock) Verify() error {
 		}
 	}
 
+	if err := sb.vm.states.Add(sb.ID(), sb.parentStateID(), onAcceptDB); err != nil {
+		return fmt.Errorf("failed to add state: %w", err)
+	}
 	sb.vm.currentBlocks[sb.ID()] = sb
-	sb.parentBlock().addChild(sb)
 	return nil
 }
 
@@ -27,9 +27,10 @@ import (
 
 // TODO: Cache prefixed IDs or use different way of keying into database
 const (
-	startDBPrefix  = "start"
-	stopDBPrefix   = "stop"
-	uptimeDBPrefix = "uptime"
+	startDBPrefix   = "start"
+	stopDBPrefix    = "stop"
+	uptimeDBPrefix  = "uptime"
+	utxoSetDBPrefix = "utxoSet"
 )
 
 var (
@@ -384,6 +385,9 @@ func (vm *VM) putUTXO(db database.Database, utxo *avax.UTXO) error {
 	if err := vm.State.Put(db, utxoTypeID, utxoID, utxo); err != nil {
 		return err
 	}
+	if err := vm.putUTXOSetID(db, utxoID); err != nil {
+		return fmt.Errorf("couldn't update UTXO set: %w", err)
+	}
 
 	// If this output lists addresses that it references index it
 	if addressable, ok := utxo.Out.(avax.Addressable); ok {
@@ -411,6 +415,9 @@ func (vm *VM) removeUTXO(db database.Database, utxoID ids.ID) error {
 	if err := vm.State.Put(db, utxoTypeID, utxoID, nil); err != nil { // remove the UTXO
 		return err
 	}
+	if err := vm.removeUTXOSetID(db, utxoID); err != nil {
+		return fmt.Errorf("couldn't update UTXO set: %w", err)
+	}
 	// If this output lists addresses that it references remove the indices
 	if addressable, ok := utxo.Out.(avax.Addressable); ok {
 		// For each owner of this UTXO, remove from their list of UTXOs
@@ -427,6 +434,28 @@ func (vm *VM) removeUTXO(db database.Database, utxoID ids.ID) error {
 	return nil
 }
 
+// Persist that the UTXO with ID [utxoID] is in the UTXO set
+func (vm *VM) putUTXOSetID(db database.Database, utxoID ids.ID) error {
+	utxoSetDB := prefixdb.NewNested([]byte(utxoSetDBPrefix), db)
+	errs := wrappers.Errs{}
+	errs.Add(
+		utxoSetDB.Put(utxoID[:], nil),
+		utxoSetDB.Close(),
+	)
+	return errs.Err
+}
+
+// Remove the UTXO with ID [utxoID] from the UTXO set
+func (vm *VM) removeUTXOSetID(db database.Database, utxoID ids.ID) error {
+	utxoSetDB := prefixdb.NewNested([]byte(utxoSetDBPrefix), db)
+	errs := wrappers.Errs{}
+	errs.Add(
+		utxoSetDB.Delete(utxoID[:]),
+		utxoSetDB.Close(),
+	)
+	return errs.Err
+}
+
 // Return the IDs of UTXOs that reference [addr].
 // Only returns UTXOs after [start].
 // Returns at most [limit] UTXO IDs.
@@ -521,7 +550,7 @@ func (vm *VM) getPaginatedUTXOs(
 			start = startUTXOID
 		}
 
-		utxoIDs, err := vm.getReferencingUTXOs(vm.DB, addr.Bytes(), start, searchSize) // Get UTXOs associated with [addr]
+		utxoIDs, err := vm.getReferencingUTXOs(db, addr.Bytes(), start, searchSize) // Get UTXOs associated with [addr]
 		if err != nil {
 			return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXOs for address %s: %w", addr, err)
 		}
@@ -533,7 +562,7 @@ func (vm *VM) getPaginatedUTXOs(
 				continue
 			}
 
-			utxo, err := vm.getUTXO(vm.DB, utxoID)
+			utxo, err := vm.getUTXO(db, utxoID)
 			if err != nil {
 				return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
 			}
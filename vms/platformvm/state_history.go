@@ -0,0 +1,232 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// This file contains the methods of VM that keep the history of the state of
+// the chain, so that the API can answer queries as of a recently accepted
+// block rather than only as of the last accepted block.
+//
+// When decision blocks are accepted, the value every key they changed had
+// before they were accepted is persisted under the height of the decision
+// block. This includes the UTXOs and stakers added and removed by the blocks.
+// The state after the block at height h is rebuilt by undoing the changes of
+// every block after h, from the last accepted block down.
+//
+// Only the changes of the last [stateHistoryDepth] heights are kept. As every
+// height from the first kept one up to the last accepted block has a diff, the
+// diffs are pruned from the newest height outside of the window down to the
+// first height without a diff. This also prunes the diffs kept by a previous
+// run of the node with a larger [stateHistoryDepth].
+
+const (
+	stateHistoryDBPrefix = "stateHistory"
+
+	// The first byte of the value of a key in a state diff. A key that didn't
+	// exist before the block was accepted has no value after this byte.
+	keyDidntExist byte = 0
+	keyExisted    byte = 1
+)
+
+var (
+	errHeightNotAccepted  = errors.New("height is after the last accepted block")
+	errStateHistoryPruned = errors.New("state at height isn't kept")
+)
+
+// stateDiffs returns the database that the state diff at each height is
+// stored in. If there is a diff at a height, the key of the height is set.
+// The keys changed by the blocks at that height are under the height's
+// prefix.
+func (vm *VM) stateDiffs() database.Database {
+	return prefixdb.NewNested([]byte(stateHistoryDBPrefix), vm.DB)
+}
+
+func stateDiffHeightKey(height uint64) []byte {
+	key := make([]byte, wrappers.LongLen)
+	binary.BigEndian.PutUint64(key, height)
+	return key
+}
+
+// putStateDiff persists the changes pending in [vm.DB], which were made by
+// the decision block at height [endHeight] and the blocks accepted with it
+// from height [startHeight]. The diff is stored under [endHeight] and the
+// other heights are marked as having changed nothing, as only decision blocks
+// change the state.
+func (vm *VM) putStateDiff(startHeight, endHeight uint64) error {
+	if vm.stateHistoryDepth == 0 {
+		return nil
+	}
+
+	batch, err := vm.DB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	stateDiffs := vm.stateDiffs()
+	heightKey := stateDiffHeightKey(endHeight)
+	writer := &stateDiffWriter{
+		state: vm.DB.GetDatabase(),
+		diff:  prefixdb.NewNested(heightKey, stateDiffs),
+	}
+	if err := batch.Replay(writer); err != nil {
+		return fmt.Errorf("couldn't record the state diff at height %d: %w", endHeight, err)
+	}
+	for height := startHeight; height <= endHeight; height++ {
+		if err := stateDiffs.Put(stateDiffHeightKey(height), nil); err != nil {
+			return err
+		}
+	}
+
+	return vm.pruneStateDiffs(stateDiffs, endHeight)
+}
+
+// pruneStateHistory removes the state diffs that are no longer within
+// [vm.stateHistoryDepth] of the last accepted block, such as those kept
+// before [vm.stateHistoryDepth] was lowered, and commits the removal
+func (vm *VM) pruneStateHistory() error {
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		return err
+	}
+	if err := vm.pruneStateDiffs(vm.stateDiffs(), lastAccepted.Height()); err != nil {
+		return err
+	}
+	return vm.DB.Commit()
+}
+
+// pruneStateDiffs removes from [stateDiffs] the diffs that aren't within
+// [vm.stateHistoryDepth] of [lastAcceptedHeight]
+func (vm *VM) pruneStateDiffs(stateDiffs database.Database, lastAcceptedHeight uint64) error {
+	if lastAcceptedHeight <= vm.stateHistoryDepth {
+		return nil
+	}
+	for height := lastAcceptedHeight - vm.stateHistoryDepth; height > 0; height-- {
+		hasDiff, err := stateDiffs.Has(stateDiffHeightKey(height))
+		if err != nil {
+			return err
+		}
+		if !hasDiff {
+			return nil
+		}
+		if err := vm.deleteStateDiff(stateDiffs, height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteStateDiff removes the state diff at [height] from [stateDiffs]
+func (vm *VM) deleteStateDiff(stateDiffs database.Database, height uint64) error {
+	heightKey := stateDiffHeightKey(height)
+	diff := prefixdb.NewNested(heightKey, stateDiffs)
+	iter := diff.NewIterator()
+	keys := [][]byte(nil)
+	for iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := diff.Delete(key); err != nil {
+			return err
+		}
+	}
+	return stateDiffs.Delete(heightKey)
+}
+
+// stateAtHeight returns the state of the chain after the accepted block at
+// [height]. Writes to the returned database are never committed.
+func (vm *VM) stateAtHeight(height uint64) (database.Database, error) {
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		return nil, err
+	}
+	lastAcceptedHeight := lastAccepted.Height()
+	switch {
+	case height > lastAcceptedHeight:
+		return nil, fmt.Errorf("%w: %d > %d", errHeightNotAccepted, height, lastAcceptedHeight)
+	case height == lastAcceptedHeight:
+		return vm.DB, nil
+	}
+
+	stateDiffs := vm.stateDiffs()
+	state := versiondb.New(vm.DB)
+	for diffHeight := lastAcceptedHeight; diffHeight > height; diffHeight-- {
+		heightKey := stateDiffHeightKey(diffHeight)
+		hasDiff, err := stateDiffs.Has(heightKey)
+		if err != nil {
+			return nil, err
+		}
+		if !hasDiff {
+			return nil, fmt.Errorf("%w: %d", errStateHistoryPruned, height)
+		}
+		if err := undoStateDiff(state, prefixdb.NewNested(heightKey, stateDiffs)); err != nil {
+			return nil, fmt.Errorf("couldn't undo the state diff at height %d: %w", diffHeight, err)
+		}
+	}
+	return state, nil
+}
+
+// undoStateDiff writes to [state] the value each key in [diff] had before the
+// diff's blocks were accepted
+func undoStateDiff(state database.KeyValueWriter, diff database.Iteratee) error {
+	iter := diff.NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		key := iter.Key()
+		value := iter.Value()
+		switch {
+		case len(value) == 0:
+			return fmt.Errorf("state diff of key 0x%x is empty", key)
+		case value[0] == keyDidntExist:
+			if err := state.Delete(key); err != nil {
+				return err
+			}
+		default:
+			if err := state.Put(key, value[1:]); err != nil {
+				return err
+			}
+		}
+	}
+	return iter.Error()
+}
+
+// stateDiffWriter records in [diff] the value that each key written to it
+// has in [state]
+type stateDiffWriter struct {
+	state database.KeyValueReader
+	diff  database.KeyValueWriter
+}
+
+// Put implements the database.KeyValueWriter interface
+func (w *stateDiffWriter) Put(key, _ []byte) error { return w.record(key) }
+
+// Delete implements the database.KeyValueWriter interface
+func (w *stateDiffWriter) Delete(key []byte) error { return w.record(key) }
+
+func (w *stateDiffWriter) record(key []byte) error {
+	value, err := w.state.Get(key)
+	switch err {
+	case nil:
+		return w.diff.Put(key, append([]byte{keyExisted}, value...))
+	case database.ErrNotFound:
+		return w.diff.Put(key, []byte{keyDidntExist})
+	default:
+		return err
+	}
+}
@@ -0,0 +1,185 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+// acceptExportTx accepts a block, built on the last accepted block, that
+// exports [amount] of keys[0]'s funds
+func acceptExportTx(t *testing.T, vm *VM, amount uint64) {
+	tx, err := vm.newExportTx(
+		amount,
+		avmID,
+		ids.GenerateTestShortID(),
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.SetPreference(vm.LastAccepted())
+	if err := vm.mempool.IssueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getBalanceAt(service *Service, addr string, height *json.Uint64) (uint64, error) {
+	args := GetBalanceArgs{
+		JSONAddress: api.JSONAddress{Address: addr},
+		Height:      height,
+	}
+	reply := GetBalanceResponse{}
+	err := service.GetBalance(nil, &args, &reply)
+	return uint64(reply.Balance), err
+}
+
+// Ensure balances can be queried at recently accepted heights and that older
+// heights are pruned
+func TestStateHistory(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+	vm.stateHistoryDepth = 2
+	service := &Service{vm: vm}
+
+	addr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	startHeight := lastAccepted.Height()
+	startBalance, err := getBalanceAt(service, addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exportAmount := uint64(100)
+	acceptExportTx(t, vm, exportAmount)
+	endBalance := startBalance - exportAmount - vm.txFee
+
+	if balance, err := getBalanceAt(service, addr, nil); err != nil {
+		t.Fatal(err)
+	} else if balance != endBalance {
+		t.Fatalf("expected current balance %d but got %d", endBalance, balance)
+	}
+	startHeightArg := json.Uint64(startHeight)
+	if balance, err := getBalanceAt(service, addr, &startHeightArg); err != nil {
+		t.Fatal(err)
+	} else if balance != startBalance {
+		t.Fatalf("expected balance %d at height %d but got %d", startBalance, startHeight, balance)
+	}
+
+	futureHeight := json.Uint64(startHeight + 2)
+	if _, err := getBalanceAt(service, addr, &futureHeight); !errors.Is(err, errHeightNotAccepted) {
+		t.Fatalf("expected %s but got %v", errHeightNotAccepted, err)
+	}
+
+	// Once [vm.stateHistoryDepth] more blocks are accepted, the state at
+	// [startHeight] is no longer kept
+	acceptExportTx(t, vm, exportAmount)
+	acceptExportTx(t, vm, exportAmount)
+	if _, err := getBalanceAt(service, addr, &startHeightArg); !errors.Is(err, errStateHistoryPruned) {
+		t.Fatalf("expected %s but got %v", errStateHistoryPruned, err)
+	}
+	exportHeight := json.Uint64(startHeight + 1)
+	if balance, err := getBalanceAt(service, addr, &exportHeight); err != nil {
+		t.Fatal(err)
+	} else if balance != endBalance {
+		t.Fatalf("expected balance %d at height %d but got %d", endBalance, exportHeight, balance)
+	}
+}
+
+// Ensure that the state diffs kept by a previous run with a larger state
+// history depth are pruned on restart
+func TestStateHistoryPrunedAfterDepthLowered(t *testing.T) {
+	baseDB := memdb.New()
+	vm, ctx, err := newGenesisVMWithStateHistory(baseDB, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.Lock.Lock()
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	startHeight := lastAccepted.Height()
+	for i := 0; i < 3; i++ {
+		acceptExportTx(t, vm, 100)
+	}
+	if err := vm.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	ctx.Lock.Unlock()
+
+	vm, ctx, err = newGenesisVMWithStateHistory(baseDB, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	// Only the diff of the last accepted block is kept
+	stateDiffs := vm.stateDiffs()
+	for height := startHeight + 1; height <= startHeight+3; height++ {
+		hasDiff, err := stateDiffs.Has(stateDiffHeightKey(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := height == startHeight+3; hasDiff != expected {
+			t.Fatalf("expected the diff at height %d to be kept: %t", height, expected)
+		}
+	}
+	iter := prefixdb.NewNested(stateDiffHeightKey(startHeight+1), stateDiffs).NewIterator()
+	defer iter.Release()
+	if iter.Next() {
+		t.Fatalf("expected the diff at height %d to be removed", startHeight+1)
+	}
+
+	service := &Service{vm: vm}
+	addr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prunedHeight := json.Uint64(startHeight + 1)
+	if _, err := getBalanceAt(service, addr, &prunedHeight); !errors.Is(err, errStateHistoryPruned) {
+		t.Fatalf("expected %s but got %v", errStateHistoryPruned, err)
+	}
+	keptHeight := json.Uint64(startHeight + 2)
+	if _, err := getBalanceAt(service, addr, &keptHeight); err != nil {
+		t.Fatal(err)
+	}
+}
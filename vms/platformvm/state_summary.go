@@ -0,0 +1,988 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
+	"github.com/ava-labs/avalanchego/vms/components/statetree"
+)
+
+// This file contains the methods of VM that summarize the state of the chain,
+// so that a new node can sync the state rather than executing every block
+// since genesis.
+//
+// A summary is built at the first decision block accepted at or after each
+// multiple of [stateSummaryInterval]. Because the summary only depends on the
+// state after that block, every node builds the same summary, which lets a
+// syncing node trust the summary sent by a majority of the validators. The
+// summary is built in the background, from the state after that block, while
+// later blocks are accepted.
+//
+// The summary covers the UTXOs, stakers, subnets, chains, supply, fee state and
+// timestamp of the chain. It doesn't cover atomic UTXOs that were exported to, but not
+// yet imported by, another chain. Those live in shared memory, which is synced
+// by the other chain, not by this one.
+
+const (
+	// A summary is built at the first decision block accepted at or after each
+	// multiple of this height
+	defaultStateSummaryInterval = 4096
+
+	// Chunks are split once they reach about this many bytes
+	targetStateChunkSize = 1 << 20 // 1 MiB
+
+	// A summary is built in slices of this many UTXOs, this long apart, so
+	// that building it doesn't stop the chain from making progress
+	stateSummarySliceSize  = 4096
+	stateSummarySliceDelay = 10 * time.Millisecond
+
+	stateSummaryDBPrefix          = "stateSummary"
+	stateSummaryBuildDBPrefix     = "stateSummaryBuild"
+	stateSummaryPreimagesDBPrefix = "stateSummaryPreimages"
+)
+
+var (
+	// If the status of this ID is choices.Accepted, every UTXO is in the UTXO
+	// set index
+	utxoSetIndexedID = ids.ID{'u', 't', 'x', 'o', ' ', 's', 'e', 't'}
+
+	// Key of the most recent summary in the summary DB. The chunks of a
+	// summary are kept in the chunk DB of its height, keyed by their index.
+	stateSummaryKey = []byte("summary")
+
+	// Keys of the block whose summary is being built, and of how far the build
+	// has got, in the summary build DB
+	stateSummaryBuildBlockKey    = []byte("block")
+	stateSummaryBuildProgressKey = []byte("progress")
+
+	// If this key is set, the state is being synced from the summary that is
+	// its value, and the database may only hold part of the synced state
+	stateSyncingKey = []byte("stateSyncing")
+
+	errUnknownStateSummary  = errors.New("unknown state summary")
+	errInvalidChunkIndex    = errors.New("invalid chunk index")
+	errInvalidChunkHash     = errors.New("chunk doesn't match the summary")
+	errWrongNumChunks       = errors.New("wrong number of chunks")
+	errSummaryNotOnDecision = errors.New("state summary must be taken at a decision block")
+
+	_ block.StateSyncableVM = &VM{}
+	_ block.StateSummary    = &stateSummary{}
+)
+
+// stateSummary describes the state of the chain after the accepted decision
+// block [Block]
+type stateSummary struct {
+	Block         []byte     `serialize:"true"`
+	Timestamp     uint64     `serialize:"true"` // Unix time in seconds
+	CurrentSupply uint64     `serialize:"true"`
+	Fees          fees.State `serialize:"true"`
+	ChunkHashes   []ids.ID   `serialize:"true"`
+
+	id     ids.ID
+	height uint64
+	bytes  []byte
+}
+
+// ID implements the block.StateSummary interface
+func (s *stateSummary) ID() ids.ID { return s.id }
+
+// Height implements the block.StateSummary interface
+func (s *stateSummary) Height() uint64 { return s.height }
+
+// Bytes implements the block.StateSummary interface
+func (s *stateSummary) Bytes() []byte { return s.bytes }
+
+// NumChunks implements the block.StateSummary interface
+func (s *stateSummary) NumChunks() uint32 { return uint32(len(s.ChunkHashes)) }
+
+// VerifyChunk implements the block.StateSummary interface
+func (s *stateSummary) VerifyChunk(index uint32, chunk []byte) error {
+	if index >= s.NumChunks() {
+		return errInvalidChunkIndex
+	}
+	if hashing.ComputeHash256Array(chunk) != s.ChunkHashes[index] {
+		return errInvalidChunkHash
+	}
+	return nil
+}
+
+// stateChunk is part of the state described by a stateSummary
+type stateChunk struct {
+	UTXOs          []*avax.UTXO     `serialize:"true"`
+	Subnets        []*Tx            `serialize:"true"`
+	Chains         []*Tx            `serialize:"true"`
+	PendingStakers []*pendingStaker `serialize:"true"`
+	CurrentStakers []*currentStaker `serialize:"true"`
+}
+
+// pendingStaker is a staker that will start staking on subnet [SubnetID]
+type pendingStaker struct {
+	SubnetID ids.ID `serialize:"true"`
+	Tx       Tx     `serialize:"true"`
+}
+
+// currentStaker is a staker that is staking on subnet [SubnetID]
+type currentStaker struct {
+	SubnetID ids.ID   `serialize:"true"`
+	Staker   rewardTx `serialize:"true"`
+}
+
+// chunkBuilder splits the state into chunks of about [targetStateChunkSize].
+// Each chunk is written to [db], keyed by its index, once it's full, so only
+// the chunk being filled is kept in memory.
+type chunkBuilder struct {
+	db database.KeyValueWriter
+	*chunkProgress
+}
+
+// chunkProgress is the part of a chunkBuilder that is persisted while a
+// summary is being built
+type chunkProgress struct {
+	// Hashes of the chunks written so far
+	ChunkHashes []ids.ID `serialize:"true"`
+
+	// If [Filling], [Chunk] is being filled and holds items of [Size] bytes
+	Filling bool       `serialize:"true"`
+	Chunk   stateChunk `serialize:"true"`
+	Size    uint64     `serialize:"true"`
+}
+
+// next returns the chunk that an item of [size] bytes should be added to
+func (b *chunkBuilder) next(size int) (*stateChunk, error) {
+	if !b.Filling || (b.Size > 0 && b.Size+uint64(size) > targetStateChunkSize) {
+		if err := b.flush(); err != nil {
+			return nil, err
+		}
+		b.Filling = true
+		b.Chunk = stateChunk{}
+		b.Size = 0
+	}
+	b.Size += uint64(size)
+	return &b.Chunk, nil
+}
+
+// flush writes the chunk being filled, if any, to [b.db]
+func (b *chunkBuilder) flush() error {
+	if !b.Filling {
+		return nil
+	}
+	chunkBytes, err := GenesisCodec.Marshal(codecVersion, &b.Chunk)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal state chunk: %w", err)
+	}
+	if err := b.db.Put(stateChunkKey(uint32(len(b.ChunkHashes))), chunkBytes); err != nil {
+		return err
+	}
+	b.ChunkHashes = append(b.ChunkHashes, hashing.ComputeHash256Array(chunkBytes))
+	b.Filling = false
+	b.Chunk = stateChunk{}
+	return nil
+}
+
+// StateSummary implements the block.StateSyncableVM interface
+func (vm *VM) StateSummary() (block.StateSummary, error) {
+	if vm.lastStateSummary == nil {
+		return nil, block.ErrNoStateSummary
+	}
+	return vm.lastStateSummary, nil
+}
+
+// ParseStateSummary implements the block.StateSyncableVM interface
+func (vm *VM) ParseStateSummary(summaryBytes []byte) (block.StateSummary, error) {
+	return vm.parseStateSummary(summaryBytes)
+}
+
+func (vm *VM) parseStateSummary(summaryBytes []byte) (*stateSummary, error) {
+	summary := &stateSummary{}
+	if _, err := GenesisCodec.Unmarshal(summaryBytes, summary); err != nil {
+		return nil, fmt.Errorf("couldn't parse state summary: %w", err)
+	}
+	blk, err := vm.unmarshalBlockFunc(summary.Block)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse state summary's block: %w", err)
+	}
+	if _, ok := blk.(decision); !ok {
+		return nil, errSummaryNotOnDecision
+	}
+	summary.id = hashing.ComputeHash256Array(summaryBytes)
+	summary.height = blk.Height()
+	summary.bytes = summaryBytes
+	return summary, nil
+}
+
+// StateChunk implements the block.StateSyncableVM interface
+func (vm *VM) StateChunk(summaryID ids.ID, index uint32) ([]byte, error) {
+	if vm.lastStateSummary == nil || vm.lastStateSummary.ID() != summaryID {
+		return nil, errUnknownStateSummary
+	}
+	if index >= vm.lastStateSummary.NumChunks() {
+		return nil, errInvalidChunkIndex
+	}
+
+	chunkDB := vm.stateChunkDB(vm.lastStateSummary.Height())
+	defer chunkDB.Close()
+
+	return chunkDB.Get(stateChunkKey(index))
+}
+
+// stateChunkDB returns the database that the chunks of the summary at
+// [height] are kept in
+func (vm *VM) stateChunkDB(height uint64) *prefixdb.Database {
+	prefix := make([]byte, len(stateSummaryDBPrefix)+wrappers.LongLen)
+	copy(prefix, stateSummaryDBPrefix)
+	binary.BigEndian.PutUint64(prefix[len(stateSummaryDBPrefix):], height)
+	return prefixdb.NewNested(prefix, vm.DB)
+}
+
+// stateChunkKey returns the key of chunk [index] in the summary DB
+func stateChunkKey(index uint32) []byte {
+	key := make([]byte, wrappers.IntLen)
+	binary.BigEndian.PutUint32(key, index)
+	return key
+}
+
+// loadStateSummary sets [vm.lastStateSummary] to the most recent summary in
+// the database, if there is one
+func (vm *VM) loadStateSummary() error {
+	summaryDB := prefixdb.NewNested([]byte(stateSummaryDBPrefix), vm.DB)
+	defer summaryDB.Close()
+
+	summaryBytes, err := summaryDB.Get(stateSummaryKey)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	vm.lastStateSummary, err = vm.parseStateSummary(summaryBytes)
+	return err
+}
+
+// putStateSummary replaces the summary in the database with [summary], whose
+// chunks must already be in the chunk DB of its height
+func (vm *VM) putStateSummary(summary *stateSummary) error {
+	if vm.lastStateSummary != nil && vm.lastStateSummary.Height() != summary.Height() {
+		if err := vm.deleteStateChunks(vm.lastStateSummary.Height()); err != nil {
+			return err
+		}
+	}
+
+	summaryDB := prefixdb.NewNested([]byte(stateSummaryDBPrefix), vm.DB)
+	errs := wrappers.Errs{}
+	errs.Add(
+		summaryDB.Put(stateSummaryKey, summary.Bytes()),
+		summaryDB.Close(),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+
+	vm.lastStateSummary = summary
+	return nil
+}
+
+// deleteStateChunks deletes the chunks of the summary at [height]
+func (vm *VM) deleteStateChunks(height uint64) error {
+	chunkDB := vm.stateChunkDB(height)
+	defer chunkDB.Close()
+
+	return clearDB(chunkDB)
+}
+
+// stateSummaryBuild is a summary of the state after the accepted decision
+// block [blk] that is being built in the background.
+//
+// The build is persisted, so that a node that restarts while building a
+// summary resumes it rather than building one at a later block than the other
+// nodes. The chunks built so far are in the chunk DB of [blk]'s height. The
+// value that each key changed by the blocks accepted after [blk] had after
+// [blk] was accepted is in the summary preimages DB, encoded like a state diff.
+// Undoing them on top of [vm.DB] gives the state after [blk].
+type stateSummaryBuild struct {
+	blk *CommonBlock
+	stateSummaryProgress
+
+	startTime time.Time
+}
+
+// stateSummaryProgress is how far a summary build has got
+type stateSummaryProgress struct {
+	// The key in the UTXO set that the next slice starts at. If [UTXOsDone],
+	// every UTXO has been added to the chunks.
+	NextUTXOKey []byte        `serialize:"true"`
+	UTXOsDone   bool          `serialize:"true"`
+	Chunks      chunkProgress `serialize:"true"`
+}
+
+// maybeBuildStateSummary starts building a summary of the state after the
+// decision block [blk] if it's the first decision block accepted at or after a
+// multiple of [vm.stateSummaryInterval]. Must be called while [blk] is being
+// accepted, before its changes to [vm.DB] are committed, so that the build is
+// persisted along with them.
+//
+// The summary is built in slices of [stateSummarySliceSize] UTXOs by
+// [vm.stateSummaryTimer], so that accepting [blk] doesn't wait for the whole
+// state to be walked.
+func (vm *VM) maybeBuildStateSummary(blk *CommonBlock) error {
+	lastHeight := uint64(0)
+	switch {
+	case vm.stateSummaryBuild != nil:
+		lastHeight = vm.stateSummaryBuild.blk.Height()
+	case vm.lastStateSummary != nil:
+		lastHeight = vm.lastStateSummary.Height()
+	}
+	if blk.Height()/vm.stateSummaryInterval <= lastHeight/vm.stateSummaryInterval {
+		return nil
+	}
+
+	if vm.stateSummaryBuild != nil {
+		vm.Ctx.Log.Warn("abandoning state summary at height %d as the summary at height %d is due",
+			vm.stateSummaryBuild.blk.Height(), blk.Height())
+		if err := vm.deleteStateSummaryBuild(vm.stateSummaryBuild); err != nil {
+			return err
+		}
+		vm.stateSummaryBuild = nil
+	}
+	build := &stateSummaryBuild{
+		blk:       blk,
+		startTime: time.Now(),
+	}
+	if err := vm.putStateSummaryBuild(build); err != nil {
+		return err
+	}
+	vm.stateSummaryBuild = build
+	vm.stateSummaryTimer.SetTimeoutIn(0)
+	return nil
+}
+
+// putStateSummaryBuild persists [build]
+func (vm *VM) putStateSummaryBuild(build *stateSummaryBuild) error {
+	progressBytes, err := GenesisCodec.Marshal(codecVersion, &build.stateSummaryProgress)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal state summary progress: %w", err)
+	}
+	blkID := build.blk.ID()
+
+	buildDB := prefixdb.NewNested([]byte(stateSummaryBuildDBPrefix), vm.DB)
+	errs := wrappers.Errs{}
+	errs.Add(
+		buildDB.Put(stateSummaryBuildBlockKey, blkID[:]),
+		buildDB.Put(stateSummaryBuildProgressKey, progressBytes),
+		buildDB.Close(),
+	)
+	return errs.Err
+}
+
+// clearStateSummaryBuild deletes the persisted summary build, other than its
+// chunks
+func (vm *VM) clearStateSummaryBuild() error {
+	buildDB := prefixdb.NewNested([]byte(stateSummaryBuildDBPrefix), vm.DB)
+	preimagesDB := prefixdb.NewNested([]byte(stateSummaryPreimagesDBPrefix), vm.DB)
+	errs := wrappers.Errs{}
+	errs.Add(
+		clearDB(buildDB),
+		clearDB(preimagesDB),
+		buildDB.Close(),
+		preimagesDB.Close(),
+	)
+	return errs.Err
+}
+
+// deleteStateSummaryBuild deletes [build], including the chunks it has built,
+// from the database
+func (vm *VM) deleteStateSummaryBuild(build *stateSummaryBuild) error {
+	if err := vm.clearStateSummaryBuild(); err != nil {
+		return err
+	}
+	return vm.deleteStateChunks(build.blk.Height())
+}
+
+// loadStateSummaryBuild resumes the summary build that was in progress when
+// the VM was last shut down, if there was one
+func (vm *VM) loadStateSummaryBuild() error {
+	buildDB := prefixdb.NewNested([]byte(stateSummaryBuildDBPrefix), vm.DB)
+	defer buildDB.Close()
+
+	blkIDBytes, err := buildDB.Get(stateSummaryBuildBlockKey)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	blkID, err := ids.ToID(blkIDBytes)
+	if err != nil {
+		return err
+	}
+	blk, err := vm.getBlock(blkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s of state summary build: %w", blkID, err)
+	}
+	build := &stateSummaryBuild{startTime: time.Now()}
+	switch blk := blk.(type) {
+	case *StandardBlock:
+		build.blk = &blk.CommonBlock
+	case *AtomicBlock:
+		build.blk = &blk.CommonBlock
+	case *Commit:
+		build.blk = &blk.CommonBlock
+	case *Abort:
+		build.blk = &blk.CommonBlock
+	default:
+		return errSummaryNotOnDecision
+	}
+
+	progressBytes, err := buildDB.Get(stateSummaryBuildProgressKey)
+	if err != nil {
+		return err
+	}
+	if _, err := GenesisCodec.Unmarshal(progressBytes, &build.stateSummaryProgress); err != nil {
+		return fmt.Errorf("couldn't parse state summary progress: %w", err)
+	}
+
+	vm.Ctx.Log.Info("resuming state summary at height %d", build.blk.Height())
+	vm.stateSummaryBuild = build
+	vm.stateSummaryTimer.SetTimeoutIn(0)
+	return nil
+}
+
+// recordStateSummaryPreimages records the value that each key changed by the
+// block being accepted had before it was accepted, if a summary is being
+// built. Must be called before the block's changes to [vm.DB] are committed.
+func (vm *VM) recordStateSummaryPreimages() error {
+	if vm.stateSummaryBuild == nil {
+		return nil
+	}
+	batch, err := vm.DB.CommitBatch()
+	if err != nil {
+		return err
+	}
+
+	preimagesDB := prefixdb.NewNested([]byte(stateSummaryPreimagesDBPrefix), vm.DB)
+	defer preimagesDB.Close()
+
+	return batch.Replay(&preimageWriter{
+		state:     vm.DB.GetDatabase(),
+		preimages: preimagesDB,
+	})
+}
+
+// buildStateSummarySlice adds the next slice of the state to the summary being
+// built. Once the whole state has been added, the summary is persisted and
+// served. Otherwise, the progress is persisted and the next slice is
+// scheduled.
+// Assumes [vm.Ctx.Lock] is held.
+func (vm *VM) buildStateSummarySlice() {
+	build := vm.stateSummaryBuild
+	if build == nil {
+		return
+	}
+
+	summary, err := vm.continueStateSummary(build, stateSummarySliceSize)
+	if err != nil {
+		vm.Ctx.Log.Warn("couldn't build state summary at block %s: %s", build.blk.ID(), err)
+		vm.DB.Abort()
+		vm.stateSummaryBuild = nil
+		if err := vm.deleteStateSummaryBuild(build); err != nil {
+			vm.Ctx.Log.Warn("couldn't delete state summary build: %s", err)
+			vm.DB.Abort()
+			return
+		}
+		if err := vm.DB.Commit(); err != nil {
+			vm.Ctx.Log.Warn("couldn't commit deletion of state summary build: %s", err)
+		}
+		return
+	}
+	if summary == nil {
+		if err := vm.DB.Commit(); err != nil {
+			vm.Ctx.Log.Warn("couldn't commit state summary progress: %s", err)
+			vm.stateSummaryBuild = nil
+			return
+		}
+		vm.stateSummaryTimer.SetTimeoutIn(stateSummarySliceDelay)
+		return
+	}
+
+	vm.stateSummaryBuild = nil
+	if err := vm.clearStateSummaryBuild(); err != nil {
+		vm.Ctx.Log.Warn("couldn't clear state summary build: %s", err)
+		vm.DB.Abort()
+		return
+	}
+	if err := vm.putStateSummary(summary); err != nil {
+		vm.Ctx.Log.Warn("couldn't persist state summary: %s", err)
+		vm.DB.Abort()
+		return
+	}
+	if err := vm.DB.Commit(); err != nil {
+		vm.Ctx.Log.Warn("couldn't commit state summary: %s", err)
+		return
+	}
+	vm.Ctx.Log.Info("built state summary %s at height %d with %d chunks in %s",
+		summary.ID(), summary.Height(), summary.NumChunks(), time.Since(build.startTime))
+}
+
+// continueStateSummary adds up to [maxUTXOs] UTXOs to [build] and writes the
+// chunks that were filled to the chunk DB of its height. Once every UTXO has
+// been added, the rest of the state is added and the summary is returned.
+// Otherwise, the progress of [build] is written to [vm.DB] and the returned
+// summary is nil. The caller commits [vm.DB].
+func (vm *VM) continueStateSummary(build *stateSummaryBuild, maxUTXOs int) (*stateSummary, error) {
+	preimagesDB := prefixdb.NewNested([]byte(stateSummaryPreimagesDBPrefix), vm.DB)
+	defer preimagesDB.Close()
+
+	state := versiondb.New(vm.DB)
+	if err := undoStateDiff(state, preimagesDB); err != nil {
+		return nil, err
+	}
+
+	chunkDB := vm.stateChunkDB(build.blk.Height())
+	defer chunkDB.Close()
+
+	builder := &chunkBuilder{
+		db:            chunkDB,
+		chunkProgress: &build.Chunks,
+	}
+	if !build.UTXOsDone {
+		nextUTXOKey, err := vm.addUTXOsToChunks(state, builder, build.NextUTXOKey, maxUTXOs)
+		if err != nil {
+			return nil, err
+		}
+		build.NextUTXOKey = nextUTXOKey
+		build.UTXOsDone = nextUTXOKey == nil
+		if !build.UTXOsDone {
+			return nil, vm.putStateSummaryBuild(build)
+		}
+	}
+	return vm.finishStateSummary(state, build.blk, builder)
+}
+
+// finishStateSummary adds the subnets, chains and stakers in [state], which
+// must be the state after the accepted decision block [blk], to the chunks in
+// [builder], which hold every UTXO in [state]. Returns the summary of [state]
+// once its last chunk has been written.
+func (vm *VM) finishStateSummary(state database.Database, blk *CommonBlock, builder *chunkBuilder) (*stateSummary, error) {
+	timestamp, err := vm.getTimestamp(state)
+	if err != nil {
+		return nil, err
+	}
+	currentSupply, err := vm.getCurrentSupply(state)
+	if err != nil {
+		return nil, err
+	}
+	feeState, err := vm.getFeeState(state)
+	if err != nil {
+		return nil, err
+	}
+	subnets, err := vm.getSubnets(state)
+	if err != nil {
+		return nil, err
+	}
+	chains, err := vm.getChains(state)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range subnets {
+		chunk, err := builder.next(len(tx.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		chunk.Subnets = append(chunk.Subnets, tx)
+	}
+	for _, tx := range chains {
+		chunk, err := builder.next(len(tx.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		chunk.Chains = append(chunk.Chains, tx)
+	}
+
+	subnetIDs := []ids.ID{constants.PrimaryNetworkID}
+	for _, tx := range subnets {
+		subnetIDs = append(subnetIDs, tx.ID())
+	}
+	for _, subnetID := range subnetIDs {
+		if err := vm.addStakersToChunks(state, builder, subnetID); err != nil {
+			return nil, err
+		}
+	}
+	if err := builder.flush(); err != nil {
+		return nil, err
+	}
+
+	summary := &stateSummary{
+		Block:         blk.Bytes(),
+		Timestamp:     uint64(timestamp.Unix()),
+		CurrentSupply: currentSupply,
+		Fees:          feeState,
+		ChunkHashes:   builder.ChunkHashes,
+	}
+	summaryBytes, err := GenesisCodec.Marshal(codecVersion, summary)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal state summary: %w", err)
+	}
+	summary.id = hashing.ComputeHash256Array(summaryBytes)
+	summary.height = blk.Height()
+	summary.bytes = summaryBytes
+	return summary, nil
+}
+
+// addUTXOsToChunks adds up to [maxUTXOs] UTXOs in [state], starting at key
+// [start] of the UTXO set, to the chunks in [builder]. If [maxUTXOs] is
+// negative, every UTXO is added. Returns the key the next UTXO should be added
+// from, or nil if every UTXO has been added.
+func (vm *VM) addUTXOsToChunks(state database.Database, builder *chunkBuilder, start []byte, maxUTXOs int) ([]byte, error) {
+	utxoSetDB := prefixdb.NewNested([]byte(utxoSetDBPrefix), state)
+	defer utxoSetDB.Close()
+
+	iter := utxoSetDB.NewIteratorWithStart(start)
+	defer iter.Release()
+
+	for numUTXOs := 0; iter.Next(); numUTXOs++ {
+		if numUTXOs == maxUTXOs {
+			return append([]byte(nil), iter.Key()...), nil
+		}
+		utxoID, err := ids.ToID(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		utxo, err := vm.getUTXO(state, utxoID)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
+		}
+		utxoBytes, err := vm.codec.Marshal(codecVersion, utxo)
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := builder.next(len(utxoBytes))
+		if err != nil {
+			return nil, err
+		}
+		chunk.UTXOs = append(chunk.UTXOs, utxo)
+	}
+	return nil, iter.Error()
+}
+
+// addStakersToChunks adds the pending and current stakers of subnet
+// [subnetID] in [state] to the chunks in [builder]
+func (vm *VM) addStakersToChunks(state database.Database, builder *chunkBuilder, subnetID ids.ID) error {
+	startDB := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", subnetID, startDBPrefix)), state)
+	defer startDB.Close()
+
+	startIter := startDB.NewIterator()
+	defer startIter.Release()
+
+	for startIter.Next() {
+		txBytes := startIter.Value()
+		staker := &pendingStaker{SubnetID: subnetID}
+		if _, err := vm.codec.Unmarshal(txBytes, &staker.Tx); err != nil {
+			return fmt.Errorf("couldn't unmarshal pending staker: %w", err)
+		}
+		chunk, err := builder.next(len(txBytes))
+		if err != nil {
+			return err
+		}
+		chunk.PendingStakers = append(chunk.PendingStakers, staker)
+	}
+	if err := startIter.Error(); err != nil {
+		return err
+	}
+
+	stopDB := prefixdb.NewNested([]byte(fmt.Sprintf("%s%s", subnetID, stopDBPrefix)), state)
+	defer stopDB.Close()
+
+	stopIter := stopDB.NewIterator()
+	defer stopIter.Release()
+
+	for stopIter.Next() {
+		txBytes := stopIter.Value()
+		staker := &currentStaker{SubnetID: subnetID}
+		if _, err := vm.codec.Unmarshal(txBytes, &staker.Staker); err != nil {
+			return fmt.Errorf("couldn't unmarshal current staker: %w", err)
+		}
+		chunk, err := builder.next(len(txBytes))
+		if err != nil {
+			return err
+		}
+		chunk.CurrentStakers = append(chunk.CurrentStakers, staker)
+	}
+	return stopIter.Error()
+}
+
+// preimageWriter records in [preimages] the value that each key written to it
+// has in [state], unless the key's value was already recorded
+type preimageWriter struct {
+	state     database.KeyValueReader
+	preimages database.Database
+}
+
+// Put implements the database.KeyValueWriter interface
+func (w *preimageWriter) Put(key, _ []byte) error { return w.record(key) }
+
+// Delete implements the database.KeyValueWriter interface
+func (w *preimageWriter) Delete(key []byte) error { return w.record(key) }
+
+func (w *preimageWriter) record(key []byte) error {
+	if has, err := w.preimages.Has(key); err != nil || has {
+		return err
+	}
+	return (&stateDiffWriter{state: w.state, diff: w.preimages}).record(key)
+}
+
+// SyncState implements the block.StateSyncableVM interface
+func (vm *VM) SyncState(summaryIntf block.StateSummary, chunkBytes [][]byte) error {
+	summary, err := vm.parseStateSummary(summaryIntf.Bytes())
+	if err != nil {
+		return err
+	}
+	if len(chunkBytes) != len(summary.ChunkHashes) {
+		return fmt.Errorf("%w: expected %d but got %d", errWrongNumChunks, len(summary.ChunkHashes), len(chunkBytes))
+	}
+
+	for i, bytes := range chunkBytes {
+		if err := summary.VerifyChunk(uint32(i), bytes); err != nil {
+			return fmt.Errorf("couldn't verify chunk %d: %w", i, err)
+		}
+	}
+
+	blk, err := vm.unmarshalBlockFunc(summary.Block)
+	if err != nil {
+		return err
+	}
+	blkID := blk.ID()
+
+	// Replace the genesis state with the synced state. The chunks are parsed
+	// and committed one at a time, so the sync is marked as in progress until
+	// the last one is. If the node stops before then, the partially synced
+	// state is discarded when the VM is next initialized.
+	if err := clearDB(vm.DB); err != nil {
+		return fmt.Errorf("couldn't clear database: %w", err)
+	}
+	vm.lastStateSummary = nil
+	vm.stateSummaryBuild = nil
+	if err := vm.DB.Put(stateSyncingKey, summary.Bytes()); err != nil {
+		return err
+	}
+	if err := vm.DB.Commit(); err != nil {
+		return err
+	}
+
+	chunkDB := vm.stateChunkDB(summary.Height())
+	defer chunkDB.Close()
+
+	var subnets, chains []*Tx
+	for i, bytes := range chunkBytes {
+		chunk := &stateChunk{}
+		if _, err := GenesisCodec.Unmarshal(bytes, chunk); err != nil {
+			return fmt.Errorf("couldn't parse chunk %d: %w", i, err)
+		}
+		if err := vm.syncStateChunk(chunk); err != nil {
+			return err
+		}
+		// The chunk is kept so that it can be served to other nodes
+		if err := chunkDB.Put(stateChunkKey(uint32(i)), bytes); err != nil {
+			return err
+		}
+		if err := vm.DB.Commit(); err != nil {
+			return fmt.Errorf("couldn't commit chunk %d: %w", i, err)
+		}
+		subnets = append(subnets, chunk.Subnets...)
+		chains = append(chains, chunk.Chains...)
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		vm.putSubnets(vm.DB, subnets),
+		vm.putChains(vm.DB, chains),
+		vm.putTimestamp(vm.DB, time.Unix(int64(summary.Timestamp), 0)),
+		vm.putCurrentSupply(vm.DB, summary.CurrentSupply),
+		vm.putFeeState(vm.DB, summary.Fees),
+		vm.State.PutBlock(vm.DB, blk),
+		vm.State.PutStatus(vm.DB, blkID, choices.Accepted),
+		vm.State.PutLastAccepted(vm.DB, blkID),
+		vm.State.PutStatus(vm.DB, utxoSetIndexedID, choices.Accepted),
+		vm.SetDBInitialized(),
+		vm.putStateSummary(summary),
+		vm.DB.Delete(stateSyncingKey),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+	if err := vm.DB.Commit(); err != nil {
+		return err
+	}
+	vm.LastAcceptedID = blkID
+	vm.currentBlocks = make(map[ids.ID]Block)
+	vm.states = statetree.New(vm.DB, blkID)
+
+	// The history of the validator sets was cleared with the rest of the
+	// state, so they're kept from the synced height
+	if err := vm.initValidatorSets(); err != nil {
+		return err
+	}
+	if err := vm.initSubnets(); err != nil {
+		return err
+	}
+	if err := vm.initBlockchains(); err != nil {
+		return err
+	}
+	vm.SetPreference(blkID)
+
+	vm.Ctx.Log.Info("synced state summary %s. last accepted block is now %s at height %d",
+		summary.ID(), blkID, summary.Height())
+	return nil
+}
+
+// discardInterruptedStateSync clears [vm.DB] if the node stopped while syncing
+// its state, so that the VM is initialized from genesis again
+func (vm *VM) discardInterruptedStateSync() error {
+	syncing, err := vm.DB.Has(stateSyncingKey)
+	if err != nil || !syncing {
+		return err
+	}
+	vm.Ctx.Log.Warn("discarding the state of an interrupted state sync")
+	if err := clearDB(vm.DB); err != nil {
+		return fmt.Errorf("couldn't clear database: %w", err)
+	}
+	return vm.DB.Commit()
+}
+
+// syncStateChunk writes the UTXOs, stakers, subnets and chains in [chunk] to
+// [vm.DB]. The lists of subnets and chains are written by the caller.
+func (vm *VM) syncStateChunk(chunk *stateChunk) error {
+	for _, utxo := range chunk.UTXOs {
+		if err := vm.putUTXO(vm.DB, utxo); err != nil {
+			return err
+		}
+	}
+	for _, tx := range chunk.Subnets {
+		if err := tx.Sign(vm.codec, nil); err != nil {
+			return err
+		}
+		if err := vm.putCommittedTx(tx); err != nil {
+			return err
+		}
+	}
+	for _, tx := range chunk.Chains {
+		if err := tx.Sign(GenesisCodec, nil); err != nil {
+			return err
+		}
+		if err := vm.putCommittedTx(tx); err != nil {
+			return err
+		}
+	}
+	for _, staker := range chunk.PendingStakers {
+		if err := staker.Tx.Sign(vm.codec, nil); err != nil {
+			return err
+		}
+		if err := vm.enqueueStaker(vm.DB, staker.SubnetID, &staker.Tx); err != nil {
+			return err
+		}
+		if err := vm.putCommittedTx(&staker.Tx); err != nil {
+			return err
+		}
+	}
+	for _, staker := range chunk.CurrentStakers {
+		if err := staker.Staker.Tx.Sign(vm.codec, nil); err != nil {
+			return err
+		}
+		if err := vm.addStaker(vm.DB, staker.SubnetID, &staker.Staker); err != nil {
+			return err
+		}
+		if err := vm.putCommittedTx(&staker.Staker.Tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putCommittedTx persists [tx] with the status Committed
+func (vm *VM) putCommittedTx(tx *Tx) error {
+	txID := tx.ID()
+	errs := wrappers.Errs{}
+	errs.Add(
+		vm.putTx(vm.DB, txID, tx.Bytes()),
+		vm.putStatus(vm.DB, txID, Committed),
+	)
+	return errs.Err
+}
+
+// indexUTXOSet adds every UTXO in [vm.DB] to the UTXO set index, if the
+// database was created before the index existed
+func (vm *VM) indexUTXOSet() error {
+	if vm.State.GetStatus(vm.DB, utxoSetIndexedID) == choices.Accepted {
+		return nil
+	}
+	vm.Ctx.Log.Info("indexing the UTXO set. This only happens once...")
+
+	// UTXOs are keyed by their ID prefixed with [utxoTypeID], so a value is a
+	// UTXO iff it parses as a UTXO whose prefixed ID is its key
+	utxoIDs := []ids.ID(nil)
+	iter := vm.DB.NewIterator()
+	for iter.Next() {
+		utxo := avax.UTXO{}
+		if _, err := vm.codec.Unmarshal(iter.Value(), &utxo); err != nil {
+			continue
+		}
+		utxoID := utxo.InputID()
+		if key := utxoID.Prefix(utxoTypeID); !bytes.Equal(key[:], iter.Key()) {
+			continue
+		}
+		utxoIDs = append(utxoIDs, utxoID)
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return err
+	}
+
+	for _, utxoID := range utxoIDs {
+		if err := vm.putUTXOSetID(vm.DB, utxoID); err != nil {
+			return err
+		}
+	}
+	if err := vm.State.PutStatus(vm.DB, utxoSetIndexedID, choices.Accepted); err != nil {
+		return err
+	}
+	vm.Ctx.Log.Info("indexed %d UTXOs", len(utxoIDs))
+	return vm.DB.Commit()
+}
+
+// clearDB deletes every key in [db]
+func clearDB(db database.Database) error {
+	keys := [][]byte(nil)
+	iter := db.NewIterator()
+	for iter.Next() {
+		keys = append(keys, append([]byte(nil), iter.Key()...))
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,523 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/vms/components/core"
+	"github.com/ava-labs/avalanchego/vms/timestampvm"
+)
+
+// Ensure a summary is built once a decision block passes the summary interval
+// and that syncing it into a new VM reproduces the state
+func TestStateSummarySync(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	if _, err := vm.StateSummary(); err != block.ErrNoStateSummary {
+		t.Fatalf("expected %s but got %v", block.ErrNoStateSummary, err)
+	}
+
+	// Build a summary at the next decision block
+	vm.stateSummaryInterval = 1
+	tx, err := vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		timestampvm.ID,
+		nil,
+		"name",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.mempool.IssueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The summary is built in the background, from the state after [blk], even
+	// if more blocks are accepted before it's built
+	if _, err := vm.StateSummary(); err != block.ErrNoStateSummary {
+		t.Fatalf("expected %s but got %v", block.ErrNoStateSummary, err)
+	}
+	vm.stateSummaryInterval = math.MaxUint64
+	laterTx, err := vm.newCreateChainTx(
+		testSubnet1.ID(),
+		nil,
+		timestampvm.ID,
+		nil,
+		"later",
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		ids.ShortEmpty, // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.mempool.IssueTx(laterTx); err != nil {
+		t.Fatal(err)
+	}
+	laterBlk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := laterBlk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := laterBlk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	for vm.stateSummaryBuild != nil {
+		vm.buildStateSummarySlice()
+	}
+
+	summary, err := vm.StateSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Height() != blk.Height() {
+		t.Fatalf("expected summary at height %d but got %d", blk.Height(), summary.Height())
+	}
+	if summary.NumChunks() == 0 {
+		t.Fatal("summary should have chunks")
+	}
+
+	chunks := make([][]byte, summary.NumChunks())
+	for i := range chunks {
+		chunks[i], err = vm.StateChunk(summary.ID(), uint32(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := summary.VerifyChunk(uint32(i), chunks[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := vm.StateChunk(ids.GenerateTestID(), 0); err != errUnknownStateSummary {
+		t.Fatalf("expected %s but got %v", errUnknownStateSummary, err)
+	}
+	if _, err := vm.StateChunk(summary.ID(), summary.NumChunks()); err != errInvalidChunkIndex {
+		t.Fatalf("expected %s but got %v", errInvalidChunkIndex, err)
+	}
+	corrupted := append([]byte{}, chunks[0]...)
+	corrupted[len(corrupted)-1]++
+	if err := summary.VerifyChunk(0, corrupted); err != errInvalidChunkHash {
+		t.Fatalf("expected %s but got %v", errInvalidChunkHash, err)
+	}
+
+	// Sync the summary into a VM that only has the genesis state
+	syncedVM, ctx, err := newGenesisVM(memdb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+		if err := syncedVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	parsedSummary, err := syncedVM.ParseStateSummary(summary.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsedSummary.ID() != summary.ID() {
+		t.Fatalf("expected summary %s but got %s", summary.ID(), parsedSummary.ID())
+	}
+	if err := syncedVM.SyncState(parsedSummary, chunks[1:]); err == nil {
+		t.Fatal("should have failed to sync with a missing chunk")
+	}
+	if err := syncedVM.SyncState(parsedSummary, chunks); err != nil {
+		t.Fatal(err)
+	}
+
+	if syncing, err := syncedVM.DB.Has(stateSyncingKey); err != nil {
+		t.Fatal(err)
+	} else if syncing {
+		t.Fatal("state sync should no longer be marked as in progress")
+	}
+	if lastAccepted := syncedVM.LastAccepted(); lastAccepted != blk.ID() {
+		t.Fatalf("expected last accepted block %s but got %s", blk.ID(), lastAccepted)
+	}
+	if status, err := syncedVM.getStatus(syncedVM.DB, tx.ID()); err != nil {
+		t.Fatal(err)
+	} else if status != Committed {
+		t.Fatalf("status should be Committed but is %s", status)
+	}
+	if _, err := syncedVM.getSubnet(syncedVM.DB, testSubnet1.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := syncedVM.getStatus(syncedVM.DB, laterTx.ID()); err == nil && status == Committed {
+		t.Fatal("tx accepted after the summary shouldn't be in the synced state")
+	}
+
+	// The synced state should summarize to the same summary
+	syncedBlk, err := syncedVM.getBlock(blk.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	standardBlk, ok := syncedBlk.(*StandardBlock)
+	if !ok {
+		t.Fatalf("expected a standard block but got %T", syncedBlk)
+	}
+	rebuiltSummary, _, err := syncedVM.buildStateSummary(&standardBlk.CommonBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuiltSummary.ID() != summary.ID() {
+		t.Fatalf("expected synced state to summarize to %s but got %s", summary.ID(), rebuiltSummary.ID())
+	}
+	if servedSummary, err := syncedVM.StateSummary(); err != nil {
+		t.Fatal(err)
+	} else if servedSummary.ID() != summary.ID() {
+		t.Fatalf("expected synced VM to serve summary %s but got %s", summary.ID(), servedSummary.ID())
+	}
+
+	// Blocks of other chains may reference a height before the synced VM kept
+	// the validator sets from, which is looked up as the synced height
+	syncedSet, err := syncedVM.GetValidatorSet(blk.Height(), constants.PrimaryNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(syncedSet) != len(keys) {
+		t.Fatalf("expected %d validators but got %d", len(keys), len(syncedSet))
+	}
+	earlierSet, err := syncedVM.GetValidatorSet(blk.Height()-1, constants.PrimaryNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(earlierSet) != len(syncedSet) {
+		t.Fatalf("expected %d validators before the synced height but got %d", len(syncedSet), len(earlierSet))
+	}
+	for nodeID, weight := range syncedSet {
+		if earlierSet[nodeID] != weight {
+			t.Fatalf("expected validator %s to have weight %d but it has %d", nodeID, weight, earlierSet[nodeID])
+		}
+	}
+}
+
+// Ensure a summary built in slices is the same as one built at once
+func TestStateSummaryBuiltInSlices(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	standardBlk, ok := lastAccepted.(*StandardBlock)
+	if !ok {
+		t.Fatalf("expected a standard block but got %T", lastAccepted)
+	}
+	commonBlk := &standardBlk.CommonBlock
+	summary, chunks, err := vm.buildStateSummary(commonBlk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := &stateSummaryBuild{blk: commonBlk}
+	numSlices := 0
+	for {
+		numSlices++
+		slicedSummary, err := vm.continueStateSummary(build, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if slicedSummary == nil {
+			continue
+		}
+		if slicedSummary.ID() != summary.ID() {
+			t.Fatalf("expected summary %s but got %s", summary.ID(), slicedSummary.ID())
+		}
+		break
+	}
+	if numSlices < 2 {
+		t.Fatalf("expected the summary to be built in more than one slice")
+	}
+
+	// The chunks were written to disk as they were built
+	chunkDB := vm.stateChunkDB(commonBlk.Height())
+	defer chunkDB.Close()
+	for i, chunk := range chunks {
+		slicedChunk, err := chunkDB.Get(stateChunkKey(uint32(i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(slicedChunk, chunk) {
+			t.Fatalf("chunk %d differs from the chunk built at once", i)
+		}
+	}
+	if _, err := chunkDB.Get(stateChunkKey(uint32(len(chunks)))); err != database.ErrNotFound {
+		t.Fatalf("expected %s but got %v", database.ErrNotFound, err)
+	}
+}
+
+// Ensure a node that restarts while building a summary resumes the build, so
+// that it builds the same summary as the nodes that didn't restart
+func TestStateSummaryBuildResumedAfterRestart(t *testing.T) {
+	baseDB := memdb.New()
+	vm, ctx, err := newGenesisVM(baseDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The slices of the summary are built by the test, so that the node is
+	// stopped before the summary is built
+	vm.stateSummaryTimer.Stop()
+	vm.stateSummaryTimer = timer.NewTimer(func() {})
+	go vm.stateSummaryTimer.Dispatch()
+
+	ctx.Lock.Lock()
+	if err := vm.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Start a summary at the next decision block
+	vm.stateSummaryInterval = 1
+	tx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[0].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+		keys[0].PublicKey().Address(), // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.mempool.IssueTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if vm.stateSummaryBuild == nil {
+		t.Fatal("should be building a state summary")
+	}
+	expectedSummary, _, err := vm.buildStateSummary(vm.stateSummaryBuild.blk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build part of the summary, then accept a block that changes the state
+	// the rest of the summary is built from
+	if summary, err := vm.continueStateSummary(vm.stateSummaryBuild, 1); err != nil {
+		t.Fatal(err)
+	} else if summary != nil {
+		t.Fatal("summary shouldn't have been built in one slice")
+	} else if err := vm.DB.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	vm.stateSummaryInterval = math.MaxUint64
+	laterTx, err := vm.newCreateSubnetTx(
+		1, // threshold
+		[]ids.ShortID{keys[1].PublicKey().Address()},
+		[]*crypto.PrivateKeySECP256K1R{keys[1]},
+		keys[1].PublicKey().Address(), // change addr
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := vm.mempool.IssueTx(laterTx); err != nil {
+		t.Fatal(err)
+	}
+	laterBlk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := laterBlk.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := laterBlk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+	ctx.Lock.Unlock()
+
+	restartedVM, ctx, err := newGenesisVM(baseDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.Lock.Lock()
+	defer func() {
+		if err := restartedVM.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	build := restartedVM.stateSummaryBuild
+	if build == nil {
+		t.Fatal("state summary build should have been resumed")
+	}
+	if build.blk.ID() != blk.ID() {
+		t.Fatalf("expected the build to be at block %s but it's at %s", blk.ID(), build.blk.ID())
+	}
+	if build.NextUTXOKey == nil {
+		t.Fatal("the build should have resumed where it stopped")
+	}
+	for restartedVM.stateSummaryBuild != nil {
+		restartedVM.buildStateSummarySlice()
+	}
+	summary, err := restartedVM.StateSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.ID() != expectedSummary.ID() {
+		t.Fatalf("expected summary %s but got %s", expectedSummary.ID(), summary.ID())
+	}
+	for i := uint32(0); i < summary.NumChunks(); i++ {
+		chunk, err := restartedVM.StateChunk(summary.ID(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := summary.VerifyChunk(i, chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing is left of the build
+	preimagesDB := prefixdb.NewNested([]byte(stateSummaryPreimagesDBPrefix), restartedVM.DB)
+	defer preimagesDB.Close()
+	iter := preimagesDB.NewIterator()
+	defer iter.Release()
+	if iter.Next() {
+		t.Fatal("the preimages of the build should have been deleted")
+	}
+}
+
+// Ensure the state of a sync that was interrupted is discarded
+func TestInterruptedStateSyncDiscarded(t *testing.T) {
+	baseDB := memdb.New()
+	vmDB := prefixdb.New([]byte{0}, baseDB)
+	garbageKey := []byte("partially synced")
+	if err := vmDB.Put(stateSyncingKey, nil); err != nil {
+		t.Fatal(err)
+	} else if err := vmDB.Put(garbageKey, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	vm, ctx, err := newGenesisVM(baseDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		ctx.Lock.Unlock()
+	}()
+
+	if has, err := vm.DB.Has(garbageKey); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("partially synced state should have been discarded")
+	}
+	if has, err := vm.DB.Has(stateSyncingKey); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("state sync should no longer be marked as in progress")
+	}
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height := lastAccepted.Height(); height != 0 {
+		t.Fatalf("expected the VM to be initialized from genesis but the last accepted height is %d", height)
+	}
+}
+
+// buildStateSummary returns a summary of the state in [vm.DB], which must be
+// the state after the accepted decision block [blk], along with its chunks
+func (vm *VM) buildStateSummary(blk *CommonBlock) (*stateSummary, [][]byte, error) {
+	chunkDB := memdb.New()
+	builder := &chunkBuilder{
+		db:            chunkDB,
+		chunkProgress: &chunkProgress{},
+	}
+	if _, err := vm.addUTXOsToChunks(vm.DB, builder, nil, -1); err != nil {
+		return nil, nil, err
+	}
+	summary, err := vm.finishStateSummary(vm.DB, blk, builder)
+	if err != nil {
+		return nil, nil, err
+	}
+	chunks := make([][]byte, summary.NumChunks())
+	for i := range chunks {
+		chunks[i], err = chunkDB.Get(stateChunkKey(uint32(i)))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return summary, chunks, nil
+}
+
+// newGenesisVM returns a VM initialized with the default genesis, whose state
+// is stored in [baseDB]
+func newGenesisVM(baseDB database.Database) (*VM, *snow.Context, error) {
+	return newGenesisVMWithStateHistory(baseDB, 0)
+}
+
+// newGenesisVMWithStateHistory returns a VM initialized with the default
+// genesis, whose state is stored in [baseDB], that keeps the state of the last
+// [stateHistoryDepth] accepted heights
+func newGenesisVMWithStateHistory(baseDB database.Database, stateHistoryDepth uint64) (*VM, *snow.Context, error) {
+	vm := &VM{
+		SnowmanVM:          &core.SnowmanVM{},
+		chainManager:       chains.MockManager{},
+		txFee:              defaultTxFee,
+		minValidatorStake:  defaultMinValidatorStake,
+		maxValidatorStake:  defaultMaxValidatorStake,
+		minDelegatorStake:  defaultMinDelegatorStake,
+		minStakeDuration:   defaultMinStakingDuration,
+		maxStakeDuration:   defaultMaxStakingDuration,
+		stakeMintingPeriod: defaultMaxStakingDuration,
+		stateHistoryDepth:  stateHistoryDepth,
+	}
+	vm.vdrMgr = validators.NewManager()
+	vm.clock.Set(defaultGenesisTime)
+	m := &atomic.Memory{}
+	if err := m.Initialize(logging.NoLog{}, prefixdb.New([]byte{1}, baseDB)); err != nil {
+		return nil, nil, err
+	}
+	ctx := defaultContext()
+	ctx.SharedMemory = m.NewSharedMemory(ctx.ChainID)
+	_, genesisBytes := defaultGenesis()
+	err := vm.Initialize(ctx, prefixdb.New([]byte{0}, baseDB), genesisBytes, make(chan common.Message, 1), nil)
+	return vm, ctx, err
+}
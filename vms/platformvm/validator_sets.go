@@ -0,0 +1,273 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// This file contains the methods of VM that keep the validator set of each
+// subnet as of each accepted height, so that other chains can agree on the
+// validators at a height of this chain.
+//
+// Only proposal txs change the validator sets, so the sets are stored when
+// the commit or abort block that decides a proposal block is accepted, and
+// only if they changed. A set is keyed by its subnet and by the inverse of its
+// height, so the first set at or after the key of a height is the set at that
+// height.
+//
+// The sets are kept from the height the node started keeping them at, which
+// is genesis, the height it synced its state at, or the height it was last
+// accepted at when the node was upgraded. The sets at earlier heights are
+// looked up as the sets at that height, so that blocks of other chains that
+// reference an earlier height can still be verified by this node.
+
+const validatorSetsDBPrefix = "validatorSets"
+
+var (
+	// Key of the first height that the validator sets are kept from
+	validatorSetsStartKey = []byte("start")
+
+	_ validators.State = &VM{}
+)
+
+// validatorSet is the serialized form of the validator set of a subnet
+type validatorSet struct {
+	Validators []validatorWeight `serialize:"true"`
+}
+
+// validatorWeight is the weight of a validator of a subnet
+type validatorWeight struct {
+	NodeID ids.ShortID `serialize:"true"`
+	Weight uint64      `serialize:"true"`
+}
+
+func (vm *VM) validatorSets() database.Database {
+	return prefixdb.NewNested([]byte(validatorSetsDBPrefix), vm.DB)
+}
+
+// validatorSetKey returns the key of the validator set of [subnetID] at
+// [height]. Keys of later heights sort first.
+func validatorSetKey(subnetID ids.ID, height uint64) []byte {
+	key := make([]byte, len(subnetID)+wrappers.LongLen)
+	copy(key, subnetID[:])
+	binary.BigEndian.PutUint64(key[len(subnetID):], math.MaxUint64-height)
+	return key
+}
+
+// GetCurrentHeight implements the validators.State interface. It's safe to
+// call without the chain's lock.
+func (vm *VM) GetCurrentHeight() (uint64, error) {
+	vm.acceptedHeightLock.RLock()
+	defer vm.acceptedHeightLock.RUnlock()
+
+	return vm.acceptedHeight, nil
+}
+
+// setAcceptedHeight marks the decision block at [height] as accepted and
+// committed, so the validator sets at [height] can be looked up
+func (vm *VM) setAcceptedHeight(height uint64) {
+	vm.acceptedHeightLock.Lock()
+	defer vm.acceptedHeightLock.Unlock()
+
+	vm.acceptedHeight = height
+}
+
+// GetValidatorSet implements the validators.State interface. It's safe to
+// call without the chain's lock, as the sets of the accepted heights are never
+// changed. The sets at heights before the sets were kept from are the sets at
+// the first height they were kept at.
+func (vm *VM) GetValidatorSet(height uint64, subnetID ids.ID) (map[ids.ShortID]uint64, error) {
+	currentHeight, err := vm.GetCurrentHeight()
+	if err != nil {
+		return nil, err
+	}
+	if height > currentHeight {
+		return nil, fmt.Errorf("%w: %d > %d", errHeightNotAccepted, height, currentHeight)
+	}
+
+	db := vm.validatorSets()
+	startBytes, err := db.Get(validatorSetsStartKey)
+	if err != nil {
+		return nil, err
+	}
+	if start := binary.BigEndian.Uint64(startBytes); height < start {
+		height = start
+	}
+
+	vdrs, err := vm.getValidatorSet(db, height, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[ids.ShortID]uint64, len(vdrs))
+	for _, vdr := range vdrs {
+		weights[vdr.NodeID] = vdr.Weight
+	}
+	return weights, nil
+}
+
+// getValidatorSet returns the validator set of [subnetID] at [height], as
+// stored in [db]. A subnet whose set was never stored has no validators.
+func (vm *VM) getValidatorSet(db database.Iteratee, height uint64, subnetID ids.ID) ([]validatorWeight, error) {
+	iter := db.NewIteratorWithStartAndPrefix(validatorSetKey(subnetID, height), subnetID[:])
+	defer iter.Release()
+
+	if !iter.Next() {
+		return nil, iter.Error()
+	}
+	set := validatorSet{}
+	if _, err := vm.codec.Unmarshal(iter.Value(), &set); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal validator set of subnet %s: %w", subnetID, err)
+	}
+	return set.Validators, nil
+}
+
+// initValidatorSets starts keeping the validator sets at the last accepted
+// height, unless they're already kept
+func (vm *VM) initValidatorSets() error {
+	lastAccepted, err := vm.getBlock(vm.LastAccepted())
+	if err != nil {
+		return err
+	}
+	height := lastAccepted.Height()
+
+	db := vm.validatorSets()
+	switch _, err := db.Get(validatorSetsStartKey); err {
+	case nil:
+	case database.ErrNotFound:
+		startBytes := make([]byte, wrappers.LongLen)
+		binary.BigEndian.PutUint64(startBytes, height)
+		errs := wrappers.Errs{}
+		errs.Add(
+			db.Put(validatorSetsStartKey, startBytes),
+			vm.putValidatorSets(height),
+			vm.DB.Commit(),
+		)
+		if errs.Errored() {
+			return errs.Err
+		}
+	default:
+		return err
+	}
+
+	vm.setAcceptedHeight(height)
+	return nil
+}
+
+// putValidatorSets stores, under [height], the validator set of each subnet in
+// [vm.DB] that changed since it was last stored
+func (vm *VM) putValidatorSets(height uint64) error {
+	subnets, err := vm.getSubnets(vm.DB)
+	if err != nil {
+		return err
+	}
+	subnetIDs := make([]ids.ID, 0, len(subnets)+1)
+	subnetIDs = append(subnetIDs, constants.PrimaryNetworkID)
+	for _, subnet := range subnets {
+		subnetIDs = append(subnetIDs, subnet.ID())
+	}
+
+	db := vm.validatorSets()
+	for _, subnetID := range subnetIDs {
+		vdrs, err := vm.currentValidators(vm.DB, subnetID)
+		if err != nil {
+			return err
+		}
+		stored, err := vm.getValidatorSet(db, height, subnetID)
+		if err != nil {
+			return err
+		}
+		if equalValidatorSets(vdrs, stored) {
+			continue
+		}
+
+		setBytes, err := vm.codec.Marshal(codecVersion, &validatorSet{Validators: vdrs})
+		if err != nil {
+			return err
+		}
+		if err := db.Put(validatorSetKey(subnetID, height), setBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentValidators returns the weight of each current validator of
+// [subnetID] in [db], in the order they first stop staking. The weight of a
+// validator of the primary network includes the stake delegated to it.
+func (vm *VM) currentValidators(db database.Database, subnetID ids.ID) ([]validatorWeight, error) {
+	stopPrefix := []byte(fmt.Sprintf("%s%s", subnetID, stopDBPrefix))
+	stopDB := prefixdb.NewNested(stopPrefix, db)
+	defer stopDB.Close()
+	stopIter := stopDB.NewIterator()
+	defer stopIter.Release()
+
+	vdrs := []validatorWeight(nil)
+	indices := make(map[ids.ShortID]int)
+	for stopIter.Next() { // Iterates in order of increasing stop time
+		txBytes := stopIter.Value()
+
+		tx := rewardTx{}
+		if _, err := vm.codec.Unmarshal(txBytes, &tx); err != nil {
+			return nil, fmt.Errorf("couldn't unmarshal validator tx: %w", err)
+		}
+		if err := tx.Tx.Sign(vm.codec, nil); err != nil {
+			return nil, err
+		}
+
+		var vdr *Validator
+		switch staker := tx.Tx.UnsignedTx.(type) {
+		case *UnsignedAddDelegatorTx:
+			vdr = &staker.Validator
+		case *UnsignedAddValidatorTx:
+			vdr = &staker.Validator
+		case *UnsignedAddSubnetValidatorTx:
+			vdr = &staker.Validator.Validator
+		default:
+			return nil, fmt.Errorf("expected validator but got %T", tx.Tx.UnsignedTx)
+		}
+
+		index, ok := indices[vdr.NodeID]
+		if !ok {
+			index = len(vdrs)
+			indices[vdr.NodeID] = index
+			vdrs = append(vdrs, validatorWeight{NodeID: vdr.NodeID})
+		}
+		weight, err := safemath.Add64(vdrs[index].Weight, vdr.Weight())
+		if err != nil {
+			return nil, err
+		}
+		vdrs[index].Weight = weight
+	}
+	return vdrs, stopIter.Error()
+}
+
+// equalValidatorSets returns true if [a] and [b] hold the same validators
+// with the same weights, in any order
+func equalValidatorSets(a, b []validatorWeight) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	weights := make(map[ids.ShortID]uint64, len(a))
+	for _, vdr := range a {
+		weights[vdr.NodeID] = vdr.Weight
+	}
+	for _, vdr := range b {
+		if weight, ok := weights[vdr.NodeID]; !ok || weight != vdr.Weight {
+			return false
+		}
+	}
+	return true
+}
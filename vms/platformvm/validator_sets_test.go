@@ -0,0 +1,107 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// acceptProposal builds a proposal block on the last accepted block and
+// accepts it with its commit
+func acceptProposal(t *testing.T, vm *VM) {
+	vm.SetPreference(vm.LastAccepted())
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	options, err := blk.(*ProposalBlock).Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, ok := options[0].(*Commit)
+	if !ok {
+		t.Fatal(errShouldPrefCommit)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := commit.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := commit.Accept(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Ensure that the validator sets can be looked up at each accepted height once
+// a validator is removed
+func TestGetValidatorSet(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Fatal(err)
+		}
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	// Advance the time to when the genesis validators leave, then reward one
+	// of them. The subnet created by defaultVM was accepted at height 1.
+	vm.clock.Set(defaultValidateEndTime)
+	acceptProposal(t, vm)
+	acceptProposal(t, vm)
+
+	height, err := vm.GetCurrentHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 5 {
+		t.Fatalf("expected the current height to be 5 but got %d", height)
+	}
+
+	genesisSet, err := vm.GetValidatorSet(0, constants.PrimaryNetworkID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(genesisSet) != len(keys) {
+		t.Fatalf("expected %d genesis validators but got %d", len(keys), len(genesisSet))
+	}
+	for _, key := range keys {
+		if weight := genesisSet[key.PublicKey().Address()]; weight != defaultWeight {
+			t.Fatalf("expected a weight of %d but got %d", defaultWeight, weight)
+		}
+	}
+
+	// The time was advanced without removing a validator, and the proposal to
+	// reward one isn't decided until its commit is accepted
+	for _, h := range []uint64{3, 4} {
+		set, err := vm.GetValidatorSet(h, constants.PrimaryNetworkID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(set) != len(keys) {
+			t.Fatalf("expected %d validators at height %d but got %d", len(keys), h, len(set))
+		}
+	}
+	if set, err := vm.GetValidatorSet(5, constants.PrimaryNetworkID); err != nil {
+		t.Fatal(err)
+	} else if len(set) != len(keys)-1 {
+		t.Fatalf("expected %d validators at height 5 but got %d", len(keys)-1, len(set))
+	}
+
+	if set, err := vm.GetValidatorSet(5, testSubnet1.ID()); err != nil {
+		t.Fatal(err)
+	} else if len(set) != 0 {
+		t.Fatalf("expected the subnet to have no validators but got %d", len(set))
+	}
+	if _, err := vm.GetValidatorSet(6, constants.PrimaryNetworkID); !errors.Is(err, errHeightNotAccepted) {
+		t.Fatalf("expected %s but got %v", errHeightNotAccepted, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"container/heap"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/avalanchego/cache"
@@ -15,9 +16,9 @@ import (
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
-	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
@@ -32,7 +33,9 @@ import (
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/core"
+	"github.com/ava-labs/avalanchego/vms/components/fees"
 	"github.com/ava-labs/avalanchego/vms/components/state"
+	"github.com/ava-labs/avalanchego/vms/components/statetree"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	safemath "github.com/ava-labs/avalanchego/utils/math"
@@ -122,11 +125,18 @@ type VM struct {
 	// Value: the block
 	currentBlocks map[ids.ID]Block
 
+	// The state of the chain if each processing block is accepted
+	states *statetree.Tree
+
 	// fee that must be burned by every state creating transaction
 	creationTxFee uint64
 	// fee that must be burned by every non-state creating transaction
 	txFee uint64
 
+	// Raises the fees that txs must burn when the chain is busy
+	feeConfig   fees.Config
+	feeRegistry *fees.Registry
+
 	// UptimePercentage is the minimum uptime required to be rewarded for staking.
 	uptimePercentage float64
 
@@ -154,6 +164,9 @@ type VM struct {
 	// Time of the apricot phase 0 rule change
 	apricotPhase0Time time.Time
 
+	// Time that txs in the mempool start being gossiped to other validators
+	mempoolGossipTime time.Time
+
 	// Contains the IDs of transactions recently dropped because they failed verification.
 	// These txs may be re-issued and put into accepted blocks, so check the database
 	// to see if it was later committed/aborted before reporting that it's dropped.
@@ -161,6 +174,36 @@ type VM struct {
 	// Value: String repr. of the verification error
 	droppedTxCache cache.LRU
 
+	// IDs of txs recently gossiped to, or rejected from, other validators
+	recentlyGossipedTxs cache.LRU
+
+	// Node ID --> number of txs it recently gossiped to us
+	peerGossipLimits map[ids.ShortID]*peerGossipLimit
+
+	// A state summary is built at the first decision block accepted at or
+	// after each multiple of this height
+	stateSummaryInterval uint64
+
+	// The most recently built or synced state summary. nil if there isn't one.
+	lastStateSummary *stateSummary
+
+	// The state summary being built in the background, if any, and the timer
+	// that builds the next slice of it
+	stateSummaryBuild *stateSummaryBuild
+	stateSummaryTimer *timer.Timer
+
+	// Number of accepted heights the state can be queried at, other than the
+	// last accepted height. If 0, the history of the state isn't kept.
+	stateHistoryDepth uint64
+
+	// Bytes of the genesis state the chain was created with
+	genesisBytes []byte
+
+	// Height of the last accepted and committed decision block. The validator
+	// sets are looked up at it by other chains, without this chain's lock.
+	acceptedHeightLock sync.RWMutex
+	acceptedHeight     uint64
+
 	// Bootstrapped remembers if this chain has finished bootstrapping or not
 	bootstrapped bool
 
@@ -194,12 +237,23 @@ func (vm *VM) Initialize(
 
 	vm.droppedTxCache = cache.LRU{Size: droppedTxCacheSize}
 	vm.connections = make(map[ids.ShortID]time.Time)
+	vm.genesisBytes = genesisBytes
+	vm.recentlyGossipedTxs = cache.LRU{Size: recentlyGossipedTxsSize}
+	vm.peerGossipLimits = make(map[ids.ShortID]*peerGossipLimit)
+
+	if vm.feeRegistry != nil {
+		vm.feeRegistry.Register(ctx.ChainID, vm)
+	}
 
 	// Register this VM's types with the database so we can get/put structs to/from it
 	vm.registerDBTypes()
 
 	vm.mempool.Initialize(vm)
 
+	if err := vm.discardInterruptedStateSync(); err != nil {
+		return fmt.Errorf("couldn't discard interrupted state sync: %w", err)
+	}
+
 	// If the database is empty, create the platform chain anew using
 	// the provided genesis state
 	if !vm.DBInitialized() {
@@ -291,11 +345,15 @@ func (vm *VM) Initialize(
 		if err := vm.State.PutBlock(vm.DB, genesisBlock); err != nil {
 			return err
 		}
-		genesisBlock.onAcceptDB = versiondb.New(vm.DB)
 		if err := genesisBlock.CommonBlock.Accept(); err != nil {
 			return fmt.Errorf("error accepting genesis block: %w", err)
 		}
 
+		// Every UTXO was added to the UTXO set index when it was persisted
+		if err := vm.State.PutStatus(vm.DB, utxoSetIndexedID, choices.Accepted); err != nil {
+			return err
+		}
+
 		if err := vm.SetDBInitialized(); err != nil {
 			return fmt.Errorf("error while setting db to initialized: %w", err)
 		}
@@ -305,7 +363,34 @@ func (vm *VM) Initialize(
 		}
 	}
 
+	if err := vm.indexUTXOSet(); err != nil {
+		return fmt.Errorf("couldn't index the UTXO set: %w", err)
+	}
+	if vm.stateSummaryInterval == 0 {
+		vm.stateSummaryInterval = defaultStateSummaryInterval
+	}
+	if err := vm.loadStateSummary(); err != nil {
+		return fmt.Errorf("couldn't load state summary: %w", err)
+	}
+	vm.stateSummaryTimer = timer.NewTimer(func() {
+		vm.Ctx.Lock.Lock()
+		defer vm.Ctx.Lock.Unlock()
+
+		vm.buildStateSummarySlice()
+	})
+	go ctx.Log.RecoverAndPanic(vm.stateSummaryTimer.Dispatch)
+
 	vm.currentBlocks = make(map[ids.ID]Block)
+	vm.states = statetree.New(vm.DB, vm.LastAcceptedID)
+	if err := vm.loadStateSummaryBuild(); err != nil {
+		return fmt.Errorf("couldn't load state summary build: %w", err)
+	}
+
+	// The chains of the subnets look up their validators at the heights of
+	// this chain once they're created
+	if err := vm.initValidatorSets(); err != nil {
+		return fmt.Errorf("couldn't initialize validator sets: %w", err)
+	}
 
 	if err := vm.initSubnets(); err != nil {
 		ctx.Log.Error("failed to initialize Subnets: %s", err)
@@ -335,6 +420,9 @@ func (vm *VM) Initialize(
 		return errInvalidLastAcceptedBlock
 	}
 
+	if err := vm.pruneStateHistory(); err != nil {
+		return fmt.Errorf("couldn't prune state history: %w", err)
+	}
 	return nil
 }
 
@@ -477,6 +565,12 @@ func (vm *VM) Shutdown() error {
 	}
 
 	vm.mempool.Shutdown()
+	if vm.stateSummaryTimer != nil {
+		// See the comment in mempool.Shutdown for why the lock is released
+		vm.Ctx.Lock.Unlock()
+		vm.stateSummaryTimer.Stop()
+		vm.Ctx.Lock.Lock()
+	}
 
 	stopPrefix := []byte(fmt.Sprintf("%s%s", constants.PrimaryNetworkID, stopDBPrefix))
 	stopDB := prefixdb.NewNested(stopPrefix, vm.DB)
@@ -632,6 +726,8 @@ func (vm *VM) Connected(vdrID ids.ShortID) {
 
 // Disconnected implements validators.Connector
 func (vm *VM) Disconnected(vdrID ids.ShortID) {
+	delete(vm.peerGossipLimits, vdrID)
+
 	timeConnected, ok := vm.connections[vdrID]
 	if !ok {
 		return
@@ -956,48 +1052,18 @@ func (vm *VM) updateVdrMgr(force bool) error {
 }
 
 func (vm *VM) updateVdrSet(subnetID ids.ID) error {
-	vdrs := validators.NewSet()
-
-	stopPrefix := []byte(fmt.Sprintf("%s%s", subnetID, stopDBPrefix))
-	stopDB := prefixdb.NewNested(stopPrefix, vm.DB)
-	defer stopDB.Close()
-	stopIter := stopDB.NewIterator()
-	defer stopIter.Release()
-
-	for stopIter.Next() { // Iterates in order of increasing stop time
-		txBytes := stopIter.Value()
-
-		tx := rewardTx{}
-		if _, err := vm.codec.Unmarshal(txBytes, &tx); err != nil {
-			return fmt.Errorf("couldn't unmarshal validator tx: %w", err)
-		}
-		if err := tx.Tx.Sign(vm.codec, nil); err != nil {
-			return err
-		}
+	weights, err := vm.currentValidators(vm.DB, subnetID)
+	if err != nil {
+		return err
+	}
 
-		var err error
-		switch staker := tx.Tx.UnsignedTx.(type) {
-		case *UnsignedAddDelegatorTx:
-			err = vdrs.AddWeight(staker.Validator.NodeID, staker.Validator.Weight())
-		case *UnsignedAddValidatorTx:
-			err = vdrs.AddWeight(staker.Validator.NodeID, staker.Validator.Weight())
-		case *UnsignedAddSubnetValidatorTx:
-			err = vdrs.AddWeight(staker.Validator.NodeID, staker.Validator.Weight())
-		default:
-			err = fmt.Errorf("expected validator but got %T", tx.Tx.UnsignedTx)
-		}
-		if err != nil {
+	vdrs := validators.NewSet()
+	for _, vdr := range weights {
+		if err := vdrs.AddWeight(vdr.NodeID, vdr.Weight); err != nil {
 			return err
 		}
 	}
-
-	errs := wrappers.Errs{}
-	errs.Add(
-		vm.vdrMgr.Set(subnetID, vdrs),
-		stopIter.Error(),
-		stopDB.Close(),
-	)
-	return errs.Err
+	return vm.vdrMgr.Set(subnetID, vdrs)
 }
 
 // Codec ...
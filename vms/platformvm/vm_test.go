@@ -2124,7 +2124,7 @@ func TestBootstrapPartiallyAccepted(t *testing.T) {
 	go timeoutManager.Dispatch()
 
 	chainRouter := &router.ChainRouter{}
-	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &timeoutManager, time.Hour, time.Second, ids.Set{}, nil)
+	chainRouter.Initialize(ids.ShortEmpty, logging.NoLog{}, &timeoutManager, time.Hour, time.Second, ids.Set{}, nil, nil)
 
 	externalSender := &sender.ExternalSenderTest{T: t}
 	externalSender.Default(true)
@@ -2355,7 +2355,7 @@ func TestFormatAddress(t *testing.T) {
 		t.Run(tt.label, func(t *testing.T) {
 			addrStr, err := vm.FormatLocalAddress(tt.in)
 			if err != nil {
-				t.Errorf("problem formatting address: %w", err)
+				t.Errorf("problem formatting address: %v", err)
 			}
 			if addrStr != tt.want {
 				t.Errorf("want %q, got %q", tt.want, addrStr)
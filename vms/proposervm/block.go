@@ -0,0 +1,419 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/vms/components/missing"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+// maxSkew is how far ahead of the local time a block's timestamp may be
+const maxSkew = 10 * time.Second
+
+// ErrWindowNotStarted is returned when a block is built, or was proposed,
+// before the window of its proposer started
+var ErrWindowNotStarted = errors.New("proposer's window hasn't started")
+
+var (
+	errUnsupportedStakingKey = errors.New("unsupported staking key type")
+	errInvalidSignature      = errors.New("invalid block signature")
+	errTimestampTooEarly     = errors.New("block's timestamp is earlier than its parent's windows")
+	errTimestampTooLate      = errors.New("block's timestamp is too far in the future")
+	errPChainHeightTooLow    = errors.New("block's P-chain height is lower than its parent's")
+	errPChainHeightTooHigh   = errors.New("block's P-chain height wasn't accepted yet")
+	errInnerParentMismatch   = errors.New("inner block's parent isn't wrapped by the block's parent")
+	errInnerDecided          = errors.New("inner block was already decided")
+	errOracleParent          = errors.New("the children of an oracle block must be its options")
+	errNotOracle             = errors.New("parent of the option isn't an oracle block")
+	errNotOption             = errors.New("inner block isn't an option of the parent")
+	errPreForkParent         = errors.New("the options of a block built before the fork must be built before the fork")
+	errPreForkAfterFork      = errors.New("block built before the fork can't follow a block built after it")
+
+	_ statelessBlock = &SignedBlock{}
+	_ statelessBlock = &OptionBlock{}
+	_ Block          = &preForkBlock{}
+	_ Block          = &postForkBlock{}
+	_ Block          = &postForkOption{}
+
+	_ smeng.OracleBlock = &oracleBlock{}
+)
+
+// statelessBlock is the serialized form of a block the wrapper added around
+// an inner block
+type statelessBlock interface {
+	parent() ids.ID
+	innerBytes() []byte
+}
+
+// SignedBlock is a block proposed by a validator in its window
+type SignedBlock struct {
+	ParentID ids.ID `serialize:"true"`
+	// Unix time, in seconds, the block was proposed at
+	Timestamp uint64 `serialize:"true"`
+	// Height of the P-chain whose validators may propose the block
+	PChainHeight uint64 `serialize:"true"`
+	// Staking certificate of the proposer, in DER form
+	Certificate []byte `serialize:"true"`
+	// Bytes of the inner block
+	Block []byte `serialize:"true"`
+	// Signature of the other fields with the proposer's staking key
+	Signature []byte `serialize:"true"`
+}
+
+func (b *SignedBlock) parent() ids.ID     { return b.ParentID }
+func (b *SignedBlock) innerBytes() []byte { return b.Block }
+
+// unsignedBytes returns the bytes that are signed
+func (b *SignedBlock) unsignedBytes() ([]byte, error) {
+	unsigned := *b
+	unsigned.Signature = nil
+	return Codec.Marshal(codecVersion, &unsigned)
+}
+
+// Bytes returns the bytes of the block, which the VM parses
+func (b *SignedBlock) Bytes() ([]byte, error) {
+	var stateless statelessBlock = b
+	return Codec.Marshal(codecVersion, &stateless)
+}
+
+// Sign sets the signature of the block with [signer], the staking key of its
+// proposer
+func (b *SignedBlock) Sign(signer crypto.Signer) error {
+	unsignedBytes, err := b.unsignedBytes()
+	if err != nil {
+		return err
+	}
+	b.Signature, err = signer.Sign(rand.Reader, hashing.ComputeHash256(unsignedBytes), crypto.SHA256)
+	return err
+}
+
+// proposer returns the ID of the node that proposed the block. Returns an
+// error if the signature isn't valid.
+func (b *SignedBlock) proposer() (ids.ShortID, error) {
+	cert, err := x509.ParseCertificate(b.Certificate)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	var algorithm x509.SignatureAlgorithm
+	switch cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		algorithm = x509.ECDSAWithSHA256
+	default:
+		return ids.ShortID{}, fmt.Errorf("%w: %T", errUnsupportedStakingKey, cert.PublicKey)
+	}
+	unsignedBytes, err := b.unsignedBytes()
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	if err := cert.CheckSignature(algorithm, unsignedBytes, b.Signature); err != nil {
+		return ids.ShortID{}, fmt.Errorf("%w: %s", errInvalidSignature, err)
+	}
+	return certToID(cert.Raw), nil
+}
+
+// OptionBlock is an option of an oracle block. Every node builds the options
+// of an oracle block itself, so they aren't signed.
+type OptionBlock struct {
+	ParentID ids.ID `serialize:"true"`
+	// Bytes of the inner block
+	Block []byte `serialize:"true"`
+}
+
+func (b *OptionBlock) parent() ids.ID     { return b.ParentID }
+func (b *OptionBlock) innerBytes() []byte { return b.Block }
+
+// certToID returns the ID of the node with the staking certificate [cert]
+func certToID(cert []byte) ids.ShortID {
+	return ids.ShortID(
+		hashing.ComputeHash160Array(
+			hashing.ComputeHash256(cert)))
+}
+
+// Block is a block of the wrapper, which wraps a block of the inner VM
+type Block interface {
+	snowman.Block
+
+	// getInner returns the inner block this block wraps
+	getInner() snowman.Block
+
+	// windowsStart returns the time the windows to propose a child of this
+	// block start at
+	windowsStart() (time.Time, error)
+
+	// option returns the block that wraps [inner], an option of the inner
+	// block this block wraps
+	option(inner snowman.Block) (Block, error)
+
+	// pChainHeight returns the height of the P-chain this block was proposed
+	// at. Its children can't be proposed at a lower height.
+	pChainHeight() (uint64, error)
+}
+
+// oracleBlock is a block that wraps an oracle block. Its options wrap the
+// options of the inner block.
+type oracleBlock struct{ Block }
+
+// oracle returns [blk], as an oracle block if the inner block is one
+func oracle(blk Block) snowman.Block {
+	if _, ok := blk.getInner().(smeng.OracleBlock); ok {
+		return &oracleBlock{Block: blk}
+	}
+	return blk
+}
+
+func (b *oracleBlock) Options() ([2]snowman.Block, error) {
+	innerOptions, err := b.getInner().(smeng.OracleBlock).Options()
+	if err != nil {
+		return [2]snowman.Block{}, err
+	}
+	options := [2]snowman.Block{}
+	for i, innerOption := range innerOptions {
+		option, err := b.option(innerOption)
+		if err != nil {
+			return [2]snowman.Block{}, err
+		}
+		options[i] = oracle(option)
+	}
+	return options, nil
+}
+
+// preForkBlock is an inner block built before the fork. It has the same ID
+// and bytes as the inner block.
+type preForkBlock struct {
+	snowman.Block
+	vm *VM
+}
+
+func (b *preForkBlock) getInner() snowman.Block { return b.Block }
+
+// The windows of the first block built after the fork start at the fork
+func (b *preForkBlock) windowsStart() (time.Time, error) { return b.vm.activationTime, nil }
+
+func (b *preForkBlock) option(inner snowman.Block) (Block, error) { return b.vm.preFork(inner), nil }
+
+// Blocks built before the fork don't record a P-chain height
+func (b *preForkBlock) pChainHeight() (uint64, error) { return 0, nil }
+
+func (b *preForkBlock) Parent() snowman.Block { return oracle(b.vm.preFork(b.Block.Parent())) }
+
+// Verify returns nil iff the inner block is valid and its parent was also
+// built before the fork
+func (b *preForkBlock) Verify() error {
+	if b.vm.lastAccepted != ids.Empty {
+		return errPreForkAfterFork
+	}
+	// A processing parent must be verified as a block built before the fork,
+	// rather than as the inner block of a block built after it
+	if parent := b.Block.Parent(); parent.Status() != choices.Accepted {
+		if _, ok := b.vm.verified[parent.ID()]; !ok {
+			return errPreForkAfterFork
+		}
+	}
+	inner, err := b.vm.verifyInner(b)
+	if err != nil {
+		return err
+	}
+	b.Block = inner
+	return nil
+}
+
+func (b *preForkBlock) Accept() error { return b.vm.acceptInner(b.ID(), b.Block) }
+func (b *preForkBlock) Reject() error { return b.vm.rejectInner(b.ID(), b.Block) }
+
+// postForkCommon is the state shared by the blocks the wrapper added around
+// inner blocks
+type postForkCommon struct {
+	vm       *VM
+	id       ids.ID
+	parentID ids.ID
+	bytes    []byte
+	inner    snowman.Block
+	status   choices.Status
+}
+
+func (b *postForkCommon) ID() ids.ID              { return b.id }
+func (b *postForkCommon) Bytes() []byte           { return b.bytes }
+func (b *postForkCommon) Height() uint64          { return b.inner.Height() }
+func (b *postForkCommon) Status() choices.Status  { return b.status }
+func (b *postForkCommon) getInner() snowman.Block { return b.inner }
+
+func (b *postForkCommon) option(inner snowman.Block) (Block, error) {
+	return b.vm.newOption(b.id, inner)
+}
+
+func (b *postForkCommon) Parent() snowman.Block {
+	parent, err := b.vm.getBlock(b.parentID)
+	if err != nil {
+		return &missing.Block{BlkID: b.parentID}
+	}
+	return oracle(parent)
+}
+
+func (b *postForkCommon) Accept() error {
+	b.status = choices.Accepted
+	if err := b.vm.acceptPostFork(b.id, b.bytes, b.Height()); err != nil {
+		return err
+	}
+	return b.vm.acceptInner(b.id, b.inner)
+}
+
+func (b *postForkCommon) Reject() error {
+	b.status = choices.Rejected
+	return b.vm.rejectInner(b.id, b.inner)
+}
+
+// postForkBlock is a block proposed after the fork
+type postForkBlock struct {
+	postForkCommon
+	stateless *SignedBlock
+}
+
+func (b *postForkBlock) timestamp() time.Time {
+	return time.Unix(int64(b.stateless.Timestamp), 0)
+}
+
+func (b *postForkBlock) windowsStart() (time.Time, error) { return b.timestamp(), nil }
+
+func (b *postForkBlock) pChainHeight() (uint64, error) { return b.stateless.PChainHeight, nil }
+
+// Verify returns nil iff:
+//   - the block was proposed in the proposer's window, no later than [maxSkew]
+//     ahead of the local time
+//   - the block's P-chain height was accepted, and isn't lower than its
+//     parent's
+//   - the proposer signed the block
+//   - the inner block is a valid child of the parent's inner block
+func (b *postForkBlock) Verify() error {
+	parent, err := b.vm.getBlock(b.parentID)
+	if err != nil {
+		return err
+	}
+	if _, ok := parent.getInner().(smeng.OracleBlock); ok {
+		return errOracleParent
+	}
+
+	windowsStart, err := parent.windowsStart()
+	if err != nil {
+		return err
+	}
+	timestamp := b.timestamp()
+	if timestamp.Before(windowsStart) {
+		return errTimestampTooEarly
+	}
+	if maxTimestamp := b.vm.clock.Time().Add(maxSkew); timestamp.After(maxTimestamp) {
+		return errTimestampTooLate
+	}
+
+	parentPChainHeight, err := parent.pChainHeight()
+	if err != nil {
+		return err
+	}
+	pChainHeight := b.stateless.PChainHeight
+	if pChainHeight < parentPChainHeight {
+		return fmt.Errorf("%w: %d < %d", errPChainHeightTooLow, pChainHeight, parentPChainHeight)
+	}
+
+	proposer, err := b.stateless.proposer()
+	if err != nil {
+		return err
+	}
+	// The P-chain may not have accepted the height of a block that's accepted
+	// while bootstrapping yet, so its window can't be checked
+	if b.vm.ctx.IsBootstrapped() {
+		currentPChainHeight, err := b.vm.state.GetCurrentHeight()
+		if err != nil {
+			return err
+		}
+		if pChainHeight > currentPChainHeight {
+			return fmt.Errorf("%w: %d > %d", errPChainHeightTooHigh, pChainHeight, currentPChainHeight)
+		}
+		delay, err := b.vm.delay(b.Height(), pChainHeight, proposer)
+		if err != nil {
+			return err
+		}
+		windowStart := windowsStart.Add(delay)
+		if timestamp.Before(windowStart) {
+			return fmt.Errorf("%w: %s may propose from %s", ErrWindowNotStarted, proposer, windowStart)
+		}
+	}
+
+	if b.inner.Parent().ID() != parent.getInner().ID() {
+		return errInnerParentMismatch
+	}
+	inner, err := b.vm.verifyInner(b)
+	if err != nil {
+		return err
+	}
+	b.inner = inner
+	return nil
+}
+
+// postForkOption is an option of an oracle block proposed after the fork
+type postForkOption struct {
+	postForkCommon
+}
+
+// The options of a block are built at the same time as the block
+func (b *postForkOption) windowsStart() (time.Time, error) {
+	parent, err := b.vm.getBlock(b.parentID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parent.windowsStart()
+}
+
+// The options of a block are proposed at the same P-chain height as the block
+func (b *postForkOption) pChainHeight() (uint64, error) {
+	parent, err := b.vm.getBlock(b.parentID)
+	if err != nil {
+		return 0, err
+	}
+	return parent.pChainHeight()
+}
+
+// Verify returns nil iff the inner block is a valid option of the parent's
+// inner block
+func (b *postForkOption) Verify() error {
+	parent, err := b.vm.getBlock(b.parentID)
+	if err != nil {
+		return err
+	}
+	if _, ok := parent.(*preForkBlock); ok {
+		return errPreForkParent
+	}
+	innerParent, ok := parent.getInner().(smeng.OracleBlock)
+	if !ok {
+		return errNotOracle
+	}
+	innerOptions, err := innerParent.Options()
+	if err != nil {
+		return err
+	}
+	innerID := b.inner.ID()
+	if innerOptions[0].ID() != innerID && innerOptions[1].ID() != innerID {
+		return errNotOption
+	}
+	inner, err := b.vm.verifyInner(b)
+	if err != nil {
+		return err
+	}
+	b.inner = inner
+	return nil
+}
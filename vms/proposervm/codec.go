@@ -0,0 +1,36 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"math"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/codec/reflectcodec"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	codecVersion = 0
+)
+
+// Codec serializes the blocks the wrapper adds around the inner VM's blocks.
+// The size of a block is only limited by the size of the inner VM's blocks.
+var Codec codec.Manager
+
+func init() {
+	c := linearcodec.New(reflectcodec.DefaultTagName, math.MaxUint32)
+	Codec = codec.NewManager(math.MaxUint32)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&SignedBlock{}),
+		c.RegisterType(&OptionBlock{}),
+		Codec.RegisterCodec(codecVersion, c),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
@@ -0,0 +1,87 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+// scheduler holds back the inner VM's requests to build a block until this
+// node's window to propose the next block starts. Otherwise, the engine would
+// ask the inner VM to build a block that can't be proposed yet, and the inner
+// VM would consume its pending txs.
+type scheduler struct {
+	// messages from the inner VM
+	fromVM <-chan common.Message
+	// messages to the engine
+	toEngine chan<- common.Message
+	// receives the time this node's next window starts. Closed on shutdown.
+	buildTimes chan time.Time
+}
+
+func newScheduler(fromVM <-chan common.Message, toEngine chan<- common.Message) *scheduler {
+	return &scheduler{
+		fromVM:     fromVM,
+		toEngine:   toEngine,
+		buildTimes: make(chan time.Time, 1),
+	}
+}
+
+// setBuildTime sets the time this node's next window starts
+func (s *scheduler) setBuildTime(buildTime time.Time) {
+	// Only the latest build time matters
+	select {
+	case <-s.buildTimes:
+	default:
+	}
+	s.buildTimes <- buildTime
+}
+
+// dispatch forwards the inner VM's messages to the engine once this node's
+// window starts, until the scheduler is shut down
+func (s *scheduler) dispatch(buildTime time.Time) {
+	timer := time.NewTimer(time.Until(buildTime))
+	defer timer.Stop()
+
+	for {
+		// Wait for the window to start
+		select {
+		case <-timer.C:
+		case buildTime, ok := <-s.buildTimes:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			if !ok {
+				return
+			}
+			timer.Reset(time.Until(buildTime))
+			continue
+		}
+
+		// The window started, so the inner VM's requests go through until the
+		// next window is set
+	forward:
+		for {
+			select {
+			case msg := <-s.fromVM:
+				select {
+				case s.toEngine <- msg:
+				default:
+					// The engine already has a request to build a block
+				}
+			case buildTime, ok := <-s.buildTimes:
+				if !ok {
+					return
+				}
+				timer.Reset(time.Until(buildTime))
+				break forward
+			}
+		}
+	}
+}
+
+// shutdown stops dispatching messages
+func (s *scheduler) shutdown() { close(s.buildTimes) }
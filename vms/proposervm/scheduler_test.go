@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+func TestSchedulerHoldsMessagesUntilWindow(t *testing.T) {
+	fromVM := make(chan common.Message, 1)
+	toEngine := make(chan common.Message, 1)
+	s := newScheduler(fromVM, toEngine)
+	defer s.shutdown()
+
+	buildTime := time.Now().Add(200 * time.Millisecond)
+	go s.dispatch(buildTime)
+
+	fromVM <- common.PendingTxs
+	select {
+	case <-toEngine:
+		t.Fatal("forwarded the message before the window started")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	select {
+	case msg := <-toEngine:
+		if msg != common.PendingTxs {
+			t.Fatalf("expected %s but got %s", common.PendingTxs, msg)
+		}
+		if time.Now().Before(buildTime) {
+			t.Fatal("forwarded the message before the window started")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("didn't forward the message once the window started")
+	}
+}
+
+func TestSchedulerNewBuildTime(t *testing.T) {
+	fromVM := make(chan common.Message, 1)
+	toEngine := make(chan common.Message, 1)
+	s := newScheduler(fromVM, toEngine)
+	defer s.shutdown()
+
+	// The window is far away, until the preference changes
+	go s.dispatch(time.Now().Add(time.Hour))
+	fromVM <- common.PendingTxs
+	s.setBuildTime(time.Now())
+
+	select {
+	case <-toEngine:
+	case <-time.After(5 * time.Second):
+		t.Fatal("didn't forward the message once the window started")
+	}
+}
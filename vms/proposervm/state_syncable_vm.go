@@ -0,0 +1,192 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var (
+	errUnknownStateSummary = errors.New("unknown state summary")
+	errSummaryHeight       = errors.New("state summary's block isn't at the summary's height")
+
+	_ block.StateSyncableVM = &StateSyncableVM{}
+	_ block.StateSummary    = &stateSummary{}
+)
+
+// StateSyncableVM is a VM that wraps a block.StateSyncableVM. Its summaries
+// wrap the inner VM's summaries, along with the block of the wrapper that
+// wraps the inner block each summary was taken at.
+type StateSyncableVM struct {
+	*VM
+}
+
+// stateSummary is a summary of the inner VM's state at the height of [Block]
+type stateSummary struct {
+	// Bytes of the inner VM's summary
+	Summary []byte `serialize:"true"`
+	// Bytes of the accepted block of the wrapper at the summary's height. Empty
+	// if the inner block at that height was built before the fork.
+	Block []byte `serialize:"true"`
+
+	inner block.StateSummary
+	id    ids.ID
+	bytes []byte
+}
+
+// ID implements the block.StateSummary interface
+func (s *stateSummary) ID() ids.ID { return s.id }
+
+// Height implements the block.StateSummary interface
+func (s *stateSummary) Height() uint64 { return s.inner.Height() }
+
+// Bytes implements the block.StateSummary interface
+func (s *stateSummary) Bytes() []byte { return s.bytes }
+
+// NumChunks implements the block.StateSummary interface
+func (s *stateSummary) NumChunks() uint32 { return s.inner.NumChunks() }
+
+// VerifyChunk implements the block.StateSummary interface
+func (s *stateSummary) VerifyChunk(index uint32, chunk []byte) error {
+	return s.inner.VerifyChunk(index, chunk)
+}
+
+func (vm *StateSyncableVM) innerVM() block.StateSyncableVM {
+	return vm.ChainVM.(block.StateSyncableVM)
+}
+
+// StateSummary implements the block.StateSyncableVM interface
+func (vm *StateSyncableVM) StateSummary() (block.StateSummary, error) {
+	inner, err := vm.innerVM().StateSummary()
+	if err != nil {
+		return nil, err
+	}
+	height := inner.Height()
+
+	var blkBytes []byte
+	blkIDBytes, err := vm.db.Get(heightKey(height))
+	switch err {
+	case nil:
+		blkID, err := ids.ToID(blkIDBytes)
+		if err != nil {
+			return nil, err
+		}
+		blkBytes, err = vm.db.Get(blkID[:])
+		if err != nil {
+			return nil, err
+		}
+	case database.ErrNotFound:
+		// The inner block at [height] was built before the fork, unless this
+		// node doesn't know the block of the wrapper at [height]
+		forkHeightBytes, err := vm.db.Get(forkHeightKey)
+		switch err {
+		case nil:
+			if forkHeight := binary.BigEndian.Uint64(forkHeightBytes); height >= forkHeight {
+				return nil, block.ErrNoStateSummary
+			}
+		case database.ErrNotFound:
+		default:
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	summary := &stateSummary{
+		Summary: inner.Bytes(),
+		Block:   blkBytes,
+		inner:   inner,
+	}
+	summary.bytes, err = Codec.Marshal(codecVersion, summary)
+	if err != nil {
+		return nil, err
+	}
+	summary.id = hashing.ComputeHash256Array(summary.bytes)
+	return summary, nil
+}
+
+// ParseStateSummary implements the block.StateSyncableVM interface
+func (vm *StateSyncableVM) ParseStateSummary(summaryBytes []byte) (block.StateSummary, error) {
+	summary := &stateSummary{}
+	if _, err := Codec.Unmarshal(summaryBytes, summary); err != nil {
+		return nil, fmt.Errorf("couldn't parse state summary: %w", err)
+	}
+	inner, err := vm.innerVM().ParseStateSummary(summary.Summary)
+	if err != nil {
+		return nil, err
+	}
+	if len(summary.Block) > 0 {
+		var stateless statelessBlock
+		if _, err := Codec.Unmarshal(summary.Block, &stateless); err != nil {
+			return nil, fmt.Errorf("couldn't parse state summary's block: %w", err)
+		}
+		innerBlk, err := vm.ChainVM.ParseBlock(stateless.innerBytes())
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse state summary's inner block: %w", err)
+		}
+		if innerBlk.Height() != inner.Height() {
+			return nil, fmt.Errorf("%w: %d != %d", errSummaryHeight, innerBlk.Height(), inner.Height())
+		}
+	}
+	summary.inner = inner
+	summary.id = hashing.ComputeHash256Array(summaryBytes)
+	summary.bytes = summaryBytes
+	return summary, nil
+}
+
+// StateChunk implements the block.StateSyncableVM interface
+func (vm *StateSyncableVM) StateChunk(summaryID ids.ID, index uint32) ([]byte, error) {
+	summary, err := vm.StateSummary()
+	if err != nil {
+		return nil, err
+	}
+	if summary.ID() != summaryID {
+		return nil, errUnknownStateSummary
+	}
+	return vm.innerVM().StateChunk(summary.(*stateSummary).inner.ID(), index)
+}
+
+// SyncState implements the block.StateSyncableVM interface. Once the inner VM
+// synced its state, the block of the wrapper at the summary's height, if any,
+// is the last accepted block.
+func (vm *StateSyncableVM) SyncState(summaryIntf block.StateSummary, chunks [][]byte) error {
+	summary, ok := summaryIntf.(*stateSummary)
+	if !ok {
+		return fmt.Errorf("%w: %T", errUnknownStateSummary, summaryIntf)
+	}
+	if err := vm.innerVM().SyncState(summary.inner, chunks); err != nil {
+		return err
+	}
+	if len(summary.Block) > 0 {
+		blk, err := vm.parsePostFork(summary.Block)
+		if err != nil {
+			return err
+		}
+		if err := vm.acceptPostFork(blk.ID(), blk.Bytes(), blk.Height()); err != nil {
+			return err
+		}
+	}
+	vm.SetPreference(vm.LastAccepted())
+	return nil
+}
+
+// heightKey returns the key of the ID of the block accepted at [height]
+func heightKey(height uint64) []byte {
+	return append(append([]byte{}, heightPrefix...), packHeight(height)...)
+}
+
+// packHeight returns the bytes of [height]
+func packHeight(height uint64) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+	p.PackLong(height)
+	return p.Bytes
+}
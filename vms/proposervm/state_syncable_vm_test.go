@@ -0,0 +1,220 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var errInvalidChunk = errors.New("invalid chunk")
+
+type testStateSummary struct {
+	id     ids.ID
+	height uint64
+	bytes  []byte
+	chunks [][]byte
+}
+
+func (s *testStateSummary) ID() ids.ID        { return s.id }
+func (s *testStateSummary) Height() uint64    { return s.height }
+func (s *testStateSummary) Bytes() []byte     { return s.bytes }
+func (s *testStateSummary) NumChunks() uint32 { return uint32(len(s.chunks)) }
+func (s *testStateSummary) VerifyChunk(index uint32, chunk []byte) error {
+	if index >= s.NumChunks() || !bytes.Equal(chunk, s.chunks[index]) {
+		return errInvalidChunk
+	}
+	return nil
+}
+
+func newTestStateSummary(height uint64) *testStateSummary {
+	summaryID := ids.GenerateTestID()
+	return &testStateSummary{
+		id:     summaryID,
+		height: height,
+		bytes:  summaryID[:],
+		chunks: [][]byte{{1}, {2}},
+	}
+}
+
+// newTestStateSyncableVM returns an inner VM with the blocks [blks] and the
+// state summary [summary]
+func newTestStateSyncableVM(t *testing.T, summary *testStateSummary, blks ...snowman.Block) *block.TestStateSyncableVM {
+	vm := &block.TestStateSyncableVM{TestVM: *newTestInnerVM(t, blks...)}
+	vm.StateSummaryF = func() (block.StateSummary, error) { return summary, nil }
+	vm.ParseStateSummaryF = func(b []byte) (block.StateSummary, error) {
+		if !bytes.Equal(b, summary.Bytes()) {
+			return nil, errUnknownStateSummary
+		}
+		return summary, nil
+	}
+	vm.StateChunkF = func(summaryID ids.ID, index uint32) ([]byte, error) {
+		if summaryID != summary.ID() {
+			return nil, errUnknownStateSummary
+		}
+		return summary.chunks[index], nil
+	}
+	return vm
+}
+
+func TestStateSyncableVM(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	summary := newTestStateSummary(child.Height())
+	inner := newTestStateSyncableVM(t, summary, genesis, child)
+	inner.BuildBlockF = func() (snowman.Block, error) { return child, nil }
+
+	cert := newTestStakingCert(t)
+	state := newTestState(t, cert)
+	activationTime := testTime.Add(-time.Minute)
+	vm := &StateSyncableVM{VM: newTestVM(t, inner, activationTime, cert, state, memdb.New())}
+	vm.SetPreference(genesis.ID())
+
+	// Before the fork, the summaries don't have a block of the wrapper
+	preForkSummary, err := vm.StateSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preForkBlock := preForkSummary.(*stateSummary).Block; len(preForkBlock) != 0 {
+		t.Fatal("the summary of a block built before the fork has a block of the wrapper")
+	}
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The summary at the height of the block includes the block
+	postForkSummary, err := vm.StateSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if postForkSummary.Height() != child.Height() {
+		t.Fatalf("expected the height %d but got %d", child.Height(), postForkSummary.Height())
+	}
+	if !bytes.Equal(postForkSummary.(*stateSummary).Block, blk.Bytes()) {
+		t.Fatal("the summary doesn't include the block of the wrapper at its height")
+	}
+	if postForkSummary.ID() == preForkSummary.ID() {
+		t.Fatal("the summaries with and without the block have the same ID")
+	}
+	for i, expected := range summary.chunks {
+		chunk, err := vm.StateChunk(postForkSummary.ID(), uint32(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(chunk, expected) {
+			t.Fatalf("expected chunk %d to be %v but got %v", i, expected, chunk)
+		}
+	}
+	if _, err := vm.StateChunk(summary.ID(), 0); err != errUnknownStateSummary {
+		t.Fatalf("expected %s but got %v", errUnknownStateSummary, err)
+	}
+
+	// Another node syncs the summary, after which the block is its last
+	// accepted block
+	syncedInner := newTestStateSyncableVM(t, summary, genesis, child)
+	syncedChunks := [][]byte(nil)
+	syncedInner.SyncStateF = func(innerSummary block.StateSummary, chunks [][]byte) error {
+		if innerSummary.ID() != summary.ID() {
+			t.Fatalf("expected the inner summary %s but got %s", summary.ID(), innerSummary.ID())
+		}
+		syncedChunks = chunks
+		return nil
+	}
+	synced := &StateSyncableVM{VM: newTestVM(t, syncedInner, activationTime, cert, state, memdb.New())}
+	parsed, err := synced.ParseStateSummary(postForkSummary.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ID() != postForkSummary.ID() {
+		t.Fatalf("expected the summary %s but got %s", postForkSummary.ID(), parsed.ID())
+	}
+	if err := parsed.VerifyChunk(0, summary.chunks[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := synced.SyncState(parsed, summary.chunks); err != nil {
+		t.Fatal(err)
+	}
+	if len(syncedChunks) != len(summary.chunks) {
+		t.Fatalf("expected the inner VM to sync %d chunks but it synced %d", len(summary.chunks), len(syncedChunks))
+	}
+	if lastAccepted := synced.LastAccepted(); lastAccepted != blk.ID() {
+		t.Fatalf("expected the last accepted block %s but got %s", blk.ID(), lastAccepted)
+	}
+	stored, err := synced.GetBlock(blk.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := stored.Status(); status != choices.Accepted {
+		t.Fatalf("expected the block to be %s but it's %s", choices.Accepted, status)
+	}
+
+	// The synced node serves the same summary
+	served, err := synced.StateSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if served.ID() != postForkSummary.ID() {
+		t.Fatalf("expected the summary %s but got %s", postForkSummary.ID(), served.ID())
+	}
+}
+
+func TestStateSyncableVMUnknownBlock(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	grandchild := newTestBlock(child)
+	summary := newTestStateSummary(child.Height())
+	inner := newTestStateSyncableVM(t, summary, genesis, child, grandchild)
+	inner.BuildBlockF = func() (snowman.Block, error) { return child, nil }
+
+	cert := newTestStakingCert(t)
+	vm := &StateSyncableVM{VM: newTestVM(t, inner, testTime.Add(-time.Minute), cert, newTestState(t, cert), memdb.New())}
+	vm.SetPreference(genesis.ID())
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A summary can't include a block of the wrapper at another height
+	invalid := &stateSummary{
+		Summary: summary.Bytes(),
+		Block:   blk.Bytes(),
+	}
+	summary.height = grandchild.Height()
+	invalidBytes, err := Codec.Marshal(codecVersion, invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.ParseStateSummary(invalidBytes); !errors.Is(err, errSummaryHeight) {
+		t.Fatalf("expected %s but got %v", errSummaryHeight, err)
+	}
+
+	// After the fork, there's no summary at a height without an accepted
+	// block of the wrapper
+	if _, err := vm.StateSummary(); err != block.ErrNoStateSummary {
+		t.Fatalf("expected %s but got %v", block.ErrNoStateSummary, err)
+	}
+}
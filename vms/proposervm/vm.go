@@ -0,0 +1,599 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package proposervm wraps a Snowman VM so that, after a fork, only a few
+// validators may propose the block at each height at first.
+//
+// The proposers of each height are sampled from the validators of the chain's
+// subnet, weighted by stake. Each block records a height of the P-chain, and
+// its proposers are sampled from the validators once the P-chain block at that
+// height was accepted, so every node agrees on them. Each proposer gets a window of WindowDuration after the
+// windows of the proposers before it, starting at the timestamp of the parent
+// block. Once MaxDelay passed, any node may propose the block. Each block is
+// signed with the staking key of its proposer.
+//
+// Blocks built before the fork pass through unchanged, so a chain can be
+// wrapped before the fork is scheduled.
+package proposervm
+
+import (
+	"crypto"
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/pubsub"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/timer"
+)
+
+var (
+	errNoStakingKey = errors.New("can't propose blocks without a staking key")
+
+	lastAcceptedKey = []byte("lastAccepted")
+	// Height of the first block accepted after the fork. The accepted blocks
+	// are indexed by height from it.
+	forkHeightKey = []byte("forkHeight")
+	heightPrefix  = []byte("height")
+
+	_ block.ChainVM        = &VM{}
+	_ common.AppHandler    = &VM{}
+	_ validators.Connector = &VM{}
+	_ pubsub.TxParser      = &VM{}
+)
+
+// VM wraps a Snowman VM and only lets the proposers of each height build a
+// block in their windows. Blocks built before [activationTime] are the inner
+// VM's blocks, unchanged.
+type VM struct {
+	block.ChainVM
+
+	activationTime time.Time
+	// Signs the blocks this node proposes. nil if this node can't propose
+	// blocks.
+	signer crypto.Signer
+	// Staking certificate of this node, in DER form
+	cert []byte
+	// Stores the blocks accepted after the fork
+	db database.Database
+	windower
+	clock timer.Clock
+
+	ctx       *snow.Context
+	scheduler *scheduler
+	// The inner VM's messages to the engine, which the scheduler forwards
+	fromVM chan common.Message
+
+	// Blocks that were verified and not decided yet
+	verified map[ids.ID]Block
+	// Inner blocks wrapped by verified blocks
+	innerBlocks map[ids.ID]*innerBlock
+	preferred   ids.ID
+	// ID of the last accepted block after the fork, or ids.Empty before the
+	// fork
+	lastAccepted ids.ID
+}
+
+// New returns a VM that wraps [vm] once [activationTime] passes. The proposers
+// are sampled from the validator sets in [state]. This node signs the blocks it proposes with
+// [stakingCert], which may be nil if this node doesn't propose blocks. The
+// wrapper stores its blocks in [db], which must be separate from the inner
+// VM's database.
+func New(
+	vm block.ChainVM,
+	activationTime time.Time,
+	stakingCert *tls.Certificate,
+	state validators.State,
+	db database.Database,
+) *VM {
+	wrapper := &VM{
+		ChainVM:        vm,
+		activationTime: activationTime,
+		db:             db,
+		windower:       windower{state: state},
+		verified:       make(map[ids.ID]Block),
+		innerBlocks:    make(map[ids.ID]*innerBlock),
+	}
+	if stakingCert != nil {
+		if signer, ok := stakingCert.PrivateKey.(crypto.Signer); ok {
+			wrapper.signer = signer
+			wrapper.cert = stakingCert.Certificate[0]
+		}
+	}
+	return wrapper
+}
+
+// Initialize implements the common.VM interface. The inner VM is initialized
+// with [db] and [genesisBytes].
+func (vm *VM) Initialize(
+	ctx *snow.Context,
+	db database.Database,
+	genesisBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+) error {
+	vm.ctx = ctx
+	vm.windower.subnetID = ctx.SubnetID
+	vm.windower.chainID = ctx.ChainID
+	vm.fromVM = make(chan common.Message, 1)
+	vm.scheduler = newScheduler(vm.fromVM, toEngine)
+
+	if err := vm.ChainVM.Initialize(ctx, db, genesisBytes, vm.fromVM, fxs); err != nil {
+		return err
+	}
+
+	lastAcceptedBytes, err := vm.db.Get(lastAcceptedKey)
+	switch err {
+	case nil:
+		vm.lastAccepted, err = ids.ToID(lastAcceptedBytes)
+		if err != nil {
+			return err
+		}
+		lastAccepted, err := vm.getBlock(vm.lastAccepted)
+		if err != nil {
+			return err
+		}
+		// If the node stopped after the block was accepted, but before the
+		// inner block was, the inner block is accepted now
+		if inner := lastAccepted.getInner(); inner.Status() != choices.Accepted {
+			if err := inner.Accept(); err != nil {
+				return err
+			}
+		}
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+	vm.preferred = vm.LastAccepted()
+
+	startTime := vm.clock.Time()
+	go ctx.Log.RecoverAndPanic(func() {
+		vm.scheduler.dispatch(startTime)
+	})
+	return nil
+}
+
+// Shutdown implements the common.VM interface
+func (vm *VM) Shutdown() error {
+	if vm.scheduler != nil {
+		vm.scheduler.shutdown()
+	}
+	return vm.ChainVM.Shutdown()
+}
+
+// BuildBlock implements the block.ChainVM interface. After the fork, the
+// inner VM only builds a block once this node's window starts.
+func (vm *VM) BuildBlock() (snowman.Block, error) {
+	preferred, err := vm.getBlock(vm.preferred)
+	if err != nil {
+		return nil, err
+	}
+	now := vm.clock.Time()
+	if _, ok := preferred.(*preForkBlock); ok && now.Before(vm.activationTime) {
+		inner, err := vm.ChainVM.BuildBlock()
+		if err != nil {
+			return nil, err
+		}
+		return oracle(vm.preFork(inner)), nil
+	}
+
+	if vm.signer == nil {
+		return nil, errNoStakingKey
+	}
+	buildTime, err := vm.buildTime(preferred)
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(buildTime) {
+		// The inner VM's request to build a block is held until this node's
+		// window starts
+		select {
+		case vm.fromVM <- common.PendingTxs:
+		default:
+		}
+		return nil, ErrWindowNotStarted
+	}
+
+	pChainHeight, err := vm.pChainHeight(preferred)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := vm.ChainVM.BuildBlock()
+	if err != nil {
+		return nil, err
+	}
+	if inner.Parent().ID() != preferred.getInner().ID() {
+		return nil, errInnerParentMismatch
+	}
+	stateless := &SignedBlock{
+		ParentID:     preferred.ID(),
+		Timestamp:    uint64(now.Unix()),
+		PChainHeight: pChainHeight,
+		Certificate:  vm.cert,
+		Block:        inner.Bytes(),
+	}
+	if err := stateless.Sign(vm.signer); err != nil {
+		return nil, err
+	}
+	blk, err := vm.newPostForkBlock(stateless, inner)
+	if err != nil {
+		return nil, err
+	}
+	return oracle(blk), nil
+}
+
+// ParseBlock implements the block.ChainVM interface. Bytes that aren't a block
+// of the wrapper are parsed as a block the inner VM built before the fork.
+func (vm *VM) ParseBlock(b []byte) (snowman.Block, error) {
+	if blk, err := vm.parsePostFork(b); err == nil {
+		return oracle(blk), nil
+	}
+	inner, err := vm.ChainVM.ParseBlock(b)
+	if err != nil {
+		return nil, err
+	}
+	return oracle(vm.preFork(inner)), nil
+}
+
+// GetBlock implements the block.ChainVM interface
+func (vm *VM) GetBlock(blkID ids.ID) (snowman.Block, error) {
+	blk, err := vm.getBlock(blkID)
+	if err != nil {
+		return nil, err
+	}
+	return oracle(blk), nil
+}
+
+// SetPreference implements the block.ChainVM interface
+func (vm *VM) SetPreference(blkID ids.ID) {
+	blk, err := vm.getBlock(blkID)
+	if err != nil {
+		vm.ctx.Log.Error("couldn't get preferred block %s: %s", blkID, err)
+		return
+	}
+	vm.preferred = blkID
+	vm.ChainVM.SetPreference(blk.getInner().ID())
+
+	buildTime, err := vm.buildTime(blk)
+	if err != nil {
+		vm.ctx.Log.Error("couldn't get the windows of the children of %s: %s", blkID, err)
+		return
+	}
+	vm.scheduler.setBuildTime(buildTime)
+}
+
+// LastAccepted implements the block.ChainVM interface
+func (vm *VM) LastAccepted() ids.ID {
+	if vm.lastAccepted != ids.Empty {
+		return vm.lastAccepted
+	}
+	return vm.ChainVM.LastAccepted()
+}
+
+// AppRequest implements the common.AppHandler interface
+func (vm *VM) AppRequest(nodeID ids.ShortID, requestID uint32, request []byte) error {
+	if handler, ok := vm.ChainVM.(common.AppHandler); ok {
+		return handler.AppRequest(nodeID, requestID, request)
+	}
+	return nil
+}
+
+// AppRequestFailed implements the common.AppHandler interface
+func (vm *VM) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
+	if handler, ok := vm.ChainVM.(common.AppHandler); ok {
+		return handler.AppRequestFailed(nodeID, requestID)
+	}
+	return nil
+}
+
+// AppResponse implements the common.AppHandler interface
+func (vm *VM) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
+	if handler, ok := vm.ChainVM.(common.AppHandler); ok {
+		return handler.AppResponse(nodeID, requestID, response)
+	}
+	return nil
+}
+
+// AppGossip implements the common.AppHandler interface
+func (vm *VM) AppGossip(nodeID ids.ShortID, msg []byte) error {
+	if handler, ok := vm.ChainVM.(common.AppHandler); ok {
+		return handler.AppGossip(nodeID, msg)
+	}
+	return nil
+}
+
+// Connected implements the validators.Connector interface
+func (vm *VM) Connected(nodeID ids.ShortID) {
+	if connector, ok := vm.ChainVM.(validators.Connector); ok {
+		connector.Connected(nodeID)
+	}
+}
+
+// Disconnected implements the validators.Connector interface
+func (vm *VM) Disconnected(nodeID ids.ShortID) {
+	if connector, ok := vm.ChainVM.(validators.Connector); ok {
+		connector.Disconnected(nodeID)
+	}
+}
+
+// AcceptedTxs implements the pubsub.TxParser interface. The txs are parsed by
+// the inner VM from the inner block of the accepted block [blkID].
+func (vm *VM) AcceptedTxs(blkID ids.ID, _ []byte) ([]pubsub.Tx, error) {
+	parser, ok := vm.ChainVM.(pubsub.TxParser)
+	if !ok {
+		return nil, nil
+	}
+	blk, err := vm.getBlock(blkID)
+	if err != nil {
+		return nil, err
+	}
+	inner := blk.getInner()
+	return parser.AcceptedTxs(inner.ID(), inner.Bytes())
+}
+
+// buildTime returns the time this node may build a child of [parent] at
+func (vm *VM) buildTime(parent Block) (time.Time, error) {
+	if _, ok := parent.(*preForkBlock); ok {
+		if now := vm.clock.Time(); now.Before(vm.activationTime) {
+			return now, nil
+		}
+	}
+	if vm.signer == nil {
+		return timer.MaxTime, nil
+	}
+	windowsStart, err := parent.windowsStart()
+	if err != nil {
+		return time.Time{}, err
+	}
+	pChainHeight, err := vm.pChainHeight(parent)
+	if err != nil {
+		return time.Time{}, err
+	}
+	delay, err := vm.delay(parent.Height()+1, pChainHeight, vm.ctx.NodeID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return windowsStart.Add(delay), nil
+}
+
+// pChainHeight returns the P-chain height this node proposes a child of
+// [parent] at, which is the last accepted height of the P-chain unless the
+// parent was proposed at a later one
+func (vm *VM) pChainHeight(parent Block) (uint64, error) {
+	parentPChainHeight, err := parent.pChainHeight()
+	if err != nil {
+		return 0, err
+	}
+	currentPChainHeight, err := vm.state.GetCurrentHeight()
+	if err != nil {
+		return 0, err
+	}
+	if currentPChainHeight < parentPChainHeight {
+		return parentPChainHeight, nil
+	}
+	return currentPChainHeight, nil
+}
+
+// getBlock returns the block with ID [blkID]
+func (vm *VM) getBlock(blkID ids.ID) (Block, error) {
+	if blk, ok := vm.verified[blkID]; ok {
+		return blk, nil
+	}
+	blkBytes, err := vm.db.Get(blkID[:])
+	switch err {
+	case nil:
+		return vm.parsePostFork(blkBytes)
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+	inner, err := vm.ChainVM.GetBlock(blkID)
+	if err != nil {
+		return nil, err
+	}
+	return vm.preFork(inner), nil
+}
+
+// preFork returns the block that wraps [inner], which was built before the
+// fork
+func (vm *VM) preFork(inner snowman.Block) Block {
+	if blk, ok := vm.verified[inner.ID()]; ok {
+		return blk
+	}
+	return &preForkBlock{
+		Block: inner,
+		vm:    vm,
+	}
+}
+
+// parsePostFork parses [b] as a block the wrapper added around an inner block
+func (vm *VM) parsePostFork(b []byte) (Block, error) {
+	blkID := hashing.ComputeHash256Array(b)
+	if blk, ok := vm.verified[blkID]; ok {
+		return blk, nil
+	}
+
+	var stateless statelessBlock
+	if _, err := Codec.Unmarshal(b, &stateless); err != nil {
+		return nil, err
+	}
+	inner, err := vm.ChainVM.ParseBlock(stateless.innerBytes())
+	if err != nil {
+		return nil, err
+	}
+	status := choices.Processing
+	if accepted, err := vm.db.Has(blkID[:]); err != nil {
+		return nil, err
+	} else if accepted {
+		status = choices.Accepted
+	}
+
+	base := postForkCommon{
+		vm:       vm,
+		id:       blkID,
+		parentID: stateless.parent(),
+		bytes:    b,
+		inner:    inner,
+		status:   status,
+	}
+	switch stateless := stateless.(type) {
+	case *SignedBlock:
+		return &postForkBlock{
+			postForkCommon: base,
+			stateless:      stateless,
+		}, nil
+	default:
+		return &postForkOption{postForkCommon: base}, nil
+	}
+}
+
+// newPostForkBlock returns the block [stateless], which wraps [inner]
+func (vm *VM) newPostForkBlock(stateless *SignedBlock, inner snowman.Block) (Block, error) {
+	b, err := stateless.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &postForkBlock{
+		postForkCommon: postForkCommon{
+			vm:       vm,
+			id:       hashing.ComputeHash256Array(b),
+			parentID: stateless.ParentID,
+			bytes:    b,
+			inner:    inner,
+			status:   choices.Processing,
+		},
+		stateless: stateless,
+	}, nil
+}
+
+// newOption returns the block that wraps [inner], an option of the inner block
+// wrapped by the block with ID [parentID]
+func (vm *VM) newOption(parentID ids.ID, inner snowman.Block) (Block, error) {
+	var stateless statelessBlock = &OptionBlock{
+		ParentID: parentID,
+		Block:    inner.Bytes(),
+	}
+	b, err := Codec.Marshal(codecVersion, &stateless)
+	if err != nil {
+		return nil, err
+	}
+	blkID := hashing.ComputeHash256Array(b)
+	if blk, ok := vm.verified[blkID]; ok {
+		return blk, nil
+	}
+	status := choices.Processing
+	if accepted, err := vm.db.Has(blkID[:]); err != nil {
+		return nil, err
+	} else if accepted {
+		status = choices.Accepted
+	}
+	return &postForkOption{
+		postForkCommon: postForkCommon{
+			vm:       vm,
+			id:       blkID,
+			parentID: parentID,
+			bytes:    b,
+			inner:    inner,
+			status:   status,
+		},
+	}, nil
+}
+
+// innerBlock is an inner block wrapped by verified blocks
+type innerBlock struct {
+	// The inner block that was verified. Inner VMs may parse the same bytes
+	// into different blocks, so only this one is decided.
+	blk snowman.Block
+	// Number of verified blocks that wrap the inner block
+	refs int
+}
+
+// verifyInner verifies the inner block of [blk], unless another verified
+// block wraps it, and marks [blk] as verified. Returns the inner block that
+// was verified, which [blk] must wrap from now on.
+func (vm *VM) verifyInner(blk Block) (snowman.Block, error) {
+	inner := blk.getInner()
+	innerID := inner.ID()
+	verified, ok := vm.innerBlocks[innerID]
+	if !ok {
+		if inner.Status().Decided() {
+			return nil, errInnerDecided
+		}
+		if err := inner.Verify(); err != nil {
+			return nil, err
+		}
+		verified = &innerBlock{blk: inner}
+		vm.innerBlocks[innerID] = verified
+	}
+	verified.refs++
+	vm.verified[blk.ID()] = blk
+	return verified.blk, nil
+}
+
+// unverify removes the block with ID [blkID], which wraps [inner], from the
+// verified blocks. Returns the number of verified blocks that still wrap
+// [inner].
+func (vm *VM) unverify(blkID ids.ID, inner snowman.Block) int {
+	innerID := inner.ID()
+	verified, ok := vm.innerBlocks[innerID]
+	if !ok {
+		return 0
+	}
+	if _, ok := vm.verified[blkID]; ok {
+		delete(vm.verified, blkID)
+		verified.refs--
+	}
+	if verified.refs == 0 {
+		delete(vm.innerBlocks, innerID)
+	}
+	return verified.refs
+}
+
+// acceptPostFork persists the block at [height] with ID [blkID] and bytes
+// [blkBytes] as the last accepted block
+func (vm *VM) acceptPostFork(blkID ids.ID, blkBytes []byte, height uint64) error {
+	batch := vm.db.NewBatch()
+	if err := batch.Put(blkID[:], blkBytes); err != nil {
+		return err
+	}
+	if err := batch.Put(lastAcceptedKey, blkID[:]); err != nil {
+		return err
+	}
+	if err := batch.Put(heightKey(height), blkID[:]); err != nil {
+		return err
+	}
+	if vm.lastAccepted == ids.Empty {
+		if err := batch.Put(forkHeightKey, packHeight(height)); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	vm.lastAccepted = blkID
+	return nil
+}
+
+// acceptInner accepts [inner], which is wrapped by the accepted block with ID
+// [blkID]
+func (vm *VM) acceptInner(blkID ids.ID, inner snowman.Block) error {
+	vm.unverify(blkID, inner)
+	return inner.Accept()
+}
+
+// rejectInner rejects [inner], which is wrapped by the rejected block with ID
+// [blkID], unless another verified block still wraps it
+func (vm *VM) rejectInner(blkID ids.ID, inner snowman.Block) error {
+	if vm.unverify(blkID, inner) > 0 || inner.Status() != choices.Processing {
+		return nil
+	}
+	return inner.Reject()
+}
@@ -0,0 +1,658 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/pubsub"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/timer"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+var testTime = time.Unix(1600000000, 0)
+
+// The last accepted height of the P-chain in the tests
+const testPChainHeight = 10
+
+func newTestStakingCert(t *testing.T) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0),
+		NotBefore:    time.Date(2000, time.January, 0, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Certificate{
+		Certificate: [][]byte{cert},
+		PrivateKey:  key,
+	}
+}
+
+func newTestBlock(parent snowman.Block) *snowman.TestBlock {
+	blkID := ids.GenerateTestID()
+	return &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID,
+			StatusV: choices.Processing,
+		},
+		ParentV: parent,
+		HeightV: parent.Height() + 1,
+		BytesV:  blkID[:],
+	}
+}
+
+func newTestGenesis() *snowman.TestBlock {
+	blkID := ids.GenerateTestID()
+	return &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     blkID,
+			StatusV: choices.Accepted,
+		},
+		BytesV: blkID[:],
+	}
+}
+
+// newTestInnerVM returns a VM with the blocks [blks], the first of which is
+// its genesis
+func newTestInnerVM(t *testing.T, blks ...snowman.Block) *block.TestVM {
+	vm := &block.TestVM{}
+	vm.T = t
+	vm.Default(true)
+	vm.CantSetPreference = false
+
+	vm.InitializeF = func(*snow.Context, database.Database, []byte, chan<- common.Message, []*common.Fx) error {
+		return nil
+	}
+	vm.ShutdownF = func() error { return nil }
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID {
+				return blk, nil
+			}
+		}
+		return nil, database.ErrNotFound
+	}
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if bytes.Equal(blk.Bytes(), b) {
+				return blk, nil
+			}
+		}
+		return nil, errors.New("unknown block")
+	}
+	vm.LastAcceptedF = func() ids.ID {
+		lastAccepted := blks[0]
+		for _, blk := range blks {
+			if blk.Status() == choices.Accepted && blk.Height() > lastAccepted.Height() {
+				lastAccepted = blk
+			}
+		}
+		return lastAccepted.ID()
+	}
+	return vm
+}
+
+// newTestVM returns an initialized VM that wraps [inner] from [activationTime]
+// and proposes blocks with [cert]
+func newTestVM(
+	t *testing.T,
+	inner block.ChainVM,
+	activationTime time.Time,
+	cert *tls.Certificate,
+	state validators.State,
+	db database.Database,
+) *VM {
+	ctx := snow.DefaultContextTest()
+	ctx.NodeID = certToID(cert.Certificate[0])
+	ctx.Bootstrapped()
+
+	vm := New(inner, activationTime, cert, state, db)
+	vm.clock.Set(testTime)
+	if err := vm.Initialize(ctx, memdb.New(), nil, make(chan common.Message, 1), nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := vm.Shutdown(); err != nil {
+			t.Error(err)
+		}
+	})
+	return vm
+}
+
+// newTestState returns a P-chain at [testPChainHeight], at whose heights each
+// node of [certs] has the same stake
+func newTestState(t *testing.T, certs ...*tls.Certificate) *validators.TestState {
+	vdrs := make(map[ids.ShortID]uint64, len(certs))
+	for _, cert := range certs {
+		vdrs[certToID(cert.Certificate[0])] = 1
+	}
+	return &validators.TestState{
+		T: t,
+		GetCurrentHeightF: func() (uint64, error) {
+			return testPChainHeight, nil
+		},
+		GetValidatorSetF: func(height uint64, _ ids.ID) (map[ids.ShortID]uint64, error) {
+			if height > testPChainHeight {
+				return nil, database.ErrNotFound
+			}
+			return vdrs, nil
+		},
+	}
+}
+
+func TestVMPreForkBlocks(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	inner := newTestInnerVM(t, genesis, child)
+	inner.BuildBlockF = func() (snowman.Block, error) { return child, nil }
+
+	cert := newTestStakingCert(t)
+	vm := newTestVM(t, inner, timer.MaxTime, cert, newTestState(t, cert), memdb.New())
+
+	if lastAccepted := vm.LastAccepted(); lastAccepted != genesis.ID() {
+		t.Fatalf("expected the last accepted block %s but got %s", genesis.ID(), lastAccepted)
+	}
+	vm.SetPreference(genesis.ID())
+
+	// Before the fork, the inner blocks are unchanged
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blk.ID() != child.ID() {
+		t.Fatalf("expected the block %s but got %s", child.ID(), blk.ID())
+	}
+	if !bytes.Equal(blk.Bytes(), child.Bytes()) {
+		t.Fatal("the block's bytes aren't the inner block's bytes")
+	}
+	parsed, err := vm.ParseBlock(child.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ID() != child.ID() {
+		t.Fatalf("expected the block %s but got %s", child.ID(), parsed.ID())
+	}
+
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if status := child.Status(); status != choices.Accepted {
+		t.Fatalf("expected the inner block to be %s but it's %s", choices.Accepted, status)
+	}
+	if lastAccepted := vm.LastAccepted(); lastAccepted != child.ID() {
+		t.Fatalf("expected the last accepted block %s but got %s", child.ID(), lastAccepted)
+	}
+}
+
+func TestVMPostForkBlock(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	inner := newTestInnerVM(t, genesis, child)
+	inner.BuildBlockF = func() (snowman.Block, error) { return child, nil }
+
+	proposerCert, cert := newTestStakingCert(t), newTestStakingCert(t)
+	state := newTestState(t, proposerCert, cert)
+	activationTime := testTime.Add(-time.Minute)
+	proposer := newTestVM(t, inner, activationTime, proposerCert, state, memdb.New())
+	proposer.SetPreference(genesis.ID())
+
+	// Every window of the first block after the fork already started
+	blk, err := proposer.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blk.ID() == child.ID() {
+		t.Fatal("the block after the fork is the inner block")
+	}
+	if blk.Parent().ID() != genesis.ID() {
+		t.Fatalf("expected the parent %s but got %s", genesis.ID(), blk.Parent().ID())
+	}
+	if blk.Height() != child.Height() {
+		t.Fatalf("expected the height %d but got %d", child.Height(), blk.Height())
+	}
+
+	// Another node verifies and accepts the block
+	db := memdb.New()
+	vm := newTestVM(t, newTestInnerVM(t, genesis, child), activationTime, cert, state, db)
+	parsed, err := vm.ParseBlock(blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ID() != blk.ID() {
+		t.Fatalf("expected the block %s but got %s", blk.ID(), parsed.ID())
+	}
+	if err := parsed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if status := child.Status(); status != choices.Accepted {
+		t.Fatalf("expected the inner block to be %s but it's %s", choices.Accepted, status)
+	}
+	if lastAccepted := vm.LastAccepted(); lastAccepted != blk.ID() {
+		t.Fatalf("expected the last accepted block %s but got %s", blk.ID(), lastAccepted)
+	}
+
+	// The block is still accepted once the node restarts
+	restarted := newTestVM(t, newTestInnerVM(t, genesis, child), activationTime, cert, state, db)
+	if lastAccepted := restarted.LastAccepted(); lastAccepted != blk.ID() {
+		t.Fatalf("expected the last accepted block %s but got %s", blk.ID(), lastAccepted)
+	}
+	stored, err := restarted.GetBlock(blk.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := stored.Status(); status != choices.Accepted {
+		t.Fatalf("expected the block to be %s but it's %s", choices.Accepted, status)
+	}
+
+	// Blocks built before the fork can't follow it
+	preForkChild := newTestBlock(child)
+	inner = newTestInnerVM(t, genesis, child, preForkChild)
+	restarted.ChainVM = inner
+	preForkBlk, err := restarted.ParseBlock(preForkChild.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := preForkBlk.Verify(); err != errPreForkAfterFork {
+		t.Fatalf("expected %s but got %v", errPreForkAfterFork, err)
+	}
+}
+
+func TestVMProposerWindows(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	grandchild := newTestBlock(child)
+
+	certs := []*tls.Certificate{newTestStakingCert(t), newTestStakingCert(t)}
+	state := newTestState(t, certs...)
+	activationTime := testTime.Add(-time.Minute)
+	vms := []*VM{}
+	builds := []int{}
+	for i, cert := range certs {
+		i := i
+		inner := newTestInnerVM(t, genesis, child, grandchild)
+		inner.BuildBlockF = func() (snowman.Block, error) {
+			builds[i]++
+			return grandchild, nil
+		}
+		vms = append(vms, newTestVM(t, inner, activationTime, cert, state, memdb.New()))
+		builds = append(builds, 0)
+	}
+
+	// The first block after the fork is built at [testTime]
+	stateless := &SignedBlock{
+		ParentID:    genesis.ID(),
+		Timestamp:   uint64(testTime.Unix()),
+		Certificate: certs[0].Certificate[0],
+		Block:       child.Bytes(),
+	}
+	if err := stateless.Sign(vms[0].signer); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := vms[0].newPostForkBlock(stateless, child)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, vm := range vms {
+		blk, err := vm.ParseBlock(parent.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		vm.SetPreference(blk.ID())
+	}
+
+	first, second := 0, 1
+	if proposers, err := vms[0].proposers(grandchild.Height(), testPChainHeight); err != nil {
+		t.Fatal(err)
+	} else if proposers[0] == vms[1].ctx.NodeID {
+		first, second = 1, 0
+	}
+
+	// The second proposer can't build a block in the first proposer's window
+	if _, err := vms[second].BuildBlock(); err != ErrWindowNotStarted {
+		t.Fatalf("expected %s but got %v", ErrWindowNotStarted, err)
+	}
+	if builds[second] != 0 {
+		t.Fatal("the inner VM built a block before the proposer's window started")
+	}
+	early := &SignedBlock{
+		ParentID:    parent.ID(),
+		Timestamp:   uint64(testTime.Unix()),
+		Certificate: certs[second].Certificate[0],
+		Block:       grandchild.Bytes(),
+	}
+	if err := early.Sign(vms[second].signer); err != nil {
+		t.Fatal(err)
+	}
+	earlyBlk, err := vms[first].newPostForkBlock(early, grandchild)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := earlyBlk.Verify(); !errors.Is(err, ErrWindowNotStarted) {
+		t.Fatalf("expected %s but got %v", ErrWindowNotStarted, err)
+	}
+
+	// Once its window starts, the second proposer builds the block
+	for _, vm := range vms {
+		vm.clock.Set(testTime.Add(WindowDuration))
+	}
+	blk, err := vms[second].BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := vms[first].ParseBlock(blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVMPChainHeight(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	grandchild := newTestBlock(child)
+	inner := newTestInnerVM(t, genesis, child, grandchild)
+	inner.BuildBlockF = func() (snowman.Block, error) { return child, nil }
+
+	cert := newTestStakingCert(t)
+	vm := newTestVM(t, inner, testTime.Add(-time.Minute), cert, newTestState(t, cert), memdb.New())
+	vm.SetPreference(genesis.ID())
+
+	// Blocks are proposed at the last accepted height of the P-chain
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height := blk.(*postForkBlock).stateless.PChainHeight; height != testPChainHeight {
+		t.Fatalf("expected the P-chain height %d but got %d", testPChainHeight, height)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pChainHeight uint64
+		expected     error
+	}{
+		{testPChainHeight - 1, errPChainHeightTooLow},
+		{testPChainHeight + 1, errPChainHeightTooHigh},
+	}
+	for _, test := range tests {
+		stateless := &SignedBlock{
+			ParentID:     blk.ID(),
+			Timestamp:    uint64(testTime.Unix()),
+			PChainHeight: test.pChainHeight,
+			Certificate:  cert.Certificate[0],
+			Block:        grandchild.Bytes(),
+		}
+		if err := stateless.Sign(vm.signer); err != nil {
+			t.Fatal(err)
+		}
+		childBlk, err := vm.newPostForkBlock(stateless, grandchild)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := childBlk.Verify(); !errors.Is(err, test.expected) {
+			t.Fatalf("expected %s but got %v", test.expected, err)
+		}
+	}
+}
+
+// testTxParserVM is an inner VM whose accepted txs can be streamed
+type testTxParserVM struct {
+	*block.TestVM
+	acceptedTxsF func(ids.ID, []byte) ([]pubsub.Tx, error)
+}
+
+func (vm *testTxParserVM) AcceptedTxs(blkID ids.ID, blkBytes []byte) ([]pubsub.Tx, error) {
+	return vm.acceptedTxsF(blkID, blkBytes)
+}
+
+func TestVMAcceptedTxs(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	tx := pubsub.Tx{ID: ids.GenerateTestID()}
+	inner := &testTxParserVM{
+		TestVM: newTestInnerVM(t, genesis, child),
+		acceptedTxsF: func(blkID ids.ID, blkBytes []byte) ([]pubsub.Tx, error) {
+			if blkID != child.ID() || !bytes.Equal(blkBytes, child.Bytes()) {
+				t.Fatalf("expected the txs of the inner block %s but got those of %s", child.ID(), blkID)
+			}
+			return []pubsub.Tx{tx}, nil
+		},
+	}
+	inner.BuildBlockF = func() (snowman.Block, error) { return child, nil }
+
+	cert := newTestStakingCert(t)
+	vm := newTestVM(t, inner, testTime.Add(-time.Minute), cert, newTestState(t, cert), memdb.New())
+	vm.SetPreference(genesis.ID())
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Accept(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The txs of the block are parsed from its inner block
+	txs, err := vm.AcceptedTxs(blk.ID(), blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].ID != tx.ID {
+		t.Fatalf("expected the tx %s but got %v", tx.ID, txs)
+	}
+
+	// Inner VMs that don't parse their txs don't stream any
+	vm.ChainVM = inner.TestVM
+	if txs, err := vm.AcceptedTxs(blk.ID(), blk.Bytes()); err != nil || len(txs) != 0 {
+		t.Fatalf("expected no txs but got %v, %v", txs, err)
+	}
+}
+
+func TestVMInvalidSignature(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	cert := newTestStakingCert(t)
+	vm := newTestVM(t, newTestInnerVM(t, genesis, child), testTime.Add(-time.Minute), cert, newTestState(t, cert), memdb.New())
+
+	stateless := &SignedBlock{
+		ParentID:    genesis.ID(),
+		Timestamp:   uint64(testTime.Unix()),
+		Certificate: cert.Certificate[0],
+		Block:       child.Bytes(),
+	}
+	if err := stateless.Sign(vm.signer); err != nil {
+		t.Fatal(err)
+	}
+	// The signature doesn't cover a different timestamp
+	stateless.Timestamp++
+	blk, err := vm.newPostForkBlock(stateless, child)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blk.Verify(); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected %s but got %v", errInvalidSignature, err)
+	}
+}
+
+func TestVMSameInnerBlock(t *testing.T) {
+	genesis := newTestGenesis()
+	child := newTestBlock(genesis)
+	// The inner VM parsed the same bytes twice
+	parsedChild := *child
+	certs := []*tls.Certificate{newTestStakingCert(t), newTestStakingCert(t)}
+	vm := newTestVM(t, newTestInnerVM(t, genesis, child), testTime.Add(-time.Minute), certs[0], newTestState(t, certs...), memdb.New())
+
+	// Two proposers wrap the same inner block
+	blks := []Block{}
+	for i, inner := range []snowman.Block{child, &parsedChild} {
+		stateless := &SignedBlock{
+			ParentID:    genesis.ID(),
+			Timestamp:   uint64(testTime.Unix()),
+			Certificate: certs[i].Certificate[0],
+			Block:       child.Bytes(),
+		}
+		if err := stateless.Sign(certs[i].PrivateKey.(crypto.Signer)); err != nil {
+			t.Fatal(err)
+		}
+		blk, err := vm.newPostForkBlock(stateless, inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := blk.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		blks = append(blks, blk)
+	}
+
+	// Only the inner block that was verified is decided
+	if err := blks[1].Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := blks[0].Reject(); err != nil {
+		t.Fatal(err)
+	}
+	if status := child.Status(); status != choices.Accepted {
+		t.Fatalf("expected the verified inner block to be %s but it's %s", choices.Accepted, status)
+	}
+	if status := parsedChild.Status(); status != choices.Processing {
+		t.Fatalf("expected the other inner block to be %s but it's %s", choices.Processing, status)
+	}
+}
+
+// testOracleBlock is a block with two options
+type testOracleBlock struct {
+	*snowman.TestBlock
+	options [2]snowman.Block
+}
+
+func (b *testOracleBlock) Options() ([2]snowman.Block, error) { return b.options, nil }
+
+func TestVMOptions(t *testing.T) {
+	genesis := newTestGenesis()
+	oracleBlk := &testOracleBlock{TestBlock: newTestBlock(genesis)}
+	oracleBlk.options = [2]snowman.Block{newTestBlock(oracleBlk), newTestBlock(oracleBlk)}
+	inner := newTestInnerVM(t, genesis, oracleBlk, oracleBlk.options[0], oracleBlk.options[1])
+	inner.BuildBlockF = func() (snowman.Block, error) { return oracleBlk, nil }
+
+	cert := newTestStakingCert(t)
+	vm := newTestVM(t, inner, testTime.Add(-time.Minute), cert, newTestState(t, cert), memdb.New())
+	vm.SetPreference(genesis.ID())
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oracle, ok := blk.(smeng.OracleBlock)
+	if !ok {
+		t.Fatal("the block that wraps an oracle block isn't an oracle block")
+	}
+	if err := oracle.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	options, err := oracle.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, option := range options {
+		if option.Parent().ID() != oracle.ID() {
+			t.Fatalf("expected option %d to be a child of %s but got %s", i, oracle.ID(), option.Parent().ID())
+		}
+		if err := option.Verify(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every node builds the same options
+	sameOptions, err := oracle.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range options {
+		if options[i].ID() != sameOptions[i].ID() {
+			t.Fatalf("expected option %d to be %s but got %s", i, options[i].ID(), sameOptions[i].ID())
+		}
+	}
+
+	if err := oracle.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := options[0].Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if err := options[1].Reject(); err != nil {
+		t.Fatal(err)
+	}
+	if status := oracleBlk.options[0].Status(); status != choices.Accepted {
+		t.Fatalf("expected the inner option to be %s but it's %s", choices.Accepted, status)
+	}
+	if status := oracleBlk.options[1].Status(); status != choices.Rejected {
+		t.Fatalf("expected the inner option to be %s but it's %s", choices.Rejected, status)
+	}
+	if lastAccepted := vm.LastAccepted(); lastAccepted != options[0].ID() {
+		t.Fatalf("expected the last accepted block %s but got %s", options[0].ID(), lastAccepted)
+	}
+
+	// The children of an oracle block must be its options
+	child := newTestBlock(oracleBlk)
+	stateless := &SignedBlock{
+		ParentID:    oracle.ID(),
+		Timestamp:   uint64(testTime.Unix()),
+		Certificate: cert.Certificate[0],
+		Block:       child.Bytes(),
+	}
+	if err := stateless.Sign(vm.signer); err != nil {
+		t.Fatal(err)
+	}
+	childBlk, err := vm.newPostForkBlock(stateless, child)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := childBlk.Verify(); err != errOracleParent {
+		t.Fatalf("expected %s but got %v", errOracleParent, err)
+	}
+}
@@ -0,0 +1,116 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+const (
+	// WindowDuration is how long each proposer of a height has to build its
+	// block before the next proposer may build one
+	WindowDuration = 5 * time.Second
+
+	// MaxWindows is the number of proposers of each height. Once all of their
+	// windows passed, any node may build the block.
+	MaxWindows = 6
+
+	// MaxDelay is how long after its parent any node may build a block
+	MaxDelay = MaxWindows * WindowDuration
+)
+
+// windower orders the validators of a chain into the proposers of each height
+type windower struct {
+	state    validators.State
+	subnetID ids.ID
+	chainID  ids.ID
+}
+
+// proposers returns the nodes that may propose the block at [height], in the
+// order their windows start. The list is sampled, weighted by stake, from the
+// validators of the chain's subnet once the P-chain block at [pChainHeight]
+// was accepted, so it's the same on every node. A validator may be sampled
+// more than once.
+func (w *windower) proposers(height, pChainHeight uint64) ([]ids.ShortID, error) {
+	weights, err := w.state.GetValidatorSet(pChainHeight, w.subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The validators are sorted so the sample doesn't depend on the order
+	// they're stored in
+	vdrIDs := make([]ids.ShortID, 0, len(weights))
+	for vdrID := range weights {
+		vdrIDs = append(vdrIDs, vdrID)
+	}
+	sort.Slice(vdrIDs, func(i, j int) bool {
+		return bytes.Compare(vdrIDs[i][:], vdrIDs[j][:]) < 0
+	})
+
+	vdrWeights := make([]uint64, len(vdrIDs))
+	totalWeight := uint64(0)
+	for i, vdrID := range vdrIDs {
+		vdrWeights[i] = weights[vdrID]
+		totalWeight, err = safemath.Add64(totalWeight, vdrWeights[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s := sampler.NewDeterministicWeightedWithoutReplacement(rand.NewSource(w.seed(height)))
+	if err := s.Initialize(vdrWeights); err != nil {
+		return nil, err
+	}
+	numProposers := MaxWindows
+	if totalWeight < MaxWindows {
+		numProposers = int(totalWeight)
+	}
+	indices, err := s.Sample(numProposers)
+	if err != nil {
+		return nil, err
+	}
+
+	proposers := make([]ids.ShortID, len(indices))
+	for i, index := range indices {
+		proposers[i] = vdrIDs[index]
+	}
+	return proposers, nil
+}
+
+// delay returns how long after its parent [nodeID] may build the block at
+// [height] that records [pChainHeight]
+func (w *windower) delay(height, pChainHeight uint64, nodeID ids.ShortID) (time.Duration, error) {
+	proposers, err := w.proposers(height, pChainHeight)
+	if err != nil {
+		return 0, err
+	}
+	for i, proposer := range proposers {
+		if proposer == nodeID {
+			return time.Duration(i) * WindowDuration, nil
+		}
+	}
+	return MaxDelay, nil
+}
+
+// seed returns the seed of the sample of the proposers of [height]. Chains
+// validated by the same nodes get different proposers.
+func (w *windower) seed(height uint64) int64 {
+	p := wrappers.Packer{Bytes: make([]byte, hashing.HashLen+wrappers.LongLen)}
+	p.PackFixedBytes(w.chainID[:])
+	p.PackLong(height)
+	hash := hashing.ComputeHash256(p.Bytes)
+	return int64(binary.BigEndian.Uint64(hash))
+}
@@ -0,0 +1,189 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+// newTestWindower returns a windower of a new chain whose validators are
+// [vdrs] at each P-chain height
+func newTestWindower(t *testing.T, vdrs map[ids.ShortID]uint64) *windower {
+	return &windower{
+		state: &validators.TestState{
+			T: t,
+			GetValidatorSetF: func(uint64, ids.ID) (map[ids.ShortID]uint64, error) {
+				return vdrs, nil
+			},
+		},
+		chainID: ids.GenerateTestID(),
+	}
+}
+
+// proposers returns the proposers of [height] at P-chain height 0
+func proposers(t *testing.T, w *windower, height uint64) []ids.ShortID {
+	proposers, err := w.proposers(height, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proposers
+}
+
+func TestWindowerProposers(t *testing.T) {
+	vdrs := make(map[ids.ShortID]uint64)
+	for i := uint64(1); i <= 10; i++ {
+		vdrs[ids.GenerateTestShortID()] = i
+	}
+	w := newTestWindower(t, vdrs)
+
+	sampled := proposers(t, w, 1)
+	if len(sampled) != MaxWindows {
+		t.Fatalf("expected %d proposers but got %d", MaxWindows, len(sampled))
+	}
+	for i, proposer := range sampled {
+		if _, ok := vdrs[proposer]; !ok {
+			t.Fatalf("proposer %d isn't a validator", i)
+		}
+	}
+	// A validator sampled more than once proposes in its first window
+	for _, proposer := range sampled {
+		first := 0
+		for sampled[first] != proposer {
+			first++
+		}
+		delay, err := w.delay(1, 0, proposer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := time.Duration(first) * WindowDuration; delay != expected {
+			t.Fatalf("expected proposer %s to wait %s but got %s", proposer, expected, delay)
+		}
+	}
+	if delay, err := w.delay(1, 0, ids.GenerateTestShortID()); err != nil {
+		t.Fatal(err)
+	} else if delay != MaxDelay {
+		t.Fatalf("expected a non-validator to wait %s but got %s", MaxDelay, delay)
+	}
+
+	// Every node samples the same proposers
+	if resampled := proposers(t, w, 1); !equalShortIDs(sampled, resampled) {
+		t.Fatalf("expected the proposers %v but got %v", sampled, resampled)
+	}
+
+	// The proposers change across heights and chains
+	otherChain := newTestWindower(t, vdrs)
+	changedHeight, changedChain := false, false
+	for height := uint64(2); height < 20; height++ {
+		changedHeight = changedHeight || !equalShortIDs(sampled, proposers(t, w, height))
+		changedChain = changedChain || !equalShortIDs(proposers(t, w, height), proposers(t, otherChain, height))
+	}
+	if !changedHeight {
+		t.Fatal("the proposers are the same at every height")
+	}
+	if !changedChain {
+		t.Fatal("the proposers are the same on every chain")
+	}
+}
+
+func TestWindowerStakeWeighted(t *testing.T) {
+	heavy, light := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	w := newTestWindower(t, map[ids.ShortID]uint64{
+		heavy: 99,
+		light: 1,
+	})
+
+	heavyFirst := 0
+	for height := uint64(0); height < 100; height++ {
+		sampled := proposers(t, w, height)
+		if len(sampled) != MaxWindows {
+			t.Fatalf("expected %d proposers but got %v", MaxWindows, sampled)
+		}
+		if sampled[0] == heavy {
+			heavyFirst++
+		}
+	}
+	if heavyFirst < 90 {
+		t.Fatalf("expected the heavy validator to propose first at most heights but it did at %d", heavyFirst)
+	}
+}
+
+func TestWindowerFewerUnitsThanWindows(t *testing.T) {
+	vdrs := map[ids.ShortID]uint64{
+		ids.GenerateTestShortID(): 1,
+		ids.GenerateTestShortID(): 1,
+	}
+	w := newTestWindower(t, vdrs)
+
+	sampled := proposers(t, w, 1)
+	if len(sampled) != len(vdrs) {
+		t.Fatalf("expected each validator to be a proposer but got %v", sampled)
+	}
+	if sampled[0] == sampled[1] {
+		t.Fatalf("a validator with a single unit of stake was sampled twice: %v", sampled)
+	}
+}
+
+// Ensure that the proposers are sampled from the validators at the P-chain
+// height, rather than the current validators
+func TestWindowerPChainHeight(t *testing.T) {
+	oldVdr, newVdr := ids.GenerateTestShortID(), ids.GenerateTestShortID()
+	w := &windower{
+		state: &validators.TestState{
+			T: t,
+			GetValidatorSetF: func(height uint64, _ ids.ID) (map[ids.ShortID]uint64, error) {
+				switch height {
+				case 1:
+					return map[ids.ShortID]uint64{oldVdr: 1}, nil
+				case 2:
+					return map[ids.ShortID]uint64{newVdr: 1}, nil
+				default:
+					return nil, database.ErrNotFound
+				}
+			},
+		},
+		chainID: ids.GenerateTestID(),
+	}
+
+	for pChainHeight, expected := range map[uint64]ids.ShortID{1: oldVdr, 2: newVdr} {
+		sampled, err := w.proposers(5, pChainHeight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sampled) != 1 || sampled[0] != expected {
+			t.Fatalf("expected the proposers at P-chain height %d to be %s but got %v", pChainHeight, expected, sampled)
+		}
+	}
+	if _, err := w.delay(5, 3, oldVdr); err != database.ErrNotFound {
+		t.Fatalf("expected %s but got %v", database.ErrNotFound, err)
+	}
+}
+
+func TestWindowerNoValidators(t *testing.T) {
+	w := newTestWindower(t, nil)
+	if sampled := proposers(t, w, 1); len(sampled) != 0 {
+		t.Fatalf("expected no proposers but got %v", sampled)
+	}
+	if delay, err := w.delay(1, 0, ids.GenerateTestShortID()); err != nil {
+		t.Fatal(err)
+	} else if delay != MaxDelay {
+		t.Fatalf("expected to wait %s but got %s", MaxDelay, delay)
+	}
+}
+
+func equalShortIDs(a, b []ids.ShortID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gappsender
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gappsender/gappsenderproto"
+)
+
+var (
+	_ snow.AppSender = &Client{}
+)
+
+// Client is an implementation of an app sender that talks over RPC.
+type Client struct {
+	client gappsenderproto.AppSenderClient
+}
+
+// NewClient returns an app sender instance connected to a remote app sender
+// instance
+func NewClient(client gappsenderproto.AppSenderClient) *Client {
+	return &Client{client: client}
+}
+
+// SendAppRequest ...
+func (c *Client) SendAppRequest(nodeIDs ids.ShortSet, requestID uint32, request []byte) error {
+	_, err := c.client.SendAppRequest(context.Background(), &gappsenderproto.SendAppRequestMsg{
+		NodeIDs:   nodeIDsToBytes(nodeIDs),
+		RequestID: requestID,
+		Request:   request,
+	})
+	return err
+}
+
+// SendAppResponse ...
+func (c *Client) SendAppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
+	_, err := c.client.SendAppResponse(context.Background(), &gappsenderproto.SendAppResponseMsg{
+		NodeID:    nodeID[:],
+		RequestID: requestID,
+		Response:  response,
+	})
+	return err
+}
+
+// SendAppGossip ...
+func (c *Client) SendAppGossip(msg []byte) error {
+	_, err := c.client.SendAppGossip(context.Background(), &gappsenderproto.SendAppGossipMsg{
+		Msg: msg,
+	})
+	return err
+}
+
+// SendAppGossipSpecific ...
+func (c *Client) SendAppGossipSpecific(nodeIDs ids.ShortSet, msg []byte) error {
+	_, err := c.client.SendAppGossipSpecific(context.Background(), &gappsenderproto.SendAppGossipSpecificMsg{
+		NodeIDs: nodeIDsToBytes(nodeIDs),
+		Msg:     msg,
+	})
+	return err
+}
+
+func nodeIDsToBytes(nodeIDs ids.ShortSet) [][]byte {
+	nodeIDsBytes := make([][]byte, 0, nodeIDs.Len())
+	for nodeID := range nodeIDs {
+		nodeID := nodeID // Prevent overwrite in next iteration
+		nodeIDsBytes = append(nodeIDsBytes, nodeID[:])
+	}
+	return nodeIDsBytes
+}
@@ -0,0 +1,81 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gappsender
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gappsender/gappsenderproto"
+)
+
+var errNoAppSender = errors.New("no app sender is available")
+
+// Server is an app sender that is managed over RPC.
+type Server struct {
+	appSender snow.AppSender
+}
+
+// NewServer returns an app sender server wrapping [appSender]
+func NewServer(appSender snow.AppSender) *Server {
+	return &Server{appSender: appSender}
+}
+
+// SendAppRequest ...
+func (s *Server) SendAppRequest(_ context.Context, req *gappsenderproto.SendAppRequestMsg) (*gappsenderproto.EmptyMsg, error) {
+	if s.appSender == nil {
+		return nil, errNoAppSender
+	}
+	nodeIDs, err := bytesToNodeIDs(req.NodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &gappsenderproto.EmptyMsg{}, s.appSender.SendAppRequest(nodeIDs, req.RequestID, req.Request)
+}
+
+// SendAppResponse ...
+func (s *Server) SendAppResponse(_ context.Context, req *gappsenderproto.SendAppResponseMsg) (*gappsenderproto.EmptyMsg, error) {
+	if s.appSender == nil {
+		return nil, errNoAppSender
+	}
+	nodeID, err := ids.ToShortID(req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	return &gappsenderproto.EmptyMsg{}, s.appSender.SendAppResponse(nodeID, req.RequestID, req.Response)
+}
+
+// SendAppGossip ...
+func (s *Server) SendAppGossip(_ context.Context, req *gappsenderproto.SendAppGossipMsg) (*gappsenderproto.EmptyMsg, error) {
+	if s.appSender == nil {
+		return nil, errNoAppSender
+	}
+	return &gappsenderproto.EmptyMsg{}, s.appSender.SendAppGossip(req.Msg)
+}
+
+// SendAppGossipSpecific ...
+func (s *Server) SendAppGossipSpecific(_ context.Context, req *gappsenderproto.SendAppGossipSpecificMsg) (*gappsenderproto.EmptyMsg, error) {
+	if s.appSender == nil {
+		return nil, errNoAppSender
+	}
+	nodeIDs, err := bytesToNodeIDs(req.NodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &gappsenderproto.EmptyMsg{}, s.appSender.SendAppGossipSpecific(nodeIDs, req.Msg)
+}
+
+func bytesToNodeIDs(nodeIDsBytes [][]byte) (ids.ShortSet, error) {
+	nodeIDs := ids.ShortSet{}
+	for _, nodeIDBytes := range nodeIDsBytes {
+		nodeID, err := ids.ToShortID(nodeIDBytes)
+		if err != nil {
+			return nil, err
+		}
+		nodeIDs.Add(nodeID)
+	}
+	return nodeIDs, nil
+}
@@ -0,0 +1,473 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: gappsender.proto
+
+package gappsenderproto
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type SendAppRequestMsg struct {
+	NodeIDs              [][]byte `protobuf:"bytes,1,rep,name=nodeIDs,proto3" json:"nodeIDs,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Request              []byte   `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendAppRequestMsg) Reset()         { *m = SendAppRequestMsg{} }
+func (m *SendAppRequestMsg) String() string { return proto.CompactTextString(m) }
+func (*SendAppRequestMsg) ProtoMessage()    {}
+func (*SendAppRequestMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67135bc9eb95e390, []int{0}
+}
+
+func (m *SendAppRequestMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendAppRequestMsg.Unmarshal(m, b)
+}
+func (m *SendAppRequestMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendAppRequestMsg.Marshal(b, m, deterministic)
+}
+func (m *SendAppRequestMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendAppRequestMsg.Merge(m, src)
+}
+func (m *SendAppRequestMsg) XXX_Size() int {
+	return xxx_messageInfo_SendAppRequestMsg.Size(m)
+}
+func (m *SendAppRequestMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendAppRequestMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendAppRequestMsg proto.InternalMessageInfo
+
+func (m *SendAppRequestMsg) GetNodeIDs() [][]byte {
+	if m != nil {
+		return m.NodeIDs
+	}
+	return nil
+}
+
+func (m *SendAppRequestMsg) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *SendAppRequestMsg) GetRequest() []byte {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+type SendAppResponseMsg struct {
+	NodeID               []byte   `protobuf:"bytes,1,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	RequestID            uint32   `protobuf:"varint,2,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	Response             []byte   `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendAppResponseMsg) Reset()         { *m = SendAppResponseMsg{} }
+func (m *SendAppResponseMsg) String() string { return proto.CompactTextString(m) }
+func (*SendAppResponseMsg) ProtoMessage()    {}
+func (*SendAppResponseMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67135bc9eb95e390, []int{1}
+}
+
+func (m *SendAppResponseMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendAppResponseMsg.Unmarshal(m, b)
+}
+func (m *SendAppResponseMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendAppResponseMsg.Marshal(b, m, deterministic)
+}
+func (m *SendAppResponseMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendAppResponseMsg.Merge(m, src)
+}
+func (m *SendAppResponseMsg) XXX_Size() int {
+	return xxx_messageInfo_SendAppResponseMsg.Size(m)
+}
+func (m *SendAppResponseMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendAppResponseMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendAppResponseMsg proto.InternalMessageInfo
+
+func (m *SendAppResponseMsg) GetNodeID() []byte {
+	if m != nil {
+		return m.NodeID
+	}
+	return nil
+}
+
+func (m *SendAppResponseMsg) GetRequestID() uint32 {
+	if m != nil {
+		return m.RequestID
+	}
+	return 0
+}
+
+func (m *SendAppResponseMsg) GetResponse() []byte {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+type SendAppGossipMsg struct {
+	Msg                  []byte   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendAppGossipMsg) Reset()         { *m = SendAppGossipMsg{} }
+func (m *SendAppGossipMsg) String() string { return proto.CompactTextString(m) }
+func (*SendAppGossipMsg) ProtoMessage()    {}
+func (*SendAppGossipMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67135bc9eb95e390, []int{2}
+}
+
+func (m *SendAppGossipMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendAppGossipMsg.Unmarshal(m, b)
+}
+func (m *SendAppGossipMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendAppGossipMsg.Marshal(b, m, deterministic)
+}
+func (m *SendAppGossipMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendAppGossipMsg.Merge(m, src)
+}
+func (m *SendAppGossipMsg) XXX_Size() int {
+	return xxx_messageInfo_SendAppGossipMsg.Size(m)
+}
+func (m *SendAppGossipMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendAppGossipMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendAppGossipMsg proto.InternalMessageInfo
+
+func (m *SendAppGossipMsg) GetMsg() []byte {
+	if m != nil {
+		return m.Msg
+	}
+	return nil
+}
+
+type SendAppGossipSpecificMsg struct {
+	NodeIDs              [][]byte `protobuf:"bytes,1,rep,name=nodeIDs,proto3" json:"nodeIDs,omitempty"`
+	Msg                  []byte   `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendAppGossipSpecificMsg) Reset()         { *m = SendAppGossipSpecificMsg{} }
+func (m *SendAppGossipSpecificMsg) String() string { return proto.CompactTextString(m) }
+func (*SendAppGossipSpecificMsg) ProtoMessage()    {}
+func (*SendAppGossipSpecificMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67135bc9eb95e390, []int{3}
+}
+
+func (m *SendAppGossipSpecificMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendAppGossipSpecificMsg.Unmarshal(m, b)
+}
+func (m *SendAppGossipSpecificMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendAppGossipSpecificMsg.Marshal(b, m, deterministic)
+}
+func (m *SendAppGossipSpecificMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendAppGossipSpecificMsg.Merge(m, src)
+}
+func (m *SendAppGossipSpecificMsg) XXX_Size() int {
+	return xxx_messageInfo_SendAppGossipSpecificMsg.Size(m)
+}
+func (m *SendAppGossipSpecificMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendAppGossipSpecificMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendAppGossipSpecificMsg proto.InternalMessageInfo
+
+func (m *SendAppGossipSpecificMsg) GetNodeIDs() [][]byte {
+	if m != nil {
+		return m.NodeIDs
+	}
+	return nil
+}
+
+func (m *SendAppGossipSpecificMsg) GetMsg() []byte {
+	if m != nil {
+		return m.Msg
+	}
+	return nil
+}
+
+type EmptyMsg struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EmptyMsg) Reset()         { *m = EmptyMsg{} }
+func (m *EmptyMsg) String() string { return proto.CompactTextString(m) }
+func (*EmptyMsg) ProtoMessage()    {}
+func (*EmptyMsg) Descriptor() ([]byte, []int) {
+	return fileDescriptor_67135bc9eb95e390, []int{4}
+}
+
+func (m *EmptyMsg) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EmptyMsg.Unmarshal(m, b)
+}
+func (m *EmptyMsg) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EmptyMsg.Marshal(b, m, deterministic)
+}
+func (m *EmptyMsg) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EmptyMsg.Merge(m, src)
+}
+func (m *EmptyMsg) XXX_Size() int {
+	return xxx_messageInfo_EmptyMsg.Size(m)
+}
+func (m *EmptyMsg) XXX_DiscardUnknown() {
+	xxx_messageInfo_EmptyMsg.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EmptyMsg proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*SendAppRequestMsg)(nil), "gappsenderproto.SendAppRequestMsg")
+	proto.RegisterType((*SendAppResponseMsg)(nil), "gappsenderproto.SendAppResponseMsg")
+	proto.RegisterType((*SendAppGossipMsg)(nil), "gappsenderproto.SendAppGossipMsg")
+	proto.RegisterType((*SendAppGossipSpecificMsg)(nil), "gappsenderproto.SendAppGossipSpecificMsg")
+	proto.RegisterType((*EmptyMsg)(nil), "gappsenderproto.EmptyMsg")
+}
+
+func init() { proto.RegisterFile("gappsender.proto", fileDescriptor_67135bc9eb95e390) }
+
+var fileDescriptor_67135bc9eb95e390 = []byte{
+	// 283 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0xc1, 0x4e, 0x83, 0x40,
+	0x10, 0x86, 0x43, 0x49, 0x6a, 0x3b, 0xb1, 0x16, 0x27, 0xd1, 0xac, 0x8d, 0x07, 0x44, 0x0f, 0xf5,
+	0xc2, 0x41, 0x9f, 0xa0, 0x49, 0xd5, 0xf4, 0x40, 0x8c, 0x70, 0xf6, 0xa0, 0x65, 0x4b, 0x38, 0x94,
+	0x1d, 0x19, 0x3c, 0xf8, 0xb0, 0xbe, 0x8b, 0x59, 0xba, 0x05, 0x69, 0xed, 0x7a, 0xe3, 0x9f, 0xfc,
+	0xfb, 0xfd, 0xe1, 0x03, 0x2f, 0x7b, 0x23, 0x62, 0x59, 0xa4, 0xb2, 0x0c, 0xa9, 0x54, 0x95, 0xc2,
+	0x71, 0x7b, 0xa9, 0x0f, 0x81, 0x84, 0xd3, 0x44, 0x16, 0xe9, 0x8c, 0x28, 0x96, 0x1f, 0x9f, 0x92,
+	0xab, 0x88, 0x33, 0x14, 0x70, 0x54, 0xa8, 0x54, 0x2e, 0xe6, 0x2c, 0x1c, 0xdf, 0x9d, 0x1e, 0xc7,
+	0xdb, 0x88, 0x97, 0x30, 0x2c, 0x37, 0xbd, 0xc5, 0x5c, 0xf4, 0x7c, 0x67, 0x3a, 0x8a, 0xdb, 0x83,
+	0x7e, 0x67, 0x82, 0x70, 0x7d, 0x47, 0xbf, 0x33, 0x31, 0x58, 0x01, 0x36, 0x33, 0x4c, 0xaa, 0x60,
+	0xa9, 0x77, 0xce, 0xa1, 0xbf, 0x01, 0x0b, 0xa7, 0xae, 0x9b, 0xf4, 0xcf, 0xca, 0x04, 0x06, 0xa5,
+	0x81, 0x98, 0x99, 0x26, 0x07, 0x37, 0xe0, 0x99, 0x9d, 0x27, 0xc5, 0x9c, 0x93, 0x5e, 0xf1, 0xc0,
+	0x5d, 0x73, 0x66, 0x26, 0xf4, 0x67, 0xf0, 0x08, 0xa2, 0xd3, 0x4a, 0x48, 0x2e, 0xf3, 0x55, 0xbe,
+	0xb4, 0xff, 0xbb, 0xe1, 0xf4, 0x5a, 0x0e, 0xc0, 0xe0, 0x61, 0x4d, 0xd5, 0x57, 0xc4, 0xd9, 0xdd,
+	0x77, 0x0f, 0x86, 0x33, 0xa2, 0xa4, 0x76, 0x8b, 0xcf, 0x70, 0xd2, 0xd5, 0x8a, 0x41, 0xb8, 0xa3,
+	0x3e, 0xdc, 0xf3, 0x3e, 0xb9, 0xd8, 0xeb, 0x6c, 0xf1, 0xf8, 0x02, 0xe3, 0x1d, 0x81, 0x78, 0x7d,
+	0x98, 0xd8, 0x28, 0xb6, 0x21, 0x23, 0x18, 0x75, 0x2c, 0xe0, 0xd5, 0x21, 0x60, 0xe3, 0xd2, 0x86,
+	0x7b, 0x85, 0xb3, 0x3f, 0xa5, 0xe2, 0xad, 0x1d, 0xfb, 0x4b, 0xbe, 0x05, 0xff, 0xde, 0xaf, 0xf3,
+	0xfd, 0xcf, 0x00, 0xe1, 0xbe, 0x21, 0xc6, 0xd4, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// AppSenderClient is the client API for AppSender service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AppSenderClient interface {
+	SendAppRequest(ctx context.Context, in *SendAppRequestMsg, opts ...grpc.CallOption) (*EmptyMsg, error)
+	SendAppResponse(ctx context.Context, in *SendAppResponseMsg, opts ...grpc.CallOption) (*EmptyMsg, error)
+	SendAppGossip(ctx context.Context, in *SendAppGossipMsg, opts ...grpc.CallOption) (*EmptyMsg, error)
+	SendAppGossipSpecific(ctx context.Context, in *SendAppGossipSpecificMsg, opts ...grpc.CallOption) (*EmptyMsg, error)
+}
+
+type appSenderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAppSenderClient(cc grpc.ClientConnInterface) AppSenderClient {
+	return &appSenderClient{cc}
+}
+
+func (c *appSenderClient) SendAppRequest(ctx context.Context, in *SendAppRequestMsg, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/gappsenderproto.AppSender/SendAppRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appSenderClient) SendAppResponse(ctx context.Context, in *SendAppResponseMsg, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/gappsenderproto.AppSender/SendAppResponse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appSenderClient) SendAppGossip(ctx context.Context, in *SendAppGossipMsg, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/gappsenderproto.AppSender/SendAppGossip", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *appSenderClient) SendAppGossipSpecific(ctx context.Context, in *SendAppGossipSpecificMsg, opts ...grpc.CallOption) (*EmptyMsg, error) {
+	out := new(EmptyMsg)
+	err := c.cc.Invoke(ctx, "/gappsenderproto.AppSender/SendAppGossipSpecific", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AppSenderServer is the server API for AppSender service.
+type AppSenderServer interface {
+	SendAppRequest(context.Context, *SendAppRequestMsg) (*EmptyMsg, error)
+	SendAppResponse(context.Context, *SendAppResponseMsg) (*EmptyMsg, error)
+	SendAppGossip(context.Context, *SendAppGossipMsg) (*EmptyMsg, error)
+	SendAppGossipSpecific(context.Context, *SendAppGossipSpecificMsg) (*EmptyMsg, error)
+}
+
+// UnimplementedAppSenderServer can be embedded to have forward compatible implementations.
+type UnimplementedAppSenderServer struct {
+}
+
+func (*UnimplementedAppSenderServer) SendAppRequest(ctx context.Context, req *SendAppRequestMsg) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendAppRequest not implemented")
+}
+func (*UnimplementedAppSenderServer) SendAppResponse(ctx context.Context, req *SendAppResponseMsg) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendAppResponse not implemented")
+}
+func (*UnimplementedAppSenderServer) SendAppGossip(ctx context.Context, req *SendAppGossipMsg) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendAppGossip not implemented")
+}
+func (*UnimplementedAppSenderServer) SendAppGossipSpecific(ctx context.Context, req *SendAppGossipSpecificMsg) (*EmptyMsg, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendAppGossipSpecific not implemented")
+}
+
+func RegisterAppSenderServer(s *grpc.Server, srv AppSenderServer) {
+	s.RegisterService(&_AppSender_serviceDesc, srv)
+}
+
+func _AppSender_SendAppRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAppRequestMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppSenderServer).SendAppRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gappsenderproto.AppSender/SendAppRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppSenderServer).SendAppRequest(ctx, req.(*SendAppRequestMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppSender_SendAppResponse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAppResponseMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppSenderServer).SendAppResponse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gappsenderproto.AppSender/SendAppResponse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppSenderServer).SendAppResponse(ctx, req.(*SendAppResponseMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppSender_SendAppGossip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAppGossipMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppSenderServer).SendAppGossip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gappsenderproto.AppSender/SendAppGossip",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppSenderServer).SendAppGossip(ctx, req.(*SendAppGossipMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AppSender_SendAppGossipSpecific_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAppGossipSpecificMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AppSenderServer).SendAppGossipSpecific(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gappsenderproto.AppSender/SendAppGossipSpecific",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AppSenderServer).SendAppGossipSpecific(ctx, req.(*SendAppGossipSpecificMsg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AppSender_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gappsenderproto.AppSender",
+	HandlerType: (*AppSenderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendAppRequest",
+			Handler:    _AppSender_SendAppRequest_Handler,
+		},
+		{
+			MethodName: "SendAppResponse",
+			Handler:    _AppSender_SendAppResponse_Handler,
+		},
+		{
+			MethodName: "SendAppGossip",
+			Handler:    _AppSender_SendAppGossip_Handler,
+		},
+		{
+			MethodName: "SendAppGossipSpecific",
+			Handler:    _AppSender_SendAppGossipSpecific_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gappsender.proto",
+}
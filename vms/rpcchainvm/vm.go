@@ -14,9 +14,12 @@ import (
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/vmproto"
 )
 
-// Handshake is a common handshake that is shared by plugin and host.
+// Handshake is a common handshake that is shared by plugin and host. The
+// protocol version is bumped whenever the VM service changes, so that plugins
+// built against another version fail to load rather than failing at runtime.
+// Version 2 added app messages.
 var Handshake = plugin.HandshakeConfig{
-	ProtocolVersion:  1,
+	ProtocolVersion:  2,
 	MagicCookieKey:   "VM_PLUGIN",
 	MagicCookieValue: "dynamic",
 }
@@ -9,6 +9,8 @@ import (
 	"fmt"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/hashicorp/go-plugin"
 	"github.com/prometheus/client_golang/prometheus"
@@ -27,6 +29,8 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/missing"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/galiaslookup"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/galiaslookup/galiaslookupproto"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gappsender"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gappsender/gappsenderproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp/ghttpproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gkeystore"
@@ -43,6 +47,8 @@ import (
 
 var (
 	errUnsupportedFXs = errors.New("unsupported feature extensions")
+
+	_ common.AppHandler = &VMClient{}
 )
 
 const (
@@ -61,6 +67,7 @@ type VMClient struct {
 	sharedMemory *gsharedmemory.Server
 	bcLookup     *galiaslookup.Server
 	snLookup     *gsubnetlookup.Server
+	appSender    *gappsender.Server
 
 	serverCloser grpcutils.ServerCloser
 	conns        []*grpc.ClientConn
@@ -129,6 +136,7 @@ func (vm *VMClient) Initialize(
 	vm.sharedMemory = gsharedmemory.NewServer(ctx.SharedMemory, db)
 	vm.bcLookup = galiaslookup.NewServer(ctx.BCLookup)
 	vm.snLookup = gsubnetlookup.NewServer(ctx.SNLookup)
+	vm.appSender = gappsender.NewServer(ctx.AppSender)
 
 	if err := vm.initializeCaches(ctx.Metrics, ctx.Namespace); err != nil {
 		return err
@@ -158,6 +166,10 @@ func (vm *VMClient) Initialize(
 	snLookupBrokerID := vm.broker.NextId()
 	go vm.broker.AcceptAndServe(snLookupBrokerID, vm.startSNLookupServer)
 
+	// start the app sender server
+	appSenderBrokerID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(appSenderBrokerID, vm.startAppSenderServer)
+
 	resp, err := vm.client.Initialize(context.Background(), &vmproto.InitializeRequest{
 		NetworkID:            ctx.NetworkID,
 		SubnetID:             ctx.SubnetID[:],
@@ -174,6 +186,7 @@ func (vm *VMClient) Initialize(
 		SnLookupServer:       snLookupBrokerID,
 		EpochFirstTransition: epochFirstTransitionBytes,
 		EpochDuration:        uint64(ctx.EpochDuration),
+		AppSenderServer:      appSenderBrokerID,
 	})
 	if err != nil {
 		return err
@@ -230,6 +243,13 @@ func (vm *VMClient) startSNLookupServer(opts []grpc.ServerOption) *grpc.Server {
 	return server
 }
 
+func (vm *VMClient) startAppSenderServer(opts []grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	vm.serverCloser.Add(server)
+	gappsenderproto.RegisterAppSenderServer(server, vm.appSender)
+	return server
+}
+
 // Bootstrapping ...
 func (vm *VMClient) Bootstrapping() error {
 	_, err := vm.client.Bootstrapping(context.Background(), &vmproto.BootstrappingRequest{})
@@ -394,6 +414,55 @@ func (vm *VMClient) Health() (interface{}, error) {
 	)
 }
 
+// AppRequest ...
+func (vm *VMClient) AppRequest(nodeID ids.ShortID, requestID uint32, request []byte) error {
+	_, err := vm.client.AppRequest(context.Background(), &vmproto.AppRequestMsg{
+		NodeID:    nodeID[:],
+		RequestID: requestID,
+		Request:   request,
+	})
+	return vm.appMsgErr("AppRequest", err)
+}
+
+// AppRequestFailed ...
+func (vm *VMClient) AppRequestFailed(nodeID ids.ShortID, requestID uint32) error {
+	_, err := vm.client.AppRequestFailed(context.Background(), &vmproto.AppRequestFailedMsg{
+		NodeID:    nodeID[:],
+		RequestID: requestID,
+	})
+	return vm.appMsgErr("AppRequestFailed", err)
+}
+
+// AppResponse ...
+func (vm *VMClient) AppResponse(nodeID ids.ShortID, requestID uint32, response []byte) error {
+	_, err := vm.client.AppResponse(context.Background(), &vmproto.AppResponseMsg{
+		NodeID:    nodeID[:],
+		RequestID: requestID,
+		Response:  response,
+	})
+	return vm.appMsgErr("AppResponse", err)
+}
+
+// AppGossip ...
+func (vm *VMClient) AppGossip(nodeID ids.ShortID, msg []byte) error {
+	_, err := vm.client.AppGossip(context.Background(), &vmproto.AppGossipMsg{
+		NodeID: nodeID[:],
+		Msg:    msg,
+	})
+	return vm.appMsgErr("AppGossip", err)
+}
+
+// appMsgErr returns the error [err] the plugin returned when handling an app
+// message. Plugins built before app messages existed don't implement them, so
+// their messages are dropped.
+func (vm *VMClient) appMsgErr(method string, err error) error {
+	if status.Code(err) == codes.Unimplemented {
+		vm.ctx.Log.Verbo("dropping %s as the plugin doesn't handle app messages", method)
+		return nil
+	}
+	return err
+}
+
 // BlockClient is an implementation of Block that talks over RPC.
 type BlockClient struct {
 	vm *VMClient
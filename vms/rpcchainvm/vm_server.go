@@ -22,6 +22,8 @@ import (
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/galiaslookup"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/galiaslookup/galiaslookupproto"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gappsender"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gappsender/gappsenderproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/ghttp/ghttpproto"
 	"github.com/ava-labs/avalanchego/vms/rpcchainvm/gkeystore"
@@ -128,6 +130,17 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		_ = bcLookupConn.Close()
 		return nil, err
 	}
+	appSenderConn, err := vm.broker.Dial(req.AppSenderServer)
+	if err != nil {
+		// Ignore closing error to return the original error
+		_ = dbConn.Close()
+		_ = msgConn.Close()
+		_ = keystoreConn.Close()
+		_ = sharedMemoryConn.Close()
+		_ = bcLookupConn.Close()
+		_ = snLookupConn.Close()
+		return nil, err
+	}
 
 	dbClient := rpcdb.NewClient(rpcdbproto.NewDatabaseClient(dbConn))
 	msgClient := messenger.NewClient(messengerproto.NewMessengerClient(msgConn))
@@ -135,6 +148,7 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 	sharedMemoryClient := gsharedmemory.NewClient(gsharedmemoryproto.NewSharedMemoryClient(sharedMemoryConn))
 	bcLookupClient := galiaslookup.NewClient(galiaslookupproto.NewAliasLookupClient(bcLookupConn))
 	snLookupClient := gsubnetlookup.NewClient(gsubnetlookupproto.NewSubnetLookupClient(snLookupConn))
+	appSenderClient := gappsender.NewClient(gappsenderproto.NewAppSenderClient(appSenderConn))
 
 	toEngine := make(chan common.Message, 1)
 	go func() {
@@ -158,6 +172,7 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		SharedMemory:         sharedMemoryClient,
 		BCLookup:             bcLookupClient,
 		SNLookup:             snLookupClient,
+		AppSender:            appSenderClient,
 		EpochFirstTransition: epochFirstTransition,
 		EpochDuration:        time.Duration(req.EpochDuration),
 	}
@@ -170,12 +185,14 @@ func (vm *VMServer) Initialize(_ context.Context, req *vmproto.InitializeRequest
 		_ = sharedMemoryConn.Close()
 		_ = bcLookupConn.Close()
 		_ = snLookupConn.Close()
+		_ = appSenderConn.Close()
 		close(toEngine)
 		return nil, err
 	}
 
 	vm.conns = append(vm.conns, dbConn)
 	vm.conns = append(vm.conns, msgConn)
+	vm.conns = append(vm.conns, appSenderConn)
 	vm.toEngine = toEngine
 	lastAccepted := vm.vm.LastAccepted()
 	return &vmproto.InitializeResponse{
@@ -370,3 +387,55 @@ func (vm *VMServer) BlockReject(_ context.Context, req *vmproto.BlockRejectReque
 	}
 	return &vmproto.BlockRejectResponse{}, nil
 }
+
+// AppRequest ...
+func (vm *VMServer) AppRequest(_ context.Context, req *vmproto.AppRequestMsg) (*vmproto.EmptyMsg, error) {
+	handler, ok := vm.vm.(common.AppHandler)
+	if !ok {
+		return &vmproto.EmptyMsg{}, nil
+	}
+	nodeID, err := ids.ToShortID(req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	return &vmproto.EmptyMsg{}, handler.AppRequest(nodeID, req.RequestID, req.Request)
+}
+
+// AppRequestFailed ...
+func (vm *VMServer) AppRequestFailed(_ context.Context, req *vmproto.AppRequestFailedMsg) (*vmproto.EmptyMsg, error) {
+	handler, ok := vm.vm.(common.AppHandler)
+	if !ok {
+		return &vmproto.EmptyMsg{}, nil
+	}
+	nodeID, err := ids.ToShortID(req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	return &vmproto.EmptyMsg{}, handler.AppRequestFailed(nodeID, req.RequestID)
+}
+
+// AppResponse ...
+func (vm *VMServer) AppResponse(_ context.Context, req *vmproto.AppResponseMsg) (*vmproto.EmptyMsg, error) {
+	handler, ok := vm.vm.(common.AppHandler)
+	if !ok {
+		return &vmproto.EmptyMsg{}, nil
+	}
+	nodeID, err := ids.ToShortID(req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	return &vmproto.EmptyMsg{}, handler.AppResponse(nodeID, req.RequestID, req.Response)
+}
+
+// AppGossip ...
+func (vm *VMServer) AppGossip(_ context.Context, req *vmproto.AppGossipMsg) (*vmproto.EmptyMsg, error) {
+	handler, ok := vm.vm.(common.AppHandler)
+	if !ok {
+		return &vmproto.EmptyMsg{}, nil
+	}
+	nodeID, err := ids.ToShortID(req.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	return &vmproto.EmptyMsg{}, handler.AppGossip(nodeID, req.Msg)
+}
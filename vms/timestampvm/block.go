@@ -7,7 +7,9 @@ import (
 	"errors"
 	"time"
 
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/vms/components/core"
+	"github.com/ava-labs/avalanchego/vms/components/missing"
 )
 
 var (
@@ -25,6 +27,16 @@ type Block struct {
 	*core.Block `serialize:"true"`
 	Data        [dataLen]byte `serialize:"true"`
 	Timestamp   int64         `serialize:"true"`
+	vm          *VM
+}
+
+// Parent returns [b]'s parent
+func (b *Block) Parent() snowman.Block {
+	parent, err := b.vm.GetBlock(b.ParentID())
+	if err != nil {
+		return &missing.Block{BlkID: b.ParentID()}
+	}
+	return parent
 }
 
 // Verify returns nil iff this block is valid.
@@ -49,9 +61,30 @@ func (b *Block) Verify() error {
 		return errTimestampTooLate
 	}
 
-	// Persist the block
-	if err := b.VM.SaveBlock(b.VM.DB, b); err != nil {
+	// The block is part of the state of the chain if it's accepted
+	db, err := b.vm.states.NewDiff(b.ParentID())
+	if err != nil {
+		return err
+	}
+	if err := b.vm.SaveBlock(db, b); err != nil {
 		return errDatabaseSave
 	}
-	return b.VM.DB.Commit()
+	return b.vm.states.Add(b.ID(), b.ParentID(), db)
+}
+
+// Accept persists this block and sets it as the last accepted block
+func (b *Block) Accept() error {
+	if err := b.Block.Accept(); err != nil {
+		return err
+	}
+	if err := b.vm.states.Accept(b.ID()); err != nil {
+		return err
+	}
+	return b.vm.DB.Commit()
+}
+
+// Reject drops this block from the state of the chain
+func (b *Block) Reject() error {
+	b.vm.states.Reject(b.ID())
+	return b.Block.Reject()
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/vms/components/core"
+	"github.com/ava-labs/avalanchego/vms/components/statetree"
 )
 
 const (
@@ -39,6 +40,8 @@ type VM struct {
 	codec codec.Manager
 	// Proposed pieces of data that haven't been put into a block and proposed yet
 	mempool [][dataLen]byte
+	// The state of the chain if each processing block is accepted
+	states *statetree.Tree
 }
 
 // Initialize this vm
@@ -91,7 +94,7 @@ func (vm *VM) Initialize(
 
 		// Accept the genesis block
 		// Sets [vm.lastAccepted] and [vm.preferred]
-		if err := genesisBlock.Accept(); err != nil {
+		if err := genesisBlock.Block.Accept(); err != nil {
 			return fmt.Errorf("error accepting genesis block: %w", err)
 		}
 
@@ -105,6 +108,7 @@ func (vm *VM) Initialize(
 			return err
 		}
 	}
+	vm.states = statetree.New(vm.DB, vm.LastAcceptedID)
 	return nil
 }
 
@@ -170,22 +174,33 @@ func (vm *VM) proposeBlock(data [dataLen]byte) {
 // ParseBlock parses [bytes] to a snowman.Block
 // This function is used by the vm's state to unmarshal blocks saved in state
 func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
-	block := &Block{}
+	block := &Block{vm: vm}
 	_, err := vm.codec.Unmarshal(bytes, block)
 	block.Initialize(bytes, &vm.SnowmanVM)
 	return block, err
 }
 
+// GetBlock returns the block with ID [blkID]
+// A processing block is only in the state of the chain if it's accepted, so
+// it's looked up in its own state
+func (vm *VM) GetBlock(blkID ids.ID) (snowman.Block, error) {
+	db, ok := vm.states.Get(blkID)
+	if !ok {
+		db = vm.DB
+	}
+	return vm.State.GetBlock(db, blkID)
+}
+
 // NewBlock returns a new Block where:
 // - the block's parent is [parentID]
 // - the block's data is [data]
 // - the block's timestamp is [timestamp]
-// The block is persisted in storage
 func (vm *VM) NewBlock(parentID ids.ID, height uint64, data [dataLen]byte, timestamp time.Time) (*Block, error) {
 	block := &Block{
 		Block:     core.NewBlock(parentID, height),
 		Data:      data,
 		Timestamp: timestamp.Unix(),
+		vm:        vm,
 	}
 	blockBytes, err := vm.codec.Marshal(codecVersion, block)
 	if err != nil {